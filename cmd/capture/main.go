@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/fixtures"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+func main() {
+	var (
+		configFile   = flag.String("config", "", "Configuration file providing the production URLs")
+		dataDir      = flag.String("data-dir", "", "Mock data directory to refresh (default: testing.data_dir)")
+		releasesFile = flag.String("supported-releases", "data/supportedReleases.json", "Supported releases file listing captured packages")
+		dryRun       = flag.Bool("dry-run", false, "Only report which fixtures would change")
+		staticDir    = flag.String("vendor-assets", "", "Download the CDN assets into this static directory instead of capturing fixtures")
+	)
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if *dataDir == "" {
+		*dataDir = cfg.Testing.DataDir
+	}
+	utils.SetHTTPConfig(cfg.HTTP.GetTimeout(), cfg.HTTP.Retries)
+	utils.SetHTTPUserAgent(cfg.HTTP.UserAgent)
+	utils.SetHTTPProxy(cfg.HTTP.HTTPProxy, cfg.HTTP.HTTPSProxy, cfg.HTTP.NoProxy)
+
+	if *staticDir != "" {
+		if err := vendorAssets(cfg.URLs.CDN, *staticDir); err != nil {
+			log.Fatalf("Failed to vendor CDN assets: %v", err)
+		}
+		return
+	}
+
+	supportedReleases, err := releases.ReadSupportedReleases(*releasesFile)
+	if err != nil {
+		log.Fatalf("Failed to read supported releases: %v", err)
+	}
+
+	var changed, unchanged, failed int
+	for _, fixture := range fixtures.Targets(cfg, supportedReleases) {
+		updated, err := refresh(fixture, *dataDir, *dryRun)
+		switch {
+		case err != nil:
+			failed++
+			fmt.Printf("❌ %s: %v\n", fixture.Path, err)
+		case updated:
+			changed++
+			fmt.Printf("✏️  %s\n", fixture.Path)
+		default:
+			unchanged++
+		}
+	}
+
+	fmt.Printf("\n%d changed, %d unchanged, %d failed (data dir: %s)\n", changed, unchanged, failed, *dataDir)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// refresh captures one fixture and reports whether its normalized content changed
+func refresh(fixture fixtures.Fixture, dataDir string, dryRun bool) (bool, error) {
+	resp, err := utils.HTTPGetWithRetry(fixture.URL)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	normalized, err := fixtures.Normalize(fixture.Path, body)
+	if err != nil {
+		return false, err
+	}
+
+	fullPath := filepath.Join(dataDir, fixture.Path)
+	if existing, err := os.ReadFile(fullPath); err == nil && bytes.Equal(existing, normalized) {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return false, fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(fullPath, normalized, 0644); err != nil {
+		return false, fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return true, nil
+}
+
+// vendorAssets downloads the CDN assets for the offline mode, checking the configured integrity
+// hashes and printing the hash of each asset
+func vendorAssets(cdn config.CDNURLs, staticDir string) error {
+	assets := cdn.Assets()
+	names := make([]string, 0, len(assets))
+	for name := range assets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		assetURL, integrity := assets[name][0], assets[name][1]
+		resp, err := utils.HTTPGetWithRetry(assetURL)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", assetURL, err)
+		}
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("unexpected status code for %s: %d", assetURL, resp.StatusCode)
+		}
+
+		sum := sha512.Sum384(body)
+		computed := "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+		if integrity != "" && strings.HasPrefix(integrity, "sha384-") && integrity != computed {
+			return fmt.Errorf("%s does not match its configured integrity %s (got %s)", assetURL, integrity, computed)
+		}
+
+		file := filepath.Join(staticDir, config.VendorFile(assetURL))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(file, body, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		fmt.Printf("✅ %s -> %s (integrity %s)\n", name, file, computed)
+	}
+	return nil
+}
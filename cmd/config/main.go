@@ -166,9 +166,16 @@ func showConfig(configFile string) {
 	fmt.Printf("  NVIDIA Archive: %s\n", cfg.URLs.NVIDIA.DriverArchiveURL)
 	fmt.Printf("  Kernel Series: %s\n", cfg.URLs.Kernel.SeriesYAMLURL)
 	fmt.Printf("  SRU Cycles: %s\n", cfg.URLs.Kernel.SRUCycleURL)
+	for _, mirror := range cfg.URLs.Kernel.SeriesYAMLMirrors {
+		fmt.Printf("  Kernel Series mirror: %s\n", mirror)
+	}
+	for _, mirror := range cfg.URLs.Kernel.SRUCycleMirrors {
+		fmt.Printf("  SRU Cycles mirror: %s\n", mirror)
+	}
 
 	fmt.Printf("\n📚 CDN Libraries:\n")
 	fmt.Printf("  Bootstrap CSS: %s\n", cfg.URLs.CDN.BootstrapCSS)
 	fmt.Printf("  Bootstrap JS:  %s\n", cfg.URLs.CDN.BootstrapJS)
 	fmt.Printf("  Chart.js:      %s\n", cfg.URLs.CDN.ChartJS)
+	fmt.Printf("  Mermaid:       %s\n", cfg.URLs.CDN.MermaidJS)
 }
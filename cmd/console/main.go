@@ -27,10 +27,12 @@ func main() {
 	lrm.SetProcessorConfig(cfg)
 	sru.SetSRUConfig(cfg)
 	packages.SetPackagesConfig(cfg)
+	releases.SetReleasesConfig(cfg)
 	lrm.SetHTTPConfig(cfg.HTTP.GetTimeout(), cfg.HTTP.Retries)
 	lrm.SetMaxConcurrency(cfg.Processing.GetMaxConcurrency())
 	utils.SetHTTPUserAgent(cfg.HTTP.UserAgent)
 	utils.SetHTTPAuthToken(cfg.HTTP.GetForgejoToken())
+	utils.SetHTTPProxy(cfg.HTTP.HTTPProxy, cfg.HTTP.HTTPSProxy, cfg.HTTP.NoProxy)
 
 	// Configuration
 	packageQuery := "nvidia-graphics-drivers-570"
@@ -103,7 +105,7 @@ func main() {
 
 	// Process each supported release
 	for _, release := range supportedReleases {
-		currentPackageName := "nvidia-graphics-drivers-" + release.BranchName
+		currentPackageName := release.PackageName()
 
 		currentSourceVersions, err := packages.GetMaxSourceVersionsArchive(cfg, currentPackageName)
 		if err != nil {
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/backfill"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/doctor"
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/hostcheck"
+	"nvidia_driver_monitor/internal/monitoring"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "host-check":
+		hostCheck(os.Args[2:])
+	case "doctor":
+		doctorCheck(os.Args[2:])
+	case "backfill":
+		backfillHistory(os.Args[2:])
+	case "gen-monitoring":
+		genMonitoring(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: nvidia-monitor <command> [flags]\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  host-check      Compare the drivers installed on this host against the archive\n")
+	fmt.Fprintf(os.Stderr, "  doctor          Check the configuration, upstream connectivity, state directories and certificates\n")
+	fmt.Fprintf(os.Stderr, "  backfill        Reconstruct past dashboard history from the Launchpad publication history\n")
+	fmt.Fprintf(os.Stderr, "  gen-monitoring  Generate Prometheus alerting rules and a Grafana dashboard from the alerts and SLOs\n")
+}
+
+// hostCheck inspects the local machine and prints a JSON report for fleet inventory systems
+func hostCheck(args []string) {
+	flags := flag.NewFlagSet("host-check", flag.ExitOnError)
+	serverURL := flags.String("server", "http://localhost:8080", "Base URL of the monitor web server")
+	configFile := flags.String("config", "config.json", "Configuration file path (HTTP settings)")
+	failOutdated := flags.Bool("fail-outdated", false, "Exit with status 1 when the host is not up to date")
+	submit := flags.Bool("report", false, "Also submit the report to the monitor's fleet endpoint")
+	reportToken := flags.String("report-token", "", "Token for the fleet endpoint (default fleet.report_token or FLEET_REPORT_TOKEN)")
+	flags.Parse(args)
+
+	cfg, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	utils.SetHTTPConfig(cfg.HTTP.GetTimeout(), cfg.HTTP.Retries)
+	utils.SetHTTPUserAgent(cfg.HTTP.UserAgent)
+	utils.SetHTTPProxy(cfg.HTTP.HTTPProxy, cfg.HTTP.HTTPSProxy, cfg.HTTP.NoProxy)
+
+	info := hostcheck.CollectHostInfo()
+
+	var status *hostcheck.PackageStatus
+	if source := info.SourcePackage(); source != "" {
+		status, err = hostcheck.FetchPackageStatus(*serverURL, source)
+		if err != nil {
+			info.Notes = append(info.Notes, err.Error())
+		}
+	}
+
+	report := hostcheck.BuildReport(info, status)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("Failed to encode report: %v", err)
+	}
+
+	if *submit {
+		token := *reportToken
+		if token == "" {
+			token = cfg.Fleet.GetReportToken()
+		}
+		if err := hostcheck.SubmitReport(*serverURL, token, report); err != nil {
+			log.Fatalf("Failed to submit report: %v", err)
+		}
+		log.Printf("Report submitted to %s", *serverURL)
+	}
+
+	if *failOutdated && !report.UpToDate {
+		os.Exit(1)
+	}
+}
+
+// doctorCheck validates a deployment and prints a pass/fail table, exiting with status 1 when
+// a check fails
+func doctorCheck(args []string) {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	configFile := flags.String("config", "config.json", "Configuration file path")
+	releasesFile := flags.String("releases", "data/supportedReleases.json", "Supported releases file path")
+	certFile := flags.String("cert", "server.crt", "Certificate file path (checked when HTTPS is enabled)")
+	keyFile := flags.String("key", "server.key", "Private key file path (checked when HTTPS is enabled)")
+	offline := flags.Bool("offline", false, "Skip DNS resolution and upstream requests")
+	jsonOutput := flags.Bool("json", false, "Print the results as JSON")
+	flags.Parse(args)
+
+	// The upstream requests use the configured timeout, user agent and proxies; the setup is
+	// not logged so that only the table reaches the terminal
+	log.SetOutput(io.Discard)
+	opts := doctor.Options{
+		ReleasesFile: *releasesFile,
+		CertFile:     *certFile,
+		KeyFile:      *keyFile,
+		SkipNetwork:  *offline,
+	}
+	if cfg, err := config.LoadConfig(*configFile); err == nil {
+		utils.SetHTTPConfig(cfg.HTTP.GetTimeout(), cfg.HTTP.Retries)
+		utils.SetHTTPUserAgent(cfg.HTTP.UserAgent)
+		utils.SetHTTPProxy(cfg.HTTP.HTTPProxy, cfg.HTTP.HTTPSProxy, cfg.HTTP.NoProxy)
+		opts.Client = utils.NewHTTPClient()
+		opts.Timeout = cfg.HTTP.GetTimeout()
+	}
+	log.SetOutput(os.Stderr)
+
+	results := doctor.Run(*configFile, opts)
+	write := doctor.WriteTable
+	if *jsonOutput {
+		write = doctor.WriteJSON
+	}
+	if err := write(os.Stdout, results); err != nil {
+		log.Fatalf("Failed to print results: %v", err)
+	}
+	if doctor.Failed(results) {
+		os.Exit(1)
+	}
+}
+
+// backfillHistory fills the history store with the days before the dashboard started recording,
+// reconstructed from the full Launchpad publication history of every tracked package
+func backfillHistory(args []string) {
+	flags := flag.NewFlagSet("backfill", flag.ExitOnError)
+	configFile := flags.String("config", "config.json", "Configuration file path")
+	releasesFile := flags.String("releases", "data/supportedReleases.json", "Supported releases file path")
+	since := flags.String("since", "", "First day to reconstruct (YYYY-MM-DD); default is each package's first publication")
+	until := flags.String("until", "", "Last day to reconstruct (YYYY-MM-DD); default is yesterday (UTC)")
+	only := flags.String("packages", "", "Comma-separated branches or source packages to backfill; default is all tracked")
+	interval := flags.Duration("interval", 2*time.Second, "Wait between Launchpad requests")
+	pageSize := flags.Int("page-size", backfill.MaxPageSize, "Publications per Launchpad request")
+	dryRun := flags.Bool("dry-run", false, "Fetch and reconstruct without writing the history store")
+	flags.Parse(args)
+
+	cfg, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := cfg.ValidateMode(); err != nil {
+		log.Fatalf("Refusing to backfill: %v", err)
+	}
+	utils.SetHTTPConfig(cfg.HTTP.GetTimeout(), cfg.HTTP.Retries)
+	utils.SetHTTPUserAgent(cfg.HTTP.UserAgent)
+	utils.SetHTTPProxy(cfg.HTTP.HTTPProxy, cfg.HTTP.HTTPSProxy, cfg.HTTP.NoProxy)
+	packages.SetPackagesConfig(cfg)
+	releases.SetReleasesConfig(cfg)
+
+	opts := backfill.Options{Interval: *interval, PageSize: *pageSize, DryRun: *dryRun}
+	if opts.Since, err = parseDay("since", *since); err != nil {
+		log.Fatal(err)
+	}
+	if opts.Until, err = parseDay("until", *until); err != nil {
+		log.Fatal(err)
+	}
+
+	supported, err := releases.ReadSupportedReleases(*releasesFile)
+	if err != nil {
+		log.Fatalf("Failed to read supported releases: %v", err)
+	}
+	supported = selectReleases(releases.EnabledReleases(supported), *only)
+	if len(supported) == 0 {
+		log.Fatalf("No tracked package matches -packages %q", *only)
+	}
+
+	udaEntries, err := drivers.GetNvidiaDriverEntries(cfg, releases.GetUniqueBranchMajors(supported))
+	if err != nil {
+		log.Fatalf("Failed to fetch the nvidia.com releases: %v", err)
+	}
+	_, allBranches, err := drivers.GetLatestServerDriverVersions(cfg)
+	if err != nil {
+		log.Fatalf("Failed to fetch the datacenter releases: %v", err)
+	}
+	timelines := backfill.UpstreamTimelines(supported, udaEntries, allBranches)
+
+	store := history.NewStore(cfg.History.GetDataFile())
+	summary, err := backfill.Run(cfg, supported, timelines, store, opts)
+	log.Printf("Backfill: %d packages, %d Launchpad requests, %d publications, %d daily observations, %d added to %s",
+		summary.Packages, summary.Requests, summary.Publications, summary.Observations, summary.Added, cfg.History.GetDataFile())
+	if err != nil {
+		log.Fatalf("Backfill stopped: %v", err)
+	}
+}
+
+// genMonitoring writes the Prometheus alerting rules and the Grafana dashboard matching the
+// alerts and SLOs of a configuration, so external monitoring can be regenerated with it
+func genMonitoring(args []string) {
+	flags := flag.NewFlagSet("gen-monitoring", flag.ExitOnError)
+	configFile := flags.String("config", "config.json", "Configuration file path")
+	rulesFile := flags.String("rules", "nvidia-monitor-rules.yml", "Prometheus rule file to write; - for standard output")
+	dashboardFile := flags.String("dashboard", "nvidia-monitor-dashboard.json", "Grafana dashboard file to write; - for standard output")
+	flags.Parse(args)
+
+	cfg, err := config.LoadConfig(*configFile)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	rules, err := monitoring.MarshalRules(monitoring.Rules(cfg))
+	if err != nil {
+		log.Fatalf("Failed to encode the alerting rules: %v", err)
+	}
+	dashboard, err := monitoring.MarshalDashboard(monitoring.BuildDashboard(cfg))
+	if err != nil {
+		log.Fatalf("Failed to encode the dashboard: %v", err)
+	}
+	for _, output := range []struct {
+		file    string
+		content []byte
+	}{{*rulesFile, rules}, {*dashboardFile, append(dashboard, '\n')}} {
+		if output.file == "-" {
+			os.Stdout.Write(output.content)
+			continue
+		}
+		if err := os.WriteFile(output.file, output.content, 0644); err != nil {
+			log.Fatalf("Failed to write %s: %v", output.file, err)
+		}
+		log.Printf("Wrote %s", output.file)
+	}
+}
+
+// parseDay parses an optional YYYY-MM-DD flag value
+func parseDay(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	day, err := time.Parse(history.DateFormat, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("-%s must be a YYYY-MM-DD date: %v", name, err)
+	}
+	return day, nil
+}
+
+// selectReleases keeps the releases named in a comma-separated list of branches or source
+// packages, or all of them when the list is empty
+func selectReleases(supported []releases.SupportedRelease, list string) []releases.SupportedRelease {
+	if list == "" {
+		return supported
+	}
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		wanted[strings.TrimSpace(name)] = true
+	}
+	var selected []releases.SupportedRelease
+	for _, rel := range supported {
+		if wanted[rel.BranchName] || wanted[rel.PackageName()] {
+			selected = append(selected, rel)
+		}
+	}
+	return selected
+}
@@ -4,9 +4,12 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 	"path/filepath"
 
 	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/doctor"
+	"nvidia_driver_monitor/internal/utils"
 	"nvidia_driver_monitor/internal/web"
 )
 
@@ -17,8 +20,12 @@ func main() {
 	var keyFile = flag.String("key", "server.key", "Private key file path (for HTTPS)")
 	var configFile = flag.String("config", "config.json", "Configuration file path")
 	var supportedReleasesFile = flag.String("releases", "data/supportedReleases.json", "Supported releases file path")
+	var releasesProfile = flag.String("profile", "", "Supported releases profile to apply (overrides supported_releases.profile)")
 	var rateLimit = flag.Int("rate-limit", 0, "Rate limit (requests per minute, 0 to use config)")
 	var templateDir = flag.String("templates", "templates", "Templates directory path")
+	var exportDir = flag.String("export", "", "Render the dashboard as static HTML/JSON into this directory and exit")
+	var offline = flag.Bool("offline", false, "Block outbound requests other than to a local mock server (overrides http.offline)")
+	var oneshotDir = flag.String("oneshot", "", "Refresh once, write the index page, API data and snapshot into this directory and exit with the health as status")
 	flag.Parse()
 
 	fmt.Printf("Starting NVIDIA Driver Package Status Web Server...\n")
@@ -33,6 +40,31 @@ func main() {
 	if *rateLimit > 0 {
 		cfg.RateLimit.RequestsPerMinute = *rateLimit
 	}
+	if *releasesProfile != "" {
+		cfg.Releases.Profile = *releasesProfile
+	}
+	if *offline {
+		cfg.HTTP.Offline = true
+	}
+
+	// Refuse to serve mock data as production data, or production data as mock data
+	if err := cfg.ValidateMode(); err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+
+	// Probe the upstreams once, failing fast or switching features off as configured. The
+	// probes go through the configured user agent and proxies; offline there is nothing to probe.
+	if cfg.Startup.Probes && cfg.HTTP.Offline {
+		log.Printf("Startup probes skipped: offline mode blocks the upstreams")
+	} else if cfg.Startup.Probes {
+		utils.SetHTTPUserAgent(cfg.HTTP.UserAgent)
+		utils.SetHTTPProxy(cfg.HTTP.HTTPProxy, cfg.HTTP.HTTPSProxy, cfg.HTTP.NoProxy)
+		results, err := doctor.StartupProbes(cfg, utils.NewHTTPClient())
+		doctor.LogProbes(results)
+		if err != nil {
+			log.Fatalf("Refusing to start: %v", err)
+		}
+	}
 
 	// Create template path
 	templatePath, err := filepath.Abs(*templateDir)
@@ -40,12 +72,30 @@ func main() {
 		log.Fatalf("Failed to resolve template directory: %v", err)
 	}
 
-	// Create and start web service with configuration
-	webService, err := web.NewWebServiceWithConfig(cfg, templatePath, *supportedReleasesFile)
-	if err != nil {
-		log.Fatalf("Failed to create web service: %v", err)
+	// Static site export mode: render once and exit without starting the server
+	if *exportDir != "" {
+		fmt.Printf("Exporting static site to %s...\n", *exportDir)
+		if err := web.ExportStaticSite(cfg, templatePath, *supportedReleasesFile, *exportDir); err != nil {
+			log.Fatalf("Static export failed: %v", err)
+		}
+		fmt.Printf("Static site written to %s\n", *exportDir)
+		return
 	}
 
+	// Oneshot mode: refresh once for cron jobs, exit 0 when healthy and 2 when degraded
+	if *oneshotDir != "" {
+		fmt.Printf("Running once, writing output to %s...\n", *oneshotDir)
+		result, err := web.RunOneshot(cfg, templatePath, *supportedReleasesFile, *oneshotDir)
+		if err != nil {
+			log.Fatalf("Oneshot run failed: %v", err)
+		}
+		fmt.Printf("Oneshot run %s, output written to %s\n", result.Status, *oneshotDir)
+		os.Exit(result.ExitCode())
+	}
+
+	// Create and start web service with configuration
+	webService := web.NewWebService(cfg, templatePath, *supportedReleasesFile)
+
 	// Configure HTTPS if requested
 	if *enableHTTPS || cfg.Server.EnableHTTPS {
 		webService.EnableHTTPS = true
@@ -4,6 +4,7 @@ go 1.21
 
 require (
 	github.com/knqyf263/go-deb-version v0.0.0-20190517075300-09fca494f03d
+	github.com/ulikunitz/xz v0.5.9
 	golang.org/x/net v0.17.0
 )
 
@@ -11,3 +12,5 @@ require (
 	gopkg.in/yaml.v2 v2.4.0
 	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/text v0.13.0 // indirect
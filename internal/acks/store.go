@@ -0,0 +1,105 @@
+package acks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"nvidia_driver_monitor/internal/cellstore"
+)
+
+// MaxReasonLength is the longest reason accepted, in characters
+const MaxReasonLength = 200
+
+// Acknowledgement marks an outdated package/series cell as an intentional decision, e.g. a
+// branch frozen in a series, until it expires
+type Acknowledgement struct {
+	Package   string    `json:"package"` // e.g. "nvidia-graphics-drivers-470"
+	Series    string    `json:"series"`  // Codename, e.g. "bionic"
+	Reason    string    `json:"reason"`  // e.g. "470 frozen on bionic"
+	Expires   time.Time `json:"expires"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+}
+
+// SchemaVersion is the version of the format of the acknowledgements file, bumped when it changes
+const SchemaVersion = 1
+
+// Cell returns the package and series the acknowledgement is attached to
+func (a Acknowledgement) Cell() (string, string) {
+	return a.Package, a.Series
+}
+
+// Store keeps the acknowledgements and persists them to disk. Expired acknowledgements are
+// kept, so the file records past decisions, but no longer apply.
+type Store struct {
+	cells *cellstore.Store[Acknowledgement]
+}
+
+// NewStore creates a store, loading previously persisted acknowledgements if available
+func NewStore(persistFile string) *Store {
+	return &Store{cells: cellstore.New[Acknowledgement](persistFile, "acknowledgements")}
+}
+
+// Validate checks an acknowledgement can be stored at now, trimming its reason
+func Validate(ack *Acknowledgement, now time.Time) error {
+	ack.Reason = strings.TrimSpace(ack.Reason)
+	if ack.Package == "" || ack.Series == "" {
+		return fmt.Errorf("package and series are required")
+	}
+	if ack.Reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if utf8.RuneCountInString(ack.Reason) > MaxReasonLength {
+		return fmt.Errorf("reason is longer than %d characters", MaxReasonLength)
+	}
+	if !ack.Expires.After(now) {
+		return fmt.Errorf("expires must be in the future")
+	}
+	return nil
+}
+
+// Set acknowledges a cell until expires, replacing the previous acknowledgement
+func (s *Store) Set(packageName, series, reason string, expires time.Time, createdBy string, now time.Time) (*Acknowledgement, error) {
+	ack := Acknowledgement{Package: packageName, Series: series, Reason: reason, Expires: expires, CreatedAt: now, CreatedBy: createdBy}
+	if err := Validate(&ack, now); err != nil {
+		return nil, err
+	}
+	s.cells.Set(ack)
+	return &ack, nil
+}
+
+// SetAll stores a batch of acknowledgements created at now, replacing those of the same cells.
+// Nothing is stored unless every acknowledgement is valid, and the batch is persisted once.
+func (s *Store) SetAll(list []Acknowledgement, now time.Time) error {
+	batch := make([]Acknowledgement, len(list))
+	for i, ack := range list {
+		ack.CreatedAt = now
+		if err := Validate(&ack, now); err != nil {
+			return fmt.Errorf("%s/%s: %w", ack.Package, ack.Series, err)
+		}
+		batch[i] = ack
+	}
+	s.cells.Set(batch...)
+	return nil
+}
+
+// Remove deletes the acknowledgement of a cell, reporting whether it existed
+func (s *Store) Remove(packageName, series string) bool {
+	return s.cells.Remove(packageName, series)
+}
+
+// Active returns the acknowledgement of a cell unless it has expired at now
+func (s *Store) Active(packageName, series string, now time.Time) (Acknowledgement, bool) {
+	ack, ok := s.cells.Get(packageName, series)
+	if !ok || !ack.Expires.After(now) {
+		return Acknowledgement{}, false
+	}
+	return ack, true
+}
+
+// List returns the acknowledgements still active at now ordered by package and series
+func (s *Store) List(now time.Time) []Acknowledgement {
+	return s.cells.List(func(ack Acknowledgement) bool { return ack.Expires.After(now) })
+}
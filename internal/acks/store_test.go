@@ -0,0 +1,87 @@
+package acks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStorePersistsAcknowledgements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acknowledgements.json")
+	store := NewStore(path)
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	week := now.Add(7 * 24 * time.Hour)
+
+	tests := []struct {
+		reason  string
+		expires time.Time
+	}{
+		{"", week},
+		{"   ", week},
+		{strings.Repeat("x", MaxReasonLength+1), week},
+		{"470 frozen on bionic", now},
+		{"470 frozen on bionic", now.Add(-time.Hour)},
+	}
+	for _, tt := range tests {
+		if _, err := store.Set("nvidia-graphics-drivers-470", "bionic", tt.reason, tt.expires, "", now); err == nil {
+			t.Errorf("Set(%q, %v) expected an error", tt.reason, tt.expires)
+		}
+	}
+
+	if _, err := store.Set("nvidia-graphics-drivers-470", "bionic", " 470 frozen on bionic ", week, "jdoe", now); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+	if _, err := store.Set("nvidia-graphics-drivers-390", "focal", "end of life", now.Add(time.Hour), "", now); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+
+	reloaded := NewStore(path)
+	if ack, ok := reloaded.Active("nvidia-graphics-drivers-470", "bionic", now); !ok || ack.Reason != "470 frozen on bionic" || ack.CreatedBy != "jdoe" {
+		t.Errorf("Active(470, bionic) after reload = %+v, %v, expected the trimmed acknowledgement", ack, ok)
+	}
+	if list := reloaded.List(now); len(list) != 2 || list[0].Package != "nvidia-graphics-drivers-390" {
+		t.Errorf("List() = %+v, expected two acknowledgements ordered by package", list)
+	}
+
+	later := now.Add(2 * time.Hour)
+	if _, ok := reloaded.Active("nvidia-graphics-drivers-390", "focal", later); ok {
+		t.Errorf("Active(390, focal) after expiry expected no acknowledgement")
+	}
+	if list := reloaded.List(later); len(list) != 1 {
+		t.Errorf("List() after expiry = %+v, expected only the unexpired acknowledgement", list)
+	}
+
+	if !store.Remove("nvidia-graphics-drivers-470", "bionic") || store.Remove("nvidia-graphics-drivers-470", "bionic") {
+		t.Errorf("Remove() should delete the acknowledgement once")
+	}
+	if _, ok := NewStore(path).Active("nvidia-graphics-drivers-470", "bionic", now); ok {
+		t.Errorf("Active(470, bionic) after removal expected no acknowledgement")
+	}
+}
+
+func TestSetAllIsAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acknowledgements.json")
+	store := NewStore(path)
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	week := now.Add(7 * 24 * time.Hour)
+
+	batch := []Acknowledgement{
+		{Package: "nvidia-graphics-drivers-470", Series: "jammy", Reason: "470 frozen on jammy", Expires: week},
+		{Package: "nvidia-graphics-drivers-390", Series: "focal", Reason: "end of life", Expires: now},
+	}
+	if err := store.SetAll(batch, now); err == nil || !strings.Contains(err.Error(), "nvidia-graphics-drivers-390/focal") {
+		t.Errorf("SetAll() with an expired acknowledgement = %v, expected an error naming it", err)
+	}
+	if list := store.List(now); len(list) != 0 {
+		t.Errorf("List() after a refused batch = %+v, expected nothing stored", list)
+	}
+
+	batch[1].Expires = week
+	if err := store.SetAll(batch, now); err != nil {
+		t.Fatalf("SetAll() returned error: %v", err)
+	}
+	if list := NewStore(path).List(now); len(list) != 2 || !list[0].CreatedAt.Equal(now) {
+		t.Errorf("List() after reload = %+v, expected the batch created at now", list)
+	}
+}
@@ -0,0 +1,255 @@
+package advisories
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/atomicfile"
+)
+
+// driverPackagePrefix is stripped from driver package names to get the branch
+const driverPackagePrefix = "nvidia-graphics-drivers-"
+
+// Advisory is a known bad driver and kernel combination, with links to the bugs describing it
+type Advisory struct {
+	ID            string    `json:"id"`
+	Branch        string    `json:"branch"`                   // Driver branch, e.g. "550" or "535-server"
+	DriverVersion string    `json:"driver_version,omitempty"` // Version prefix, e.g. "550.120"; empty matches every version
+	KernelSource  string    `json:"kernel_source,omitempty"`  // e.g. "linux-hwe-6.8"; empty matches every source
+	KernelVersion string    `json:"kernel_version,omitempty"` // Version prefix, e.g. "6.8.0-45"; empty matches every version
+	Series        string    `json:"series,omitempty"`         // Codename, e.g. "jammy"; empty matches every series
+	Title         string    `json:"title"`
+	Links         []string  `json:"links"`
+	CreatedAt     time.Time `json:"created_at"`
+	CreatedBy     string    `json:"created_by,omitempty"`
+}
+
+// Validate checks that an advisory identifies a combination and has well-formed links
+func (a *Advisory) Validate() error {
+	if strings.TrimSpace(a.Branch) == "" {
+		return fmt.Errorf("branch is required")
+	}
+	if a.KernelSource == "" && a.KernelVersion == "" {
+		return fmt.Errorf("kernel_source or kernel_version is required")
+	}
+	if strings.TrimSpace(a.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	for _, link := range a.Links {
+		parsed, err := url.Parse(link)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid link %q", link)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the advisory applies to a driver published for a kernel. Only
+// published versions match, so placeholders such as "N/A" never do.
+func (a *Advisory) Matches(series, kernelSource, kernelVersion, driverName, driverVersion string) bool {
+	if strings.TrimPrefix(driverName, driverPackagePrefix) != a.Branch {
+		return false
+	}
+	if a.Series != "" && a.Series != series {
+		return false
+	}
+	if a.KernelSource != "" && a.KernelSource != kernelSource {
+		return false
+	}
+	if !isPublished(kernelVersion) || !strings.HasPrefix(kernelVersion, a.KernelVersion) {
+		return false
+	}
+	if a.DriverVersion != "" && (!isPublished(driverVersion) || !strings.HasPrefix(driverVersion, a.DriverVersion)) {
+		return false
+	}
+	return true
+}
+
+// isPublished reports whether a version field holds a version rather than a placeholder
+func isPublished(version string) bool {
+	return version != "" && version != "N/A" && version != "ERROR" && version != "-"
+}
+
+// ErrNotFound is returned when updating an advisory that does not exist
+var ErrNotFound = errors.New("advisory not found")
+
+// SchemaVersion is the version of the format of the advisories file, bumped when it changes
+const SchemaVersion = 1
+
+// Store keeps the curated advisories and persists them to disk
+type Store struct {
+	mu          sync.RWMutex
+	advisories  map[string]*Advisory
+	persistFile string
+	revision    int // Bumped on every change
+}
+
+// NewStore creates a store, loading previously persisted advisories if available
+func NewStore(persistFile string) *Store {
+	s := &Store{
+		advisories:  make(map[string]*Advisory),
+		persistFile: persistFile,
+	}
+	if err := s.loadFromFile(); err != nil {
+		log.Printf("Warning: Could not load existing advisories: %v", err)
+	}
+	return s
+}
+
+// newID returns a random advisory identifier
+func newID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Add validates and stores a new advisory, returning it with its ID set
+func (s *Store) Add(advisory Advisory, createdBy string, now time.Time) (*Advisory, error) {
+	if err := advisory.Validate(); err != nil {
+		return nil, err
+	}
+	advisory.ID = newID()
+	advisory.CreatedAt = now
+	advisory.CreatedBy = createdBy
+
+	s.mu.Lock()
+	s.advisories[advisory.ID] = &advisory
+	s.revision++
+	s.mu.Unlock()
+	s.persist()
+
+	copied := advisory
+	return &copied, nil
+}
+
+// Update replaces an advisory, keeping its ID and creation details
+func (s *Store) Update(id string, advisory Advisory) (*Advisory, error) {
+	if err := advisory.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	existing, ok := s.advisories[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	advisory.ID = id
+	advisory.CreatedAt = existing.CreatedAt
+	advisory.CreatedBy = existing.CreatedBy
+	s.advisories[id] = &advisory
+	s.revision++
+	s.mu.Unlock()
+	s.persist()
+
+	copied := advisory
+	return &copied, nil
+}
+
+// Remove deletes an advisory, reporting whether it existed
+func (s *Store) Remove(id string) bool {
+	s.mu.Lock()
+	_, ok := s.advisories[id]
+	delete(s.advisories, id)
+	if ok {
+		s.revision++
+	}
+	s.mu.Unlock()
+	if ok {
+		s.persist()
+	}
+	return ok
+}
+
+// Revision changes whenever an advisory is added, updated or removed
+func (s *Store) Revision() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.revision
+}
+
+// List returns all advisories ordered by branch and creation time
+func (s *Store) List() []Advisory {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Advisory, 0, len(s.advisories))
+	for _, advisory := range s.advisories {
+		list = append(list, *advisory)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Branch != list[j].Branch {
+			return list[i].Branch < list[j].Branch
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Match returns the advisories that apply to a driver published for a kernel
+func (s *Store) Match(series, kernelSource, kernelVersion, driverName, driverVersion string) []Advisory {
+	var matches []Advisory
+	for _, advisory := range s.List() {
+		if advisory.Matches(series, kernelSource, kernelVersion, driverName, driverVersion) {
+			matches = append(matches, advisory)
+		}
+	}
+	return matches
+}
+
+// persist saves the advisories, logging failures
+func (s *Store) persist() {
+	if err := s.saveToFile(); err != nil {
+		log.Printf("Warning: Failed to persist advisories: %v", err)
+	}
+}
+
+// saveToFile writes all advisories to the persistence file
+func (s *Store) saveToFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return atomicfile.WriteJSON(s.persistFile, s.advisories)
+}
+
+// loadFromFile restores advisories from the persistence file
+func (s *Store) loadFromFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	jsonData, err := os.ReadFile(s.persistFile)
+	if os.IsNotExist(err) {
+		return nil // No existing data, start fresh
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read advisories file: %w", err)
+	}
+
+	advisories := make(map[string]*Advisory)
+	if err := json.Unmarshal(jsonData, &advisories); err != nil {
+		return fmt.Errorf("failed to parse advisories JSON: %w", err)
+	}
+
+	s.mu.Lock()
+	s.advisories = advisories
+	s.mu.Unlock()
+
+	log.Printf("Loaded %d advisories from %s", len(advisories), s.persistFile)
+	return nil
+}
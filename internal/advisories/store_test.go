@@ -0,0 +1,60 @@
+package advisories
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAdvisoryMatches(t *testing.T) {
+	advisory := Advisory{Branch: "470", DriverVersion: "470.256", KernelVersion: "6.8.0-45", Title: "Black screen on boot"}
+	for _, test := range []struct {
+		series, kernelSource, kernelVersion, driverName, driverVersion string
+		expected                                                       bool
+	}{
+		{"noble", "linux", "6.8.0-45.45", "nvidia-graphics-drivers-470", "470.256.02-0ubuntu0.24.04.1", true},
+		{"jammy", "linux-hwe-6.8", "6.8.0-45.45~22.04.1", "nvidia-graphics-drivers-470", "470.256.02-0ubuntu0.22.04.1", true},
+		{"noble", "linux", "6.8.0-47.47", "nvidia-graphics-drivers-470", "470.256.02-0ubuntu0.24.04.1", false},
+		{"noble", "linux", "6.8.0-45.45", "nvidia-graphics-drivers-470-server", "470.256.02-0ubuntu0.24.04.1", false},
+		{"noble", "linux", "6.8.0-45.45", "nvidia-graphics-drivers-470", "470.239.06-0ubuntu0.24.04.1", false},
+		{"noble", "linux", "6.8.0-45.45", "nvidia-graphics-drivers-470", "", false},
+		{"noble", "linux", "N/A", "nvidia-graphics-drivers-470", "470.256.02-0ubuntu0.24.04.1", false},
+	} {
+		got := advisory.Matches(test.series, test.kernelSource, test.kernelVersion, test.driverName, test.driverVersion)
+		if got != test.expected {
+			t.Errorf("Matches(%s, %s, %s, %s) = %v, expected %v", test.series, test.kernelVersion, test.driverName, test.driverVersion, got, test.expected)
+		}
+	}
+}
+
+func TestStorePersistsAdvisories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "advisories.json")
+	store := NewStore(path)
+
+	if _, err := store.Add(Advisory{Branch: "470", Title: "No kernel"}, "", time.Now()); err == nil {
+		t.Errorf("Add() without a kernel expected an error")
+	}
+	if _, err := store.Add(Advisory{Branch: "470", KernelVersion: "6.8", Title: "Bad link", Links: []string{"javascript:alert(1)"}}, "", time.Now()); err == nil {
+		t.Errorf("Add() with a non-HTTP link expected an error")
+	}
+
+	added, err := store.Add(Advisory{Branch: "470", KernelSource: "linux-hwe-6.8", Title: "Build failure", Links: []string{"https://bugs.launchpad.net/bugs/1"}}, "jdoe", time.Now())
+	if err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+	if _, err := store.Update("missing", *added); err != ErrNotFound {
+		t.Errorf("Update(missing) = %v, expected ErrNotFound", err)
+	}
+	added.Title = "DKMS build failure"
+	if _, err := store.Update(added.ID, *added); err != nil {
+		t.Fatalf("Update() returned error: %v", err)
+	}
+
+	reloaded := NewStore(path).List()
+	if len(reloaded) != 1 || reloaded[0].Title != "DKMS build failure" || reloaded[0].CreatedBy != "jdoe" {
+		t.Fatalf("Reloaded advisories = %+v, expected the updated advisory", reloaded)
+	}
+	if !store.Remove(added.ID) || store.Remove(added.ID) {
+		t.Errorf("Remove() should delete the advisory once")
+	}
+}
@@ -0,0 +1,176 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/domain"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Alert severities
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Alert is a currently firing condition
+type Alert struct {
+	Name     string    `json:"name"`
+	Severity string    `json:"severity"`
+	Message  string    `json:"message"`
+	FiredAt  time.Time `json:"fired_at"`
+	Silenced bool      `json:"silenced,omitempty"` // Fired during maintenance and not delivered yet
+	// ReleaseNotes is set on notices announcing a new archive version
+	ReleaseNotes *domain.ReleaseNotes `json:"release_notes,omitempty"`
+}
+
+// webhookPayload is posted to the configured webhook on alert state changes
+type webhookPayload struct {
+	State string `json:"state"` // "firing", "resolved" or "notice"
+	Alert
+}
+
+var (
+	alertsMu   sync.RWMutex
+	active     = make(map[string]*Alert)
+	webhookURL string
+)
+
+// SetAlertsConfig sets the global configuration for alert delivery
+func SetAlertsConfig(cfg *config.Config) {
+	alertsMu.Lock()
+	defer alertsMu.Unlock()
+	if cfg != nil {
+		webhookURL = cfg.Alerts.WebhookURL
+		setMaintenanceWindows(cfg.Alerts.MaintenanceWindows)
+	}
+}
+
+// Fire raises an alert. Repeated calls for an already firing alert only update its message,
+// and deliver it if it was silenced by a maintenance window that has since ended.
+func Fire(name, severity, message string) {
+	now := time.Now()
+	alertsMu.Lock()
+	silenced := len(activeWindowsLocked(now)) > 0
+	if existing, ok := active[name]; ok {
+		existing.Message = message
+		existing.Severity = severity
+		deliver := existing.Silenced && !silenced
+		if deliver {
+			existing.Silenced = false
+		}
+		alert := *existing
+		url := webhookURL
+		alertsMu.Unlock()
+
+		if deliver {
+			log.Printf("ALERT [%s] %s: %s (maintenance ended)", severity, name, message)
+			notify(url, "firing", alert)
+		}
+		return
+	}
+	alert := &Alert{Name: name, Severity: severity, Message: message, FiredAt: now, Silenced: silenced}
+	active[name] = alert
+	url := webhookURL
+	alertsMu.Unlock()
+
+	if silenced {
+		log.Printf("ALERT [%s] %s: %s (silenced by maintenance window)", severity, name, message)
+		return
+	}
+	log.Printf("ALERT [%s] %s: %s", severity, name, message)
+	notify(url, "firing", *alert)
+}
+
+// Resolve clears a firing alert
+func Resolve(name string) {
+	alertsMu.Lock()
+	alert, ok := active[name]
+	if ok {
+		delete(active, name)
+	}
+	url := webhookURL
+	alertsMu.Unlock()
+
+	// Alerts that were never delivered are not reported as resolved either
+	if ok && !alert.Silenced {
+		log.Printf("RESOLVED %s", name)
+		notify(url, "resolved", *alert)
+	}
+}
+
+// Notify delivers a one-time informational notice that never becomes an active alert.
+// It returns false without delivering while a maintenance window is active, so callers can retry later.
+func Notify(name, severity, message string) bool {
+	return NotifyWithReleaseNotes(name, severity, message, nil)
+}
+
+// NotifyWithReleaseNotes delivers a notice like Notify, with the release notes of a new archive
+// version in its payload
+func NotifyWithReleaseNotes(name, severity, message string, notes *domain.ReleaseNotes) bool {
+	now := time.Now()
+	alertsMu.RLock()
+	silenced := len(activeWindowsLocked(now)) > 0
+	url := webhookURL
+	alertsMu.RUnlock()
+
+	if silenced {
+		return false
+	}
+	log.Printf("NOTICE [%s] %s: %s", severity, name, message)
+	notify(url, "notice", Alert{Name: name, Severity: severity, Message: message, FiredAt: now, ReleaseNotes: notes})
+	return true
+}
+
+// Active returns all firing alerts ordered by name
+func Active() []*Alert {
+	alertsMu.RLock()
+	defer alertsMu.RUnlock()
+
+	list := make([]*Alert, 0, len(active))
+	for _, alert := range active {
+		copied := *alert
+		list = append(list, &copied)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// IsFiring reports whether the named alert is currently firing
+func IsFiring(name string) bool {
+	alertsMu.RLock()
+	defer alertsMu.RUnlock()
+	_, ok := active[name]
+	return ok
+}
+
+// notify posts an alert state change to the webhook, if one is configured
+func notify(url, state string, alert Alert) {
+	if url == "" {
+		return
+	}
+	go func() {
+		body, err := json.Marshal(webhookPayload{State: state, Alert: alert})
+		if err != nil {
+			log.Printf("Failed to encode alert %s: %v", alert.Name, err)
+			return
+		}
+		client := utils.NewHTTPClient()
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Printf("Failed to deliver alert %s: %v", alert.Name, err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			log.Printf("Alert webhook returned HTTP %d for %s", resp.StatusCode, alert.Name)
+		}
+	}()
+}
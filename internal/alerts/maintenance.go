@@ -0,0 +1,255 @@
+package alerts
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// Window is a maintenance period during which alert delivery is silenced
+type Window struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Reason   string    `json:"reason,omitempty"`
+	Schedule string    `json:"schedule,omitempty"` // Set for occurrences of configured recurring windows
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+// recurringWindow is a configured maintenance window with its parsed schedule
+type recurringWindow struct {
+	config.MaintenanceWindow
+	schedule *schedule
+}
+
+var (
+	recurringWindows []recurringWindow
+	adhocWindows     = make(map[string]*Window)
+	nextWindowID     int
+)
+
+// setMaintenanceWindows replaces the recurring windows; callers hold alertsMu
+func setMaintenanceWindows(windows []config.MaintenanceWindow) {
+	recurringWindows = nil
+	for _, window := range windows {
+		parsed, err := parseSchedule(window.Schedule)
+		if err != nil {
+			log.Printf("Warning: Ignoring maintenance window %q: %v", window.Name, err)
+			continue
+		}
+		recurringWindows = append(recurringWindows, recurringWindow{MaintenanceWindow: window, schedule: parsed})
+	}
+}
+
+// occurrenceAt returns the occurrence of a recurring window covering t, if any
+func (r *recurringWindow) occurrenceAt(t time.Time) (*Window, bool) {
+	t = t.UTC()
+	duration := r.GetDuration()
+	latestStart := t.Truncate(time.Minute)
+	for start := latestStart; t.Sub(start) < duration; start = start.Add(-time.Minute) {
+		if r.schedule.matches(start) {
+			return &Window{
+				ID:       "config:" + r.Name,
+				Name:     r.Name,
+				Reason:   r.Reason,
+				Schedule: r.Schedule,
+				Start:    start,
+				End:      start.Add(duration),
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// activeWindowsLocked returns the maintenance windows covering t; callers hold alertsMu
+func activeWindowsLocked(t time.Time) []*Window {
+	var windows []*Window
+	for i := range recurringWindows {
+		if window, ok := recurringWindows[i].occurrenceAt(t); ok {
+			windows = append(windows, window)
+		}
+	}
+	for _, window := range adhocWindows {
+		if !t.Before(window.Start) && t.Before(window.End) {
+			copied := *window
+			windows = append(windows, &copied)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].ID < windows[j].ID })
+	return windows
+}
+
+// ActiveMaintenance returns the maintenance windows covering the given time
+func ActiveMaintenance(t time.Time) []*Window {
+	alertsMu.RLock()
+	defer alertsMu.RUnlock()
+	return activeWindowsLocked(t)
+}
+
+// AdhocMaintenance returns the ad-hoc windows that have not ended yet, ordered by start
+func AdhocMaintenance(t time.Time) []*Window {
+	alertsMu.Lock()
+	defer alertsMu.Unlock()
+
+	var windows []*Window
+	for id, window := range adhocWindows {
+		if !t.Before(window.End) {
+			delete(adhocWindows, id)
+			continue
+		}
+		copied := *window
+		windows = append(windows, &copied)
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Start.Before(windows[j].Start) })
+	return windows
+}
+
+// RecurringMaintenance returns the configured recurring windows
+func RecurringMaintenance() []config.MaintenanceWindow {
+	alertsMu.RLock()
+	defer alertsMu.RUnlock()
+
+	windows := make([]config.MaintenanceWindow, 0, len(recurringWindows))
+	for _, window := range recurringWindows {
+		windows = append(windows, window.MaintenanceWindow)
+	}
+	return windows
+}
+
+// AddMaintenance schedules an ad-hoc maintenance window
+func AddMaintenance(name, reason string, start, end time.Time) (*Window, error) {
+	if name == "" {
+		return nil, fmt.Errorf("maintenance window name is required")
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("maintenance window must end after it starts")
+	}
+	if !end.After(time.Now()) {
+		return nil, fmt.Errorf("maintenance window has already ended")
+	}
+
+	alertsMu.Lock()
+	defer alertsMu.Unlock()
+	nextWindowID++
+	window := &Window{
+		ID:     strconv.Itoa(nextWindowID),
+		Name:   name,
+		Reason: reason,
+		Start:  start.UTC(),
+		End:    end.UTC(),
+	}
+	adhocWindows[window.ID] = window
+	log.Printf("Maintenance window %s (%s) scheduled from %s to %s", window.ID, name, window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339))
+
+	copied := *window
+	return &copied, nil
+}
+
+// RemoveMaintenance cancels an ad-hoc maintenance window, reporting whether it existed
+func RemoveMaintenance(id string) bool {
+	alertsMu.Lock()
+	defer alertsMu.Unlock()
+	if _, ok := adhocWindows[id]; !ok {
+		return false
+	}
+	delete(adhocWindows, id)
+	return true
+}
+
+// schedule is a parsed five-field cron expression (minute hour day-of-month month day-of-week)
+type schedule struct {
+	minutes, hours, days, months, weekdays map[int]bool
+	anyDay, anyWeekday                     bool
+}
+
+// parseSchedule parses a cron expression supporting "*", lists, ranges and steps
+func parseSchedule(expr string) (*schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q must have 5 fields", expr)
+	}
+
+	s := &schedule{anyDay: fields[2] == "*", anyWeekday: fields[4] == "*"}
+	var err error
+	if s.minutes, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if s.hours, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if s.days, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if s.months, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if s.weekdays, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	if s.weekdays[7] {
+		s.weekdays[0] = true // Both 0 and 7 mean Sunday
+	}
+	return s, nil
+}
+
+// parseField expands one cron field into the set of values it matches
+func parseField(field string, min, max int) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid step in %q", field)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		low, high := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value in %q", field)
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid range in %q", field)
+				}
+			}
+		}
+		if low < min || high > max || low > high {
+			return nil, fmt.Errorf("%q is out of range %d-%d", field, min, max)
+		}
+		for v := low; v <= high; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// matches reports whether the schedule fires at the minute of t
+func (s *schedule) matches(t time.Time) bool {
+	if !s.minutes[t.Minute()] || !s.hours[t.Hour()] || !s.months[int(t.Month())] {
+		return false
+	}
+	dayMatch := s.days[t.Day()]
+	weekdayMatch := s.weekdays[int(t.Weekday())]
+	// As in cron, a restricted day-of-month and day-of-week match when either does
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekdayMatch
+	case s.anyWeekday:
+		return dayMatch
+	}
+	return dayMatch || weekdayMatch
+}
@@ -0,0 +1,81 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+func TestScheduleMatches(t *testing.T) {
+	tests := []struct {
+		expr     string
+		at       time.Time
+		expected bool
+	}{
+		{"0 2 * * 6", time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC), true}, // Saturday
+		{"0 2 * * 6", time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC), false},
+		{"*/15 * * * *", time.Date(2026, 10, 17, 9, 45, 0, 0, time.UTC), true},
+		{"*/15 * * * *", time.Date(2026, 10, 17, 9, 50, 0, 0, time.UTC), false},
+		{"30 22 1-7 * 0", time.Date(2026, 10, 4, 22, 30, 0, 0, time.UTC), true},  // Day 4 matches the range
+		{"30 22 1-7 * 7", time.Date(2026, 10, 18, 22, 30, 0, 0, time.UTC), true}, // Sunday matches either field
+	}
+
+	for _, test := range tests {
+		s, err := parseSchedule(test.expr)
+		if err != nil {
+			t.Fatalf("parseSchedule(%s) returned error: %v", test.expr, err)
+		}
+		if result := s.matches(test.at); result != test.expected {
+			t.Errorf("matches(%s, %s) = %t, expected %t", test.expr, test.at, result, test.expected)
+		}
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, expr := range []string{"", "0 2 * *", "60 * * * *", "0 2 * * mon", "*/0 * * * *"} {
+		if _, err := parseSchedule(expr); err == nil {
+			t.Errorf("parseSchedule(%s) expected an error", expr)
+		}
+	}
+}
+
+func TestMaintenanceSilencesAlerts(t *testing.T) {
+	defer func() {
+		SetAlertsConfig(&config.Config{})
+		adhocWindows = make(map[string]*Window)
+		Resolve("test-maintenance")
+	}()
+	SetAlertsConfig(&config.Config{})
+
+	window, err := AddMaintenance("archive-cleanup", "planned", time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("AddMaintenance returned error: %v", err)
+	}
+	if active := ActiveMaintenance(time.Now()); len(active) != 1 || active[0].ID != window.ID {
+		t.Fatalf("ActiveMaintenance() = %v, expected window %s", active, window.ID)
+	}
+
+	Fire("test-maintenance", SeverityWarning, "stale")
+	if list := Active(); len(list) != 1 || !list[0].Silenced {
+		t.Fatalf("Active() = %v, expected one silenced alert", list)
+	}
+
+	RemoveMaintenance(window.ID)
+	Fire("test-maintenance", SeverityWarning, "still stale")
+	if list := Active(); len(list) != 1 || list[0].Silenced {
+		t.Errorf("Active() = %v, expected the alert to be delivered after maintenance", list)
+	}
+}
+
+func TestRecurringWindowOccurrence(t *testing.T) {
+	window := recurringWindow{MaintenanceWindow: config.MaintenanceWindow{Name: "weekly", Schedule: "0 2 * * 6", Duration: "3h"}}
+	window.schedule, _ = parseSchedule(window.Schedule)
+
+	if occurrence, ok := window.occurrenceAt(time.Date(2026, 10, 17, 4, 59, 0, 0, time.UTC)); !ok || occurrence.Start.Hour() != 2 {
+		t.Errorf("occurrenceAt(04:59) = %v, %t, expected the 02:00 occurrence", occurrence, ok)
+	}
+	if _, ok := window.occurrenceAt(time.Date(2026, 10, 17, 5, 0, 0, 0, time.UTC)); ok {
+		t.Errorf("occurrenceAt(05:00) expected no occurrence after the window ends")
+	}
+}
@@ -0,0 +1,181 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Armor lines of a clearsigned InRelease file
+const (
+	signedMessageHeader = "-----BEGIN PGP SIGNED MESSAGE-----"
+	signatureHeader     = "-----BEGIN PGP SIGNATURE-----"
+)
+
+// maxReleaseSize bounds an InRelease download; the Ubuntu ones are a few hundred KB
+const maxReleaseSize = 16 * 1024 * 1024
+
+// Release is the metadata of a suite read from its InRelease file
+type Release struct {
+	Suite      string
+	Date       time.Time // When the archive last published the suite
+	ValidUntil time.Time // Zero when the suite does not expire
+	Components []string
+	Signed     bool // Whether the file carries an inline signature
+}
+
+// ReleaseURL returns the InRelease file of a suite under an archive root
+func ReleaseURL(mirror, suite string) string {
+	return fmt.Sprintf("%s/dists/%s/InRelease", strings.TrimSuffix(mirror, "/"), suite)
+}
+
+// ParseRelease reads the fields of an InRelease (or unsigned Release) file. The checksum lists
+// are skipped.
+func ParseRelease(data []byte) (*Release, error) {
+	release := &Release{}
+	text := string(data)
+	if strings.HasPrefix(strings.TrimSpace(text), signedMessageHeader) {
+		body, _, found := strings.Cut(text, signatureHeader)
+		if !found {
+			return nil, fmt.Errorf("signed message has no signature block")
+		}
+		release.Signed = true
+		// The armor headers end at the first empty line
+		if _, after, ok := strings.Cut(body, "\n\n"); ok {
+			text = after
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		var err error
+		switch field {
+		case "Suite":
+			release.Suite = value
+		case "Date":
+			release.Date, err = parseReleaseDate(value)
+		case "Valid-Until":
+			release.ValidUntil, err = parseReleaseDate(value)
+		case "Components":
+			release.Components = strings.Fields(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", field, value, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Release file: %w", err)
+	}
+	if release.Date.IsZero() {
+		return nil, fmt.Errorf("Release file has no Date field")
+	}
+	return release, nil
+}
+
+// parseReleaseDate parses an RFC 2822 date as written by the archive, e.g.
+// "Thu, 16 Oct 2026 21:08:51 UTC"
+func parseReleaseDate(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC1123, time.RFC1123Z, "Mon, 2 Jan 2006 15:04:05 MST", "Mon, 2 Jan 2006 15:04:05 -0700"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date format")
+}
+
+// FetchRelease downloads the InRelease file of a suite and returns its raw content
+func FetchRelease(url string) ([]byte, error) {
+	resp, err := utils.HTTPGetWithRetry(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status code %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxReleaseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// VerifySignature checks the inline signature of an InRelease file against a keyring with
+// gpgv, as apt does. Expired keys and signatures are reported as errors although gpgv
+// accepts them.
+func VerifySignature(data []byte, keyring string) error {
+	gpgv, err := exec.LookPath("gpgv")
+	if err != nil {
+		return fmt.Errorf("gpgv is not installed: %w", err)
+	}
+	if _, err := os.Stat(keyring); err != nil {
+		return fmt.Errorf("keyring %s: %w", keyring, err)
+	}
+
+	var status, stderr bytes.Buffer
+	cmd := exec.Command(gpgv, "--status-fd", "1", "--keyring", keyring, "-")
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &status
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	if problem := signatureProblem(status.String()); problem != "" {
+		return fmt.Errorf("%s", problem)
+	}
+	if runErr != nil {
+		return fmt.Errorf("gpgv failed: %v: %s", runErr, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// signatureProblem describes the first failure reported in gpgv status output, or returns ""
+// when it reports a good signature
+func signatureProblem(status string) string {
+	good := false
+	for _, line := range strings.Split(status, "\n") {
+		fields := strings.Fields(strings.TrimPrefix(line, "[GNUPG:] "))
+		if len(fields) == 0 {
+			continue
+		}
+		arg := ""
+		if len(fields) > 1 {
+			arg = fields[1]
+		}
+		switch fields[0] {
+		case "EXPKEYSIG":
+			return "signed with expired key " + arg
+		case "REVKEYSIG":
+			return "signed with revoked key " + arg
+		case "EXPSIG":
+			return "signature by " + arg + " has expired"
+		case "BADSIG":
+			return "bad signature by " + arg
+		case "NO_PUBKEY":
+			return "signing key " + arg + " is not in the keyring"
+		case "GOODSIG":
+			good = true
+		}
+	}
+	if !good {
+		return "no valid signature"
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package archive
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const signedRelease = `-----BEGIN PGP SIGNED MESSAGE-----
+Hash: SHA512
+
+Origin: Ubuntu
+Label: Ubuntu
+Suite: noble-updates
+Version: 24.04
+Codename: noble
+Date: Thu, 16 Oct 2026 21:08:51 UTC
+Architectures: amd64 arm64 armhf i386 ppc64el riscv64 s390x
+Components: main restricted universe multiverse
+Description: Ubuntu Noble Updates
+MD5Sum:
+ 0d0d0a3b8b1f3a0e0f5c1f8f2b3d7c6a   1234567 main/binary-amd64/Packages
+-----BEGIN PGP SIGNATURE-----
+
+iQIzBAEBCgAdFiEE
+-----END PGP SIGNATURE-----
+`
+
+func TestParseRelease(t *testing.T) {
+	release, err := ParseRelease([]byte(signedRelease))
+	if err != nil {
+		t.Fatalf("ParseRelease() returned error: %v", err)
+	}
+	if !release.Signed || release.Suite != "noble-updates" {
+		t.Errorf("release = %+v, expected signed noble-updates", release)
+	}
+	if want := time.Date(2026, 10, 16, 21, 8, 51, 0, time.UTC); !release.Date.Equal(want) {
+		t.Errorf("Date = %v, expected %v", release.Date, want)
+	}
+	if !release.ValidUntil.IsZero() {
+		t.Errorf("ValidUntil = %v, expected none", release.ValidUntil)
+	}
+	if strings.Join(release.Components, " ") != "main restricted universe multiverse" {
+		t.Errorf("Components = %v", release.Components)
+	}
+
+	unsigned := "Suite: jammy\nDate: Thu, 21 Apr 2022 17:16:08 UTC\nValid-Until: Thu, 28 Apr 2022 17:16:08 UTC\nComponents: main\n"
+	release, err = ParseRelease([]byte(unsigned))
+	if err != nil || release.Signed || release.ValidUntil.IsZero() {
+		t.Errorf("ParseRelease(unsigned) = %+v, %v, expected an unsigned release with Valid-Until", release, err)
+	}
+
+	for name, data := range map[string]string{
+		"truncated signature": strings.SplitN(signedRelease, "-----BEGIN PGP SIGNATURE-----", 2)[0],
+		"no date":             "Suite: jammy\nComponents: main\n",
+		"bad date":            "Suite: jammy\nDate: yesterday\n",
+	} {
+		if _, err := ParseRelease([]byte(data)); err == nil {
+			t.Errorf("ParseRelease(%s) expected an error", name)
+		}
+	}
+}
+
+func TestSignatureProblem(t *testing.T) {
+	for status, want := range map[string]string{
+		"[GNUPG:] NEWSIG\n[GNUPG:] GOODSIG 871920D1991BC93C Ubuntu Archive\n[GNUPG:] VALIDSIG ABC\n":   "",
+		"[GNUPG:] EXPKEYSIG 871920D1991BC93C Ubuntu Archive\n":                                         "signed with expired key 871920D1991BC93C",
+		"[GNUPG:] ERRSIG 871920D1991BC93C 1 10 01 1697000000 9\n[GNUPG:] NO_PUBKEY 871920D1991BC93C\n": "signing key 871920D1991BC93C is not in the keyring",
+		"[GNUPG:] BADSIG 871920D1991BC93C Ubuntu Archive\n":                                            "bad signature by 871920D1991BC93C",
+		"": "no valid signature",
+	} {
+		if got := signatureProblem(status); got != want {
+			t.Errorf("signatureProblem(%q) = %q, expected %q", status, got, want)
+		}
+	}
+}
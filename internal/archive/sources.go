@@ -0,0 +1,146 @@
+package archive
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	version "github.com/knqyf263/go-deb-version"
+	"github.com/ulikunitz/xz"
+
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// maxLineLength bounds a single Sources line; Binary fields of large sources are long
+const maxLineLength = 1024 * 1024
+
+// Index maps a suite (e.g. "noble-updates") to the greatest version of each source package
+type Index map[string]map[string]string
+
+// Version returns the version of a source package in a suite, or "" if it is not listed
+func (idx Index) Version(suite, packageName string) string {
+	return idx[suite][packageName]
+}
+
+// Suite returns the archive suite of a Launchpad pocket, e.g. ("noble", "Updates") -> "noble-updates"
+func Suite(series, pocket string) string {
+	if pocket == "" || pocket == "Release" {
+		return series
+	}
+	return series + "-" + strings.ToLower(pocket)
+}
+
+// SourcesURL returns the Sources.xz index of a suite and component under an archive root
+func SourcesURL(mirror, suite, component string) string {
+	return fmt.Sprintf("%s/dists/%s/%s/source/Sources.xz", strings.TrimSuffix(mirror, "/"), suite, component)
+}
+
+// ParseSources reads an uncompressed Sources index and returns the greatest version of each
+// wanted source package. Stanzas of other packages are skipped.
+func ParseSources(r io.Reader, wanted map[string]bool) (map[string]string, error) {
+	versions := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+
+	var name, ver string
+	flush := func() {
+		if name != "" && ver != "" && wanted[name] && newer(ver, versions[name]) {
+			versions[name] = ver
+		}
+		name, ver = "", ""
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		// Continuation lines of multi-line fields start with whitespace
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch field {
+		case "Package":
+			name = strings.TrimSpace(value)
+		case "Version":
+			ver = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Sources index: %w", err)
+	}
+	flush()
+
+	return versions, nil
+}
+
+// newer reports whether candidate is greater than current; unparsable candidates never win
+func newer(candidate, current string) bool {
+	if current == "" {
+		return true
+	}
+	c, err := version.NewVersion(candidate)
+	if err != nil {
+		return false
+	}
+	v, err := version.NewVersion(current)
+	if err != nil {
+		return true
+	}
+	return c.GreaterThan(v)
+}
+
+// FetchSources downloads and parses a Sources.xz index
+func FetchSources(url string, wanted map[string]bool) (map[string]string, error) {
+	resp, err := utils.HTTPGetWithRetry(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status code %d", url, resp.StatusCode)
+	}
+
+	reader, err := xz.NewReader(bufio.NewReader(resp.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", url, err)
+	}
+	return ParseSources(reader, wanted)
+}
+
+// FetchIndex reads the Sources.xz of every component of the given suites. Indexes that
+// cannot be fetched are returned as errors and left out, so the rest can still be compared.
+func FetchIndex(mirror string, suites, components []string, wanted map[string]bool) (Index, []error) {
+	index := make(Index)
+	var errs []error
+	for _, suite := range suites {
+		merged := make(map[string]string)
+		complete := true
+		for _, component := range components {
+			versions, err := FetchSources(SourcesURL(mirror, suite, component), wanted)
+			if err != nil {
+				errs = append(errs, err)
+				complete = false
+				continue
+			}
+			for name, ver := range versions {
+				if newer(ver, merged[name]) {
+					merged[name] = ver
+				}
+			}
+		}
+		// A suite missing a component could report a package as absent when it is not
+		if complete {
+			index[suite] = merged
+		}
+	}
+	return index, errs
+}
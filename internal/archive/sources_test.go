@@ -0,0 +1,77 @@
+package archive
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+)
+
+const restrictedSources = `Package: nvidia-graphics-drivers-550
+Binary: nvidia-driver-550,
+ nvidia-dkms-550
+Version: 550.120-0ubuntu0.24.04.1
+Section: restricted/libs
+
+Package: nvidia-graphics-drivers-550
+Version: 550.127.05-0ubuntu0.24.04.1
+
+Package: nvidia-graphics-drivers-550
+Version: 550.107.02-0ubuntu0.24.04.1
+
+Package: nvidia-settings
+Version: 510.47.03-0ubuntu4
+`
+
+func TestParseSources(t *testing.T) {
+	wanted := map[string]bool{"nvidia-graphics-drivers-550": true, "nvidia-graphics-drivers-570": true}
+	versions, err := ParseSources(strings.NewReader(restrictedSources), wanted)
+	if err != nil {
+		t.Fatalf("ParseSources() returned error: %v", err)
+	}
+	if len(versions) != 1 || versions["nvidia-graphics-drivers-550"] != "550.127.05-0ubuntu0.24.04.1" {
+		t.Errorf("ParseSources() = %v, expected only the greatest 550 version", versions)
+	}
+}
+
+func TestSuite(t *testing.T) {
+	for pocket, expected := range map[string]string{"Release": "noble", "Updates": "noble-updates", "Proposed": "noble-proposed"} {
+		if got := Suite("noble", pocket); got != expected {
+			t.Errorf("Suite(noble, %s) = %s, expected %s", pocket, got, expected)
+		}
+	}
+}
+
+func TestFetchIndex(t *testing.T) {
+	var compressed bytes.Buffer
+	writer, err := xz.NewWriter(&compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Write([]byte(restrictedSources))
+	writer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ubuntu/dists/noble-updates/restricted/source/Sources.xz" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	wanted := map[string]bool{"nvidia-graphics-drivers-550": true}
+	index, errs := FetchIndex(server.URL+"/ubuntu/", []string{"noble-updates", "noble-proposed"}, []string{"restricted"}, wanted)
+	if len(errs) != 1 {
+		t.Errorf("FetchIndex() errors = %v, expected the missing noble-proposed index", errs)
+	}
+	if got := index.Version("noble-updates", "nvidia-graphics-drivers-550"); got != "550.127.05-0ubuntu0.24.04.1" {
+		t.Errorf("Version(noble-updates) = %q, expected 550.127.05-0ubuntu0.24.04.1", got)
+	}
+	if _, ok := index["noble-proposed"]; ok {
+		t.Errorf("FetchIndex() kept noble-proposed although its index could not be fetched")
+	}
+}
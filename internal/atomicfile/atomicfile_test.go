@@ -0,0 +1,45 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteJSONReplacesTheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "notes.json")
+
+	for _, value := range []map[string]int{{"a": 1}, {"b": 2}} {
+		if err := WriteJSON(path, value); err != nil {
+			t.Fatalf("WriteJSON() error = %v", err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "{\n  \"b\": 2\n}" {
+		t.Errorf("file = %q (%v), expected the last value", data, err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	if err := WriteJSON(path, func() {}); err == nil {
+		t.Errorf("WriteJSON() of a function expected an error")
+	}
+}
+
+func TestWriteReplacesTheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "index.html")
+
+	for _, body := range []string{"first", "second"} {
+		if err := Write(path, []byte(body)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "second" {
+		t.Errorf("file = %q (%v), expected the last body", data, err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// Cookie names used by the login flow
+const (
+	sessionCookie = "nvdm_session"
+	stateCookie   = "nvdm_oidc_state"
+)
+
+// IdentityProvider authenticates users through a browser redirect flow
+type IdentityProvider interface {
+	// AuthCodeURL returns where to send the browser to log in
+	AuthCodeURL(state, nonce string) (string, error)
+	// Exchange turns the code returned to the callback into verified claims
+	Exchange(code, nonce string) (*Claims, error)
+}
+
+// Authenticator protects routes with provider logins and group based roles
+type Authenticator struct {
+	cfg      config.AuthConfig
+	provider IdentityProvider
+	sessions *sessionStore
+	secure   bool // Set cookies with the Secure flag (HTTPS deployments)
+	now      func() time.Time
+}
+
+// sessionKey is the request context key of the logged in session
+type sessionKey struct{}
+
+// NewAuthenticator returns the configured authenticator, or nil when login is disabled
+func NewAuthenticator(cfg *config.Config, secure bool) *Authenticator {
+	if cfg == nil || !cfg.Auth.OIDC.Enabled {
+		return nil
+	}
+	return newAuthenticator(cfg.Auth, NewProvider(cfg.Auth.OIDC), secure)
+}
+
+func newAuthenticator(cfg config.AuthConfig, provider IdentityProvider, secure bool) *Authenticator {
+	return &Authenticator{
+		cfg:      cfg,
+		provider: provider,
+		sessions: newSessionStore(),
+		secure:   secure,
+		now:      time.Now,
+	}
+}
+
+// SessionFromContext returns the session of an authenticated request
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionKey{}).(*Session)
+	return session, ok
+}
+
+// WithSession returns a context carrying the session, as the middleware does for logged-in requests
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// Role maps a user's groups to a role; "" means the user may not log in
+func (a *Authenticator) Role(groups []string) string {
+	if containsAny(a.cfg.AdminGroups, groups) {
+		return RoleAdmin
+	}
+	if containsAny(a.cfg.ReadOnlyGroups, groups) {
+		return RoleReadOnly
+	}
+	return ""
+}
+
+// containsAny reports whether any of the groups is in allowed
+func containsAny(allowed, groups []string) bool {
+	for _, group := range groups {
+		for _, candidate := range allowed {
+			if group == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPublic reports whether a path is served without login. Public paths ending in "/" match
+// everything below them.
+func (a *Authenticator) isPublic(path string) bool {
+	if strings.HasPrefix(path, "/auth/") || strings.HasPrefix(path, "/static/") {
+		return true
+	}
+	for _, public := range a.cfg.GetPublicPaths() {
+		if path == public || (strings.HasSuffix(public, "/") && strings.HasPrefix(path, public)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAPI reports whether a path is called by scripts rather than browsers
+func isAPI(path string) bool {
+	return path == "/api" || strings.HasPrefix(path, "/api/") || path == "/metrics"
+}
+
+// readOnlyMethod reports whether a request method cannot change state
+func readOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// currentSession returns the session of the request's cookie, if it is still valid
+func (a *Authenticator) currentSession(r *http.Request) (*Session, bool) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil, false
+	}
+	return a.sessions.get(cookie.Value, a.now())
+}
+
+// Middleware requires a session for non-public paths. Browsers are sent to the login page,
+// API clients get 401, and read-only users get 403 for requests that change state.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.isPublic(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		session, ok := a.currentSession(r)
+		if !ok {
+			if isAPI(r.URL.Path) {
+				w.Header().Set("Content-Type", "application/json")
+				problems.Write(w, http.StatusUnauthorized, problems.Unauthorized, "Authentication required")
+				return
+			}
+			http.Redirect(w, r, "/auth/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusFound)
+			return
+		}
+		if session.Role != RoleAdmin && !readOnlyMethod(r.Method) {
+			w.Header().Set("Content-Type", "application/json")
+			problems.Write(w, http.StatusForbidden, problems.Forbidden, "Admin role required")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithSession(r.Context(), session)))
+	})
+}
+
+// safeNext returns a local redirect target, so the login flow cannot be used as an open redirect
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+// setCookie sets a cookie scoped to the whole site
+func (a *Authenticator) setCookie(w http.ResponseWriter, name, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   a.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// LoginHandler sends the browser to the provider (/auth/login?next={path})
+func (a *Authenticator) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	state, nonce := a.sessions.begin(safeNext(r.URL.Query().Get("next")), a.now())
+	target, err := a.provider.AuthCodeURL(state, nonce)
+	if err != nil {
+		log.Printf("OIDC login failed: %v", err)
+		http.Error(w, "Login provider is unavailable", http.StatusBadGateway)
+		return
+	}
+	a.setCookie(w, stateCookie, state, int(loginTimeout.Seconds()))
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// CallbackHandler completes a login started by LoginHandler (/auth/callback)
+func (a *Authenticator) CallbackHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		log.Printf("OIDC provider returned an error: %s %s", providerErr, query.Get("error_description"))
+		http.Error(w, "Login was not completed", http.StatusUnauthorized)
+		return
+	}
+
+	// The state must match the cookie of the browser that started the login
+	state := query.Get("state")
+	cookie, err := r.Cookie(stateCookie)
+	if state == "" || err != nil || cookie.Value != state {
+		http.Error(w, "Invalid login state", http.StatusBadRequest)
+		return
+	}
+	a.setCookie(w, stateCookie, "", -1)
+	login, ok := a.sessions.complete(state, a.now())
+	if !ok {
+		http.Error(w, "Login expired, please try again", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := a.provider.Exchange(query.Get("code"), login.nonce)
+	if err != nil {
+		log.Printf("OIDC login failed: %v", err)
+		http.Error(w, "Login failed", http.StatusUnauthorized)
+		return
+	}
+	groups := claims.Groups(a.cfg.OIDC.GetGroupsClaim())
+	role := a.Role(groups)
+	if role == "" {
+		log.Printf("OIDC login refused for %s: not in an allowed group", claims.DisplayName())
+		http.Error(w, "You are not in a group allowed to use this service", http.StatusForbidden)
+		return
+	}
+
+	ttl := a.cfg.GetSessionTTL()
+	session := a.sessions.create(Session{
+		Subject: claims.Subject,
+		Name:    claims.DisplayName(),
+		Groups:  groups,
+		Role:    role,
+	}, ttl, a.now())
+	log.Printf("OIDC login: %s (%s)", session.Name, session.Role)
+	a.setCookie(w, sessionCookie, session.ID, int(ttl.Seconds()))
+	http.Redirect(w, r, login.next, http.StatusFound)
+}
+
+// LogoutHandler ends the session (/auth/logout)
+func (a *Authenticator) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		a.sessions.remove(cookie.Value)
+	}
+	a.setCookie(w, sessionCookie, "", -1)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// MeHandler returns the logged in user (/auth/me)
+func (a *Authenticator) MeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	session, ok := a.currentSession(r)
+	if !ok {
+		problems.Write(w, http.StatusUnauthorized, problems.Unauthorized, "Not logged in")
+		return
+	}
+	if err := json.NewEncoder(w).Encode(session); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
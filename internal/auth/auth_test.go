@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// testIssuer is an OIDC provider that signs ID tokens with a generated key
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]interface{} // Claims of the next issued ID token
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.server.URL,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if clientID, secret, ok := r.BasicAuth(); !ok || clientID != "monitor" || secret != "secret" || r.FormValue("code") != "good-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": issuer.sign(t, issuer.claims)})
+	})
+	issuer.server = httptest.NewServer(mux)
+	return issuer
+}
+
+func (i *testIssuer) sign(t *testing.T, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestProviderVerifiesIDTokens(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+	provider := NewProvider(config.OIDCConfig{IssuerURL: issuer.server.URL, ClientID: "monitor", ClientSecret: "secret"})
+
+	valid := map[string]interface{}{
+		"iss": issuer.server.URL, "sub": "u1", "aud": "monitor", "nonce": "n1",
+		"exp": time.Now().Add(time.Hour).Unix(), "groups": []string{"kernel-team"},
+	}
+	claims, err := provider.Verify(issuer.sign(t, valid), "n1")
+	if err != nil {
+		t.Fatalf("Verify() returned error: %v", err)
+	}
+	if groups := claims.Groups("groups"); len(groups) != 1 || groups[0] != "kernel-team" {
+		t.Errorf("Groups() = %v, expected [kernel-team]", groups)
+	}
+
+	for name, change := range map[string]func(map[string]interface{}){
+		"wrong nonce":    func(c map[string]interface{}) { c["nonce"] = "other" },
+		"wrong audience": func(c map[string]interface{}) { c["aud"] = []string{"someone-else"} },
+		"wrong issuer":   func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" },
+		"expired":        func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+	} {
+		claims := make(map[string]interface{})
+		for k, v := range valid {
+			claims[k] = v
+		}
+		change(claims)
+		if _, err := provider.Verify(issuer.sign(t, claims), "n1"); err == nil {
+			t.Errorf("Verify() with %s expected an error", name)
+		}
+	}
+
+	token := issuer.sign(t, valid)
+	tampered := token[:strings.LastIndex(token, ".")] + ".AAAA"
+	if _, err := provider.Verify(tampered, "n1"); err == nil {
+		t.Errorf("Verify() with a bad signature expected an error")
+	}
+}
+
+func TestLoginFlowAndRoles(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+	cfg := config.AuthConfig{
+		OIDC: config.OIDCConfig{
+			Enabled: true, IssuerURL: issuer.server.URL, ClientID: "monitor", ClientSecret: "secret",
+			RedirectURL: "https://monitor.example.com/auth/callback",
+		},
+		AdminGroups:    []string{"kernel-admins"},
+		ReadOnlyGroups: []string{"kernel-team"},
+	}
+	a := NewAuthenticator(&config.Config{Auth: cfg}, true)
+	protected := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if session, ok := SessionFromContext(r.Context()); ok {
+			w.Write([]byte(session.Name))
+		}
+	}))
+
+	// Unauthenticated browsers are sent to the login page, API clients get 401
+	w := httptest.NewRecorder()
+	protected.ServeHTTP(w, httptest.NewRequest("GET", "/package?name=570", nil))
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/auth/login?next=%2Fpackage%3Fname%3D570" {
+		t.Errorf("GET /package = %d %s, expected a redirect to the login", w.Code, w.Header().Get("Location"))
+	}
+	w = httptest.NewRecorder()
+	protected.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/packages", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("GET /api/v1/packages = %d, expected %d", w.Code, http.StatusUnauthorized)
+	}
+	w = httptest.NewRecorder()
+	protected.ServeHTTP(w, httptest.NewRequest("GET", "/api/health", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("GET /api/health = %d, expected public access", w.Code)
+	}
+
+	login := func(groups []string) *http.Cookie {
+		w := httptest.NewRecorder()
+		a.LoginHandler(w, httptest.NewRequest("GET", "/auth/login?next=//evil.example.com", nil))
+		location, _ := url.Parse(w.Header().Get("Location"))
+		state, nonce := location.Query().Get("state"), location.Query().Get("nonce")
+		issuer.claims = map[string]interface{}{
+			"iss": issuer.server.URL, "sub": "u1", "aud": "monitor", "nonce": nonce,
+			"exp": time.Now().Add(time.Hour).Unix(), "preferred_username": "jdoe", "groups": groups,
+		}
+
+		req := httptest.NewRequest("GET", "/auth/callback?code=good-code&state="+state, nil)
+		req.AddCookie(&http.Cookie{Name: stateCookie, Value: state})
+		w = httptest.NewRecorder()
+		a.CallbackHandler(w, req)
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == sessionCookie && cookie.Value != "" {
+				if !cookie.Secure || !cookie.HttpOnly {
+					t.Errorf("Session cookie should be Secure and HttpOnly")
+				}
+				if w.Header().Get("Location") != "/" {
+					t.Errorf("Login redirected to %s, expected /", w.Header().Get("Location"))
+				}
+				return cookie
+			}
+		}
+		return nil
+	}
+
+	if cookie := login([]string{"marketing"}); cookie != nil {
+		t.Errorf("Users outside the allowed groups should not get a session")
+	}
+
+	readOnly := login([]string{"kernel-team"})
+	if readOnly == nil {
+		t.Fatal("Expected a session for a read-only user")
+	}
+	for _, test := range []struct {
+		method   string
+		cookie   *http.Cookie
+		expected int
+	}{
+		{"GET", readOnly, http.StatusOK},
+		{"POST", readOnly, http.StatusForbidden},
+		{"POST", login([]string{"kernel-team", "kernel-admins"}), http.StatusOK},
+	} {
+		req := httptest.NewRequest(test.method, "/api/maintenance", nil)
+		req.AddCookie(test.cookie)
+		w := httptest.NewRecorder()
+		protected.ServeHTTP(w, req)
+		if w.Code != test.expected {
+			t.Errorf("%s /api/maintenance = %d, expected %d", test.method, w.Code, test.expected)
+		}
+	}
+
+	// A state can only be used once, and only by the browser that started the login
+	req := httptest.NewRequest("GET", "/auth/callback?code=good-code&state=forged", nil)
+	w = httptest.NewRecorder()
+	a.CallbackHandler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Callback without the state cookie = %d, expected %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestRoleAdmitsOnlyListedGroups(t *testing.T) {
+	a := newAuthenticator(config.AuthConfig{AdminGroups: []string{"kernel-admins"}}, nil, false)
+	for _, test := range []struct {
+		groups   []string
+		expected string
+	}{
+		{[]string{"kernel-admins"}, RoleAdmin},
+		{[]string{"marketing"}, ""},
+		{nil, ""},
+	} {
+		if role := a.Role(test.groups); role != test.expected {
+			t.Errorf("Role(%v) without read-only groups = %q, expected %q", test.groups, role, test.expected)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// jsonWebKey is an RSA signing key of a JWKS document
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// jsonWebKeySet is the document served at the provider's jwks_uri
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// rsaKeys returns the RSA signing keys of the set by key ID
+func (s *jsonWebKeySet) rsaKeys() map[string]*rsa.PublicKey {
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range s.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys
+}
+
+// jwtHeader is the header of a signed ID token
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Claims are the ID token claims used for sessions. Audience may be a string or a list.
+type Claims struct {
+	Issuer            string          `json:"iss"`
+	Subject           string          `json:"sub"`
+	Audience          json.RawMessage `json:"aud"`
+	Expiry            int64           `json:"exp"`
+	Nonce             string          `json:"nonce"`
+	Email             string          `json:"email"`
+	Name              string          `json:"name"`
+	PreferredUsername string          `json:"preferred_username"`
+	// Raw holds every claim, so the configured groups claim can be read
+	Raw map[string]interface{} `json:"-"`
+}
+
+// errUnknownKey is returned when a token is signed with a key missing from the key set
+var errUnknownKey = errors.New("ID token signed with an unknown key")
+
+// parseIDToken verifies the RS256 signature of a compact JWT and decodes its claims
+func parseIDToken(raw string, keys map[string]*rsa.PublicKey) (*Claims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed ID token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid ID token header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported ID token algorithm %q", header.Alg)
+	}
+	key, ok := keys[header.Kid]
+	if !ok {
+		return nil, errUnknownKey
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token signature: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("ID token signature does not verify")
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid ID token claims: %w", err)
+	}
+	if err := decodeSegment(parts[1], &claims.Raw); err != nil {
+		return nil, fmt.Errorf("invalid ID token claims: %w", err)
+	}
+	return &claims, nil
+}
+
+// decodeSegment decodes a base64url JSON segment of a JWT
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// validate checks the issuer, audience, expiry and nonce of the claims
+func (c *Claims) validate(issuer, clientID, nonce string, now time.Time) error {
+	if strings.TrimSuffix(c.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return fmt.Errorf("ID token issued by %q, expected %q", c.Issuer, issuer)
+	}
+	if !c.hasAudience(clientID) {
+		return errors.New("ID token was not issued for this client")
+	}
+	if now.Unix() >= c.Expiry {
+		return errors.New("ID token has expired")
+	}
+	if c.Nonce != nonce {
+		return errors.New("ID token nonce does not match the login request")
+	}
+	return nil
+}
+
+// hasAudience reports whether the audience claim contains the client ID
+func (c *Claims) hasAudience(clientID string) bool {
+	var single string
+	if err := json.Unmarshal(c.Audience, &single); err == nil {
+		return single == clientID
+	}
+	var list []string
+	if err := json.Unmarshal(c.Audience, &list); err == nil {
+		for _, aud := range list {
+			if aud == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Groups returns the string values of the named claim; a single string counts as one group
+func (c *Claims) Groups(claim string) []string {
+	switch value := c.Raw[claim].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var groups []string
+		for _, item := range value {
+			if group, ok := item.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	}
+	return nil
+}
+
+// DisplayName returns the most readable identifier of the user
+func (c *Claims) DisplayName() string {
+	for _, name := range []string{c.PreferredUsername, c.Email, c.Name} {
+		if name != "" {
+			return name
+		}
+	}
+	return c.Subject
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// discoveryDocument is the subset of the provider's openid-configuration used by the login flow
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// tokenResponse is the token endpoint reply to an authorization code exchange
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Provider runs the OpenID Connect authorization code flow against one issuer. The discovery
+// document and signing keys are fetched on first use, and keys again when they rotate.
+type Provider struct {
+	cfg config.OIDCConfig
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]*rsa.PublicKey
+}
+
+// NewProvider returns a provider for the configured issuer
+func NewProvider(cfg config.OIDCConfig) *Provider {
+	return &Provider{cfg: cfg}
+}
+
+// getJSON fetches a JSON document from the provider
+func getJSON(url string, v interface{}) error {
+	resp, err := utils.HTTPGetWithRetry(url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code fetching %s: %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	return nil
+}
+
+// discover returns the provider metadata, fetching it on first use
+func (p *Provider) discover() (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var doc discoveryDocument
+	wellKnown := strings.TrimSuffix(p.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(wellKnown, &doc); err != nil {
+		return nil, err
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery document is missing endpoints")
+	}
+	p.discovery = &doc
+	return p.discovery, nil
+}
+
+// signingKeys returns the provider's RSA keys, refetching them when refresh is set
+func (p *Provider) signingKeys(refresh bool) (map[string]*rsa.PublicKey, error) {
+	doc, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keys != nil && !refresh {
+		return p.keys, nil
+	}
+	var set jsonWebKeySet
+	if err := getJSON(doc.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	p.keys = set.rsaKeys()
+	return p.keys, nil
+}
+
+// AuthCodeURL returns the provider login URL for a new login attempt
+func (p *Provider) AuthCodeURL(state, nonce string) (string, error) {
+	doc, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.cfg.ClientID},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"scope":         {strings.Join(p.cfg.GetScopes(), " ")},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+	separator := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return doc.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems an authorization code and returns the verified ID token claims
+func (p *Provider) Exchange(code, nonce string) (*Claims, error) {
+	doc, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {p.cfg.RedirectURL},
+	}
+	req, err := http.NewRequest(http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.GetClientSecret()))
+
+	client := utils.NewHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return nil, fmt.Errorf("token endpoint returned HTTP %d: %s %s", resp.StatusCode, token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no ID token")
+	}
+	return p.Verify(token.IDToken, nonce)
+}
+
+// Verify checks the signature and claims of an ID token
+func (p *Provider) Verify(rawIDToken, nonce string) (*Claims, error) {
+	keys, err := p.signingKeys(false)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := parseIDToken(rawIDToken, keys)
+	if errors.Is(err, errUnknownKey) {
+		// The provider may have rotated its keys since they were fetched
+		if keys, err = p.signingKeys(true); err != nil {
+			return nil, err
+		}
+		claims, err = parseIDToken(rawIDToken, keys)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := p.cfg.IssuerURL
+	if doc, err := p.discover(); err == nil && doc.Issuer != "" {
+		issuer = doc.Issuer
+	}
+	if err := claims.validate(issuer, p.cfg.ClientID, nonce, time.Now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Roles granted from the user's groups
+const (
+	RoleAdmin    = "admin"
+	RoleReadOnly = "read-only"
+)
+
+// loginTimeout bounds how long a user may take at the provider's login page
+const loginTimeout = 10 * time.Minute
+
+// Session is a logged in user
+type Session struct {
+	ID        string    `json:"-"`
+	Subject   string    `json:"subject"`
+	Name      string    `json:"name"`
+	Groups    []string  `json:"groups,omitempty"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// pendingLogin is a login started at the provider and not yet completed
+type pendingLogin struct {
+	nonce     string
+	next      string
+	expiresAt time.Time
+}
+
+// sessionStore keeps sessions and pending logins in memory; a restart logs everyone out
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	pending  map[string]*pendingLogin
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*Session),
+		pending:  make(map[string]*pendingLogin),
+	}
+}
+
+// randomID returns a random hex identifier for session cookies, states and nonces
+func randomID() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic("crypto/rand failed: " + err.Error())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// create stores a new session and returns it
+func (s *sessionStore) create(session Session, ttl time.Duration, now time.Time) *Session {
+	session.ID = randomID()
+	session.ExpiresAt = now.Add(ttl)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked(now)
+	s.sessions[session.ID] = &session
+	copied := session
+	return &copied
+}
+
+// get returns an unexpired session
+func (s *sessionStore) get(id string, now time.Time) (*Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok || !now.Before(session.ExpiresAt) {
+		return nil, false
+	}
+	copied := *session
+	return &copied, true
+}
+
+// remove deletes a session
+func (s *sessionStore) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
+
+// begin records a login sent to the provider and returns its state and nonce
+func (s *sessionStore) begin(next string, now time.Time) (string, string) {
+	state, nonce := randomID(), randomID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked(now)
+	s.pending[state] = &pendingLogin{nonce: nonce, next: next, expiresAt: now.Add(loginTimeout)}
+	return state, nonce
+}
+
+// complete returns and forgets the pending login of a state; each state is usable once
+func (s *sessionStore) complete(state string, now time.Time) (*pendingLogin, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, ok := s.pending[state]
+	delete(s.pending, state)
+	if !ok || !now.Before(login.expiresAt) {
+		return nil, false
+	}
+	return login, true
+}
+
+// expireLocked drops expired sessions and logins; callers hold mu
+func (s *sessionStore) expireLocked(now time.Time) {
+	for id, session := range s.sessions {
+		if !now.Before(session.ExpiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	for state, login := range s.pending {
+		if !now.Before(login.expiresAt) {
+			delete(s.pending, state)
+		}
+	}
+}
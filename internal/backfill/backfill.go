@@ -0,0 +1,298 @@
+package backfill
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/utils"
+
+	version "github.com/knqyf263/go-deb-version"
+)
+
+// MaxPageSize is the largest page Launchpad serves for a collection
+const MaxPageSize = 300
+
+// Release is an upstream driver release of a branch
+type Release struct {
+	Version string
+	Date    time.Time
+}
+
+// Options controls a backfill run
+type Options struct {
+	Since    time.Time     // First day to reconstruct; zero starts at the first publication
+	Until    time.Time     // Last day to reconstruct; zero stops the day before today (UTC)
+	Interval time.Duration // Wait between Launchpad requests
+	PageSize int           // Publications per Launchpad request, at most MaxPageSize
+	DryRun   bool          // Reconstruct without writing to the store
+	// Get performs the Launchpad requests; nil uses utils.HTTPGetWithRetry
+	Get func(string) (*http.Response, error)
+}
+
+// Summary reports what a backfill run did
+type Summary struct {
+	Packages     int `json:"packages"`
+	Requests     int `json:"requests"`
+	Publications int `json:"publications"`
+	Observations int `json:"observations"` // Reconstructed package, series and day slots
+	Added        int `json:"added"`        // Slots that were empty in the store
+}
+
+// UpstreamTimelines maps each tracked package to its upstream releases: nvidia.com UDA
+// releases for desktop branches and the datacenter releases for -server branches. Tegra
+// branches have no timeline, so their history records no upstream version.
+func UpstreamTimelines(supported []releases.SupportedRelease, uda []drivers.DriverEntry, erd drivers.AllBranches) map[string][]Release {
+	timelines := make(map[string][]Release)
+	for _, rel := range supported {
+		if rel.IsTegra() {
+			continue
+		}
+		var timeline []Release
+		if branch, ok := strings.CutSuffix(rel.BranchName, "-server"); ok {
+			for _, info := range erd[branch].DriverInfo {
+				if date, err := time.Parse(history.DateFormat, info.ReleaseDate); err == nil {
+					timeline = append(timeline, Release{Version: info.ReleaseVersion, Date: date})
+				}
+			}
+		} else {
+			for _, entry := range uda {
+				if !entry.IsBeta && strings.SplitN(entry.Version, ".", 2)[0] == rel.BranchName {
+					timeline = append(timeline, Release{Version: entry.Version, Date: entry.Date})
+				}
+			}
+		}
+		sort.Slice(timeline, func(i, j int) bool { return timeline[i].Date.Before(timeline[j].Date) })
+		timelines[rel.PackageName()] = timeline
+	}
+	return timelines
+}
+
+// FetchPublications pages through the complete Launchpad publication history of a source
+// package, without the created_since_date cutoff of the dashboard, waiting interval between
+// requests. It returns the publications and the number of requests made.
+func FetchPublications(get func(string) (*http.Response, error), sourcesAPI, packageName string, pageSize int, interval time.Duration) ([]packages.SourcePubHistory, int, error) {
+	if pageSize <= 0 || pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	next := fmt.Sprintf("%s/?ws.op=getPublishedSources&source_name=%s&order_by_date=true&exact_match=true&ws.size=%d",
+		sourcesAPI, packageName, pageSize)
+
+	var publications []packages.SourcePubHistory
+	requests := 0
+	for next != "" {
+		if requests > 0 && interval > 0 {
+			time.Sleep(interval)
+		}
+		requests++
+
+		page, err := fetchPage(get, next)
+		if err != nil {
+			return nil, requests, fmt.Errorf("failed to fetch publication history of %s: %w", packageName, err)
+		}
+		publications = append(publications, page.Entries...)
+		next = page.NextCollectionLink
+	}
+	return publications, requests, nil
+}
+
+// fetchPage requests one page of a Launchpad collection
+func fetchPage(get func(string) (*http.Response, error), url string) (*packages.SourceAPIResponse, error) {
+	resp, err := get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	var page packages.SourceAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return &page, nil
+}
+
+// publication is a publication with parsed dates and version
+type publication struct {
+	series    string
+	pocket    string
+	version   version.Version
+	component string
+	from      time.Time
+	until     time.Time // Zero while still published
+}
+
+// activeAt reports whether the publication was in the archive at the given time
+func (p *publication) activeAt(at time.Time) bool {
+	return !p.from.After(at) && (p.until.IsZero() || p.until.After(at))
+}
+
+// parsePublications drops publications that never reached the archive
+func parsePublications(entries []packages.SourcePubHistory) []publication {
+	var parsed []publication
+	for _, entry := range entries {
+		from, err := time.Parse(time.RFC3339Nano, entry.DatePublished)
+		if err != nil {
+			continue
+		}
+		series := packages.SeriesFromDistroSeriesLink(entry.DistroSeriesLink)
+		ver, err := version.NewVersion(entry.SourcePackageVersion)
+		if series == "" || err != nil {
+			continue
+		}
+		pub := publication{series: series, pocket: entry.Pocket, version: ver, component: entry.ComponentName, from: from}
+		for _, end := range []string{entry.DateSuperseded, entry.DateRemoved} {
+			if until, err := time.Parse(time.RFC3339Nano, end); err == nil && (pub.until.IsZero() || until.Before(pub.until)) {
+				pub.until = until
+			}
+		}
+		parsed = append(parsed, pub)
+	}
+	return parsed
+}
+
+// upstreamAt returns the newest release of a timeline published on or before the given time
+func upstreamAt(timeline []Release, at time.Time) (Release, bool) {
+	var latest Release
+	found := false
+	for _, release := range timeline {
+		if release.Date.After(at) {
+			break
+		}
+		latest, found = release, true
+	}
+	return latest, found
+}
+
+// Reconstruct rebuilds the daily observations of a package from its publication history and
+// upstream timeline. Each day records the archive state at its end (UTC), and is outdated, as
+// on the dashboard, when the published version does not contain the upstream version.
+func Reconstruct(packageName string, entries []packages.SourcePubHistory, timeline []Release, publishedPockets []string, since, until time.Time) []history.Observation {
+	publications := parsePublications(entries)
+	if len(publications) == 0 {
+		return nil
+	}
+
+	bySeries := make(map[string][]publication)
+	first := publications[0].from
+	for _, pub := range publications {
+		bySeries[pub.series] = append(bySeries[pub.series], pub)
+		if pub.from.Before(first) {
+			first = pub.from
+		}
+	}
+	if since.IsZero() || since.Before(first) {
+		since = first
+	}
+	seriesNames := make([]string, 0, len(bySeries))
+	for series := range bySeries {
+		seriesNames = append(seriesNames, series)
+	}
+	sort.Strings(seriesNames)
+
+	isPublished := make(map[string]bool)
+	for _, pocket := range publishedPockets {
+		isPublished[pocket] = true
+	}
+
+	var observations []history.Observation
+	start := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.UTC)
+	for day := start; !day.After(until); day = day.AddDate(0, 0, 1) {
+		endOfDay := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		upstream, hasUpstream := upstreamAt(timeline, endOfDay)
+
+		for _, series := range seriesNames {
+			var published, proposed *publication
+			for i := range bySeries[series] {
+				pub := &bySeries[series][i]
+				if !pub.activeAt(endOfDay) {
+					continue
+				}
+				if isPublished[pub.pocket] && (published == nil || pub.version.GreaterThan(published.version)) {
+					published = pub
+				}
+				if pub.pocket == "Proposed" && (proposed == nil || pub.version.GreaterThan(proposed.version)) {
+					proposed = pub
+				}
+			}
+			if published == nil && proposed == nil {
+				continue
+			}
+
+			obs := history.Observation{
+				Date:    day.Format(history.DateFormat),
+				Package: packageName,
+				Series:  series,
+			}
+			if published != nil {
+				obs.Published = published.version.String()
+				obs.Component = published.component
+			}
+			if proposed != nil {
+				obs.Proposed = proposed.version.String()
+			}
+			if hasUpstream {
+				obs.Upstream = upstream.Version
+				obs.UpstreamDate = upstream.Date.Format(history.DateFormat)
+				obs.Outdated = obs.Published != "" && !strings.Contains(obs.Published, upstream.Version)
+			}
+			observations = append(observations, obs)
+		}
+	}
+	return observations
+}
+
+// Run backfills the history store with the reconstructed history of every tracked package,
+// one package at a time. The store is written after each package, so an interrupted run
+// keeps the packages already done and a rerun only fills what is still missing.
+func Run(cfg *config.Config, supported []releases.SupportedRelease, timelines map[string][]Release, store *history.Store, opts Options) (Summary, error) {
+	get := opts.Get
+	if get == nil {
+		get = utils.HTTPGetWithRetry
+	}
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now().UTC().AddDate(0, 0, -1)
+	}
+	until = time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, time.UTC)
+	publishedPockets := cfg.Pockets.GetPublished()
+
+	var summary Summary
+	for i, rel := range supported {
+		packageName := rel.PackageName()
+		if i > 0 && opts.Interval > 0 {
+			time.Sleep(opts.Interval)
+		}
+
+		entries, requests, err := FetchPublications(get, cfg.URLs.Launchpad.PublishedSourcesAPI, packageName, opts.PageSize, opts.Interval)
+		summary.Requests += requests
+		if err != nil {
+			return summary, err
+		}
+		observations := Reconstruct(packageName, entries, timelines[packageName], publishedPockets, opts.Since, until)
+		summary.Packages++
+		summary.Publications += len(entries)
+		summary.Observations += len(observations)
+
+		added := 0
+		if !opts.DryRun {
+			if added, err = store.Backfill(observations); err != nil {
+				return summary, fmt.Errorf("failed to store history of %s: %w", packageName, err)
+			}
+		}
+		summary.Added += added
+		log.Printf("Backfilled %s: %d publications, %d daily observations, %d new", packageName, len(entries), len(observations), added)
+	}
+	return summary, nil
+}
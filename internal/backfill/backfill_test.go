@@ -0,0 +1,148 @@
+package backfill
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/releases"
+)
+
+const packageName = "nvidia-graphics-drivers-570"
+
+// publications is a small history: 570.153 published to noble-updates on 2026-01-05 and
+// superseded by 570.172 on 2026-01-08, which sat in -proposed from 2026-01-06
+var publications = []packages.SourcePubHistory{
+	{SourcePackageVersion: "570.172.08-0ubuntu1", DistroSeriesLink: "https://api.launchpad.net/devel/ubuntu/noble", Pocket: "Updates", ComponentName: "restricted", Status: "Published", DatePublished: "2026-01-08T10:00:00+00:00"},
+	{SourcePackageVersion: "570.172.08-0ubuntu1", DistroSeriesLink: "https://api.launchpad.net/devel/ubuntu/noble", Pocket: "Proposed", ComponentName: "restricted", Status: "Deleted", DatePublished: "2026-01-06T10:00:00+00:00", DateRemoved: "2026-01-09T10:00:00+00:00"},
+	{SourcePackageVersion: "570.153.02-0ubuntu1", DistroSeriesLink: "https://api.launchpad.net/devel/ubuntu/noble", Pocket: "Updates", ComponentName: "restricted", Status: "Superseded", DatePublished: "2026-01-05T10:00:00+00:00", DateSuperseded: "2026-01-08T10:00:00+00:00"},
+	{SourcePackageVersion: "570.999-0ubuntu1", DistroSeriesLink: "https://api.launchpad.net/devel/ubuntu/noble", Pocket: "Proposed", Status: "Pending"},
+}
+
+func TestFetchPublicationsFollowsPages(t *testing.T) {
+	pages := map[string]packages.SourceAPIResponse{
+		"https://lp/primary/?ws.op=getPublishedSources&source_name=" + packageName + "&order_by_date=true&exact_match=true&ws.size=2": {
+			Entries: publications[:2], NextCollectionLink: "https://lp/primary/?page=2",
+		},
+		"https://lp/primary/?page=2": {Entries: publications[2:]},
+	}
+	var requested []string
+	get := func(url string) (*http.Response, error) {
+		requested = append(requested, url)
+		page, ok := pages[url]
+		if !ok {
+			return nil, errors.New("unexpected URL " + url)
+		}
+		body, _ := json.Marshal(page)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(body)))}, nil
+	}
+
+	entries, requests, err := FetchPublications(get, "https://lp/primary", packageName, 2, 0)
+	if err != nil {
+		t.Fatalf("FetchPublications() error = %v", err)
+	}
+	if requests != 2 || len(entries) != len(publications) || len(requested) != 2 {
+		t.Errorf("FetchPublications() = %d entries in %d requests, expected %d in 2", len(entries), requests, len(publications))
+	}
+	if strings.Contains(requested[0], "created_since_date") {
+		t.Errorf("Backfill query %s should not have the created_since_date cutoff", requested[0])
+	}
+
+	failing := func(string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	if _, _, err := FetchPublications(failing, "https://lp/primary", packageName, 2, 0); err == nil {
+		t.Error("FetchPublications() should fail when Launchpad does")
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+	timeline := []Release{
+		{Version: "570.153.02", Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Version: "570.172.08", Date: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC)},
+	}
+	until := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	observations := Reconstruct(packageName, publications, timeline, []string{"Updates", "Security"}, time.Time{}, until)
+
+	tests := []struct {
+		date, published, proposed, upstream string
+		outdated                            bool
+	}{
+		{"2026-01-05", "570.153.02-0ubuntu1", "", "570.153.02", false},
+		{"2026-01-06", "570.153.02-0ubuntu1", "570.172.08-0ubuntu1", "570.172.08", true},
+		{"2026-01-07", "570.153.02-0ubuntu1", "570.172.08-0ubuntu1", "570.172.08", true},
+		{"2026-01-08", "570.172.08-0ubuntu1", "570.172.08-0ubuntu1", "570.172.08", false},
+		{"2026-01-09", "570.172.08-0ubuntu1", "", "570.172.08", false},
+	}
+	if len(observations) != len(tests) {
+		t.Fatalf("Reconstruct() returned %d observations, expected %d: %+v", len(observations), len(tests), observations)
+	}
+	for i, tt := range tests {
+		obs := observations[i]
+		if obs.Date != tt.date || obs.Series != "noble" || obs.Published != tt.published || obs.Proposed != tt.proposed || obs.Upstream != tt.upstream || obs.Outdated != tt.outdated {
+			t.Errorf("observation %d = %+v, expected %+v", i, obs, tt)
+		}
+		if obs.Component != "restricted" {
+			t.Errorf("observation %d component = %q, expected restricted", i, obs.Component)
+		}
+	}
+
+	since := time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC)
+	if got := Reconstruct(packageName, publications, nil, []string{"Updates"}, since, until); len(got) != 2 || got[0].Upstream != "" || got[0].Outdated {
+		t.Errorf("Reconstruct() since 2026-01-08 without a timeline = %+v, expected 2 days without upstream", got)
+	}
+}
+
+func TestUpstreamTimelines(t *testing.T) {
+	supported := []releases.SupportedRelease{{BranchName: "570"}, {BranchName: "570-server"}, {BranchName: "36", Platform: releases.PlatformTegra}}
+	uda := []drivers.DriverEntry{
+		{Version: "570.172.08", Date: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{Version: "570.153.02", Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Version: "575.51.02", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), IsBeta: true},
+		{Version: "580.65.06", Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	erd := drivers.AllBranches{"570": {DriverInfo: []drivers.DriverInfo{{ReleaseVersion: "570.158.01", ReleaseDate: "2026-01-04"}}}}
+
+	timelines := UpstreamTimelines(supported, uda, erd)
+	if desktop := timelines[packageName]; len(desktop) != 2 || desktop[0].Version != "570.153.02" {
+		t.Errorf("570 timeline = %+v, expected the two UDA releases oldest first", desktop)
+	}
+	if server := timelines[packageName+"-server"]; len(server) != 1 || server[0].Version != "570.158.01" {
+		t.Errorf("570-server timeline = %+v, expected the datacenter release", server)
+	}
+	if len(timelines) != 2 {
+		t.Errorf("UpstreamTimelines() = %v, expected no Tegra timeline", timelines)
+	}
+}
+
+func TestRunFillsStore(t *testing.T) {
+	body, _ := json.Marshal(packages.SourceAPIResponse{Entries: publications})
+	get := func(string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(body)))}, nil
+	}
+	store := history.NewStore(filepath.Join(t.TempDir(), "history.json"))
+	opts := Options{Until: time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC), Get: get}
+	supported := []releases.SupportedRelease{{BranchName: "570"}}
+
+	summary, err := Run(config.DefaultConfig(), supported, nil, store, opts)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if summary.Packages != 1 || summary.Requests != 1 || summary.Observations != 5 || summary.Added != 5 || store.Len() != 5 {
+		t.Errorf("Run() = %+v with %d stored, expected 5 observations added", summary, store.Len())
+	}
+
+	summary, err = Run(config.DefaultConfig(), supported, nil, store, opts)
+	if err != nil || summary.Added != 0 {
+		t.Errorf("second Run() = %+v, %v, expected nothing new", summary, err)
+	}
+}
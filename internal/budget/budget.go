@@ -0,0 +1,251 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/atomicfile"
+	"nvidia_driver_monitor/internal/config"
+)
+
+// DayFormat is the layout of the UTC days requests are counted under
+const DayFormat = "2006-01-02"
+
+// retentionDays is how many days of counts are kept in the data file
+const retentionDays = 30
+
+// Usage is the request count of one upstream host on one day
+type Usage struct {
+	Host     string  `json:"host"` // Budget key, e.g. "launchpad.net", or the host itself when it has no budget
+	Day      string  `json:"day"`
+	Requests int64   `json:"requests"`
+	Limit    int64   `json:"limit,omitempty"` // Daily budget; 0 when the host has none
+	Used     float64 `json:"used,omitempty"`  // Fraction of the budget used
+}
+
+// Tracker counts upstream requests per host and UTC day and compares them with the budgets
+type Tracker struct {
+	mu          sync.Mutex
+	counts      map[string]map[string]int64 // Day -> host -> requests
+	limits      map[string]int64            // Budget key -> daily requests
+	warnAt      []int                       // Ascending percentages of a budget
+	persistFile string
+	dirty       bool
+}
+
+var (
+	trackerMu sync.RWMutex
+	tracker   *Tracker
+)
+
+// SetBudgetConfig creates the global tracker from the configuration, loading the persisted counts
+func SetBudgetConfig(cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+	t := NewTracker(cfg.Budget.GetDataFile(), cfg.Budget.Daily, cfg.Budget.GetWarnAt())
+	trackerMu.Lock()
+	tracker = t
+	trackerMu.Unlock()
+}
+
+// Current returns the global tracker, or nil before SetBudgetConfig
+func Current() *Tracker {
+	trackerMu.RLock()
+	defer trackerMu.RUnlock()
+	return tracker
+}
+
+// Record counts a request to rawURL with the global tracker
+func Record(rawURL string) {
+	if t := Current(); t != nil {
+		t.Record(rawURL, time.Now())
+	}
+}
+
+// NewTracker creates a tracker, loading previously persisted counts if available
+func NewTracker(persistFile string, limits map[string]int64, warnAt []int) *Tracker {
+	t := &Tracker{
+		counts:      make(map[string]map[string]int64),
+		limits:      make(map[string]int64, len(limits)),
+		warnAt:      append([]int(nil), warnAt...),
+		persistFile: persistFile,
+	}
+	for host, limit := range limits {
+		t.limits[strings.ToLower(host)] = limit
+	}
+	sort.Ints(t.warnAt)
+	if err := t.loadFromFile(); err != nil {
+		log.Printf("Warning: Could not load existing budget data: %v", err)
+	}
+	return t
+}
+
+// hostKey returns the budget a URL counts against: the configured host it is, or is a
+// subdomain of, otherwise its own host
+func (t *Tracker) hostKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return "unknown"
+	}
+	host := strings.ToLower(u.Hostname())
+	for key := range t.limits {
+		if host == key || strings.HasSuffix(host, "."+key) {
+			return key
+		}
+	}
+	return host
+}
+
+// Record counts a request to rawURL made at now
+func (t *Tracker) Record(rawURL string, now time.Time) {
+	host := t.hostKey(rawURL)
+	day := now.UTC().Format(DayFormat)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.counts[day] == nil {
+		t.counts[day] = make(map[string]int64)
+	}
+	t.counts[day][host]++
+	t.dirty = true
+}
+
+// Usage returns the requests of the day of now per host, ordered by host. Hosts with a
+// budget are listed even before their first request.
+func (t *Tracker) Usage(now time.Time) []Usage {
+	day := now.UTC().Format(DayFormat)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hosts := make(map[string]bool, len(t.limits))
+	for host := range t.limits {
+		hosts[host] = true
+	}
+	for host := range t.counts[day] {
+		hosts[host] = true
+	}
+
+	usage := make([]Usage, 0, len(hosts))
+	for host := range hosts {
+		u := Usage{Host: host, Day: day, Requests: t.counts[day][host], Limit: t.limits[host]}
+		if u.Limit > 0 {
+			u.Used = float64(u.Requests) / float64(u.Limit)
+		}
+		usage = append(usage, u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Host < usage[j].Host })
+	return usage
+}
+
+// Threshold returns the highest warning percentage the usage has reached, or 0
+func (t *Tracker) Threshold(u Usage) int {
+	reached := 0
+	for _, percent := range t.warnAt {
+		if u.Used*100 >= float64(percent) {
+			reached = percent
+		}
+	}
+	return reached
+}
+
+// Slowdown returns how many times longer than usual automatic refreshes should wait: 1 below
+// the first warning threshold of every budget, doubling at each threshold reached
+func (t *Tracker) Slowdown(now time.Time) int {
+	factor := 1
+	for _, u := range t.Usage(now) {
+		hostFactor := 1
+		for _, percent := range t.warnAt {
+			if u.Used*100 >= float64(percent) {
+				hostFactor *= 2
+			}
+		}
+		if hostFactor > factor {
+			factor = hostFactor
+		}
+	}
+	return factor
+}
+
+// Exhausted reports whether any budget is used up for the day of now
+func (t *Tracker) Exhausted(now time.Time) bool {
+	for _, u := range t.Usage(now) {
+		if u.Limit > 0 && u.Requests >= u.Limit {
+			return true
+		}
+	}
+	return false
+}
+
+// NextDay returns when the budgets of the day of now are reset
+func NextDay(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+}
+
+// Save persists the counts if they changed since the last save, dropping old days
+func (t *Tracker) Save(now time.Time) error {
+	if t.persistFile == "" {
+		return nil
+	}
+
+	oldest := now.UTC().AddDate(0, 0, -retentionDays).Format(DayFormat)
+	t.mu.Lock()
+	if !t.dirty {
+		t.mu.Unlock()
+		return nil
+	}
+	for day := range t.counts {
+		if day < oldest {
+			delete(t.counts, day)
+		}
+	}
+	counts := make(map[string]map[string]int64, len(t.counts))
+	for day, byService := range t.counts {
+		counts[day] = make(map[string]int64, len(byService))
+		for service, count := range byService {
+			counts[day][service] = count
+		}
+	}
+	t.dirty = false
+	t.mu.Unlock()
+	return atomicfile.WriteJSON(t.persistFile, counts)
+}
+
+// loadFromFile restores the counts from the persistence file
+func (t *Tracker) loadFromFile() error {
+	if t.persistFile == "" {
+		return nil
+	}
+
+	jsonData, err := os.ReadFile(t.persistFile)
+	if os.IsNotExist(err) {
+		return nil // No existing data, start fresh
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read budget file: %w", err)
+	}
+
+	var counts map[string]map[string]int64
+	if err := json.Unmarshal(jsonData, &counts); err != nil {
+		return fmt.Errorf("failed to parse budget JSON: %w", err)
+	}
+	if counts == nil {
+		counts = make(map[string]map[string]int64)
+	}
+
+	t.mu.Lock()
+	t.counts = counts
+	t.mu.Unlock()
+
+	log.Printf("Loaded %d days of upstream request counts from %s", len(counts), t.persistFile)
+	return nil
+}
@@ -0,0 +1,68 @@
+package budget
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrackerBudgets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget_data.json")
+	now := time.Date(2026, 10, 17, 23, 30, 0, 0, time.UTC)
+	tracker := NewTracker(path, map[string]int64{"launchpad.net": 10, "Kernel.Ubuntu.com": 100}, []int{95, 80})
+
+	for i := 0; i < 8; i++ {
+		tracker.Record("https://api.launchpad.net/devel/ubuntu/+archive/primary?ws.op=getPublishedSources", now)
+	}
+	tracker.Record("https://kernel.ubuntu.com/forgejo/kernel/kernel-versions/raw/branch/main/info/sru-cycle.yaml", now)
+	tracker.Record("https://download.nvidia.com/XFree86/Linux-x86_64/", now)
+	tracker.Record("https://launchpad.net/ubuntu", now.Add(-24*time.Hour))
+
+	usage := tracker.Usage(now)
+	expected := []Usage{
+		{Host: "download.nvidia.com", Requests: 1},
+		{Host: "kernel.ubuntu.com", Requests: 1, Limit: 100},
+		{Host: "launchpad.net", Requests: 8, Limit: 10},
+	}
+	if len(usage) != len(expected) {
+		t.Fatalf("Usage() = %+v, expected %d hosts", usage, len(expected))
+	}
+	for i, want := range expected {
+		if usage[i].Host != want.Host || usage[i].Requests != want.Requests || usage[i].Limit != want.Limit {
+			t.Errorf("Usage()[%d] = %+v, expected %+v", i, usage[i], want)
+		}
+	}
+
+	if threshold := tracker.Threshold(usage[2]); threshold != 80 {
+		t.Errorf("Threshold(launchpad.net) = %d, expected 80", threshold)
+	}
+	if slowdown := tracker.Slowdown(now); slowdown != 2 {
+		t.Errorf("Slowdown() = %d, expected 2 past the first threshold", slowdown)
+	}
+	if tracker.Exhausted(now) {
+		t.Errorf("Exhausted() = true, expected false with 8 of 10 requests used")
+	}
+
+	tracker.Record("https://api.launchpad.net/devel/ubuntu", now)
+	tracker.Record("https://api.launchpad.net/devel/ubuntu", now)
+	if slowdown := tracker.Slowdown(now); slowdown != 4 {
+		t.Errorf("Slowdown() = %d, expected 4 past both thresholds", slowdown)
+	}
+	if !tracker.Exhausted(now) {
+		t.Errorf("Exhausted() = false, expected true with 10 of 10 requests used")
+	}
+	if reset := NextDay(now); !reset.Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("NextDay() = %v, expected midnight UTC", reset)
+	}
+	if tomorrow := now.Add(time.Hour); tracker.Exhausted(tomorrow) || tracker.Slowdown(tomorrow) != 1 {
+		t.Errorf("Budgets should reset on the next UTC day")
+	}
+
+	if err := tracker.Save(now); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	reloaded := NewTracker(path, map[string]int64{"launchpad.net": 10}, nil)
+	if usage := reloaded.Usage(now); len(usage) != 3 || usage[2].Requests != 10 {
+		t.Errorf("Reloaded Usage() = %+v, expected the persisted counts", usage)
+	}
+}
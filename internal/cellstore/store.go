@@ -0,0 +1,146 @@
+// Package cellstore keeps records attached to the package/series cells of the dashboard, such
+// as notes and acknowledgements, and persists them to a JSON file.
+package cellstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+
+	"nvidia_driver_monitor/internal/atomicfile"
+)
+
+// Record is attached to one cell
+type Record interface {
+	Cell() (packageName, series string)
+}
+
+// Store keeps at most one record per cell and persists them to disk
+type Store[T Record] struct {
+	mu          sync.RWMutex
+	records     map[string]T // Keyed by package/series
+	persistFile string
+	kind        string // What the records are, e.g. "notes", for messages
+}
+
+// New creates a store, loading previously persisted records if available
+func New[T Record](persistFile, kind string) *Store[T] {
+	s := &Store[T]{
+		records:     make(map[string]T),
+		persistFile: persistFile,
+		kind:        kind,
+	}
+	if err := s.loadFromFile(); err != nil {
+		log.Printf("Warning: Could not load existing %s: %v", kind, err)
+	}
+	return s
+}
+
+// key identifies a cell
+func key(packageName, series string) string {
+	return packageName + "/" + series
+}
+
+// recordKey identifies the cell of a record
+func recordKey[T Record](record T) string {
+	return key(record.Cell())
+}
+
+// Set stores the records, replacing those of the same cells, and persists them once
+func (s *Store[T]) Set(records ...T) {
+	s.mu.Lock()
+	for _, record := range records {
+		s.records[recordKey(record)] = record
+	}
+	s.mu.Unlock()
+	s.persist()
+}
+
+// Remove deletes the record of a cell, reporting whether it existed
+func (s *Store[T]) Remove(packageName, series string) bool {
+	s.mu.Lock()
+	_, ok := s.records[key(packageName, series)]
+	delete(s.records, key(packageName, series))
+	s.mu.Unlock()
+	if ok {
+		s.persist()
+	}
+	return ok
+}
+
+// Get returns the record of a cell
+func (s *Store[T]) Get(packageName, series string) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.records[key(packageName, series)]
+	return record, ok
+}
+
+// List returns the records keep accepts, or all of them when keep is nil, ordered by package
+// and series
+func (s *Store[T]) List(keep func(T) bool) []T {
+	s.mu.RLock()
+	list := make([]T, 0, len(s.records))
+	for _, record := range s.records {
+		if keep == nil || keep(record) {
+			list = append(list, record)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		pi, si := list[i].Cell()
+		pj, sj := list[j].Cell()
+		if pi != pj {
+			return pi < pj
+		}
+		return si < sj
+	})
+	return list
+}
+
+// persist saves the records, logging failures
+func (s *Store[T]) persist() {
+	if s.persistFile == "" {
+		return
+	}
+	if err := atomicfile.WriteJSON(s.persistFile, s.List(nil)); err != nil {
+		log.Printf("Warning: Failed to persist %s: %v", s.kind, err)
+	}
+}
+
+// loadFromFile restores the records from the persistence file
+func (s *Store[T]) loadFromFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	jsonData, err := os.ReadFile(s.persistFile)
+	if os.IsNotExist(err) {
+		return nil // No existing data, start fresh
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s file: %w", s.kind, err)
+	}
+
+	var list []T
+	if err := json.Unmarshal(jsonData, &list); err != nil {
+		return fmt.Errorf("failed to parse %s JSON: %w", s.kind, err)
+	}
+
+	records := make(map[string]T, len(list))
+	for _, record := range list {
+		records[recordKey(record)] = record
+	}
+
+	s.mu.Lock()
+	s.records = records
+	s.mu.Unlock()
+
+	log.Printf("Loaded %d %s from %s", len(records), s.kind, s.persistFile)
+	return nil
+}
@@ -0,0 +1,39 @@
+package cellstore
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+type label struct {
+	Package string `json:"package"`
+	Series  string `json:"series"`
+	Text    string `json:"text"`
+}
+
+func (l label) Cell() (string, string) {
+	return l.Package, l.Series
+}
+
+func TestStoreKeepsOneRecordPerCellAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.json")
+	s := New[label](path, "labels")
+	s.Set(label{"nvidia-graphics-drivers-570", "noble", "old"}, label{"nvidia-graphics-drivers-535", "jammy", "kept"})
+	s.Set(label{"nvidia-graphics-drivers-570", "noble", "new"})
+	if !s.Remove("nvidia-graphics-drivers-535", "jammy") || s.Remove("nvidia-graphics-drivers-535", "jammy") {
+		t.Errorf("Remove() should report only the first removal")
+	}
+	s.Set(label{"nvidia-graphics-drivers-535", "focal", "x"})
+
+	reloaded := New[label](path, "labels")
+	list := reloaded.List(nil)
+	if len(list) != 2 || list[0].Package != "nvidia-graphics-drivers-535" || list[1].Text != "new" {
+		t.Errorf("List() after reload = %+v, expected 535/focal then the replaced 570/noble", list)
+	}
+	if got := reloaded.List(func(l label) bool { return l.Series == "noble" }); len(got) != 1 {
+		t.Errorf("List(noble) = %+v, expected one record", got)
+	}
+	if _, ok := reloaded.Get("nvidia-graphics-drivers-535", "jammy"); ok {
+		t.Errorf("Get() of a removed record should fail")
+	}
+}
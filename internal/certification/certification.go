@@ -0,0 +1,139 @@
+// Package certification reads the Ubuntu certified hardware that depends on an NVIDIA driver
+// branch from the certification API, or from a file in the same format.
+package certification
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"nvidia_driver_monitor/internal/cloudimages"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// maxPages bounds how many pages of the API are followed, in case "next" loops
+const maxPages = 100
+
+// Platform is a certified hardware platform and the driver branch it was certified with
+type Platform struct {
+	CanonicalID string `json:"canonical_id"` // Certificate id, e.g. "202404-34012"
+	Vendor      string `json:"vendor"`
+	Model       string `json:"model"`
+	Category    string `json:"category,omitempty"` // e.g. "Desktop", "Laptop" or "Server"
+	Series      string `json:"series"`             // Codename of the certified release, e.g. "noble"
+	Release     string `json:"release,omitempty"`  // e.g. "24.04 LTS"
+	Branch      string `json:"branch"`             // e.g. "570" or "570-server"
+	Driver      string `json:"driver"`             // Driver as listed by the API, e.g. "nvidia-driver-570"
+}
+
+// apiPlatform is an object of the certification API
+type apiPlatform struct {
+	CanonicalID string `json:"canonical_id"`
+	Make        string `json:"make"`
+	Model       string `json:"model"`
+	Category    string `json:"category"`
+	Release     struct {
+		Codename string `json:"codename"`
+		Release  string `json:"release"`
+	} `json:"release"`
+	Driver string `json:"driver"`
+}
+
+// page is one page of the certification API: {"meta": {"next": ...}, "objects": [...]}
+type page struct {
+	Meta struct {
+		Next string `json:"next"`
+	} `json:"meta"`
+	Objects []apiPlatform `json:"objects"`
+}
+
+// DriverBranch returns the branch of a certified driver, which the API lists either as a
+// package ("nvidia-driver-570-server") or as a bare branch ("570"); "" when it is not NVIDIA's
+func DriverBranch(driver string) string {
+	driver = strings.TrimSpace(driver)
+	if branch := cloudimages.DriverBranch(driver); branch != "" {
+		return branch
+	}
+	if cloudimages.DriverBranch("nvidia-driver-"+driver) == driver {
+		return driver
+	}
+	return ""
+}
+
+// parsePage decodes a page and keeps the platforms certified with an NVIDIA driver branch
+func parsePage(r io.Reader) ([]Platform, string, error) {
+	var p page
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, "", fmt.Errorf("failed to parse certified platforms: %w", err)
+	}
+	var platforms []Platform
+	for _, object := range p.Objects {
+		branch := DriverBranch(object.Driver)
+		if branch == "" || object.Release.Codename == "" {
+			continue
+		}
+		platforms = append(platforms, Platform{
+			CanonicalID: object.CanonicalID,
+			Vendor:      object.Make,
+			Model:       object.Model,
+			Category:    object.Category,
+			Series:      strings.ToLower(object.Release.Codename),
+			Release:     object.Release.Release,
+			Branch:      branch,
+			Driver:      object.Driver,
+		})
+	}
+	return platforms, p.Meta.Next, nil
+}
+
+// ReadFile reads the certified platforms from a file holding one page of the API
+func ReadFile(path string) ([]Platform, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	platforms, _, err := parsePage(file)
+	return platforms, err
+}
+
+// Fetch reads the certified platforms from the API, following the "next" link of each page.
+// A relative link is resolved against the page it was found on.
+func Fetch(apiURL string) ([]Platform, error) {
+	var platforms []Platform
+	next := apiURL
+	for pages := 0; next != ""; pages++ {
+		if pages == maxPages {
+			return nil, fmt.Errorf("certified platforms span more than %d pages", maxPages)
+		}
+		resp, err := utils.HTTPGetWithRetry(next)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", next, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to fetch %s: unexpected status code %d", next, resp.StatusCode)
+		}
+		found, link, err := parsePage(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, found...)
+
+		if link == "" {
+			break
+		}
+		base, _ := url.Parse(next)
+		ref, err := url.Parse(link)
+		if err != nil {
+			return nil, fmt.Errorf("invalid next page %q: %w", link, err)
+		}
+		next = base.ResolveReference(ref).String()
+	}
+	return platforms, nil
+}
@@ -0,0 +1,79 @@
+package certification
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const firstPage = `{"meta": {"next": "/api/v1/platforms/?offset=2"}, "objects": [
+  {"canonical_id": "202404-34012", "make": "Dell", "model": "Precision 5690", "category": "Laptop",
+   "release": {"codename": "noble", "release": "24.04 LTS"}, "driver": "nvidia-driver-570"},
+  {"canonical_id": "202404-34013", "make": "Dell", "model": "OptiPlex 7020", "category": "Desktop",
+   "release": {"codename": "noble", "release": "24.04 LTS"}, "driver": ""}
+]}`
+
+const secondPage = `{"meta": {"next": null}, "objects": [
+  {"canonical_id": "202204-30001", "make": "HPE", "model": "ProLiant DL380 Gen11", "category": "Server",
+   "release": {"codename": "Jammy", "release": "22.04 LTS"}, "driver": "535-server"}
+]}`
+
+func TestDriverBranch(t *testing.T) {
+	tests := map[string]string{
+		"nvidia-driver-570":        "570",
+		"nvidia-driver-535-server": "535-server",
+		"570":                      "570",
+		"535-server":               "535-server",
+		"amdgpu":                   "",
+		"":                         "",
+	}
+	for driver, want := range tests {
+		if got := DriverBranch(driver); got != want {
+			t.Errorf("DriverBranch(%q) = %q, expected %q", driver, got, want)
+		}
+	}
+}
+
+func TestFetchFollowsPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "2" {
+			w.Write([]byte(secondPage))
+			return
+		}
+		w.Write([]byte(firstPage))
+	}))
+	defer server.Close()
+
+	platforms, err := Fetch(server.URL + "/api/v1/platforms/")
+	if err != nil {
+		t.Fatalf("Fetch() returned error: %v", err)
+	}
+	if len(platforms) != 2 {
+		t.Fatalf("Fetch() = %+v, expected the two platforms certified with an NVIDIA driver", platforms)
+	}
+	if platforms[0].Vendor != "Dell" || platforms[0].Branch != "570" || platforms[0].Series != "noble" {
+		t.Errorf("platform 0 = %+v, expected the Dell laptop on 570 in noble", platforms[0])
+	}
+	if platforms[1].Branch != "535-server" || platforms[1].Series != "jammy" {
+		t.Errorf("platform 1 = %+v, expected the lowercased codename of the second page", platforms[1])
+	}
+
+	failing := httptest.NewServer(http.NotFoundHandler())
+	defer failing.Close()
+	if _, err := Fetch(failing.URL); err == nil {
+		t.Error("Fetch() of a missing API should return an error")
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "platforms.json")
+	if err := os.WriteFile(path, []byte(secondPage), 0644); err != nil {
+		t.Fatal(err)
+	}
+	platforms, err := ReadFile(path)
+	if err != nil || len(platforms) != 1 || platforms[0].CanonicalID != "202204-30001" {
+		t.Errorf("ReadFile() = %+v, %v, expected the HPE server", platforms, err)
+	}
+}
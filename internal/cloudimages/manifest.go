@@ -0,0 +1,81 @@
+package cloudimages
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// driverBinaryPattern matches the binaries built from a driver branch and captures the branch,
+// e.g. "nvidia-driver-570-server" or "libnvidia-compute-580"
+var driverBinaryPattern = regexp.MustCompile(`^(?:lib)?nvidia-[a-z0-9-]*?-(\d{3}(?:-server)?)$`)
+
+// Image is a published cloud image of a series, variant and architecture
+type Image struct {
+	Series  string `json:"series"`
+	Variant string `json:"variant"` // e.g. "server" or "minimal"
+	Arch    string `json:"arch"`
+}
+
+// Binary is a driver binary shipped in an image
+type Binary struct {
+	Name    string `json:"name"`
+	Branch  string `json:"branch"` // e.g. "570" or "570-server"
+	Version string `json:"version"`
+}
+
+// ManifestURL returns the package manifest of the current build of an image, e.g.
+// https://cloud-images.ubuntu.com/noble/current/noble-server-cloudimg-amd64.manifest
+func ManifestURL(base string, image Image) string {
+	return fmt.Sprintf("%s/%s/current/%s-%s-cloudimg-%s.manifest",
+		strings.TrimSuffix(base, "/"), image.Series, image.Series, image.Variant, image.Arch)
+}
+
+// DriverBranch returns the driver branch a binary is built from, or "" for other packages
+func DriverBranch(binary string) string {
+	match := driverBinaryPattern.FindStringSubmatch(binary)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// ParseManifest reads a manifest ("package[:arch]<TAB>version" per line) and returns the
+// driver binaries it lists, in manifest order
+func ParseManifest(r io.Reader) ([]Binary, error) {
+	var binaries []Binary
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		name, _, _ := strings.Cut(fields[0], ":")
+		if branch := DriverBranch(name); branch != "" {
+			binaries = append(binaries, Binary{Name: name, Branch: branch, Version: fields[1]})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return binaries, nil
+}
+
+// FetchManifest downloads and parses the manifest of an image
+func FetchManifest(base string, image Image) ([]Binary, error) {
+	url := ManifestURL(base, image)
+	resp, err := utils.HTTPGetWithRetry(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status code %d", url, resp.StatusCode)
+	}
+	return ParseManifest(resp.Body)
+}
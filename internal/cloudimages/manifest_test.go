@@ -0,0 +1,48 @@
+package cloudimages
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const nobleManifest = `adduser	3.137ubuntu1
+libnvidia-compute-570-server:amd64	570.172.08-0ubuntu0.24.04.1
+linux-modules-nvidia-570-server-generic	6.8.0-85.85+1
+nvidia-firmware-570-server-570.172.08	570.172.08-0ubuntu0.24.04.1
+nvidia-utils-570-server	570.172.08-0ubuntu0.24.04.1
+nvidia-settings	510.47.03-0ubuntu4
+`
+
+func TestParseManifest(t *testing.T) {
+	binaries, err := ParseManifest(strings.NewReader(nobleManifest))
+	if err != nil {
+		t.Fatalf("ParseManifest() returned error: %v", err)
+	}
+	if len(binaries) != 2 {
+		t.Fatalf("ParseManifest() = %+v, expected the compute library and utils only", binaries)
+	}
+	if binaries[0].Name != "libnvidia-compute-570-server" || binaries[0].Branch != "570-server" || binaries[0].Version != "570.172.08-0ubuntu0.24.04.1" {
+		t.Errorf("binary 0 = %+v, expected the architecture stripped from the name", binaries[0])
+	}
+}
+
+func TestFetchManifest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/noble/current/noble-server-cloudimg-amd64.manifest" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(nobleManifest))
+	}))
+	defer server.Close()
+
+	binaries, err := FetchManifest(server.URL+"/", Image{Series: "noble", Variant: "server", Arch: "amd64"})
+	if err != nil || len(binaries) != 2 {
+		t.Errorf("FetchManifest() = %+v, %v, expected 2 driver binaries", binaries, err)
+	}
+	if _, err := FetchManifest(server.URL, Image{Series: "jammy", Variant: "server", Arch: "amd64"}); err == nil {
+		t.Error("FetchManifest() of a missing image should return an error")
+	}
+}
@@ -3,20 +3,67 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+
+	"nvidia_driver_monitor/internal/faults"
+	"nvidia_driver_monitor/internal/i18n"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server       ServerConfig       `json:"server"`
-	Cache        CacheConfig        `json:"cache"`
-	RateLimit    RateLimitConfig    `json:"rate_limit"`
-	RequestLimit RequestLimitConfig `json:"request_limit"`
-	URLs         URLConfig          `json:"urls"`
-	HTTP         HTTPConfig         `json:"http"`
-	Processing   ProcessingConfig   `json:"processing"`
-	Testing      TestingConfig      `json:"testing"`
+	Server        ServerConfig        `json:"server"`
+	Cache         CacheConfig         `json:"cache"`
+	Janitor       JanitorConfig       `json:"janitor"`
+	RateLimit     RateLimitConfig     `json:"rate_limit"`
+	RequestLimit  RequestLimitConfig  `json:"request_limit"`
+	Security      SecurityConfig      `json:"security"`
+	URLs          URLConfig           `json:"urls"`
+	HTTP          HTTPConfig          `json:"http"`
+	Processing    ProcessingConfig    `json:"processing"`
+	Fleet         FleetConfig         `json:"fleet"`
+	Pockets       PocketsConfig       `json:"pockets"`
+	Series        SeriesConfig        `json:"series"`
+	Changelog     ChangelogConfig     `json:"changelog"`
+	Queue         QueueConfig         `json:"queue"`
+	Phasing       PhasingConfig       `json:"phasing"`
+	History       HistoryConfig       `json:"history"`
+	SLO           SLOConfig           `json:"slo"`
+	Targets       TargetsConfig       `json:"targets"`
+	DKMS          DKMSConfig          `json:"dkms"`
+	Advisories    AdvisoriesConfig    `json:"advisories"`
+	ArchiveCheck  ArchiveCheckConfig  `json:"archive_check"`
+	ReleaseCheck  ReleaseCheckConfig  `json:"release_check"`
+	Discovery     DiscoveryConfig     `json:"discovery"`
+	I386          I386Config          `json:"i386"`
+	CloudImages   CloudImagesConfig   `json:"cloud_images"`
+	Seeds         SeedsConfig         `json:"seeds"`
+	Certification CertificationConfig `json:"certification"`
+	IssueTracker  IssueTrackerConfig  `json:"issue_tracker"`
+	LRMReport     LRMReportConfig     `json:"lrm_report"`
+	Notes         NotesConfig         `json:"notes"`
+	Acks          AcksConfig          `json:"acknowledgements"`
+	I18n          I18nConfig          `json:"i18n"`
+	Tegra         TegraConfig         `json:"tegra"`
+	Releases      ReleasesConfig      `json:"supported_releases"`
+	Stats         StatsConfig         `json:"stats"`
+	Budget        BudgetConfig        `json:"budget"`
+	Alerts        AlertsConfig        `json:"alerts"`
+	Peer          PeerConfig          `json:"peer"`
+	Jobs          JobsConfig          `json:"jobs"`
+	Redis         RedisConfig         `json:"redis"`
+	Startup       StartupConfig       `json:"startup"`
+	Views         []ViewConfig        `json:"views"`
+	Presets       []PresetConfig      `json:"presets"`
+	Auth          AuthConfig          `json:"auth"`
+	Testing       TestingConfig       `json:"testing"`
+	Debug         DebugConfig         `json:"debug"`
 }
 
 // ServerConfig holds server-related configuration
@@ -30,6 +77,10 @@ type ServerConfig struct {
 type CacheConfig struct {
 	RefreshInterval string `json:"refresh_interval"` // Duration string like "15m"
 	Enabled         bool   `json:"enabled"`
+	// SourceVersionTTL bounds how long archive lookups are shared between subsystems
+	SourceVersionTTL string `json:"source_version_ttl"`
+	// MaxBackoff caps the wait between refresh attempts while upstream APIs are failing
+	MaxBackoff string `json:"max_backoff"`
 }
 
 // GetRefreshInterval parses and returns the refresh interval as time.Duration
@@ -46,10 +97,70 @@ func (c *CacheConfig) GetRefreshInterval() time.Duration {
 	return duration
 }
 
+// GetMaxBackoff parses and returns the longest wait between failing refresh attempts
+func (c *CacheConfig) GetMaxBackoff() time.Duration {
+	if c.MaxBackoff == "" {
+		return time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(c.MaxBackoff)
+	if err != nil || duration <= 0 {
+		return time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetSourceVersionTTL parses and returns the archive lookup memoization TTL
+func (c *CacheConfig) GetSourceVersionTTL() time.Duration {
+	if c.SourceVersionTTL == "" {
+		return 2 * time.Minute // default
+	}
+
+	duration, err := time.ParseDuration(c.SourceVersionTTL)
+	if err != nil {
+		return 2 * time.Minute // fallback to default
+	}
+
+	return duration
+}
+
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
-	RequestsPerMinute int  `json:"requests_per_minute"`
-	Enabled           bool `json:"enabled"`
+	RequestsPerMinute int    `json:"requests_per_minute"`
+	Enabled           bool   `json:"enabled"`
+	Backend           string `json:"backend"` // "memory" (per instance) or "redis" (shared through the redis connection)
+	Burst             int    `json:"burst"`   // Requests a client can make at once with the redis backend
+}
+
+// GetBackend returns where the request counts of the clients are kept
+func (r *RateLimitConfig) GetBackend() string {
+	if r.Backend == "" {
+		return "memory"
+	}
+	return r.Backend
+}
+
+// GetBurst returns the token bucket capacity of the redis backend, requests_per_minute by default
+func (r *RateLimitConfig) GetBurst() int {
+	if r.Burst < 1 {
+		return r.RequestsPerMinute
+	}
+	return r.Burst
+}
+
+// ValidateRateLimit checks the rate limit backend, and that the redis one has an address
+func (c *Config) ValidateRateLimit() error {
+	switch c.RateLimit.GetBackend() {
+	case "memory":
+	case "redis":
+		if c.Redis.Address == "" {
+			return fmt.Errorf("rate_limit.backend redis requires redis.address")
+		}
+	default:
+		return fmt.Errorf("rate_limit.backend must be memory or redis, got %q", c.RateLimit.Backend)
+	}
+	return nil
 }
 
 // RequestLimitConfig holds request limiting configuration
@@ -118,6 +229,22 @@ func (r *RequestLimitConfig) GetRequestTimeout() time.Duration {
 	return duration
 }
 
+// GetMaxBodySize returns the request body limit; 0 takes the 1MB default
+func (r *RequestLimitConfig) GetMaxBodySize() int64 {
+	if r.MaxBodySize <= 0 {
+		return 1048576 // default
+	}
+	return r.MaxBodySize
+}
+
+// GetMaxHeaderBytes returns the request header limit; 0 takes the 1MB default
+func (r *RequestLimitConfig) GetMaxHeaderBytes() int {
+	if r.MaxHeaderBytes <= 0 {
+		return 1048576 // default
+	}
+	return r.MaxHeaderBytes
+}
+
 // ValidateRequestLimits validates the request limits configuration
 func (r *RequestLimitConfig) ValidateRequestLimits() error {
 	if r.MaxBodySize < 0 {
@@ -204,6 +331,9 @@ func (c *Config) GetTestingURLs() URLConfig {
 
 	// Create testing URLs that point to local mock server
 	mockBase := fmt.Sprintf("http://localhost:%d", c.Testing.MockServerPort)
+	if c.Testing.Scenario != "" {
+		mockBase += "/scenarios/" + c.Testing.Scenario
+	}
 
 	return URLConfig{
 		Ubuntu: UbuntuURLs{
@@ -228,6 +358,80 @@ func (c *Config) GetTestingURLs() URLConfig {
 	}
 }
 
+// IsLocalURL reports whether a URL points at this host, where the mock server runs
+func IsLocalURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	host := parsed.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// upstreamURLs returns the upstream URLs that testing mode redirects to the mock server, by
+// configuration key
+func (u *URLConfig) upstreamURLs() [][2]string {
+	urls := [][2]string{
+		{"urls.ubuntu.assets_base_url", u.Ubuntu.AssetsBaseURL},
+		{"urls.launchpad.base_url", u.Launchpad.BaseURL},
+		{"urls.launchpad.published_sources_api", u.Launchpad.PublishedSourcesAPI},
+		{"urls.launchpad.published_binaries_api", u.Launchpad.PublishedBinariesAPI},
+		{"urls.launchpad.ubuntu_series_base_url", u.Launchpad.UbuntuSeriesBaseURL},
+		{"urls.nvidia.driver_archive_url", u.NVIDIA.DriverArchiveURL},
+		{"urls.nvidia.server_drivers_api", u.NVIDIA.ServerDriversAPI},
+		{"urls.kernel.series_yaml_url", u.Kernel.SeriesYAMLURL},
+		{"urls.kernel.sru_cycle_url", u.Kernel.SRUCycleURL},
+	}
+	for _, mirror := range u.Kernel.SeriesYAMLMirrors {
+		urls = append(urls, [2]string{"urls.kernel.series_yaml_mirrors", mirror})
+	}
+	for _, mirror := range u.Kernel.SRUCycleMirrors {
+		urls = append(urls, [2]string{"urls.kernel.sru_cycle_mirrors", mirror})
+	}
+	return urls
+}
+
+// ValidateMode refuses configurations mixing mock and production data: production mode with
+// an upstream on localhost, i.e. a mock server, and testing mode with an enabled check reading a
+// production upstream the mock server does not replace
+func (c *Config) ValidateMode() error {
+	if !c.Testing.Enabled {
+		for _, upstream := range c.URLs.upstreamURLs() {
+			if IsLocalURL(upstream[1]) {
+				return fmt.Errorf("%s points at %s, a local mock server, while testing is disabled; enable testing.enabled or use the production URL", upstream[0], upstream[1])
+			}
+		}
+		return nil
+	}
+
+	production := [][2]string{}
+	if c.ArchiveCheck.Enabled {
+		production = append(production, [2]string{"archive_check.mirror_url", c.ArchiveCheck.GetMirrorURL()})
+	}
+	if c.ReleaseCheck.Enabled {
+		production = append(production, [2]string{"release_check.mirror_url", c.ReleaseCheck.GetMirrorURL()})
+	}
+	if c.CloudImages.Enabled {
+		production = append(production, [2]string{"cloud_images.base_url", c.CloudImages.GetBaseURL()})
+	}
+	if c.Seeds.Enabled {
+		production = append(production, [2]string{"seeds.base_url", c.Seeds.GetBaseURL()})
+	}
+	if c.Tegra.Enabled {
+		production = append(production, [2]string{"tegra.releases_url", c.Tegra.GetReleasesURL()})
+	}
+	for _, upstream := range production {
+		if !IsLocalURL(upstream[1]) {
+			return fmt.Errorf("%s reads %s while testing is enabled, mixing production data into the mock data; disable the check or point it at a local server", upstream[0], upstream[1])
+		}
+	}
+	return nil
+}
+
 // GetEffectiveURLs returns the URLs that should be used (testing or production)
 func (c *Config) GetEffectiveURLs() URLConfig {
 	if c.Testing.Enabled {
@@ -247,13 +451,90 @@ type CDNURLs struct {
 	BootstrapCSS string `json:"bootstrap_css"`
 	BootstrapJS  string `json:"bootstrap_js"`
 	ChartJS      string `json:"chart_js"`
+	MermaidJS    string `json:"mermaid_js"`
 	VanillaCSS   string `json:"vanilla_css"`
+	// Subresource Integrity hashes of the assets, e.g. "sha384-..."; empty skips the check
+	BootstrapCSSIntegrity string `json:"bootstrap_css_integrity"`
+	BootstrapJSIntegrity  string `json:"bootstrap_js_integrity"`
+	ChartJSIntegrity      string `json:"chart_js_integrity"`
+	MermaidJSIntegrity    string `json:"mermaid_js_integrity"`
+	VanillaCSSIntegrity   string `json:"vanilla_css_integrity"`
+	// Offline serves the vendored copies in static/vendor instead of the CDN URLs
+	Offline bool `json:"offline"`
+}
+
+// Assets returns the URL and integrity hash of each asset by template name
+func (c *CDNURLs) Assets() map[string][2]string {
+	return map[string][2]string{
+		"BootstrapCSS": {c.BootstrapCSS, c.BootstrapCSSIntegrity},
+		"BootstrapJS":  {c.BootstrapJS, c.BootstrapJSIntegrity},
+		"ChartJS":      {c.ChartJS, c.ChartJSIntegrity},
+		"MermaidJS":    {c.MermaidJS, c.MermaidJSIntegrity},
+		"VanillaCSS":   {c.VanillaCSS, c.VanillaCSSIntegrity},
+	}
+}
+
+// VendorFile returns where the vendored copy of an asset lives, relative to the static directory
+func VendorFile(assetURL string) string {
+	if u, err := url.Parse(assetURL); err == nil {
+		assetURL = u.Path
+	}
+	return "vendor/" + path.Base(assetURL)
+}
+
+// Origins returns the sorted origins the assets are loaded from, none when offline
+func (c *CDNURLs) Origins() []string {
+	if c.Offline {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var origins []string
+	for _, asset := range c.Assets() {
+		u, err := url.Parse(asset[0])
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		if !seen[origin] {
+			seen[origin] = true
+			origins = append(origins, origin)
+		}
+	}
+	sort.Strings(origins)
+	return origins
+}
+
+// Validate checks that the integrity hashes use a hash algorithm browsers support
+func (c *CDNURLs) Validate() error {
+	for name, asset := range c.Assets() {
+		integrity := asset[1]
+		if integrity == "" {
+			continue
+		}
+		if !strings.HasPrefix(integrity, "sha256-") && !strings.HasPrefix(integrity, "sha384-") && !strings.HasPrefix(integrity, "sha512-") {
+			return fmt.Errorf("urls.cdn integrity of %s must start with sha256-, sha384- or sha512-, got %q", name, integrity)
+		}
+	}
+	return nil
 }
 
 // KernelURLs holds kernel-related URLs
 type KernelURLs struct {
 	SeriesYAMLURL string `json:"series_yaml_url"`
 	SRUCycleURL   string `json:"sru_cycle_url"`
+	// Mirrors are tried in order when the primary URL fails, e.g. a git-based raw mirror
+	SeriesYAMLMirrors []string `json:"series_yaml_mirrors"`
+	SRUCycleMirrors   []string `json:"sru_cycle_mirrors"`
+}
+
+// SeriesYAMLURLs returns the kernel-series.yaml URL followed by its mirrors
+func (k *KernelURLs) SeriesYAMLURLs() []string {
+	return append([]string{k.SeriesYAMLURL}, k.SeriesYAMLMirrors...)
+}
+
+// SRUCycleURLs returns the sru-cycle.yaml URL followed by its mirrors
+func (k *KernelURLs) SRUCycleURLs() []string {
+	return append([]string{k.SRUCycleURL}, k.SRUCycleMirrors...)
 }
 
 // HTTPConfig holds HTTP client configuration
@@ -263,6 +544,13 @@ type HTTPConfig struct {
 	UserAgent string `json:"user_agent"`
 	// ForgejoToken is used to access protected Forgejo raw URLs (e.g., kernel-series.yaml)
 	ForgejoToken string `json:"forgejo_token"`
+	// Proxies override HTTP_PROXY/HTTPS_PROXY; NoProxy hosts are added to NO_PROXY
+	HTTPProxy  string   `json:"http_proxy"`
+	HTTPSProxy string   `json:"https_proxy"`
+	NoProxy    []string `json:"no_proxy"` // e.g. ["launchpad.net", ".ubuntu.com"]
+	// Offline blocks every outbound request but those to a local mock server, for development
+	// on cached and persisted data without network access
+	Offline bool `json:"offline"`
 }
 
 // ProcessingConfig holds worker/concurrency configuration.
@@ -270,131 +558,1923 @@ type ProcessingConfig struct {
 	MaxConcurrency int `json:"max_concurrency"`
 }
 
-// TestingConfig holds testing/mock service configuration
-type TestingConfig struct {
-	Enabled        bool   `json:"enabled"`
-	MockServerPort int    `json:"mock_server_port"`
-	DataDir        string `json:"data_dir"`
+// FleetConfig holds host report ingestion configuration
+type FleetConfig struct {
+	DataFile   string `json:"data_file"`   // Where host reports are persisted
+	StaleAfter string `json:"stale_after"` // Duration string like "24h"
+	// GPUInventoryFile is where uploaded GPU inventories are persisted
+	GPUInventoryFile string `json:"gpu_inventory_file"`
+	// GPUBranchesFile is the JSON table mapping GPU PCI IDs and models to driver branches
+	GPUBranchesFile string `json:"gpu_branches_file"`
+	// ReportToken is the bearer token hosts present with their reports; env var FLEET_REPORT_TOKEN takes precedence
+	ReportToken string `json:"report_token"`
 }
 
-// GetTimeout parses and returns the timeout as time.Duration
-func (h *HTTPConfig) GetTimeout() time.Duration {
-	if h.Timeout == "" {
-		return 10 * time.Second // default
+// GetStaleAfter parses and returns how long a host may go without reporting before it is stale
+func (f *FleetConfig) GetStaleAfter() time.Duration {
+	if f.StaleAfter == "" {
+		return 24 * time.Hour // default
 	}
 
-	duration, err := time.ParseDuration(h.Timeout)
+	duration, err := time.ParseDuration(f.StaleAfter)
 	if err != nil {
-		return 10 * time.Second // fallback to default
+		return 24 * time.Hour // fallback to default
 	}
 
 	return duration
 }
 
-// GetMaxConcurrency returns the configured worker count clamped to safe limits.
-func (p *ProcessingConfig) GetMaxConcurrency() int {
-	if p.MaxConcurrency < 1 {
-		return 10
+// GetDataFile returns the host report persistence file
+func (f *FleetConfig) GetDataFile() string {
+	if f.DataFile == "" {
+		return "fleet_data.json"
 	}
-	if p.MaxConcurrency > 50 {
-		return 50
+	return f.DataFile
+}
+
+// GetGPUInventoryFile returns the GPU inventory persistence file
+func (f *FleetConfig) GetGPUInventoryFile() string {
+	if f.GPUInventoryFile == "" {
+		return "gpu_inventory_data.json"
 	}
-	return p.MaxConcurrency
+	return f.GPUInventoryFile
 }
 
-// GetForgejoToken returns the Forgejo token from env or config.
-// Env var KERNEL_FORGEJO_TOKEN takes precedence.
-func (h *HTTPConfig) GetForgejoToken() string {
-	if token := os.Getenv("KERNEL_FORGEJO_TOKEN"); token != "" {
+// GetGPUBranchesFile returns the GPU device-to-branch table
+func (f *FleetConfig) GetGPUBranchesFile() string {
+	if f.GPUBranchesFile == "" {
+		return "data/gpu-branches.json"
+	}
+	return f.GPUBranchesFile
+}
+
+// GetReportToken returns the bearer token accepted with host reports from env or config.
+// Env var FLEET_REPORT_TOKEN takes precedence.
+func (f *FleetConfig) GetReportToken() string {
+	if token := os.Getenv("FLEET_REPORT_TOKEN"); token != "" {
 		return token
 	}
-	return h.ForgejoToken
+	return f.ReportToken
 }
 
-// DefaultConfig returns the default configuration
-func DefaultConfig() *Config {
-	return &Config{
-		Server: ServerConfig{
-			Port:        8080,
-			HTTPSPort:   8443,
-			EnableHTTPS: false,
-		},
-		Cache: CacheConfig{
-			RefreshInterval: "15m",
-			Enabled:         true,
-		},
-		RateLimit: RateLimitConfig{
-			RequestsPerMinute: 60,
-			Enabled:           true,
-		},
-		RequestLimit: RequestLimitConfig{
-			MaxBodySize:    1048576, // 1MB
-			ReadTimeout:    "15s",
-			WriteTimeout:   "15s",
-			IdleTimeout:    "60s",
-			RequestTimeout: "30s",
-			MaxHeaderBytes: 1048576, // 1MB
-		},
-		URLs: URLConfig{
-			Ubuntu: UbuntuURLs{
-				AssetsBaseURL: "https://assets.ubuntu.com/v1",
-			},
-			Launchpad: LaunchpadURLs{
-				BaseURL:              "https://api.launchpad.net/devel",
-				PublishedSourcesAPI:  "https://api.launchpad.net/devel/ubuntu/+archive/primary",
-				PublishedBinariesAPI: "https://api.launchpad.net/devel/ubuntu/+archive/primary",
-				UbuntuSeriesBaseURL:  "https://api.launchpad.net/devel/ubuntu",
-				CreatedSinceDate:     "2026-01-01",
-			},
-			NVIDIA: NVIDIAURLs{
-				DriverArchiveURL: "https://download.nvidia.com/XFree86/Linux-x86_64/",
-				ServerDriversAPI: "https://docs.nvidia.com/datacenter/tesla/drivers/releases.json",
-			},
-			CDN: CDNURLs{
-				BootstrapCSS: "https://cdn.jsdelivr.net/npm/bootstrap@5.1.3/dist/css/bootstrap.min.css",
-				BootstrapJS:  "https://cdn.jsdelivr.net/npm/bootstrap@5.1.3/dist/js/bootstrap.bundle.min.js",
-				ChartJS:      "https://cdn.jsdelivr.net/npm/chart.js@3.9.1/dist/chart.min.js",
-				VanillaCSS:   "https://assets.ubuntu.com/v1/vanilla-framework-version-4.15.0.min.css",
-			},
-			Kernel: KernelURLs{
-				SeriesYAMLURL: "https://kernel.ubuntu.com/forgejo/kernel/kernel-versions/raw/branch/main/info/kernel-series.yaml",
-				SRUCycleURL:   "https://kernel.ubuntu.com/forgejo/kernel/kernel-versions/raw/branch/main/info/sru-cycle.yaml",
-			},
-		},
-		HTTP: HTTPConfig{
-			Timeout:   "30s",
-			Retries:   5,
-			UserAgent: "nvidia-driver-monitor/1.0",
-		},
-		Processing: ProcessingConfig{
-			MaxConcurrency: 10,
-		},
-		Testing: TestingConfig{
-			Enabled:        false,
-			MockServerPort: 9999,
-			DataDir:        "test-data",
-		},
+// PocketsConfig controls which archive pockets count as published and their display order
+type PocketsConfig struct {
+	Published []string `json:"published"` // e.g. ["Updates", "Security", "Release", "Backports"]
+}
+
+// knownPublishedPockets lists the pockets that may be merged into the published column
+var knownPublishedPockets = map[string]bool{
+	"Updates":   true,
+	"Security":  true,
+	"Release":   true,
+	"Backports": true,
+}
+
+// GetPublished returns the configured published pockets in display order, dropping unknown names
+func (p *PocketsConfig) GetPublished() []string {
+	var pockets []string
+	seen := make(map[string]bool)
+	for _, pocket := range p.Published {
+		if knownPublishedPockets[pocket] && !seen[pocket] {
+			pockets = append(pockets, pocket)
+			seen[pocket] = true
+		}
 	}
+	if len(pockets) == 0 {
+		return []string{"Updates", "Security", "Release"} // default
+	}
+	return pockets
 }
 
-// LoadConfig loads configuration from a file
-func LoadConfig(configPath string) (*Config, error) {
-	config := DefaultConfig()
+// SeriesConfig controls the order in which Ubuntu series are displayed and exported
+type SeriesConfig struct {
+	Order []string `json:"order"` // Newest first, e.g. ["resolute", "noble", "jammy"]
+	Devel string   `json:"devel"` // Codename the "devel" alias stands for; empty resolves it from Launchpad
+	// EOL maps codenames to their end-of-life date (YYYY-MM-DD), taking precedence over EOLFile
+	EOL map[string]string `json:"eol"`
+	// EOLFile is a distro-info CSV file (ubuntu.csv) to read EOL dates from; a missing file is ignored
+	EOLFile  string `json:"eol_file"`
+	EOLGrace string `json:"eol_grace"` // Duration series stay listed after their EOL, e.g. "720h"
+}
 
-	if configPath == "" {
-		return config, nil
+// GetEOLGrace parses and returns the grace period after a series' EOL
+func (s *SeriesConfig) GetEOLGrace() time.Duration {
+	if s.EOLGrace == "" {
+		return 30 * 24 * time.Hour // default
+	}
+	duration, err := time.ParseDuration(s.EOLGrace)
+	if err != nil || duration < 0 {
+		return 30 * 24 * time.Hour // fallback
 	}
+	return duration
+}
 
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return config, nil // Use defaults if file doesn't exist
+// GetOrder returns the configured series order, dropping duplicates
+func (s *SeriesConfig) GetOrder() []string {
+	var order []string
+	seen := make(map[string]bool)
+	for _, series := range s.Order {
+		if series != "" && !seen[series] {
+			order = append(order, series)
+			seen[series] = true
 		}
-		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
+	if len(order) == 0 {
+		return []string{"resolute", "noble", "jammy", "focal", "bionic"} // default
+	}
+	return order
+}
 
-	if err := json.Unmarshal(data, config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+// Sort orders series names by the configured order; names missing from it are appended
+// alphabetically so output is deterministic
+func (s *SeriesConfig) Sort(names []string) []string {
+	rank := make(map[string]int)
+	for i, series := range s.GetOrder() {
+		rank[series] = i
+	}
+	sorted := make([]string, len(names))
+	copy(sorted, names)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, knownI := rank[sorted[i]]
+		rj, knownJ := rank[sorted[j]]
+		switch {
+		case knownI && knownJ:
+			return ri < rj
+		case knownI != knownJ:
+			return knownI
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+// ChangelogConfig controls fetching of Debian changelogs for published versions
+type ChangelogConfig struct {
+	Enabled  bool   `json:"enabled"`
+	CacheDir string `json:"cache_dir"` // Directory where parsed changelog entries are cached
+	// NotifyUpdates sends a notice with the release notes of each new archive version
+	NotifyUpdates bool `json:"notify_updates"`
+}
+
+// GetCacheDir returns the changelog cache directory
+func (c *ChangelogConfig) GetCacheDir() string {
+	if c.CacheDir == "" {
+		return "changelog_cache"
+	}
+	return c.CacheDir
+}
+
+// QueueConfig controls querying of the Launchpad upload queues (unapproved/NEW)
+type QueueConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// PhasingConfig controls querying of the phased update percentage of the published drivers
+type PhasingConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TargetsConfig selects where per-branch target versions are read from; URL takes precedence
+type TargetsConfig struct {
+	File string `json:"file"` // Static JSON file mapping branch to {"version", "note"}
+	URL  string `json:"url"`  // HTTP JSON feed in the same format
+}
+
+// DKMSConfig holds the DKMS kernel compatibility matrix configuration
+type DKMSConfig struct {
+	CompatFile string `json:"compat_file"` // JSON file mapping branch to its supported kernel range
+}
+
+// GetCompatFile returns the kernel compatibility file
+func (d *DKMSConfig) GetCompatFile() string {
+	if d.CompatFile == "" {
+		return "data/dkms-compat.json"
+	}
+	return d.CompatFile
+}
+
+// AdvisoriesConfig holds the known bad driver/kernel combinations datastore configuration
+type AdvisoriesConfig struct {
+	DataFile string `json:"data_file"` // Where advisories edited through the API are persisted
+}
+
+// GetDataFile returns the advisories persistence file
+func (a *AdvisoriesConfig) GetDataFile() string {
+	if a.DataFile == "" {
+		return "advisories_data.json"
+	}
+	return a.DataFile
+}
+
+// NotesConfig holds the package/series notes store configuration
+type NotesConfig struct {
+	DataFile string `json:"data_file"` // Where notes edited through the API are persisted
+}
+
+// GetDataFile returns the notes persistence file
+func (n *NotesConfig) GetDataFile() string {
+	if n.DataFile == "" {
+		return "notes_data.json"
+	}
+	return n.DataFile
+}
+
+// AcksConfig holds the acknowledged (intentionally outdated) cells store configuration
+type AcksConfig struct {
+	DataFile string `json:"data_file"` // Where acknowledgements set through the API are persisted
+}
+
+// GetDataFile returns the acknowledgements persistence file
+func (a *AcksConfig) GetDataFile() string {
+	if a.DataFile == "" {
+		return "acknowledgements_data.json"
+	}
+	return a.DataFile
+}
+
+// I18nConfig holds the web UI translation configuration
+type I18nConfig struct {
+	// DefaultLocale is used when the Accept-Language header of a request matches no catalog
+	DefaultLocale string `json:"default_locale"`
+	// DefaultTimezone is the IANA time zone dates are shown in without ?tz=, e.g. "Europe/London"
+	DefaultTimezone string `json:"default_timezone"`
+}
+
+// GetDefaultLocale returns the locale used when the browser asks for none we support
+func (c *I18nConfig) GetDefaultLocale() string {
+	if c.DefaultLocale == "" {
+		return i18n.DefaultLocale
+	}
+	return c.DefaultLocale
+}
+
+// GetDefaultTimezone returns the time zone dates are shown in when the request picks none,
+// defaulting to UTC
+func (c *I18nConfig) GetDefaultTimezone() string {
+	if c.DefaultTimezone == "" {
+		return "UTC"
+	}
+	return c.DefaultTimezone
+}
+
+// Validate checks that the default locale has a catalog and the default time zone exists
+func (c *I18nConfig) Validate() error {
+	if !i18n.IsSupported(c.GetDefaultLocale()) {
+		return fmt.Errorf("i18n.default_locale %q is not one of %s", c.DefaultLocale, strings.Join(i18n.Supported(), ", "))
+	}
+	if _, err := time.LoadLocation(c.GetDefaultTimezone()); err != nil {
+		return fmt.Errorf("i18n.default_timezone %q is not a known time zone: %v", c.DefaultTimezone, err)
+	}
+	return nil
+}
+
+// BudgetConfig holds the daily upstream request budgets
+type BudgetConfig struct {
+	DataFile string           `json:"data_file"` // Where the daily request counts are persisted
+	Daily    map[string]int64 `json:"daily"`     // Host (subdomains included) to requests per UTC day, e.g. {"launchpad.net": 100000}
+	WarnAt   []int            `json:"warn_at"`   // Percentages of a budget at which to warn and slow down, e.g. [80, 95]
+}
+
+// GetDataFile returns the budget persistence file
+func (b *BudgetConfig) GetDataFile() string {
+	if b.DataFile == "" {
+		return "budget_data.json"
+	}
+	return b.DataFile
+}
+
+// GetWarnAt returns the warning percentages
+func (b *BudgetConfig) GetWarnAt() []int {
+	if len(b.WarnAt) == 0 {
+		return []int{80, 95}
+	}
+	return b.WarnAt
+}
+
+// Validate rejects budgets that cannot be enforced
+func (b *BudgetConfig) Validate() error {
+	for host, limit := range b.Daily {
+		if limit <= 0 {
+			return fmt.Errorf("budget.daily[%s] must be positive, got %d", host, limit)
+		}
+	}
+	for _, percent := range b.WarnAt {
+		if percent <= 0 || percent > 100 {
+			return fmt.Errorf("budget.warn_at must be between 1 and 100, got %d", percent)
+		}
+	}
+	return nil
+}
+
+// TegraConfig controls tracking of the Tegra/Jetson (L4T) driver branches
+type TegraConfig struct {
+	Enabled       bool   `json:"enabled"`
+	ReleasesURL   string `json:"releases_url"`   // Directory listing of the L4T releases, e.g. r36.4/
+	PackagePrefix string `json:"package_prefix"` // Source package of a branch is this prefix followed by the L4T major
+}
+
+// GetReleasesURL returns the L4T release listing
+func (t *TegraConfig) GetReleasesURL() string {
+	if t.ReleasesURL == "" {
+		return "https://repo.download.nvidia.com/jetson/common/dists/"
+	}
+	return t.ReleasesURL
+}
+
+// GetPackagePrefix returns the source package prefix of the Tegra branches
+func (t *TegraConfig) GetPackagePrefix() string {
+	if t.PackagePrefix == "" {
+		return "nvidia-tegra-drivers-"
+	}
+	return t.PackagePrefix
+}
+
+// ReleasesConfig selects how the supported releases file is composed
+type ReleasesConfig struct {
+	// Profile names a profile of the supported releases file whose includes and releases are
+	// added to the base list; empty uses the base list only
+	Profile string `json:"profile"`
+}
+
+// StatsConfig holds the upstream API statistics collector configuration
+type StatsConfig struct {
+	DataFile     string `json:"data_file"`     // Where the statistics windows are persisted
+	Window       string `json:"window"`        // Duration of one statistics window, e.g. "10m"
+	Retention    int    `json:"retention"`     // Number of past windows kept
+	SaveInterval string `json:"save_interval"` // Time between saves of the data file, e.g. "5m"
+}
+
+// GetDataFile returns the statistics persistence file
+func (s *StatsConfig) GetDataFile() string {
+	if s.DataFile == "" {
+		return "statistics_data.json"
+	}
+	return s.DataFile
+}
+
+// GetWindow parses and returns the duration of a statistics window
+func (s *StatsConfig) GetWindow() time.Duration {
+	duration, err := time.ParseDuration(s.Window)
+	if err != nil || duration <= 0 {
+		return 10 * time.Minute // default
+	}
+	return duration
+}
+
+// GetRetention returns the number of past windows kept
+func (s *StatsConfig) GetRetention() int {
+	if s.Retention <= 0 {
+		return 100
+	}
+	return s.Retention
+}
+
+// GetSaveInterval parses and returns the time between saves of the data file
+func (s *StatsConfig) GetSaveInterval() time.Duration {
+	duration, err := time.ParseDuration(s.SaveInterval)
+	if err != nil || duration <= 0 {
+		return 5 * time.Minute // default
+	}
+	return duration
+}
+
+// Validate rejects statistics settings that cannot be used; empty values take the defaults
+func (s *StatsConfig) Validate() error {
+	if s.Window != "" {
+		duration, err := time.ParseDuration(s.Window)
+		if err != nil {
+			return fmt.Errorf("stats.window: %w", err)
+		}
+		if duration < time.Minute {
+			return fmt.Errorf("stats.window must be at least 1m, got %s", s.Window)
+		}
+	}
+	if s.SaveInterval != "" {
+		duration, err := time.ParseDuration(s.SaveInterval)
+		if err != nil {
+			return fmt.Errorf("stats.save_interval: %w", err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("stats.save_interval must be positive, got %s", s.SaveInterval)
+		}
+	}
+	if s.Retention < 0 || s.Retention > 10000 {
+		return fmt.Errorf("stats.retention must be between 1 and 10000, got %d", s.Retention)
+	}
+	return nil
+}
+
+// ArchiveCheckConfig holds the scheduled consistency check against the archive Sources indexes
+type ArchiveCheckConfig struct {
+	Enabled    bool     `json:"enabled"`
+	Interval   string   `json:"interval"`   // Time between checks, e.g. "24h"
+	MirrorURL  string   `json:"mirror_url"` // Archive root holding dists/, e.g. "http://archive.ubuntu.com/ubuntu"
+	Components []string `json:"components"` // Components whose Sources.xz are read
+}
+
+// GetInterval returns the time between consistency checks
+func (a *ArchiveCheckConfig) GetInterval() time.Duration {
+	if a.Interval == "" {
+		return 24 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(a.Interval)
+	if err != nil || duration <= 0 {
+		return 24 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetMirrorURL returns the archive root without a trailing slash
+func (a *ArchiveCheckConfig) GetMirrorURL() string {
+	if a.MirrorURL == "" {
+		return "http://archive.ubuntu.com/ubuntu"
+	}
+	return strings.TrimSuffix(a.MirrorURL, "/")
+}
+
+// GetComponents returns the archive components to read
+func (a *ArchiveCheckConfig) GetComponents() []string {
+	if len(a.Components) == 0 {
+		return []string{"main", "restricted", "universe", "multiverse"}
+	}
+	return a.Components
+}
+
+// ReleaseCheckConfig holds the health check of the archive Release files of the shown suites
+type ReleaseCheckConfig struct {
+	Enabled    bool     `json:"enabled"`
+	Interval   string   `json:"interval"`   // Time between checks, e.g. "1h"
+	MirrorURL  string   `json:"mirror_url"` // Archive root holding dists/, e.g. "http://archive.ubuntu.com/ubuntu"
+	Components []string `json:"components"` // Components every suite must list
+	MaxAge     string   `json:"max_age"`    // Age of the Date of a pocket suite after which it is stale, e.g. "48h"
+	Keyring    string   `json:"keyring"`    // Keyring verifying the signatures with gpgv; empty only checks they are present
+}
+
+// GetInterval returns the time between Release file checks
+func (r *ReleaseCheckConfig) GetInterval() time.Duration {
+	if r.Interval == "" {
+		return time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(r.Interval)
+	if err != nil || duration <= 0 {
+		return time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetMirrorURL returns the archive root without a trailing slash
+func (r *ReleaseCheckConfig) GetMirrorURL() string {
+	if r.MirrorURL == "" {
+		return "http://archive.ubuntu.com/ubuntu"
+	}
+	return strings.TrimSuffix(r.MirrorURL, "/")
+}
+
+// GetComponents returns the components every suite must list
+func (r *ReleaseCheckConfig) GetComponents() []string {
+	if len(r.Components) == 0 {
+		return []string{"restricted", "multiverse"}
+	}
+	return r.Components
+}
+
+// GetMaxAge returns the age after which the Release file of a pocket suite is stale
+func (r *ReleaseCheckConfig) GetMaxAge() time.Duration {
+	if r.MaxAge == "" {
+		return 48 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(r.MaxAge)
+	if err != nil || duration <= 0 {
+		return 48 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// DiscoveryConfig holds the scan of the archive for NVIDIA related source packages the monitor
+// does not track
+type DiscoveryConfig struct {
+	Enabled  bool     `json:"enabled"`
+	Interval string   `json:"interval"` // Time between scans, e.g. "24h"
+	Search   string   `json:"search"`   // Substring Launchpad matches the source names against, e.g. "nvidia"
+	Patterns []string `json:"patterns"` // Globs of the source names reported, e.g. "nvidia-*"
+	Ignore   []string `json:"ignore"`   // Globs of the source names deliberately not tracked
+}
+
+// GetInterval returns the time between discovery scans
+func (d *DiscoveryConfig) GetInterval() time.Duration {
+	if d.Interval == "" {
+		return 24 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(d.Interval)
+	if err != nil || duration <= 0 {
+		return 24 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetSearch returns the substring the source names are searched for
+func (d *DiscoveryConfig) GetSearch() string {
+	if d.Search == "" {
+		return "nvidia"
+	}
+	return d.Search
+}
+
+// GetPatterns returns the globs of the source names reported
+func (d *DiscoveryConfig) GetPatterns() []string {
+	if len(d.Patterns) == 0 {
+		return []string{"nvidia-*", "libnvidia-*"}
+	}
+	return d.Patterns
+}
+
+// Validate rejects malformed globs
+func (d *DiscoveryConfig) Validate() error {
+	for _, pattern := range append(append([]string{}, d.Patterns...), d.Ignore...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("discovery pattern %q is invalid: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// DebugConfig bounds the debug logging switched on at runtime through /api/v1/debug
+type DebugConfig struct {
+	DefaultDuration   string `json:"default_duration"`     // How long debugging stays on when no duration is given, e.g. "15m"
+	MaxDuration       string `json:"max_duration"`         // Longest duration that may be requested, e.g. "4h"
+	MaxLinesPerMinute int    `json:"max_lines_per_minute"` // Debug lines written per minute; the rest are dropped
+}
+
+// GetDefaultDuration returns how long debugging stays on when no duration is requested
+func (d *DebugConfig) GetDefaultDuration() time.Duration {
+	if d.DefaultDuration == "" {
+		return 15 * time.Minute // default
+	}
+
+	duration, err := time.ParseDuration(d.DefaultDuration)
+	if err != nil || duration <= 0 {
+		return 15 * time.Minute // fallback to default
+	}
+
+	return duration
+}
+
+// GetMaxDuration returns the longest duration debugging may be switched on for
+func (d *DebugConfig) GetMaxDuration() time.Duration {
+	if d.MaxDuration == "" {
+		return 4 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(d.MaxDuration)
+	if err != nil || duration <= 0 {
+		return 4 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetMaxLinesPerMinute returns how many debug lines may be written per minute
+func (d *DebugConfig) GetMaxLinesPerMinute() int {
+	if d.MaxLinesPerMinute <= 0 {
+		return 600
+	}
+	return d.MaxLinesPerMinute
+}
+
+// I386Config holds the check that the i386 multiarch libraries of the UDA branches are built
+type I386Config struct {
+	Enabled   bool     `json:"enabled"`
+	Interval  string   `json:"interval"`  // Time between checks, e.g. "6h"
+	Libraries []string `json:"libraries"` // Binary names without the branch, e.g. "libnvidia-gl"
+}
+
+// GetInterval returns the time between i386 checks
+func (i *I386Config) GetInterval() time.Duration {
+	if i.Interval == "" {
+		return 6 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(i.Interval)
+	if err != nil || duration <= 0 {
+		return 6 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetLibraries returns the i386 libraries expected for every UDA branch
+func (i *I386Config) GetLibraries() []string {
+	if len(i.Libraries) == 0 {
+		return []string{"libnvidia-gl"}
+	}
+	return i.Libraries
+}
+
+// CloudImagesConfig holds the check of the drivers shipped in the Ubuntu cloud images
+type CloudImagesConfig struct {
+	Enabled       bool     `json:"enabled"`
+	Interval      string   `json:"interval"`      // Time between checks, e.g. "24h"
+	BaseURL       string   `json:"base_url"`      // Root of the image streams, e.g. "https://cloud-images.ubuntu.com"
+	Variants      []string `json:"variants"`      // Image variants, e.g. ["server", "minimal"]
+	Architectures []string `json:"architectures"` // e.g. ["amd64", "arm64"]
+}
+
+// GetInterval returns the time between cloud image checks
+func (c *CloudImagesConfig) GetInterval() time.Duration {
+	if c.Interval == "" {
+		return 24 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(c.Interval)
+	if err != nil || duration <= 0 {
+		return 24 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetBaseURL returns the root of the cloud image streams
+func (c *CloudImagesConfig) GetBaseURL() string {
+	if c.BaseURL == "" {
+		return "https://cloud-images.ubuntu.com"
+	}
+	return c.BaseURL
+}
+
+// GetVariants returns the image variants whose manifests are read
+func (c *CloudImagesConfig) GetVariants() []string {
+	if len(c.Variants) == 0 {
+		return []string{"server"}
+	}
+	return c.Variants
+}
+
+// GetArchitectures returns the image architectures whose manifests are read
+func (c *CloudImagesConfig) GetArchitectures() []string {
+	if len(c.Architectures) == 0 {
+		return []string{"amd64"}
+	}
+	return c.Architectures
+}
+
+// SeedsConfig holds the check of the driver branch seeded by default in each series
+type SeedsConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Interval string `json:"interval"` // Time between checks, e.g. "24h"
+	BaseURL  string `json:"base_url"` // Platform seeds repository, read as <base_url>/restricted?h=<series>
+}
+
+// GetInterval returns the time between seed checks
+func (c *SeedsConfig) GetInterval() time.Duration {
+	if c.Interval == "" {
+		return 24 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(c.Interval)
+	if err != nil || duration <= 0 {
+		return 24 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetBaseURL returns the platform seeds repository
+func (c *SeedsConfig) GetBaseURL() string {
+	if c.BaseURL == "" {
+		return "https://git.launchpad.net/~ubuntu-core-dev/ubuntu-seeds/+git/platform/plain"
+	}
+	return c.BaseURL
+}
+
+// CertificationConfig holds the cross-reference of the Ubuntu certified hardware with the driver
+// branches it was certified with
+type CertificationConfig struct {
+	Enabled  bool   `json:"enabled"`
+	URL      string `json:"url"`      // Certification API listing the certified platforms, followed page by page
+	File     string `json:"file"`     // JSON file in the format of one API page, used instead of url
+	Interval string `json:"interval"` // Time between checks, e.g. "24h"
+	// BranchEOL maps driver branches to the date NVIDIA ends their support (YYYY-MM-DD)
+	BranchEOL  map[string]string `json:"branch_eol"`
+	EOLWarning string            `json:"eol_warning"` // How long before a branch EOL certified hardware is warned about, e.g. "2160h"
+}
+
+// GetInterval returns the time between certification checks
+func (c *CertificationConfig) GetInterval() time.Duration {
+	if c.Interval == "" {
+		return 24 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(c.Interval)
+	if err != nil || duration <= 0 {
+		return 24 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetEOLWarning returns how long before a branch EOL its certified hardware is warned about
+func (c *CertificationConfig) GetEOLWarning() time.Duration {
+	if c.EOLWarning == "" {
+		return 90 * 24 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(c.EOLWarning)
+	if err != nil || duration < 0 {
+		return 90 * 24 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// Validate requires a source of the certified platforms and well-formed branch EOL dates
+func (c *CertificationConfig) Validate() error {
+	if c.Enabled && c.URL == "" && c.File == "" {
+		return fmt.Errorf("certification.url or certification.file is required when certification is enabled")
+	}
+	for branch, date := range c.BranchEOL {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("certification.branch_eol[%q] must be a YYYY-MM-DD date, got %q", branch, date)
+		}
+	}
+	return nil
+}
+
+// IssueTrackerConfig holds the issues opened in a GitHub or Forgejo repository for cells that
+// stay outdated
+type IssueTrackerConfig struct {
+	Enabled       bool     `json:"enabled"`
+	Kind          string   `json:"kind"`           // "github" or "forgejo"
+	APIURL        string   `json:"api_url"`        // e.g. "https://forgejo.example.com/api/v1"; defaults to api.github.com for GitHub
+	Repository    string   `json:"repository"`     // "owner/repo"
+	Token         string   `json:"token"`          // API token; env ISSUE_TRACKER_TOKEN takes precedence
+	Labels        []string `json:"labels"`         // Label names added to GitHub issues
+	ThresholdDays int      `json:"threshold_days"` // Days a cell stays outdated before an issue is opened
+	Interval      string   `json:"interval"`       // Time between syncs, e.g. "1h"
+	DashboardURL  string   `json:"dashboard_url"`  // Public URL of the dashboard linked from issues, e.g. "https://monitor.example.com"
+	DataFile      string   `json:"data_file"`      // Where the open issues are remembered
+}
+
+// GetAPIURL returns the API root of the tracker
+func (t *IssueTrackerConfig) GetAPIURL() string {
+	if t.APIURL == "" && t.Kind == "github" {
+		return "https://api.github.com"
+	}
+	return t.APIURL
+}
+
+// GetToken returns the API token, preferring the ISSUE_TRACKER_TOKEN environment variable
+func (t *IssueTrackerConfig) GetToken() string {
+	if token := os.Getenv("ISSUE_TRACKER_TOKEN"); token != "" {
+		return token
+	}
+	return t.Token
+}
+
+// GetThresholdDays returns how long a cell stays outdated before an issue is opened, defaulting to 14 days
+func (t *IssueTrackerConfig) GetThresholdDays() int {
+	if t.ThresholdDays < 1 {
+		return 14
+	}
+	return t.ThresholdDays
+}
+
+// GetInterval returns the time between tracker syncs
+func (t *IssueTrackerConfig) GetInterval() time.Duration {
+	if t.Interval == "" {
+		return time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(t.Interval)
+	if err != nil || duration <= 0 {
+		return time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetDataFile returns the tracker issues persistence file
+func (t *IssueTrackerConfig) GetDataFile() string {
+	if t.DataFile == "" {
+		return "tracker_issues.json"
+	}
+	return t.DataFile
+}
+
+// LRMReportConfig schedules the report of the L-R-M problems sent to the kernel and drivers
+// teams once per SRU cycle, by email and/or webhook
+type LRMReportConfig struct {
+	Enabled           bool     `json:"enabled"`
+	DaysBeforeRelease int      `json:"days_before_release"` // Days ahead of each cycle's release date the report is sent
+	WebhookURLs       []string `json:"webhook_urls"`        // Receive the report as JSON, its HTML included
+	SMTPServer        string   `json:"smtp_server"`         // "host:port", e.g. "smtp.example.com:587"; empty sends no email
+	SMTPUsername      string   `json:"smtp_username"`       // Empty sends without authentication
+	SMTPPassword      string   `json:"smtp_password"`       // Env LRM_REPORT_SMTP_PASSWORD takes precedence
+	From              string   `json:"from"`
+	To                []string `json:"to"`            // e.g. the kernel and drivers team lists
+	DashboardURL      string   `json:"dashboard_url"` // Public URL of the dashboard linked from the report
+	DataFile          string   `json:"data_file"`     // Where the cycles already reported are remembered
+}
+
+// GetDaysBeforeRelease returns how many days ahead of a cycle's release the report is sent,
+// defaulting to 2
+func (r *LRMReportConfig) GetDaysBeforeRelease() int {
+	if r.DaysBeforeRelease < 1 {
+		return 2
+	}
+	return r.DaysBeforeRelease
+}
+
+// GetSMTPPassword returns the SMTP password, preferring the LRM_REPORT_SMTP_PASSWORD
+// environment variable
+func (r *LRMReportConfig) GetSMTPPassword() string {
+	if password := os.Getenv("LRM_REPORT_SMTP_PASSWORD"); password != "" {
+		return password
+	}
+	return r.SMTPPassword
+}
+
+// GetDataFile returns the file remembering the reported cycles
+func (r *LRMReportConfig) GetDataFile() string {
+	if r.DataFile == "" {
+		return "lrm_reports.json"
+	}
+	return r.DataFile
+}
+
+// EmailEnabled reports whether the report is emailed
+func (r *LRMReportConfig) EmailEnabled() bool {
+	return r.SMTPServer != ""
+}
+
+// Validate requires somewhere to send the report to when it is enabled
+func (r *LRMReportConfig) Validate() error {
+	if !r.Enabled {
+		return nil
+	}
+	if !r.EmailEnabled() && len(r.WebhookURLs) == 0 {
+		return fmt.Errorf("lrm_report.smtp_server or lrm_report.webhook_urls is required when the L-R-M report is enabled")
+	}
+	if r.EmailEnabled() && (r.From == "" || len(r.To) == 0) {
+		return fmt.Errorf("lrm_report.from and lrm_report.to are required to email the L-R-M report")
+	}
+	return nil
+}
+
+// JobsConfig holds the exports and reports that CI systems trigger through the API
+type JobsConfig struct {
+	Enabled    bool   `json:"enabled"`
+	Token      string `json:"token"`       // Bearer token for the job endpoints; env JOBS_TOKEN takes precedence
+	Dir        string `json:"dir"`         // Where job output is kept until it expires
+	Retention  string `json:"retention"`   // How long job output can be downloaded, e.g. "24h"
+	MaxRunning int    `json:"max_running"` // Jobs running at once; more are refused
+}
+
+// GetToken returns the job token from env or config.
+// Env var JOBS_TOKEN takes precedence.
+func (j *JobsConfig) GetToken() string {
+	if token := os.Getenv("JOBS_TOKEN"); token != "" {
+		return token
+	}
+	return j.Token
+}
+
+// GetDir returns the job output directory
+func (j *JobsConfig) GetDir() string {
+	if j.Dir == "" {
+		return "jobs"
+	}
+	return j.Dir
+}
+
+// GetRetention returns how long job output is kept
+func (j *JobsConfig) GetRetention() time.Duration {
+	if j.Retention == "" {
+		return 24 * time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(j.Retention)
+	if err != nil || duration <= 0 {
+		return 24 * time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetMaxRunning returns how many jobs may run at once
+func (j *JobsConfig) GetMaxRunning() int {
+	if j.MaxRunning < 1 {
+		return 2
+	}
+	return j.MaxRunning
+}
+
+// ValidateJobs requires the job endpoints to be protected, by a token or by a login
+func (c *Config) ValidateJobs() error {
+	if c.Jobs.Enabled && c.Jobs.GetToken() == "" && !c.Auth.OIDC.Enabled {
+		return fmt.Errorf("jobs requires jobs.token or auth.oidc when enabled")
+	}
+	return nil
+}
+
+// PeerConfig holds the cache priming from another instance when a replica starts
+type PeerConfig struct {
+	URL     string `json:"url"`     // Base URL of the instance to prime from, e.g. "http://monitor-0:8080"; empty loads from the upstreams
+	Timeout string `json:"timeout"` // Time allowed for the snapshot download, e.g. "30s"
+	MaxAge  string `json:"max_age"` // Snapshots whose data is older than this are ignored, e.g. "1h"
+	Token   string `json:"token"`   // Shared secret for /api/v1/snapshot; env PEER_TOKEN takes precedence
+}
+
+// GetTimeout returns the time allowed for the snapshot download
+func (p *PeerConfig) GetTimeout() time.Duration {
+	if p.Timeout == "" {
+		return 30 * time.Second // default
+	}
+
+	duration, err := time.ParseDuration(p.Timeout)
+	if err != nil || duration <= 0 {
+		return 30 * time.Second // fallback to default
+	}
+
+	return duration
+}
+
+// GetMaxAge returns the age beyond which a peer snapshot is not used
+func (p *PeerConfig) GetMaxAge() time.Duration {
+	if p.MaxAge == "" {
+		return time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(p.MaxAge)
+	if err != nil || duration <= 0 {
+		return time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetToken returns the snapshot token from env or config.
+// Env var PEER_TOKEN takes precedence.
+func (p *PeerConfig) GetToken() string {
+	if token := os.Getenv("PEER_TOKEN"); token != "" {
+		return token
+	}
+	return p.Token
+}
+
+// RedisConfig holds the optional Redis cache of the serialized responses of the hot API
+// endpoints, for deployments with many pollers
+type RedisConfig struct {
+	Enabled   bool   `json:"enabled"`
+	Address   string `json:"address"`    // Redis host:port, e.g. "redis:6379"
+	Password  string `json:"password"`   // env REDIS_PASSWORD takes precedence
+	DB        int    `json:"db"`         // Database number selected after connecting
+	KeyPrefix string `json:"key_prefix"` // Prefix of every key written, e.g. "nvidia-monitor:"
+	TTL       string `json:"ttl"`        // How long a cached response is kept, e.g. "15m"
+	Timeout   string `json:"timeout"`    // Time allowed for a Redis round trip, e.g. "200ms"
+	PoolSize  int    `json:"pool_size"`  // Idle connections kept open
+}
+
+// GetPassword returns the Redis password, preferring the REDIS_PASSWORD environment variable
+func (r *RedisConfig) GetPassword() string {
+	if password := os.Getenv("REDIS_PASSWORD"); password != "" {
+		return password
+	}
+	return r.Password
+}
+
+// GetKeyPrefix returns the prefix of the cache keys
+func (r *RedisConfig) GetKeyPrefix() string {
+	if r.KeyPrefix == "" {
+		return "nvidia-monitor:"
+	}
+	return r.KeyPrefix
+}
+
+// GetTTL returns how long a cached response is kept
+func (r *RedisConfig) GetTTL() time.Duration {
+	if r.TTL == "" {
+		return 15 * time.Minute // default
+	}
+
+	duration, err := time.ParseDuration(r.TTL)
+	if err != nil || duration <= 0 {
+		return 15 * time.Minute // fallback to default
+	}
+
+	return duration
+}
+
+// GetTimeout returns the time allowed for a Redis round trip
+func (r *RedisConfig) GetTimeout() time.Duration {
+	if r.Timeout == "" {
+		return 200 * time.Millisecond // default
+	}
+
+	duration, err := time.ParseDuration(r.Timeout)
+	if err != nil || duration <= 0 {
+		return 200 * time.Millisecond // fallback to default
+	}
+
+	return duration
+}
+
+// GetPoolSize returns the number of idle connections kept open
+func (r *RedisConfig) GetPoolSize() int {
+	if r.PoolSize < 1 {
+		return 8
+	}
+	return r.PoolSize
+}
+
+// Validate requires an address when the cache is enabled
+func (r *RedisConfig) Validate() error {
+	if r.Enabled && r.Address == "" {
+		return fmt.Errorf("redis.address is required when redis is enabled")
+	}
+	if r.DB < 0 {
+		return fmt.Errorf("redis.db must not be negative")
+	}
+	return nil
+}
+
+// Startup probe policies, applied when an upstream does not answer at startup
+const (
+	ProbeFail     = "fail"     // Refuse to start
+	ProbeDegrade  = "degrade"  // Switch off the optional feature that needs the upstream
+	ProbeContinue = "continue" // Start anyway; the refreshes retry the upstream
+)
+
+// StartupConfig holds the probes of the configured upstreams run once before the service starts
+type StartupConfig struct {
+	Probes    bool              `json:"probes"`     // Probe the upstreams before starting
+	Timeout   string            `json:"timeout"`    // Time allowed per upstream, e.g. "5s"
+	OnFailure string            `json:"on_failure"` // Policy of the upstreams not listed in policies
+	Policies  map[string]string `json:"policies"`   // Policy per upstream name, e.g. {"launchpad": "fail"}
+}
+
+// GetTimeout returns the time allowed per upstream probe
+func (s *StartupConfig) GetTimeout() time.Duration {
+	if s.Timeout == "" {
+		return 5 * time.Second // default
+	}
+
+	duration, err := time.ParseDuration(s.Timeout)
+	if err != nil || duration <= 0 {
+		return 5 * time.Second // fallback to default
+	}
+
+	return duration
+}
+
+// Policy returns what to do when an upstream does not answer at startup
+func (s *StartupConfig) Policy(upstream string) string {
+	if policy, ok := s.Policies[upstream]; ok {
+		return policy
+	}
+	if s.OnFailure == "" {
+		return ProbeContinue
+	}
+	return s.OnFailure
+}
+
+// Validate rejects unknown probe policies
+func (s *StartupConfig) Validate() error {
+	valid := func(policy string) bool {
+		return policy == ProbeFail || policy == ProbeDegrade || policy == ProbeContinue
+	}
+	if s.OnFailure != "" && !valid(s.OnFailure) {
+		return fmt.Errorf("startup.on_failure must be fail, degrade or continue, got %q", s.OnFailure)
+	}
+	for upstream, policy := range s.Policies {
+		if !valid(policy) {
+			return fmt.Errorf("startup.policies[%q] must be fail, degrade or continue, got %q", upstream, policy)
+		}
+	}
+	return nil
+}
+
+// Disk caches pruned by the janitor
+const (
+	JanitorCacheDSC       = "dsc"       // DSC files downloaded by the L-R-M verifier
+	JanitorCacheChangelog = "changelog" // Changelog entries cached from Launchpad, in changelog.cache_dir
+	JanitorCacheFixtures  = "fixtures"  // Fixtures captured into testing.data_dir
+)
+
+// JanitorConfig holds the pruning of the disk caches
+type JanitorConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Interval string `json:"interval"` // Time between prunes, e.g. "1h"
+	// Caches holds the limits of each cache by name: "dsc", "changelog" or "fixtures". A cache
+	// without limits is only reported.
+	Caches map[string]CacheLimits `json:"caches"`
+}
+
+// CacheLimits bounds a disk cache
+type CacheLimits struct {
+	MaxAge    string `json:"max_age"`     // Files not written for this long are evicted, e.g. "168h"; empty keeps them
+	MaxSizeMB int    `json:"max_size_mb"` // The oldest files are evicted beyond this size; 0 for no limit
+}
+
+// GetInterval returns the time between prunes
+func (j *JanitorConfig) GetInterval() time.Duration {
+	if j.Interval == "" {
+		return time.Hour // default
+	}
+
+	duration, err := time.ParseDuration(j.Interval)
+	if err != nil || duration <= 0 {
+		return time.Hour // fallback to default
+	}
+
+	return duration
+}
+
+// GetMaxAge returns the age past which files are evicted, or 0 to keep them
+func (l CacheLimits) GetMaxAge() time.Duration {
+	duration, err := time.ParseDuration(l.MaxAge)
+	if err != nil || duration <= 0 {
+		return 0
+	}
+	return duration
+}
+
+// GetMaxBytes returns the size beyond which the oldest files are evicted, or 0 for no limit
+func (l CacheLimits) GetMaxBytes() int64 {
+	if l.MaxSizeMB <= 0 {
+		return 0
+	}
+	return int64(l.MaxSizeMB) << 20
+}
+
+// Validate rejects unknown caches and malformed limits
+func (j *JanitorConfig) Validate() error {
+	if j.Interval != "" {
+		if duration, err := time.ParseDuration(j.Interval); err != nil || duration <= 0 {
+			return fmt.Errorf("janitor.interval must be a positive duration, got %q", j.Interval)
+		}
+	}
+	for name, limits := range j.Caches {
+		if name != JanitorCacheDSC && name != JanitorCacheChangelog && name != JanitorCacheFixtures {
+			return fmt.Errorf("janitor.caches has unknown cache %q; expected dsc, changelog or fixtures", name)
+		}
+		if limits.MaxAge != "" {
+			if duration, err := time.ParseDuration(limits.MaxAge); err != nil || duration <= 0 {
+				return fmt.Errorf("janitor.caches[%q].max_age must be a positive duration, got %q", name, limits.MaxAge)
+			}
+		}
+		if limits.MaxSizeMB < 0 {
+			return fmt.Errorf("janitor.caches[%q].max_size_mb must not be negative", name)
+		}
+	}
+	return nil
+}
+
+// HistoryConfig holds the daily dashboard history store configuration
+type HistoryConfig struct {
+	DataFile      string `json:"data_file"`      // Where daily observations are persisted
+	RetentionDays int    `json:"retention_days"` // Days of observations kept; 0 means 730
+}
+
+// GetRetentionDays returns how many days of observations are kept, defaulting to two years
+func (h *HistoryConfig) GetRetentionDays() int {
+	if h.RetentionDays <= 0 {
+		return 730
+	}
+	return h.RetentionDays
+}
+
+// GetDataFile returns the history persistence file
+func (h *HistoryConfig) GetDataFile() string {
+	if h.DataFile == "" {
+		return "history_data.json"
+	}
+	return h.DataFile
+}
+
+// SLOConfig holds per-branch service level objectives
+type SLOConfig struct {
+	Targets []SLOTarget `json:"targets"`
+}
+
+// SLOTarget is the objective that a branch publishes upstream releases within MaxDays
+type SLOTarget struct {
+	Branch      string `json:"branch"`      // Branch name like "570" or "535-server"; "*" matches every branch
+	Description string `json:"description"` // e.g. "security updates published within 7 days of upstream"
+	MaxDays     int    `json:"max_days"`
+	WindowDays  int    `json:"window_days"` // Upstream releases older than this are not evaluated
+}
+
+// GetWindowDays returns the evaluation window, defaulting to 90 days
+func (t *SLOTarget) GetWindowDays() int {
+	if t.WindowDays < 1 {
+		return 90
+	}
+	return t.WindowDays
+}
+
+// TargetFor returns the SLO of a branch; an exact branch match takes precedence over "*"
+func (s *SLOConfig) TargetFor(branch string) *SLOTarget {
+	var wildcard *SLOTarget
+	for i := range s.Targets {
+		target := &s.Targets[i]
+		if target.MaxDays < 1 {
+			continue
+		}
+		if target.Branch == branch {
+			return target
+		}
+		if target.Branch == "*" && wildcard == nil {
+			wildcard = target
+		}
+	}
+	return wildcard
+}
+
+// AlertsConfig holds self-monitoring alert configuration
+type AlertsConfig struct {
+	WebhookURL         string              `json:"webhook_url"`         // Optional URL that receives alert state changes as JSON
+	StaleFactor        int                 `json:"stale_factor"`        // Data older than this many refresh intervals is stale
+	MaintenanceWindows []MaintenanceWindow `json:"maintenance_windows"` // Recurring windows during which alert delivery is silenced
+	CutoffRules        []CutoffRule        `json:"cutoff_rules"`        // Alerts on outdated branches approaching an SRU cutoff
+}
+
+// CutoffRule fires an alert DaysBefore days ahead of the SRU cutoff targeted by an outdated
+// branch that has not been uploaded to proposed yet
+type CutoffRule struct {
+	Branches   []string `json:"branches"`    // Branch names or patterns, e.g. ["570", "*-server"]; empty matches all
+	DaysBefore int      `json:"days_before"` // Days ahead of the cutoff the alert fires
+	Severity   string   `json:"severity"`    // "warning" (default) or "critical"
+}
+
+// IncludesBranch reports whether a driver branch (e.g. "570-server") is covered by the rule
+func (r *CutoffRule) IncludesBranch(branch string) bool {
+	return matchBranch(r.Branches, branch)
+}
+
+// GetSeverity returns the alert severity, defaulting to "warning"
+func (r *CutoffRule) GetSeverity() string {
+	if r.Severity == "critical" {
+		return r.Severity
+	}
+	return "warning"
+}
+
+// CutoffRuleFor returns the first cutoff rule covering a branch, or nil
+func (a *AlertsConfig) CutoffRuleFor(branch string) *CutoffRule {
+	for i := range a.CutoffRules {
+		rule := &a.CutoffRules[i]
+		if rule.DaysBefore > 0 && rule.IncludesBranch(branch) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// MaintenanceWindow is a recurring period of planned archive operations
+type MaintenanceWindow struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"` // Cron expression of the window start, e.g. "0 2 * * 6" (UTC)
+	Duration string `json:"duration"` // Duration string like "2h"
+	Reason   string `json:"reason"`
+}
+
+// GetDuration parses the window duration, defaulting to 1 hour
+func (m *MaintenanceWindow) GetDuration() time.Duration {
+	duration, err := time.ParseDuration(m.Duration)
+	if err != nil || duration <= 0 {
+		return time.Hour
+	}
+	return duration
+}
+
+// GetStaleFactor returns the stale data multiplier, defaulting to 3
+func (a *AlertsConfig) GetStaleFactor() int {
+	if a.StaleFactor < 1 {
+		return 3
+	}
+	return a.StaleFactor
+}
+
+// SecurityConfig controls the security headers of the web UI
+type SecurityConfig struct {
+	// ContentSecurityPolicy replaces the generated policy when set
+	ContentSecurityPolicy string `json:"content_security_policy"`
+}
+
+// GetContentSecurityPolicy returns the configured policy, or one that allows the pages' own
+// resources and the CDN origins of the assets
+func (s *SecurityConfig) GetContentSecurityPolicy(cdn CDNURLs) string {
+	if s.ContentSecurityPolicy != "" {
+		return s.ContentSecurityPolicy
+	}
+	sources := strings.Join(append([]string{"'self'"}, cdn.Origins()...), " ")
+	return "default-src 'self'; " +
+		"script-src " + sources + " 'unsafe-inline'; " +
+		"style-src " + sources + " 'unsafe-inline'; " +
+		"img-src 'self' data:; " +
+		"connect-src 'self'; " +
+		"font-src " + sources + "; " +
+		"object-src 'none'; " +
+		"base-uri 'self'; " +
+		"form-action 'self'"
+}
+
+// AuthConfig protects the web UI and API behind a login. Users must be in an admin or
+// read-only group; read-only users cannot make changes (POST, PUT, DELETE).
+type AuthConfig struct {
+	OIDC           OIDCConfig `json:"oidc"`
+	AdminGroups    []string   `json:"admin_groups"`
+	ReadOnlyGroups []string   `json:"read_only_groups"` // Groups granted read-only access; empty admits admins only
+	SessionTTL     string     `json:"session_ttl"`      // Duration string like "8h"
+	PublicPaths    []string   `json:"public_paths"`     // Paths served without login, e.g. health checks
+	AdminToken     string     `json:"admin_token"`      // Bearer token for admin API writes; env var ADMIN_TOKEN takes precedence
+}
+
+// GetAdminToken returns the bearer token accepted for admin API writes from env or config.
+// Env var ADMIN_TOKEN takes precedence.
+func (a *AuthConfig) GetAdminToken() string {
+	if token := os.Getenv("ADMIN_TOKEN"); token != "" {
+		return token
+	}
+	return a.AdminToken
+}
+
+// OIDCConfig holds the OpenID Connect provider settings for the authorization code flow
+type OIDCConfig struct {
+	Enabled      bool     `json:"enabled"`
+	IssuerURL    string   `json:"issuer_url"` // e.g. "https://login.example.com/realms/eng"
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"` // Env var OIDC_CLIENT_SECRET takes precedence
+	RedirectURL  string   `json:"redirect_url"`  // e.g. "https://monitor.example.com/auth/callback"
+	Scopes       []string `json:"scopes"`        // Requested in addition to "openid"
+	GroupsClaim  string   `json:"groups_claim"`  // ID token claim listing the user's groups
+}
+
+// GetClientSecret returns the OIDC client secret from env or config.
+// Env var OIDC_CLIENT_SECRET takes precedence.
+func (o *OIDCConfig) GetClientSecret() string {
+	if secret := os.Getenv("OIDC_CLIENT_SECRET"); secret != "" {
+		return secret
+	}
+	return o.ClientSecret
+}
+
+// GetScopes returns the requested scopes, always including "openid"
+func (o *OIDCConfig) GetScopes() []string {
+	scopes := []string{"openid"}
+	if len(o.Scopes) == 0 {
+		return append(scopes, "profile", "email", "groups")
+	}
+	for _, scope := range o.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// GetGroupsClaim returns the groups claim name, defaulting to "groups"
+func (o *OIDCConfig) GetGroupsClaim() string {
+	if o.GroupsClaim == "" {
+		return "groups"
+	}
+	return o.GroupsClaim
+}
+
+// GetSessionTTL parses the session lifetime, defaulting to 8 hours
+func (a *AuthConfig) GetSessionTTL() time.Duration {
+	ttl, err := time.ParseDuration(a.SessionTTL)
+	if err != nil || ttl <= 0 {
+		return 8 * time.Hour
+	}
+	return ttl
+}
+
+// GetPublicPaths returns the paths served without login
+func (a *AuthConfig) GetPublicPaths() []string {
+	if a.PublicPaths == nil {
+		return []string{"/api/health", "/api/ready", "/metrics"}
+	}
+	return a.PublicPaths
+}
+
+// ViewConfig is a named, team-scoped view of the dashboard (e.g. "desktop", "server")
+type ViewConfig struct {
+	Name                string   `json:"name"`
+	Title               string   `json:"title"`
+	Branches            []string `json:"branches"`              // Branch names or patterns, e.g. ["570", "*-server"]; empty matches all
+	Series              []string `json:"series"`                // Series shown in the view; empty shows all
+	Token               string   `json:"token"`                 // Optional token required to access the view
+	AlertOutdatedSeries int      `json:"alert_outdated_series"` // Fire an alert when this many series are outdated; 0 disables
+}
+
+// IncludesBranch reports whether a driver branch (e.g. "570-server") belongs to the view
+func (v *ViewConfig) IncludesBranch(branch string) bool {
+	return matchBranch(v.Branches, branch)
+}
+
+// matchBranch reports whether a branch matches one of the patterns; no patterns match all
+func matchBranch(patterns []string, branch string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, err := filepath.Match(pattern, branch); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// IncludesSeries reports whether a series is shown in the view
+func (v *ViewConfig) IncludesSeries(series string) bool {
+	if len(v.Series) == 0 {
+		return true
+	}
+	for _, s := range v.Series {
+		if s == series {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTitle returns the view title, defaulting to its name
+func (v *ViewConfig) GetTitle() string {
+	if v.Title == "" {
+		return v.Name
+	}
+	return v.Title
+}
+
+// View returns the named view, or nil when it is not configured
+func (c *Config) View(name string) *ViewConfig {
+	for i := range c.Views {
+		if c.Views[i].Name == name {
+			return &c.Views[i]
+		}
+	}
+	return nil
+}
+
+// Pages a preset applies to
+const (
+	PresetPageIndex = "index" // The dashboard, /
+	PresetPageLRM   = "lrm"   // The L-R-M verifier, /l-r-m-verifier
+)
+
+// PresetConfig is a named set of page filters, opened with ?view=<name> so a link always
+// reproduces the same view, e.g. "server-jammy-outdated"
+type PresetConfig struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	Page  string `json:"page"`  // "index" (default) or "lrm"
+	Query string `json:"query"` // Filters as a query string, e.g. "branch=*-server&series=jammy&status=outdated"
+}
+
+// GetPage returns the page of the preset, defaulting to the dashboard
+func (p *PresetConfig) GetPage() string {
+	if p.Page == "" {
+		return PresetPageIndex
+	}
+	return p.Page
+}
+
+// GetTitle returns the preset title, defaulting to its name
+func (p *PresetConfig) GetTitle() string {
+	if p.Title == "" {
+		return p.Name
+	}
+	return p.Title
+}
+
+// Values returns the filters of the preset
+func (p *PresetConfig) Values() url.Values {
+	values, _ := url.ParseQuery(p.Query)
+	return values
+}
+
+// Preset returns the named preset of a page, or nil when it is not configured
+func (c *Config) Preset(page, name string) *PresetConfig {
+	for i := range c.Presets {
+		if c.Presets[i].Name == name && c.Presets[i].GetPage() == page {
+			return &c.Presets[i]
+		}
+	}
+	return nil
+}
+
+// ValidatePresets checks the presets have a page, a parseable query and a name no view or
+// other preset of the page uses
+func (c *Config) ValidatePresets() error {
+	seen := make(map[string]bool)
+	for _, preset := range c.Presets {
+		if preset.Name == "" {
+			return fmt.Errorf("presets: every preset needs a name")
+		}
+		page := preset.GetPage()
+		if page != PresetPageIndex && page != PresetPageLRM {
+			return fmt.Errorf("presets[%s]: unknown page %q, expected %q or %q", preset.Name, preset.Page, PresetPageIndex, PresetPageLRM)
+		}
+		if _, err := url.ParseQuery(preset.Query); err != nil {
+			return fmt.Errorf("presets[%s]: invalid query: %v", preset.Name, err)
+		}
+		if seen[page+"/"+preset.Name] {
+			return fmt.Errorf("presets[%s]: name is used twice for page %s", preset.Name, page)
+		}
+		if page == PresetPageIndex && c.View(preset.Name) != nil {
+			return fmt.Errorf("presets[%s]: name is already used by a view", preset.Name)
+		}
+		seen[page+"/"+preset.Name] = true
+	}
+	return nil
+}
+
+// TestingConfig holds testing/mock service configuration
+type TestingConfig struct {
+	Enabled        bool   `json:"enabled"`
+	MockServerPort int    `json:"mock_server_port"`
+	DataDir        string `json:"data_dir"`
+	// Scenario pins the mock data set the upstream requests are served from, e.g. "proposed-stuck"
+	Scenario string `json:"scenario"`
+	// Faults are injected into the refresh pipeline at startup; more can be injected and
+	// cleared at runtime through /api/v1/testing/faults
+	Faults []FaultConfig `json:"faults"`
+}
+
+// FaultConfig makes a fetch stage fail or hang, to exercise the failure paths of the refresh
+type FaultConfig struct {
+	Stage string `json:"stage"` // "uda", "erd", "sru", "tegra", "packages" or "lrm"
+	Mode  string `json:"mode"`  // "fail" or "hang"
+}
+
+// ValidateFaults rejects faults outside testing mode and unknown stages or modes
+func (t *TestingConfig) ValidateFaults() error {
+	if len(t.Faults) > 0 && !t.Enabled {
+		return fmt.Errorf("testing.faults requires testing.enabled")
+	}
+	for _, fault := range t.Faults {
+		known := false
+		for _, stage := range faults.Stages() {
+			known = known || fault.Stage == stage
+		}
+		if !known {
+			return fmt.Errorf("testing.faults: unknown stage %q, expected one of %s", fault.Stage, strings.Join(faults.Stages(), ", "))
+		}
+		if fault.Mode != faults.ModeFail && fault.Mode != faults.ModeHang {
+			return fmt.Errorf("testing.faults: unknown mode %q for %s, expected %s or %s", fault.Mode, fault.Stage, faults.ModeFail, faults.ModeHang)
+		}
+	}
+	return nil
+}
+
+// GetTimeout parses and returns the timeout as time.Duration
+func (h *HTTPConfig) GetTimeout() time.Duration {
+	if h.Timeout == "" {
+		return 10 * time.Second // default
+	}
+
+	duration, err := time.ParseDuration(h.Timeout)
+	if err != nil {
+		return 10 * time.Second // fallback to default
+	}
+
+	return duration
+}
+
+// GetMaxConcurrency returns the configured worker count clamped to safe limits.
+func (p *ProcessingConfig) GetMaxConcurrency() int {
+	if p.MaxConcurrency < 1 {
+		return 10
+	}
+	if p.MaxConcurrency > 50 {
+		return 50
+	}
+	return p.MaxConcurrency
+}
+
+// GetForgejoToken returns the Forgejo token from env or config.
+// Env var KERNEL_FORGEJO_TOKEN takes precedence.
+func (h *HTTPConfig) GetForgejoToken() string {
+	if token := os.Getenv("KERNEL_FORGEJO_TOKEN"); token != "" {
+		return token
+	}
+	return h.ForgejoToken
+}
+
+// DefaultConfig returns the default configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:        8080,
+			HTTPSPort:   8443,
+			EnableHTTPS: false,
+		},
+		Cache: CacheConfig{
+			RefreshInterval:  "15m",
+			Enabled:          true,
+			SourceVersionTTL: "2m",
+			MaxBackoff:       "1h",
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerMinute: 60,
+			Enabled:           true,
+			Backend:           "memory",
+		},
+		RequestLimit: RequestLimitConfig{
+			MaxBodySize:    1048576, // 1MB
+			ReadTimeout:    "15s",
+			WriteTimeout:   "15s",
+			IdleTimeout:    "60s",
+			RequestTimeout: "30s",
+			MaxHeaderBytes: 1048576, // 1MB
+		},
+		URLs: URLConfig{
+			Ubuntu: UbuntuURLs{
+				AssetsBaseURL: "https://assets.ubuntu.com/v1",
+			},
+			Launchpad: LaunchpadURLs{
+				BaseURL:              "https://api.launchpad.net/devel",
+				PublishedSourcesAPI:  "https://api.launchpad.net/devel/ubuntu/+archive/primary",
+				PublishedBinariesAPI: "https://api.launchpad.net/devel/ubuntu/+archive/primary",
+				UbuntuSeriesBaseURL:  "https://api.launchpad.net/devel/ubuntu",
+				CreatedSinceDate:     "2026-01-01",
+			},
+			NVIDIA: NVIDIAURLs{
+				DriverArchiveURL: "https://download.nvidia.com/XFree86/Linux-x86_64/",
+				ServerDriversAPI: "https://docs.nvidia.com/datacenter/tesla/drivers/releases.json",
+			},
+			CDN: CDNURLs{
+				BootstrapCSS: "https://cdn.jsdelivr.net/npm/bootstrap@5.1.3/dist/css/bootstrap.min.css",
+				BootstrapJS:  "https://cdn.jsdelivr.net/npm/bootstrap@5.1.3/dist/js/bootstrap.bundle.min.js",
+				ChartJS:      "https://cdn.jsdelivr.net/npm/chart.js@3.9.1/dist/chart.min.js",
+				MermaidJS:    "https://cdn.jsdelivr.net/npm/mermaid@10.9.1/dist/mermaid.min.js",
+				VanillaCSS:   "https://assets.ubuntu.com/v1/vanilla-framework-version-4.15.0.min.css",
+				// Published by Bootstrap for 5.1.3; the other assets are not pinned by default
+				BootstrapCSSIntegrity: "sha384-1BmE4kWBq78iYhFldvKuhfTAU6auU8tT94WrHftjDbrCEXSU1oBoqyl2QvZ6jIW3",
+				BootstrapJSIntegrity:  "sha384-ka7Sk0Gln4gmtz2MlQnikT1wXgYsOg+OMhuP+IlRH9sENBO0LRn5q+8nbTov4+1p",
+			},
+			Kernel: KernelURLs{
+				SeriesYAMLURL:     "https://kernel.ubuntu.com/forgejo/kernel/kernel-versions/raw/branch/main/info/kernel-series.yaml",
+				SRUCycleURL:       "https://kernel.ubuntu.com/forgejo/kernel/kernel-versions/raw/branch/main/info/sru-cycle.yaml",
+				SeriesYAMLMirrors: []string{},
+				SRUCycleMirrors:   []string{},
+			},
+		},
+		HTTP: HTTPConfig{
+			Timeout:   "30s",
+			Retries:   5,
+			UserAgent: "nvidia-driver-monitor/1.0",
+		},
+		Processing: ProcessingConfig{
+			MaxConcurrency: 10,
+		},
+		Fleet: FleetConfig{
+			DataFile:         "fleet_data.json",
+			StaleAfter:       "24h",
+			GPUInventoryFile: "gpu_inventory_data.json",
+			GPUBranchesFile:  "data/gpu-branches.json",
+		},
+		Pockets: PocketsConfig{
+			Published: []string{"Updates", "Security", "Release"},
+		},
+		Series: SeriesConfig{
+			Order:    []string{"resolute", "noble", "jammy", "focal", "bionic"},
+			Devel:    "",
+			EOL:      map[string]string{},
+			EOLFile:  "/usr/share/distro-info/ubuntu.csv",
+			EOLGrace: "720h",
+		},
+		Changelog: ChangelogConfig{
+			Enabled:       false,
+			CacheDir:      "changelog_cache",
+			NotifyUpdates: false,
+		},
+		Queue: QueueConfig{
+			Enabled: false,
+		},
+		Phasing: PhasingConfig{
+			Enabled: false,
+		},
+		History: HistoryConfig{
+			DataFile:      "history_data.json",
+			RetentionDays: 730,
+		},
+		DKMS: DKMSConfig{
+			CompatFile: "data/dkms-compat.json",
+		},
+		Advisories: AdvisoriesConfig{
+			DataFile: "advisories_data.json",
+		},
+		Notes: NotesConfig{
+			DataFile: "notes_data.json",
+		},
+		Acks: AcksConfig{
+			DataFile: "acknowledgements_data.json",
+		},
+		I18n: I18nConfig{
+			DefaultLocale:   "en",
+			DefaultTimezone: "UTC",
+		},
+		Stats: StatsConfig{
+			DataFile:     "statistics_data.json",
+			Window:       "10m",
+			Retention:    100,
+			SaveInterval: "5m",
+		},
+		Budget: BudgetConfig{
+			DataFile: "budget_data.json",
+			Daily:    map[string]int64{},
+			WarnAt:   []int{80, 95},
+		},
+		Tegra: TegraConfig{
+			Enabled:       false,
+			ReleasesURL:   "https://repo.download.nvidia.com/jetson/common/dists/",
+			PackagePrefix: "nvidia-tegra-drivers-",
+		},
+		Releases: ReleasesConfig{
+			Profile: "",
+		},
+		ArchiveCheck: ArchiveCheckConfig{
+			Enabled:    false,
+			Interval:   "24h",
+			MirrorURL:  "http://archive.ubuntu.com/ubuntu",
+			Components: []string{"main", "restricted", "universe", "multiverse"},
+		},
+		ReleaseCheck: ReleaseCheckConfig{
+			Enabled:    false,
+			Interval:   "1h",
+			MirrorURL:  "http://archive.ubuntu.com/ubuntu",
+			Components: []string{"restricted", "multiverse"},
+			MaxAge:     "48h",
+			Keyring:    "/usr/share/keyrings/ubuntu-archive-keyring.gpg",
+		},
+		Discovery: DiscoveryConfig{
+			Enabled:  false,
+			Interval: "24h",
+			Search:   "nvidia",
+			Patterns: []string{"nvidia-*", "libnvidia-*"},
+			Ignore:   []string{"nvidia-settings", "nvidia-prime", "nvidia-modprobe", "nvidia-persistenced", "nvidia-xconfig", "nvidia-cuda-toolkit"},
+		},
+		I386: I386Config{
+			Enabled:   true,
+			Interval:  "6h",
+			Libraries: []string{"libnvidia-gl"},
+		},
+		CloudImages: CloudImagesConfig{
+			Enabled:       false,
+			Interval:      "24h",
+			BaseURL:       "https://cloud-images.ubuntu.com",
+			Variants:      []string{"server"},
+			Architectures: []string{"amd64"},
+		},
+		Seeds: SeedsConfig{
+			Enabled:  false,
+			Interval: "24h",
+			BaseURL:  "https://git.launchpad.net/~ubuntu-core-dev/ubuntu-seeds/+git/platform/plain",
+		},
+		Certification: CertificationConfig{
+			Enabled:    false,
+			Interval:   "24h",
+			BranchEOL:  map[string]string{},
+			EOLWarning: "2160h",
+		},
+		IssueTracker: IssueTrackerConfig{
+			Enabled:       false,
+			Kind:          "github",
+			ThresholdDays: 14,
+			Interval:      "1h",
+			DataFile:      "tracker_issues.json",
+		},
+		LRMReport: LRMReportConfig{
+			Enabled:           false,
+			DaysBeforeRelease: 2,
+			DataFile:          "lrm_reports.json",
+		},
+		Alerts: AlertsConfig{
+			StaleFactor: 3,
+		},
+		Peer: PeerConfig{
+			Timeout: "30s",
+			MaxAge:  "1h",
+		},
+		Jobs: JobsConfig{
+			Enabled:    false,
+			Dir:        "jobs",
+			Retention:  "24h",
+			MaxRunning: 2,
+		},
+		Startup: StartupConfig{
+			Timeout:   "5s",
+			OnFailure: ProbeContinue,
+		},
+		Janitor: JanitorConfig{
+			Enabled:  true,
+			Interval: "1h",
+			Caches: map[string]CacheLimits{
+				JanitorCacheDSC:       {MaxAge: "168h", MaxSizeMB: 512},
+				JanitorCacheChangelog: {MaxAge: "2160h", MaxSizeMB: 64},
+			},
+		},
+		Redis: RedisConfig{
+			KeyPrefix: "nvidia-monitor:",
+			TTL:       "15m",
+			Timeout:   "200ms",
+			PoolSize:  8,
+		},
+		Auth: AuthConfig{
+			OIDC: OIDCConfig{
+				Enabled:     false,
+				Scopes:      []string{"profile", "email", "groups"},
+				GroupsClaim: "groups",
+			},
+			SessionTTL:  "8h",
+			PublicPaths: []string{"/api/health", "/api/ready", "/metrics"},
+		},
+		Testing: TestingConfig{
+			Enabled:        false,
+			MockServerPort: 9999,
+			DataDir:        "test-data",
+		},
+		Debug: DebugConfig{
+			DefaultDuration:   "15m",
+			MaxDuration:       "4h",
+			MaxLinesPerMinute: 600,
+		},
+	}
+}
+
+// LoadConfig loads configuration from a file
+func LoadConfig(configPath string) (*Config, error) {
+	config := DefaultConfig()
+
+	if configPath == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil // Use defaults if file doesn't exist
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := config.Stats.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.Budget.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.URLs.CDN.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.I18n.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.Discovery.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.ValidatePresets(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.Redis.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.ValidateRateLimit(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.Startup.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.Certification.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.RequestLimit.ValidateRequestLimits(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.LRMReport.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.ValidateJobs(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.Janitor.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.Testing.ValidateFaults(); err != nil {
+		return nil, fmt.Errorf("invalid config file: %w", err)
 	}
 
 	return config, nil
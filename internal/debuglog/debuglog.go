@@ -0,0 +1,276 @@
+// Package debuglog holds the debug logging switched on at runtime through /api/v1/debug. Debug
+// lines of a module are only written while the module is enabled, at most a configured number
+// per minute, and debugging switches itself off after the requested duration.
+package debuglog
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// Modules writing debug lines
+const (
+	ModuleHTTP   = "http"   // Every upstream request with its status and duration
+	ModuleLRM    = "lrm"    // kernel-series.yaml and DSC parsing traces of the L-R-M verifier
+	ModuleSchema = "schema" // API responses that do not match their published JSON Schema
+	ModuleAll    = "all"    // Enables every module
+)
+
+var modules = []string{ModuleHTTP, ModuleLRM, ModuleSchema}
+
+// Modules returns the modules that can be enabled, "all" aside
+func Modules() []string {
+	return append([]string(nil), modules...)
+}
+
+// Status is the debug logging state shown by /api/v1/debug
+type Status struct {
+	Modules           []string   `json:"modules"`         // Enabled modules; empty while debugging is off
+	Until             *time.Time `json:"until,omitempty"` // When debugging switches itself off
+	MaxLinesPerMinute int        `json:"max_lines_per_minute"`
+	Suppressed        int64      `json:"suppressed"` // Lines dropped by the rate limit since debugging was switched on
+	Available         []string   `json:"available"`
+}
+
+// Switch holds the enabled modules and rate limits their lines
+type Switch struct {
+	active atomic.Bool // Fast path for the common case of debugging being off
+
+	mu           sync.Mutex
+	enabled      map[string]bool
+	until        time.Time
+	timer        *time.Timer
+	generation   int // Bumped on every change so a stale timer does not switch off a newer request
+	maxPerMinute int
+	window       time.Time // Start of the current rate limit minute
+	lines        int       // Lines written in the current window
+	dropped      int       // Lines dropped in the current window
+	suppressed   int64
+	now          func() time.Time
+	output       func(string)
+}
+
+// NewSwitch creates a switch with debugging off
+func NewSwitch(maxPerMinute int) *Switch {
+	return &Switch{
+		enabled:      make(map[string]bool),
+		maxPerMinute: maxPerMinute,
+		now:          time.Now,
+		output:       func(line string) { log.Print(line) },
+	}
+}
+
+var global = NewSwitch(600)
+
+// SetDebugConfig applies the configured rate limit to the global switch
+func SetDebugConfig(cfg *config.Config) {
+	if cfg == nil {
+		return
+	}
+	global.mu.Lock()
+	global.maxPerMinute = cfg.Debug.GetMaxLinesPerMinute()
+	global.mu.Unlock()
+}
+
+// Enable switches on the global debug logging of modules for duration
+func Enable(names []string, duration time.Duration) (Status, error) {
+	return global.Enable(names, duration)
+}
+
+// Disable switches off the global debug logging
+func Disable() Status {
+	return global.Disable()
+}
+
+// CurrentStatus returns the state of the global debug logging
+func CurrentStatus() Status {
+	return global.Status()
+}
+
+// Enabled reports whether the global debug logging of a module is on, for callers that need to
+// do work before logging
+func Enabled(module string) bool {
+	return global.Enabled(module)
+}
+
+// Printf writes a debug line of a module through the global switch
+func Printf(module, format string, args ...interface{}) {
+	global.Printf(module, format, args...)
+}
+
+// Enable switches on the debug logging of modules for duration, replacing the modules enabled
+// before
+func (s *Switch) Enable(names []string, duration time.Duration) (Status, error) {
+	if duration <= 0 {
+		return Status{}, fmt.Errorf("duration must be positive")
+	}
+	enabled := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == ModuleAll:
+			for _, module := range modules {
+				enabled[module] = true
+			}
+		case contains(modules, name):
+			enabled[name] = true
+		default:
+			return Status{}, fmt.Errorf("unknown module %q, expected one of %s or %s", name, strings.Join(modules, ", "), ModuleAll)
+		}
+	}
+	if len(enabled) == 0 {
+		return Status{}, fmt.Errorf("no module given")
+	}
+
+	s.mu.Lock()
+	s.enabled = enabled
+	s.until = s.now().Add(duration)
+	s.suppressed = 0
+	s.generation++
+	generation := s.generation
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(duration, func() { s.expire(generation) })
+	s.active.Store(true)
+	status := s.statusLocked()
+	s.mu.Unlock()
+
+	log.Printf("Debug logging of %s enabled until %s", strings.Join(status.Modules, ", "), status.Until.Format(time.RFC3339))
+	return status, nil
+}
+
+// Disable switches off the debug logging of every module
+func (s *Switch) Disable() Status {
+	s.mu.Lock()
+	wasOn := len(s.enabled) > 0
+	s.disableLocked()
+	status := s.statusLocked()
+	s.mu.Unlock()
+
+	if wasOn {
+		log.Printf("Debug logging disabled")
+	}
+	return status
+}
+
+// expire switches debugging off once its duration is over, unless it was changed since
+func (s *Switch) expire(generation int) {
+	s.mu.Lock()
+	if generation != s.generation || len(s.enabled) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	suppressed := s.suppressed
+	s.disableLocked()
+	s.mu.Unlock()
+
+	log.Printf("Debug logging switched off after its duration; %d lines were dropped by the rate limit", suppressed)
+}
+
+func (s *Switch) disableLocked() {
+	s.enabled = make(map[string]bool)
+	s.until = time.Time{}
+	s.generation++
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.active.Store(false)
+}
+
+// Status returns the enabled modules and the lines dropped so far
+func (s *Switch) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statusLocked()
+}
+
+func (s *Switch) statusLocked() Status {
+	status := Status{
+		Modules:           []string{},
+		MaxLinesPerMinute: s.maxPerMinute,
+		Suppressed:        s.suppressed,
+		Available:         Modules(),
+	}
+	if s.enabledLocked("", s.now()) {
+		for module := range s.enabled {
+			status.Modules = append(status.Modules, module)
+		}
+		sort.Strings(status.Modules)
+		until := s.until
+		status.Until = &until
+	}
+	return status
+}
+
+// Enabled reports whether the debug logging of a module is on
+func (s *Switch) Enabled(module string) bool {
+	if !s.active.Load() {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enabledLocked(module, s.now())
+}
+
+// enabledLocked reports whether a module is on, or any module when module is empty. The
+// duration is checked here too, as the timer switching debugging off may not have fired yet.
+func (s *Switch) enabledLocked(module string, now time.Time) bool {
+	if len(s.enabled) == 0 || !now.Before(s.until) {
+		return false
+	}
+	return module == "" || s.enabled[module]
+}
+
+// Printf writes a debug line of a module while it is enabled. Lines over the rate limit are
+// dropped and counted; the count is written when the next minute starts.
+func (s *Switch) Printf(module, format string, args ...interface{}) {
+	if !s.active.Load() {
+		return
+	}
+
+	s.mu.Lock()
+	now := s.now()
+	if !s.enabledLocked(module, now) {
+		s.mu.Unlock()
+		return
+	}
+	var dropped string
+	if now.Sub(s.window) >= time.Minute {
+		if s.dropped > 0 {
+			dropped = fmt.Sprintf("[debug] dropped %d lines over the limit of %d per minute", s.dropped, s.maxPerMinute)
+		}
+		s.window, s.lines, s.dropped = now, 0, 0
+	}
+	if s.lines >= s.maxPerMinute {
+		s.dropped++
+		s.suppressed++
+		s.mu.Unlock()
+		return
+	}
+	s.lines++
+	output := s.output
+	s.mu.Unlock()
+
+	if dropped != "" {
+		output(dropped)
+	}
+	output(fmt.Sprintf("[debug %s] ", module) + fmt.Sprintf(format, args...))
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package debuglog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSwitch(maxPerMinute int, now *time.Time) (*Switch, *[]string) {
+	var lines []string
+	s := NewSwitch(maxPerMinute)
+	s.now = func() time.Time { return *now }
+	s.output = func(line string) { lines = append(lines, line) }
+	return s, &lines
+}
+
+func TestSwitchModulesAndExpiry(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	s, lines := newTestSwitch(100, &now)
+
+	s.Printf(ModuleLRM, "before enabling")
+	if len(*lines) != 0 || s.Enabled(ModuleLRM) {
+		t.Fatalf("lines = %v, expected nothing while debugging is off", *lines)
+	}
+
+	if _, err := s.Enable([]string{"dsc"}, time.Hour); err == nil {
+		t.Error("Enable() accepted an unknown module")
+	}
+	status, err := s.Enable([]string{ModuleLRM}, 30*time.Minute)
+	if err != nil {
+		t.Fatalf("Enable() failed: %v", err)
+	}
+	if len(status.Modules) != 1 || status.Modules[0] != ModuleLRM || status.Until == nil || !status.Until.Equal(now.Add(30*time.Minute)) {
+		t.Errorf("status = %+v, expected lrm enabled for 30 minutes", status)
+	}
+
+	s.Printf(ModuleLRM, "parsed %d lines", 3)
+	s.Printf(ModuleHTTP, "not enabled")
+	if len(*lines) != 1 || (*lines)[0] != "[debug lrm] parsed 3 lines" {
+		t.Errorf("lines = %v, expected only the lrm line", *lines)
+	}
+
+	// Past the duration nothing is written, even before the timer fires
+	now = now.Add(31 * time.Minute)
+	s.Printf(ModuleLRM, "after expiry")
+	if len(*lines) != 1 || len(s.Status().Modules) != 0 {
+		t.Errorf("lines = %v, status = %+v, expected debugging off after its duration", *lines, s.Status())
+	}
+
+	status, err = s.Enable([]string{ModuleAll}, time.Minute)
+	if err != nil || len(status.Modules) != len(modules) {
+		t.Errorf("Enable(all) = %+v, %v, expected every module", status, err)
+	}
+	if status := s.Disable(); len(status.Modules) != 0 || status.Until != nil || s.Enabled(ModuleHTTP) {
+		t.Errorf("Disable() = %+v, expected debugging off", status)
+	}
+}
+
+func TestSwitchRateLimit(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	s, lines := newTestSwitch(2, &now)
+	if _, err := s.Enable([]string{ModuleHTTP}, time.Hour); err != nil {
+		t.Fatalf("Enable() failed: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		s.Printf(ModuleHTTP, "request %d", i)
+	}
+	if len(*lines) != 2 || s.Status().Suppressed != 3 {
+		t.Errorf("lines = %v, suppressed = %d, expected 2 lines and 3 dropped", *lines, s.Status().Suppressed)
+	}
+
+	// The next minute reports the dropped lines before writing again
+	now = now.Add(time.Minute)
+	s.Printf(ModuleHTTP, "request 5")
+	if len(*lines) != 4 || !strings.Contains((*lines)[2], "dropped 3 lines") || (*lines)[3] != "[debug http] request 5" {
+		t.Errorf("lines = %v, expected the dropped count followed by the new line", *lines)
+	}
+	s.Disable()
+}
@@ -0,0 +1,114 @@
+// Package discovery scans the archive for NVIDIA related source packages the monitor does not
+// track, so new packaging such as a new driver branch is noticed when it is first published.
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/pkg/debversion"
+)
+
+// MaxPageSize is the largest page Launchpad returns
+const MaxPageSize = 300
+
+// Package is an untracked source package published in the archive
+type Package struct {
+	Name      string   `json:"name"`
+	Series    []string `json:"series"`  // Series it is published in, in display order
+	Version   string   `json:"version"` // Newest published version
+	Component string   `json:"component"`
+}
+
+// Matches reports whether a source name matches any of the globs
+func Matches(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan pages through the published sources whose name contains search and returns those
+// matching patterns that are neither tracked nor ignored, limited to the given series. It
+// returns the packages ordered by name and the number of requests made.
+func Scan(get func(string) (*http.Response, error), sourcesAPI, search string, patterns, ignore []string, tracked map[string]bool, series []string) ([]Package, int, error) {
+	shown := make(map[string]bool, len(series))
+	for _, name := range series {
+		shown[name] = true
+	}
+
+	next := fmt.Sprintf("%s/?ws.op=getPublishedSources&source_name=%s&exact_match=false&status=Published&ws.size=%d",
+		sourcesAPI, url.QueryEscape(search), MaxPageSize)
+	found := make(map[string]*Package)
+	requests := 0
+	for next != "" {
+		requests++
+		page, err := fetchPage(get, next)
+		if err != nil {
+			return nil, requests, fmt.Errorf("failed to search the published sources for %q: %w", search, err)
+		}
+		for _, entry := range page.Entries {
+			name := entry.SourcePackageName
+			codename := packages.SeriesFromDistroSeriesLink(entry.DistroSeriesLink)
+			if entry.Status != "Published" || !shown[codename] || tracked[name] ||
+				!Matches(name, patterns) || Matches(name, ignore) {
+				continue
+			}
+			pkg, ok := found[name]
+			if !ok {
+				pkg = &Package{Name: name}
+				found[name] = pkg
+			}
+			if !contains(pkg.Series, codename) {
+				pkg.Series = append(pkg.Series, codename)
+			}
+			if pkg.Version == "" || debversion.OlderThan(pkg.Version, entry.SourcePackageVersion) {
+				pkg.Version = entry.SourcePackageVersion
+				pkg.Component = entry.ComponentName
+			}
+		}
+		next = page.NextCollectionLink
+	}
+
+	result := make([]Package, 0, len(found))
+	for _, pkg := range found {
+		pkg.Series = packages.SortSeries(pkg.Series)
+		result = append(result, *pkg)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, requests, nil
+}
+
+// fetchPage requests one page of a Launchpad collection
+func fetchPage(get func(string) (*http.Response, error), url string) (*packages.SourceAPIResponse, error) {
+	resp, err := get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	var page packages.SourceAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return &page, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package discovery
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		series := func(name string) string { return server.URL + "/ubuntu/" + name }
+		if r.URL.Query().Get("ws.start") == "" {
+			if r.URL.Query().Get("source_name") != "nvidia" || r.URL.Query().Get("exact_match") != "false" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, `{"entries": [
+				{"source_package_name": "nvidia-graphics-drivers-570", "source_package_version": "570.195.03-0ubuntu1", "distro_series_link": %q, "status": "Published", "component_name": "restricted"},
+				{"source_package_name": "nvidia-vaapi-driver", "source_package_version": "0.0.11-1", "distro_series_link": %q, "status": "Published", "component_name": "universe"},
+				{"source_package_name": "nvidia-settings", "source_package_version": "570.144-1", "distro_series_link": %q, "status": "Published", "component_name": "restricted"},
+				{"source_package_name": "libnvidia-container", "source_package_version": "1.17.0-1", "distro_series_link": %q, "status": "Published", "component_name": "multiverse"}
+			], "next_collection_link": %q}`, series("noble"), series("jammy"), series("noble"), series("xenial"), server.URL+"/?ws.start=300")
+			return
+		}
+		fmt.Fprintf(w, `{"entries": [
+			{"source_package_name": "nvidia-vaapi-driver", "source_package_version": "0.0.12-1", "distro_series_link": %q, "status": "Published", "component_name": "universe"},
+			{"source_package_name": "nvidia-graphics-drivers-590", "source_package_version": "590.44.01-0ubuntu1", "distro_series_link": %q, "status": "Published", "component_name": "restricted"},
+			{"source_package_name": "xserver-xorg-video-nvidia", "source_package_version": "1.0-1", "distro_series_link": %q, "status": "Published", "component_name": "main"}
+		]}`, series("noble"), series("noble"), series("noble"))
+	}))
+	defer server.Close()
+
+	tracked := map[string]bool{"nvidia-graphics-drivers-570": true}
+	found, requests, err := Scan(http.Get, server.URL, "nvidia", []string{"nvidia-*", "libnvidia-*"}, []string{"nvidia-settings"}, tracked, []string{"noble", "jammy"})
+	if err != nil {
+		t.Fatalf("Scan() failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, expected both pages", requests)
+	}
+	if len(found) != 2 {
+		t.Fatalf("found = %+v, expected the 590 branch and the VA-API driver", found)
+	}
+	if found[0].Name != "nvidia-graphics-drivers-590" || found[1].Name != "nvidia-vaapi-driver" {
+		t.Errorf("found = %+v, expected the packages ordered by name", found)
+	}
+	if vaapi := found[1]; vaapi.Version != "0.0.12-1" || len(vaapi.Series) != 2 || vaapi.Series[0] != "noble" {
+		t.Errorf("nvidia-vaapi-driver = %+v, expected the newest version in noble and jammy", vaapi)
+	}
+}
@@ -0,0 +1,233 @@
+package dkms
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Compatibility status of a driver branch against a kernel
+const (
+	StatusOK       = "ok"
+	StatusAboveMax = "kernel-too-new"
+	StatusBelowMin = "kernel-too-old"
+	StatusUnknown  = "unknown"
+)
+
+// KernelRange is the kernel version range a driver branch's DKMS module builds against.
+// Curated values take precedence over the ones read from the package's dkms.conf.
+type KernelRange struct {
+	Min         string `json:"min_kernel,omitempty"` // e.g. "3.10"; empty means no lower bound
+	Max         string `json:"max_kernel,omitempty"` // e.g. "6.7"; empty means no upper bound
+	Note        string `json:"note,omitempty"`
+	DKMSConfURL string `json:"dkms_conf_url,omitempty"` // debian/dkms.conf with BUILD_EXCLUSIVE_KERNEL_MIN/MAX
+}
+
+// Kernel is a kernel source published in a series
+type Kernel struct {
+	Series  string // Codename, e.g. "noble"
+	Source  string // e.g. "linux-hwe-6.8"
+	Version string // Source package version, e.g. "6.8.0-45.45~22.04.1"
+}
+
+// Finding is one cell of the compatibility matrix: a driver branch against a kernel of a series
+type Finding struct {
+	Branch        string `json:"branch"`
+	Series        string `json:"series"`
+	KernelSource  string `json:"kernel_source"`
+	KernelVersion string `json:"kernel_version"`
+	Kernel        string `json:"kernel"` // Upstream kernel version compared, e.g. "6.8"
+	MinKernel     string `json:"min_kernel,omitempty"`
+	MaxKernel     string `json:"max_kernel,omitempty"`
+	Note          string `json:"note,omitempty"`
+	Status        string `json:"status"`
+	Flagged       bool   `json:"flagged"`
+}
+
+// LoadCompat reads the curated branch -> kernel range JSON file and completes entries that
+// point at a dkms.conf. A dkms.conf that cannot be fetched only leaves its entry unbounded.
+func LoadCompat(path string) (map[string]KernelRange, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DKMS compatibility %s: %w", path, err)
+	}
+	ranges, err := ParseCompat(data)
+	if err != nil {
+		return nil, err
+	}
+	for branch, kernelRange := range ranges {
+		if kernelRange.DKMSConfURL == "" || (kernelRange.Min != "" && kernelRange.Max != "") {
+			continue
+		}
+		fetched, err := fetchDKMSConf(kernelRange.DKMSConfURL)
+		if err != nil {
+			continue
+		}
+		ranges[branch] = mergeRange(kernelRange, fetched)
+	}
+	return ranges, nil
+}
+
+// ParseCompat decodes a branch -> kernel range JSON object
+func ParseCompat(data []byte) (map[string]KernelRange, error) {
+	var ranges map[string]KernelRange
+	if err := json.Unmarshal(data, &ranges); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal DKMS compatibility: %w", err)
+	}
+	return ranges, nil
+}
+
+// fetchDKMSConf downloads and parses a dkms.conf
+func fetchDKMSConf(url string) (KernelRange, error) {
+	resp, err := utils.HTTPGetWithRetry(url)
+	if err != nil {
+		return KernelRange{}, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return KernelRange{}, fmt.Errorf("unexpected status code fetching %s: %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return KernelRange{}, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return ParseDKMSConf(data), nil
+}
+
+// ParseDKMSConf reads the BUILD_EXCLUSIVE_KERNEL_MIN and BUILD_EXCLUSIVE_KERNEL_MAX variables
+// of a dkms.conf
+func ParseDKMSConf(data []byte) KernelRange {
+	var kernelRange KernelRange
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "BUILD_EXCLUSIVE_KERNEL_MIN":
+			kernelRange.Min = value
+		case "BUILD_EXCLUSIVE_KERNEL_MAX":
+			kernelRange.Max = value
+		}
+	}
+	return kernelRange
+}
+
+// mergeRange fills the bounds missing from the curated range
+func mergeRange(curated, fetched KernelRange) KernelRange {
+	if curated.Min == "" {
+		curated.Min = fetched.Min
+	}
+	if curated.Max == "" {
+		curated.Max = fetched.Max
+	}
+	return curated
+}
+
+// KernelVersion returns the upstream major.minor of a kernel source version
+// ("6.8.0-45.45~22.04.1" -> "6.8"), or "" when it cannot be parsed
+func KernelVersion(sourceVersion string) string {
+	parts := strings.SplitN(sourceVersion, ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	minor := strings.TrimRightFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' })
+	if _, err := strconv.Atoi(parts[0]); err != nil {
+		return ""
+	}
+	if _, err := strconv.Atoi(minor); err != nil {
+		return ""
+	}
+	return parts[0] + "." + minor
+}
+
+// compareKernel compares dotted kernel versions numerically; missing components count as 0
+func compareKernel(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Status checks an upstream kernel version (e.g. "6.8") against the range. A maximum of
+// "6.7" accepts every 6.7.x kernel.
+func (r KernelRange) Status(kernel string) string {
+	if kernel == "" || (r.Min == "" && r.Max == "") {
+		return StatusUnknown
+	}
+	if r.Max != "" && compareKernel(truncate(kernel, r.Max), r.Max) > 0 {
+		return StatusAboveMax
+	}
+	if r.Min != "" && compareKernel(kernel, truncate(r.Min, kernel)) < 0 {
+		return StatusBelowMin
+	}
+	return StatusOK
+}
+
+// truncate cuts version to as many components as like has
+func truncate(version, like string) string {
+	parts := strings.Split(version, ".")
+	if n := strings.Count(like, ".") + 1; len(parts) > n {
+		parts = parts[:n]
+	}
+	return strings.Join(parts, ".")
+}
+
+// Matrix checks every driver branch published in a series against the kernels of that series.
+// branches maps a series to the driver branches published in it (e.g. "470", "535-server").
+func Matrix(ranges map[string]KernelRange, kernels []Kernel, branches map[string][]string) []Finding {
+	var findings []Finding
+	for _, kernel := range kernels {
+		upstream := KernelVersion(kernel.Version)
+		for _, branch := range branches[kernel.Series] {
+			kernelRange := ranges[branch]
+			status := kernelRange.Status(upstream)
+			findings = append(findings, Finding{
+				Branch:        branch,
+				Series:        kernel.Series,
+				KernelSource:  kernel.Source,
+				KernelVersion: kernel.Version,
+				Kernel:        upstream,
+				MinKernel:     kernelRange.Min,
+				MaxKernel:     kernelRange.Max,
+				Note:          kernelRange.Note,
+				Status:        status,
+				Flagged:       status == StatusAboveMax || status == StatusBelowMin,
+			})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Branch != findings[j].Branch {
+			return findings[i].Branch < findings[j].Branch
+		}
+		if findings[i].Series != findings[j].Series {
+			return findings[i].Series < findings[j].Series
+		}
+		return findings[i].KernelSource < findings[j].KernelSource
+	})
+	return findings
+}
@@ -0,0 +1,86 @@
+package dkms
+
+import "testing"
+
+func TestKernelVersion(t *testing.T) {
+	for _, test := range []struct {
+		source   string
+		expected string
+	}{
+		{"6.8.0-45.45~22.04.1", "6.8"},
+		{"5.15.0-119.129", "5.15"},
+		{"6.11.0-8.8", "6.11"},
+		{"N/A", ""},
+		{"", ""},
+	} {
+		if got := KernelVersion(test.source); got != test.expected {
+			t.Errorf("KernelVersion(%s) = %s, expected %s", test.source, got, test.expected)
+		}
+	}
+}
+
+func TestKernelRangeStatus(t *testing.T) {
+	for _, test := range []struct {
+		kernelRange KernelRange
+		kernel      string
+		expected    string
+	}{
+		{KernelRange{Max: "6.7"}, "6.8", StatusAboveMax},
+		{KernelRange{Max: "6.7"}, "6.7", StatusOK},
+		{KernelRange{Max: "6.7.12"}, "6.7", StatusOK},
+		{KernelRange{Min: "5.4", Max: "6.11"}, "4.15", StatusBelowMin},
+		{KernelRange{Min: "5.4.0"}, "5.4", StatusOK},
+		{KernelRange{}, "6.8", StatusUnknown},
+		{KernelRange{Max: "6.7"}, "", StatusUnknown},
+	} {
+		if got := test.kernelRange.Status(test.kernel); got != test.expected {
+			t.Errorf("%+v.Status(%s) = %s, expected %s", test.kernelRange, test.kernel, got, test.expected)
+		}
+	}
+}
+
+func TestParseDKMSConf(t *testing.T) {
+	conf := `PACKAGE_NAME="nvidia"
+PACKAGE_VERSION="470.256.02"
+BUILD_EXCLUSIVE_KERNEL_MIN="3.10"
+BUILD_EXCLUSIVE_KERNEL_MAX='6.7'
+AUTOINSTALL=yes
+`
+	kernelRange := ParseDKMSConf([]byte(conf))
+	if kernelRange.Min != "3.10" || kernelRange.Max != "6.7" {
+		t.Errorf("ParseDKMSConf() = %+v, expected 3.10 to 6.7", kernelRange)
+	}
+	if merged := mergeRange(KernelRange{Max: "6.8"}, kernelRange); merged.Min != "3.10" || merged.Max != "6.8" {
+		t.Errorf("mergeRange() = %+v, expected the curated maximum to win", merged)
+	}
+}
+
+func TestMatrixFlagsKernelsAboveRange(t *testing.T) {
+	ranges, err := ParseCompat([]byte(`{"470": {"max_kernel": "6.7", "note": "fixed in 470.256"}, "550": {"min_kernel": "4.15"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kernels := []Kernel{
+		{Series: "noble", Source: "linux", Version: "6.8.0-45.45"},
+		{Series: "jammy", Source: "linux", Version: "5.15.0-119.129"},
+		{Series: "jammy", Source: "linux-hwe-6.8", Version: "6.8.0-45.45~22.04.1"},
+	}
+	branches := map[string][]string{"noble": {"470", "550"}, "jammy": {"470", "570"}}
+
+	findings := Matrix(ranges, kernels, branches)
+	if len(findings) != 6 {
+		t.Fatalf("Matrix() returned %d findings, expected 6", len(findings))
+	}
+	var flagged []string
+	for _, finding := range findings {
+		if finding.Flagged {
+			flagged = append(flagged, finding.Branch+"/"+finding.Series+"/"+finding.KernelSource)
+		}
+		if finding.Branch == "570" && finding.Status != StatusUnknown {
+			t.Errorf("Branch without a range has status %s, expected %s", finding.Status, StatusUnknown)
+		}
+	}
+	if len(flagged) != 2 || flagged[0] != "470/jammy/linux-hwe-6.8" || flagged[1] != "470/noble/linux" {
+		t.Errorf("Matrix() flagged %v, expected 470 on the 6.8 kernels", flagged)
+	}
+}
@@ -0,0 +1,408 @@
+// Package doctor checks that a deployment can run: the configuration loads, the upstream
+// hosts resolve and answer, the state directories are writable and the certificates are
+// valid. It backs the `nvidia-monitor doctor` command.
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/releases"
+)
+
+// certificateWarning is how close to its expiry a certificate is reported as a warning
+const certificateWarning = 14 * 24 * time.Hour
+
+// Status is the outcome of one check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Result is one row of the doctor report
+type Result struct {
+	Check  string `json:"check"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Options holds what the checks need besides the configuration
+type Options struct {
+	ReleasesFile string // Supported releases file, e.g. data/supportedReleases.json
+	CertFile     string // HTTPS certificate, checked when HTTPS is enabled
+	KeyFile      string
+	SkipNetwork  bool // Skip DNS resolution and upstream requests
+	Client       *http.Client
+	// LookupHost resolves a host name; defaults to net.DefaultResolver.LookupHost
+	LookupHost func(ctx context.Context, host string) ([]string, error)
+	Timeout    time.Duration // Per resolution or request; defaults to 10s
+	Now        time.Time
+}
+
+// upstream is a configured URL and what it is used for
+type upstream struct {
+	name string
+	url  string
+}
+
+// Run loads the configuration file and runs every check against it
+func Run(configPath string, opts Options) []Result {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: opts.Timeout}
+	}
+	if opts.LookupHost == nil {
+		opts.LookupHost = net.DefaultResolver.LookupHost
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return []Result{{Check: "config", Status: StatusFail, Detail: err.Error()}}
+	}
+	results := []Result{checkConfigFile(configPath, cfg)}
+	results = append(results, checkInputFiles(cfg, opts.ReleasesFile)...)
+	results = append(results, checkStateDirectories(cfg)...)
+	results = append(results, checkServerCertificate(cfg, opts)...)
+
+	if opts.SkipNetwork {
+		return append(results, Result{Check: "upstreams", Status: StatusSkip, Detail: "network checks disabled"})
+	}
+	upstreams, invalid := configuredUpstreams(cfg)
+	results = append(results, invalid...)
+	return append(results, checkUpstreams(upstreams, cfg.HTTP.HTTPProxy != "" || cfg.HTTP.HTTPSProxy != "", opts)...)
+}
+
+// Failed reports whether any check failed
+func Failed(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteTable prints the results as an aligned table followed by a summary line
+func WriteTable(w io.Writer, results []Result) error {
+	counts := make(map[Status]int)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+	for _, result := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Check, strings.ToUpper(string(result.Status)), result.Detail)
+		counts[result.Status]++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed, %d skipped\n",
+		counts[StatusPass], counts[StatusWarn], counts[StatusFail], counts[StatusSkip])
+	return err
+}
+
+// WriteJSON prints the results as a JSON array
+func WriteJSON(w io.Writer, results []Result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// checkConfigFile reports which configuration was loaded
+func checkConfigFile(configPath string, cfg *config.Config) Result {
+	detail := "loaded " + configPath
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return Result{Check: "config", Status: StatusWarn, Detail: configPath + " not found, using the built-in defaults"}
+	}
+	if cfg.Testing.Enabled {
+		detail += fmt.Sprintf(" (testing mode, upstreams served by the mock server on port %d)", cfg.Testing.MockServerPort)
+	}
+	return Result{Check: "config", Status: StatusPass, Detail: detail}
+}
+
+// checkInputFiles checks that the files the service reads at startup can be parsed
+func checkInputFiles(cfg *config.Config, releasesFile string) []Result {
+	var results []Result
+	if releasesFile != "" {
+		if supported, err := releases.LoadSupportedReleases(releasesFile, cfg.Releases.Profile); err != nil {
+			results = append(results, Result{Check: "supported releases", Status: StatusFail, Detail: err.Error()})
+		} else {
+			detail := fmt.Sprintf("%d releases in %s", len(supported), releasesFile)
+			if cfg.Releases.Profile != "" {
+				detail += fmt.Sprintf(" with profile %s", cfg.Releases.Profile)
+			}
+			results = append(results, Result{Check: "supported releases", Status: StatusPass, Detail: detail})
+		}
+	}
+	// The DKMS matrix is optional, a configured targets file is not
+	for _, input := range []struct {
+		name    string
+		file    string
+		missing Status
+	}{
+		{"targets file", cfg.Targets.File, StatusFail},
+		{"dkms compat file", cfg.DKMS.GetCompatFile(), StatusWarn},
+	} {
+		if input.file == "" {
+			continue
+		}
+		if _, err := os.Stat(input.file); err != nil {
+			results = append(results, Result{Check: input.name, Status: input.missing, Detail: err.Error()})
+		} else {
+			results = append(results, Result{Check: input.name, Status: StatusPass, Detail: input.file})
+		}
+	}
+	return results
+}
+
+// checkStateDirectories checks that the directories of the persisted state and caches are
+// writable, creating a temporary file in each
+func checkStateDirectories(cfg *config.Config) []Result {
+	users := make(map[string][]string)
+	add := func(dir, user string) {
+		if dir == "" {
+			dir = "."
+		}
+		users[dir] = append(users[dir], user)
+	}
+	add(filepath.Dir(cfg.History.GetDataFile()), "history")
+	add(filepath.Dir(cfg.Notes.GetDataFile()), "notes")
+	add(filepath.Dir(cfg.Acks.GetDataFile()), "acknowledgements")
+	add(filepath.Dir(cfg.Advisories.GetDataFile()), "advisories")
+	add(filepath.Dir(cfg.Stats.GetDataFile()), "stats")
+	add(filepath.Dir(cfg.Budget.GetDataFile()), "budget")
+	add(filepath.Dir(cfg.Fleet.GetDataFile()), "fleet")
+	if cfg.Changelog.Enabled {
+		add(cfg.Changelog.GetCacheDir(), "changelog cache")
+	}
+	if cfg.Jobs.Enabled {
+		add(cfg.Jobs.GetDir(), "jobs")
+	}
+
+	dirs := make([]string, 0, len(users))
+	for dir := range users {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	results := make([]Result, 0, len(dirs))
+	for _, dir := range dirs {
+		check := "writable " + dir
+		usedBy := "used by " + strings.Join(users[dir], ", ")
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			results = append(results, Result{Check: check, Status: StatusWarn, Detail: "does not exist yet, created on first save; " + usedBy})
+			continue
+		}
+		file, err := os.CreateTemp(dir, ".doctor-*")
+		if err != nil {
+			results = append(results, Result{Check: check, Status: StatusFail, Detail: err.Error()})
+			continue
+		}
+		file.Close()
+		os.Remove(file.Name())
+		results = append(results, Result{Check: check, Status: StatusPass, Detail: usedBy})
+	}
+	return results
+}
+
+// checkServerCertificate checks the HTTPS certificate when HTTPS is enabled
+func checkServerCertificate(cfg *config.Config, opts Options) []Result {
+	if !cfg.Server.EnableHTTPS {
+		return nil
+	}
+	const check = "https certificate"
+	if _, err := os.Stat(opts.CertFile); os.IsNotExist(err) {
+		return []Result{{Check: check, Status: StatusWarn, Detail: opts.CertFile + " not found, a self-signed certificate will be generated"}}
+	}
+	pair, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return []Result{{Check: check, Status: StatusFail, Detail: err.Error()}}
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return []Result{{Check: check, Status: StatusFail, Detail: err.Error()}}
+	}
+	return []Result{certificateResult(check, leaf, opts.Now)}
+}
+
+// certificateResult reports whether a certificate is valid at now and for how long
+func certificateResult(check string, cert *x509.Certificate, now time.Time) Result {
+	until := cert.NotAfter.UTC().Format("2006-01-02")
+	switch {
+	case now.Before(cert.NotBefore):
+		return Result{Check: check, Status: StatusFail, Detail: "certificate not valid before " + cert.NotBefore.UTC().Format("2006-01-02")}
+	case now.After(cert.NotAfter):
+		return Result{Check: check, Status: StatusFail, Detail: "certificate expired on " + until}
+	case cert.NotAfter.Sub(now) < certificateWarning:
+		return Result{Check: check, Status: StatusWarn, Detail: "certificate expires on " + until}
+	}
+	return Result{Check: check, Status: StatusPass, Detail: "certificate valid until " + until}
+}
+
+// configuredUpstreams lists the upstream URLs in use, reporting the ones that do not parse
+func configuredUpstreams(cfg *config.Config) ([]upstream, []Result) {
+	urls := cfg.GetEffectiveURLs()
+	candidates := serviceCandidates(cfg)
+	if !urls.CDN.Offline {
+		assets := urls.CDN.Assets()
+		names := make([]string, 0, len(assets))
+		for name := range assets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			candidates = append(candidates, upstream{"cdn " + name, assets[name][0]})
+		}
+	}
+	// Listed after the CDN assets so that a file on the same host, such as the Vanilla CSS, is
+	// requested rather than the bare base URL
+	candidates = append(candidates, upstream{"ubuntu assets", urls.Ubuntu.AssetsBaseURL})
+	return validUpstreams(candidates)
+}
+
+// serviceCandidates lists the upstreams the service itself requests; the CDN assets are only
+// requested by the browsers
+func serviceCandidates(cfg *config.Config) []upstream {
+	urls := cfg.GetEffectiveURLs()
+	candidates := []upstream{
+		{"launchpad", urls.Launchpad.BaseURL},
+		{"launchpad published sources", urls.Launchpad.PublishedSourcesAPI},
+		{"launchpad series", urls.Launchpad.UbuntuSeriesBaseURL},
+		{"nvidia driver archive", urls.NVIDIA.DriverArchiveURL},
+		{"nvidia datacenter releases", urls.NVIDIA.ServerDriversAPI},
+	}
+	for _, u := range urls.Kernel.SeriesYAMLURLs() {
+		candidates = append(candidates, upstream{"kernel-series.yaml", u})
+	}
+	for _, u := range urls.Kernel.SRUCycleURLs() {
+		candidates = append(candidates, upstream{"sru-cycle.yaml", u})
+	}
+	if cfg.Targets.URL != "" {
+		candidates = append(candidates, upstream{"targets feed", cfg.Targets.URL})
+	}
+	if cfg.Tegra.Enabled {
+		candidates = append(candidates, upstream{"tegra releases", cfg.Tegra.GetReleasesURL()})
+	}
+	if cfg.ArchiveCheck.Enabled {
+		candidates = append(candidates, upstream{"archive mirror", cfg.ArchiveCheck.GetMirrorURL()})
+	}
+	if cfg.Certification.Enabled && cfg.Certification.File == "" {
+		candidates = append(candidates, upstream{"certification api", cfg.Certification.URL})
+	}
+	return candidates
+}
+
+// validUpstreams drops the candidates without a URL and reports the ones that do not parse
+func validUpstreams(candidates []upstream) ([]upstream, []Result) {
+	var upstreams []upstream
+	var invalid []Result
+	for _, candidate := range candidates {
+		if candidate.url == "" {
+			continue
+		}
+		parsed, err := url.Parse(candidate.url)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			invalid = append(invalid, Result{Check: "url " + candidate.name, Status: StatusFail, Detail: fmt.Sprintf("invalid URL %q", candidate.url)})
+			continue
+		}
+		upstreams = append(upstreams, candidate)
+	}
+	return upstreams, invalid
+}
+
+// checkUpstreams resolves each upstream host and sends one request per host, to the first
+// URL configured on it. Resolution failures are only warnings behind a proxy, which resolves
+// the names itself.
+func checkUpstreams(upstreams []upstream, proxied bool, opts Options) []Result {
+	var hosts []string
+	byHost := make(map[string][]upstream)
+	for _, u := range upstreams {
+		parsed, _ := url.Parse(u.url)
+		if _, ok := byHost[parsed.Host]; !ok {
+			hosts = append(hosts, parsed.Host)
+		}
+		byHost[parsed.Host] = append(byHost[parsed.Host], u)
+	}
+
+	var results []Result
+	for _, host := range hosts {
+		results = append(results, resolveHost(host, proxied, opts))
+		results = append(results, requestUpstream(host, byHost[host], opts)...)
+	}
+	return results
+}
+
+// resolveHost looks up the name of an upstream host
+func resolveHost(host string, proxied bool, opts Options) Result {
+	check := "resolve " + host
+	name := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		name = h
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	addresses, err := opts.LookupHost(ctx, name)
+	if err != nil {
+		if proxied {
+			return Result{Check: check, Status: StatusWarn, Detail: err.Error() + " (the proxy may still resolve it)"}
+		}
+		return Result{Check: check, Status: StatusFail, Detail: err.Error()}
+	}
+	return Result{Check: check, Status: StatusPass, Detail: strings.Join(addresses, ", ")}
+}
+
+// requestUpstream sends a GET to the first URL of a host without reading the body, and checks
+// the certificate it presents over HTTPS
+func requestUpstream(host string, upstreams []upstream, opts Options) []Result {
+	names := make([]string, 0, len(upstreams))
+	for _, u := range upstreams {
+		names = append(names, u.name)
+	}
+	check := "reach " + host
+	target := upstreams[0].url
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return []Result{{Check: check, Status: StatusFail, Detail: err.Error()}}
+	}
+	started := time.Now()
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return []Result{{Check: check, Status: StatusFail, Detail: err.Error()}}
+	}
+	resp.Body.Close()
+	elapsed := time.Since(started).Round(time.Millisecond)
+
+	detail := fmt.Sprintf("%s from %s in %v (%s)", resp.Status, target, elapsed, strings.Join(names, ", "))
+	results := []Result{{Check: check, Status: StatusPass, Detail: detail}}
+	if resp.StatusCode >= 400 {
+		results[0].Status = StatusFail
+	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		results = append(results, certificateResult("certificate "+host, resp.TLS.PeerCertificates[0], opts.Now))
+	}
+	return results
+}
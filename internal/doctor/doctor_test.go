@@ -0,0 +1,260 @@
+package doctor
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// writeConfig saves a configuration whose state lives in dir and whose upstreams are baseURL
+func writeConfig(t *testing.T, dir, baseURL string) string {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.URLs.Launchpad.BaseURL = baseURL + "/launchpad"
+	cfg.URLs.Launchpad.PublishedSourcesAPI = baseURL + "/launchpad/ubuntu/+archive/primary"
+	cfg.URLs.Launchpad.PublishedBinariesAPI = baseURL + "/launchpad/ubuntu/+archive/primary"
+	cfg.URLs.Launchpad.UbuntuSeriesBaseURL = baseURL + "/launchpad/ubuntu"
+	cfg.URLs.NVIDIA.DriverArchiveURL = baseURL + "/missing/"
+	cfg.URLs.NVIDIA.ServerDriversAPI = "not a url"
+	cfg.URLs.Kernel.SeriesYAMLURL = "http://kernel.invalid/kernel-series.yaml"
+	cfg.URLs.Kernel.SRUCycleURL = "http://kernel.invalid/sru-cycle.yaml"
+	cfg.URLs.Ubuntu.AssetsBaseURL = ""
+	cfg.URLs.CDN.Offline = true
+	cfg.History.DataFile = filepath.Join(dir, "state", "history.json")
+	cfg.Notes.DataFile = filepath.Join(dir, "notes.json")
+	cfg.Acks.DataFile = filepath.Join(dir, "acks.json")
+	cfg.Advisories.DataFile = filepath.Join(dir, "advisories.json")
+	cfg.Stats.DataFile = filepath.Join(dir, "stats.json")
+	cfg.Budget.DataFile = filepath.Join(dir, "budget.json")
+	cfg.Fleet.DataFile = filepath.Join(dir, "fleet.json")
+	cfg.DKMS.CompatFile = filepath.Join(dir, "dkms-compat.json")
+
+	path := filepath.Join(dir, "config.json")
+	if err := config.SaveConfig(cfg, path); err != nil {
+		t.Fatalf("SaveConfig() returned error: %v", err)
+	}
+	return path
+}
+
+// statuses indexes results by check
+func statuses(results []Result) map[string]Result {
+	byCheck := make(map[string]Result, len(results))
+	for _, result := range results {
+		byCheck[result.Check] = result
+	}
+	return byCheck
+}
+
+func TestRunReportsEachCheck(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/launchpad") {
+			w.Write([]byte(`{}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	path := writeConfig(t, dir, upstream.URL)
+	lookups := map[string]bool{}
+	results := Run(path, Options{
+		LookupHost: func(ctx context.Context, host string) ([]string, error) {
+			lookups[host] = true
+			if host == "kernel.invalid" {
+				return nil, errors.New("no such host")
+			}
+			return []string{"127.0.0.1"}, nil
+		},
+		Timeout: time.Second,
+	})
+	byCheck := statuses(results)
+	host := strings.TrimPrefix(upstream.URL, "http://")
+
+	tests := []struct {
+		check    string
+		expected Status
+	}{
+		{"config", StatusPass},
+		{"dkms compat file", StatusWarn},
+		{"writable " + dir, StatusPass},
+		{"writable " + filepath.Join(dir, "state"), StatusWarn},
+		{"url nvidia datacenter releases", StatusFail},
+		{"resolve " + host, StatusPass},
+		{"reach " + host, StatusPass},
+		{"resolve kernel.invalid", StatusFail},
+	}
+	for _, tt := range tests {
+		if got, ok := byCheck[tt.check]; !ok || got.Status != tt.expected {
+			t.Errorf("check %q = %+v, expected %s", tt.check, got, tt.expected)
+		}
+	}
+	if !strings.Contains(byCheck["reach "+host].Detail, "/launchpad") {
+		t.Errorf("reach %s = %+v, expected one request to the first URL of the host", host, byCheck["reach "+host])
+	}
+	if !lookups["127.0.0.1"] || len(lookups) != 2 {
+		t.Errorf("lookups = %v, expected one per host", lookups)
+	}
+	if !Failed(results) {
+		t.Errorf("Failed() = false, expected the invalid URL to fail the run")
+	}
+}
+
+func TestRunFailsOnInvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"i18n": {"default_locale": "xx"}}`), 0644)
+
+	results := Run(path, Options{SkipNetwork: true})
+	if len(results) != 1 || results[0].Check != "config" || results[0].Status != StatusFail {
+		t.Errorf("Run(invalid config) = %+v, expected a single failed config check", results)
+	}
+}
+
+func TestRunSkipsNetworkChecks(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "http://127.0.0.1:1")
+	results := Run(path, Options{SkipNetwork: true})
+	byCheck := statuses(results)
+	if byCheck["upstreams"].Status != StatusSkip {
+		t.Errorf("upstreams = %+v, expected skipped", byCheck["upstreams"])
+	}
+	for _, result := range results {
+		if strings.HasPrefix(result.Check, "reach ") || strings.HasPrefix(result.Check, "resolve ") {
+			t.Errorf("unexpected network check %+v", result)
+		}
+	}
+}
+
+func TestCertificateResult(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		notBefore time.Time
+		notAfter  time.Time
+		expected  Status
+	}{
+		{now.AddDate(0, -1, 0), now.AddDate(1, 0, 0), StatusPass},
+		{now.AddDate(0, -1, 0), now.AddDate(0, 0, 3), StatusWarn},
+		{now.AddDate(-1, 0, 0), now.AddDate(0, 0, -1), StatusFail},
+		{now.AddDate(0, 0, 1), now.AddDate(1, 0, 0), StatusFail},
+	}
+	for _, tt := range tests {
+		cert := &x509.Certificate{NotBefore: tt.notBefore, NotAfter: tt.notAfter}
+		if got := certificateResult("certificate", cert, now); got.Status != tt.expected {
+			t.Errorf("certificateResult(%v - %v) = %+v, expected %s", tt.notBefore, tt.notAfter, got, tt.expected)
+		}
+	}
+}
+
+func TestRunChecksUpstreamCertificates(t *testing.T) {
+	upstream := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	path := writeConfig(t, t.TempDir(), upstream.URL)
+	results := Run(path, Options{
+		Client:     upstream.Client(),
+		LookupHost: func(ctx context.Context, host string) ([]string, error) { return []string{host}, nil },
+		Timeout:    time.Second,
+	})
+	host := strings.TrimPrefix(upstream.URL, "https://")
+	if got := statuses(results)["certificate "+host]; got.Status != StatusPass {
+		t.Errorf("certificate %s = %+v, expected a valid certificate", host, got)
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	results := []Result{
+		{Check: "config", Status: StatusPass, Detail: "loaded config.json"},
+		{Check: "reach api.launchpad.net", Status: StatusFail, Detail: "timeout"},
+	}
+
+	var table strings.Builder
+	WriteTable(&table, results)
+	if !strings.Contains(table.String(), "reach api.launchpad.net  FAIL    timeout") || !strings.Contains(table.String(), "1 passed, 0 warnings, 1 failed, 0 skipped") {
+		t.Errorf("WriteTable() = %q, expected aligned rows and a summary", table.String())
+	}
+
+	var out strings.Builder
+	WriteJSON(&out, results)
+	var decoded []Result
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil || len(decoded) != 2 || decoded[1].Status != StatusFail {
+		t.Errorf("WriteJSON() = %s, expected the results", out.String())
+	}
+}
+
+func TestStartupProbesApplyPolicies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/missing") {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	newConfig := func() *config.Config {
+		cfg := config.DefaultConfig()
+		cfg.URLs.Launchpad.BaseURL = server.URL + "/launchpad"
+		cfg.URLs.Launchpad.PublishedSourcesAPI = server.URL + "/launchpad/ubuntu/+archive/primary"
+		cfg.URLs.Launchpad.UbuntuSeriesBaseURL = server.URL + "/launchpad/ubuntu"
+		cfg.URLs.NVIDIA.DriverArchiveURL = server.URL + "/nvidia/"
+		cfg.URLs.NVIDIA.ServerDriversAPI = server.URL + "/datacenter"
+		// The first mirror is down, the second answers
+		cfg.URLs.Kernel.SeriesYAMLURL = server.URL + "/missing/kernel-series.yaml"
+		cfg.URLs.Kernel.SeriesYAMLMirrors = []string{server.URL + "/mirror/kernel-series.yaml"}
+		cfg.URLs.Kernel.SRUCycleURL = server.URL + "/sru-cycle.yaml"
+		cfg.Tegra.Enabled = true
+		cfg.Tegra.ReleasesURL = server.URL + "/missing/jetson/"
+		cfg.Targets.URL = server.URL + "/missing/targets.json"
+		cfg.Targets.File = "data/targets.json"
+		cfg.Startup.Timeout = "2s"
+		cfg.Startup.OnFailure = config.ProbeDegrade
+		return cfg
+	}
+
+	cfg := newConfig()
+	results, err := StartupProbes(cfg, nil)
+	if err != nil {
+		t.Fatalf("StartupProbes returned error: %v", err)
+	}
+	byName := make(map[string]ProbeResult)
+	for _, result := range results {
+		byName[result.Upstream] = result
+	}
+	if result := byName["kernel-series.yaml"]; result.Status != StatusPass || !strings.Contains(result.Detail, "/mirror/") {
+		t.Errorf("kernel-series.yaml = %+v, expected the mirror to answer", result)
+	}
+	if result := byName["tegra releases"]; result.Status != StatusFail || result.Action != "Tegra branches disabled" {
+		t.Errorf("tegra releases = %+v, expected it degraded", result)
+	}
+	if cfg.Tegra.Enabled || cfg.Targets.URL != "" {
+		t.Errorf("Degraded features should be switched off: tegra=%v targets=%q", cfg.Tegra.Enabled, cfg.Targets.URL)
+	}
+	if result := byName["targets feed"]; result.Action != "targets read from data/targets.json" {
+		t.Errorf("targets feed = %+v, expected the targets file to take over", result)
+	}
+	if len(results) != 9 {
+		t.Errorf("StartupProbes returned %d results, expected one per upstream: %+v", len(results), results)
+	}
+
+	cfg = newConfig()
+	cfg.URLs.Launchpad.BaseURL = server.URL + "/missing/launchpad"
+	cfg.Startup.OnFailure = config.ProbeContinue
+	cfg.Startup.Policies = map[string]string{"launchpad": config.ProbeFail}
+	if _, err := StartupProbes(cfg, nil); err == nil || !strings.Contains(err.Error(), "launchpad") {
+		t.Errorf("StartupProbes error = %v, expected the required launchpad upstream to fail", err)
+	}
+	if !cfg.Tegra.Enabled {
+		t.Errorf("Upstreams with the continue policy should keep their feature")
+	}
+}
@@ -0,0 +1,164 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// ProbeResult is the outcome of probing one upstream before the service starts
+type ProbeResult struct {
+	Upstream string `json:"upstream"`
+	Status   Status `json:"status"`           // pass or fail
+	Detail   string `json:"detail"`           // e.g. "200 OK from https://... in 120ms", or the error
+	Policy   string `json:"policy,omitempty"` // Policy applied to a failed upstream
+	Action   string `json:"action,omitempty"` // What the policy did, e.g. "Tegra branches disabled"
+}
+
+// degraders switch off the optional feature that needs an upstream and describe the change.
+// Upstreams without one are needed by the dashboard itself and cannot be degraded.
+var degraders = map[string]func(cfg *config.Config) string{
+	"targets feed": func(cfg *config.Config) string {
+		cfg.Targets.URL = ""
+		if cfg.Targets.File != "" {
+			return "targets read from " + cfg.Targets.File
+		}
+		return "target versions disabled"
+	},
+	"tegra releases": func(cfg *config.Config) string {
+		cfg.Tegra.Enabled = false
+		return "Tegra branches disabled"
+	},
+	"archive mirror": func(cfg *config.Config) string {
+		cfg.ArchiveCheck.Enabled = false
+		return "archive check disabled"
+	},
+	"certification api": func(cfg *config.Config) string {
+		cfg.Certification.Enabled = false
+		return "certified hardware check disabled"
+	},
+}
+
+// StartupProbes requests each upstream the service uses once, in parallel and with the
+// startup timeout, then applies the failure policy of each upstream that did not answer:
+// "degrade" switches its feature off in cfg, and "fail" makes StartupProbes return an error.
+// An upstream with several mirrors passes when one of them answers.
+func StartupProbes(cfg *config.Config, client *http.Client) ([]ProbeResult, error) {
+	timeout := cfg.Startup.GetTimeout()
+	if client == nil {
+		client = &http.Client{Timeout: timeout}
+	}
+
+	upstreams, invalid := validUpstreams(serviceCandidates(cfg))
+	var names []string
+	byName := make(map[string][]upstream)
+	for _, u := range upstreams {
+		if _, ok := byName[u.name]; !ok {
+			names = append(names, u.name)
+		}
+		byName[u.name] = append(byName[u.name], u)
+	}
+
+	results := make([]ProbeResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i] = probeUpstream(name, byName[name], client, timeout)
+		}(i, name)
+	}
+	wg.Wait()
+	for _, result := range invalid {
+		results = append(results, ProbeResult{
+			Upstream: strings.TrimPrefix(result.Check, "url "),
+			Status:   StatusFail,
+			Detail:   result.Detail,
+		})
+	}
+
+	var failed []string
+	for i := range results {
+		result := &results[i]
+		if result.Status == StatusPass {
+			continue
+		}
+		result.Policy = cfg.Startup.Policy(result.Upstream)
+		switch result.Policy {
+		case config.ProbeFail:
+			result.Action = "refusing to start"
+			failed = append(failed, result.Upstream)
+		case config.ProbeDegrade:
+			if degrade, ok := degraders[result.Upstream]; ok {
+				result.Action = degrade(cfg)
+			} else {
+				result.Action = "cannot be degraded, continuing"
+			}
+		default:
+			result.Action = "continuing, the refreshes retry it"
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("required upstreams did not answer: %s", strings.Join(failed, ", "))
+	}
+	return results, nil
+}
+
+// probeUpstream requests the URLs of an upstream in turn until one answers
+func probeUpstream(name string, urls []upstream, client *http.Client, timeout time.Duration) ProbeResult {
+	var details []string
+	for _, u := range urls {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.url, nil)
+		if err != nil {
+			cancel()
+			details = append(details, err.Error())
+			continue
+		}
+		started := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			cancel()
+			details = append(details, err.Error())
+			continue
+		}
+		resp.Body.Close()
+		cancel()
+		detail := fmt.Sprintf("%s from %s in %v", resp.Status, u.url, time.Since(started).Round(time.Millisecond))
+		if resp.StatusCode < 400 {
+			return ProbeResult{Upstream: name, Status: StatusPass, Detail: detail}
+		}
+		details = append(details, detail)
+	}
+	return ProbeResult{Upstream: name, Status: StatusFail, Detail: strings.Join(details, "; ")}
+}
+
+// LogProbes writes one aligned line per probed upstream, then a summary line
+func LogProbes(results []ProbeResult) {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	failed := 0
+	for _, result := range results {
+		line := fmt.Sprintf("%s\t%s\t%s", result.Upstream, strings.ToUpper(string(result.Status)), result.Detail)
+		if result.Status != StatusPass {
+			failed++
+			line += fmt.Sprintf(" -> %s: %s", result.Policy, result.Action)
+		}
+		fmt.Fprintln(tw, line)
+	}
+	tw.Flush()
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		if line != "" {
+			log.Printf("Startup probe: %s", line)
+		}
+	}
+	log.Printf("Startup probes: %d of %d upstreams answered", len(results)-failed, len(results))
+}
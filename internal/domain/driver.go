@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// DriverEntry represents a driver entry from NVIDIA's website
+type DriverEntry struct {
+	Version string
+	Date    time.Time
+	IsBeta  bool
+	// Installer is the .run installer listed in the version directory; empty when none is
+	Installer       string
+	InstallerURL    string
+	InstallerSize   int64  // Bytes as shown by the directory listing; 0 when not shown
+	InstallerSHA256 string // Hex digest from the published .sha256sum file; empty when none is
+}
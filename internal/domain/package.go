@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"time"
+
+	"nvidia_driver_monitor/internal/slo"
+)
+
+// SeriesData represents the data for a single series row
+type SeriesData struct {
+	Series          string
+	UpdatesSecurity string
+	PocketMarkers   string
+	Proposed        string
+	UpstreamVersion string
+	TargetVersion   string // Blessed target overriding upstream for comparisons; "-" when not set
+	TargetNote      string
+	ReleaseDate     string
+	SRUCycle        string
+	UpdatesColor    string
+	ProposedColor   string
+	QueueStatus     string // e.g. "in unapproved since 2026-10-01"; empty when nothing is queued
+	QueueVersion    string
+	// Phasing is the phased update percentage of the published version while -updates is
+	// still phasing it, e.g. "40%"; "0%" when phasing is halted, empty once fully phased
+	Phasing string `json:",omitempty"`
+	// Components are the archive components (main, restricted, multiverse) of the shown versions
+	Component         string `json:",omitempty"`
+	ProposedComponent string `json:",omitempty"`
+	Section           string `json:",omitempty"` // Archive section of the published version
+	// Removed is set when the package was deleted from the series, e.g. "removed on 2024-05-01"
+	Removed     string `json:",omitempty"`
+	RemovalNote string `json:",omitempty"` // Deleted version and removal comment
+	// Availability explains a row without a published version: "not-uploaded" when the series
+	// exists in Launchpad, "series-eol" or "series-unknown" when it no longer does, "in-queue"
+	// while an upload waits for approval and "in-proposed" when only proposed has a version
+	Availability string `json:",omitempty"`
+	// Freeze is set on rows of the development series while Launchpad has it in pre-release
+	// freeze: "needs-exception" when an upload is still needed, otherwise "frozen"
+	Freeze string `json:",omitempty"`
+	// DefaultBranch is set on the rows of the branch seeded by default in their series, the
+	// one most users have installed, see /api/v1/seeds
+	DefaultBranch bool `json:",omitempty"`
+	// Note is an operator annotation such as "blocked on LP#2012345", see /api/v1/notes
+	Note        string `json:",omitempty"`
+	NoteUpdated string `json:",omitempty"` // e.g. "jdoe on 2026-10-17"
+	// Acknowledged is the reason an outdated row is intentionally held back, in which case
+	// UpdatesColor is "acknowledged" instead of "danger", see /api/v1/acknowledgements
+	Acknowledged      string `json:",omitempty"`
+	AcknowledgedUntil string `json:",omitempty"` // e.g. "2026-12-31 00:00 UTC"
+	// OutdatedSince is the first day of the current outdated run of the published version and
+	// OutdatedDays its length, both from the history store
+	OutdatedSince string `json:",omitempty"`
+	OutdatedDays  int    `json:",omitempty"`
+	// Comparisons holds structured published/proposed results against the comparison version
+	Comparisons []VersionComparison `json:",omitempty"`
+}
+
+// PackageData represents the data for a complete package table
+type PackageData struct {
+	PackageName string
+	Series      []SeriesData
+	// Changelogs maps shown versions to their latest changelog entry (when enabled)
+	Changelogs map[string]*ChangelogEntry `json:",omitempty"`
+	// SLO is the branch's service level objective status (when a target is configured)
+	SLO *slo.Status `json:",omitempty"`
+	// StaleSince is set when upstream APIs failed and the last good data from that time is shown
+	StaleSince *time.Time `json:",omitempty"`
+}
+
+// PackageError records why a package could not be generated during a refresh
+type PackageError struct {
+	PackageName string    `json:"package_name"`
+	Error       string    `json:"error"`
+	FailedAt    time.Time `json:"failed_at"`
+}
+
+// OutdatedSeries returns how many series rows are behind the upstream version
+func (p *PackageData) OutdatedSeries() int {
+	count := 0
+	for _, s := range p.Series {
+		if s.UpdatesColor == "danger" {
+			count++
+		}
+	}
+	return count
+}
+
+// LongestOutdatedDays returns the longest time, in days, any series row has been outdated
+func (p *PackageData) LongestOutdatedDays() int {
+	longest := 0
+	for _, s := range p.Series {
+		if s.OutdatedDays > longest {
+			longest = s.OutdatedDays
+		}
+	}
+	return longest
+}
+
+// VersionComparison is the structured result of comparing one pocket of a series to upstream
+type VersionComparison struct {
+	Pocket                 string `json:"pocket"` // "published" or "proposed"
+	UpstreamVersion        string `json:"upstream_version"`
+	ArchiveVersion         string `json:"archive_version"`
+	Comparison             string `json:"comparison"` // "behind", "equal", "ahead" or "unknown"
+	DeltaDaysSinceUpstream *int   `json:"delta_days_since_upstream,omitempty"` // Days since the compared version's release, when behind and its date is known
+}
+
+// ChangelogEntry is the latest entry of a Debian changelog
+type ChangelogEntry struct {
+	Package      string   `json:"package"`
+	Version      string   `json:"version"`
+	Distribution string   `json:"distribution"`
+	Urgency      string   `json:"urgency"`
+	Maintainer   string   `json:"maintainer"`
+	Date         string   `json:"date"`
+	Changes      []string `json:"changes"`
+	Bugs         []string `json:"bugs"`
+	CVEs         []string `json:"cves"`
+}
+
+// ReleaseNotes is what a new archive version adds over the version it replaced, taken from the
+// latest entries of both changelogs
+type ReleaseNotes struct {
+	Version  string   `json:"version"`
+	Previous string   `json:"previous,omitempty"` // Empty when the changelog of the replaced version is unknown
+	Added    []string `json:"added"`              // Bullet points of the new entry missing from the previous one
+	Bugs     []string `json:"bugs"`               // Launchpad bugs referenced by the new entry only
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/slo"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the domain golden files")
+
+func TestPackageDataCounts(t *testing.T) {
+	pkg := &PackageData{Series: []SeriesData{
+		{Series: "resolute", UpdatesColor: "success"},
+		{Series: "noble", UpdatesColor: "danger", OutdatedDays: 3},
+		{Series: "jammy", UpdatesColor: "danger", OutdatedDays: 12},
+		{Series: "focal", UpdatesColor: "acknowledged", OutdatedDays: 40},
+	}}
+	if got := pkg.OutdatedSeries(); got != 2 {
+		t.Errorf("OutdatedSeries() = %d, expected 2; acknowledged rows are not outdated", got)
+	}
+	if got := pkg.LongestOutdatedDays(); got != 40 {
+		t.Errorf("LongestOutdatedDays() = %d, expected 40", got)
+	}
+	if got := (&PackageData{}).LongestOutdatedDays(); got != 0 {
+		t.Errorf("LongestOutdatedDays() = %d without rows, expected 0", got)
+	}
+}
+
+// TestPackageDataJSON pins the wire format of a package, which every API response and the
+// peer snapshots share. Run go test -update after an intended change.
+func TestPackageDataJSON(t *testing.T) {
+	days := 15
+	stale := time.Date(2026, 10, 15, 7, 45, 0, 0, time.UTC)
+	packages := []PackageData{
+		{
+			PackageName: "nvidia-graphics-drivers-570",
+			Series: []SeriesData{
+				{
+					Series:            "jammy",
+					UpdatesSecurity:   "570.172.08-0ubuntu0.22.04.1",
+					PocketMarkers:     " (U/S/-)",
+					Proposed:          "570.195.03-0ubuntu0.22.04.1",
+					UpstreamVersion:   "570.195.03",
+					TargetVersion:     "-",
+					ReleaseDate:       "2026-09-30",
+					SRUCycle:          "2026-10-26",
+					UpdatesColor:      "danger",
+					ProposedColor:     "success",
+					Component:         "restricted",
+					ProposedComponent: "restricted",
+					Note:              "blocked on LP#2012345",
+					NoteUpdated:       "jdoe on 2026-10-14",
+					OutdatedSince:     "2026-10-01",
+					OutdatedDays:      14,
+					Comparisons: []VersionComparison{
+						{Pocket: "published", UpstreamVersion: "570.195.03", ArchiveVersion: "570.172.08-0ubuntu0.22.04.1", Comparison: "behind", DeltaDaysSinceUpstream: &days},
+					},
+				},
+			},
+			Changelogs: map[string]*ChangelogEntry{
+				"570.172.08-0ubuntu0.22.04.1": {Package: "nvidia-graphics-drivers-570", Version: "570.172.08-0ubuntu0.22.04.1", Distribution: "jammy",
+					Urgency: "medium", Date: "Mon, 01 Sep 2026 10:00:00 +0000", Changes: []string{"New upstream release (LP: #2123456)"}, Bugs: []string{"2123456"}},
+			},
+			SLO:        &slo.Status{Branch: "570", TargetDays: 14, WindowDays: 90, State: "breached", Compliance: 0.5, Evaluated: 2, Met: 1},
+			StaleSince: &stale,
+		},
+		{
+			// Optional fields are left out of the wire format
+			PackageName: "nvidia-graphics-drivers-570-server",
+			Series:      []SeriesData{{Series: "noble", UpdatesSecurity: "N/A", Proposed: "N/A", Availability: "not-uploaded"}},
+		},
+	}
+
+	got, err := json.MarshalIndent(packages, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	golden := filepath.Join("testdata", "packages.golden.json")
+	if *updateGolden {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("missing golden file (run go test -update): %v", err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Errorf("PackageData JSON does not match %s:\n%s", golden, got)
+	}
+
+	// The golden file decodes back to the same packages
+	var decoded []PackageData
+	if err := json.Unmarshal(expected, &decoded); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if again, _ := json.MarshalIndent(decoded, "", "  "); !bytes.Equal(append(again, '\n'), expected) {
+		t.Errorf("%s does not round-trip", golden)
+	}
+}
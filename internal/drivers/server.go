@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/faults"
 	"nvidia_driver_monitor/internal/utils"
 )
 
@@ -32,8 +33,72 @@ type DriverInfo struct {
 	RunfileURL     map[string]string `json:"runfile_url"`
 }
 
+// Architectures of the datacenter releases; aarch64 is the arm64 (SBSA) build used on Grace
+const (
+	ArchitectureX86   = "x86_64"
+	ArchitectureARM64 = "aarch64"
+)
+
+// ArchRelease is the newest release of a branch for one architecture
+type ArchRelease struct {
+	Architecture string `json:"architecture"`
+	Version      string `json:"version"`
+	Date         string `json:"date"`
+}
+
+// BranchArchitectures is the per-architecture availability of a datacenter branch
+type BranchArchitectures struct {
+	Branch string        `json:"branch"`
+	Latest []ArchRelease `json:"latest"` // Ordered by architecture
+	// ARM64Lag is set when the newest x86_64 release has no arm64 (SBSA) build
+	ARM64Lag bool `json:"arm64_lag"`
+}
+
+// AvailableArchitectures returns the architectures a release is published for, falling back to
+// its runfiles when the architectures are not listed
+func (info DriverInfo) AvailableArchitectures() []string {
+	if len(info.Architectures) > 0 {
+		return info.Architectures
+	}
+	archs := make([]string, 0, len(info.RunfileURL))
+	for arch := range info.RunfileURL {
+		archs = append(archs, arch)
+	}
+	sort.Strings(archs)
+	return archs
+}
+
+// GetBranchArchitectures returns the newest release of each architecture of a branch, and
+// whether arm64 lags behind x86_64
+func GetBranchArchitectures(branch string, entry BranchEntry) BranchArchitectures {
+	latest := make(map[string]ArchRelease)
+	for _, info := range entry.DriverInfo {
+		for _, arch := range info.AvailableArchitectures() {
+			current, ok := latest[arch]
+			if !ok || compareVersionParts(parseVersionParts(info.ReleaseVersion), parseVersionParts(current.Version)) > 0 {
+				latest[arch] = ArchRelease{Architecture: arch, Version: info.ReleaseVersion, Date: info.ReleaseDate}
+			}
+		}
+	}
+
+	result := BranchArchitectures{Branch: branch, Latest: make([]ArchRelease, 0, len(latest))}
+	for _, release := range latest {
+		result.Latest = append(result.Latest, release)
+	}
+	sort.Slice(result.Latest, func(i, j int) bool { return result.Latest[i].Architecture < result.Latest[j].Architecture })
+
+	if x86, ok := latest[ArchitectureX86]; ok {
+		arm64, ok := latest[ArchitectureARM64]
+		result.ARM64Lag = !ok || compareVersionParts(parseVersionParts(arm64.Version), parseVersionParts(x86.Version)) < 0
+	}
+	return result
+}
+
 // GetLatestServerDriverVersions retrieves the latest server driver versions
 func GetLatestServerDriverVersions(cfg *config.Config) (map[string]DriverInfo, AllBranches, error) {
+	if err := faults.Check(faults.StageERD); err != nil {
+		return nil, nil, err
+	}
 	url := cfg.GetEffectiveURLs().NVIDIA.ServerDriversAPI
 	resp, err := utils.HTTPGetWithRetry(url)
 	if err != nil {
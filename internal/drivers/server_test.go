@@ -0,0 +1,62 @@
+package drivers
+
+import "testing"
+
+func TestGetBranchArchitectures(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    BranchEntry
+		latest   map[string]string
+		arm64Lag bool
+	}{
+		{
+			name: "both architectures current",
+			entry: BranchEntry{DriverInfo: []DriverInfo{
+				{ReleaseVersion: "570.172.08", ReleaseDate: "2025-07-17", Architectures: []string{"x86_64", "aarch64"}},
+				{ReleaseVersion: "570.158.01", ReleaseDate: "2025-06-02", Architectures: []string{"x86_64", "aarch64"}},
+			}},
+			latest: map[string]string{"x86_64": "570.172.08", "aarch64": "570.172.08"},
+		},
+		{
+			name: "arm64 one release behind",
+			entry: BranchEntry{DriverInfo: []DriverInfo{
+				{ReleaseVersion: "570.9.1", ReleaseDate: "2025-07-17", Architectures: []string{"x86_64"}},
+				{ReleaseVersion: "570.10.1", ReleaseDate: "2025-07-20", Architectures: []string{"x86_64"}},
+				{ReleaseVersion: "570.8.1", ReleaseDate: "2025-06-02", Architectures: []string{"x86_64", "aarch64", "ppc64le"}},
+			}},
+			latest:   map[string]string{"x86_64": "570.10.1", "aarch64": "570.8.1", "ppc64le": "570.8.1"},
+			arm64Lag: true,
+		},
+		{
+			name: "architectures taken from the runfiles",
+			entry: BranchEntry{DriverInfo: []DriverInfo{
+				{ReleaseVersion: "575.57.08", RunfileURL: map[string]string{"x86_64": "x.run"}},
+			}},
+			latest:   map[string]string{"x86_64": "575.57.08"},
+			arm64Lag: true,
+		},
+		{
+			name: "arm64 only",
+			entry: BranchEntry{DriverInfo: []DriverInfo{
+				{ReleaseVersion: "580.65.06", Architectures: []string{"aarch64"}},
+			}},
+			latest: map[string]string{"aarch64": "580.65.06"},
+		},
+	}
+
+	for _, test := range tests {
+		result := GetBranchArchitectures("570", test.entry)
+		if result.ARM64Lag != test.arm64Lag {
+			t.Errorf("%s: ARM64Lag = %v, expected %v", test.name, result.ARM64Lag, test.arm64Lag)
+		}
+		if len(result.Latest) != len(test.latest) {
+			t.Errorf("%s: Latest = %v, expected %v", test.name, result.Latest, test.latest)
+			continue
+		}
+		for _, release := range result.Latest {
+			if release.Version != test.latest[release.Architecture] {
+				t.Errorf("%s: latest %s = %s, expected %s", test.name, release.Architecture, release.Version, test.latest[release.Architecture])
+			}
+		}
+	}
+}
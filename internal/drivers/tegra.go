@@ -0,0 +1,108 @@
+package drivers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/faults"
+	"nvidia_driver_monitor/internal/utils"
+
+	"golang.org/x/net/html"
+)
+
+// tegraReleaseDir matches the L4T release directories of the listing, e.g. "r36.4/"
+var tegraReleaseDir = regexp.MustCompile(`^r(\d+\.\d+(?:\.\d+)?)/$`)
+
+// listingDate matches the modification time next to a directory of an Apache or nginx listing
+var listingDate = regexp.MustCompile(`\d{2}-[A-Z][a-z]{2}-\d{4} \d{2}:\d{2}|\d{4}-\d{2}-\d{2} \d{2}:\d{2}`)
+
+// TegraMajor returns the L4T major of a Tegra version, e.g. "36" for "36.4.3"
+func TegraMajor(version string) string {
+	return strings.SplitN(version, ".", 2)[0]
+}
+
+// GetTegraReleases retrieves the L4T (Tegra/Jetson) releases, newest first. Versions follow
+// the L4T scheme, e.g. "36.4", and are not comparable with the desktop driver versions.
+func GetTegraReleases(cfg *config.Config) ([]DriverEntry, error) {
+	if err := faults.Check(faults.StageTegra); err != nil {
+		return nil, err
+	}
+	url := ensureTrailingSlash(cfg.Tegra.GetReleasesURL())
+
+	resp, err := utils.HTTPGetWithRetry(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch L4T release index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	root, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse L4T release index HTML: %w", err)
+	}
+
+	entries := parseTegraListing(root)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no L4T releases found at %s", url)
+	}
+	return entries, nil
+}
+
+// parseTegraListing extracts the release directories and their dates from a listing
+func parseTegraListing(root *html.Node) []DriverEntry {
+	var entries []DriverEntry
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			if match := tegraReleaseDir.FindStringSubmatch(getAttr(n, "href")); match != nil {
+				entries = append(entries, DriverEntry{Version: match[1], Date: listingEntryDate(n)})
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	sort.Slice(entries, func(i, j int) bool {
+		return compareVersionParts(parseVersionParts(entries[i].Version), parseVersionParts(entries[j].Version)) > 0
+	})
+	return entries
+}
+
+// listingEntryDate returns the time following a link, either in the same preformatted line
+// or in a later cell of the same table row; zero when there is none
+func listingEntryDate(link *html.Node) time.Time {
+	var candidates []*html.Node
+	if link.NextSibling != nil && link.NextSibling.Type == html.TextNode {
+		candidates = append(candidates, link.NextSibling)
+	}
+	if parent := link.Parent; parent != nil && parent.Data == "td" {
+		for cell := parent.NextSibling; cell != nil; cell = cell.NextSibling {
+			candidates = append(candidates, cell)
+		}
+	}
+
+	for _, node := range candidates {
+		text := node.Data
+		if node.Type == html.ElementNode {
+			text = collectText(node)
+		}
+		// Only the first line belongs to this entry of a preformatted listing
+		text = strings.SplitN(text, "\n", 2)[0]
+		stamp := listingDate.FindString(text)
+		if stamp == "" {
+			continue
+		}
+		for _, layout := range []string{"02-Jan-2006 15:04", "2006-01-02 15:04"} {
+			if date, err := time.Parse(layout, stamp); err == nil {
+				return date
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,43 @@
+package drivers
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestParseTegraListing(t *testing.T) {
+	listing := `<html><body><h1>Index of /jetson/common/dists/</h1><hr><pre><a href="../">../</a>
+<a href="r35.6/">r35.6/</a>                                             18-Sep-2024 21:04                   -
+<a href="r36.4/">r36.4/</a>                                             10-Dec-2024 19:03                   -
+<a href="r36.3/">r36.3/</a>                                             29-Apr-2024 17:55                   -
+<a href="common/">common/</a>                                           01-Jan-2020 00:00                   -
+</pre><hr></body></html>`
+	root, err := html.Parse(strings.NewReader(listing))
+	if err != nil {
+		t.Fatalf("Failed to parse listing: %v", err)
+	}
+
+	entries := parseTegraListing(root)
+	expected := []struct {
+		version string
+		date    string
+	}{
+		{"36.4", "2024-12-10"},
+		{"36.3", "2024-04-29"},
+		{"35.6", "2024-09-18"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("parseTegraListing() returned %d entries, expected %d: %+v", len(entries), len(expected), entries)
+	}
+	for i, want := range expected {
+		if entries[i].Version != want.version || entries[i].Date.Format("2006-01-02") != want.date {
+			t.Errorf("parseTegraListing()[%d] = %s (%s), expected %s (%s)", i, entries[i].Version, entries[i].Date.Format("2006-01-02"), want.version, want.date)
+		}
+	}
+
+	if major := TegraMajor("36.4.3"); major != "36" {
+		t.Errorf("TegraMajor(36.4.3) = %s, expected 36", major)
+	}
+}
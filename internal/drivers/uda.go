@@ -2,7 +2,9 @@ package drivers
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"sort"
 	"strconv"
@@ -11,17 +13,15 @@ import (
 	"time"
 
 	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/domain"
+	"nvidia_driver_monitor/internal/faults"
 	"nvidia_driver_monitor/internal/utils"
 
 	"golang.org/x/net/html"
 )
 
 // DriverEntry represents a driver entry from NVIDIA's website
-type DriverEntry struct {
-	Version string
-	Date    time.Time
-	IsBeta  bool
-}
+type DriverEntry = domain.DriverEntry
 
 // PrintTableUDAReleases prints all DriverEntries in a table format to standard output
 func PrintTableUDAReleases(entries []DriverEntry) {
@@ -52,6 +52,9 @@ func LogTableUDAReleases(entries []DriverEntry) {
 // GetNvidiaDriverEntries retrieves driver entries from NVIDIA's website
 // branchMajors limits directory traversal to the supplied major versions (e.g. "580")
 func GetNvidiaDriverEntries(cfg *config.Config, branchMajors []string) ([]DriverEntry, error) {
+	if err := faults.Check(faults.StageUDA); err != nil {
+		return nil, err
+	}
 	baseURL := ensureTrailingSlash(cfg.URLs.NVIDIA.DriverArchiveURL)
 
 	resp, err := utils.HTTPGetWithRetry(baseURL)
@@ -149,7 +152,124 @@ func buildDriverEntry(baseURL, directory string) (*DriverEntry, error) {
 	version := strings.TrimSuffix(directory, "/")
 	isBeta := strings.Contains(strings.ToLower(version), "beta")
 
-	return &DriverEntry{Version: version, Date: licenseDate, IsBeta: isBeta}, nil
+	entry := &DriverEntry{Version: version, Date: licenseDate, IsBeta: isBeta}
+	installer, size, checksumFile := findInstaller(root, version)
+	if installer != "" {
+		entry.Installer = installer
+		entry.InstallerURL = dirURL + installer
+		entry.InstallerSize = size
+	}
+	if checksumFile != "" {
+		digest, err := fetchSHA256(dirURL + checksumFile)
+		if err != nil {
+			log.Printf("failed to fetch installer checksum of %s: %v", version, err)
+		}
+		entry.InstallerSHA256 = digest
+	}
+	return entry, nil
+}
+
+// findInstaller returns the .run installer of a version directory, e.g.
+// "NVIDIA-Linux-x86_64-570.172.08.run", its listed size and the .sha256sum file published next
+// to it, if any
+func findInstaller(root *html.Node, version string) (installer string, size int64, checksumFile string) {
+	files := make(map[string]*html.Node)
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "span" && getAttr(n, "class") == "file" {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && c.Data == "a" {
+					files[getAttr(c, "href")] = n
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	for name, node := range files {
+		if !strings.HasPrefix(name, "NVIDIA-Linux-") || !strings.HasSuffix(name, "-"+version+".run") {
+			continue
+		}
+		installer = name
+		if sizeNode := findSiblingSize(node); sizeNode != nil {
+			size = parseListingSize(collectText(sizeNode))
+		}
+		break
+	}
+	if installer == "" {
+		return "", 0, ""
+	}
+	for _, name := range []string{installer + ".sha256sum", installer + ".sha256"} {
+		if _, ok := files[name]; ok {
+			return installer, size, name
+		}
+	}
+	return installer, size, ""
+}
+
+// parseListingSize parses a size of the directory listing, either in bytes or with a binary
+// suffix such as "358M" or "358.2 MB". Suffixed sizes are rounded by the listing. Returns 0 when
+// the size cannot be parsed.
+func parseListingSize(text string) int64 {
+	text = strings.TrimSpace(text)
+	number := strings.TrimRight(text, "KMGTBikmgtb ")
+	unit := strings.ToUpper(strings.TrimSpace(text[len(number):]))
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0
+	}
+	multiplier := float64(1)
+	if unit != "" {
+		switch unit[0] {
+		case 'B':
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		case 'T':
+			multiplier = 1 << 40
+		}
+	}
+	return int64(value * multiplier)
+}
+
+// fetchSHA256 returns the digest of a sha256sum(1) style checksum file
+func fetchSHA256(url string) (string, error) {
+	resp, err := utils.HTTPGetWithRetry(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 || !isSHA256(fields[0]) {
+		return "", fmt.Errorf("no SHA256 digest in %s", url)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+func isSHA256(digest string) bool {
+	if len(digest) != 64 {
+		return false
+	}
+	for _, r := range strings.ToLower(digest) {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
 }
 
 func findLicenseDate(root *html.Node) (time.Time, error) {
@@ -235,6 +355,23 @@ func findSiblingDate(node *html.Node) *html.Node {
 	return nil
 }
 
+// findSiblingSize returns the size span of a file span, stopping at the next listed entry so
+// that a file without a size does not get the one of the following file
+func findSiblingSize(node *html.Node) *html.Node {
+	for sibling := node.NextSibling; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode || sibling.Data != "span" {
+			continue
+		}
+		switch getAttr(sibling, "class") {
+		case "size":
+			return sibling
+		case "file", "dir":
+			return nil
+		}
+	}
+	return nil
+}
+
 func collectText(n *html.Node) string {
 	var b strings.Builder
 
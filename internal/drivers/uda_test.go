@@ -0,0 +1,69 @@
+package drivers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBuildDriverEntryInstaller(t *testing.T) {
+	const digest = "5f1c3a0c0e2f4b7b9d8e6a1f2c3b4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/580.82.09/":
+			w.Write([]byte(`<html><body><pre>
+<span class="dir"><a href="../">../</a></span>
+<span class="file"><a href="license.txt">license.txt</a></span> <span class="size">24K</span> <span class="date">2025-09-09 17:12</span>
+<span class="file"><a href="NVIDIA-Linux-x86_64-580.82.09.run">NVIDIA-Linux-x86_64-580.82.09.run</a></span> <span class="size">384512301</span> <span class="date">2025-09-09 17:12</span>
+<span class="file"><a href="NVIDIA-Linux-x86_64-580.82.09.run.sha256sum">NVIDIA-Linux-x86_64-580.82.09.run.sha256sum</a></span> <span class="size">100</span> <span class="date">2025-09-09 17:12</span>
+</pre></body></html>`))
+		case "/580.82.09/NVIDIA-Linux-x86_64-580.82.09.run.sha256sum":
+			w.Write([]byte(digest + "  NVIDIA-Linux-x86_64-580.82.09.run\n"))
+		case "/575.51.02/":
+			w.Write([]byte(`<html><body><pre>
+<span class="file"><a href="license.txt">license.txt</a></span> <span class="date">2025-04-15 10:00</span>
+<span class="file"><a href="NVIDIA-Linux-x86_64-575.51.02.run">NVIDIA-Linux-x86_64-575.51.02.run</a></span> <span class="date">2025-04-15 10:00</span>
+<span class="file"><a href="README.txt">README.txt</a></span> <span class="size">1M</span> <span class="date">2025-04-15 10:00</span>
+</pre></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	entry, err := buildDriverEntry(server.URL+"/", "580.82.09/")
+	if err != nil {
+		t.Fatalf("buildDriverEntry() failed: %v", err)
+	}
+	if entry.Installer != "NVIDIA-Linux-x86_64-580.82.09.run" || entry.InstallerURL != server.URL+"/580.82.09/NVIDIA-Linux-x86_64-580.82.09.run" {
+		t.Errorf("Installer = %q (%q), expected the x86_64 .run", entry.Installer, entry.InstallerURL)
+	}
+	if entry.InstallerSize != 384512301 || entry.InstallerSHA256 != digest {
+		t.Errorf("InstallerSize = %d, InstallerSHA256 = %q, expected 384512301 and the published digest", entry.InstallerSize, entry.InstallerSHA256)
+	}
+
+	// Without a size or a checksum file, only the installer is known
+	entry, err = buildDriverEntry(server.URL+"/", "575.51.02/")
+	if err != nil {
+		t.Fatalf("buildDriverEntry() failed: %v", err)
+	}
+	if entry.Installer != "NVIDIA-Linux-x86_64-575.51.02.run" || entry.InstallerSize != 0 || entry.InstallerSHA256 != "" {
+		t.Errorf("entry = %+v, expected the installer without size or checksum", entry)
+	}
+}
+
+func TestParseListingSize(t *testing.T) {
+	tests := map[string]int64{
+		"384512301": 384512301,
+		"358M":      358 << 20,
+		"1.5 GB":    3 << 29,
+		"24K":       24 << 10,
+		"-":         0,
+		"":          0,
+	}
+	for text, expected := range tests {
+		if size := parseListingSize(text); size != expected {
+			t.Errorf("parseListingSize(%q) = %d, expected %d", text, size, expected)
+		}
+	}
+}
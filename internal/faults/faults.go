@@ -0,0 +1,162 @@
+// Package faults injects failures into the fetch stages of the refresh pipeline, so that tests
+// and testing deployments can exercise its failure paths on demand: a stage either fails at
+// once or hangs until the fault is cleared, as a stuck upstream would. Injection is disabled
+// unless testing mode or a test enables it, and checking a stage is then a no-op.
+package faults
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fetch stages faults can be injected into
+const (
+	StageUDA      = "uda"      // nvidia.com driver releases
+	StageERD      = "erd"      // Datacenter driver releases
+	StageSRU      = "sru"      // SRU cycles
+	StageTegra    = "tegra"    // L4T releases
+	StagePackages = "packages" // Launchpad versions, checked for every package
+	StageLRM      = "lrm"      // L-R-M verification data
+)
+
+// Stages lists the stages faults can be injected into
+func Stages() []string {
+	return []string{StageUDA, StageERD, StageSRU, StageTegra, StagePackages, StageLRM}
+}
+
+// Fault modes
+const (
+	ModeFail = "fail" // The stage returns an error at once
+	ModeHang = "hang" // The stage blocks until the fault is cleared, then returns an error
+)
+
+// ErrInjected is wrapped by the errors of stages failing from an injected fault
+var ErrInjected = errors.New("injected fault")
+
+// Fault is a fault injected into a stage
+type Fault struct {
+	Stage      string    `json:"stage"`
+	Mode       string    `json:"mode"`
+	InjectedAt time.Time `json:"injected_at"`
+	Hits       int       `json:"hits"`    // Times the stage ran into the fault
+	Waiting    int       `json:"waiting"` // Stage runs blocked by a hang right now
+}
+
+// fault is an active fault; released is closed when it is cleared, letting hung stages return
+type fault struct {
+	Fault
+	released chan struct{}
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	active  = make(map[string]*fault)
+)
+
+// Enable turns injection on or off. Turning it off clears every fault.
+func Enable(on bool) {
+	mu.Lock()
+	enabled = on
+	mu.Unlock()
+	if !on {
+		Reset()
+	}
+}
+
+// Enabled reports whether faults can be injected
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Inject makes a stage fail or hang until the fault is cleared, replacing any fault of the
+// stage. It fails when injection is disabled or the stage or mode is unknown.
+func Inject(stage, mode string) error {
+	if !validStage(stage) {
+		return fmt.Errorf("unknown stage %q", stage)
+	}
+	if mode != ModeFail && mode != ModeHang {
+		return fmt.Errorf("unknown mode %q, expected %s or %s", mode, ModeFail, ModeHang)
+	}
+	mu.Lock()
+	if !enabled {
+		mu.Unlock()
+		return fmt.Errorf("fault injection is disabled")
+	}
+	previous := active[stage]
+	active[stage] = &fault{Fault: Fault{Stage: stage, Mode: mode, InjectedAt: time.Now()}, released: make(chan struct{})}
+	mu.Unlock()
+	if previous != nil {
+		close(previous.released)
+	}
+	return nil
+}
+
+// Clear removes the fault of a stage, letting its hung runs return
+func Clear(stage string) {
+	mu.Lock()
+	f := active[stage]
+	delete(active, stage)
+	mu.Unlock()
+	if f != nil {
+		close(f.released)
+	}
+}
+
+// Reset clears every fault
+func Reset() {
+	for _, f := range Active() {
+		Clear(f.Stage)
+	}
+}
+
+// Active returns the injected faults ordered by stage
+func Active() []Fault {
+	mu.Lock()
+	defer mu.Unlock()
+	list := make([]Fault, 0, len(active))
+	for _, f := range active {
+		list = append(list, f.Fault)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Stage < list[j].Stage })
+	return list
+}
+
+// Check runs a stage into its fault, if any: it returns an error wrapping ErrInjected at once
+// for a failing stage, or once the fault is cleared for a hanging one. It returns nil when
+// the stage has no fault.
+func Check(stage string) error {
+	mu.Lock()
+	f, ok := active[stage]
+	if !ok {
+		mu.Unlock()
+		return nil
+	}
+	f.Hits++
+	if f.Mode == ModeFail {
+		mu.Unlock()
+		return fmt.Errorf("%w: %s failed", ErrInjected, stage)
+	}
+	f.Waiting++
+	mu.Unlock()
+
+	<-f.released
+	mu.Lock()
+	f.Waiting--
+	mu.Unlock()
+	return fmt.Errorf("%w: %s hung", ErrInjected, stage)
+}
+
+func validStage(stage string) bool {
+	for _, known := range Stages() {
+		if stage == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package faults
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInjectRequiresEnabling(t *testing.T) {
+	Enable(false)
+	if err := Inject(StageUDA, ModeFail); err == nil {
+		t.Error("Inject succeeded with injection disabled")
+	}
+	if err := Check(StageUDA); err != nil {
+		t.Errorf("Check with injection disabled = %v, expected nil", err)
+	}
+
+	Enable(true)
+	defer Enable(false)
+	if err := Inject("launchpad", ModeFail); err == nil {
+		t.Error("Inject accepted an unknown stage")
+	}
+	if err := Inject(StageUDA, "slow"); err == nil {
+		t.Error("Inject accepted an unknown mode")
+	}
+}
+
+func TestFailingStage(t *testing.T) {
+	Enable(true)
+	defer Enable(false)
+
+	if err := Inject(StageERD, ModeFail); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := Check(StageERD); !errors.Is(err, ErrInjected) {
+			t.Errorf("Check(erd) = %v, expected an injected failure", err)
+		}
+	}
+	if err := Check(StageSRU); err != nil {
+		t.Errorf("Check(sru) = %v, expected no fault in another stage", err)
+	}
+	if active := Active(); len(active) != 1 || active[0].Hits != 2 {
+		t.Errorf("Active() = %+v, expected erd hit twice", active)
+	}
+
+	Clear(StageERD)
+	if err := Check(StageERD); err != nil {
+		t.Errorf("Check(erd) after Clear = %v, expected nil", err)
+	}
+}
+
+func TestHangingStageReturnsOnceCleared(t *testing.T) {
+	Enable(true)
+	defer Enable(false)
+
+	if err := Inject(StageLRM, ModeHang); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	done := make(chan error)
+	go func() { done <- Check(StageLRM) }()
+
+	waitFor(t, func() bool { return len(Active()) == 1 && Active()[0].Waiting == 1 })
+	select {
+	case err := <-done:
+		t.Fatalf("Check(lrm) returned %v before the fault was cleared", err)
+	default:
+	}
+
+	// Disabling injection releases hung stages too
+	Enable(false)
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrInjected) {
+			t.Errorf("Check(lrm) = %v, expected an injected failure", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Check(lrm) still hangs after injection was disabled")
+	}
+}
+
+// waitFor polls condition until it holds, failing the test after a second
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within a second")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
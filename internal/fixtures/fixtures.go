@@ -0,0 +1,108 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/releases"
+)
+
+// Fixture is an upstream endpoint captured into a file of the mock server data directory
+type Fixture struct {
+	URL  string
+	Path string // Relative to the data directory, e.g. "launchpad/sources/nvidia-graphics-drivers-570.json"
+}
+
+// volatileFields change on every request without carrying data the monitor uses
+var volatileFields = map[string]bool{
+	"http_etag":            true,
+	"next_collection_link": true,
+	"prev_collection_link": true,
+	"total_size_link":      true,
+}
+
+// anonymizedPersonLink replaces links to Launchpad people (creators, signers, sponsors)
+const anonymizedPersonLink = "https://api.launchpad.net/devel/~anonymized"
+
+// Targets lists the fixtures served by the mock server for the given supported releases
+func Targets(cfg *config.Config, supportedReleases []releases.SupportedRelease) []Fixture {
+	urls := cfg.URLs
+	fixtures := []Fixture{
+		{URL: urls.NVIDIA.ServerDriversAPI, Path: "nvidia/server-drivers.json"},
+		{URL: urls.NVIDIA.DriverArchiveURL, Path: "nvidia/driver-archive.html"},
+		{URL: urls.Kernel.SeriesYAMLURL, Path: "kernel/series.yaml"},
+		{URL: urls.Kernel.SRUCycleURL, Path: "kernel/sru-cycle.yaml"},
+	}
+	if cfg.Tegra.Enabled {
+		fixtures = append(fixtures, Fixture{URL: cfg.Tegra.GetReleasesURL(), Path: "nvidia/tegra-releases.html"})
+	}
+	for _, rel := range supportedReleases {
+		packageName := rel.PackageName()
+		fixtures = append(fixtures, Fixture{
+			URL:  urls.Launchpad.GetPublishedSourcesURL(packageName),
+			Path: fmt.Sprintf("launchpad/sources/%s.json", packageName),
+		})
+	}
+	return fixtures
+}
+
+// Normalize makes a captured response reviewable: JSON is stripped of volatile fields, person
+// links are anonymized, timestamps are rewritten in UTC at second precision and keys are sorted.
+// Non-JSON content is returned with normalized line endings only.
+func Normalize(path string, data []byte) ([]byte, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if !strings.HasSuffix(path, ".json") {
+		return data, nil
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	normalized, err := json.MarshalIndent(normalizeValue(value), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return append(normalized, '\n'), nil
+}
+
+// normalizeValue walks decoded JSON applying the normalization rules
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if volatileFields[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = normalizeValue(field)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeValue(item)
+		}
+		return v
+	case string:
+		return normalizeString(v)
+	}
+	return value
+}
+
+// normalizeString anonymizes person links and canonicalizes timestamps
+func normalizeString(s string) string {
+	if strings.Contains(s, "launchpad.net/") && strings.Contains(s, "/~") {
+		return anonymizedPersonLink
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC().Truncate(time.Second).Format(time.RFC3339)
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package fixtures
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeJSON(t *testing.T) {
+	input := `{
+		"total_size": 2,
+		"next_collection_link": "https://api.launchpad.net/devel/ubuntu/+archive/primary?ws.start=75",
+		"entries": [{
+			"http_etag": "\"abc-123\"",
+			"source_package_version": "570.195.03-0ubuntu0.24.04.1",
+			"date_published": "2026-10-01T08:30:12.345678+02:00",
+			"package_signer_link": "https://api.launchpad.net/devel/~someone"
+		}]
+	}`
+
+	output, err := Normalize("launchpad/sources/nvidia-graphics-drivers-570.json", []byte(input))
+	if err != nil {
+		t.Fatalf("Normalize returned error: %v", err)
+	}
+
+	result := string(output)
+	for _, unexpected := range []string{"http_etag", "next_collection_link", "~someone", ".345678"} {
+		if strings.Contains(result, unexpected) {
+			t.Errorf("Normalize output should not contain %s:\n%s", unexpected, result)
+		}
+	}
+	for _, expected := range []string{
+		`"date_published": "2026-10-01T06:30:12Z"`,
+		`"package_signer_link": "` + anonymizedPersonLink + `"`,
+		`"total_size": 2`,
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Normalize output should contain %s:\n%s", expected, result)
+		}
+	}
+
+	// Normalizing twice must be stable so unchanged upstream data produces no diff
+	again, err := Normalize("launchpad/sources/nvidia-graphics-drivers-570.json", output)
+	if err != nil || string(again) != result {
+		t.Errorf("Normalize is not idempotent: %v\n%s", err, again)
+	}
+}
+
+func TestNormalizeLeavesNonJSONContent(t *testing.T) {
+	output, err := Normalize("kernel/series.yaml", []byte("noble:\r\n  codename: noble\r\n"))
+	if err != nil {
+		t.Fatalf("Normalize returned error: %v", err)
+	}
+	if string(output) != "noble:\n  codename: noble\n" {
+		t.Errorf("Normalize(yaml) = %q, expected line endings normalized only", output)
+	}
+}
@@ -0,0 +1,206 @@
+package fleet
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/atomicfile"
+	"nvidia_driver_monitor/internal/hostcheck"
+)
+
+// hostnamePattern restricts reported hostnames to DNS-like names
+var hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,252}$`)
+
+// HostRecord is the latest host-check report received from a host
+type HostRecord struct {
+	hostcheck.Report
+	ReceivedAt time.Time `json:"received_at"`
+	Stale      bool      `json:"stale"`
+}
+
+// VersionCount is the number of active hosts running a driver version
+type VersionCount struct {
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	Hosts   int    `json:"hosts"`
+}
+
+// SeriesSummary aggregates active hosts of a series by installed driver version
+type SeriesSummary struct {
+	Series        string         `json:"series"`
+	TotalHosts    int            `json:"total_hosts"`
+	UpToDateHosts int            `json:"up_to_date_hosts"`
+	Versions      []VersionCount `json:"versions"`
+}
+
+// Summary is the fleet-wide compliance overview
+type Summary struct {
+	TotalHosts  int             `json:"total_hosts"`
+	ActiveHosts int             `json:"active_hosts"`
+	StaleHosts  []*HostRecord   `json:"stale_hosts"`
+	Series      []SeriesSummary `json:"series"`
+	StaleAfter  string          `json:"stale_after"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// SchemaVersion is the version of the format of the fleet reports file, bumped when it changes
+const SchemaVersion = 1
+
+// Store keeps the latest report per host and persists them to disk
+type Store struct {
+	mu          sync.RWMutex
+	hosts       map[string]*HostRecord
+	persistFile string
+	staleAfter  time.Duration
+}
+
+// NewStore creates a store, loading previously persisted reports if available
+func NewStore(persistFile string, staleAfter time.Duration) *Store {
+	s := &Store{
+		hosts:       make(map[string]*HostRecord),
+		persistFile: persistFile,
+		staleAfter:  staleAfter,
+	}
+	if err := s.loadFromFile(); err != nil {
+		log.Printf("Warning: Could not load existing fleet data: %v", err)
+	}
+	return s
+}
+
+// Record validates and stores a host report, replacing any previous report from the same host
+func (s *Store) Record(report *hostcheck.Report) error {
+	if report == nil || !hostnamePattern.MatchString(report.Hostname) {
+		return fmt.Errorf("invalid or missing hostname")
+	}
+
+	s.mu.Lock()
+	s.hosts[report.Hostname] = &HostRecord{
+		Report:     *report,
+		ReceivedAt: time.Now(),
+	}
+	s.mu.Unlock()
+
+	if err := s.saveToFile(); err != nil {
+		log.Printf("Warning: Failed to persist fleet data: %v", err)
+	}
+	return nil
+}
+
+// Hosts returns all known hosts sorted by hostname, with staleness evaluated at now
+func (s *Store) Hosts(now time.Time) []*HostRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hosts := make([]*HostRecord, 0, len(s.hosts))
+	for _, record := range s.hosts {
+		copied := *record
+		copied.Stale = now.Sub(record.ReceivedAt) > s.staleAfter
+		hosts = append(hosts, &copied)
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Hostname < hosts[j].Hostname })
+	return hosts
+}
+
+// Summary aggregates active hosts per series and driver version; stale hosts are listed separately
+func (s *Store) Summary(now time.Time) *Summary {
+	hosts := s.Hosts(now)
+	summary := &Summary{
+		TotalHosts:  len(hosts),
+		StaleHosts:  make([]*HostRecord, 0),
+		StaleAfter:  s.staleAfter.String(),
+		GeneratedAt: now,
+	}
+
+	bySeries := make(map[string]*SeriesSummary)
+	versionIndex := make(map[string]map[string]int)
+	for _, host := range hosts {
+		if host.Stale {
+			summary.StaleHosts = append(summary.StaleHosts, host)
+			continue
+		}
+		summary.ActiveHosts++
+
+		series := host.Series
+		if series == "" {
+			series = "unknown"
+		}
+		ss, ok := bySeries[series]
+		if !ok {
+			ss = &SeriesSummary{Series: series}
+			bySeries[series] = ss
+			versionIndex[series] = make(map[string]int)
+		}
+		ss.TotalHosts++
+		if host.UpToDate {
+			ss.UpToDateHosts++
+		}
+
+		ver := host.InstalledVersion
+		if ver == "" {
+			ver = "none"
+		}
+		if i, ok := versionIndex[series][ver]; ok {
+			ss.Versions[i].Hosts++
+		} else {
+			versionIndex[series][ver] = len(ss.Versions)
+			ss.Versions = append(ss.Versions, VersionCount{Version: ver, Status: host.Status, Hosts: 1})
+		}
+	}
+
+	for _, ss := range bySeries {
+		sort.Slice(ss.Versions, func(i, j int) bool {
+			if ss.Versions[i].Hosts != ss.Versions[j].Hosts {
+				return ss.Versions[i].Hosts > ss.Versions[j].Hosts
+			}
+			return ss.Versions[i].Version < ss.Versions[j].Version
+		})
+		summary.Series = append(summary.Series, *ss)
+	}
+	sort.Slice(summary.Series, func(i, j int) bool { return summary.Series[i].Series < summary.Series[j].Series })
+
+	return summary
+}
+
+// saveToFile writes all host records to the persistence file
+func (s *Store) saveToFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return atomicfile.WriteJSON(s.persistFile, s.hosts)
+}
+
+// loadFromFile restores host records from the persistence file
+func (s *Store) loadFromFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	jsonData, err := os.ReadFile(s.persistFile)
+	if os.IsNotExist(err) {
+		return nil // No existing data, start fresh
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read fleet file: %w", err)
+	}
+
+	hosts := make(map[string]*HostRecord)
+	if err := json.Unmarshal(jsonData, &hosts); err != nil {
+		return fmt.Errorf("failed to parse fleet JSON: %w", err)
+	}
+
+	s.mu.Lock()
+	s.hosts = hosts
+	s.mu.Unlock()
+
+	log.Printf("Loaded %d fleet hosts from %s", len(hosts), s.persistFile)
+	return nil
+}
@@ -0,0 +1,80 @@
+package fleet
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/hostcheck"
+)
+
+func newReport(hostname, series, installed, status string) *hostcheck.Report {
+	return &hostcheck.Report{
+		HostInfo:         hostcheck.HostInfo{Hostname: hostname, Series: series},
+		InstalledVersion: installed,
+		Status:           status,
+		UpToDate:         status == hostcheck.StatusUpToDate,
+	}
+}
+
+func TestStoreSummary(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "fleet.json"), time.Hour)
+
+	reports := []*hostcheck.Report{
+		newReport("gpu-01", "noble", "570.153.02-0ubuntu0.24.04.1", hostcheck.StatusUpToDate),
+		newReport("gpu-02", "noble", "570.153.02-0ubuntu0.24.04.1", hostcheck.StatusUpToDate),
+		newReport("gpu-03", "noble", "570.133.07-0ubuntu0.24.04.1", hostcheck.StatusOutdated),
+		newReport("gpu-04", "jammy", "550.163.01-0ubuntu0.22.04.1", hostcheck.StatusUpToDate),
+	}
+	for _, report := range reports {
+		if err := store.Record(report); err != nil {
+			t.Fatalf("Record(%s) returned error: %v", report.Hostname, err)
+		}
+	}
+
+	summary := store.Summary(time.Now())
+	if summary.TotalHosts != 4 || summary.ActiveHosts != 4 {
+		t.Errorf("Summary() hosts = %d/%d, expected 4/4", summary.ActiveHosts, summary.TotalHosts)
+	}
+	if len(summary.Series) != 2 || summary.Series[1].Series != "noble" {
+		t.Fatalf("Summary() series = %+v, expected jammy and noble", summary.Series)
+	}
+
+	noble := summary.Series[1]
+	if noble.TotalHosts != 3 || noble.UpToDateHosts != 2 {
+		t.Errorf("noble hosts = %d up to date of %d, expected 2 of 3", noble.UpToDateHosts, noble.TotalHosts)
+	}
+	if len(noble.Versions) != 2 || noble.Versions[0].Hosts != 2 {
+		t.Errorf("noble versions = %+v, expected most common version first", noble.Versions)
+	}
+
+	// Hosts that have not reported within the stale window are excluded from counts
+	stale := store.Summary(time.Now().Add(2 * time.Hour))
+	if stale.ActiveHosts != 0 || len(stale.StaleHosts) != 4 {
+		t.Errorf("Summary(+2h) = %d active, %d stale, expected 0 active, 4 stale", stale.ActiveHosts, len(stale.StaleHosts))
+	}
+}
+
+func TestStoreRejectsInvalidHostname(t *testing.T) {
+	store := NewStore("", time.Hour)
+
+	for _, hostname := range []string{"", "bad host", "<script>"} {
+		if err := store.Record(newReport(hostname, "noble", "1.0", hostcheck.StatusUnknown)); err == nil {
+			t.Errorf("Record(%q) succeeded, expected error", hostname)
+		}
+	}
+}
+
+func TestStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fleet.json")
+
+	store := NewStore(path, time.Hour)
+	if err := store.Record(newReport("gpu-01", "noble", "1.0", hostcheck.StatusUpToDate)); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+
+	reloaded := NewStore(path, time.Hour)
+	if hosts := reloaded.Hosts(time.Now()); len(hosts) != 1 || hosts[0].Hostname != "gpu-01" {
+		t.Errorf("reloaded Hosts() = %+v, expected gpu-01", hosts)
+	}
+}
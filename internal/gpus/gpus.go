@@ -0,0 +1,294 @@
+package gpus
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pciIDPattern matches a vendor:device PCI ID such as "10de:1eb8"
+var pciIDPattern = regexp.MustCompile(`^[0-9a-f]{4}:[0-9a-f]{4}$`)
+
+// Device maps GPU models to the driver branch they need. Branch is the legacy branch for GPUs
+// that newer drivers dropped, otherwise the branch the deployment runs on that hardware.
+type Device struct {
+	PCIIDs []string `json:"pci_ids,omitempty"` // e.g. "10de:1eb8"
+	Models []string `json:"models,omitempty"`  // Model names as reported, e.g. "Tesla T4"
+	Branch string   `json:"branch"`            // e.g. "535" or "470"
+	Legacy bool     `json:"legacy,omitempty"`  // Branch is the last one supporting the device
+	Note   string   `json:"note,omitempty"`
+}
+
+// Table is the device-to-branch table; the first device matching a GPU wins
+type Table []Device
+
+// LoadTable reads the device-to-branch table JSON file
+func LoadTable(path string) (Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GPU branch table %s: %w", path, err)
+	}
+	return ParseTable(data)
+}
+
+// ParseTable decodes a device-to-branch table, normalizing its PCI IDs
+func ParseTable(data []byte) (Table, error) {
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal GPU branch table: %w", err)
+	}
+	for i := range table {
+		if table[i].Branch == "" {
+			return nil, fmt.Errorf("GPU branch table entry %d has no branch", i+1)
+		}
+		for j, id := range table[i].PCIIDs {
+			normalized, err := NormalizePCIID(id)
+			if err != nil {
+				return nil, fmt.Errorf("GPU branch table entry %d: %w", i+1, err)
+			}
+			table[i].PCIIDs[j] = normalized
+		}
+	}
+	return table, nil
+}
+
+// Match returns the device of a GPU, by PCI ID first and then by case-insensitive model name
+func (t Table) Match(gpu GPU) (*Device, bool) {
+	if gpu.PCIID != "" {
+		for i := range t {
+			for _, id := range t[i].PCIIDs {
+				if id == gpu.PCIID {
+					return &t[i], true
+				}
+			}
+		}
+	}
+	if gpu.Model != "" {
+		for i := range t {
+			for _, model := range t[i].Models {
+				if strings.EqualFold(model, gpu.Model) {
+					return &t[i], true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// NormalizePCIID lowercases a vendor:device PCI ID, accepting "0x" prefixes, the brackets of
+// lspci -nn and trailing text such as a revision
+func NormalizePCIID(id string) (string, error) {
+	normalized := strings.ToLower(strings.TrimLeft(strings.TrimSpace(id), "["))
+	normalized = strings.ReplaceAll(normalized, "0x", "")
+	if len(normalized) > 9 {
+		normalized = normalized[:9]
+	}
+	if !pciIDPattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid PCI ID %q, expected vendor:device such as 10de:1eb8", id)
+	}
+	return normalized, nil
+}
+
+// GPU is one line of an inventory: a GPU model and how many of it the fleet has
+type GPU struct {
+	PCIID string `json:"pci_id,omitempty"`
+	Model string `json:"model,omitempty"`
+	Count int    `json:"count"`
+}
+
+// Normalize validates a GPU and fills its defaults: a count of one and a lowercase PCI ID
+func (g *GPU) Normalize() error {
+	g.Model = strings.TrimSpace(g.Model)
+	if g.PCIID != "" {
+		id, err := NormalizePCIID(g.PCIID)
+		if err != nil {
+			return err
+		}
+		g.PCIID = id
+	}
+	if g.PCIID == "" && g.Model == "" {
+		return errors.New("a PCI ID or a model is required")
+	}
+	if g.Count == 0 {
+		g.Count = 1
+	}
+	if g.Count < 0 {
+		return fmt.Errorf("count must be positive, got %d", g.Count)
+	}
+	return nil
+}
+
+// ParseCSV reads an inventory from CSV with a header naming the pci_id, model and count
+// columns (in any order; count is optional). Rows of the same GPU are added up.
+func ParseCSV(r io.Reader) ([]GPU, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := map[string]int{"pci_id": -1, "model": -1, "count": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	if columns["pci_id"] < 0 && columns["model"] < 0 {
+		return nil, errors.New("CSV header must name a pci_id or model column")
+	}
+
+	field := func(record []string, column string) string {
+		if i := columns[column]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	var gpus []GPU
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		gpu := GPU{PCIID: field(record, "pci_id"), Model: field(record, "model")}
+		if gpu.PCIID == "" && gpu.Model == "" {
+			continue // Blank line
+		}
+		if count := field(record, "count"); count != "" {
+			if gpu.Count, err = strconv.Atoi(count); err != nil || gpu.Count <= 0 {
+				return nil, fmt.Errorf("line %d: count must be a positive integer, got %q", line, count)
+			}
+		}
+		if err := gpu.Normalize(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		gpus = append(gpus, gpu)
+	}
+	return Merge(gpus), nil
+}
+
+// Merge adds up the counts of identical GPUs, keeping their first-seen order
+func Merge(gpus []GPU) []GPU {
+	index := make(map[string]int)
+	var merged []GPU
+	for _, gpu := range gpus {
+		key := gpu.PCIID + "|" + strings.ToLower(gpu.Model)
+		if i, ok := index[key]; ok {
+			merged[i].Count += gpu.Count
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, gpu)
+	}
+	return merged
+}
+
+// ModelCount is how many GPUs of one model the inventories hold
+type ModelCount struct {
+	Model string `json:"model,omitempty"`
+	PCIID string `json:"pci_id,omitempty"`
+	GPUs  int    `json:"gpus"`
+}
+
+// BranchNeed groups the GPU models that need one driver branch
+type BranchNeed struct {
+	Branch string       `json:"branch"`
+	Legacy bool         `json:"legacy,omitempty"`
+	Models []ModelCount `json:"models"`
+	GPUs   int          `json:"gpus"`
+}
+
+// Summary maps the GPUs of all inventories to the branches they need
+type Summary struct {
+	Branches    []BranchNeed `json:"branches"`    // Most models first, then most GPUs
+	Unmapped    []ModelCount `json:"unmapped"`    // GPUs no table entry matches
+	Inventories []string     `json:"inventories"` // Names of the summarized inventories
+	GPUs        int          `json:"gpus"`
+}
+
+// Summarize maps every GPU of the inventories through the table
+func Summarize(inventories []Inventory, table Table) Summary {
+	summary := Summary{Branches: []BranchNeed{}, Unmapped: []ModelCount{}, Inventories: []string{}}
+	needs := make(map[string]*BranchNeed)
+	models := make(map[string]map[string]int) // Branch to model key to index in Models
+	unmapped := make(map[string]int)
+
+	for _, inventory := range inventories {
+		summary.Inventories = append(summary.Inventories, inventory.Name)
+		for _, gpu := range inventory.GPUs {
+			summary.GPUs += gpu.Count
+			device, ok := table.Match(gpu)
+			if !ok {
+				key := gpu.PCIID + "|" + strings.ToLower(gpu.Model)
+				if i, ok := unmapped[key]; ok {
+					summary.Unmapped[i].GPUs += gpu.Count
+				} else {
+					unmapped[key] = len(summary.Unmapped)
+					summary.Unmapped = append(summary.Unmapped, ModelCount{Model: gpu.Model, PCIID: gpu.PCIID, GPUs: gpu.Count})
+				}
+				continue
+			}
+
+			need, ok := needs[device.Branch]
+			if !ok {
+				need = &BranchNeed{Branch: device.Branch}
+				needs[device.Branch] = need
+				models[device.Branch] = make(map[string]int)
+			}
+			need.Legacy = need.Legacy || device.Legacy
+			need.GPUs += gpu.Count
+
+			model := canonicalModel(device, gpu.Model)
+			key := strings.ToLower(model)
+			if model == "" {
+				key = gpu.PCIID
+			}
+			if i, ok := models[device.Branch][key]; ok {
+				need.Models[i].GPUs += gpu.Count
+			} else {
+				models[device.Branch][key] = len(need.Models)
+				need.Models = append(need.Models, ModelCount{Model: model, PCIID: gpu.PCIID, GPUs: gpu.Count})
+			}
+		}
+	}
+
+	for _, need := range needs {
+		sort.Slice(need.Models, func(i, j int) bool { return need.Models[i].GPUs > need.Models[j].GPUs })
+		summary.Branches = append(summary.Branches, *need)
+	}
+	sort.Slice(summary.Branches, func(i, j int) bool {
+		a, b := summary.Branches[i], summary.Branches[j]
+		if len(a.Models) != len(b.Models) {
+			return len(a.Models) > len(b.Models)
+		}
+		if a.GPUs != b.GPUs {
+			return a.GPUs > b.GPUs
+		}
+		return a.Branch < b.Branch
+	})
+	sort.Slice(summary.Unmapped, func(i, j int) bool { return summary.Unmapped[i].GPUs > summary.Unmapped[j].GPUs })
+	return summary
+}
+
+// canonicalModel spells a model as the table does, or uses the device's first model when the
+// inventory only gave a PCI ID
+func canonicalModel(device *Device, model string) string {
+	for _, name := range device.Models {
+		if model == "" || strings.EqualFold(name, model) {
+			return name
+		}
+	}
+	return model
+}
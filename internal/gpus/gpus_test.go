@@ -0,0 +1,106 @@
+package gpus
+
+import (
+	"strings"
+	"testing"
+)
+
+const testTable = `[
+  {"pci_ids": ["10DE:1EB8"], "models": ["Tesla T4"], "branch": "535"},
+  {"pci_ids": ["0x10de:0x20b0"], "models": ["NVIDIA A100-SXM4-40GB"], "branch": "535"},
+  {"models": ["Tesla K80", "GeForce GTX 780"], "branch": "470", "legacy": true}
+]`
+
+func TestNormalizePCIID(t *testing.T) {
+	tests := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"10de:1eb8", "10de:1eb8", true},
+		{"10DE:1EB8", "10de:1eb8", true},
+		{"0x10de:0x1eb8", "10de:1eb8", true},
+		{"[10de:1eb8] (rev a1)", "10de:1eb8", true},
+		{"10de-1eb8", "", false},
+		{"Tesla T4", "", false},
+	}
+	for _, tt := range tests {
+		got, err := NormalizePCIID(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("NormalizePCIID(%q) = %q, %v; want %q, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	csv := "\ufeffModel,PCI_ID,Count\n" +
+		"Tesla T4,10de:1eb8,8\n" +
+		"Tesla T4,10de:1eb8,4\n" +
+		"Tesla K80,,\n" +
+		",,\n" +
+		"NVIDIA A100-SXM4-40GB,10DE:20B0,16\n"
+	gpus, err := ParseCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	want := []GPU{
+		{PCIID: "10de:1eb8", Model: "Tesla T4", Count: 12},
+		{Model: "Tesla K80", Count: 1},
+		{PCIID: "10de:20b0", Model: "NVIDIA A100-SXM4-40GB", Count: 16},
+	}
+	if len(gpus) != len(want) {
+		t.Fatalf("ParseCSV() = %+v, want %+v", gpus, want)
+	}
+	for i := range want {
+		if gpus[i] != want[i] {
+			t.Errorf("ParseCSV()[%d] = %+v, want %+v", i, gpus[i], want[i])
+		}
+	}
+
+	for name, input := range map[string]string{
+		"no columns": "name,qty\nT4,1\n",
+		"bad count":  "model,count\nTesla T4,many\n",
+		"bad PCI ID": "pci_id\n10de\n",
+		"negative":   "model,count\nTesla T4,-1\n",
+		"empty":      "",
+	} {
+		if _, err := ParseCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseCSV(%s) should fail", name)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	table, err := ParseTable([]byte(testTable))
+	if err != nil {
+		t.Fatalf("ParseTable() error = %v", err)
+	}
+	inventories := []Inventory{
+		{Name: "lab-a", GPUs: []GPU{{PCIID: "10de:1eb8", Count: 8}, {Model: "tesla k80", Count: 2}, {Model: "Quadro Mystery", Count: 1}}},
+		{Name: "lab-b", GPUs: []GPU{{PCIID: "10de:1eb8", Model: "Tesla T4", Count: 4}, {PCIID: "10de:20b0", Count: 16}, {Model: "GeForce GTX 780", Count: 1}}},
+	}
+
+	summary := Summarize(inventories, table)
+	if summary.GPUs != 32 || len(summary.Inventories) != 2 {
+		t.Errorf("Summarize() counted %d GPUs in %v, want 32 in 2 inventories", summary.GPUs, summary.Inventories)
+	}
+	if len(summary.Branches) != 2 {
+		t.Fatalf("Summarize() branches = %+v, want 535 then 470", summary.Branches)
+	}
+	current, legacy := summary.Branches[0], summary.Branches[1]
+	if current.Branch != "535" || current.Legacy || current.GPUs != 28 || len(current.Models) != 2 {
+		t.Errorf("535 need = %+v, want 2 models and 28 GPUs", current)
+	}
+	if current.Models[0].Model != "NVIDIA A100-SXM4-40GB" || current.Models[1].Model != "Tesla T4" || current.Models[1].GPUs != 12 {
+		t.Errorf("535 models = %+v, want models named from the table and T4 counted once", current.Models)
+	}
+	if legacy.Branch != "470" || !legacy.Legacy || legacy.GPUs != 3 || len(legacy.Models) != 2 {
+		t.Errorf("470 need = %+v, want 2 legacy models and 3 GPUs", legacy)
+	}
+	if len(summary.Unmapped) != 1 || summary.Unmapped[0].Model != "Quadro Mystery" {
+		t.Errorf("Unmapped = %+v, want the unknown model", summary.Unmapped)
+	}
+
+	if _, err := ParseTable([]byte(`[{"models": ["Tesla T4"]}]`)); err == nil {
+		t.Error("ParseTable() should reject an entry without a branch")
+	}
+}
@@ -0,0 +1,142 @@
+package gpus
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/atomicfile"
+)
+
+// namePattern restricts inventory names to short identifiers, e.g. "lab-a"
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Inventory is one uploaded GPU list, e.g. the GPUs of a lab or a team
+type Inventory struct {
+	Name       string    `json:"name"`
+	GPUs       []GPU     `json:"gpus"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// SchemaVersion is the version of the format of the GPU inventories file, bumped when it changes
+const SchemaVersion = 1
+
+// Store keeps the latest upload of each named inventory and persists them to disk
+type Store struct {
+	mu          sync.RWMutex
+	inventories map[string]*Inventory
+	persistFile string
+}
+
+// NewStore creates a store, loading previously persisted inventories if available
+func NewStore(persistFile string) *Store {
+	s := &Store{
+		inventories: make(map[string]*Inventory),
+		persistFile: persistFile,
+	}
+	if err := s.loadFromFile(); err != nil {
+		log.Printf("Warning: Could not load existing GPU inventories: %v", err)
+	}
+	return s
+}
+
+// Put validates the GPUs and replaces the inventory of that name
+func (s *Store) Put(name string, gpus []GPU, now time.Time) (*Inventory, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid or missing inventory name")
+	}
+	if len(gpus) == 0 {
+		return nil, fmt.Errorf("the inventory lists no GPUs")
+	}
+	for i := range gpus {
+		if err := gpus[i].Normalize(); err != nil {
+			return nil, fmt.Errorf("GPU %d: %w", i+1, err)
+		}
+	}
+
+	inventory := Inventory{Name: name, GPUs: Merge(gpus), UploadedAt: now}
+	s.mu.Lock()
+	s.inventories[name] = &inventory
+	s.mu.Unlock()
+	s.persist()
+
+	return &inventory, nil
+}
+
+// Remove deletes an inventory, reporting whether it existed
+func (s *Store) Remove(name string) bool {
+	s.mu.Lock()
+	_, ok := s.inventories[name]
+	delete(s.inventories, name)
+	s.mu.Unlock()
+	if ok {
+		s.persist()
+	}
+	return ok
+}
+
+// List returns the inventories ordered by name
+func (s *Store) List() []Inventory {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]Inventory, 0, len(s.inventories))
+	for _, inventory := range s.inventories {
+		list = append(list, *inventory)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// persist saves the inventories, logging failures
+func (s *Store) persist() {
+	if err := s.saveToFile(); err != nil {
+		log.Printf("Warning: Failed to persist GPU inventories: %v", err)
+	}
+}
+
+// saveToFile writes all inventories to the persistence file
+func (s *Store) saveToFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	return atomicfile.WriteJSON(s.persistFile, s.List())
+}
+
+// loadFromFile restores inventories from the persistence file
+func (s *Store) loadFromFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	jsonData, err := os.ReadFile(s.persistFile)
+	if os.IsNotExist(err) {
+		return nil // No existing data, start fresh
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read GPU inventories file: %w", err)
+	}
+
+	var list []Inventory
+	if err := json.Unmarshal(jsonData, &list); err != nil {
+		return fmt.Errorf("failed to parse GPU inventories JSON: %w", err)
+	}
+
+	inventories := make(map[string]*Inventory, len(list))
+	for i := range list {
+		inventories[list[i].Name] = &list[i]
+	}
+
+	s.mu.Lock()
+	s.inventories = inventories
+	s.mu.Unlock()
+
+	log.Printf("Loaded %d GPU inventories from %s", len(inventories), s.persistFile)
+	return nil
+}
@@ -0,0 +1,43 @@
+package gpus
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStorePersistsInventories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gpus.json")
+	store := NewStore(path)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	if _, err := store.Put("lab-a", []GPU{{PCIID: "10DE:1EB8", Count: 2}, {PCIID: "10de:1eb8"}}, now); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := store.Put("lab-b", []GPU{{Model: "Tesla K80"}}, now); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	for name, gpus := range map[string][]GPU{
+		"":       {{Model: "Tesla T4"}},
+		"../etc": {{Model: "Tesla T4"}},
+		"lab-c":  nil,
+		"lab-d":  {{Count: 3}},
+	} {
+		if _, err := store.Put(name, gpus, now); err == nil {
+			t.Errorf("Put(%q, %+v) should fail", name, gpus)
+		}
+	}
+
+	reloaded := NewStore(path)
+	list := reloaded.List()
+	if len(list) != 2 || list[0].Name != "lab-a" || len(list[0].GPUs) != 1 || list[0].GPUs[0].Count != 3 || list[0].GPUs[0].PCIID != "10de:1eb8" {
+		t.Fatalf("List() after reload = %+v, want lab-a with 3 merged T4s and lab-b", list)
+	}
+
+	if !reloaded.Remove("lab-b") || reloaded.Remove("lab-b") {
+		t.Error("Remove() should report whether the inventory existed")
+	}
+	if list := NewStore(path).List(); len(list) != 1 {
+		t.Errorf("List() after Remove = %+v, want only lab-a", list)
+	}
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Node kinds, used to style the rendered graph
+const (
+	KindSource     = "source"     // Source package uploaded to the archive
+	KindBinary     = "binary"     // Binary package built from a source
+	KindLRM        = "lrm"        // linux-restricted-modules and the prebuilt objects it produces
+	KindSignatures = "signatures" // Signed module signatures produced by L-R-M
+	KindMeta       = "meta"       // Meta packages pulling in the right modules for a kernel flavour
+	KindUser       = "user"       // Installation on a user's machine
+)
+
+// Node is a package (or the final installation step) in the driver delivery graph
+type Node struct {
+	ID          string `json:"id"`
+	Label       string `json:"label"`
+	Kind        string `json:"kind"`
+	Step        int    `json:"step"` // Order in which the node becomes available, starting at 1
+	Description string `json:"description"`
+}
+
+// Edge is a relationship between two nodes, e.g. "builds" or "build-depends"
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label"`
+}
+
+// Graph describes what has to happen, in order, for a driver branch to reach users
+type Graph struct {
+	Branch string `json:"branch"`
+	Nodes  []Node `json:"nodes"`
+	Edges  []Edge `json:"edges"`
+}
+
+// branchPattern matches driver branch names such as "550" or "535-server"
+var branchPattern = regexp.MustCompile(`^[0-9]+(-server)?$`)
+
+// Build returns the delivery graph of a driver branch for the generic kernel flavour
+func Build(branch string) (*Graph, error) {
+	if !branchPattern.MatchString(branch) {
+		return nil, fmt.Errorf("invalid driver branch %q", branch)
+	}
+
+	source := "nvidia-graphics-drivers-" + branch
+	kernelSource := "nvidia-kernel-source-" + branch
+	dkms := "nvidia-dkms-" + branch
+	driver := "nvidia-driver-" + branch
+	objects := fmt.Sprintf("linux-objects-nvidia-%s-ABI-generic", branch)
+	signatures := "linux-signatures-nvidia-ABI-generic"
+	modules := fmt.Sprintf("linux-modules-nvidia-%s-ABI-generic", branch)
+	modulesMeta := fmt.Sprintf("linux-modules-nvidia-%s-generic", branch)
+
+	g := &Graph{
+		Branch: branch,
+		Nodes: []Node{
+			{source, source, KindSource, 1, "Driver source uploaded to -proposed, then released to -updates/-security"},
+			{kernelSource, kernelSource, KindBinary, 2, "Kernel module sources consumed by L-R-M"},
+			{dkms, dkms, KindBinary, 2, "Builds the kernel module locally; fallback when no prebuilt modules exist"},
+			{driver, driver, KindBinary, 2, "Userspace driver installed by ubuntu-drivers"},
+			{"linux-restricted-modules", "linux-restricted-modules", KindLRM, 3, "Respun for every kernel SRU cycle to prebuild the NVIDIA modules"},
+			{objects, objects, KindLRM, 4, "Prebuilt module objects for one kernel ABI (e.g. 6.8.0-45)"},
+			{signatures, signatures, KindSignatures, 4, "Module signatures created with the Canonical signing key"},
+			{modules, modules, KindLRM, 5, "Signed modules assembled at install time from objects and signatures"},
+			{"linux-meta", "linux-meta", KindSource, 5, "Kernel meta source, updated to point at the new ABI"},
+			{modulesMeta, modulesMeta, KindMeta, 6, "Tracks the latest ABI so modules follow kernel upgrades"},
+			{"ubuntu-drivers", "ubuntu-drivers install", KindUser, 7, "Installs the driver and prebuilt modules on user machines"},
+		},
+		Edges: []Edge{
+			{source, kernelSource, "builds"},
+			{source, dkms, "builds"},
+			{source, driver, "builds"},
+			{kernelSource, "linux-restricted-modules", "build-depends"},
+			{"linux-restricted-modules", objects, "builds"},
+			{"linux-restricted-modules", signatures, "builds"},
+			{objects, modules, "links"},
+			{signatures, modules, "signs"},
+			{"linux-meta", modulesMeta, "builds"},
+			{modulesMeta, modules, "depends"},
+			{driver, "ubuntu-drivers", "installed"},
+			{modulesMeta, "ubuntu-drivers", "installed"},
+			{dkms, "ubuntu-drivers", "fallback"},
+		},
+	}
+	return g, nil
+}
+
+// kindStyles are the Mermaid class definitions of each node kind
+var kindStyles = []struct{ kind, style string }{
+	{KindSource, "fill:#fbe3d9,stroke:#e95420"},
+	{KindBinary, "fill:#e8f0fb,stroke:#335280"},
+	{KindLRM, "fill:#eef6e7,stroke:#0e8420"},
+	{KindSignatures, "fill:#fff5d6,stroke:#c7a600"},
+	{KindMeta, "fill:#f2e6f5,stroke:#772953"},
+	{KindUser, "fill:#f7f7f7,stroke:#111111"},
+}
+
+// mermaidID turns a node ID into an identifier accepted by Mermaid
+func mermaidID(id string) string {
+	return "n_" + strings.NewReplacer("-", "_", " ", "_").Replace(id)
+}
+
+// Mermaid renders the graph as a Mermaid flowchart definition
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, ks := range kindStyles {
+		fmt.Fprintf(&b, "    classDef %s %s\n", ks.kind, ks.style)
+	}
+	for _, node := range g.Nodes {
+		label := strings.ReplaceAll(node.Label, `"`, "'")
+		fmt.Fprintf(&b, "    %s[\"%d. %s\"]:::%s\n", mermaidID(node.ID), node.Step, label, node.Kind)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "    %s -->|%s| %s\n", mermaidID(edge.From), edge.Label, mermaidID(edge.To))
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	g, err := Build("550-server")
+	if err != nil {
+		t.Fatalf("Build(550-server) returned error: %v", err)
+	}
+
+	ids := make(map[string]bool)
+	for _, node := range g.Nodes {
+		ids[node.ID] = true
+	}
+	for _, id := range []string{"nvidia-graphics-drivers-550-server", "nvidia-dkms-550-server", "linux-restricted-modules", "linux-modules-nvidia-550-server-generic"} {
+		if !ids[id] {
+			t.Errorf("Build(550-server) is missing node %s", id)
+		}
+	}
+	for _, edge := range g.Edges {
+		if !ids[edge.From] || !ids[edge.To] {
+			t.Errorf("edge %s -> %s references an unknown node", edge.From, edge.To)
+		}
+	}
+}
+
+func TestBuildInvalidBranch(t *testing.T) {
+	for _, branch := range []string{"", "550; rm", "nvidia-graphics-drivers-550"} {
+		if _, err := Build(branch); err == nil {
+			t.Errorf("Build(%s) expected an error", branch)
+		}
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	g, err := Build("570")
+	if err != nil {
+		t.Fatalf("Build(570) returned error: %v", err)
+	}
+	definition := g.Mermaid()
+	for _, expected := range []string{
+		"flowchart TD\n",
+		`n_nvidia_graphics_drivers_570["1. nvidia-graphics-drivers-570"]:::source`,
+		"n_nvidia_graphics_drivers_570 -->|builds| n_nvidia_dkms_570",
+	} {
+		if !strings.Contains(definition, expected) {
+			t.Errorf("Mermaid() does not contain %q", expected)
+		}
+	}
+}
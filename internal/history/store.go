@@ -0,0 +1,317 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/atomicfile"
+)
+
+// DateFormat is the layout of observation dates
+const DateFormat = "2006-01-02"
+
+// Observation is the dashboard state of one package in one series on a given day
+type Observation struct {
+	Date         string `json:"date"`
+	Package      string `json:"package"`
+	Series       string `json:"series"`
+	Published    string `json:"published"`
+	Proposed     string `json:"proposed"`
+	Upstream     string `json:"upstream"`
+	UpstreamDate string `json:"upstream_date"`
+	Outdated     bool   `json:"outdated"`
+	Component    string `json:"component,omitempty"` // Archive component of the published version
+	Section      string `json:"section,omitempty"`   // Archive section of the published version
+}
+
+// ComponentTransition records a published version moving to another archive component or section
+type ComponentTransition struct {
+	Date    string `json:"date"`
+	Package string `json:"package"`
+	Series  string `json:"series"`
+	Field   string `json:"field"` // "component" or "section"
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Version string `json:"version"`
+}
+
+// key identifies the observation slot; later observations on the same day replace earlier ones
+func (o *Observation) key() string {
+	return o.Date + "|" + o.Package + "|" + o.Series
+}
+
+// SchemaVersion is the version of the format of the history file, bumped when it changes:
+// 2 added the component of observations, 3 their section
+const SchemaVersion = 3
+
+// Store keeps one observation per package, series and day and persists them to disk
+type Store struct {
+	mu           sync.RWMutex
+	observations map[string]*Observation
+	persistFile  string
+	retention    int       // Days of observations kept by Record; 0 keeps them all
+	dirty        bool      // Observations changed since they were last persisted
+	lastRecorded time.Time // When Record was last called
+	lastError    string    // Why Record last failed to persist, empty when it succeeded
+}
+
+// NewStore creates a store, loading previously persisted observations if available.
+// An empty persistFile keeps the history in memory only.
+func NewStore(persistFile string) *Store {
+	s := &Store{
+		observations: make(map[string]*Observation),
+		persistFile:  persistFile,
+	}
+	if err := s.loadFromFile(); err != nil {
+		log.Printf("Warning: Could not load existing history data: %v", err)
+	}
+	return s
+}
+
+// SetRetention makes Record drop the observations older than days; 0 keeps them all
+func (s *Store) SetRetention(days int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retention = days
+}
+
+// Record stores observations taken at the given time, drops those past the retention, and
+// persists the store when any of them changed
+func (s *Store) Record(at time.Time, observations []Observation) error {
+	date := at.Format(DateFormat)
+
+	s.mu.Lock()
+	for _, obs := range observations {
+		copied := obs
+		if copied.Date == "" {
+			copied.Date = date
+		}
+		if current, ok := s.observations[copied.key()]; ok && *current == copied {
+			continue
+		}
+		s.observations[copied.key()] = &copied
+		s.dirty = true
+	}
+	if s.retention > 0 {
+		oldest := at.AddDate(0, 0, -s.retention).Format(DateFormat)
+		for key, obs := range s.observations {
+			if obs.Date < oldest {
+				delete(s.observations, key)
+				s.dirty = true
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	err := s.saveToFile()
+	s.mu.Lock()
+	s.lastRecorded, s.lastError = at, ""
+	if err != nil {
+		s.lastError = err.Error()
+	}
+	s.mu.Unlock()
+	return err
+}
+
+// Backfill stores dated observations into empty slots only, so reconstructed history never
+// replaces what the dashboard observed, and persists the store once. It returns how many
+// observations were added.
+func (s *Store) Backfill(observations []Observation) (int, error) {
+	added := 0
+	s.mu.Lock()
+	for _, obs := range observations {
+		copied := obs
+		if _, ok := s.observations[copied.key()]; ok || copied.Date == "" {
+			continue
+		}
+		s.observations[copied.key()] = &copied
+		s.dirty = true
+		added++
+	}
+	s.mu.Unlock()
+
+	if added == 0 {
+		return 0, nil
+	}
+	return added, s.saveToFile()
+}
+
+// Series returns the observations of a package in a series ordered by date
+func (s *Store) Series(packageName, series string) []Observation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []Observation
+	for _, obs := range s.observations {
+		if obs.Package == packageName && obs.Series == series {
+			result = append(result, *obs)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+	return result
+}
+
+// SeriesNames returns the series observed for a package in alphabetical order
+func (s *Store) SeriesNames(packageName string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, obs := range s.observations {
+		if obs.Package == packageName && !seen[obs.Series] {
+			seen[obs.Series] = true
+			names = append(names, obs.Series)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AsOf returns, for every package and series, the latest observation recorded on or before
+// the given date (YYYY-MM-DD), ordered by package and series
+func (s *Store) AsOf(date string) []Observation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	latest := make(map[string]*Observation)
+	for _, obs := range s.observations {
+		if obs.Date > date {
+			continue
+		}
+		slot := obs.Package + "|" + obs.Series
+		if current, ok := latest[slot]; !ok || obs.Date > current.Date {
+			latest[slot] = obs
+		}
+	}
+
+	result := make([]Observation, 0, len(latest))
+	for _, obs := range latest {
+		result = append(result, *obs)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Package != result[j].Package {
+			return result[i].Package < result[j].Package
+		}
+		return result[i].Series < result[j].Series
+	})
+	return result
+}
+
+// ComponentTransitions returns the days on which the published version of a package changed
+// component or section in a series, ordered by date with component moves first. Observations
+// without a value are skipped.
+func (s *Store) ComponentTransitions(packageName, series string) []ComponentTransition {
+	var transitions []ComponentTransition
+	previous := map[string]string{}
+	for _, obs := range s.Series(packageName, series) {
+		for _, field := range []struct{ name, value string }{
+			{"component", obs.Component},
+			{"section", obs.Section},
+		} {
+			if field.value == "" {
+				continue
+			}
+			if from := previous[field.name]; from != "" && field.value != from {
+				transitions = append(transitions, ComponentTransition{
+					Date:    obs.Date,
+					Package: obs.Package,
+					Series:  obs.Series,
+					Field:   field.name,
+					From:    from,
+					To:      field.value,
+					Version: obs.Published,
+				})
+			}
+			previous[field.name] = field.value
+		}
+	}
+	return transitions
+}
+
+// OutdatedSince returns the date of the first observation of the current outdated run of a
+// package in a series, or "" when its latest observation is not outdated
+func (s *Store) OutdatedSince(packageName, series string) string {
+	observations := s.Series(packageName, series)
+	since := ""
+	for i := len(observations) - 1; i >= 0 && observations[i].Outdated; i-- {
+		since = observations[i].Date
+	}
+	return since
+}
+
+// Len returns the number of stored observations
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.observations)
+}
+
+// LastRecord returns when observations were last recorded, zero before the first time, and why
+// persisting them failed, empty when it succeeded
+func (s *Store) LastRecord() (time.Time, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastRecorded, s.lastError
+}
+
+// saveToFile writes all observations to the persistence file, unless none changed since the
+// last write
+func (s *Store) saveToFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	list := make([]*Observation, 0, len(s.observations))
+	for _, obs := range s.observations {
+		list = append(list, obs)
+	}
+	s.dirty = false
+	s.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].key() < list[j].key() })
+
+	if err := atomicfile.WriteJSON(s.persistFile, list); err != nil {
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// loadFromFile restores observations from the persistence file
+func (s *Store) loadFromFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.persistFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read history data: %w", err)
+	}
+
+	var list []*Observation
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("failed to parse history data: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, obs := range list {
+		s.observations[obs.key()] = obs
+	}
+	return nil
+}
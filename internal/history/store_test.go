@@ -0,0 +1,211 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreKeepsLatestObservationPerDay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store := NewStore(path)
+
+	day1 := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	obs := Observation{Package: "nvidia-graphics-drivers-570", Series: "noble", Upstream: "570.195.03", Outdated: true}
+
+	if err := store.Record(day1, []Observation{obs}); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+	obs.Outdated = false
+	if err := store.Record(day1.Add(time.Hour), []Observation{obs}); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+	if err := store.Record(day2, []Observation{obs}); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+
+	reloaded := NewStore(path)
+	rows := reloaded.Series("nvidia-graphics-drivers-570", "noble")
+	if len(rows) != 2 {
+		t.Fatalf("Series() returned %d rows, expected 2", len(rows))
+	}
+	if rows[0].Date != "2026-10-01" || rows[0].Outdated {
+		t.Errorf("Series()[0] = %+v, expected the later 2026-10-01 observation", rows[0])
+	}
+	if names := reloaded.SeriesNames("nvidia-graphics-drivers-570"); len(names) != 1 || names[0] != "noble" {
+		t.Errorf("SeriesNames() = %v, expected [noble]", names)
+	}
+}
+
+func TestStoreAsOf(t *testing.T) {
+	store := NewStore("")
+	day1 := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	store.Record(day1, []Observation{
+		{Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.172.08-0ubuntu1"},
+		{Package: "nvidia-graphics-drivers-570", Series: "jammy", Published: "570.172.08-0ubuntu1"},
+	})
+	store.Record(day1.AddDate(0, 0, 5), []Observation{
+		{Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.195.03-0ubuntu1"},
+	})
+
+	tests := []struct {
+		date     string
+		expected int
+		noble    string
+	}{
+		{"2026-09-30", 0, ""},
+		{"2026-10-03", 2, "570.172.08-0ubuntu1"},
+		{"2026-10-06", 2, "570.195.03-0ubuntu1"},
+	}
+
+	for _, test := range tests {
+		rows := store.AsOf(test.date)
+		if len(rows) != test.expected {
+			t.Errorf("AsOf(%s) returned %d rows, expected %d", test.date, len(rows), test.expected)
+			continue
+		}
+		for _, row := range rows {
+			if row.Series == "noble" && row.Published != test.noble {
+				t.Errorf("AsOf(%s) noble = %s, expected %s", test.date, row.Published, test.noble)
+			}
+		}
+	}
+}
+
+func TestStoreComponentTransitions(t *testing.T) {
+	store := NewStore("")
+	day1 := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i, component := range []string{"restricted", "", "restricted", "multiverse"} {
+		store.Record(day1.AddDate(0, 0, i), []Observation{
+			{Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.195.03-0ubuntu1", Component: component},
+		})
+	}
+
+	transitions := store.ComponentTransitions("nvidia-graphics-drivers-570", "noble")
+	if len(transitions) != 1 {
+		t.Fatalf("ComponentTransitions() returned %d transitions, expected 1", len(transitions))
+	}
+	if got := transitions[0]; got.Date != "2026-10-04" || got.Field != "component" || got.From != "restricted" || got.To != "multiverse" {
+		t.Errorf("ComponentTransitions()[0] = %+v, expected restricted -> multiverse on 2026-10-04", got)
+	}
+}
+
+func TestStoreSectionTransitions(t *testing.T) {
+	store := NewStore("")
+	day1 := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i, section := range []string{"restricted/misc", "restricted/misc", "multiverse/libs"} {
+		store.Record(day1.AddDate(0, 0, i), []Observation{
+			{Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.195.03-0ubuntu1", Component: "restricted", Section: section},
+		})
+	}
+
+	transitions := store.ComponentTransitions("nvidia-graphics-drivers-570", "noble")
+	if len(transitions) != 1 {
+		t.Fatalf("ComponentTransitions() returned %d transitions, expected the section move only", len(transitions))
+	}
+	if got := transitions[0]; got.Date != "2026-10-03" || got.Field != "section" || got.From != "restricted/misc" || got.To != "multiverse/libs" {
+		t.Errorf("ComponentTransitions()[0] = %+v, expected the section move on 2026-10-03", got)
+	}
+}
+
+func TestStoreOutdatedSince(t *testing.T) {
+	store := NewStore("")
+	day1 := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i, outdated := range []bool{true, false, true, true} {
+		store.Record(day1.AddDate(0, 0, i), []Observation{
+			{Package: "nvidia-graphics-drivers-570", Series: "noble", Outdated: outdated},
+			{Package: "nvidia-graphics-drivers-570", Series: "jammy", Outdated: i == 0},
+		})
+	}
+
+	if since := store.OutdatedSince("nvidia-graphics-drivers-570", "noble"); since != "2026-10-03" {
+		t.Errorf("OutdatedSince(noble) = %q, expected 2026-10-03", since)
+	}
+	if since := store.OutdatedSince("nvidia-graphics-drivers-570", "jammy"); since != "" {
+		t.Errorf("OutdatedSince(jammy) = %q, expected none", since)
+	}
+}
+
+func TestBackfillKeepsObservedDays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store := NewStore(path)
+
+	observed := Observation{Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.195.03-0ubuntu1"}
+	if err := store.Record(time.Date(2026, 10, 2, 8, 0, 0, 0, time.UTC), []Observation{observed}); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+
+	added, err := store.Backfill([]Observation{
+		{Date: "2026-10-01", Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.181-0ubuntu1", Outdated: true},
+		{Date: "2026-10-02", Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.181-0ubuntu1", Outdated: true},
+		{Package: "nvidia-graphics-drivers-570", Series: "noble"},
+	})
+	if err != nil || added != 1 {
+		t.Fatalf("Backfill() = %d, %v, expected 1 added", added, err)
+	}
+
+	rows := NewStore(path).Series("nvidia-graphics-drivers-570", "noble")
+	if len(rows) != 2 || rows[0].Published != "570.181-0ubuntu1" || rows[1].Published != "570.195.03-0ubuntu1" {
+		t.Errorf("Series() = %+v, expected the backfilled day before the observed one", rows)
+	}
+}
+
+func TestStoreLastRecord(t *testing.T) {
+	dir := t.TempDir()
+	// The parent of the history file is a file, so persisting fails
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore(filepath.Join(blocker, "history.json"))
+	if at, lastErr := store.LastRecord(); !at.IsZero() || lastErr != "" {
+		t.Errorf("LastRecord() before recording = %v, %q", at, lastErr)
+	}
+
+	now := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	if err := store.Record(now, []Observation{{Package: "nvidia-graphics-drivers-570", Series: "noble"}}); err == nil {
+		t.Fatal("Record() into an unwritable directory should fail")
+	}
+	if at, lastErr := store.LastRecord(); !at.Equal(now) || lastErr == "" {
+		t.Errorf("LastRecord() = %v, %q, expected the failed record", at, lastErr)
+	}
+
+	store.persistFile = filepath.Join(dir, "history.json")
+	store.Record(now.Add(time.Hour), nil)
+	if at, lastErr := store.LastRecord(); !at.Equal(now.Add(time.Hour)) || lastErr != "" {
+		t.Errorf("LastRecord() = %v, %q, expected the error cleared", at, lastErr)
+	}
+}
+
+func TestStoreWritesOnlyChangesAndDropsExpiredDays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store := NewStore(path)
+	store.SetRetention(30)
+	day := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	observation := Observation{Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.195.03-0ubuntu1"}
+
+	if err := store.Record(day, []Observation{observation}); err != nil {
+		t.Fatal(err)
+	}
+	// An unchanged observation leaves the file alone
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	store.Record(day.Add(time.Hour), []Observation{observation})
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Record() of an unchanged observation rewrote the file")
+	}
+
+	observation.Published = "570.211.01-0ubuntu1"
+	store.Record(day.Add(2*time.Hour), []Observation{observation})
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Record() of a changed observation did not write the file: %v", err)
+	}
+
+	store.Record(day.AddDate(0, 0, 31), nil)
+	if rows := NewStore(path).Series("nvidia-graphics-drivers-570", "noble"); len(rows) != 0 {
+		t.Errorf("Series() after the retention = %+v, expected the expired day dropped", rows)
+	}
+}
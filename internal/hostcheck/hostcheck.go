@@ -0,0 +1,320 @@
+package hostcheck
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/utils"
+
+	version "github.com/knqyf263/go-deb-version"
+)
+
+// Host check status values reported to fleet inventory systems
+const (
+	StatusUpToDate     = "up-to-date"
+	StatusOutdated     = "outdated"
+	StatusNewer        = "newer-than-archive"
+	StatusNotInstalled = "not-installed"
+	StatusUnknown      = "unknown"
+)
+
+// sourcePackagePrefix identifies dpkg packages built from NVIDIA driver sources
+const sourcePackagePrefix = "nvidia-graphics-drivers-"
+
+// InstalledPackage is a dpkg package built from an NVIDIA driver source package
+type InstalledPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  string `json:"source"`
+}
+
+// HostInfo describes the NVIDIA driver state of the local machine
+type HostInfo struct {
+	Hostname         string             `json:"hostname"`
+	Series           string             `json:"series"`
+	Kernel           string             `json:"kernel"`
+	Packages         []InstalledPackage `json:"packages"`
+	SMIDriverVersion string             `json:"nvidia_smi_driver_version,omitempty"`
+	ModuleVersion    string             `json:"kernel_module_version,omitempty"`
+	Notes            []string           `json:"notes,omitempty"`
+}
+
+// SeriesStatus is the subset of the monitor's per-series table row used by the host check
+type SeriesStatus struct {
+	Series          string `json:"Series"`
+	UpdatesSecurity string `json:"UpdatesSecurity"`
+	Proposed        string `json:"Proposed"`
+	UpstreamVersion string `json:"UpstreamVersion"`
+}
+
+// PackageStatus is the monitor API view of a source package
+type PackageStatus struct {
+	PackageName string         `json:"PackageName"`
+	Series      []SeriesStatus `json:"Series"`
+}
+
+// Report is the JSON document emitted by the host check
+type Report struct {
+	HostInfo
+	SourcePackage    string    `json:"source_package,omitempty"`
+	InstalledVersion string    `json:"installed_version,omitempty"`
+	LatestVersion    string    `json:"latest_version,omitempty"`
+	ProposedVersion  string    `json:"proposed_version,omitempty"`
+	Status           string    `json:"status"`
+	UpToDate         bool      `json:"up_to_date"`
+	CheckedAt        time.Time `json:"checked_at"`
+}
+
+// CollectHostInfo inspects the local machine. Missing tools are recorded as notes, not errors.
+func CollectHostInfo() *HostInfo {
+	info := &HostInfo{}
+
+	if hostname, err := os.Hostname(); err == nil {
+		info.Hostname = hostname
+	}
+
+	if data, err := os.ReadFile("/etc/os-release"); err == nil {
+		info.Series = ParseOSRelease(string(data))
+	} else {
+		info.Notes = append(info.Notes, fmt.Sprintf("cannot read /etc/os-release: %v", err))
+	}
+
+	if data, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		info.Kernel = strings.TrimSpace(string(data))
+	}
+
+	out, err := exec.Command("dpkg-query", "-W", "-f=${Package}\t${Version}\t${source:Package}\t${db:Status-Status}\n").Output()
+	if err != nil {
+		info.Notes = append(info.Notes, fmt.Sprintf("dpkg-query failed: %v", err))
+	} else {
+		info.Packages = ParseDpkgOutput(string(out))
+	}
+
+	out, err = exec.Command("nvidia-smi", "--query-gpu=driver_version", "--format=csv,noheader").Output()
+	if err != nil {
+		info.Notes = append(info.Notes, fmt.Sprintf("nvidia-smi unavailable: %v", err))
+	} else {
+		info.SMIDriverVersion = ParseSMIOutput(string(out))
+	}
+
+	if data, err := os.ReadFile("/sys/module/nvidia/version"); err == nil {
+		info.ModuleVersion = strings.TrimSpace(string(data))
+	} else {
+		info.Notes = append(info.Notes, "nvidia kernel module is not loaded")
+	}
+
+	return info
+}
+
+// ParseOSRelease returns the series codename from /etc/os-release content
+func ParseOSRelease(content string) string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		values[key] = strings.Trim(value, `"'`)
+	}
+	if codename := values["VERSION_CODENAME"]; codename != "" {
+		return codename
+	}
+	return values["UBUNTU_CODENAME"]
+}
+
+// ParseDpkgOutput extracts installed NVIDIA driver packages from dpkg-query output
+func ParseDpkgOutput(output string) []InstalledPackage {
+	var installed []InstalledPackage
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 4 || fields[3] != "installed" {
+			continue
+		}
+		// source:Package carries a version suffix when it differs from the binary version
+		source := strings.Fields(fields[2])
+		if len(source) == 0 || !strings.HasPrefix(source[0], sourcePackagePrefix) {
+			continue
+		}
+		installed = append(installed, InstalledPackage{
+			Name:    fields[0],
+			Version: fields[1],
+			Source:  source[0],
+		})
+	}
+	sort.Slice(installed, func(i, j int) bool { return installed[i].Name < installed[j].Name })
+	return installed
+}
+
+// ParseSMIOutput returns the driver version reported by nvidia-smi for the first GPU
+func ParseSMIOutput(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[0])
+}
+
+// primaryPackage picks the package that represents the installed driver,
+// preferring the nvidia-driver-* metapackage when present
+func primaryPackage(packages []InstalledPackage) *InstalledPackage {
+	if len(packages) == 0 {
+		return nil
+	}
+	for i := range packages {
+		if strings.HasPrefix(packages[i].Name, "nvidia-driver-") {
+			return &packages[i]
+		}
+	}
+	return &packages[0]
+}
+
+// SourcePackage returns the NVIDIA driver source package installed on the host, if any
+func (info *HostInfo) SourcePackage() string {
+	if pkg := primaryPackage(info.Packages); pkg != nil {
+		return pkg.Source
+	}
+	return ""
+}
+
+// FetchPackageStatus queries the monitor API for a source package
+func FetchPackageStatus(serverURL, packageName string) (*PackageStatus, error) {
+	apiURL := strings.TrimRight(serverURL, "/") + "/api?package=" + url.QueryEscape(packageName)
+
+	resp, err := utils.HTTPGetWithRetry(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query monitor: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("package %s is not tracked by the monitor", packageName)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("monitor returned HTTP %d", resp.StatusCode)
+	}
+
+	var status PackageStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode monitor response: %v", err)
+	}
+	return &status, nil
+}
+
+// SubmitReport posts a report to the monitor's fleet ingestion endpoint, with the report token
+// when one is given
+func SubmitReport(serverURL, token string, report *Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(serverURL, "/")+"/api/v1/hosts/report", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if utils.HTTPUserAgent != "" {
+		req.Header.Set("User-Agent", utils.HTTPUserAgent)
+	}
+
+	client := utils.NewHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to submit report: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("monitor rejected report: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// BuildReport compares the host against the archive state published by the monitor.
+// status may be nil when the monitor could not be queried.
+func BuildReport(info *HostInfo, status *PackageStatus) *Report {
+	report := &Report{
+		HostInfo:  *info,
+		Status:    StatusUnknown,
+		CheckedAt: time.Now(),
+	}
+
+	pkg := primaryPackage(info.Packages)
+	if pkg == nil {
+		report.Status = StatusNotInstalled
+		return report
+	}
+	report.SourcePackage = pkg.Source
+	report.InstalledVersion = pkg.Version
+
+	upstream := upstreamVersion(pkg.Version)
+	if info.SMIDriverVersion != "" && info.SMIDriverVersion != upstream {
+		report.Notes = append(report.Notes, fmt.Sprintf("nvidia-smi reports %s but %s is installed (reboot pending?)", info.SMIDriverVersion, upstream))
+	}
+	if info.ModuleVersion != "" && info.ModuleVersion != upstream {
+		report.Notes = append(report.Notes, fmt.Sprintf("loaded kernel module is %s but %s is installed (reboot pending?)", info.ModuleVersion, upstream))
+	}
+
+	if status == nil {
+		return report
+	}
+
+	var row *SeriesStatus
+	for i := range status.Series {
+		if status.Series[i].Series == info.Series {
+			row = &status.Series[i]
+			break
+		}
+	}
+	if row == nil {
+		report.Notes = append(report.Notes, fmt.Sprintf("series %q is not tracked for %s", info.Series, pkg.Source))
+		return report
+	}
+	report.LatestVersion = row.UpdatesSecurity
+	report.ProposedVersion = row.Proposed
+
+	installed, err := version.NewVersion(pkg.Version)
+	if err != nil {
+		report.Notes = append(report.Notes, fmt.Sprintf("cannot parse installed version %s", pkg.Version))
+		return report
+	}
+	latest, err := version.NewVersion(row.UpdatesSecurity)
+	if err != nil {
+		report.Notes = append(report.Notes, fmt.Sprintf("no published version for %s in %s", pkg.Source, info.Series))
+		return report
+	}
+
+	switch {
+	case installed.LessThan(latest):
+		report.Status = StatusOutdated
+	case installed.GreaterThan(latest):
+		report.Status = StatusNewer
+		if row.Proposed == pkg.Version {
+			report.Notes = append(report.Notes, "installed version comes from -proposed")
+		}
+	default:
+		report.Status = StatusUpToDate
+		report.UpToDate = true
+	}
+
+	return report
+}
+
+// upstreamVersion strips the Debian revision from a package version
+func upstreamVersion(v string) string {
+	if i := strings.IndexRune(v, '-'); i >= 0 {
+		return v[:i]
+	}
+	return v
+}
@@ -0,0 +1,76 @@
+package hostcheck
+
+import (
+	"testing"
+)
+
+func TestParseOSRelease(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"NAME=\"Ubuntu\"\nVERSION_CODENAME=noble\nUBUNTU_CODENAME=noble\n", "noble"},
+		{"NAME=\"Ubuntu\"\nUBUNTU_CODENAME=\"jammy\"\n", "jammy"},
+		{"NAME=\"Debian\"\n", ""},
+	}
+
+	for _, test := range tests {
+		result := ParseOSRelease(test.content)
+		if result != test.expected {
+			t.Errorf("ParseOSRelease(%q) = %s, expected %s", test.content, result, test.expected)
+		}
+	}
+}
+
+func TestParseDpkgOutput(t *testing.T) {
+	output := "nvidia-driver-570\t570.153.02-0ubuntu0.24.04.1\tnvidia-graphics-drivers-570\tinstalled\n" +
+		"libnvidia-gl-570\t570.153.02-0ubuntu0.24.04.1\tnvidia-graphics-drivers-570 (570.153.02-0ubuntu0.24.04.1)\tinstalled\n" +
+		"nvidia-driver-550\t550.163.01-0ubuntu0.24.04.1\tnvidia-graphics-drivers-550\tconfig-files\n" +
+		"bash\t5.2.21-2ubuntu4\tbash\tinstalled\n"
+
+	packages := ParseDpkgOutput(output)
+	if len(packages) != 2 {
+		t.Fatalf("ParseDpkgOutput returned %d packages, expected 2", len(packages))
+	}
+	if packages[1].Name != "nvidia-driver-570" || packages[1].Source != "nvidia-graphics-drivers-570" {
+		t.Errorf("ParseDpkgOutput()[1] = %+v, expected nvidia-driver-570 from nvidia-graphics-drivers-570", packages[1])
+	}
+	if packages[0].Source != "nvidia-graphics-drivers-570" {
+		t.Errorf("ParseDpkgOutput()[0].Source = %s, expected version suffix to be stripped", packages[0].Source)
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	status := &PackageStatus{
+		PackageName: "nvidia-graphics-drivers-570",
+		Series: []SeriesStatus{
+			{Series: "noble", UpdatesSecurity: "570.153.02-0ubuntu0.24.04.1", Proposed: "570.169-0ubuntu0.24.04.1"},
+		},
+	}
+
+	tests := []struct {
+		installed string
+		expected  string
+	}{
+		{"570.153.02-0ubuntu0.24.04.1", StatusUpToDate},
+		{"570.133.07-0ubuntu0.24.04.1", StatusOutdated},
+		{"570.169-0ubuntu0.24.04.1", StatusNewer},
+	}
+
+	for _, test := range tests {
+		info := &HostInfo{
+			Series: "noble",
+			Packages: []InstalledPackage{
+				{Name: "nvidia-driver-570", Version: test.installed, Source: "nvidia-graphics-drivers-570"},
+			},
+		}
+		report := BuildReport(info, status)
+		if report.Status != test.expected {
+			t.Errorf("BuildReport(%s) = %s, expected %s", test.installed, report.Status, test.expected)
+		}
+	}
+
+	if report := BuildReport(&HostInfo{Series: "noble"}, status); report.Status != StatusNotInstalled {
+		t.Errorf("BuildReport(no packages) = %s, expected %s", report.Status, StatusNotInstalled)
+	}
+}
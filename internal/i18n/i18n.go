@@ -0,0 +1,122 @@
+// Package i18n translates the user-facing strings of the web UI. Messages live in one JSON
+// catalog per locale under locales/, keyed by dotted names such as "index.title", and are
+// formatted with fmt verbs when called with arguments.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the locale every catalog falls back to
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs holds the messages of each locale, keyed by message name
+var catalogs = mustLoadCatalogs()
+
+// mustLoadCatalogs parses the embedded catalogs, named after their locale (e.g. es.json)
+func mustLoadCatalogs() map[string]map[string]string {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: failed to list catalogs: %v", err))
+	}
+	loaded := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to read %s: %v", file.Name(), err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: failed to parse %s: %v", file.Name(), err))
+		}
+		loaded[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	if _, ok := loaded[DefaultLocale]; !ok {
+		panic("i18n: missing the " + DefaultLocale + " catalog")
+	}
+	return loaded
+}
+
+// Supported returns the available locales in alphabetical order
+func Supported() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// IsSupported reports whether a catalog exists for locale
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// T returns the message key in locale, falling back to the default locale and then to the key
+// itself. Arguments are formatted into the message with fmt.Sprintf.
+func T(locale, key string, args ...interface{}) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// Missing returns the keys of the default catalog that locale does not translate
+func Missing(locale string) []string {
+	var missing []string
+	for key := range catalogs[DefaultLocale] {
+		if _, ok := catalogs[locale][key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Negotiate picks the supported locale preferred by an Accept-Language header, e.g.
+// "es-AR,es;q=0.9,en;q=0.8", matching regional tags by their language. It returns fallback
+// when nothing matches.
+func Negotiate(acceptLanguage, fallback string) string {
+	type preference struct {
+		locale string
+		q      float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		language, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if q > 0 && IsSupported(language) {
+			preferences = append(preferences, preference{language, q})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].q > preferences[j].q })
+	if len(preferences) > 0 {
+		return preferences[0].locale
+	}
+	return fallback
+}
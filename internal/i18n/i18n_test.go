@@ -0,0 +1,59 @@
+package i18n
+
+import "testing"
+
+func TestCatalogsTranslateEveryMessage(t *testing.T) {
+	for _, locale := range Supported() {
+		if missing := Missing(locale); len(missing) > 0 {
+			t.Errorf("catalog %s is missing %v", locale, missing)
+		}
+	}
+	if !IsSupported("es") {
+		t.Errorf("Supported() = %v, expected a Spanish catalog", Supported())
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		locale   string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en", "legend.green", nil, "Green"},
+		{"es", "legend.green", nil, "Verde"},
+		{"es", "index.series_outdated", []interface{}{2, 5}, "2 de 5 series desactualizadas"},
+		{"fr", "legend.green", nil, "Green"},
+		{"es", "no.such.key", nil, "no.such.key"},
+	}
+	for _, tt := range tests {
+		if got := T(tt.locale, tt.key, tt.args...); got != tt.expected {
+			t.Errorf("T(%q, %q, %v) = %q, expected %q", tt.locale, tt.key, tt.args, got, tt.expected)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header   string
+		fallback string
+		expected string
+	}{
+		{"", "en", "en"},
+		{"", "es", "es"},
+		{"es", "en", "es"},
+		{"es-AR,es;q=0.9,en;q=0.8", "en", "es"},
+		{"pt-BR,es-MX;q=0.7,en;q=0.5", "en", "es"},
+		{"en-US,en;q=0.9,es;q=0.8", "es", "en"},
+		{"es;q=0.4,en;q=0.6", "es", "en"},
+		{"es;q=0", "en", "en"},
+		{"fr-FR,de", "es", "es"},
+		{"ES-es", "en", "es"},
+		{"es;q=bogus,en", "es", "en"},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header, tt.fallback); got != tt.expected {
+			t.Errorf("Negotiate(%q, %q) = %q, expected %q", tt.header, tt.fallback, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package janitor bounds the disk caches of the monitor: files past the maximum age of their
+// cache are evicted, then the least recently written ones until the cache fits its maximum size.
+package janitor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Cache is a directory of cached files and its limits
+type Cache struct {
+	Name     string
+	Dir      string
+	MaxAge   time.Duration // Zero keeps files of any age
+	MaxBytes int64         // Zero sets no size limit
+}
+
+// Report is the disk footprint of a cache and what the janitor evicted from it since startup
+type Report struct {
+	Name         string     `json:"name"`
+	Dir          string     `json:"dir"`
+	Files        int        `json:"files"`
+	Bytes        int64      `json:"bytes"`
+	MaxAge       string     `json:"max_age,omitempty"`
+	MaxBytes     int64      `json:"max_bytes,omitempty"`
+	Evictions    int        `json:"evictions"`     // Files evicted since startup
+	EvictedBytes int64      `json:"evicted_bytes"` // Bytes freed since startup
+	LastPrune    *time.Time `json:"last_prune,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// cachedFile is a file found in a cache directory
+type cachedFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// scan lists the regular files under dir; a missing directory is an empty cache
+func scan(dir string) ([]cachedFile, error) {
+	var files []cachedFile
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil // Removed while walking
+			}
+			return err
+		}
+		files = append(files, cachedFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// prune evicts the files of a cache past its limits and returns the number of files and bytes
+// removed and the files kept
+func prune(cache Cache, now time.Time) (int, int64, []cachedFile, error) {
+	files, err := scan(cache.Dir)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+	evicted, freed := 0, int64(0)
+	kept := files[:0]
+	for i, file := range files {
+		expired := cache.MaxAge > 0 && now.Sub(file.modTime) > cache.MaxAge
+		oversized := cache.MaxBytes > 0 && total > cache.MaxBytes
+		if !expired && !oversized {
+			kept = append(kept, files[i:]...)
+			break
+		}
+		if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			return evicted, freed, nil, fmt.Errorf("failed to evict %s: %w", file.path, err)
+		}
+		evicted++
+		freed += file.size
+		total -= file.size
+	}
+	return evicted, freed, kept, nil
+}
+
+// Janitor prunes a set of caches and keeps their eviction counters
+type Janitor struct {
+	caches []Cache
+
+	mu           sync.Mutex
+	evictions    map[string]int
+	evictedBytes map[string]int64
+	lastPrune    map[string]time.Time
+}
+
+// New returns a janitor of the given caches
+func New(caches []Cache) *Janitor {
+	return &Janitor{
+		caches:       caches,
+		evictions:    make(map[string]int),
+		evictedBytes: make(map[string]int64),
+		lastPrune:    make(map[string]time.Time),
+	}
+}
+
+// Prune evicts the files past the limits of every cache and returns the caches' footprint
+// afterwards. A cache that fails to prune keeps the files it did not get to.
+func (j *Janitor) Prune(now time.Time) []Report {
+	reports := make([]Report, 0, len(j.caches))
+	for _, cache := range j.caches {
+		evicted, freed, kept, err := prune(cache, now)
+
+		j.mu.Lock()
+		j.evictions[cache.Name] += evicted
+		j.evictedBytes[cache.Name] += freed
+		j.lastPrune[cache.Name] = now
+		j.mu.Unlock()
+
+		if err != nil {
+			reports = append(reports, j.report(cache, nil, err))
+			continue
+		}
+		reports = append(reports, j.report(cache, kept, nil))
+	}
+	return reports
+}
+
+// Usage returns the current footprint of every cache without evicting anything
+func (j *Janitor) Usage() []Report {
+	reports := make([]Report, 0, len(j.caches))
+	for _, cache := range j.caches {
+		files, err := scan(cache.Dir)
+		reports = append(reports, j.report(cache, files, err))
+	}
+	return reports
+}
+
+// report builds the report of a cache holding the given files
+func (j *Janitor) report(cache Cache, files []cachedFile, err error) Report {
+	report := Report{Name: cache.Name, Dir: cache.Dir, MaxBytes: cache.MaxBytes}
+	if cache.MaxAge > 0 {
+		report.MaxAge = cache.MaxAge.String()
+	}
+	for _, file := range files {
+		report.Files++
+		report.Bytes += file.size
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	report.Evictions = j.evictions[cache.Name]
+	report.EvictedBytes = j.evictedBytes[cache.Name]
+	if last, ok := j.lastPrune[cache.Name]; ok {
+		report.LastPrune = &last
+	}
+	return report
+}
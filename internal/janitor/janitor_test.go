@@ -0,0 +1,68 @@
+package janitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCached writes a cached file of the given size, last written age ago
+func writeCached(t *testing.T, path string, size int, age time.Duration, now time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPruneEvictsExpiredThenOldestFiles(t *testing.T) {
+	now := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	writeCached(t, filepath.Join(dir, "expired.dsc"), 100, 10*24*time.Hour, now)
+	writeCached(t, filepath.Join(dir, "old.dsc"), 300, 3*24*time.Hour, now)
+	writeCached(t, filepath.Join(dir, "nested", "recent.dsc"), 300, time.Hour, now)
+	writeCached(t, filepath.Join(dir, "new.dsc"), 300, time.Minute, now)
+
+	j := New([]Cache{{Name: "dsc", Dir: dir, MaxAge: 7 * 24 * time.Hour, MaxBytes: 700}})
+	reports := j.Prune(now)
+	if len(reports) != 1 {
+		t.Fatalf("Prune() returned %d reports, expected 1", len(reports))
+	}
+	report := reports[0]
+	if report.Error != "" || report.Files != 2 || report.Bytes != 600 || report.Evictions != 2 || report.EvictedBytes != 400 {
+		t.Errorf("Prune() = %+v, expected the expired and the oldest file evicted", report)
+	}
+	for _, name := range []string{"expired.dsc", "old.dsc"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should have been evicted", name)
+		}
+	}
+
+	// Counters add up across prunes, and a cache within its limits is left alone
+	reports = j.Prune(now)
+	if report := reports[0]; report.Evictions != 2 || report.Files != 2 || report.LastPrune == nil {
+		t.Errorf("second Prune() = %+v, expected nothing more evicted", report)
+	}
+}
+
+func TestUsageWithoutLimitsOrDirectory(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	writeCached(t, filepath.Join(dir, "launchpad", "sources.json"), 50, 365*24*time.Hour, now)
+
+	j := New([]Cache{{Name: "fixtures", Dir: dir}, {Name: "changelog", Dir: filepath.Join(dir, "missing")}})
+	j.Prune(now)
+	reports := j.Usage()
+	if reports[0].Files != 1 || reports[0].Bytes != 50 || reports[0].Evictions != 0 {
+		t.Errorf("Usage() of a cache without limits = %+v, expected its file kept", reports[0])
+	}
+	if reports[1].Error != "" || reports[1].Files != 0 {
+		t.Errorf("Usage() of a missing directory = %+v, expected an empty cache", reports[1])
+	}
+}
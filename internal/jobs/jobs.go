@@ -0,0 +1,201 @@
+// Package jobs runs the exports and reports triggered through the API in the background, so
+// CI systems can chain them into release pipelines, and keeps their output on disk until it
+// expires.
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job states
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// filePrefix names the output files, so that only job output is removed from the directory
+const filePrefix = "job-"
+
+// ErrBusy is returned when the maximum number of jobs is already running
+var ErrBusy = errors.New("too many jobs are running")
+
+// Job is an export or report run in the background
+type Job struct {
+	ID          string     `json:"id"`
+	Kind        string     `json:"kind"` // "export" or "report"
+	Type        string     `json:"type"` // e.g. "packages" or "lrm"
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // When the output is removed
+	Error       string     `json:"error,omitempty"`
+	ContentType string     `json:"content_type,omitempty"`
+	Size        int        `json:"size,omitempty"`
+}
+
+// Output is what a job produced
+type Output struct {
+	ContentType string
+	Body        []byte
+}
+
+// Task produces the output of a job
+type Task func() (Output, error)
+
+// Store runs jobs and keeps them and their output until the retention period is over
+type Store struct {
+	dir        string
+	retention  time.Duration
+	maxRunning int
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	running int
+	wg      sync.WaitGroup
+}
+
+// NewStore creates a store writing job output to dir. Output left over from a previous run is
+// removed, as the jobs it belonged to are not known anymore.
+func NewStore(dir string, retention time.Duration, maxRunning int) *Store {
+	s := &Store{dir: dir, retention: retention, maxRunning: maxRunning, jobs: make(map[string]*Job)}
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name(), filePrefix) {
+				os.Remove(filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return s
+}
+
+// newID returns a random job ID
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate job ID: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// path returns the output file of a job
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, filePrefix+id)
+}
+
+// Submit starts a job and returns it in its running state, or ErrBusy when the maximum number
+// of jobs is already running
+func (s *Store) Submit(kind, jobType string, task Task, now time.Time) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked(now)
+	if s.maxRunning > 0 && s.running >= s.maxRunning {
+		return Job{}, ErrBusy
+	}
+
+	job := &Job{ID: newID(), Kind: kind, Type: jobType, Status: StatusRunning, CreatedAt: now}
+	s.jobs[job.ID] = job
+	s.running++
+	s.wg.Add(1)
+	go s.run(job.ID, task)
+	return *job, nil
+}
+
+// run runs a task and records its outcome
+func (s *Store) run(id string, task Task) {
+	defer s.wg.Done()
+	output, err := func() (output Output, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("job panicked: %v", r)
+			}
+		}()
+		return task()
+	}()
+	if err == nil {
+		if err = os.MkdirAll(s.dir, 0755); err == nil {
+			err = os.WriteFile(s.path(id), output.Body, 0644)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+	job, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	finished := time.Now()
+	expires := finished.Add(s.retention)
+	job.FinishedAt, job.ExpiresAt = &finished, &expires
+	if err != nil {
+		job.Status, job.Error = StatusFailed, err.Error()
+		log.Printf("Warning: %s job %s (%s) failed: %v", job.Kind, job.ID, job.Type, err)
+		return
+	}
+	job.Status, job.ContentType, job.Size = StatusSucceeded, output.ContentType, len(output.Body)
+	log.Printf("%s job %s (%s) finished: %d bytes", job.Kind, job.ID, job.Type, job.Size)
+}
+
+// expireLocked forgets the jobs past their retention and removes their output
+func (s *Store) expireLocked(now time.Time) {
+	for id, job := range s.jobs {
+		if job.ExpiresAt != nil && now.After(*job.ExpiresAt) {
+			os.Remove(s.path(id))
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// Get returns a job by ID
+func (s *Store) Get(id string, now time.Time) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked(now)
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// List returns the known jobs, newest first
+func (s *Store) List(now time.Time) []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLocked(now)
+	list := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		list = append(list, *job)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list
+}
+
+// Read returns the output of a job that succeeded
+func (s *Store) Read(id string, now time.Time) (Job, []byte, error) {
+	job, ok := s.Get(id, now)
+	if !ok {
+		return job, nil, os.ErrNotExist
+	}
+	if job.Status != StatusSucceeded {
+		return job, nil, fmt.Errorf("job %s is %s", id, job.Status)
+	}
+	body, err := os.ReadFile(s.path(id))
+	return job, body, err
+}
+
+// Wait blocks until the running jobs are done
+func (s *Store) Wait() {
+	s.wg.Wait()
+}
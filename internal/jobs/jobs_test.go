@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreRunsJobsAndKeepsTheirOutput(t *testing.T) {
+	dir := t.TempDir()
+	leftover := filepath.Join(dir, filePrefix+"stale")
+	os.WriteFile(leftover, []byte("old"), 0644)
+	store := NewStore(dir, time.Hour, 0)
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Errorf("output of a previous run should be removed")
+	}
+
+	now := time.Now()
+	succeeded, err := store.Submit("export", "packages", func() (Output, error) {
+		return Output{ContentType: "application/json", Body: []byte(`{"packages":{}}`)}, nil
+	}, now)
+	if err != nil || succeeded.Status != StatusRunning {
+		t.Fatalf("Submit() = %+v, %v, expected a running job", succeeded, err)
+	}
+	failed, _ := store.Submit("report", "lrm", func() (Output, error) { return Output{}, errors.New("no data") }, now)
+	panicked, _ := store.Submit("report", "lrm", func() (Output, error) { panic("boom") }, now)
+	store.Wait()
+
+	job, body, err := store.Read(succeeded.ID, now)
+	if err != nil || job.Status != StatusSucceeded || string(body) != `{"packages":{}}` || job.ContentType != "application/json" {
+		t.Errorf("Read() = %+v, %q, %v, expected the export output", job, body, err)
+	}
+	for _, id := range []string{failed.ID, panicked.ID} {
+		if job, _ := store.Get(id, now); job.Status != StatusFailed || job.Error == "" {
+			t.Errorf("Get(%s) = %+v, expected a failed job with its error", id, job)
+		}
+		if _, _, err := store.Read(id, now); err == nil {
+			t.Errorf("Read() of a failed job should return an error")
+		}
+	}
+	if list := store.List(now); len(list) != 3 {
+		t.Errorf("List() returned %d jobs, expected 3", len(list))
+	}
+
+	// Past the retention the job and its output are gone
+	if _, ok := store.Get(succeeded.ID, now.Add(2*time.Hour)); ok {
+		t.Errorf("Get() after the retention should not find the job")
+	}
+	if _, err := os.Stat(filepath.Join(dir, filePrefix+succeeded.ID)); !os.IsNotExist(err) {
+		t.Errorf("output should be removed after the retention")
+	}
+}
+
+func TestStoreRefusesJobsBeyondMaxRunning(t *testing.T) {
+	store := NewStore(t.TempDir(), time.Hour, 1)
+	release := make(chan struct{})
+	if _, err := store.Submit("export", "packages", func() (Output, error) {
+		<-release
+		return Output{}, nil
+	}, time.Now()); err != nil {
+		t.Fatalf("Submit() returned error: %v", err)
+	}
+	if _, err := store.Submit("export", "matrix", func() (Output, error) { return Output{}, nil }, time.Now()); !errors.Is(err, ErrBusy) {
+		t.Errorf("Submit() while a job runs = %v, expected ErrBusy", err)
+	}
+	close(release)
+	store.Wait()
+	if _, err := store.Submit("export", "matrix", func() (Output, error) { return Output{}, nil }, time.Now()); err != nil {
+		t.Errorf("Submit() once the job finished returned error: %v", err)
+	}
+	store.Wait()
+}
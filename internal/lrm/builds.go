@@ -0,0 +1,158 @@
+package lrm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Summarized build states of an L-R-M publication
+const (
+	BuildStateBuilt       = "built"
+	BuildStateBuilding    = "building"
+	BuildStateFailed      = "failed"
+	BuildStateUploadQueue = "upload-queue"
+	BuildStateUnknown     = "unknown"
+)
+
+// buildTrackedPackageTypes are the kernel-series package types whose builds are tracked
+var buildTrackedPackageTypes = map[string]bool{
+	"lrm": true, // linux-restricted-modules
+	"lrs": true, // linux-restricted-signatures
+}
+
+// LaunchpadBuild represents a build record from the Launchpad API
+type LaunchpadBuild struct {
+	ArchTag    string `json:"arch_tag"`
+	BuildState string `json:"buildstate"`
+	WebLink    string `json:"web_link"`
+}
+
+// launchpadBuildsResponse represents a collection of build records from the Launchpad API
+type launchpadBuildsResponse struct {
+	Entries []LaunchpadBuild `json:"entries"`
+}
+
+// ArchBuild is the build state of a publication on one architecture
+type ArchBuild struct {
+	Arch    string
+	State   string // Launchpad buildstate, e.g. "Successfully built"
+	WebLink string
+}
+
+// BuildStatus summarizes the Launchpad builds of the latest publication of an L-R-M package
+type BuildStatus struct {
+	Package string
+	Version string
+	Pocket  string
+	State   string // One of the BuildState* constants
+	Summary string // e.g. "Failed to build on arm64"
+	Builds  []ArchBuild
+}
+
+// SummarizeBuilds reduces per-architecture build records to a single state and description.
+// Failures take precedence over builds in progress, which take precedence over uploads.
+func SummarizeBuilds(builds []ArchBuild) (string, string) {
+	if len(builds) == 0 {
+		return BuildStateUnknown, "No build records"
+	}
+
+	var failed, building, uploading []string
+	for _, build := range builds {
+		switch build.State {
+		case "Failed to build", "Failed to upload", "Chroot problem", "Dependency wait", "Cancelled build":
+			failed = append(failed, build.Arch)
+		case "Needs building", "Currently building", "Gathering build output", "Cancelling build":
+			building = append(building, build.Arch)
+		case "Uploading build":
+			uploading = append(uploading, build.Arch)
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		return BuildStateFailed, "Failed to build on " + strings.Join(failed, ", ")
+	case len(building) > 0:
+		return BuildStateBuilding, "Building on " + strings.Join(building, ", ")
+	case len(uploading) > 0:
+		return BuildStateUploadQueue, "Waiting in upload queue (" + strings.Join(uploading, ", ") + ")"
+	}
+	return BuildStateBuilt, "Built"
+}
+
+// queryBuildStatus returns the build status of the newest publication of a package in a series,
+// including publications still pending in -proposed
+func queryBuildStatus(packageName, codename, dateThreshold string) *BuildStatus {
+	apiResp, err := fetchPublications(packageName, dateThreshold)
+	if err != nil {
+		log.Printf("Error querying publications of %s for builds: %v", packageName, err)
+		return nil
+	}
+
+	// Entries are ordered newest first
+	var latest *LaunchpadPackageEntry
+	for i := range apiResp.Entries {
+		entry := &apiResp.Entries[i]
+		if entry.Status != "Published" && entry.Status != "Pending" {
+			continue
+		}
+		if extractSeriesFromLink(entry.DistroSeriesLink) == codename {
+			latest = entry
+			break
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	status := &BuildStatus{
+		Package: packageName,
+		Version: latest.SourcePackageVersion,
+		Pocket:  latest.Pocket,
+	}
+
+	builds, err := fetchBuilds(latest.SelfLink)
+	if err != nil {
+		log.Printf("Error querying builds of %s %s: %v", packageName, latest.SourcePackageVersion, err)
+		status.State = BuildStateUnknown
+		status.Summary = "Build records unavailable"
+		return status
+	}
+
+	status.Builds = builds
+	status.State, status.Summary = SummarizeBuilds(builds)
+	return status
+}
+
+// fetchBuilds fetches the build records of a source publication
+func fetchBuilds(publicationLink string) ([]ArchBuild, error) {
+	if publicationLink == "" {
+		return nil, fmt.Errorf("publication has no self link")
+	}
+
+	resp, err := utils.LaunchpadGetWithRetry(publicationLink + "?ws.op=getBuilds")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("HTTP error: %d", resp.StatusCode)
+	}
+
+	var apiResp launchpadBuildsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("JSON decode error: %v", err)
+	}
+
+	builds := make([]ArchBuild, 0, len(apiResp.Entries))
+	for _, entry := range apiResp.Entries {
+		builds = append(builds, ArchBuild{Arch: entry.ArchTag, State: entry.BuildState, WebLink: entry.WebLink})
+	}
+	sort.Slice(builds, func(i, j int) bool { return builds[i].Arch < builds[j].Arch })
+	return builds, nil
+}
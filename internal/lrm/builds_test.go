@@ -0,0 +1,41 @@
+package lrm
+
+import "testing"
+
+func TestSummarizeBuilds(t *testing.T) {
+	tests := []struct {
+		name          string
+		builds        []ArchBuild
+		expectedState string
+		expectedText  string
+	}{
+		{"no builds", nil, BuildStateUnknown, "No build records"},
+		{"all built", []ArchBuild{
+			{Arch: "amd64", State: "Successfully built"},
+			{Arch: "arm64", State: "Successfully built"},
+		}, BuildStateBuilt, "Built"},
+		{"failed on arm64", []ArchBuild{
+			{Arch: "amd64", State: "Successfully built"},
+			{Arch: "arm64", State: "Failed to build"},
+		}, BuildStateFailed, "Failed to build on arm64"},
+		{"failure wins over building", []ArchBuild{
+			{Arch: "amd64", State: "Currently building"},
+			{Arch: "arm64", State: "Dependency wait"},
+		}, BuildStateFailed, "Failed to build on arm64"},
+		{"building", []ArchBuild{
+			{Arch: "amd64", State: "Needs building"},
+			{Arch: "arm64", State: "Uploading build"},
+		}, BuildStateBuilding, "Building on amd64"},
+		{"upload queue", []ArchBuild{
+			{Arch: "amd64", State: "Uploading build"},
+			{Arch: "arm64", State: "Successfully built"},
+		}, BuildStateUploadQueue, "Waiting in upload queue (amd64)"},
+	}
+
+	for _, tt := range tests {
+		state, text := SummarizeBuilds(tt.builds)
+		if state != tt.expectedState || text != tt.expectedText {
+			t.Errorf("SummarizeBuilds(%s) = %s, %q, expected %s, %q", tt.name, state, text, tt.expectedState, tt.expectedText)
+		}
+	}
+}
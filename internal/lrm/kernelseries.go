@@ -0,0 +1,378 @@
+package lrm
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kernel-series.yaml layouts recognized by ParseKernelSeries
+const (
+	SchemaFlat     = 1 // Series at the top level, no defaults section
+	SchemaDefaults = 2 // Series at the top level next to a defaults section holding the routing table
+	SchemaNested   = 3 // Series nested under a top-level "series" key
+)
+
+// nonSeriesKeys are top-level keys that never describe a series
+var nonSeriesKeys = map[string]bool{
+	"defaults":       true,
+	"routing-table":  true,
+	"schema-version": true,
+	"series":         true,
+}
+
+// hweVariantPattern matches the variants of a kernel rolled out to an LTS as its hardware
+// enablement kernel, e.g. "-hwe-24.04" or "-hwe-24.04-edge"
+var hweVariantPattern = regexp.MustCompile(`^-hwe-(\d+\.\d+)(?:-edge)?$`)
+
+// HWETargets returns the LTS series a kernel source of a series is rolled out to as HWE kernel,
+// from its variants. The GA kernel of an LTS lists its own series, which is left out.
+func HWETargets(series string, variants []string) []string {
+	var targets []string
+	seen := make(map[string]bool)
+	for _, variant := range variants {
+		m := hweVariantPattern.FindStringSubmatch(variant)
+		if m == nil || m[1] == series || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		targets = append(targets, m[1])
+	}
+	return targets
+}
+
+// KernelSeriesDiagnostics describes how the last kernel-series.yaml was parsed
+type KernelSeriesDiagnostics struct {
+	SchemaVersion int       `json:"schema_version"`
+	Series        int       `json:"series"`
+	Sources       int       `json:"sources"`
+	Warnings      []string  `json:"warnings"`
+	ParsedAt      time.Time `json:"parsed_at"`
+}
+
+// warnf records a field-level problem that was tolerated
+func (d *KernelSeriesDiagnostics) warnf(format string, args ...interface{}) {
+	d.Warnings = append(d.Warnings, fmt.Sprintf(format, args...))
+}
+
+var (
+	diagnosticsMu   sync.RWMutex
+	lastDiagnostics *KernelSeriesDiagnostics
+)
+
+// GetKernelSeriesDiagnostics returns the diagnostics of the last parsed kernel-series.yaml, or nil
+func GetKernelSeriesDiagnostics() *KernelSeriesDiagnostics {
+	diagnosticsMu.RLock()
+	defer diagnosticsMu.RUnlock()
+	if lastDiagnostics == nil {
+		return nil
+	}
+	copied := *lastDiagnostics
+	copied.Warnings = append([]string(nil), lastDiagnostics.Warnings...)
+	return &copied
+}
+
+// parseKernelSeries parses kernel-series.yaml and keeps its diagnostics for the diagnostics API
+func parseKernelSeries(body []byte) (KernelSeries, error) {
+	kernelSeries, diagnostics, err := ParseKernelSeries(body)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range diagnostics.Warnings {
+		log.Printf("Warning: kernel-series.yaml: %s", warning)
+	}
+	diagnosticsMu.Lock()
+	lastDiagnostics = diagnostics
+	diagnosticsMu.Unlock()
+	return kernelSeries, nil
+}
+
+// ParseKernelSeries detects the layout of kernel-series.yaml and parses it tolerantly: entries
+// with unexpected types are coerced or skipped with a warning instead of failing the whole file.
+// Only a document that is not a YAML mapping is an error.
+func ParseKernelSeries(body []byte) (KernelSeries, *KernelSeriesDiagnostics, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse kernel-series.yaml: %v", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("failed to parse kernel-series.yaml: top level is not a mapping")
+	}
+	root := doc.Content[0]
+
+	diagnostics := &KernelSeriesDiagnostics{ParsedAt: time.Now(), Warnings: []string{}}
+	diagnostics.SchemaVersion = detectSchema(root, diagnostics)
+
+	seriesRoot := root
+	if diagnostics.SchemaVersion == SchemaNested {
+		seriesRoot = mappingValue(root, "series")
+	}
+
+	kernelSeries := make(KernelSeries)
+	forEachMapping(seriesRoot, func(key string, value *yaml.Node) {
+		if seriesRoot == root && nonSeriesKeys[key] {
+			return
+		}
+		if value.Kind != yaml.MappingNode {
+			diagnostics.warnf("series %s: expected a mapping, got %s; skipped", key, describeNode(value))
+			return
+		}
+		info := parseSeriesInfo(key, value, diagnostics)
+		kernelSeries[key] = info
+		diagnostics.Sources += len(info.Sources)
+	})
+	diagnostics.Series = len(kernelSeries)
+	return kernelSeries, diagnostics, nil
+}
+
+// detectSchema returns the layout of the document, honoring an explicit schema-version key
+func detectSchema(root *yaml.Node, diagnostics *KernelSeriesDiagnostics) int {
+	if node := mappingValue(root, "schema-version"); node != nil {
+		version, err := strconv.Atoi(node.Value)
+		if err == nil && version >= SchemaFlat && version <= SchemaNested {
+			return version
+		}
+		diagnostics.warnf("unsupported schema-version %q; detecting the layout instead", node.Value)
+	}
+	if series := mappingValue(root, "series"); series != nil && series.Kind == yaml.MappingNode {
+		return SchemaNested
+	}
+	if mappingValue(root, "defaults") != nil || mappingValue(root, "routing-table") != nil {
+		return SchemaDefaults
+	}
+	return SchemaFlat
+}
+
+// parseSeriesInfo decodes one series entry field by field
+func parseSeriesInfo(series string, node *yaml.Node, diagnostics *KernelSeriesDiagnostics) SeriesInfo {
+	info := SeriesInfo{Sources: make(map[string]SourceInfo)}
+	path := "series " + series
+	forEachMapping(node, func(key string, value *yaml.Node) {
+		switch key {
+		case "codename":
+			info.Codename = decodeString(value, path+": codename", diagnostics)
+		case "development":
+			info.Development = decodeBool(value, path+": development", diagnostics)
+		case "supported":
+			info.Supported = decodeBool(value, path+": supported", diagnostics)
+		case "lts":
+			info.LTS = decodeBool(value, path+": lts", diagnostics)
+		case "esm":
+			info.ESM = decodeBool(value, path+": esm", diagnostics)
+		case "sources":
+			if value.Kind != yaml.MappingNode {
+				if value.Tag != "!!null" {
+					diagnostics.warnf("%s: sources: expected a mapping, got %s; ignored", path, describeNode(value))
+				}
+				return
+			}
+			forEachMapping(value, func(source string, sourceNode *yaml.Node) {
+				if sourceNode.Tag == "!!null" {
+					info.Sources[source] = SourceInfo{}
+					return
+				}
+				if sourceNode.Kind != yaml.MappingNode {
+					diagnostics.warnf("%s: source %s: expected a mapping, got %s; skipped", path, source, describeNode(sourceNode))
+					return
+				}
+				info.Sources[source] = parseSourceInfo(path+": source "+source, sourceNode, diagnostics)
+			})
+		}
+	})
+	return info
+}
+
+// parseSourceInfo decodes one source entry field by field
+func parseSourceInfo(path string, node *yaml.Node, diagnostics *KernelSeriesDiagnostics) SourceInfo {
+	var info SourceInfo
+	forEachMapping(node, func(key string, value *yaml.Node) {
+		switch key {
+		case "routing":
+			// Some revisions list the routing as a one-element sequence
+			if value.Kind == yaml.SequenceNode && len(value.Content) > 0 {
+				diagnostics.warnf("%s: routing: expected a string, got a sequence; using the first entry", path)
+				value = value.Content[0]
+			}
+			info.Routing = decodeString(value, path+": routing", diagnostics)
+		case "versions":
+			info.Versions = decodeStrings(value, path+": versions", diagnostics)
+		case "variants":
+			info.Variants = decodeStrings(value, path+": variants", diagnostics)
+		case "package-relations":
+			info.PackageRelations = decodeString(value, path+": package-relations", diagnostics)
+		case "supported":
+			supported := decodeBool(value, path+": supported", diagnostics)
+			info.Supported = &supported
+		case "development":
+			development := decodeBool(value, path+": development", diagnostics)
+			info.Development = &development
+		case "packages":
+			info.Packages = parsePackages(path, value, diagnostics)
+		}
+	})
+	return info
+}
+
+// parsePackages decodes the packages of a source; entries may be null, a mapping or a bare type string
+func parsePackages(path string, node *yaml.Node, diagnostics *KernelSeriesDiagnostics) map[string]PackageInfo {
+	packages := make(map[string]PackageInfo)
+	if node.Kind != yaml.MappingNode {
+		if node.Tag != "!!null" {
+			diagnostics.warnf("%s: packages: expected a mapping, got %s; ignored", path, describeNode(node))
+		}
+		return packages
+	}
+	forEachMapping(node, func(name string, value *yaml.Node) {
+		var info PackageInfo
+		switch value.Kind {
+		case yaml.ScalarNode:
+			if value.Tag != "!!null" {
+				info.Type = value.Value
+			}
+		case yaml.MappingNode:
+			forEachMapping(value, func(key string, field *yaml.Node) {
+				switch key {
+				case "type":
+					info.Type = decodeString(field, path+": package "+name+": type", diagnostics)
+				case "repo":
+					info.Repo = decodeStrings(field, path+": package "+name+": repo", diagnostics)
+				}
+			})
+		default:
+			diagnostics.warnf("%s: package %s: expected a mapping, got %s; skipped", path, name, describeNode(value))
+			return
+		}
+		packages[name] = info
+	})
+	return packages
+}
+
+// decodeString returns a scalar as a string, warning about other node kinds
+func decodeString(node *yaml.Node, path string, diagnostics *KernelSeriesDiagnostics) string {
+	if node.Kind != yaml.ScalarNode {
+		diagnostics.warnf("%s: expected a string, got %s; ignored", path, describeNode(node))
+		return ""
+	}
+	if node.Tag == "!!null" {
+		return ""
+	}
+	return node.Value
+}
+
+// decodeBool returns a boolean, accepting the string spellings some revisions used
+func decodeBool(node *yaml.Node, path string, diagnostics *KernelSeriesDiagnostics) bool {
+	if node.Kind == yaml.ScalarNode {
+		switch strings.ToLower(node.Value) {
+		case "true":
+			return true
+		case "false", "", "~", "null":
+			return false
+		case "yes", "on", "1":
+			diagnostics.warnf("%s: expected a boolean, got %q; treated as true", path, node.Value)
+			return true
+		case "no", "off", "0":
+			diagnostics.warnf("%s: expected a boolean, got %q; treated as false", path, node.Value)
+			return false
+		}
+	}
+	diagnostics.warnf("%s: expected a boolean, got %s; treated as false", path, describeNode(node))
+	return false
+}
+
+// decodeStrings returns a sequence of scalars, accepting a single scalar as a one-element list
+func decodeStrings(node *yaml.Node, path string, diagnostics *KernelSeriesDiagnostics) []string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil
+		}
+		diagnostics.warnf("%s: expected a sequence, got a scalar; using it as a single entry", path)
+		return []string{node.Value}
+	case yaml.SequenceNode:
+		var values []string
+		for i, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				diagnostics.warnf("%s[%d]: expected a string, got %s; skipped", path, i, describeNode(item))
+				continue
+			}
+			values = append(values, item.Value)
+		}
+		return values
+	}
+	diagnostics.warnf("%s: expected a sequence, got %s; ignored", path, describeNode(node))
+	return nil
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// forEachMapping calls fn for every key of a mapping node in key order, resolving aliases
+// and "<<" merge keys
+func forEachMapping(node *yaml.Node, fn func(key string, value *yaml.Node)) {
+	if node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	values := make(map[string]*yaml.Node)
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		value := node.Content[i+1]
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		if node.Content[i].Value == "<<" {
+			merged = append(merged, value)
+			continue
+		}
+		values[node.Content[i].Value] = value
+	}
+	// Explicit keys take precedence over merged ones
+	for _, base := range merged {
+		forEachMapping(base, func(key string, value *yaml.Node) {
+			if _, ok := values[key]; !ok {
+				values[key] = value
+			}
+		})
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fn(key, values[key])
+	}
+}
+
+// describeNode names the kind of a node for warnings
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a sequence"
+	case yaml.ScalarNode:
+		return fmt.Sprintf("%q", node.Value)
+	}
+	return "an unsupported node"
+}
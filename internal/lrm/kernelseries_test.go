@@ -0,0 +1,113 @@
+package lrm
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the kernel-series golden files")
+
+// kernelSeriesGolden is the parsed form of a testdata layout compared against its golden file
+type kernelSeriesGolden struct {
+	SchemaVersion int          `json:"schema_version"`
+	Warnings      []string     `json:"warnings"`
+	Series        KernelSeries `json:"series"`
+}
+
+func TestParseKernelSeriesGolden(t *testing.T) {
+	layouts, err := filepath.Glob(filepath.Join("testdata", "kernel-series", "*.yaml"))
+	if err != nil || len(layouts) == 0 {
+		t.Fatalf("no kernel-series layouts found: %v", err)
+	}
+
+	for _, layout := range layouts {
+		name := strings.TrimSuffix(filepath.Base(layout), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			body, err := os.ReadFile(layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kernelSeries, diagnostics, err := ParseKernelSeries(body)
+			if err != nil {
+				t.Fatalf("ParseKernelSeries(%s) returned error: %v", name, err)
+			}
+			if diagnostics.Series != len(kernelSeries) {
+				t.Errorf("diagnostics.Series = %d, expected %d", diagnostics.Series, len(kernelSeries))
+			}
+
+			got, err := json.MarshalIndent(kernelSeriesGolden{
+				SchemaVersion: diagnostics.SchemaVersion,
+				Warnings:      diagnostics.Warnings,
+				Series:        kernelSeries,
+			}, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := strings.TrimSuffix(layout, ".yaml") + ".golden.json"
+			if *updateGolden {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("missing golden file (run go test -update): %v", err)
+			}
+			if !bytes.Equal(got, expected) {
+				t.Errorf("ParseKernelSeries(%s) does not match %s:\n%s", name, golden, got)
+			}
+		})
+	}
+}
+
+func TestParseKernelSeriesInvalid(t *testing.T) {
+	for _, body := range []string{"", "- 24.04\n- 22.04\n", "key: [unterminated"} {
+		if _, _, err := ParseKernelSeries([]byte(body)); err == nil {
+			t.Errorf("ParseKernelSeries(%q) expected an error", body)
+		}
+	}
+}
+
+func TestKernelSeriesDiagnosticsKept(t *testing.T) {
+	if _, err := parseKernelSeries([]byte("'24.04':\n    codename: noble\n    supported: 1\n")); err != nil {
+		t.Fatalf("parseKernelSeries returned error: %v", err)
+	}
+	diagnostics := GetKernelSeriesDiagnostics()
+	if diagnostics == nil || diagnostics.SchemaVersion != SchemaFlat || len(diagnostics.Warnings) != 1 {
+		t.Errorf("GetKernelSeriesDiagnostics() = %+v, expected a flat layout with one warning", diagnostics)
+	}
+}
+
+func TestKernelSeriesDevelopmentCodename(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "kernel-series", "defaults.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kernelSeries, _, err := ParseKernelSeries(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codename, err := kernelSeries.DevelopmentCodename(); err != nil || codename != "questing" {
+		t.Errorf("DevelopmentCodename() = %s, %v, expected questing", codename, err)
+	}
+	if _, err := (KernelSeries{}).DevelopmentCodename(); err == nil {
+		t.Errorf("DevelopmentCodename() of an empty file expected an error")
+	}
+}
+
+func TestHWETargets(t *testing.T) {
+	if targets := HWETargets("25.04", []string{"--", "-hwe-24.04", "-hwe-24.04-edge", "-generic"}); strings.Join(targets, ",") != "24.04" {
+		t.Errorf("HWETargets() = %v, expected [24.04]", targets)
+	}
+	// The GA kernel of an LTS is not an HWE kernel of it
+	if targets := HWETargets("24.04", []string{"--", "-hwe-24.04"}); len(targets) != 0 {
+		t.Errorf("HWETargets() of the GA kernel = %v, expected none", targets)
+	}
+}
@@ -1,8 +1,14 @@
 package lrm
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"nvidia_driver_monitor/internal/faults"
 )
 
 func TestLRMVerifierDataInitialization(t *testing.T) {
@@ -84,3 +90,109 @@ func TestSimplifyNvidiaDriverName(t *testing.T) {
 		}
 	}
 }
+
+func TestParseRoutingDefinitions(t *testing.T) {
+	content := []byte(`
+defaults:
+  routing-table:
+    ubuntu/4:
+      build: [['ppa:canonical-kernel-team/ubuntu/bootstrap', 'Release']]
+      proposed: [['ubuntu', 'Proposed'], ['ppa:canonical-kernel-team/ubuntu/proposed2', 'Release']]
+      updates: ['ubuntu', 'Updates']
+'22.04':
+  codename: jammy
+`)
+
+	definitions, err := ParseRoutingDefinitions(content)
+	if err != nil {
+		t.Fatalf("ParseRoutingDefinitions returned error: %v", err)
+	}
+
+	def, ok := definitions["ubuntu/4"]
+	if !ok {
+		t.Fatalf("Expected routing ubuntu/4 to be parsed, got %v", definitions)
+	}
+
+	if len(def.Destinations["proposed"]) != 2 {
+		t.Errorf("Expected 2 proposed destinations, got %d", len(def.Destinations["proposed"]))
+	}
+
+	updates := def.Destinations["updates"]
+	if len(updates) != 1 || updates[0].Archive != "ubuntu" || updates[0].Pocket != "Updates" {
+		t.Errorf("Unexpected updates destinations: %v", updates)
+	}
+
+	expected := "build → ppa:canonical-kernel-team/ubuntu/bootstrap (Release); " +
+		"proposed → ubuntu (Proposed), ppa:canonical-kernel-team/ubuntu/proposed2 (Release); " +
+		"updates → ubuntu (Updates)"
+	if def.Explanation != expected {
+		t.Errorf("Explanation = %q, expected %q", def.Explanation, expected)
+	}
+}
+
+func TestGetCachedLRMDataServesStaleDuringBackoff(t *testing.T) {
+	defer func() {
+		lrmCache = nil
+		nextRefreshAt = time.Time{}
+	}()
+	lrmCache = &LRMVerifierData{LastUpdated: time.Now().Add(-time.Hour), IsInitialized: true, TotalKernels: 3}
+	nextRefreshAt = time.Now().Add(time.Minute)
+
+	data, err := GetCachedLRMData()
+	if err != nil || !data.Stale || data.TotalKernels != 3 {
+		t.Errorf("GetCachedLRMData() = %+v, %v, expected the cached data marked stale", data, err)
+	}
+	if lrmCache.Stale {
+		t.Errorf("GetCachedLRMData() should not mark the shared cache stale")
+	}
+}
+
+func TestGetCachedLRMDataServesStaleWhenRefreshFails(t *testing.T) {
+	faults.Enable(true)
+	defer func() {
+		faults.Enable(false)
+		lrmCache = nil
+		nextRefreshAt = time.Time{}
+		refreshFailures = 0
+		lastRefreshError = ""
+	}()
+	if err := faults.Inject(faults.StageLRM, faults.ModeFail); err != nil {
+		t.Fatal(err)
+	}
+	lrmCache = &LRMVerifierData{LastUpdated: time.Now().Add(-2 * cacheExpiry), IsInitialized: true, TotalKernels: 3}
+
+	data, err := GetCachedLRMData()
+	if err != nil || !data.Stale || data.TotalKernels != 3 {
+		t.Errorf("GetCachedLRMData() = %+v, %v, expected the expired data marked stale", data, err)
+	}
+	if refreshFailures != 1 || !refreshBackedOff(time.Now()) || !strings.Contains(lastRefreshError, faults.ErrInjected.Error()) {
+		t.Errorf("failures = %d, error = %q; expected one failure backing off the next refresh", refreshFailures, lastRefreshError)
+	}
+	if age, loaded := GetCacheAge(); !loaded || age < cacheExpiry {
+		t.Errorf("GetCacheAge() = %v, %v, expected the age of the expired data for the watchdog", age, loaded)
+	}
+}
+
+func TestParseDSCFileRecordsProvenance(t *testing.T) {
+	content := "Format: 3.0 (native)\nSource: linux-restricted-modules\nUbuntu-Nvidia-Dependencies:\n nvidia-graphics-drivers-570 (= 570.172.08-0ubuntu0.24.04.1),\n nvidia-graphics-drivers-535-server (= 535.261.03-0ubuntu0.24.04.1)\n\n"
+	path := filepath.Join(t.TempDir(), "noble-linux-restricted-modules_6.8.0-45.45.dsc")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	downloaded := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	os.Chtimes(path, downloaded, downloaded)
+
+	url := "https://launchpad.net/ubuntu/+archive/primary/+sourcefiles/linux-restricted-modules/6.8.0-45.45%2Bhwe1/linux-restricted-modules_6.8.0-45.45%2Bhwe1.dsc"
+	drivers, dsc, err := parseDSCFile(path, url)
+	if err != nil {
+		t.Fatalf("parseDSCFile() returned error: %v", err)
+	}
+	expected := []string{"nvidia-graphics-drivers-570=570.172.08-0ubuntu0.24.04.1", "nvidia-graphics-drivers-535-server=535.261.03-0ubuntu0.24.04.1"}
+	if !reflect.DeepEqual(drivers, expected) {
+		t.Errorf("parseDSCFile() drivers = %v, expected %v", drivers, expected)
+	}
+	want := DSCProvenance{URL: url, Version: "6.8.0-45.45+hwe1", DownloadedAt: downloaded, SHA256: "5c43b1474fcad85b3b3828bc28a9cf7c1a8b811c632319893d3bcc0963f8461d"}
+	if dsc == nil || *dsc != want {
+		t.Errorf("parseDSCFile() provenance = %+v, expected %+v", dsc, want)
+	}
+}
@@ -1,21 +1,26 @@
 package lrm
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"net/url"
 	"os"
+	"path"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"nvidia_driver_monitor/internal/budget"
 	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/debuglog"
+	"nvidia_driver_monitor/internal/faults"
 	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/supervise"
 	"nvidia_driver_monitor/internal/utils"
-
-	"gopkg.in/yaml.v3"
 )
 
 // Global cache for LRM data
@@ -26,6 +31,10 @@ var (
 	refreshInterval = 10 * time.Minute // Background refresh interval
 	refreshTicker   *time.Ticker
 	stopRefresh     chan bool
+	// Refresh backoff while upstream APIs fail, guarded by lrmCacheMux
+	refreshFailures  int
+	nextRefreshAt    time.Time
+	lastRefreshError string
 	// Configuration
 	MaxConcurrency = 10 // Default concurrent workers for kernel querying
 	// Configuration instance
@@ -39,9 +48,18 @@ var (
 	progressStart      time.Time
 )
 
+// publicationsMemo shares Launchpad publication lookups within the memo TTL
+var publicationsMemo = utils.NewTTLMemo(2 * time.Minute)
+
 // SetProcessorConfig sets the global configuration for the processor
 func SetProcessorConfig(cfg *config.Config) {
 	processorConfig = cfg
+	if cfg != nil {
+		publicationsMemo.SetTTL(cfg.Cache.GetSourceVersionTTL())
+		publicationsMemo.SetMaxBackoff(cfg.Cache.GetMaxBackoff())
+		routingsMemo.SetTTL(cfg.Cache.GetRefreshInterval())
+		routingsMemo.SetMaxBackoff(cfg.Cache.GetMaxBackoff())
+	}
 }
 
 // GetKernelSeriesURL returns the configured kernel series URL
@@ -53,6 +71,15 @@ func GetKernelSeriesURL() string {
 	return "https://kernel.ubuntu.com/forgejo/kernel/kernel-versions/raw/branch/main/info/kernel-series.yaml" // fallback
 }
 
+// GetKernelSeriesURLs returns the kernel series URL followed by its configured mirrors
+func GetKernelSeriesURLs() []string {
+	if processorConfig != nil {
+		effectiveURLs := processorConfig.GetEffectiveURLs()
+		return effectiveURLs.Kernel.SeriesYAMLURLs()
+	}
+	return []string{GetKernelSeriesURL()}
+}
+
 // GetLaunchpadAPIURL returns the configured Launchpad API URL template
 func GetLaunchpadAPIURL() string {
 	if processorConfig != nil {
@@ -108,34 +135,25 @@ func FetchKernelLRMData(routing string) (*LRMVerifierData, error) {
 	log.Printf("Fetching kernel-series.yaml...")
 
 	// Download kernel-series.yaml
-	resp, err := utils.HTTPGetWithRetry(GetKernelSeriesURL())
+	body, err := utils.FetchYAMLFromMirrors("kernel-series.yaml", GetKernelSeriesURLs())
 	if err != nil {
-		return nil, fmt.Errorf("failed to download kernel-series.yaml: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read kernel-series.yaml: %v", err)
-	}
-	if err := utils.ValidateYAMLResponse(resp, body, "kernel-series.yaml"); err != nil {
 		return nil, err
 	}
 
-	// Debug: log the first few lines to see what we got
-	lines := strings.Split(string(body), "\n")
-	log.Printf("Downloaded %d bytes, first few lines:", len(body))
-	for i, line := range lines {
-		if i >= 5 { // Only show first 5 lines
-			break
+	log.Printf("Downloaded %d bytes of kernel-series.yaml", len(body))
+	if debuglog.Enabled(debuglog.ModuleLRM) {
+		for i, line := range strings.SplitN(string(body), "\n", 6) {
+			if i == 5 {
+				break
+			}
+			debuglog.Printf(debuglog.ModuleLRM, "kernel-series.yaml line %d: %s", i+1, line)
 		}
-		log.Printf("Line %d: %s", i+1, line)
 	}
 
 	// Parse YAML
-	var kernelSeries KernelSeries
-	if err := yaml.Unmarshal(body, &kernelSeries); err != nil {
-		return nil, fmt.Errorf("failed to parse kernel-series.yaml: %v", err)
+	kernelSeries, err := parseKernelSeries(body)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Printf("Processing kernel sources...")
@@ -152,12 +170,16 @@ func FetchKernelLRMData(routing string) (*LRMVerifierData, error) {
 			}
 
 			// Find L-R-M packages in this source
-			var lrmPackages []string
+			var lrmPackages, buildPackages []string
 			for pkgName, pkgInfo := range sourceInfo.Packages {
 				if pkgInfo.Type == "lrm" {
 					lrmPackages = append(lrmPackages, pkgName)
 				}
+				if buildTrackedPackageTypes[pkgInfo.Type] {
+					buildPackages = append(buildPackages, pkgName)
+				}
 			}
+			sort.Strings(buildPackages)
 
 			// Determine final supported/development status
 			supported := seriesInfo.Supported
@@ -171,16 +193,18 @@ func FetchKernelLRMData(routing string) (*LRMVerifierData, error) {
 			}
 
 			result := KernelLRMResult{
-				Series:      series,
-				Codename:    seriesInfo.Codename,
-				Source:      source,
-				Routing:     sourceInfo.Routing,
-				LRMPackages: lrmPackages,
-				HasLRM:      len(lrmPackages) > 0,
-				Supported:   supported,
-				Development: development,
-				LTS:         seriesInfo.LTS,
-				ESM:         seriesInfo.ESM,
+				Series:        series,
+				Codename:      seriesInfo.Codename,
+				Source:        source,
+				Routing:       sourceInfo.Routing,
+				LRMPackages:   lrmPackages,
+				BuildPackages: buildPackages,
+				HasLRM:        len(lrmPackages) > 0,
+				Supported:     supported,
+				Development:   development,
+				LTS:           seriesInfo.LTS,
+				ESM:           seriesInfo.ESM,
+				HWETargets:    HWETargets(series, sourceInfo.Variants),
 			}
 
 			allKernels = append(allKernels, result)
@@ -230,24 +254,15 @@ func FetchKernelLRMDataDebug(routing string) (*LRMVerifierData, error) {
 	log.Printf("Fetching kernel-series.yaml...")
 
 	// Download kernel-series.yaml
-	resp, err := utils.HTTPGetWithRetry(GetKernelSeriesURL())
-	if err != nil {
-		return nil, fmt.Errorf("failed to download kernel-series.yaml: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	body, err := utils.FetchYAMLFromMirrors("kernel-series.yaml", GetKernelSeriesURLs())
 	if err != nil {
-		return nil, fmt.Errorf("failed to read kernel-series.yaml: %v", err)
-	}
-	if err := utils.ValidateYAMLResponse(resp, body, "kernel-series.yaml"); err != nil {
 		return nil, err
 	}
 
 	// Parse YAML
-	var kernelSeries KernelSeries
-	if err := yaml.Unmarshal(body, &kernelSeries); err != nil {
-		return nil, fmt.Errorf("failed to parse kernel-series.yaml: %v", err)
+	kernelSeries, err := parseKernelSeries(body)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Printf("Processing kernel sources...")
@@ -264,12 +279,16 @@ func FetchKernelLRMDataDebug(routing string) (*LRMVerifierData, error) {
 			}
 
 			// Find L-R-M packages in this source
-			var lrmPackages []string
+			var lrmPackages, buildPackages []string
 			for pkgName, pkgInfo := range sourceInfo.Packages {
 				if pkgInfo.Type == "lrm" {
 					lrmPackages = append(lrmPackages, pkgName)
 				}
+				if buildTrackedPackageTypes[pkgInfo.Type] {
+					buildPackages = append(buildPackages, pkgName)
+				}
 			}
+			sort.Strings(buildPackages)
 
 			// Determine final supported/development status
 			supported := seriesInfo.Supported
@@ -283,16 +302,18 @@ func FetchKernelLRMDataDebug(routing string) (*LRMVerifierData, error) {
 			}
 
 			result := KernelLRMResult{
-				Series:      series,
-				Codename:    seriesInfo.Codename,
-				Source:      source,
-				Routing:     sourceInfo.Routing,
-				LRMPackages: lrmPackages,
-				HasLRM:      len(lrmPackages) > 0,
-				Supported:   supported,
-				Development: development,
-				LTS:         seriesInfo.LTS,
-				ESM:         seriesInfo.ESM,
+				Series:        series,
+				Codename:      seriesInfo.Codename,
+				Source:        source,
+				Routing:       sourceInfo.Routing,
+				LRMPackages:   lrmPackages,
+				BuildPackages: buildPackages,
+				HasLRM:        len(lrmPackages) > 0,
+				Supported:     supported,
+				Development:   development,
+				LTS:           seriesInfo.LTS,
+				ESM:           seriesInfo.ESM,
+				HWETargets:    HWETargets(series, sourceInfo.Variants),
 			}
 
 			allKernels = append(allKernels, result)
@@ -372,6 +393,17 @@ func fetchLatestVersions(kernels []KernelLRMResult) ([]KernelLRMResult, error) {
 				mu.Unlock()
 			}
 
+			// Query Launchpad build status of the L-R-M and signature packages
+			var buildStatuses []BuildStatus
+			for _, pkg := range kernel.BuildPackages {
+				if status := queryBuildStatus(pkg, kernel.Codename, dateThreshold); status != nil {
+					buildStatuses = append(buildStatuses, *status)
+				}
+			}
+			mu.Lock()
+			kernel.BuildStatuses = buildStatuses
+			mu.Unlock()
+
 			// Query source package version
 			sourceVersion := queryPackageVersion(kernel.Source, kernel.Codename, dateThreshold)
 			mu.Lock()
@@ -380,9 +412,10 @@ func fetchLatestVersions(kernels []KernelLRMResult) ([]KernelLRMResult, error) {
 
 			// Get NVIDIA driver versions for this kernel from DSC files
 			if kernel.LatestLRMVersion != "N/A" && kernel.LatestLRMVersion != "ERROR" && len(kernel.LRMPackages) > 0 {
-				driverVersions := generateNvidiaDriverVersions(kernel.LRMPackages[0], kernel.LatestLRMVersion, kernel.Codename)
+				driverVersions, dsc := generateNvidiaDriverVersions(kernel.LRMPackages[0], kernel.LatestLRMVersion, kernel.Codename)
 				mu.Lock()
 				kernel.NvidiaDriverVersions = driverVersions
+				kernel.DSC = dsc
 				mu.Unlock()
 			}
 
@@ -441,20 +474,20 @@ func fetchLatestVersions(kernels []KernelLRMResult) ([]KernelLRMResult, error) {
 			if cfg == nil {
 				cfg = config.DefaultConfig()
 			}
-			sourceVersions, err := packages.GetMaxSourceVersionsArchive(cfg, packageName)
+			sourceVersions, err := packages.GetMaxSourceVersionsCached(cfg, packageName)
 			if err != nil {
 				log.Printf("Warning: Failed to get source versions for %s: %v", packageName, err)
 				return
 			}
 
-			// Extract Updates/Security versions for each series (same logic as main dashboard)
-			seriesList := []string{"resolute", "noble", "jammy", "focal", "bionic"}
+			// Extract published versions for each series (same logic as main dashboard)
+			publishedPockets := packages.PublishedPockets()
 			packageVersions := make(map[string]string)
 
-			for _, series := range seriesList {
-				if pocket, exists := sourceVersions.VersionMap[series]; exists {
-					if pocket.UpdatesSecurity.String() != "" {
-						packageVersions[series] = pocket.UpdatesSecurity.String()
+			for _, series := range packages.SeriesOrder() {
+				if pocket, exists := sourceVersions.VersionMap[series]; exists && pocket != nil {
+					if best, ok := pocket.LatestPublished(publishedPockets); ok {
+						packageVersions[series] = best.String()
 					}
 				}
 			}
@@ -500,27 +533,45 @@ func fetchLatestVersions(kernels []KernelLRMResult) ([]KernelLRMResult, error) {
 	return kernels, nil
 }
 
-// queryPackageVersion queries Launchpad API for the latest version of a package
-func queryPackageVersion(packageName, codename, dateThreshold string) string {
+// fetchPublications queries Launchpad for the publications of a package.
+// The result covers every series, so lookups are memoized per URL and shared
+// between kernels of different series and with other subsystems.
+func fetchPublications(packageName, dateThreshold string) (*LaunchpadResponse, error) {
 	url := fmt.Sprintf(GetLaunchpadAPIURL(), dateThreshold, packageName)
 
-	log.Printf("Querying %s in %s...", packageName, codename)
+	value, staleSince, err := publicationsMemo.GetStale(url, func() (interface{}, error) {
+		resp, err := utils.LaunchpadGetWithRetry(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
 
-	resp, err := utils.HTTPGetWithRetry(url)
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("HTTP error: %d", resp.StatusCode)
+		}
+
+		var apiResp LaunchpadResponse
+		if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+			return nil, fmt.Errorf("JSON decode error: %v", err)
+		}
+		return &apiResp, nil
+	})
 	if err != nil {
-		log.Printf("Error querying %s: %v", packageName, err)
-		return "ERROR"
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		log.Printf("HTTP error for %s: %d", packageName, resp.StatusCode)
-		return "ERROR"
+	if !staleSince.IsZero() {
+		log.Printf("Warning: Serving publications of %s from %s while Launchpad is unavailable", packageName, staleSince.Format(time.RFC3339))
 	}
+	return value.(*LaunchpadResponse), nil
+}
+
+// queryPackageVersion queries Launchpad API for the latest version of a package
+func queryPackageVersion(packageName, codename, dateThreshold string) string {
+	log.Printf("Querying %s in %s...", packageName, codename)
 
-	var apiResp LaunchpadResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		log.Printf("JSON decode error for %s: %v", packageName, err)
+	apiResp, err := fetchPublications(packageName, dateThreshold)
+	if err != nil {
+		log.Printf("Error querying %s: %v", packageName, err)
 		return "ERROR"
 	}
 
@@ -578,10 +629,11 @@ func extractSeriesFromLink(link string) string {
 	return ""
 }
 
-// generateNvidiaDriverVersions finds NVIDIA driver versions from DSC files
-func generateNvidiaDriverVersions(lrmPackage, version, codename string) []string {
+// generateNvidiaDriverVersions finds NVIDIA driver versions from DSC files, along with the
+// provenance of the DSC file they were read from
+func generateNvidiaDriverVersions(lrmPackage, version, codename string) ([]string, *DSCProvenance) {
 	if version == "N/A" || version == "ERROR" || lrmPackage == "" {
-		return []string{}
+		return []string{}, nil
 	}
 
 	log.Printf("Fetching NVIDIA driver versions for %s in %s from DSC file", lrmPackage, codename)
@@ -590,18 +642,19 @@ func generateNvidiaDriverVersions(lrmPackage, version, codename string) []string
 	dscURL, err := findDSCURL(lrmPackage, codename, version)
 	if err != nil {
 		log.Printf("Failed to find DSC URL for %s: %v", lrmPackage, err)
-		return []string{}
+		return []string{}, nil
 	}
 
 	// Create DSC cache directory if it doesn't exist
 	err = os.MkdirAll(DSCCacheDir, 0755)
 	if err != nil {
 		log.Printf("Failed to create DSC cache directory: %v", err)
-		return []string{}
+		return []string{}, nil
 	}
 
-	// Generate filename for the DSC file
-	filename := fmt.Sprintf("%s-%s.dsc", codename, lrmPackage)
+	// The cached file is named after the .dsc, which carries its version, so a new upload is
+	// downloaded instead of the DSC of an older version being read again
+	filename := fmt.Sprintf("%s-%s", codename, path.Base(dscURL))
 	filePath := fmt.Sprintf("%s/%s", DSCCacheDir, filename)
 
 	// Download DSC file if it doesn't exist
@@ -609,19 +662,23 @@ func generateNvidiaDriverVersions(lrmPackage, version, codename string) []string
 		err = downloadDSCFile(dscURL, filename)
 		if err != nil {
 			log.Printf("Failed to download DSC file for %s: %v", lrmPackage, err)
-			return []string{}
+			return []string{}, nil
 		}
+	} else {
+		// Mark the cached file as used, so the cache janitor evicts the least recently used DSCs
+		now := time.Now()
+		os.Chtimes(filePath, now, now)
 	}
 
 	// Parse DSC file to extract NVIDIA driver versions
-	driverVersions, err := parseDSCFile(filePath)
+	driverVersions, dsc, err := parseDSCFile(filePath, dscURL)
 	if err != nil {
 		log.Printf("Failed to parse DSC file %s: %v", filePath, err)
-		return []string{}
+		return []string{}, nil
 	}
 
-	log.Printf("Found %d NVIDIA drivers for %s in %s: %v", len(driverVersions), lrmPackage, codename, driverVersions)
-	return driverVersions
+	log.Printf("Found %d NVIDIA drivers for %s in %s: %v (from %s, sha256 %s)", len(driverVersions), lrmPackage, codename, driverVersions, dscURL, dsc.SHA256)
+	return driverVersions, dsc
 }
 
 // extractDriverBranch extracts the driver branch from a package name
@@ -806,7 +863,7 @@ func findDSCURL(packageName, codename, version string) (string, error) {
 
 	log.Printf("Querying Launchpad API for %s: %s", packageName, url)
 
-	resp, err := utils.HTTPGetWithRetry(url)
+	resp, err := utils.LaunchpadGetWithRetry(url)
 	if err != nil {
 		return "", fmt.Errorf("failed to query Launchpad API: %v", err)
 	}
@@ -852,7 +909,7 @@ func fetchSourceFileUrls(selfLink string) ([]string, error) {
 	sourceFileUrlsURL := selfLink + "?ws.op=sourceFileUrls"
 
 	// Make the HTTP request
-	resp, err := utils.HTTPGetWithRetry(sourceFileUrlsURL)
+	resp, err := utils.LaunchpadGetWithRetry(sourceFileUrlsURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch source file URLs: %v", err)
 	}
@@ -905,14 +962,32 @@ func downloadDSCFile(url, filename string) error {
 	return nil
 }
 
-// parseDSCFile reads a DSC file and extracts NVIDIA driver dependencies
-func parseDSCFile(filePath string) ([]string, error) {
+// parseDSCFile reads a DSC file downloaded from dscURL and extracts NVIDIA driver dependencies
+func parseDSCFile(filePath, dscURL string) ([]string, *DSCProvenance, error) {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read DSC file %s: %v", filePath, err)
+		return nil, nil, fmt.Errorf("failed to read DSC file %s: %v", filePath, err)
+	}
+	dsc := &DSCProvenance{URL: dscURL, Version: dscVersion(dscURL), SHA256: fmt.Sprintf("%x", sha256.Sum256(content))}
+	if info, err := os.Stat(filePath); err == nil {
+		dsc.DownloadedAt = info.ModTime().UTC()
 	}
 
-	return parseNvidiaDriverDependencies(string(content)), nil
+	return parseNvidiaDriverDependencies(string(content)), dsc, nil
+}
+
+// dscVersion returns the source version in the file name of a .dsc URL, e.g. "6.8.0-45.45" for
+// ".../linux-restricted-modules_6.8.0-45.45.dsc"
+func dscVersion(dscURL string) string {
+	name := path.Base(dscURL)
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	_, version, ok := strings.Cut(strings.TrimSuffix(name, ".dsc"), "_")
+	if !ok {
+		return ""
+	}
+	return version
 }
 
 // parseNvidiaDriverDependencies extracts NVIDIA driver versions from DSC content
@@ -936,6 +1011,10 @@ func parseNvidiaDriverDependencies(content string) []string {
 			break
 		}
 
+		if inDependenciesSection && !strings.Contains(trimmedLine, "nvidia-graphics-drivers-") {
+			debuglog.Printf(debuglog.ModuleLRM, "DSC: skipping dependency line %q", trimmedLine)
+		}
+
 		// Parse driver dependency lines
 		if inDependenciesSection && strings.Contains(trimmedLine, "nvidia-graphics-drivers-") {
 			// Remove leading/trailing whitespace and comma
@@ -949,8 +1028,11 @@ func parseNvidiaDriverDependencies(content string) []string {
 				if endIdx := strings.Index(versionPart, ")"); endIdx > 0 {
 					version := versionPart[:endIdx]
 					driverVersions = append(driverVersions, fmt.Sprintf("%s=%s", driverName, version))
+					debuglog.Printf(debuglog.ModuleLRM, "DSC: %q -> %s=%s", trimmedLine, driverName, version)
+					continue
 				}
 			}
+			debuglog.Printf(debuglog.ModuleLRM, "DSC: no pinned version in %q", trimmedLine)
 		}
 	}
 
@@ -1061,29 +1143,46 @@ func generateNvidiaDriverStatuses(nvidiaDrivers []string, dkmsVersions map[strin
 	return statuses
 }
 
-// GetAvailableRoutings fetches all available routing values from kernel-series.yaml
-func GetAvailableRoutings() ([]string, error) {
-	log.Printf("Fetching available routings from kernel-series.yaml...")
-
-	// Download kernel-series.yaml
-	resp, err := utils.HTTPGetWithRetry(GetKernelSeriesURL())
+// fetchKernelSeries downloads and parses kernel-series.yaml
+func fetchKernelSeries() (KernelSeries, error) {
+	body, err := utils.FetchYAMLFromMirrors("kernel-series.yaml", GetKernelSeriesURLs())
 	if err != nil {
-		return nil, fmt.Errorf("failed to download kernel-series.yaml: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return parseKernelSeries(body)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// DevelopmentCodename returns the codename of the series marked development in kernel-series.yaml
+func DevelopmentCodename() (string, error) {
+	kernelSeries, err := fetchKernelSeries()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+		return "", err
 	}
-	if err := utils.ValidateYAMLResponse(resp, body, "kernel-series.yaml"); err != nil {
-		return nil, err
+	return kernelSeries.DevelopmentCodename()
+}
+
+// DevelopmentCodename returns the codename of the development series, preferring the first in
+// series order when several are marked
+func (ks KernelSeries) DevelopmentCodename() (string, error) {
+	var candidates []string
+	for _, info := range ks {
+		if info.Development && info.Codename != "" {
+			candidates = append(candidates, info.Codename)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no development series in kernel-series.yaml")
 	}
+	return packages.SortSeries(candidates)[0], nil
+}
 
-	// Parse YAML
-	var kernelSeries KernelSeries
-	if err := yaml.Unmarshal(body, &kernelSeries); err != nil {
-		return nil, fmt.Errorf("failed to parse kernel-series.yaml: %v", err)
+// GetAvailableRoutings fetches all available routing values from kernel-series.yaml
+func GetAvailableRoutings() ([]string, error) {
+	log.Printf("Fetching available routings from kernel-series.yaml...")
+
+	kernelSeries, err := fetchKernelSeries()
+	if err != nil {
+		return nil, err
 	}
 
 	// Collect all unique routing values
@@ -1125,37 +1224,87 @@ func InitializeLRMCache() error {
 	return nil
 }
 
-// GetCachedLRMData returns cached LRM data or fetches fresh data if cache is expired
+// GetCachedLRMData returns cached LRM data or fetches fresh data if cache is expired.
+// While refreshes fail, the previous data is returned marked stale.
 func GetCachedLRMData() (*LRMVerifierData, error) {
 	lrmCacheMux.RLock()
 	if lrmCache != nil && time.Since(lrmCache.LastUpdated) < cacheExpiry {
 		defer lrmCacheMux.RUnlock()
 		return lrmCache, nil
 	}
+	if lrmCache != nil && time.Now().Before(nextRefreshAt) {
+		defer lrmCacheMux.RUnlock()
+		return staleCacheLocked(), nil
+	}
 	lrmCacheMux.RUnlock()
 
 	// Cache is expired or doesn't exist, refresh it
-	return refreshLRMCache()
+	data, err := refreshLRMCache()
+	if err != nil {
+		lrmCacheMux.RLock()
+		defer lrmCacheMux.RUnlock()
+		if lrmCache != nil {
+			return staleCacheLocked(), nil
+		}
+		return nil, err
+	}
+	return data, nil
 }
 
-// refreshLRMCache refreshes the LRM cache
+// staleCacheLocked returns a copy of the cache marked stale; callers hold lrmCacheMux
+func staleCacheLocked() *LRMVerifierData {
+	stale := *lrmCache
+	stale.Stale = true
+	return &stale
+}
+
+// refreshLRMCache refreshes the LRM cache, backing off further attempts while it fails
 func refreshLRMCache() (*LRMVerifierData, error) {
 	log.Printf("Refreshing LRM cache...")
 	data, err := fetchLRMDataInternal()
 	if err != nil {
+		lrmCacheMux.Lock()
+		refreshFailures++
+		nextRefreshAt = time.Now().Add(utils.Backoff(refreshFailures, refreshInterval, getMaxBackoff()))
+		lastRefreshError = err.Error()
+		lrmCacheMux.Unlock()
 		return nil, fmt.Errorf("failed to refresh LRM cache: %v", err)
 	}
 
 	lrmCacheMux.Lock()
 	lrmCache = data
+	if refreshFailures > 0 {
+		log.Printf("LRM refresh recovered after %d failed attempts", refreshFailures)
+	}
+	refreshFailures = 0
+	nextRefreshAt = time.Time{}
+	lastRefreshError = ""
 	lrmCacheMux.Unlock()
 
 	log.Printf("LRM cache refreshed successfully with %d kernel results", len(data.KernelResults))
 	return data, nil
 }
 
+// getMaxBackoff returns the longest wait between failing refresh attempts
+func getMaxBackoff() time.Duration {
+	if processorConfig != nil {
+		return processorConfig.Cache.GetMaxBackoff()
+	}
+	return time.Hour
+}
+
+// refreshBackedOff reports whether background refreshes are paused after failures
+func refreshBackedOff(now time.Time) bool {
+	lrmCacheMux.RLock()
+	defer lrmCacheMux.RUnlock()
+	return now.Before(nextRefreshAt)
+}
+
 // fetchLRMDataInternal is the internal function that actually fetches the data
 func fetchLRMDataInternal() (*LRMVerifierData, error) {
+	if err := faults.Check(faults.StageLRM); err != nil {
+		return nil, err
+	}
 	return FetchKernelLRMDataDebug("") // Use debug function to get ALL kernels, not just supported with LRM
 }
 
@@ -1170,10 +1319,38 @@ func StartBackgroundRefresh() {
 	refreshTicker = time.NewTicker(refreshInterval)
 	stopRefresh = make(chan bool)
 
-	go func() {
+	supervise.Loop("lrm-refresh", backgroundRefreshLoop(refreshTicker, stopRefresh))
+}
+
+// budgetDefersRefresh reports whether the upstream request budgets skip the given tick: all
+// ticks are skipped while a budget is used up, and all but every slowdown-th while nearing one
+func budgetDefersRefresh(now time.Time, tick int) bool {
+	tracker := budget.Current()
+	if tracker == nil {
+		return false
+	}
+	if tracker.Exhausted(now) {
+		return true
+	}
+	return tick%tracker.Slowdown(now) != 0
+}
+
+// backgroundRefreshLoop returns the LRM refresh loop bound to the given ticker and stop channel
+func backgroundRefreshLoop(ticker *time.Ticker, stop chan bool) func() {
+	return func() {
+		tick := 0
 		for {
 			select {
-			case <-refreshTicker.C:
+			case <-ticker.C:
+				tick++
+				if refreshBackedOff(time.Now()) {
+					log.Printf("Background refresh: skipped while backing off after failures")
+					continue
+				}
+				if budgetDefersRefresh(time.Now(), tick) {
+					log.Printf("Background refresh: skipped to stay within the upstream request budget")
+					continue
+				}
 				log.Printf("Background refresh: updating LRM cache...")
 				start := time.Now()
 
@@ -1185,12 +1362,12 @@ func StartBackgroundRefresh() {
 					log.Printf("Background refresh completed successfully in %v", duration)
 				}
 
-			case <-stopRefresh:
+			case <-stop:
 				log.Printf("Background LRM cache refresh stopped")
 				return
 			}
 		}
-	}()
+	}
 }
 
 // StopBackgroundRefresh stops the background cache refresh goroutine
@@ -1203,6 +1380,22 @@ func StopBackgroundRefresh() {
 	}
 }
 
+// GetRefreshInterval returns the background LRM refresh interval
+func GetRefreshInterval() time.Duration {
+	return refreshInterval
+}
+
+// GetCacheAge returns how old the LRM cache is and whether it has been loaded
+func GetCacheAge() (time.Duration, bool) {
+	lrmCacheMux.RLock()
+	defer lrmCacheMux.RUnlock()
+
+	if lrmCache == nil {
+		return 0, false
+	}
+	return time.Since(lrmCache.LastUpdated), true
+}
+
 // GetCacheStatus returns information about the current cache status
 func GetCacheStatus() map[string]interface{} {
 	lrmCacheMux.RLock()
@@ -1215,6 +1408,11 @@ func GetCacheStatus() map[string]interface{} {
 		"kernel_count":              0,
 		"background_refresh_active": refreshTicker != nil,
 		"refresh_interval_minutes":  int(refreshInterval.Minutes()),
+		"refresh_failures":          refreshFailures,
+	}
+	if refreshFailures > 0 {
+		status["next_refresh_attempt"] = nextRefreshAt.Format("2006-01-02 15:04:05 UTC")
+		status["last_refresh_error"] = lastRefreshError
 	}
 
 	if lrmCache != nil {
@@ -0,0 +1,179 @@
+package lrm
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RoutingDestination is a single archive/pocket target of a routing step
+type RoutingDestination struct {
+	Archive string `json:"archive"`
+	Pocket  string `json:"pocket"`
+}
+
+// RoutingDefinition describes where a named routing builds and publishes packages
+type RoutingDefinition struct {
+	Name         string                          `json:"name"`
+	Destinations map[string][]RoutingDestination `json:"destinations"`
+	Explanation  string                          `json:"explanation"`
+}
+
+// routingStepOrder lists routing steps in the order packages move through them
+var routingStepOrder = []string{
+	"build", "build-private", "security-build", "signing",
+	"proposed", "as-proposed", "updates", "security", "release",
+}
+
+// routingDocument captures the routing-table section of kernel-series.yaml.
+// Depending on the file revision it lives either at the top level or under defaults.
+type routingDocument struct {
+	RoutingTable map[string]map[string]interface{} `yaml:"routing-table"`
+	Defaults     struct {
+		RoutingTable map[string]map[string]interface{} `yaml:"routing-table"`
+	} `yaml:"defaults"`
+}
+
+// routingsMemo caches the parsed routing table for a cache refresh interval, so requests do not
+// each download kernel-series.yaml
+var routingsMemo = utils.NewTTLMemo(15 * time.Minute)
+
+// GetRoutingDefinitions returns the routing table of kernel-series.yaml. It is cached, and the
+// last parsed table is kept while the mirrors are failing.
+func GetRoutingDefinitions() (map[string]RoutingDefinition, error) {
+	urls := GetKernelSeriesURLs()
+	value, _, err := routingsMemo.GetStale(strings.Join(urls, " "), func() (interface{}, error) {
+		return fetchRoutingDefinitions(urls)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]RoutingDefinition), nil
+}
+
+// fetchRoutingDefinitions downloads kernel-series.yaml and parses its routing table
+func fetchRoutingDefinitions(urls []string) (map[string]RoutingDefinition, error) {
+	log.Printf("Fetching routing definitions from kernel-series.yaml...")
+
+	body, err := utils.FetchYAMLFromMirrors("kernel-series.yaml", urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRoutingDefinitions(body)
+}
+
+// ParseRoutingDefinitions extracts the routing table from kernel-series.yaml content
+func ParseRoutingDefinitions(body []byte) (map[string]RoutingDefinition, error) {
+	var doc routingDocument
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse kernel-series.yaml: %v", err)
+	}
+
+	table := doc.RoutingTable
+	if len(table) == 0 {
+		table = doc.Defaults.RoutingTable
+	}
+
+	definitions := make(map[string]RoutingDefinition, len(table))
+	for name, steps := range table {
+		def := RoutingDefinition{
+			Name:         name,
+			Destinations: make(map[string][]RoutingDestination),
+		}
+		for step, raw := range steps {
+			if dests := parseRoutingDestinations(raw); len(dests) > 0 {
+				def.Destinations[step] = dests
+			}
+		}
+		def.Explanation = explainRouting(def)
+		definitions[name] = def
+	}
+
+	log.Printf("Parsed %d routing definitions", len(definitions))
+	return definitions, nil
+}
+
+// parseRoutingDestinations accepts either a single [archive, pocket] pair or a list of pairs
+func parseRoutingDestinations(raw interface{}) []RoutingDestination {
+	items, ok := raw.([]interface{})
+	if !ok {
+		if s, ok := raw.(string); ok && s != "" {
+			return []RoutingDestination{{Archive: s}}
+		}
+		return nil
+	}
+
+	// A flat list of scalars is a single [archive, pocket] pair
+	if len(items) > 0 {
+		if _, isScalar := items[0].(string); isScalar {
+			return []RoutingDestination{destinationFromPair(items)}
+		}
+	}
+
+	var dests []RoutingDestination
+	for _, item := range items {
+		if pair, ok := item.([]interface{}); ok && len(pair) > 0 {
+			dests = append(dests, destinationFromPair(pair))
+		}
+	}
+	return dests
+}
+
+func destinationFromPair(pair []interface{}) RoutingDestination {
+	var dest RoutingDestination
+	if len(pair) > 0 {
+		dest.Archive = fmt.Sprint(pair[0])
+	}
+	if len(pair) > 1 {
+		dest.Pocket = fmt.Sprint(pair[1])
+	}
+	return dest
+}
+
+// explainRouting builds a human-readable summary of where a routing publishes
+func explainRouting(def RoutingDefinition) string {
+	var parts []string
+	for _, step := range orderedRoutingSteps(def.Destinations) {
+		var targets []string
+		for _, dest := range def.Destinations[step] {
+			if dest.Pocket != "" {
+				targets = append(targets, fmt.Sprintf("%s (%s)", dest.Archive, dest.Pocket))
+			} else {
+				targets = append(targets, dest.Archive)
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s → %s", step, strings.Join(targets, ", ")))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("Routing %s has no destinations defined", def.Name)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// orderedRoutingSteps returns known steps in pipeline order followed by unknown ones alphabetically
+func orderedRoutingSteps(destinations map[string][]RoutingDestination) []string {
+	known := make(map[string]bool, len(routingStepOrder))
+	var steps []string
+	for _, step := range routingStepOrder {
+		known[step] = true
+		if _, ok := destinations[step]; ok {
+			steps = append(steps, step)
+		}
+	}
+
+	var extra []string
+	for step := range destinations {
+		if !known[step] {
+			extra = append(extra, step)
+		}
+	}
+	sort.Strings(extra)
+	return append(steps, extra...)
+}
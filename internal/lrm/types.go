@@ -12,6 +12,7 @@ type KernelLRMResult struct {
 	Source               string
 	Routing              string
 	LRMPackages          []string
+	BuildPackages        []string // L-R-M and signature packages whose builds are tracked
 	HasLRM               bool
 	Supported            bool
 	Development          bool
@@ -24,6 +25,18 @@ type KernelLRMResult struct {
 	DKMSVersions         map[string]string // DKMS package versions for this kernel's series
 	UpdateStatus         string
 	NvidiaDriverStatuses []NvidiaDriverStatus // Individual driver statuses with detailed info
+	BuildStatuses        []BuildStatus        // Launchpad build status of the latest publications
+	HWETargets           []string             // LTS series (e.g. "24.04") the kernel is rolled out to as HWE
+	DSC                  *DSCProvenance       `json:",omitempty"` // Source of NvidiaDriverVersions
+}
+
+// DSCProvenance identifies the L-R-M source package (.dsc) the NVIDIA driver dependencies of a
+// kernel were read from, so they can be checked against the archive
+type DSCProvenance struct {
+	URL          string    // e.g. "https://launchpad.net/ubuntu/+archive/primary/+sourcefiles/linux-restricted-modules/6.8.0-45.45/linux-restricted-modules_6.8.0-45.45.dsc"
+	Version      string    // Source version from the file name, e.g. "6.8.0-45.45"
+	DownloadedAt time.Time // When the .dsc was downloaded into the DSC cache
+	SHA256       string    // Checksum of the .dsc as read
 }
 
 // LRMVerifierData holds all the cached L-R-M data
@@ -33,6 +46,7 @@ type LRMVerifierData struct {
 	IsInitialized bool
 	TotalKernels  int
 	SupportedLRM  int
+	Stale         bool // Set when refreshing failed and this older data is served instead
 }
 
 // SeriesInfo represents information about a kernel series from kernel-series.yaml
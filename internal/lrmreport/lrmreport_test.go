@@ -0,0 +1,93 @@
+package lrmreport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/sru"
+)
+
+func TestDueCycle(t *testing.T) {
+	cycle := func(name, release string, complete bool) sru.SRUCycle {
+		date, _ := time.Parse("2006-01-02", release)
+		return sru.SRUCycle{Name: name, ReleaseDate: release, ParsedDate: date, Complete: complete}
+	}
+	cycles := []sru.SRUCycle{
+		cycle("s2026.11.02", "2026-11-23", false),
+		cycle("s2026.10.05", "2026-10-26", false),
+		cycle("s2026.09.07", "2026-09-28", true),
+	}
+
+	tests := []struct {
+		now      string
+		expected string
+	}{
+		{"2026-10-23T12:00:00Z", ""},            // Three days ahead
+		{"2026-10-24T00:30:00Z", "s2026.10.05"}, // Two days ahead
+		{"2026-10-26T23:00:00Z", "s2026.10.05"}, // Release day
+		{"2026-10-27T08:00:00Z", ""},            // Released
+		{"2026-09-27T08:00:00Z", ""},            // Complete
+	}
+	for _, tt := range tests {
+		now, _ := time.Parse(time.RFC3339, tt.now)
+		name := ""
+		if due := DueCycle(cycles, 2, now); due != nil {
+			name = due.Name
+		}
+		if name != tt.expected {
+			t.Errorf("DueCycle at %s = %q, expected %q", tt.now, name, tt.expected)
+		}
+	}
+}
+
+func TestStoreRemembersSentCycles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lrm_reports.json")
+	NewStore(path).Record(SentReport{Cycle: "s2026.10.05", SentAt: time.Date(2026, 10, 24, 9, 0, 0, 0, time.UTC), Problems: 3})
+
+	reloaded := NewStore(path)
+	if !reloaded.Sent("s2026.10.05") || reloaded.Sent("s2026.11.02") {
+		t.Errorf("sent cycles after reload = %+v, expected s2026.10.05 only", reloaded.List())
+	}
+}
+
+func TestDeliverPostsToWebhooks(t *testing.T) {
+	var received Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("webhook body: %v", err)
+		}
+	}))
+	defer server.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	report := Report{Cycle: "s2026.10.05", Subject: "L-R-M verification", Problems: []Problem{{Kernel: "linux-aws", Series: "24.04"}}, HTML: "<p>linux-aws</p>"}
+	err := Deliver(&config.LRMReportConfig{WebhookURLs: []string{server.URL, failing.URL}}, report)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 502") {
+		t.Errorf("Deliver error = %v, expected the failing webhook's HTTP 502", err)
+	}
+	if received.Cycle != "s2026.10.05" || received.HTML != report.HTML || len(received.Problems) != 1 {
+		t.Errorf("webhook received %+v", received)
+	}
+}
+
+func TestEmailMessage(t *testing.T) {
+	cfg := &config.LRMReportConfig{From: "monitor@example.com", To: []string{"kernel@example.com", "drivers@example.com"}}
+	msg := string(emailMessage(cfg, Report{Subject: "L-R-M verification", GeneratedAt: time.Now(), HTML: "<p>one</p>\n<p>two</p>"}))
+	for _, header := range []string{"To: kernel@example.com, drivers@example.com\r\n", "Content-Type: text/html; charset=utf-8\r\n", "Subject: L-R-M verification\r\n"} {
+		if !strings.Contains(msg, header) {
+			t.Errorf("message lacks %q:\n%s", header, msg)
+		}
+	}
+	if !strings.HasSuffix(msg, "\r\n\r\n<p>one</p>\r\n<p>two</p>") {
+		t.Errorf("body is not separated from the headers with CRLF line endings:\n%q", msg)
+	}
+}
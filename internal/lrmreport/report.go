@@ -0,0 +1,122 @@
+// Package lrmreport sends the report of the L-R-M verification problems to the kernel and
+// drivers teams once per SRU cycle, a configurable number of days before its release, by email
+// and/or webhook, and remembers which cycles were reported.
+package lrmreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/sru"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Problem is a kernel whose L-R-M verification found a problem
+type Problem struct {
+	Kernel      string   `json:"kernel"` // Source package, e.g. "linux-restricted-modules-aws"
+	Series      string   `json:"series"`
+	Version     string   `json:"version"`                // Latest L-R-M version, "ERROR" when it could not be read
+	Drivers     []string `json:"drivers,omitempty"`      // Driver status lines, e.g. "nvidia-graphics-drivers-570: Outdated"
+	BuildStatus string   `json:"build_status,omitempty"` // Set when the latest build did not succeed
+}
+
+// Report is the L-R-M report of an SRU cycle
+type Report struct {
+	Cycle       string    `json:"cycle"`
+	ReleaseDate string    `json:"release_date"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Subject     string    `json:"subject"`
+	Problems    []Problem `json:"problems"`
+	HTML        string    `json:"html"` // The rendered report, as emailed
+}
+
+// DueCycle returns the cycle whose report is due at now: the earliest cycle not yet complete
+// whose release is at most daysBefore days away and not past. Nil when none is.
+func DueCycle(cycles []sru.SRUCycle, daysBefore int, now time.Time) *sru.SRUCycle {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var due *sru.SRUCycle
+	for i := range cycles {
+		cycle := &cycles[i]
+		if cycle.Complete || cycle.ParsedDate.IsZero() {
+			continue
+		}
+		release := time.Date(cycle.ParsedDate.Year(), cycle.ParsedDate.Month(), cycle.ParsedDate.Day(), 0, 0, 0, 0, time.UTC)
+		if today.Before(release.AddDate(0, 0, -daysBefore)) || today.After(release) {
+			continue
+		}
+		if due == nil || cycle.ParsedDate.Before(due.ParsedDate) {
+			due = cycle
+		}
+	}
+	return due
+}
+
+// Deliver sends a report to every configured webhook and email recipient, returning the
+// errors of the deliveries that failed
+func Deliver(cfg *config.LRMReportConfig, report Report) error {
+	var errs []error
+	for _, url := range cfg.WebhookURLs {
+		if err := postWebhook(url, report); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", url, err))
+		}
+	}
+	if cfg.EmailEnabled() {
+		if err := sendEmail(cfg, report); err != nil {
+			errs = append(errs, fmt.Errorf("email via %s: %w", cfg.SMTPServer, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// postWebhook posts the report as JSON
+func postWebhook(url string, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	client := utils.NewHTTPClient()
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sendEmail emails the HTML report, authenticating when a username is configured
+func sendEmail(cfg *config.LRMReportConfig, report Report) error {
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		host, _, err := net.SplitHostPort(cfg.SMTPServer)
+		if err != nil {
+			return fmt.Errorf("invalid smtp_server: %w", err)
+		}
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.GetSMTPPassword(), host)
+	}
+	return smtp.SendMail(cfg.SMTPServer, auth, cfg.From, cfg.To, emailMessage(cfg, report))
+}
+
+// emailMessage builds the MIME message of a report
+func emailMessage(cfg *config.LRMReportConfig, report Report) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", report.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", report.GeneratedAt.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(report.HTML, "\n", "\r\n"))
+	return msg.Bytes()
+}
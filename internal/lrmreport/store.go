@@ -0,0 +1,107 @@
+package lrmreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/atomicfile"
+)
+
+// SentReport records the report sent for an SRU cycle
+type SentReport struct {
+	Cycle    string    `json:"cycle"` // e.g. "s2026.10.05"
+	SentAt   time.Time `json:"sent_at"`
+	Problems int       `json:"problems"` // Kernels with problems in the report
+}
+
+// SchemaVersion is the version of the format of the sent reports file, bumped when it changes
+const SchemaVersion = 1
+
+// Store remembers the cycles already reported and persists them to disk, so a restart does not
+// send a report twice
+type Store struct {
+	mu          sync.RWMutex
+	sent        map[string]SentReport // Keyed by cycle name
+	persistFile string
+}
+
+// NewStore creates a store, loading previously persisted reports if available
+func NewStore(persistFile string) *Store {
+	s := &Store{
+		sent:        make(map[string]SentReport),
+		persistFile: persistFile,
+	}
+	if err := s.loadFromFile(); err != nil {
+		log.Printf("Warning: Could not load existing L-R-M reports: %v", err)
+	}
+	return s
+}
+
+// Sent reports whether the report of a cycle was sent
+func (s *Store) Sent(cycle string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.sent[cycle]
+	return ok
+}
+
+// Record remembers the report of a cycle as sent
+func (s *Store) Record(report SentReport) {
+	s.mu.Lock()
+	s.sent[report.Cycle] = report
+	s.mu.Unlock()
+	if err := s.saveToFile(); err != nil {
+		log.Printf("Warning: Failed to persist L-R-M reports: %v", err)
+	}
+}
+
+// List returns the sent reports, oldest first
+func (s *Store) List() []SentReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]SentReport, 0, len(s.sent))
+	for _, report := range s.sent {
+		list = append(list, report)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].SentAt.Before(list[j].SentAt) })
+	return list
+}
+
+// saveToFile writes all sent reports to the persistence file
+func (s *Store) saveToFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	return atomicfile.WriteJSON(s.persistFile, s.List())
+}
+
+// loadFromFile restores sent reports from the persistence file
+func (s *Store) loadFromFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	jsonData, err := os.ReadFile(s.persistFile)
+	if os.IsNotExist(err) {
+		return nil // No existing data, start fresh
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read L-R-M reports file: %w", err)
+	}
+
+	var list []SentReport
+	if err := json.Unmarshal(jsonData, &list); err != nil {
+		return fmt.Errorf("failed to parse L-R-M reports file: %w", err)
+	}
+	for _, report := range list {
+		s.sent[report.Cycle] = report
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// ScenarioHeader selects the scenario of a single request
+	ScenarioHeader = "X-Mock-Scenario"
+	// ScenarioPathPrefix selects the scenario of a request by URL, e.g. /scenarios/proposed-stuck/launchpad/...
+	ScenarioPathPrefix = "/scenarios/"
+	// scenariosDir holds one overlay directory per scenario under the data directory
+	scenariosDir = "scenarios"
+	// scenarioFile describes a scenario and the failures it injects
+	scenarioFile = "scenario.json"
+	// adminPath lists the scenarios and switches the active one
+	adminPath = "/_mock/scenario"
+)
+
+// scenarioNamePattern restricts scenario names to directory-safe identifiers
+var scenarioNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// Failure makes the requests under a path prefix fail with a status code, e.g. a Launchpad outage
+type Failure struct {
+	Path   string `json:"path"`   // Path prefix, e.g. "/launchpad/"
+	Status int    `json:"status"` // e.g. 503
+	Body   string `json:"body,omitempty"`
+}
+
+// Scenario is a named data set: files in its directory replace those of the base data
+// directory, and its failures are served instead of any data
+type Scenario struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Failures    []Failure `json:"failures,omitempty"`
+}
+
+// Scenarios lists the scenarios of the data directory ordered by name
+func (s *Server) Scenarios() []Scenario {
+	entries, err := os.ReadDir(filepath.Join(s.dataDir, scenariosDir))
+	if err != nil {
+		return []Scenario{}
+	}
+	scenarios := make([]Scenario, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || !scenarioNamePattern.MatchString(entry.Name()) {
+			continue
+		}
+		scenario, err := s.loadScenario(entry.Name())
+		if err != nil {
+			log.Printf("⚠️  Skipping scenario %s: %v", entry.Name(), err)
+			continue
+		}
+		scenarios = append(scenarios, *scenario)
+	}
+	sort.Slice(scenarios, func(i, j int) bool { return scenarios[i].Name < scenarios[j].Name })
+	return scenarios
+}
+
+// loadScenario reads a scenario directory and its optional description file
+func (s *Server) loadScenario(name string) (*Scenario, error) {
+	if !scenarioNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid scenario name %q", name)
+	}
+	dir := filepath.Join(s.dataDir, scenariosDir, name)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("unknown scenario %q", name)
+	}
+
+	scenario := &Scenario{}
+	data, err := os.ReadFile(filepath.Join(dir, scenarioFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", scenarioFile, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, scenario); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", scenarioFile, err)
+		}
+	}
+	scenario.Name = name
+	return scenario, nil
+}
+
+// ActiveScenario returns the scenario served to requests that do not select one, "" for the base data
+func (s *Server) ActiveScenario() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active
+}
+
+// SetActiveScenario switches the scenario served by default; "" switches back to the base data
+func (s *Server) SetActiveScenario(name string) error {
+	if name != "" {
+		if _, err := s.loadScenario(name); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	s.active = name
+	s.mu.Unlock()
+	log.Printf("🎬 Active scenario: %s", scenarioLabel(name))
+	return nil
+}
+
+// requestScenario strips a scenario path prefix from the request and returns the scenario it
+// selects: the path prefix first, then the header, then the active scenario
+func (s *Server) requestScenario(r *http.Request) (*Scenario, error) {
+	name := s.ActiveScenario()
+	if header := r.Header.Get(ScenarioHeader); header != "" {
+		name = header
+	}
+	if strings.HasPrefix(r.URL.Path, ScenarioPathPrefix) {
+		rest := strings.TrimPrefix(r.URL.Path, ScenarioPathPrefix)
+		name = rest
+		r.URL.Path = "/"
+		if i := strings.Index(rest, "/"); i >= 0 {
+			name, r.URL.Path = rest[:i], rest[i:]
+		}
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return s.loadScenario(name)
+}
+
+// failure returns the injected failure of a scenario for a path, if any
+func (sc *Scenario) failure(path string) *Failure {
+	if sc == nil {
+		return nil
+	}
+	for i := range sc.Failures {
+		if strings.HasPrefix(path, sc.Failures[i].Path) {
+			return &sc.Failures[i]
+		}
+	}
+	return nil
+}
+
+// dataPath returns where a data file is read from: the scenario's copy when it has one,
+// otherwise the base data directory
+func (s *Server) dataPath(scenario *Scenario, filename string) string {
+	if scenario != nil {
+		path := filepath.Join(s.dataDir, scenariosDir, scenario.Name, filename)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(s.dataDir, filename)
+}
+
+// handleAdmin lists the scenarios (GET) and switches the active one (PUT or POST with ?name=,
+// DELETE to go back to the base data)
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		if err := s.SetActiveScenario(r.URL.Query().Get("name")); err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+	case http.MethodDelete:
+		s.SetActiveScenario("")
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"active":    s.ActiveScenario(),
+		"scenarios": s.Scenarios(),
+	})
+}
+
+// scenarioLabel names a scenario for the request log
+func scenarioLabel(name string) string {
+	if name == "" {
+		return "base data"
+	}
+	return name
+}
@@ -0,0 +1,292 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Server provides mock responses for external APIs from files in a data directory, optionally
+// overlaid by a named scenario
+type Server struct {
+	dataDir string
+
+	mu     sync.RWMutex
+	active string // Scenario served when a request selects none
+}
+
+// NewServer creates a mock server serving the files of dataDir
+func NewServer(dataDir string) *Server {
+	return &Server{dataDir: dataDir}
+}
+
+// DataDir returns the directory the mock data is served from
+func (s *Server) DataDir() string {
+	return s.dataDir
+}
+
+// ServeHTTP routes requests to the appropriate mock handlers
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📥 Mock request: %s %s", r.Method, r.URL.Path)
+
+	// Add CORS headers for browser requests
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, "+ScenarioHeader)
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.URL.Path == adminPath {
+		s.handleAdmin(w, r)
+		return
+	}
+
+	scenario, err := s.requestScenario(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if scenario != nil {
+		w.Header().Set(ScenarioHeader, scenario.Name)
+	}
+	if failure := scenario.failure(r.URL.Path); failure != nil {
+		log.Printf("💥 Scenario %s fails %s with %d", scenario.Name, r.URL.Path, failure.Status)
+		http.Error(w, failure.Body, failure.Status)
+		return
+	}
+
+	path := r.URL.Path
+
+	switch {
+	case strings.HasPrefix(path, "/launchpad/"):
+		s.handleLaunchpadAPI(w, r, scenario)
+	case strings.HasPrefix(path, "/nvidia/"):
+		s.handleNVIDIAAPI(w, r, scenario)
+	case strings.HasPrefix(path, "/kernel/"):
+		s.handleKernelAPI(w, r, scenario)
+	case strings.HasPrefix(path, "/ubuntu/"):
+		s.handleUbuntuAPI(w, r)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+// seriesFromPath returns the series of a series-scoped archive path such as
+// /launchpad/ubuntu/noble/+archive/primary, or "" for the distribution-wide archive
+func seriesFromPath(path string) string {
+	if !strings.Contains(path, "/ubuntu/") || strings.Contains(path, "/ubuntu/+archive/") {
+		return ""
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if part == "ubuntu" && i+1 < len(parts) && parts[i+1] != "+archive" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// resolveFile returns the data file of a package under dir ("sources" or "binaries"),
+// preferring the series-specific file when the scenario or the base data has it
+func (s *Server) resolveFile(scenario *Scenario, dir, series, name string) string {
+	generic := fmt.Sprintf("launchpad/%s/%s.json", dir, name)
+	if series == "" {
+		return generic
+	}
+	specific := fmt.Sprintf("launchpad/%s/%s-%s.json", dir, series, name)
+	if _, err := os.Stat(s.dataPath(scenario, specific)); os.IsNotExist(err) {
+		return generic
+	}
+	return specific
+}
+
+// seriesLabel formats the series of a query for the request log
+func seriesLabel(series string) string {
+	if series == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [series=%s]", series)
+}
+
+// handleLaunchpadAPI handles Launchpad API mock responses with parameter awareness
+func (s *Server) handleLaunchpadAPI(w http.ResponseWriter, r *http.Request, scenario *Scenario) {
+	path := r.URL.Path
+	query := r.URL.Query()
+
+	// Handle published sources API
+	if strings.Contains(path, "+archive/primary") && query.Get("ws.op") == "getPublishedSources" {
+		sourceName := query.Get("source_name")
+		if sourceName == "" {
+			http.Error(w, "Missing source_name parameter", http.StatusBadRequest)
+			return
+		}
+
+		// Try to serve series-specific file first, then fall back to generic
+		series := seriesFromPath(path)
+		filename := s.resolveFile(scenario, "sources", series, sourceName)
+
+		// Log parameter analysis for debugging
+		params := []string{}
+		if query.Get("created_since_date") != "" {
+			params = append(params, fmt.Sprintf("date=%s", query.Get("created_since_date")))
+		}
+		if query.Get("exact_match") == "true" {
+			params = append(params, "exact_match=true")
+		}
+		if query.Get("order_by_date") == "true" {
+			params = append(params, "order_by_date=true")
+		}
+
+		paramStr := ""
+		if len(params) > 0 {
+			paramStr = fmt.Sprintf(" [%s]", strings.Join(params, ", "))
+		}
+
+		log.Printf("📦 Source query: %s%s%s", sourceName, seriesLabel(series), paramStr)
+		s.serveFile(w, scenario, filename, "application/json")
+		return
+	}
+
+	// Handle published binaries API
+	if strings.Contains(path, "+archive/primary") && query.Get("ws.op") == "getPublishedBinaries" {
+		binaryName := query.Get("binary_name")
+		if binaryName == "" {
+			http.Error(w, "Missing binary_name parameter", http.StatusBadRequest)
+			return
+		}
+
+		// Try series-specific file first, then fall back to generic
+		series := seriesFromPath(path)
+		filename := s.resolveFile(scenario, "binaries", series, binaryName)
+
+		exactMatch := ""
+		if query.Get("exact_match") == "true" {
+			exactMatch = " [exact_match=true]"
+		}
+
+		log.Printf("📦 Binary query: %s%s%s", binaryName, seriesLabel(series), exactMatch)
+		s.serveFile(w, scenario, filename, "application/json")
+		return
+	}
+
+	// Handle Ubuntu series API
+	if strings.HasPrefix(path, "/launchpad/ubuntu/") {
+		series := strings.TrimPrefix(path, "/launchpad/ubuntu/")
+		// Remove any trailing path components
+		if idx := strings.Index(series, "/"); idx != -1 {
+			series = series[:idx]
+		}
+
+		if series != "" {
+			log.Printf("🐧 Series info: %s", series)
+			s.serveFile(w, scenario, fmt.Sprintf("launchpad/series/%s.json", series), "application/json")
+			return
+		}
+	}
+
+	s.handleNotFound(w, r)
+}
+
+// handleNVIDIAAPI handles NVIDIA API mock responses
+func (s *Server) handleNVIDIAAPI(w http.ResponseWriter, r *http.Request, scenario *Scenario) {
+	switch r.URL.Path {
+	case "/nvidia/datacenter/releases.json":
+		s.serveFile(w, scenario, "nvidia/server-drivers.json", "application/json")
+	case "/nvidia/drivers":
+		s.serveFile(w, scenario, "nvidia/driver-archive.html", "text/html")
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+// handleKernelAPI handles kernel API mock responses
+func (s *Server) handleKernelAPI(w http.ResponseWriter, r *http.Request, scenario *Scenario) {
+	switch r.URL.Path {
+	case "/kernel/series.yaml":
+		s.serveFile(w, scenario, "kernel/series.yaml", "text/yaml")
+	case "/kernel/sru-cycle.yaml":
+		s.serveFile(w, scenario, "kernel/sru-cycle.yaml", "text/yaml")
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+// handleUbuntuAPI handles Ubuntu API mock responses
+func (s *Server) handleUbuntuAPI(w http.ResponseWriter, r *http.Request) {
+	// For now, just return a simple response
+	// This could be expanded to serve Ubuntu assets
+	s.handleNotFound(w, r)
+}
+
+// handleNotFound handles 404 responses
+func (s *Server) handleNotFound(w http.ResponseWriter, r *http.Request) {
+	log.Printf("❌ Mock endpoint not found: %s", r.URL.Path)
+	response := map[string]interface{}{
+		"error":   "Mock endpoint not found",
+		"path":    r.URL.Path,
+		"message": "This mock endpoint is not implemented yet",
+		"hint":    "Check the mock server configuration or add test data files",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(response)
+}
+
+// serveFile serves a file from the scenario or the test data directory
+func (s *Server) serveFile(w http.ResponseWriter, scenario *Scenario, filename, contentType string) {
+	fullPath := s.dataPath(scenario, filename)
+
+	// Check if file exists
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		log.Printf("⚠️  Mock data file not found: %s", fullPath)
+		// Generate a minimal response based on the file type
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(fallbackResponse(filename))
+		log.Printf("🔄 Generated fallback response for: %s", filename)
+		return
+	}
+
+	// Serve the file
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		log.Printf("❌ Error reading mock data file %s: %v", fullPath, err)
+		http.Error(w, "Error reading mock data", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+	log.Printf("✅ Served mock data: %s", filename)
+}
+
+// fallbackResponse generates a minimal response when a data file doesn't exist. Known APIs get
+// an empty response of the same shape as the real one.
+func fallbackResponse(filename string) interface{} {
+	switch {
+	case strings.Contains(filename, "launchpad/sources/"), strings.Contains(filename, "launchpad/binaries/"):
+		return map[string]interface{}{
+			"total_size": 0,
+			"start":      0,
+			"entries":    []interface{}{},
+		}
+	case strings.Contains(filename, "nvidia/server-drivers"):
+		// The datacenter releases are keyed by branch at the top level
+		return map[string]interface{}{}
+	default:
+		return map[string]interface{}{
+			"mock":    true,
+			"message": "Fallback response - no test data file found",
+			"file":    filename,
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/packages"
+)
+
+// capturedDir holds real upstream responses captured from the production APIs
+const capturedDir = "../../captured_real_api_responses"
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	dataDir := t.TempDir()
+	files := map[string]string{
+		"launchpad/sources/nvidia-graphics-drivers-570.json":       `{"source": "generic"}`,
+		"launchpad/sources/noble-nvidia-graphics-drivers-570.json": `{"source": "noble"}`,
+		"launchpad/binaries/nvidia-dkms-570.json":                  `{"binary": "generic"}`,
+		"launchpad/binaries/jammy-nvidia-dkms-570.json":            `{"binary": "jammy"}`,
+		"launchpad/series/noble.json":                              `{"series": "noble"}`,
+		"nvidia/server-drivers.json":                               `{"570": {"type": "production branch", "driver_info": []}}`,
+		"nvidia/driver-archive.html":                               `<html>archive</html>`,
+		"kernel/series.yaml":                                       "series: yaml\n",
+		"kernel/sru-cycle.yaml":                                    "cycle: yaml\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dataDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewServer(dataDir)
+}
+
+func TestServerRouting(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"sources", "GET", "/launchpad/ubuntu/+archive/primary?ws.op=getPublishedSources&source_name=nvidia-graphics-drivers-570&exact_match=true", 200, "application/json", `"generic"`},
+		{"sources of a series", "GET", "/launchpad/ubuntu/noble/+archive/primary?ws.op=getPublishedSources&source_name=nvidia-graphics-drivers-570", 200, "application/json", `"noble"`},
+		{"sources of a series without its own file", "GET", "/launchpad/ubuntu/jammy/+archive/primary?ws.op=getPublishedSources&source_name=nvidia-graphics-drivers-570", 200, "application/json", `"generic"`},
+		{"sources without name", "GET", "/launchpad/ubuntu/+archive/primary?ws.op=getPublishedSources", 400, "", "Missing source_name"},
+		{"unknown sources", "GET", "/launchpad/ubuntu/+archive/primary?ws.op=getPublishedSources&source_name=nvidia-graphics-drivers-000", 200, "application/json", `"entries":[]`},
+		{"binaries", "GET", "/launchpad/ubuntu/+archive/primary?ws.op=getPublishedBinaries&binary_name=nvidia-dkms-570", 200, "application/json", `"generic"`},
+		{"binaries of a series", "GET", "/launchpad/ubuntu/jammy/+archive/primary?ws.op=getPublishedBinaries&binary_name=nvidia-dkms-570", 200, "application/json", `"jammy"`},
+		{"binaries without name", "GET", "/launchpad/ubuntu/+archive/primary?ws.op=getPublishedBinaries", 400, "", "Missing binary_name"},
+		{"series", "GET", "/launchpad/ubuntu/noble", 200, "application/json", `"noble"`},
+		{"series with trailing path", "GET", "/launchpad/ubuntu/noble/extra", 200, "application/json", `"noble"`},
+		{"unknown series", "GET", "/launchpad/ubuntu/zesty", 200, "application/json", `"mock":true`},
+		{"unknown launchpad path", "GET", "/launchpad/other", 404, "application/json", "Mock endpoint not found"},
+		{"datacenter releases", "GET", "/nvidia/datacenter/releases.json", 200, "application/json", "production branch"},
+		{"driver archive", "GET", "/nvidia/drivers", 200, "text/html", "archive"},
+		{"unknown nvidia path", "GET", "/nvidia/other", 404, "application/json", "Mock endpoint not found"},
+		{"kernel series", "GET", "/kernel/series.yaml", 200, "text/yaml", "series: yaml"},
+		{"sru cycle", "GET", "/kernel/sru-cycle.yaml", 200, "text/yaml", "cycle: yaml"},
+		{"unknown kernel path", "GET", "/kernel/other.yaml", 404, "application/json", "Mock endpoint not found"},
+		{"ubuntu assets", "GET", "/ubuntu/assets/logo.svg", 404, "application/json", "Mock endpoint not found"},
+		{"unknown prefix", "GET", "/other", 404, "application/json", "Mock endpoint not found"},
+		{"preflight", "OPTIONS", "/launchpad/ubuntu/noble", 200, "", ""},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(test.method, test.target, nil))
+
+		if w.Code != test.status {
+			t.Errorf("%s: status = %d, expected %d", test.name, w.Code, test.status)
+		}
+		if test.contentType != "" && w.Header().Get("Content-Type") != test.contentType {
+			t.Errorf("%s: Content-Type = %q, expected %q", test.name, w.Header().Get("Content-Type"), test.contentType)
+		}
+		if !strings.Contains(w.Body.String(), test.body) {
+			t.Errorf("%s: body = %q, expected it to contain %q", test.name, w.Body.String(), test.body)
+		}
+		if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Errorf("%s: missing CORS headers", test.name)
+		}
+	}
+}
+
+func TestSeriesFromPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/launchpad/ubuntu/+archive/primary", ""},
+		{"/launchpad/ubuntu/noble/+archive/primary", "noble"},
+		{"/launchpad/other/+archive/primary", ""},
+	}
+	for _, test := range tests {
+		if series := seriesFromPath(test.path); series != test.expected {
+			t.Errorf("seriesFromPath(%s) = %q, expected %q", test.path, series, test.expected)
+		}
+	}
+}
+
+// readCaptured decodes a captured response, skipping the test when it is not available
+func readCaptured(t *testing.T, pattern string) map[string]interface{} {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(capturedDir, pattern))
+	if len(matches) == 0 {
+		t.Skipf("no captured response matches %s", pattern)
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode %s: %v", matches[0], err)
+	}
+	return decoded
+}
+
+// fallback returns the fallback response of a data file as decoded JSON
+func fallback(t *testing.T, filename string) (map[string]interface{}, []byte) {
+	t.Helper()
+	data, err := json.Marshal(fallbackResponse(filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded, data
+}
+
+func TestFallbackMatchesLaunchpadCollections(t *testing.T) {
+	real := readCaptured(t, "api_launchpad_net_*getPublishedSources*nvidia_graphics_drivers_570_created*.json")
+
+	for _, filename := range []string{"launchpad/sources/nvidia-graphics-drivers-000.json", "launchpad/binaries/nvidia-dkms-000.json"} {
+		fake, data := fallback(t, filename)
+		for key, value := range fake {
+			realValue, ok := real[key]
+			if !ok {
+				t.Errorf("%s: fallback key %q is not in the real response", filename, key)
+				continue
+			}
+			if jsonKind(value) != jsonKind(realValue) {
+				t.Errorf("%s: fallback %q is a %s, the real response has a %s", filename, key, jsonKind(value), jsonKind(realValue))
+			}
+		}
+		for key := range real {
+			if _, ok := fake[key]; !ok {
+				t.Errorf("%s: real key %q is missing from the fallback", filename, key)
+			}
+		}
+
+		var sources packages.SourceAPIResponse
+		if err := json.Unmarshal(data, &sources); err != nil || len(sources.Entries) != 0 {
+			t.Errorf("%s: fallback should decode as an empty collection, got %+v (%v)", filename, sources, err)
+		}
+	}
+}
+
+func TestFallbackMatchesDatacenterReleases(t *testing.T) {
+	real := readCaptured(t, "docs_nvidia_com_datacenter_tesla_drivers_releases_json.json")
+
+	// The real response is keyed by branch, so the fallback may only hold branch entries
+	fake, data := fallback(t, "nvidia/server-drivers.json")
+	for key, value := range fake {
+		if jsonKind(value) != "object" {
+			t.Errorf("fallback key %q is a %s, the real response holds branch objects", key, jsonKind(value))
+		}
+	}
+	for branch, value := range real {
+		if jsonKind(value) != "object" {
+			t.Errorf("real branch %q is a %s, the fallback contract assumes objects", branch, jsonKind(value))
+		}
+	}
+
+	var branches drivers.AllBranches
+	if err := json.Unmarshal(data, &branches); err != nil || len(branches) != 0 {
+		t.Errorf("fallback should decode as no branches, got %v (%v)", branches, err)
+	}
+}
+
+// jsonKind returns the JSON type of a decoded value
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	server := newTestServer(t)
+	files := map[string]string{
+		"scenarios/proposed-stuck/scenario.json":                                      `{"description": "570 stuck in -proposed"}`,
+		"scenarios/proposed-stuck/launchpad/sources/nvidia-graphics-drivers-570.json": `{"source": "stuck"}`,
+		"scenarios/launchpad-outage/scenario.json":                                    `{"failures": [{"path": "/launchpad/", "status": 503, "body": "down"}]}`,
+		"scenarios/broken/scenario.json":                                              `{`,
+	}
+	for name, content := range files {
+		path := filepath.Join(server.DataDir(), name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if scenarios := server.Scenarios(); len(scenarios) != 2 || scenarios[0].Name != "launchpad-outage" || scenarios[1].Description != "570 stuck in -proposed" {
+		t.Errorf("Scenarios() = %+v, expected the two valid scenarios", scenarios)
+	}
+
+	sources := "/launchpad/ubuntu/+archive/primary?ws.op=getPublishedSources&source_name=nvidia-graphics-drivers-570"
+	get := func(target, header string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", target, nil)
+		if header != "" {
+			r.Header.Set(ScenarioHeader, header)
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name, target, header string
+		status               int
+		body                 string
+	}{
+		{"base data", sources, "", 200, `"generic"`},
+		{"scenario file", sources, "proposed-stuck", 200, `"stuck"`},
+		{"base file under a scenario", "/nvidia/datacenter/releases.json", "proposed-stuck", 200, "production branch"},
+		{"scenario path prefix", "/scenarios/proposed-stuck" + sources, "", 200, `"stuck"`},
+		{"path prefix wins over header", "/scenarios/proposed-stuck" + sources, "launchpad-outage", 200, `"stuck"`},
+		{"injected failure", sources, "launchpad-outage", 503, "down"},
+		{"failure outside its path", "/kernel/series.yaml", "launchpad-outage", 200, "series: yaml"},
+		{"unknown scenario", sources, "missing", 404, "unknown scenario"},
+		{"invalid scenario", "/scenarios/../launchpad/ubuntu/noble", "", 404, "invalid scenario"},
+	}
+	for _, test := range tests {
+		w := get(test.target, test.header)
+		if w.Code != test.status || !strings.Contains(w.Body.String(), test.body) {
+			t.Errorf("%s: %d %q, expected %d containing %q", test.name, w.Code, w.Body.String(), test.status, test.body)
+		}
+	}
+
+	admin := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+	if w := admin("PUT", "/_mock/scenario?name=missing"); w.Code != 404 || server.ActiveScenario() != "" {
+		t.Errorf("PUT unknown scenario = %d, active %q, expected 404 and no change", w.Code, server.ActiveScenario())
+	}
+	if w := admin("PUT", "/_mock/scenario?name=proposed-stuck"); w.Code != 200 || !strings.Contains(w.Body.String(), `"active":"proposed-stuck"`) {
+		t.Errorf("PUT proposed-stuck = %d %s", w.Code, w.Body.String())
+	}
+	if w := get(sources, ""); !strings.Contains(w.Body.String(), `"stuck"`) || w.Header().Get(ScenarioHeader) != "proposed-stuck" {
+		t.Errorf("request after switching = %q, expected the active scenario's data", w.Body.String())
+	}
+	if w := admin("DELETE", "/_mock/scenario"); w.Code != 200 || server.ActiveScenario() != "" {
+		t.Errorf("DELETE = %d, active %q, expected the base data", w.Code, server.ActiveScenario())
+	}
+	if w := get(sources, ""); !strings.Contains(w.Body.String(), `"generic"`) {
+		t.Errorf("request after reset = %q, expected the base data", w.Body.String())
+	}
+}
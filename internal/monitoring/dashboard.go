@@ -0,0 +1,203 @@
+package monitoring
+
+import (
+	"encoding/json"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// Dashboard is a Grafana dashboard, in the JSON model Grafana imports
+type Dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	Refresh       string     `json:"refresh"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Time          TimeRange  `json:"time"`
+	Templating    Templating `json:"templating"`
+	Panels        []Panel    `json:"panels"`
+}
+
+// TimeRange is the default time range of the dashboard
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Templating holds the dashboard variables
+type Templating struct {
+	List []Variable `json:"list"`
+}
+
+// Variable is a dashboard variable; the only one selects the Prometheus data source
+type Variable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+// Panel is a dashboard panel
+type Panel struct {
+	ID          int          `json:"id"`
+	Type        string       `json:"type"` // "stat", "timeseries", "bargauge" or "table"
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	GridPos     GridPos      `json:"gridPos"`
+	Datasource  Datasource   `json:"datasource"`
+	Targets     []Target     `json:"targets"`
+	FieldConfig *FieldConfig `json:"fieldConfig,omitempty"`
+}
+
+// GridPos places a panel on the 24 column grid
+type GridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// Datasource refers to the data source selected by the dashboard variable
+type Datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+// Target is a PromQL query of a panel
+type Target struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+	Instant      bool   `json:"instant,omitempty"`
+	Format       string `json:"format,omitempty"` // "table" for table panels
+}
+
+// FieldConfig sets the unit, range and thresholds of the values of a panel
+type FieldConfig struct {
+	Defaults FieldDefaults `json:"defaults"`
+}
+
+// FieldDefaults are the field settings of every series of a panel
+type FieldDefaults struct {
+	Unit       string      `json:"unit,omitempty"`
+	Min        *float64    `json:"min,omitempty"`
+	Max        *float64    `json:"max,omitempty"`
+	Thresholds *Thresholds `json:"thresholds,omitempty"`
+}
+
+// Thresholds color values from the first step up to the next one
+type Thresholds struct {
+	Mode  string          `json:"mode"`
+	Steps []ThresholdStep `json:"steps"`
+}
+
+// ThresholdStep starts a color at Value; the first step has no value
+type ThresholdStep struct {
+	Color string   `json:"color"`
+	Value *float64 `json:"value"`
+}
+
+// dashboardLayout places panels left to right, starting a new row when one does not fit
+type dashboardLayout struct {
+	panels []Panel
+	x, y   int
+	rowH   int
+}
+
+// add appends a panel of the given size with its queries
+func (l *dashboardLayout) add(panelType, title, description string, w, h int, fields *FieldConfig, targets ...Target) {
+	if l.x+w > 24 {
+		l.x, l.y, l.rowH = 0, l.y+l.rowH, 0
+	}
+	for i := range targets {
+		targets[i].RefID = string(rune('A' + i))
+	}
+	l.panels = append(l.panels, Panel{
+		ID:          len(l.panels) + 1,
+		Type:        panelType,
+		Title:       title,
+		Description: description,
+		GridPos:     GridPos{X: l.x, Y: l.y, W: w, H: h},
+		Datasource:  prometheusSource,
+		Targets:     targets,
+		FieldConfig: fields,
+	})
+	l.x += w
+	if h > l.rowH {
+		l.rowH = h
+	}
+}
+
+// prometheusSource is the data source chosen with the dashboard variable on import
+var prometheusSource = Datasource{Type: "prometheus", UID: "${datasource}"}
+
+// thresholds colors values with base below the first step
+func thresholds(base string, steps ...ThresholdStep) *Thresholds {
+	return &Thresholds{Mode: "absolute", Steps: append([]ThresholdStep{{Color: base}}, steps...)}
+}
+
+// step starts color at value
+func step(value float64, color string) ThresholdStep {
+	return ThresholdStep{Color: color, Value: &value}
+}
+
+// BuildDashboard builds the dashboard of the metrics a configuration exposes: data freshness
+// against the stale limit, outdated series, SLO compliance when targets are configured, firing
+// alerts and upstream traffic
+func BuildDashboard(cfg *config.Config) Dashboard {
+	var layout dashboardLayout
+	limit := staleLimit(cfg).Seconds()
+
+	layout.add("stat", "Data age", "Seconds since the dashboard data was refreshed; red past the stale limit", 8, 4,
+		&FieldConfig{Defaults: FieldDefaults{Unit: "s", Thresholds: thresholds("green", step(limit, "red"))}},
+		Target{Expr: "nvidia_monitor_data_age_seconds"})
+	layout.add("stat", "Outdated series", "Series whose published version is behind upstream", 8, 4,
+		&FieldConfig{Defaults: FieldDefaults{Thresholds: thresholds("green", step(1, "orange"))}},
+		Target{Expr: "sum(nvidia_monitor_outdated_series)"})
+	layout.add("stat", "Firing alerts", "Alerts raised by the monitor and not silenced by a maintenance window", 8, 4,
+		&FieldConfig{Defaults: FieldDefaults{Thresholds: thresholds("green", step(1, "red"))}},
+		Target{Expr: "count(nvidia_monitor_alert_firing) or vector(0)"})
+
+	layout.add("timeseries", "Outdated series by package", "", 24, 8, nil,
+		Target{Expr: "nvidia_monitor_outdated_series", LegendFormat: "{{package}}"})
+
+	if len(sloRules(&cfg.SLO)) > 0 {
+		zero, one := 0.0, 1.0
+		layout.add("bargauge", "SLO compliance", "Upstream releases in the SLO window published within the target of their branch", 12, 8,
+			&FieldConfig{Defaults: FieldDefaults{Unit: "percentunit", Min: &zero, Max: &one, Thresholds: thresholds("red", step(0.8, "orange"), step(0.95, "green"))}},
+			Target{Expr: "nvidia_monitor_slo_compliance_ratio", LegendFormat: "{{branch}}", Instant: true})
+		layout.add("table", "SLO breaches", "Series whose latest upstream release missed the target", 12, 8, nil,
+			Target{Expr: "nvidia_monitor_slo_breached == 1", Instant: true, Format: "table"})
+	}
+
+	layout.add("table", "Alerts", "The alerts firing now, with their severity", 24, 6, nil,
+		Target{Expr: "nvidia_monitor_alert_firing", Instant: true, Format: "table"})
+
+	layout.add("timeseries", "Upstream responses", "Upstream HTTP responses per second by domain and status code", 8, 8,
+		&FieldConfig{Defaults: FieldDefaults{Unit: "reqps"}},
+		Target{Expr: "sum by (domain, code) (rate(nvidia_monitor_upstream_responses_total[5m]))", LegendFormat: "{{domain}} {{code}}"})
+	layout.add("timeseries", "Upstream failures", "Upstream requests per second that got no response after all retries", 8, 8,
+		&FieldConfig{Defaults: FieldDefaults{Unit: "reqps"}},
+		Target{Expr: "sum by (domain) (rate(nvidia_monitor_upstream_failed_requests_total[5m]))", LegendFormat: "{{domain}}"})
+	layout.add("timeseries", "Upstream traffic", "Bytes of upstream response bodies read per second", 8, 8,
+		&FieldConfig{Defaults: FieldDefaults{Unit: "Bps"}},
+		Target{Expr: "sum by (domain) (rate(nvidia_monitor_upstream_response_bytes_total[5m]))", LegendFormat: "{{domain}}"})
+
+	return Dashboard{
+		UID:           "nvidia-driver-monitor",
+		Title:         "NVIDIA Driver Monitor",
+		Tags:          []string{"nvidia", "drivers"},
+		Timezone:      "utc",
+		Refresh:       "5m",
+		SchemaVersion: 39,
+		Time:          TimeRange{From: "now-7d", To: "now"},
+		Templating:    Templating{List: []Variable{{Name: "datasource", Label: "Prometheus", Type: "datasource", Query: "prometheus"}}},
+		Panels:        layout.panels,
+	}
+}
+
+// MarshalDashboard returns the dashboard in indented JSON, ready for import
+func MarshalDashboard(dashboard Dashboard) ([]byte, error) {
+	return json.MarshalIndent(dashboard, "", "  ")
+}
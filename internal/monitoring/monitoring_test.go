@@ -0,0 +1,100 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+func TestRulesFollowConfiguration(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Alerts.StaleFactor = 4
+	cfg.SLO.Targets = []config.SLOTarget{
+		{Branch: "*", Description: "updates published within 14 days of upstream", MaxDays: 14},
+		{Branch: "570", MaxDays: 7},
+		{Branch: "535-server", MaxDays: 10},
+		{Branch: "550", MaxDays: 0}, // Disabled, left to the wildcard
+	}
+	cfg.Alerts.CutoffRules = []config.CutoffRule{{Branches: []string{"*-server"}, DaysBefore: 3, Severity: "critical"}}
+
+	data, err := MarshalRules(Rules(cfg))
+	if err != nil {
+		t.Fatalf("MarshalRules: %v", err)
+	}
+	var file RuleFile
+	if err := yaml.Unmarshal(data, &file); err != nil || len(file.Groups) != 1 {
+		t.Fatalf("rule file does not read back: %v\n%s", err, data)
+	}
+	exprs := make(map[string][]string)
+	for _, rule := range file.Groups[0].Rules {
+		exprs[rule.Alert] = append(exprs[rule.Alert], rule.Expr)
+		if rule.Labels["severity"] == "" {
+			t.Errorf("%s has no severity", rule.Alert)
+		}
+	}
+
+	expected := map[string][]string{
+		"NvidiaMonitorDataStale":   {"nvidia_monitor_data_age_seconds > 1200"},
+		"NvidiaMonitorDataMissing": {"absent(nvidia_monitor_data_age_seconds)"},
+		"NvidiaDriverSLOBreached": {
+			`nvidia_monitor_slo_breached{branch="570"} == 1`,
+			`nvidia_monitor_slo_breached{branch="535-server"} == 1`,
+			`nvidia_monitor_slo_breached{branch!~"570|535-server"} == 1`,
+		},
+		"NvidiaDriverSRUCutoff": {`nvidia_monitor_alert_firing{name=~"sru-cutoff-.*"} == 1`},
+		"NvidiaMonitorAlert":    {`nvidia_monitor_alert_firing{name!~"dashboard-data-stale|sru-cutoff-.*"} == 1`},
+	}
+	for alert, want := range expected {
+		if strings.Join(exprs[alert], "\n") != strings.Join(want, "\n") {
+			t.Errorf("%s = %q, expected %q", alert, exprs[alert], want)
+		}
+	}
+	if len(exprs) != len(expected) {
+		t.Errorf("rules = %v, expected only %v", exprs, expected)
+	}
+
+	// Without SLOs or cutoff rules only the rules of the built-in alerts are generated
+	rules := Rules(config.DefaultConfig()).Groups[0].Rules
+	if len(rules) != 3 || rules[2].Expr != `nvidia_monitor_alert_firing{name!~"dashboard-data-stale"} == 1` {
+		t.Errorf("default rules = %+v", rules)
+	}
+}
+
+func TestDashboardPanelsFitTheGrid(t *testing.T) {
+	cfg := config.DefaultConfig()
+	without := BuildDashboard(cfg)
+	cfg.SLO.Targets = []config.SLOTarget{{Branch: "570", MaxDays: 7}}
+	dashboard := BuildDashboard(cfg)
+	if len(dashboard.Panels) != len(without.Panels)+2 {
+		t.Errorf("%d panels with an SLO, %d without; expected the 2 SLO panels only with one", len(dashboard.Panels), len(without.Panels))
+	}
+
+	data, err := MarshalDashboard(dashboard)
+	if err != nil {
+		t.Fatalf("MarshalDashboard: %v", err)
+	}
+	var decoded Dashboard
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("dashboard does not read back: %v", err)
+	}
+
+	// Panels must neither leave the grid nor overlap
+	for i, a := range decoded.Panels {
+		if a.GridPos.X+a.GridPos.W > 24 || len(a.Targets) == 0 || !strings.Contains(a.Targets[0].Expr, "nvidia_monitor_") {
+			t.Errorf("panel %q is misplaced or queries no monitor metric: %+v", a.Title, a)
+		}
+		for _, b := range decoded.Panels[i+1:] {
+			if a.GridPos.X < b.GridPos.X+b.GridPos.W && b.GridPos.X < a.GridPos.X+a.GridPos.W &&
+				a.GridPos.Y < b.GridPos.Y+b.GridPos.H && b.GridPos.Y < a.GridPos.Y+a.GridPos.H {
+				t.Errorf("panels %q and %q overlap", a.Title, b.Title)
+			}
+		}
+	}
+	if limit := *decoded.Panels[0].FieldConfig.Defaults.Thresholds.Steps[1].Value; limit != 900 {
+		t.Errorf("data age turns red at %v, expected the 900s stale limit", limit)
+	}
+}
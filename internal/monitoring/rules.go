@@ -0,0 +1,148 @@
+// Package monitoring generates the Prometheus alerting rules and the Grafana dashboard that
+// match the metrics of /metrics and the alerts and SLOs of a configuration, so that external
+// monitoring follows the in-app configuration instead of being maintained by hand.
+package monitoring
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// dashboardRefresh is how often the web service refreshes the dashboard data; the stale-data
+// limit is a multiple of it, as in the web service's watchdog
+const dashboardRefresh = 5 * time.Minute
+
+// In-app alert names the rules refer to; the web service raises them under these names
+const (
+	dashboardStaleAlert = "dashboard-data-stale"
+	cutoffAlertPrefix   = "sru-cutoff-"
+)
+
+// RuleFile is a Prometheus rule file
+type RuleFile struct {
+	Groups []RuleGroup `yaml:"groups"`
+}
+
+// RuleGroup is a named group of rules evaluated together
+type RuleGroup struct {
+	Name  string `yaml:"name"`
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is a Prometheus alerting rule
+type Rule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// staleLimit returns the age after which the dashboard data is stale
+func staleLimit(cfg *config.Config) time.Duration {
+	return time.Duration(cfg.Alerts.GetStaleFactor()) * dashboardRefresh
+}
+
+// Rules builds the alerting rules of a configuration: stale or missing dashboard data, one SLO
+// rule per configured target, the SRU cutoff rules when any are configured, and the other
+// alerts the monitor raises itself, with the severity it gave them
+func Rules(cfg *config.Config) RuleFile {
+	limit := staleLimit(cfg)
+	rules := []Rule{
+		{
+			Alert:       "NvidiaMonitorDataStale",
+			Expr:        fmt.Sprintf("nvidia_monitor_data_age_seconds > %.0f", limit.Seconds()),
+			For:         "5m",
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": fmt.Sprintf("Dashboard data has not been refreshed for more than %s", promDuration(limit))},
+		},
+		{
+			Alert:       "NvidiaMonitorDataMissing",
+			Expr:        "absent(nvidia_monitor_data_age_seconds)",
+			For:         promDuration(limit),
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "The monitor is down or has not loaded the dashboard data"},
+		},
+	}
+	rules = append(rules, sloRules(&cfg.SLO)...)
+
+	handled := []string{regexp.QuoteMeta(dashboardStaleAlert)}
+	if len(cfg.Alerts.CutoffRules) > 0 {
+		rules = append(rules, Rule{
+			Alert:       "NvidiaDriverSRUCutoff",
+			Expr:        fmt.Sprintf(`nvidia_monitor_alert_firing{name=~"%s.*"} == 1`, regexp.QuoteMeta(cutoffAlertPrefix)),
+			Labels:      map[string]string{"severity": "{{ $labels.severity }}"},
+			Annotations: map[string]string{"summary": "{{ $labels.name }}: an outdated branch is not in proposed ahead of its SRU cutoff"},
+		})
+		handled = append(handled, regexp.QuoteMeta(cutoffAlertPrefix)+".*")
+	}
+	rules = append(rules, Rule{
+		Alert:       "NvidiaMonitorAlert",
+		Expr:        fmt.Sprintf(`nvidia_monitor_alert_firing{name!~"%s"} == 1`, strings.Join(handled, "|")),
+		Labels:      map[string]string{"severity": "{{ $labels.severity }}"},
+		Annotations: map[string]string{"summary": "The monitor raised {{ $labels.name }}"},
+	})
+
+	return RuleFile{Groups: []RuleGroup{{Name: "nvidia-driver-monitor", Rules: rules}}}
+}
+
+// sloRules returns one rule per SLO target. A branch uses its own target over "*", so the
+// wildcard rule leaves out the branches with their own.
+func sloRules(cfg *config.SLOConfig) []Rule {
+	var rules []Rule
+	var branches []string
+	for _, target := range cfg.Targets {
+		if target.MaxDays < 1 || target.Branch == "*" {
+			continue
+		}
+		branches = append(branches, regexp.QuoteMeta(target.Branch))
+		rules = append(rules, sloRule(target, fmt.Sprintf(`branch=%q`, target.Branch)))
+	}
+	if wildcard := cfg.TargetFor("*"); wildcard != nil && wildcard.Branch == "*" {
+		matcher := ""
+		if len(branches) > 0 {
+			matcher = fmt.Sprintf(`branch!~"%s"`, strings.Join(branches, "|"))
+		}
+		rules = append(rules, sloRule(*wildcard, matcher))
+	}
+	return rules
+}
+
+// sloRule fires while the latest upstream release of a series covered by the target missed it
+func sloRule(target config.SLOTarget, matcher string) Rule {
+	rule := Rule{
+		Alert:  "NvidiaDriverSLOBreached",
+		Expr:   fmt.Sprintf("nvidia_monitor_slo_breached{%s} == 1", matcher),
+		For:    "1h",
+		Labels: map[string]string{"severity": "warning"},
+		Annotations: map[string]string{
+			"summary": fmt.Sprintf("{{ $labels.branch }} in {{ $labels.series }}: the latest upstream release was not published within %d days", target.MaxDays),
+		},
+	}
+	if target.Description != "" {
+		rule.Annotations["description"] = target.Description
+	}
+	return rule
+}
+
+// promDuration formats a duration the way Prometheus reads it, e.g. "15m" or "2h"
+func promDuration(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	if d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return fmt.Sprintf("%ds", d/time.Second)
+}
+
+// MarshalRules returns the rule file in YAML
+func MarshalRules(rules RuleFile) ([]byte, error) {
+	return yaml.Marshal(rules)
+}
@@ -0,0 +1,95 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"nvidia_driver_monitor/internal/cellstore"
+)
+
+// MaxLength is the longest note accepted, in characters; notes are shown as badges
+const MaxLength = 200
+
+// Note is an operator annotation on one package/series cell of the dashboard
+type Note struct {
+	Package   string    `json:"package"` // e.g. "nvidia-graphics-drivers-570"
+	Series    string    `json:"series"`  // Codename, e.g. "noble"
+	Text      string    `json:"text"`    // e.g. "blocked on LP#2012345"
+	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+}
+
+// SchemaVersion is the version of the format of the notes file, bumped when it changes
+const SchemaVersion = 1
+
+// Cell returns the package and series the note is attached to
+func (n Note) Cell() (string, string) {
+	return n.Package, n.Series
+}
+
+// Store keeps the notes and persists them to disk
+type Store struct {
+	cells *cellstore.Store[Note]
+}
+
+// NewStore creates a store, loading previously persisted notes if available
+func NewStore(persistFile string) *Store {
+	return &Store{cells: cellstore.New[Note](persistFile, "notes")}
+}
+
+// Validate checks a note can be stored, trimming its text
+func Validate(note *Note) error {
+	note.Text = strings.TrimSpace(note.Text)
+	if note.Package == "" || note.Series == "" {
+		return fmt.Errorf("package and series are required")
+	}
+	if note.Text == "" {
+		return fmt.Errorf("text is required")
+	}
+	if utf8.RuneCountInString(note.Text) > MaxLength {
+		return fmt.Errorf("text is longer than %d characters", MaxLength)
+	}
+	return nil
+}
+
+// Set attaches a note to a cell, replacing the previous one
+func (s *Store) Set(packageName, series, text, updatedBy string, now time.Time) (*Note, error) {
+	note := Note{Package: packageName, Series: series, Text: text, UpdatedAt: now, UpdatedBy: updatedBy}
+	if err := Validate(&note); err != nil {
+		return nil, err
+	}
+	s.cells.Set(note)
+	return &note, nil
+}
+
+// SetAll stores a batch of notes updated at now, replacing those of the same cells. Nothing is
+// stored unless every note is valid, and the batch is persisted once.
+func (s *Store) SetAll(list []Note, now time.Time) error {
+	batch := make([]Note, len(list))
+	for i, note := range list {
+		note.UpdatedAt = now
+		if err := Validate(&note); err != nil {
+			return fmt.Errorf("%s/%s: %w", note.Package, note.Series, err)
+		}
+		batch[i] = note
+	}
+	s.cells.Set(batch...)
+	return nil
+}
+
+// Remove deletes the note of a cell, reporting whether it existed
+func (s *Store) Remove(packageName, series string) bool {
+	return s.cells.Remove(packageName, series)
+}
+
+// Get returns the note of a cell
+func (s *Store) Get(packageName, series string) (Note, bool) {
+	return s.cells.Get(packageName, series)
+}
+
+// List returns all notes ordered by package and series
+func (s *Store) List() []Note {
+	return s.cells.List(nil)
+}
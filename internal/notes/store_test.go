@@ -0,0 +1,45 @@
+package notes
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStorePersistsNotes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	store := NewStore(path)
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	for _, text := range []string{"", "   ", strings.Repeat("x", MaxLength+1)} {
+		if _, err := store.Set("nvidia-graphics-drivers-570", "noble", text, "", now); err == nil {
+			t.Errorf("Set(%q) expected an error", text)
+		}
+	}
+
+	if _, err := store.Set("nvidia-graphics-drivers-570", "noble", "waiting for SRU review", "jdoe", now); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+	if _, err := store.Set("nvidia-graphics-drivers-570", "noble", " blocked on LP#2012345 ", "jdoe", now); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+	if _, err := store.Set("nvidia-graphics-drivers-535", "jammy", "waiting for SRU review", "", now); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+
+	reloaded := NewStore(path)
+	if note, ok := reloaded.Get("nvidia-graphics-drivers-570", "noble"); !ok || note.Text != "blocked on LP#2012345" || note.UpdatedBy != "jdoe" {
+		t.Errorf("Get(570, noble) after reload = %+v, %v, expected the replaced note", note, ok)
+	}
+	if list := reloaded.List(); len(list) != 2 || list[0].Package != "nvidia-graphics-drivers-535" {
+		t.Errorf("List() = %+v, expected two notes ordered by package", list)
+	}
+
+	if !store.Remove("nvidia-graphics-drivers-570", "noble") || store.Remove("nvidia-graphics-drivers-570", "noble") {
+		t.Errorf("Remove() should delete the note once")
+	}
+	if _, ok := NewStore(path).Get("nvidia-graphics-drivers-570", "noble"); ok {
+		t.Errorf("Get(570, noble) after removal expected no note")
+	}
+}
@@ -37,7 +37,8 @@ type BinaryPubHistory struct {
 	SourcePackageVersion string `json:"source_package_version"`
 }
 
-// BinaryVersionPerPocket holds binary package versions per pocket and architecture
+// BinaryVersionPerPocket holds binary package versions per pocket and architecture. The
+// UpdatesSecurity versions are the greatest across the published pockets, see PublishedPockets.
 type BinaryVersionPerPocket struct {
 	Amd64UpdatesSecurity version.Version
 	Amd64Proposed        version.Version
@@ -53,6 +54,15 @@ type BinaryVersionPerSeries struct {
 	VersionMap  map[string]*BinaryVersionPerPocket
 }
 
+// SeriesNames returns the series of the version map the dashboard lists, in display order
+func (bvps *BinaryVersionPerSeries) SeriesNames() []string {
+	names := make([]string, 0, len(bvps.VersionMap))
+	for series := range bvps.VersionMap {
+		names = append(names, series)
+	}
+	return DisplaySeries(names)
+}
+
 // SeriesArchFromDistroArchSeriesLink extracts series and architecture from distro_arch_series_link
 func SeriesArchFromDistroArchSeriesLink(s string) (string, string) {
 	parts := strings.Split(strings.TrimRight(s, "/"), "/")
@@ -70,12 +80,16 @@ func GetMaxBinaryVersionsArchive(cfg *config.Config, packageName string) (*Binar
 
 	url := cfg.URLs.Launchpad.GetPublishedBinariesURL(packageName)
 
-	resp, err := utils.HTTPGetWithRetry(url)
+	resp, err := utils.LaunchpadGetWithRetry(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data: %w", err)
+		return nil, fmt.Errorf("failed to fetch binary package history for %s: %w", packageName, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
 	var apiResp BinaryAPIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
 		return nil, fmt.Errorf("failed to decode JSON: %w", err)
@@ -83,6 +97,10 @@ func GetMaxBinaryVersionsArchive(cfg *config.Config, packageName string) (*Binar
 
 	log.Printf("Found %d entries for binary package %s", len(apiResp.Entries), packageName)
 
+	published := make(map[string]bool)
+	for _, pocket := range PublishedPockets() {
+		published[pocket] = true
+	}
 	versionMap := make(map[string]*BinaryVersionPerPocket)
 
 	for _, entry := range apiResp.Entries {
@@ -107,8 +125,8 @@ func GetMaxBinaryVersionsArchive(cfg *config.Config, packageName string) (*Binar
 
 		pocket := versionMap[series]
 
-		switch entry.Pocket {
-		case "Updates", "Security":
+		switch {
+		case published[entry.Pocket]:
 			switch arch {
 			case "amd64":
 				if pocket.Amd64UpdatesSecurity.String() == "" || ver.GreaterThan(pocket.Amd64UpdatesSecurity) {
@@ -123,7 +141,7 @@ func GetMaxBinaryVersionsArchive(cfg *config.Config, packageName string) (*Binar
 					pocket.I386UpdatesSecurity = ver
 				}
 			}
-		case "Proposed":
+		case entry.Pocket == "Proposed":
 			switch arch {
 			case "amd64":
 				if pocket.Amd64Proposed.String() == "" || ver.GreaterThan(pocket.Amd64Proposed) {
@@ -154,7 +172,8 @@ func PrintBinaryVersionMapTable(bvps *BinaryVersionPerSeries) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintln(w, "Series\tAMD64 Updates/Security\tAMD64 Proposed\tARM64 Updates/Security\tARM64 Proposed\tI386 Updates/Security\tI386 Proposed")
 
-	for series, pocket := range bvps.VersionMap {
+	for _, series := range bvps.SeriesNames() {
+		pocket := bvps.VersionMap[series]
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			series,
 			pocket.Amd64UpdatesSecurity.String(),
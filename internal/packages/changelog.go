@@ -0,0 +1,235 @@
+package packages
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/domain"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// ChangelogEntry is the latest entry of a Debian changelog
+type ChangelogEntry = domain.ChangelogEntry
+
+// ReleaseNotes is what a new archive version adds over the version it replaced
+type ReleaseNotes = domain.ReleaseNotes
+
+var (
+	changelogHeaderPattern = regexp.MustCompile(`^(\S+) \(([^)]+)\) ([^;]+);\s*urgency=(\S+)`)
+	launchpadBugsPattern   = regexp.MustCompile(`LP:\s*#\d+(?:\s*,\s*#\d+)*`)
+	bugNumberPattern       = regexp.MustCompile(`#(\d+)`)
+	cvePattern             = regexp.MustCompile(`CVE-\d{4}-\d{4,}`)
+)
+
+// ParseLatestChangelogEntry extracts the first (most recent) entry from a Debian changelog
+func ParseLatestChangelogEntry(text string) (*ChangelogEntry, error) {
+	var entry *ChangelogEntry
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if entry == nil {
+			if m := changelogHeaderPattern.FindStringSubmatch(line); m != nil {
+				entry = &ChangelogEntry{
+					Package:      m[1],
+					Version:      m[2],
+					Distribution: strings.TrimSpace(m[3]),
+					Urgency:      m[4],
+				}
+			}
+			continue
+		}
+
+		// The trailer line " -- Maintainer <email>  Date" ends the entry
+		if strings.HasPrefix(line, " -- ") {
+			trailer := strings.TrimPrefix(line, " -- ")
+			if maintainer, date, found := strings.Cut(trailer, "  "); found {
+				entry.Maintainer = strings.TrimSpace(maintainer)
+				entry.Date = strings.TrimSpace(date)
+			} else {
+				entry.Maintainer = strings.TrimSpace(trailer)
+			}
+			break
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		entry.Changes = append(entry.Changes, strings.TrimRight(line, " \t"))
+	}
+
+	if entry == nil {
+		return nil, fmt.Errorf("no changelog entry found")
+	}
+
+	body := strings.Join(entry.Changes, "\n")
+	entry.Bugs = uniqueStrings(extractBugNumbers(body))
+	entry.CVEs = uniqueStrings(cvePattern.FindAllString(body, -1))
+	return entry, nil
+}
+
+// extractBugNumbers returns Launchpad bug numbers from "LP: #123, #456" references
+func extractBugNumbers(text string) []string {
+	var bugs []string
+	for _, ref := range launchpadBugsPattern.FindAllString(text, -1) {
+		for _, m := range bugNumberPattern.FindAllStringSubmatch(ref, -1) {
+			bugs = append(bugs, m[1])
+		}
+	}
+	return bugs
+}
+
+// changelogBullets joins the change lines of an entry into bullet points, continuation lines
+// included. Nested "-" and "+" items are bullets of their own, and "[ Maintainer ]" headers are
+// dropped.
+func changelogBullets(changes []string) []string {
+	var bullets []string
+	for _, line := range changes {
+		text := strings.TrimSpace(line)
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			continue
+		}
+		if marker, rest, ok := strings.Cut(text, " "); ok && (marker == "*" || marker == "-" || marker == "+") {
+			bullets = append(bullets, strings.TrimSpace(rest))
+			continue
+		}
+		if len(bullets) == 0 {
+			bullets = append(bullets, text)
+			continue
+		}
+		bullets[len(bullets)-1] += " " + text
+	}
+	return bullets
+}
+
+// DiffChangelogEntries returns the bullet points and Launchpad bugs of the latest changelog
+// entry of a new version that the entry of the version it replaced did not have. previous may
+// be nil, in which case the whole entry is new.
+func DiffChangelogEntries(previous, current *ChangelogEntry) *ReleaseNotes {
+	notes := &ReleaseNotes{Version: current.Version, Added: []string{}, Bugs: []string{}}
+	seenBullets := make(map[string]bool)
+	seenBugs := make(map[string]bool)
+	if previous != nil {
+		notes.Previous = previous.Version
+		for _, bullet := range changelogBullets(previous.Changes) {
+			seenBullets[strings.Join(strings.Fields(bullet), " ")] = true
+		}
+		for _, bug := range previous.Bugs {
+			seenBugs[bug] = true
+		}
+	}
+	for _, bullet := range changelogBullets(current.Changes) {
+		if !seenBullets[strings.Join(strings.Fields(bullet), " ")] {
+			notes.Added = append(notes.Added, bullet)
+		}
+	}
+	for _, bug := range current.Bugs {
+		if !seenBugs[bug] {
+			notes.Bugs = append(notes.Bugs, bug)
+		}
+	}
+	return notes
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// changelogCachePath returns the on-disk cache file for a package version
+func changelogCachePath(cacheDir, packageName, pkgVersion string) string {
+	safe := strings.NewReplacer(":", "_", "/", "_", "\\", "_").Replace(packageName + "_" + pkgVersion)
+	return filepath.Join(cacheDir, safe+".json")
+}
+
+// GetChangelogEntry returns the latest changelog entry for a published source version.
+// Changelogs of published versions never change, so entries are cached on disk until the
+// cache janitor evicts them as unused.
+func GetChangelogEntry(cacheDir, packageName, pkgVersion, publicationLink string) (*ChangelogEntry, error) {
+	cachePath := changelogCachePath(cacheDir, packageName, pkgVersion)
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var entry ChangelogEntry
+		if err := json.Unmarshal(data, &entry); err == nil {
+			now := time.Now()
+			os.Chtimes(cachePath, now, now) // Keep entries still shown out of the janitor's eviction
+			return &entry, nil
+		}
+		log.Printf("Warning: Ignoring corrupt changelog cache %s", cachePath)
+	}
+
+	if publicationLink == "" {
+		return nil, fmt.Errorf("no publication link for %s %s", packageName, pkgVersion)
+	}
+
+	changelogURL, err := fetchChangelogURL(publicationLink)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := utils.HTTPGetWithRetry(changelogURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch changelog for %s %s: %w", packageName, pkgVersion, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code fetching changelog: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read changelog: %w", err)
+	}
+
+	entry, err := ParseLatestChangelogEntry(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse changelog for %s %s: %w", packageName, pkgVersion, err)
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		log.Printf("Warning: Failed to create changelog cache directory: %v", err)
+	} else if data, err := json.MarshalIndent(entry, "", "  "); err == nil {
+		if err := os.WriteFile(cachePath, data, 0644); err != nil {
+			log.Printf("Warning: Failed to cache changelog %s: %v", cachePath, err)
+		}
+	}
+
+	return entry, nil
+}
+
+// fetchChangelogURL resolves the changelog file URL of a publication via the changelogUrl operation
+func fetchChangelogURL(publicationLink string) (string, error) {
+	resp, err := utils.LaunchpadGetWithRetry(publicationLink + "?ws.op=changelogUrl")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve changelog URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("unexpected status code resolving changelog URL: %d", resp.StatusCode)
+	}
+
+	var changelogURL string
+	if err := json.NewDecoder(resp.Body).Decode(&changelogURL); err != nil {
+		return "", fmt.Errorf("failed to decode changelog URL: %w", err)
+	}
+	if changelogURL == "" {
+		return "", fmt.Errorf("publication has no changelog")
+	}
+	return changelogURL, nil
+}
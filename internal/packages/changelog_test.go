@@ -0,0 +1,107 @@
+package packages
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const sampleChangelog = `nvidia-graphics-drivers-570 (570.153.02-0ubuntu0.24.04.1) noble; urgency=medium
+
+  * New upstream release (LP: #2112345, #2112346):
+    - Fixed a regression in suspend/resume.
+    - Security fixes for CVE-2025-23244 and CVE-2025-23245.
+  * debian/rules: refresh dkms patches (LP: #2112345).
+
+ -- Kernel Team <kernel-team@lists.ubuntu.com>  Mon, 02 Jun 2025 10:00:00 +0200
+
+nvidia-graphics-drivers-570 (570.133.07-0ubuntu0.24.04.1) noble; urgency=medium
+
+  * New upstream release (LP: #2100000).
+
+ -- Kernel Team <kernel-team@lists.ubuntu.com>  Mon, 07 Apr 2025 10:00:00 +0200
+`
+
+func TestParseLatestChangelogEntry(t *testing.T) {
+	entry, err := ParseLatestChangelogEntry(sampleChangelog)
+	if err != nil {
+		t.Fatalf("ParseLatestChangelogEntry returned error: %v", err)
+	}
+
+	if entry.Version != "570.153.02-0ubuntu0.24.04.1" || entry.Distribution != "noble" || entry.Urgency != "medium" {
+		t.Errorf("header = %s %s %s, expected 570.153.02-0ubuntu0.24.04.1 noble medium", entry.Version, entry.Distribution, entry.Urgency)
+	}
+	if strings.Join(entry.Bugs, ",") != "2112345,2112346" {
+		t.Errorf("Bugs = %v, expected [2112345 2112346]", entry.Bugs)
+	}
+	if strings.Join(entry.CVEs, ",") != "CVE-2025-23244,CVE-2025-23245" {
+		t.Errorf("CVEs = %v, expected [CVE-2025-23244 CVE-2025-23245]", entry.CVEs)
+	}
+	if entry.Maintainer != "Kernel Team <kernel-team@lists.ubuntu.com>" || entry.Date != "Mon, 02 Jun 2025 10:00:00 +0200" {
+		t.Errorf("trailer = %q %q, expected maintainer and date", entry.Maintainer, entry.Date)
+	}
+	if len(entry.Changes) != 4 {
+		t.Errorf("Changes has %d lines, expected 4", len(entry.Changes))
+	}
+
+	if _, err := ParseLatestChangelogEntry("not a changelog"); err == nil {
+		t.Errorf("ParseLatestChangelogEntry(invalid) succeeded, expected error")
+	}
+}
+
+func TestGetChangelogEntryUsesDiskCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	cached := `{"package": "nvidia-graphics-drivers-570", "version": "1:570.1-1", "bugs": ["1"]}`
+	if err := os.WriteFile(changelogCachePath(cacheDir, "nvidia-graphics-drivers-570", "1:570.1-1"), []byte(cached), 0644); err != nil {
+		t.Fatalf("failed to seed cache: %v", err)
+	}
+
+	// No publication link: only the cache can satisfy the lookup
+	entry, err := GetChangelogEntry(cacheDir, "nvidia-graphics-drivers-570", "1:570.1-1", "")
+	if err != nil {
+		t.Fatalf("GetChangelogEntry returned error: %v", err)
+	}
+	if entry.Version != "1:570.1-1" {
+		t.Errorf("GetChangelogEntry().Version = %s, expected 1:570.1-1", entry.Version)
+	}
+}
+
+func TestDiffChangelogEntries(t *testing.T) {
+	previous, err := ParseLatestChangelogEntry(`nvidia-graphics-drivers-570 (570.172.08-0ubuntu0.24.04.1) noble; urgency=medium
+
+  [ Kernel Team ]
+  * New upstream release (LP: #2100000).
+  * debian/rules: refresh dkms patches
+    for linux 6.8.
+
+ -- Kernel Team <kernel-team@lists.ubuntu.com>  Mon, 07 Jul 2025 10:00:00 +0200
+`)
+	if err != nil {
+		t.Fatalf("ParseLatestChangelogEntry returned error: %v", err)
+	}
+	current, err := ParseLatestChangelogEntry(`nvidia-graphics-drivers-570 (570.195.03-0ubuntu0.24.04.1) noble; urgency=medium
+
+  * New upstream release (LP: #2112345):
+    - Fixed a regression in suspend/resume.
+  * debian/rules: refresh dkms patches for
+    linux 6.8.
+
+ -- Kernel Team <kernel-team@lists.ubuntu.com>  Mon, 08 Sep 2025 10:00:00 +0200
+`)
+	if err != nil {
+		t.Fatalf("ParseLatestChangelogEntry returned error: %v", err)
+	}
+
+	notes := DiffChangelogEntries(previous, current)
+	want := "New upstream release (LP: #2112345):|Fixed a regression in suspend/resume."
+	if notes.Version != "570.195.03-0ubuntu0.24.04.1" || notes.Previous != "570.172.08-0ubuntu0.24.04.1" || strings.Join(notes.Added, "|") != want {
+		t.Errorf("DiffChangelogEntries() = %+v, expected the bullets other than the rewrapped dkms one", notes)
+	}
+	if strings.Join(notes.Bugs, ",") != "2112345" {
+		t.Errorf("Bugs = %v, expected [2112345]", notes.Bugs)
+	}
+
+	if notes := DiffChangelogEntries(nil, current); len(notes.Added) != 3 || notes.Previous != "" {
+		t.Errorf("DiffChangelogEntries(nil) = %+v, expected the whole entry", notes)
+	}
+}
@@ -0,0 +1,123 @@
+package packages
+
+import (
+	"strings"
+	"time"
+
+	version "github.com/knqyf263/go-deb-version"
+
+	"nvidia_driver_monitor/internal/domain"
+	"nvidia_driver_monitor/internal/releases"
+)
+
+// Comparison results of an archive version against the upstream version
+const (
+	ComparisonBehind = "behind"
+	ComparisonEqual  = "equal"
+	ComparisonAhead  = "ahead"
+	// ComparisonUnknown is the result when either version cannot be parsed
+	ComparisonUnknown = "unknown"
+)
+
+// VersionComparison is the structured result of comparing one pocket of a series to upstream
+type VersionComparison = domain.VersionComparison
+
+// archiveUpstreamPart returns the upstream portion of a Debian version, dropping epoch and revision
+func archiveUpstreamPart(archiveVersion string) string {
+	if i := strings.Index(archiveVersion, ":"); i >= 0 {
+		archiveVersion = archiveVersion[i+1:]
+	}
+	if i := strings.LastIndex(archiveVersion, "-"); i >= 0 {
+		archiveVersion = archiveVersion[:i]
+	}
+	return archiveVersion
+}
+
+// withEpoch returns an upstream version with its epoch, adding epoch 0 when it has none
+func withEpoch(upstreamVersion string) string {
+	if strings.Contains(upstreamVersion, ":") {
+		return upstreamVersion
+	}
+	return "0:" + upstreamVersion
+}
+
+// withoutEpoch returns an upstream version without its epoch
+func withoutEpoch(upstreamVersion string) string {
+	if i := strings.Index(upstreamVersion, ":"); i >= 0 {
+		return upstreamVersion[i+1:]
+	}
+	return upstreamVersion
+}
+
+// epochUpstreamPart returns the epoch and upstream portion of a Debian version, dropping the revision
+func epochUpstreamPart(archiveVersion string) string {
+	epoch := "0"
+	if i := strings.Index(archiveVersion, ":"); i >= 0 {
+		epoch = archiveVersion[:i]
+	}
+	return epoch + ":" + archiveUpstreamPart(archiveVersion)
+}
+
+// MatchesUpstream reports whether an archive version matches the comparison (upstream or
+// target) version under a comparison policy, see releases.PolicyPrefix
+func MatchesUpstream(archiveVersion, upstreamVersion, policy string) bool {
+	switch policy {
+	case releases.PolicyStrict:
+		return archiveUpstreamPart(archiveVersion) == withoutEpoch(upstreamVersion)
+	case releases.PolicyEpochAware:
+		return epochUpstreamPart(archiveVersion) == withEpoch(upstreamVersion)
+	default:
+		return strings.Contains(archiveVersion, upstreamVersion)
+	}
+}
+
+// CompareToUpstream reports whether an archive version is behind, equal to or ahead of upstream.
+// An archive version containing the upstream version is equal, matching the dashboard colors.
+func CompareToUpstream(archiveVersion, upstreamVersion string) string {
+	return CompareToUpstreamWithPolicy(archiveVersion, upstreamVersion, releases.PolicyPrefix)
+}
+
+// CompareToUpstreamWithPolicy is CompareToUpstream with the equality decided by a comparison
+// policy. The epoch is only taken into account by releases.PolicyEpochAware.
+func CompareToUpstreamWithPolicy(archiveVersion, upstreamVersion, policy string) string {
+	if MatchesUpstream(archiveVersion, upstreamVersion, policy) {
+		return ComparisonEqual
+	}
+
+	archivePart, upstreamPart := archiveUpstreamPart(archiveVersion), withoutEpoch(upstreamVersion)
+	if policy == releases.PolicyEpochAware {
+		archivePart, upstreamPart = epochUpstreamPart(archiveVersion), withEpoch(upstreamVersion)
+	}
+	archive, err := version.NewVersion(archivePart)
+	if err != nil {
+		return ComparisonUnknown
+	}
+	upstream, err := version.NewVersion(upstreamPart)
+	if err != nil {
+		return ComparisonUnknown
+	}
+	if archive.GreaterThan(upstream) {
+		return ComparisonAhead
+	}
+	return ComparisonBehind
+}
+
+// NewVersionComparison compares a pocket's archive version to upstream under a comparison
+// policy; upstreamDate is the YYYY-MM-DD release date of upstreamVersion and may be empty when
+// unknown. The days since upstream are only set for a pocket that is behind.
+func NewVersionComparison(pocket, archiveVersion, upstreamVersion, upstreamDate, policy string, now time.Time) VersionComparison {
+	comparison := VersionComparison{
+		Pocket:          pocket,
+		UpstreamVersion: upstreamVersion,
+		ArchiveVersion:  archiveVersion,
+		Comparison:      CompareToUpstreamWithPolicy(archiveVersion, upstreamVersion, policy),
+	}
+	if comparison.Comparison != ComparisonBehind {
+		return comparison
+	}
+	if released, err := time.Parse("2006-01-02", upstreamDate); err == nil {
+		days := int(now.Sub(released).Hours() / 24)
+		comparison.DeltaDaysSinceUpstream = &days
+	}
+	return comparison
+}
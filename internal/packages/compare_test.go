@@ -0,0 +1,75 @@
+package packages
+
+import (
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/releases"
+)
+
+func TestCompareToUpstream(t *testing.T) {
+	tests := []struct {
+		archive  string
+		upstream string
+		expected string
+	}{
+		{"570.172.08-0ubuntu0.24.04.1", "570.172.08", ComparisonEqual},
+		{"570.153.02-0ubuntu0.24.04.1", "570.172.08", ComparisonBehind},
+		{"570.181-0ubuntu0.24.04.1", "570.172.08", ComparisonAhead},
+		{"1:535.261.03-0ubuntu1", "535.247.01", ComparisonAhead},
+		{"570.9-0ubuntu1", "570.10", ComparisonBehind},
+		{"not a version", "570.172.08", ComparisonUnknown},
+		{"570.172.08-0ubuntu1", "latest", ComparisonUnknown},
+	}
+
+	for _, test := range tests {
+		if result := CompareToUpstream(test.archive, test.upstream); result != test.expected {
+			t.Errorf("CompareToUpstream(%s, %s) = %s, expected %s", test.archive, test.upstream, result, test.expected)
+		}
+	}
+}
+
+func TestComparisonPolicies(t *testing.T) {
+	tests := []struct {
+		archive  string
+		upstream string
+		policy   string
+		expected string
+	}{
+		// Ubuntu-only revisions of the upstream version match the prefix policy only
+		{"570.172.08.1-0ubuntu1", "570.172.08", releases.PolicyPrefix, ComparisonEqual},
+		{"570.172.08.1-0ubuntu1", "570.172.08", releases.PolicyStrict, ComparisonAhead},
+		{"570.172.08-0ubuntu0.24.04.1", "570.172.08", releases.PolicyStrict, ComparisonEqual},
+		{"1:570.172.08-0ubuntu1", "570.172.08", releases.PolicyStrict, ComparisonEqual},
+		// The epoch-aware policy tells epochs apart
+		{"1:570.172.08-0ubuntu1", "570.172.08", releases.PolicyEpochAware, ComparisonAhead},
+		{"1:570.172.08-0ubuntu1", "1:570.172.08", releases.PolicyEpochAware, ComparisonEqual},
+		{"570.195.03-0ubuntu1", "1:570.172.08", releases.PolicyEpochAware, ComparisonBehind},
+		{"570.172.08-0ubuntu1", "570.172.08", releases.PolicyEpochAware, ComparisonEqual},
+	}
+
+	for _, test := range tests {
+		if result := CompareToUpstreamWithPolicy(test.archive, test.upstream, test.policy); result != test.expected {
+			t.Errorf("CompareToUpstreamWithPolicy(%s, %s, %s) = %s, expected %s", test.archive, test.upstream, test.policy, result, test.expected)
+		}
+	}
+}
+
+func TestNewVersionComparisonDeltaDays(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	comparison := NewVersionComparison("proposed", "570.153.02-0ubuntu1", "570.172.08", "2026-10-07", "", now)
+	if comparison.DeltaDaysSinceUpstream == nil || *comparison.DeltaDaysSinceUpstream != 10 {
+		t.Errorf("DeltaDaysSinceUpstream = %v, expected 10", comparison.DeltaDaysSinceUpstream)
+	}
+
+	comparison = NewVersionComparison("proposed", "570.172.08-0ubuntu1", "570.172.08", "2026-10-07", "", now)
+	if comparison.DeltaDaysSinceUpstream != nil {
+		t.Errorf("DeltaDaysSinceUpstream = %d, expected nil for a pocket that is not behind", *comparison.DeltaDaysSinceUpstream)
+	}
+
+	comparison = NewVersionComparison("published", "570.153.02-0ubuntu1", "570.172.08", "", "", now)
+	if comparison.DeltaDaysSinceUpstream != nil {
+		t.Errorf("DeltaDaysSinceUpstream = %d, expected nil for unknown date", *comparison.DeltaDaysSinceUpstream)
+	}
+}
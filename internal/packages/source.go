@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"nvidia_driver_monitor/internal/config"
 	"nvidia_driver_monitor/internal/releases"
@@ -17,9 +18,16 @@ import (
 // Global configuration for packages
 var packagesConfig *config.Config
 
+// sourceVersionMemo shares archive lookups between the dashboard refresh and the LRM DKMS step
+var sourceVersionMemo = utils.NewTTLMemo(2 * time.Minute)
+
 // SetPackagesConfig sets the global configuration for packages
 func SetPackagesConfig(cfg *config.Config) {
 	packagesConfig = cfg
+	if cfg != nil {
+		sourceVersionMemo.SetTTL(cfg.Cache.GetSourceVersionTTL())
+		sourceVersionMemo.SetMaxBackoff(cfg.Cache.GetMaxBackoff())
+	}
 }
 
 // SourceAPIResponse represents the JSON response for source packages
@@ -27,6 +35,8 @@ type SourceAPIResponse struct {
 	Start     int                `json:"start"`
 	TotalSize int                `json:"total_size"`
 	Entries   []SourcePubHistory `json:"entries"`
+	// NextCollectionLink is the URL of the next page, empty on the last page
+	NextCollectionLink string `json:"next_collection_link,omitempty"`
 }
 
 // SourcePubHistory represents a source package publication history entry
@@ -36,26 +46,225 @@ type SourcePubHistory struct {
 	SourcePackageVersion string `json:"source_package_version"`
 	DistroSeriesLink     string `json:"distro_series_link"`
 	DatePublished        string `json:"date_published"`
+	DateSuperseded       string `json:"date_superseded"`
 	Pocket               string `json:"pocket"`
 	Status               string `json:"status"`
 	ComponentName        string `json:"component_name"`
 	SectionName          string `json:"section_name"`
+	SelfLink             string `json:"self_link"`
+	DateRemoved          string `json:"date_removed"`
+	RemovalComment       string `json:"removal_comment"`
+}
+
+// Removal records a source package deleted from a series in which nothing is published anymore
+type Removal struct {
+	Version string    `json:"version"`
+	Date    time.Time `json:"date"` // Zero when Launchpad has not processed the deletion yet
+	Comment string    `json:"comment,omitempty"`
 }
 
 // SourceVersionPerPocket holds the latest version per pocket for a source package
 type SourceVersionPerPocket struct {
 	UpdatesSecurity version.Version
 	// Track individual pockets as well for major breakdowns
-	Release  version.Version
-	Updates  version.Version
-	Security version.Version
-	Proposed version.Version
+	Release   version.Version
+	Updates   version.Version
+	Security  version.Version
+	Proposed  version.Version
+	Backports version.Version
+	// Components maps a pocket to the archive component (main, restricted, ...) of its version
+	Components map[string]string
+	// Sections maps a pocket to the archive section (e.g. "restricted/misc") of its version
+	Sections map[string]string
+}
+
+// PocketComponent returns the archive component of the version published to the named pocket
+func (p *SourceVersionPerPocket) PocketComponent(pocket string) string {
+	return p.Components[pocket]
+}
+
+// PocketSection returns the archive section of the version published to the named pocket
+func (p *SourceVersionPerPocket) PocketSection(pocket string) string {
+	return p.Sections[pocket]
+}
+
+// PublishedComponent returns the component of the given version in the first pocket carrying it
+func (p *SourceVersionPerPocket) PublishedComponent(pockets []string, ver string) string {
+	for _, pocket := range pockets {
+		if p.PocketVersion(pocket).String() == ver {
+			return p.PocketComponent(pocket)
+		}
+	}
+	return ""
+}
+
+// PublishedSection returns the section of the given version in the first pocket carrying it
+func (p *SourceVersionPerPocket) PublishedSection(pockets []string, ver string) string {
+	for _, pocket := range pockets {
+		if p.PocketVersion(pocket).String() == ver {
+			return p.PocketSection(pocket)
+		}
+	}
+	return ""
+}
+
+// PocketVersion returns the latest version published to the named pocket
+func (p *SourceVersionPerPocket) PocketVersion(pocket string) version.Version {
+	switch pocket {
+	case "Release":
+		return p.Release
+	case "Updates":
+		return p.Updates
+	case "Security":
+		return p.Security
+	case "Proposed":
+		return p.Proposed
+	case "Backports":
+		return p.Backports
+	}
+	return version.Version{}
+}
+
+// LatestPublished returns the greatest version among the given pockets.
+// On ties the pocket listed first wins; ok is false if none has a version.
+func (p *SourceVersionPerPocket) LatestPublished(pockets []string) (best version.Version, ok bool) {
+	for _, pocket := range pockets {
+		ver := p.PocketVersion(pocket)
+		if ver.String() == "" {
+			continue
+		}
+		if !ok || ver.GreaterThan(best) {
+			best = ver
+			ok = true
+		}
+	}
+	return best, ok
+}
+
+// PocketMarkers returns markers like " (U/S/R)" showing which pockets carry the given version
+func (p *SourceVersionPerPocket) PocketMarkers(pockets []string, ver string) string {
+	markers := make([]string, len(pockets))
+	for i, pocket := range pockets {
+		markers[i] = "-"
+		if p.PocketVersion(pocket).String() == ver {
+			markers[i] = pocket[:1]
+		}
+	}
+	return " (" + strings.Join(markers, "/") + ")"
+}
+
+// PublishedPockets returns the pockets merged into the published column, in display order
+func PublishedPockets() []string {
+	if packagesConfig != nil {
+		return packagesConfig.Pockets.GetPublished()
+	}
+	return (&config.PocketsConfig{}).GetPublished()
+}
+
+// SeriesOrder returns the configured display order of series, newest first, without retired series
+func SeriesOrder() []string {
+	order := (&config.SeriesConfig{}).GetOrder()
+	if packagesConfig != nil {
+		order = packagesConfig.Series.GetOrder()
+	}
+	return withoutRetired(order)
+}
+
+// withoutRetired drops the series past their EOL and grace period, see releases.Retired
+func withoutRetired(names []string) []string {
+	now := time.Now()
+	kept := make([]string, 0, len(names))
+	for _, series := range names {
+		if !releases.Retired(series, now) {
+			kept = append(kept, series)
+		}
+	}
+	return kept
+}
+
+// SortSeries orders series names by the configured series order
+func SortSeries(names []string) []string {
+	if packagesConfig != nil {
+		return packagesConfig.Series.Sort(names)
+	}
+	return (&config.SeriesConfig{}).Sort(names)
+}
+
+// DisplaySeries returns the series listed by the dashboard and the CLI in display order: those of
+// the configured series order, which is also the allow-list, and the current devel series, which
+// opens before the order is updated. Retired series and series outside the order, such as interim
+// releases, are dropped.
+func DisplaySeries(names []string) []string {
+	cfg := &config.SeriesConfig{}
+	if packagesConfig != nil {
+		cfg = &packagesConfig.Series
+	}
+	allowed := make(map[string]bool)
+	for _, series := range cfg.GetOrder() {
+		allowed[series] = true
+	}
+	if devel := releases.DevelCodename(); devel != "" {
+		allowed[devel] = true
+	}
+	kept := make([]string, 0, len(names))
+	for _, series := range withoutRetired(names) {
+		if allowed[series] {
+			kept = append(kept, series)
+		}
+	}
+	return cfg.Sort(kept)
 }
 
 // SourceVersionPerSeries holds package versions per series
 type SourceVersionPerSeries struct {
 	PackageName string
 	VersionMap  map[string]*SourceVersionPerPocket
+	// PublicationLinks maps a published version to its Launchpad publication self link
+	PublicationLinks map[string]string
+	// StaleSince is set when Launchpad failed and the last good lookup from that time is served
+	StaleSince time.Time
+	// Removals maps series the package was deleted from, and has no published version in, to the latest deletion
+	Removals map[string]Removal
+}
+
+// SeriesNames returns the series of the version map the dashboard lists, in display order
+func (vps *SourceVersionPerSeries) SeriesNames() []string {
+	names := make([]string, 0, len(vps.VersionMap))
+	for series := range vps.VersionMap {
+		names = append(names, series)
+	}
+	return DisplaySeries(names)
+}
+
+// AllSeriesNames returns the series with versions and the series the package was removed from, in display order
+func (vps *SourceVersionPerSeries) AllSeriesNames() []string {
+	names := make([]string, 0, len(vps.VersionMap)+len(vps.Removals))
+	for series := range vps.VersionMap {
+		names = append(names, series)
+	}
+	for series := range vps.Removals {
+		if _, ok := vps.VersionMap[series]; !ok {
+			names = append(names, series)
+		}
+	}
+	return DisplaySeries(names)
+}
+
+// recordRemoval keeps the latest deletion of a package per series
+func recordRemoval(removals map[string]Removal, entry SourcePubHistory) {
+	series := SeriesFromDistroSeriesLink(entry.DistroSeriesLink)
+	if series == "" {
+		return
+	}
+	removal := Removal{Version: entry.SourcePackageVersion, Comment: entry.RemovalComment}
+	if entry.DateRemoved != "" {
+		if date, err := time.Parse(time.RFC3339Nano, entry.DateRemoved); err == nil {
+			removal.Date = date
+		}
+	}
+	if current, ok := removals[series]; !ok || removal.Date.After(current.Date) {
+		removals[series] = removal
+	}
 }
 
 // SeriesFromDistroSeriesLink extracts series from distro_series_link
@@ -77,7 +286,7 @@ func GetMaxSourceVersionsArchive(cfg *config.Config, packageName string) (*Sourc
 
 	fmt.Println("Query:", url)
 
-	resp, err := utils.HTTPGetWithRetry(url)
+	resp, err := utils.LaunchpadGetWithRetry(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch source package history for %s: %w", packageName, err)
 	}
@@ -95,11 +304,19 @@ func GetMaxSourceVersionsArchive(cfg *config.Config, packageName string) (*Sourc
 	log.Printf("📦 Found %d source publications:\n\n", apiResp.TotalSize)
 
 	versionMap := make(map[string]*SourceVersionPerPocket)
+	publicationLinks := make(map[string]string)
+	removals := make(map[string]Removal)
 
 	for _, entry := range apiResp.Entries {
+		if entry.Status == "Deleted" {
+			recordRemoval(removals, entry)
+		}
 		if entry.Status != "Published" {
 			continue
 		}
+		if _, exists := publicationLinks[entry.SourcePackageVersion]; !exists && entry.SelfLink != "" {
+			publicationLinks[entry.SourcePackageVersion] = entry.SelfLink
+		}
 
 		log.Printf("📦 %s\n", entry.DisplayName)
 		log.Printf("  → Version:     %s\n", entry.SourcePackageVersion)
@@ -130,17 +347,24 @@ func GetMaxSourceVersionsArchive(cfg *config.Config, packageName string) (*Sourc
 			versionMap[series].Updates = emptyVersion
 			versionMap[series].Security = emptyVersion
 			versionMap[series].Proposed = emptyVersion
+			versionMap[series].Backports = emptyVersion
+			versionMap[series].Components = make(map[string]string)
+			versionMap[series].Sections = make(map[string]string)
 		}
 
 		switch entry.Pocket {
 		case "Proposed":
 			if ver.GreaterThan(versionMap[series].Proposed) {
 				versionMap[series].Proposed = ver
+				versionMap[series].Components["Proposed"] = entry.ComponentName
+				versionMap[series].Sections["Proposed"] = entry.SectionName
 			}
 		case "Updates":
 			// Track Updates individually and merged Updates/Security
 			if ver.GreaterThan(versionMap[series].Updates) {
 				versionMap[series].Updates = ver
+				versionMap[series].Components["Updates"] = entry.ComponentName
+				versionMap[series].Sections["Updates"] = entry.SectionName
 			}
 			if ver.GreaterThan(versionMap[series].UpdatesSecurity) {
 				versionMap[series].UpdatesSecurity = ver
@@ -149,6 +373,8 @@ func GetMaxSourceVersionsArchive(cfg *config.Config, packageName string) (*Sourc
 			// Track Security individually and merged Updates/Security
 			if ver.GreaterThan(versionMap[series].Security) {
 				versionMap[series].Security = ver
+				versionMap[series].Components["Security"] = entry.ComponentName
+				versionMap[series].Sections["Security"] = entry.SectionName
 			}
 			if ver.GreaterThan(versionMap[series].UpdatesSecurity) {
 				versionMap[series].UpdatesSecurity = ver
@@ -156,18 +382,76 @@ func GetMaxSourceVersionsArchive(cfg *config.Config, packageName string) (*Sourc
 		case "Release":
 			if ver.GreaterThan(versionMap[series].Release) {
 				versionMap[series].Release = ver
+				versionMap[series].Components["Release"] = entry.ComponentName
+				versionMap[series].Sections["Release"] = entry.SectionName
+			}
+		case "Backports":
+			if ver.GreaterThan(versionMap[series].Backports) {
+				versionMap[series].Backports = ver
+				versionMap[series].Components["Backports"] = entry.ComponentName
+				versionMap[series].Sections["Backports"] = entry.SectionName
 			}
 		default:
 			// ignore
 		}
 	}
 
+	// Deletions only matter for series where nothing else is published
+	for series := range versionMap {
+		delete(removals, series)
+	}
+
 	return &SourceVersionPerSeries{
-		PackageName: packageName,
-		VersionMap:  versionMap,
+		PackageName:      packageName,
+		VersionMap:       versionMap,
+		PublicationLinks: publicationLinks,
+		Removals:         removals,
 	}, nil
 }
 
+// GetMaxSourceVersionsCached is GetMaxSourceVersionsArchive with results memoized per query URL,
+// so each Launchpad lookup happens at most once per TTL across all callers. While Launchpad
+// fails, the last good result is returned with StaleSince set.
+func GetMaxSourceVersionsCached(cfg *config.Config, packageName string) (*SourceVersionPerSeries, error) {
+	if packageName == "" {
+		return nil, fmt.Errorf("package name cannot be empty")
+	}
+
+	key := cfg.URLs.Launchpad.GetPublishedSourcesURL(packageName)
+	value, staleSince, err := sourceVersionMemo.GetStale(key, func() (interface{}, error) {
+		return GetMaxSourceVersionsArchive(cfg, packageName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if staleSince.IsZero() {
+		return value.(*SourceVersionPerSeries), nil
+	}
+	log.Printf("Warning: Serving source versions of %s from %s while Launchpad is unavailable", packageName, staleSince.Format(time.RFC3339))
+	stale := *value.(*SourceVersionPerSeries)
+	stale.StaleSince = staleSince
+	return &stale, nil
+}
+
+// RefreshSourceVersions looks up the source versions of a package in Launchpad now, replacing
+// the memoized result. On failure the memoized result is kept.
+func RefreshSourceVersions(cfg *config.Config, packageName string) error {
+	if packageName == "" {
+		return fmt.Errorf("package name cannot be empty")
+	}
+
+	key := cfg.URLs.Launchpad.GetPublishedSourcesURL(packageName)
+	_, err := sourceVersionMemo.Refresh(key, func() (interface{}, error) {
+		return GetMaxSourceVersionsArchive(cfg, packageName)
+	})
+	return err
+}
+
+// ClearSourceVersionCache drops all memoized archive lookups
+func ClearSourceVersionCache() {
+	sourceVersionMemo.Clear()
+}
+
 // getMaxSourceVersionsArchive is a wrapper function for backward compatibility
 func getMaxSourceVersionsArchive(packageName string) (*SourceVersionPerSeries, error) {
 	// Use global config if available, otherwise create a default one
@@ -178,23 +462,30 @@ func getMaxSourceVersionsArchive(packageName string) (*SourceVersionPerSeries, e
 	return GetMaxSourceVersionsArchive(cfg, packageName)
 }
 
+// publishedColumnLabel returns the console column header for the published pockets
+func publishedColumnLabel(pockets []string) string {
+	return strings.ToLower(strings.Join(pockets, "_"))
+}
+
 // PrintSourceVersionMapTable prints the source version map in table format
 func PrintSourceVersionMapTable(vps *SourceVersionPerSeries) {
+	pockets := PublishedPockets()
 	fmt.Printf("Source Package: %s\n", vps.PackageName)
 	fmt.Printf(
 		"| %-30s | %-42s | %-42s |\n",
 		"Series",
-		"updates_security",
+		publishedColumnLabel(pockets),
 		"proposed",
 	)
 	fmt.Println("|--------------------------------|--------------------------------------------|--------------------------------------------|")
 
-	for series, pocket := range vps.VersionMap {
+	for _, series := range vps.SeriesNames() {
+		pocket := vps.VersionMap[series]
 		updates := "-"
 		proposed := "-"
 		if pocket != nil {
-			if pocket.UpdatesSecurity.String() != "" {
-				updates = pocket.UpdatesSecurity.String()
+			if best, ok := pocket.LatestPublished(pockets); ok {
+				updates = best.String()
 			}
 			if pocket.Proposed.String() != "" {
 				proposed = pocket.Proposed.String()
@@ -211,11 +502,12 @@ func PrintSourceVersionMapTable(vps *SourceVersionPerSeries) {
 
 // PrintSourceVersionMapTableWithSupported prints source version map with supported releases and SRU cycles
 func PrintSourceVersionMapTableWithSupported(vps *SourceVersionPerSeries, supportedReleases []releases.SupportedRelease, sruCycles *sru.SRUCycles) {
+	pockets := PublishedPockets()
 	fmt.Printf("Source Package: %s\n", vps.PackageName)
 	fmt.Printf(
 		"| %-30s | %-42s | %-42s | %-20s | %-15s | %-15s |\n",
 		"Series",
-		"updates_security",
+		publishedColumnLabel(pockets),
 		"proposed",
 		"Upstream Version",
 		"Release Date",
@@ -254,13 +546,8 @@ func PrintSourceVersionMapTableWithSupported(vps *SourceVersionPerSeries, suppor
 
 	supported, found := supportedMap[branchName]
 
-	orderedSeries := []string{"resolute", "noble", "jammy", "focal", "bionic"} // Specify the desired order of series
-
-	for _, series := range orderedSeries {
-		pocket, exists := vps.VersionMap[series]
-		if !exists {
-			continue // Skip series that don't exist in the version map
-		}
+	for _, series := range vps.SeriesNames() {
+		pocket := vps.VersionMap[series]
 		updates := "-"
 		proposed := "-"
 		updatesColor := ColorReset
@@ -276,11 +563,16 @@ func PrintSourceVersionMapTableWithSupported(vps *SourceVersionPerSeries, suppor
 			}
 		}
 
-		if pocket != nil && pocket.UpdatesSecurity.String() != "" {
-			updates = pocket.UpdatesSecurity.String()
+		var best version.Version
+		hasBest := false
+		if pocket != nil {
+			best, hasBest = pocket.LatestPublished(pockets)
+		}
+		if hasBest {
+			updates = best.String()
 			if found && supported.CurrentUpstreamVersion != "" {
-				// Check if the upstream version is contained in the package version
-				if strings.Contains(updates, supported.CurrentUpstreamVersion) {
+				// Check if the package version matches the upstream version
+				if MatchesUpstream(updates, supported.CurrentUpstreamVersion, supported.GetComparisonPolicy()) {
 					updatesColor = ColorGreen
 				} else {
 					updatesColor = ColorRed
@@ -297,8 +589,8 @@ func PrintSourceVersionMapTableWithSupported(vps *SourceVersionPerSeries, suppor
 		if pocket != nil && pocket.Proposed.String() != "" {
 			proposed = pocket.Proposed.String()
 			if found && supported.CurrentUpstreamVersion != "" {
-				// Check if the upstream version is contained in the package version
-				if strings.Contains(proposed, supported.CurrentUpstreamVersion) {
+				// Check if the package version matches the upstream version
+				if MatchesUpstream(proposed, supported.CurrentUpstreamVersion, supported.GetComparisonPolicy()) {
 					proposedColor = ColorGreen
 				} else {
 					proposedColor = ColorRed
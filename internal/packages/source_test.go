@@ -0,0 +1,135 @@
+package packages
+
+import (
+	"strings"
+	"testing"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/releases"
+
+	version "github.com/knqyf263/go-deb-version"
+)
+
+func mustVersion(t *testing.T, v string) version.Version {
+	t.Helper()
+	ver, err := version.NewVersion(v)
+	if err != nil {
+		t.Fatalf("NewVersion(%s) returned error: %v", v, err)
+	}
+	return ver
+}
+
+func TestLatestPublished(t *testing.T) {
+	pocket := &SourceVersionPerPocket{
+		Release:   mustVersion(t, "570.86.15-0ubuntu1"),
+		Updates:   mustVersion(t, "570.153.02-0ubuntu0.24.04.1"),
+		Security:  mustVersion(t, "570.133.07-0ubuntu0.24.04.1"),
+		Backports: mustVersion(t, "570.169-0ubuntu0.24.04.1"),
+	}
+
+	tests := []struct {
+		pockets  []string
+		expected string
+		markers  string
+	}{
+		{[]string{"Updates", "Security", "Release"}, "570.153.02-0ubuntu0.24.04.1", " (U/-/-)"},
+		{[]string{"Release"}, "570.86.15-0ubuntu1", " (R)"},
+		{[]string{"Release", "Backports"}, "570.169-0ubuntu0.24.04.1", " (-/B)"},
+	}
+
+	for _, test := range tests {
+		best, ok := pocket.LatestPublished(test.pockets)
+		if !ok || best.String() != test.expected {
+			t.Errorf("LatestPublished(%v) = %s, expected %s", test.pockets, best.String(), test.expected)
+		}
+		if markers := pocket.PocketMarkers(test.pockets, best.String()); markers != test.markers {
+			t.Errorf("PocketMarkers(%v) = %s, expected %s", test.pockets, markers, test.markers)
+		}
+	}
+
+	if _, ok := (&SourceVersionPerPocket{}).LatestPublished([]string{"Updates"}); ok {
+		t.Errorf("LatestPublished on empty pockets returned ok, expected none")
+	}
+}
+
+func TestSortSeries(t *testing.T) {
+	defer SetPackagesConfig(nil)
+
+	tests := []struct {
+		order    []string
+		names    []string
+		expected string
+	}{
+		{nil, []string{"bionic", "noble", "jammy", "resolute", "focal"}, "resolute,noble,jammy,focal,bionic"},
+		{[]string{"jammy", "noble"}, []string{"noble", "focal", "jammy"}, "jammy,noble,focal"},
+	}
+
+	for _, test := range tests {
+		cfg := config.DefaultConfig()
+		if test.order != nil {
+			cfg.Series.Order = test.order
+		}
+		SetPackagesConfig(cfg)
+		if sorted := strings.Join(SortSeries(test.names), ","); sorted != test.expected {
+			t.Errorf("SortSeries(%v) = %s, expected %s", test.names, sorted, test.expected)
+		}
+	}
+}
+
+func TestDisplaySeriesDropsUnknownSeries(t *testing.T) {
+	defer SetPackagesConfig(nil)
+	defer releases.SetDevelCodename("")
+
+	SetPackagesConfig(config.DefaultConfig())
+	if shown := strings.Join(DisplaySeries([]string{"plucky", "noble", "oracular", "jammy"}), ","); shown != "noble,jammy" {
+		t.Errorf("DisplaySeries() = %s, expected the interim releases dropped", shown)
+	}
+	vps := &SourceVersionPerSeries{
+		VersionMap: map[string]*SourceVersionPerPocket{"noble": {}, "oracular": {}},
+		Removals:   map[string]Removal{"plucky": {}, "jammy": {}},
+	}
+	if names := strings.Join(vps.AllSeriesNames(), ","); names != "noble,jammy" {
+		t.Errorf("AllSeriesNames() = %s, expected series outside the order dropped", names)
+	}
+
+	// A devel series opening before the order lists it is still shown
+	releases.SetDevelCodename("stonking")
+	if shown := strings.Join(DisplaySeries([]string{"noble", "stonking"}), ","); shown != "noble,stonking" {
+		t.Errorf("DisplaySeries() = %s, expected the devel series kept", shown)
+	}
+}
+
+func TestSeriesNamesDeterministic(t *testing.T) {
+	vps := &SourceVersionPerSeries{VersionMap: map[string]*SourceVersionPerPocket{
+		"focal": {}, "noble": {}, "jammy": {}, "resolute": {},
+	}}
+	expected := "resolute,noble,jammy,focal"
+	for i := 0; i < 10; i++ {
+		if names := strings.Join(vps.SeriesNames(), ","); names != expected {
+			t.Fatalf("SeriesNames() = %s, expected %s", names, expected)
+		}
+	}
+}
+
+func TestRecordRemovalKeepsLatest(t *testing.T) {
+	removals := make(map[string]Removal)
+	for _, entry := range []SourcePubHistory{
+		{DistroSeriesLink: "https://api.launchpad.net/devel/ubuntu/noble", SourcePackageVersion: "390.1", DateRemoved: "2024-03-01T10:00:00.000000+00:00"},
+		{DistroSeriesLink: "https://api.launchpad.net/devel/ubuntu/noble", SourcePackageVersion: "390.2", DateRemoved: "2024-05-01T10:00:00.000000+00:00", RemovalComment: "EOL"},
+		{DistroSeriesLink: "https://api.launchpad.net/devel/ubuntu/noble", SourcePackageVersion: "390.0"},
+	} {
+		recordRemoval(removals, entry)
+	}
+	removal := removals["noble"]
+	if removal.Version != "390.2" || removal.Comment != "EOL" || removal.Date.Format("2006-01-02") != "2024-05-01" {
+		t.Errorf("recordRemoval() kept %+v, expected the 2024-05-01 removal of 390.2", removal)
+	}
+
+	vps := &SourceVersionPerSeries{
+		VersionMap: map[string]*SourceVersionPerPocket{"jammy": {}},
+		Removals:   removals,
+	}
+	if names := strings.Join(vps.AllSeriesNames(), ","); names != "noble,jammy" {
+		t.Errorf("AllSeriesNames() = %s, expected noble,jammy", names)
+	}
+}
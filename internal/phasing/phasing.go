@@ -0,0 +1,153 @@
+// Package phasing reads the phased update percentage of the driver binaries published to
+// -updates. A phased update only reaches a share of the users until it is fully phased, so a
+// published driver is not necessarily installed yet.
+package phasing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/utils"
+
+	version "github.com/knqyf263/go-deb-version"
+)
+
+// sourcePrefix and binaryPrefix name the driver sources and the metapackages users install
+const (
+	sourcePrefix = "nvidia-graphics-drivers-"
+	binaryPrefix = "nvidia-driver-"
+)
+
+// phasingArch is the architecture whose publication is read; phasing is set per source
+// upload, so every architecture has the same percentage
+const phasingArch = "amd64"
+
+// Phase is a binary publication of -updates that is still being phased
+type Phase struct {
+	Binary        string    `json:"binary"`
+	Version       string    `json:"version"`
+	Series        string    `json:"series"`
+	Percentage    int       `json:"percentage"` // Share of users offered the update; 0 while phasing is halted
+	DatePublished time.Time `json:"date_published"`
+}
+
+// Label returns the percentage as shown on the dashboard, e.g. "40%"
+func (p *Phase) Label() string {
+	return fmt.Sprintf("%d%%", p.Percentage)
+}
+
+// binaryPublication is the part of a Launchpad binary publication that phasing needs
+type binaryPublication struct {
+	BinaryPackageVersion   string    `json:"binary_package_version"`
+	ArchitectureSeries     string    `json:"distro_arch_series_link"`
+	Pocket                 string    `json:"pocket"`
+	Status                 string    `json:"status"`
+	DatePublished          time.Time `json:"date_published"`
+	PhasedUpdatePercentage *int      `json:"phased_update_percentage"` // null once fully phased
+}
+
+var (
+	phasingConfig *config.Config
+	phasingMemo   = utils.NewTTLMemo(2 * time.Minute)
+)
+
+// SetPhasingConfig sets the configuration for phasing queries
+func SetPhasingConfig(cfg *config.Config) {
+	phasingConfig = cfg
+	if cfg != nil {
+		phasingMemo.SetTTL(cfg.Cache.GetSourceVersionTTL())
+		phasingMemo.SetMaxBackoff(cfg.Cache.GetMaxBackoff())
+	}
+}
+
+// Enabled reports whether phasing queries are turned on
+func Enabled() bool {
+	return phasingConfig != nil && phasingConfig.Phasing.Enabled
+}
+
+// BinaryName returns the driver metapackage built from a source package, e.g. nvidia-driver-570
+// for nvidia-graphics-drivers-570, or "" for sources that do not build one
+func BinaryName(sourcePackage string) string {
+	if !strings.HasPrefix(sourcePackage, sourcePrefix) {
+		return ""
+	}
+	return binaryPrefix + strings.TrimPrefix(sourcePackage, sourcePrefix)
+}
+
+// ParsePhases decodes a Launchpad binary publication collection and returns, per series, the
+// greatest published -updates version of the binary while it is still being phased
+func ParsePhases(data []byte, binary string) (map[string]Phase, error) {
+	var resp struct {
+		Entries []binaryPublication `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("JSON decode error: %v", err)
+	}
+
+	latest := make(map[string]version.Version)
+	phases := make(map[string]Phase)
+	for _, entry := range resp.Entries {
+		series, arch := packages.SeriesArchFromDistroArchSeriesLink(entry.ArchitectureSeries)
+		if entry.Status != "Published" || entry.Pocket != "Updates" || arch != phasingArch {
+			continue
+		}
+		ver, err := version.NewVersion(entry.BinaryPackageVersion)
+		if err != nil {
+			continue
+		}
+		if previous, ok := latest[series]; ok && !ver.GreaterThan(previous) {
+			continue
+		}
+		latest[series] = ver
+		delete(phases, series) // A newer upload replaces the phasing of an older one
+		if entry.PhasedUpdatePercentage == nil || *entry.PhasedUpdatePercentage >= 100 {
+			continue
+		}
+		phases[series] = Phase{
+			Binary:        binary,
+			Version:       entry.BinaryPackageVersion,
+			Series:        series,
+			Percentage:    *entry.PhasedUpdatePercentage,
+			DatePublished: entry.DatePublished,
+		}
+	}
+	return phases, nil
+}
+
+// FetchPhases returns the phasing of a binary per series; series without an entry are fully
+// phased or have nothing in -updates
+func FetchPhases(binary string) (map[string]Phase, error) {
+	urls := config.DefaultConfig().URLs
+	if phasingConfig != nil {
+		urls = phasingConfig.GetEffectiveURLs()
+	}
+	queryURL := urls.Launchpad.GetPublishedBinariesURL(binary) + "&status=Published&pocket=Updates"
+
+	// Phasing badges keep showing the last good lookup while Launchpad is unavailable
+	value, _, err := phasingMemo.GetStale(queryURL, func() (interface{}, error) {
+		resp, err := utils.HTTPGetWithRetry(queryURL)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("HTTP error: %d", resp.StatusCode)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read binary publications: %v", err)
+		}
+		return ParsePhases(body, binary)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the phasing of %s: %v", binary, err)
+	}
+	return value.(map[string]Phase), nil
+}
@@ -0,0 +1,58 @@
+package phasing
+
+import "testing"
+
+func TestParsePhases(t *testing.T) {
+	data := []byte(`{"entries": [
+		{"binary_package_version": "570.181-0ubuntu0.24.04.1", "pocket": "Updates", "status": "Published",
+		 "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/noble/amd64",
+		 "date_published": "2026-09-01T10:00:00+00:00", "phased_update_percentage": null},
+		{"binary_package_version": "570.195.03-0ubuntu0.24.04.1", "pocket": "Updates", "status": "Published",
+		 "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/noble/amd64",
+		 "date_published": "2026-10-14T10:00:00+00:00", "phased_update_percentage": 40},
+		{"binary_package_version": "570.195.03-0ubuntu0.24.04.1", "pocket": "Updates", "status": "Published",
+		 "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/noble/arm64",
+		 "date_published": "2026-10-14T10:00:00+00:00", "phased_update_percentage": 40},
+		{"binary_package_version": "570.195.03-0ubuntu0.22.04.1", "pocket": "Updates", "status": "Published",
+		 "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/jammy/amd64",
+		 "date_published": "2026-10-14T10:00:00+00:00", "phased_update_percentage": 0},
+		{"binary_package_version": "570.200-0ubuntu0.22.04.1", "pocket": "Proposed", "status": "Published",
+		 "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/jammy/amd64",
+		 "date_published": "2026-10-16T10:00:00+00:00", "phased_update_percentage": 10},
+		{"binary_package_version": "570.195.03-0ubuntu0.20.04.1", "pocket": "Updates", "status": "Published",
+		 "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/focal/amd64",
+		 "date_published": "2026-10-01T10:00:00+00:00", "phased_update_percentage": 20},
+		{"binary_package_version": "570.200-0ubuntu0.20.04.1", "pocket": "Updates", "status": "Published",
+		 "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/focal/amd64",
+		 "date_published": "2026-10-15T10:00:00+00:00", "phased_update_percentage": null}
+	]}`)
+
+	phases, err := ParsePhases(data, "nvidia-driver-570")
+	if err != nil {
+		t.Fatalf("ParsePhases returned error: %v", err)
+	}
+	if len(phases) != 2 {
+		t.Fatalf("ParsePhases returned %d phases, expected noble and jammy: %+v", len(phases), phases)
+	}
+	if noble := phases["noble"]; noble.Version != "570.195.03-0ubuntu0.24.04.1" || noble.Label() != "40%" {
+		t.Errorf("noble phase = %+v, expected the newest upload at 40%%", noble)
+	}
+	if jammy := phases["jammy"]; jammy.Label() != "0%" {
+		t.Errorf("jammy phase = %+v, expected halted phasing; proposed is not phased", jammy)
+	}
+	if _, ok := phases["focal"]; ok {
+		t.Errorf("focal should be fully phased once a newer upload has no percentage")
+	}
+}
+
+func TestBinaryName(t *testing.T) {
+	for source, expected := range map[string]string{
+		"nvidia-graphics-drivers-570":        "nvidia-driver-570",
+		"nvidia-graphics-drivers-535-server": "nvidia-driver-535-server",
+		"linux-restricted-modules":           "",
+	} {
+		if got := BinaryName(source); got != expected {
+			t.Errorf("BinaryName(%q) = %q, expected %q", source, got, expected)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package problems writes the error responses of the API as RFC 7807 problem details
+// (application/problem+json), whose type is a stable category clients can branch on instead of
+// parsing the message.
+package problems
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ContentType is the media type of problem details
+const ContentType = "application/problem+json"
+
+// Problem types. They are part of the API: add new ones rather than renaming these.
+const (
+	CacheWarming        = "cache_warming"        // The data is not loaded yet; retry shortly
+	PackageNotFound     = "package_not_found"    // No such package (and series) on the dashboard
+	UpstreamUnavailable = "upstream_unavailable" // An upstream source could not be read
+	ValidationFailed    = "validation_failed"    // The request parameters or body are invalid
+	NotFound            = "not_found"            // Any other missing resource
+	NotConfigured       = "not_configured"       // The feature is disabled or its data is missing
+	MethodNotAllowed    = "method_not_allowed"
+	Unauthorized        = "unauthorized"
+	Forbidden           = "forbidden"
+	RateLimited         = "rate_limited"
+	Internal            = "internal_error"
+)
+
+var titles = map[string]string{
+	CacheWarming:        "The service is still loading its data",
+	PackageNotFound:     "Package not found",
+	UpstreamUnavailable: "An upstream source is unavailable",
+	ValidationFailed:    "The request is invalid",
+	NotFound:            "Not found",
+	NotConfigured:       "The feature is not configured",
+	MethodNotAllowed:    "Method not allowed",
+	Unauthorized:        "Authentication required",
+	Forbidden:           "Forbidden",
+	RateLimited:         "Too many requests",
+	Internal:            "Internal error",
+}
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Error repeats detail for the clients of the former {"error": "..."} responses
+	Error string `json:"error,omitempty"`
+	// Extensions are extra members merged into the object, e.g. the rows of a rejected file
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// New returns the problem of a type with a human readable detail
+func New(status int, problemType, detail string) *Problem {
+	return &Problem{Type: problemType, Title: titles[problemType], Status: status, Detail: detail, Error: detail}
+}
+
+// MarshalJSON adds the extension members to the standard ones
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	standard, err := json.Marshal((*plain)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return standard, err
+	}
+	members := make(map[string]interface{}, len(p.Extensions)+6)
+	for name, value := range p.Extensions {
+		members[name] = value
+	}
+	if err := json.Unmarshal(standard, &members); err != nil {
+		return nil, err
+	}
+	return json.Marshal(members)
+}
+
+// Write sends a problem as the response
+func (p *Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Write sends a problem of a type with a human readable detail as the response
+func Write(w http.ResponseWriter, status int, problemType, detail string) {
+	New(status, problemType, detail).Write(w)
+}
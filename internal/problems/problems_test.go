@@ -0,0 +1,45 @@
+package problems
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "application/json")
+	Write(w, http.StatusNotFound, PackageNotFound, "Package not found")
+
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != ContentType {
+		t.Errorf("Write() = %d %s, expected 404 as problem+json", w.Code, w.Header().Get("Content-Type"))
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid body %s: %v", w.Body, err)
+	}
+	want := map[string]interface{}{
+		"type": PackageNotFound, "title": "Package not found", "status": float64(404),
+		"detail": "Package not found", "error": "Package not found",
+	}
+	for member, value := range want {
+		if body[member] != value {
+			t.Errorf("%s = %v, expected %v", member, body[member], value)
+		}
+	}
+}
+
+func TestExtensionsDoNotReplaceStandardMembers(t *testing.T) {
+	problem := New(http.StatusBadRequest, ValidationFailed, "Invalid CSV")
+	problem.Extensions = map[string]interface{}{"problems": []string{"line 2: unknown series"}, "status": "ignored"}
+	data, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]interface{}
+	json.Unmarshal(data, &body)
+	if body["status"] != float64(400) || body["problems"] == nil {
+		t.Errorf("body = %s, expected the status kept and the problems added", data)
+	}
+}
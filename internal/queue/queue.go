@@ -0,0 +1,153 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Upload queue statuses that are tracked; accepted and rejected uploads are ignored
+const (
+	StatusUnapproved = "Unapproved"
+	StatusNew        = "New"
+)
+
+// trackedStatuses are queried in this order
+var trackedStatuses = []string{StatusUnapproved, StatusNew}
+
+// Upload is a package upload waiting in a Launchpad upload queue
+type Upload struct {
+	Package     string    `json:"package"`
+	Version     string    `json:"version"`
+	Series      string    `json:"series"`
+	Pocket      string    `json:"pocket"`
+	Status      string    `json:"status"`
+	DateCreated time.Time `json:"date_created"`
+}
+
+// Label returns a short description such as "in unapproved since 2026-10-01"
+func (u *Upload) Label() string {
+	queueName := strings.ToLower(u.Status)
+	if u.Status == StatusNew {
+		queueName = "NEW"
+	}
+	return fmt.Sprintf("in %s since %s", queueName, u.DateCreated.Format("2006-01-02"))
+}
+
+// launchpadUpload represents a package upload entry from the Launchpad API
+type launchpadUpload struct {
+	PackageName    string    `json:"package_name"`
+	PackageVersion string    `json:"package_version"`
+	DisplayName    string    `json:"display_name"`
+	DisplayVersion string    `json:"display_version"`
+	Pocket         string    `json:"pocket"`
+	Status         string    `json:"status"`
+	DateCreated    time.Time `json:"date_created"`
+}
+
+// launchpadUploadsResponse represents a collection of package uploads from the Launchpad API
+type launchpadUploadsResponse struct {
+	Entries []launchpadUpload `json:"entries"`
+}
+
+var (
+	queueConfig *config.Config
+	uploadsMemo = utils.NewTTLMemo(2 * time.Minute)
+)
+
+// SetQueueConfig sets the configuration for upload queue queries
+func SetQueueConfig(cfg *config.Config) {
+	queueConfig = cfg
+	if cfg != nil {
+		uploadsMemo.SetTTL(cfg.Cache.GetSourceVersionTTL())
+		uploadsMemo.SetMaxBackoff(cfg.Cache.GetMaxBackoff())
+	}
+}
+
+// Enabled reports whether upload queue queries are turned on
+func Enabled() bool {
+	return queueConfig != nil && queueConfig.Queue.Enabled
+}
+
+// getSeriesURL returns the Launchpad API URL of an Ubuntu series
+func getSeriesURL(series string) string {
+	if queueConfig != nil {
+		effectiveURLs := queueConfig.GetEffectiveURLs()
+		return effectiveURLs.Launchpad.GetUbuntuSeriesURL(series)
+	}
+	return "https://api.launchpad.net/devel/ubuntu/" + series // fallback
+}
+
+// ParseUploads decodes a Launchpad package upload collection, keeping only queued uploads of the package
+func ParseUploads(data []byte, packageName, series string) ([]Upload, error) {
+	var resp launchpadUploadsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("JSON decode error: %v", err)
+	}
+
+	var uploads []Upload
+	for _, entry := range resp.Entries {
+		if entry.Status != StatusUnapproved && entry.Status != StatusNew {
+			continue
+		}
+		name, version := entry.PackageName, entry.PackageVersion
+		if name == "" {
+			name, version = entry.DisplayName, entry.DisplayVersion
+		}
+		if name != packageName {
+			continue
+		}
+		uploads = append(uploads, Upload{
+			Package:     name,
+			Version:     version,
+			Series:      series,
+			Pocket:      entry.Pocket,
+			Status:      entry.Status,
+			DateCreated: entry.DateCreated,
+		})
+	}
+	return uploads, nil
+}
+
+// FetchUploads returns the uploads of a package waiting in the unapproved and NEW queues of a series,
+// oldest first
+func FetchUploads(series, packageName string) ([]Upload, error) {
+	var uploads []Upload
+	for _, status := range trackedStatuses {
+		queryURL := fmt.Sprintf("%s?ws.op=getPackageUploads&exact_match=true&name=%s&status=%s",
+			getSeriesURL(series), url.QueryEscape(packageName), status)
+
+		// Queue badges keep showing the last good lookup while Launchpad is unavailable
+		value, _, err := uploadsMemo.GetStale(queryURL, func() (interface{}, error) {
+			resp, err := utils.HTTPGetWithRetry(queryURL)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return nil, fmt.Errorf("HTTP error: %d", resp.StatusCode)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read upload queue: %v", err)
+			}
+			return ParseUploads(body, packageName, series)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s queue of %s for %s: %v", status, series, packageName, err)
+		}
+		uploads = append(uploads, value.([]Upload)...)
+	}
+
+	sort.Slice(uploads, func(i, j int) bool { return uploads[i].DateCreated.Before(uploads[j].DateCreated) })
+	return uploads, nil
+}
@@ -0,0 +1,46 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUploads(t *testing.T) {
+	data := []byte(`{"entries": [
+		{"package_name": "nvidia-graphics-drivers-570", "package_version": "570.195.03-0ubuntu0.24.04.1",
+		 "pocket": "Proposed", "status": "Unapproved", "date_created": "2026-10-01T08:30:00+00:00"},
+		{"package_name": "nvidia-graphics-drivers-570", "package_version": "570.181-0ubuntu0.24.04.1",
+		 "pocket": "Proposed", "status": "Done", "date_created": "2026-08-01T08:30:00+00:00"},
+		{"package_name": "nvidia-graphics-drivers-570-server", "package_version": "570.195.03-0ubuntu0.24.04.1",
+		 "pocket": "Proposed", "status": "Unapproved", "date_created": "2026-10-01T08:30:00+00:00"}
+	]}`)
+
+	uploads, err := ParseUploads(data, "nvidia-graphics-drivers-570", "noble")
+	if err != nil {
+		t.Fatalf("ParseUploads returned error: %v", err)
+	}
+	if len(uploads) != 1 {
+		t.Fatalf("ParseUploads returned %d uploads, expected 1: %+v", len(uploads), uploads)
+	}
+	if uploads[0].Version != "570.195.03-0ubuntu0.24.04.1" || uploads[0].Series != "noble" {
+		t.Errorf("ParseUploads()[0] = %+v, expected the unapproved noble upload", uploads[0])
+	}
+}
+
+func TestUploadLabel(t *testing.T) {
+	created := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	tests := []struct {
+		status   string
+		expected string
+	}{
+		{StatusUnapproved, "in unapproved since 2026-10-01"},
+		{StatusNew, "in NEW since 2026-10-01"},
+	}
+
+	for _, tt := range tests {
+		upload := Upload{Status: tt.status, DateCreated: created}
+		if label := upload.Label(); label != tt.expected {
+			t.Errorf("Label(%s) = %s, expected %s", tt.status, label, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Package rediscache keeps serialized API responses, and the token buckets of the inbound rate
+// limiter, in Redis. It speaks the few commands it needs (AUTH, SELECT, GET, SET and EVAL) over
+// RESP, and never fails a request: while Redis is unreachable lookups miss, writes are dropped
+// and token requests return an error for the caller to fall back on.
+package rediscache
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// retryDelay is how long Redis is left alone after a failure, so an outage does not add a
+// timeout to every request
+const retryDelay = 10 * time.Second
+
+// Cache stores responses in Redis under keys built by Key
+type Cache struct {
+	address  string
+	password string
+	db       int
+	prefix   string
+	ttl      time.Duration
+	timeout  time.Duration
+
+	idle chan *conn // Connections ready for reuse
+
+	mu      sync.Mutex
+	retryAt time.Time // Zero while Redis is reachable
+}
+
+// New returns the response cache of the configuration, or nil when it is disabled
+func New(cfg *config.RedisConfig) *Cache {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	return Connect(cfg)
+}
+
+// Connect returns a client of the configured Redis whether or not the response cache is
+// enabled, for the other users of the connection such as the rate limiter
+func Connect(cfg *config.RedisConfig) *Cache {
+	return &Cache{
+		address:  cfg.Address,
+		password: cfg.GetPassword(),
+		db:       cfg.DB,
+		prefix:   cfg.GetKeyPrefix(),
+		ttl:      cfg.GetTTL(),
+		timeout:  cfg.GetTimeout(),
+		idle:     make(chan *conn, cfg.GetPoolSize()),
+	}
+}
+
+// Key returns the key of a response of an endpoint built from a data generation. Query
+// parameters are sorted, so equivalent requests share a key.
+func (c *Cache) Key(endpoint, generation string, query url.Values) string {
+	sum := sha256.Sum256([]byte(query.Encode()))
+	return c.prefix + endpoint + ":" + generation + ":" + hex.EncodeToString(sum[:12])
+}
+
+// Get returns a cached response, and false on a miss or while Redis is unavailable
+func (c *Cache) Get(key string) ([]byte, bool) {
+	reply, err := c.do("GET", key)
+	if err != nil {
+		return nil, false
+	}
+	value, ok := reply.([]byte)
+	return value, ok
+}
+
+// Set stores a response for the configured TTL
+func (c *Cache) Set(key string, value []byte) {
+	c.do("SET", key, string(value), "PX", strconv.FormatInt(c.ttl.Milliseconds(), 10))
+}
+
+// tokenBucketScript takes a token from the bucket KEYS[1] holding up to ARGV[1] tokens and
+// refilled with ARGV[2] tokens per millisecond. It returns 0 when a token was taken, else the
+// milliseconds until the next one. The clock of Redis is used, so that every replica shares it.
+const tokenBucketScript = `
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1]) or capacity
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+else
+  wait = math.ceil((1 - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate) + 1000)
+return wait
+`
+
+// TakeToken takes a token from the bucket of a client, holding up to capacity tokens and
+// refilled with perMinute tokens a minute. It returns whether a token was taken, and otherwise
+// how long until the next one; an error means Redis is unavailable.
+func (c *Cache) TakeToken(client string, capacity, perMinute int) (bool, time.Duration, error) {
+	rate := strconv.FormatFloat(float64(perMinute)/float64(time.Minute.Milliseconds()), 'g', -1, 64)
+	reply, err := c.do("EVAL", tokenBucketScript, "1", c.prefix+"ratelimit:"+client, strconv.Itoa(capacity), rate)
+	if err != nil {
+		return false, 0, err
+	}
+	wait, ok := reply.(int64)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected token bucket reply %v", reply)
+	}
+	return wait == 0, time.Duration(wait) * time.Millisecond, nil
+}
+
+// errUnavailable is returned without contacting Redis during the retry delay
+var errUnavailable = errors.New("redis unavailable")
+
+// do runs a command on a pooled connection. Failed connections are closed, and a failure
+// keeps Redis out of the way until the retry delay is over.
+func (c *Cache) do(args ...string) (interface{}, error) {
+	c.mu.Lock()
+	waiting := !c.retryAt.IsZero() && time.Now().Before(c.retryAt)
+	c.mu.Unlock()
+	if waiting {
+		return nil, errUnavailable
+	}
+
+	cn, err := c.get()
+	if err == nil {
+		var reply interface{}
+		reply, err = cn.do(c.timeout, args...)
+		var redisErr redisError
+		if err == nil || errors.As(err, &redisErr) {
+			// Redis answered, so the connection can be reused
+			c.put(cn)
+			c.recovered()
+			return reply, err
+		}
+		cn.Close()
+	}
+
+	c.mu.Lock()
+	if c.retryAt.IsZero() {
+		log.Printf("Warning: Redis at %s is unavailable, serving without it: %v", c.address, err)
+	}
+	c.retryAt = time.Now().Add(retryDelay)
+	c.mu.Unlock()
+	return nil, err
+}
+
+// recovered notes that Redis answers again after a failure
+func (c *Cache) recovered() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.retryAt.IsZero() {
+		log.Printf("Redis at %s is reachable again", c.address)
+		c.retryAt = time.Time{}
+	}
+}
+
+// get takes an idle connection, or dials and sets up a new one
+func (c *Cache) get() (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	netConn, err := net.DialTimeout("tcp", c.address, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if c.password != "" {
+		if _, err := cn.do(c.timeout, "AUTH", c.password); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("AUTH failed: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.do(c.timeout, "SELECT", strconv.Itoa(c.db)); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("SELECT failed: %w", err)
+		}
+	}
+	return cn, nil
+}
+
+// put returns a connection to the pool, closing it when the pool is full
+func (c *Cache) put(cn *conn) {
+	select {
+	case c.idle <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+// Close closes the idle connections
+func (c *Cache) Close() {
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.Close()
+		default:
+			return
+		}
+	}
+}
+
+// redisError is an error reply of Redis, e.g. "WRONGPASS invalid username-password pair"
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// conn is a connection speaking RESP
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// do sends a command and reads its reply: a string for status replies, an int64 for
+// integers and a []byte for bulk strings, which is nil for a missing key
+func (c *conn) do(timeout time.Duration, args ...string) (interface{}, error) {
+	if err := c.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.Write([]byte(command.String())); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+func (c *conn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk length %q", line)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		value := make([]byte, length+2) // Followed by CRLF
+		if _, err := io.ReadFull(c.reader, value); err != nil {
+			return nil, err
+		}
+		return value[:length], nil
+	}
+	return nil, fmt.Errorf("unexpected reply %q", line)
+}
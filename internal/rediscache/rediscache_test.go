@@ -0,0 +1,186 @@
+package rediscache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// fakeRedis answers AUTH, SELECT, GET and SET from a map, and EVAL with the next of waits
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	values   map[string]string
+	waits    []int // Replies of the next EVAL calls
+	evalArgs []string
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{listener: listener, password: password, values: make(map[string]string)}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			netConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(netConn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(netConn net.Conn) {
+	defer netConn.Close()
+	reader := bufio.NewReader(netConn)
+	authenticated := f.password == ""
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, count)
+		for i := range args {
+			header, _ := reader.ReadString('\n')
+			length, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+			value := make([]byte, length+2)
+			io.ReadFull(reader, value)
+			args[i] = string(value[:length])
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, args[0])
+		var reply string
+		switch {
+		case args[0] == "AUTH":
+			authenticated = args[1] == f.password
+			reply = "+OK\r\n"
+			if !authenticated {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required\r\n"
+		case args[0] == "SELECT":
+			reply = "+OK\r\n"
+		case args[0] == "SET":
+			f.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case args[0] == "GET":
+			if value, ok := f.values[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case args[0] == "EVAL" && len(f.waits) > 0:
+			f.evalArgs = args[1:]
+			reply = fmt.Sprintf(":%d\r\n", f.waits[0])
+			f.waits = f.waits[1:]
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+		netConn.Write([]byte(reply))
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	cache := New(&config.RedisConfig{Enabled: true, Address: server.listener.Addr().String(), Password: "secret", DB: 2})
+	defer cache.Close()
+
+	key := cache.Key("packages", "1", url.Values{"view": {"server"}, "component": {"main"}})
+	if same := cache.Key("packages", "1", url.Values{"component": {"main"}, "view": {"server"}}); same != key {
+		t.Errorf("Key() depends on the parameter order: %s != %s", key, same)
+	}
+	if other := cache.Key("packages", "2", url.Values{"view": {"server"}, "component": {"main"}}); other == key {
+		t.Errorf("Key() should change with the generation")
+	}
+	if !strings.HasPrefix(key, "nvidia-monitor:packages:1:") {
+		t.Errorf("Key() = %s, expected the default prefix, endpoint and generation", key)
+	}
+
+	if _, ok := cache.Get(key); ok {
+		t.Errorf("Get() of a missing key should miss")
+	}
+	body := "{\"packages\": {}}\r\n"
+	cache.Set(key, []byte(body))
+	if value, ok := cache.Get(key); !ok || string(value) != body {
+		t.Errorf("Get() = %q, %v, expected the stored response", value, ok)
+	}
+
+	server.mu.Lock()
+	commands := strings.Join(server.commands, " ")
+	server.mu.Unlock()
+	if commands != "AUTH SELECT GET SET GET" {
+		t.Errorf("Commands sent = %s, expected a single pooled connection", commands)
+	}
+}
+
+func TestCacheUnavailable(t *testing.T) {
+	if New(&config.RedisConfig{}) != nil {
+		t.Errorf("New() should return nil when disabled")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	cache := New(&config.RedisConfig{Enabled: true, Address: address, Timeout: "100ms"})
+	start := time.Now()
+	cache.Set("key", []byte("value"))
+	if _, ok := cache.Get("key"); ok {
+		t.Errorf("Get() should miss while Redis is unreachable")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("An unreachable Redis took %v, expected the lookups to be skipped", elapsed)
+	}
+	if cache.retryAt.IsZero() {
+		t.Errorf("A failure should delay the next attempt")
+	}
+}
+
+func TestTakeToken(t *testing.T) {
+	server := newFakeRedis(t, "")
+	server.waits = []int{0, 1500}
+	cache := Connect(&config.RedisConfig{Address: server.listener.Addr().String()})
+	defer cache.Close()
+
+	if allowed, _, err := cache.TakeToken("ip:10.0.0.1", 10, 60); !allowed || err != nil {
+		t.Errorf("TakeToken() = %v, %v, expected a token", allowed, err)
+	}
+	server.mu.Lock()
+	args := server.evalArgs
+	server.mu.Unlock()
+	if len(args) != 5 || args[1] != "1" || args[2] != "nvidia-monitor:ratelimit:ip:10.0.0.1" || args[3] != "10" || args[4] != "0.001" {
+		t.Errorf("EVAL arguments = %q, expected the bucket key, capacity and rate per millisecond", args[1:])
+	}
+	if allowed, wait, err := cache.TakeToken("ip:10.0.0.1", 10, 60); allowed || wait != 1500*time.Millisecond || err != nil {
+		t.Errorf("TakeToken() = %v, %v, %v, expected to wait 1.5s", allowed, wait, err)
+	}
+
+	// An EVAL error reply, e.g. from a Redis without scripting, is an error too
+	if _, _, err := cache.TakeToken("ip:10.0.0.1", 10, 60); err == nil {
+		t.Error("TakeToken() should return the error reply of Redis")
+	}
+}
@@ -0,0 +1,135 @@
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// DevelAlias is the is_supported key that stands for the current development series
+const DevelAlias = "devel"
+
+var (
+	develMu       sync.RWMutex
+	develCodename string
+)
+
+// DevelCodename returns the codename the devel alias resolves to, or "" if it is unresolved
+func DevelCodename() string {
+	develMu.RLock()
+	defer develMu.RUnlock()
+	if releasesConfig != nil && releasesConfig.Series.Devel != "" {
+		return releasesConfig.Series.Devel
+	}
+	return develCodename
+}
+
+// SetDevelCodename records the codename the devel alias resolves to
+func SetDevelCodename(codename string) {
+	develMu.Lock()
+	defer develMu.Unlock()
+	develCodename = codename
+}
+
+// ResolveDevelCodename determines the development series from Launchpad's /ubuntu/devel,
+// then from fallback (e.g. kernel-series.yaml). A configured series.devel skips the lookups,
+// and the previous codename is kept when every source fails.
+func ResolveDevelCodename(fallback func() (string, error)) (string, error) {
+	if releasesConfig != nil && releasesConfig.Series.Devel != "" {
+		return releasesConfig.Series.Devel, nil
+	}
+
+	codename, err := fetchLaunchpadDevel()
+	if err != nil && fallback != nil {
+		log.Printf("Warning: %v; trying kernel-series.yaml", err)
+		codename, err = fallback()
+	}
+	if err != nil || codename == "" {
+		if previous := DevelCodename(); previous != "" {
+			log.Printf("Warning: Could not resolve the devel series, keeping %s", previous)
+			return previous, nil
+		}
+		return "", fmt.Errorf("could not resolve the devel series: %v", err)
+	}
+
+	if previous := DevelCodename(); previous != codename {
+		log.Printf("Devel series resolved to %s", codename)
+	}
+	SetDevelCodename(codename)
+	return codename, nil
+}
+
+// develSeriesURL returns the Launchpad URL of the development series
+func develSeriesURL() string {
+	if releasesConfig != nil {
+		effectiveURLs := releasesConfig.GetEffectiveURLs()
+		return effectiveURLs.Launchpad.GetUbuntuSeriesURL(DevelAlias)
+	}
+	return (&config.LaunchpadURLs{UbuntuSeriesBaseURL: "https://api.launchpad.net/devel/ubuntu"}).GetUbuntuSeriesURL(DevelAlias) // fallback
+}
+
+// fetchLaunchpadDevel reads the name of the distro series Launchpad serves as /ubuntu/devel
+func fetchLaunchpadDevel() (string, error) {
+	url := develSeriesURL()
+	resp, err := utils.LaunchpadGetWithRetry(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to fetch %s: unexpected status code %d", url, resp.StatusCode)
+	}
+
+	var series struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&series); err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	if series.Name == "" {
+		return "", fmt.Errorf("no series name in %s", url)
+	}
+	return series.Name, nil
+}
+
+// ResolveSeries maps the devel alias to its codename and returns other series unchanged
+func ResolveSeries(series string) string {
+	if series == DevelAlias {
+		if codename := DevelCodename(); codename != "" {
+			return codename
+		}
+	}
+	return series
+}
+
+// Supports reports whether the release is supported in a series. A series that is the
+// current development series is supported through the devel alias unless listed explicitly.
+func (r *SupportedRelease) Supports(series string) bool {
+	if supported, ok := r.IsSupported[series]; ok {
+		return supported
+	}
+	codename := DevelCodename()
+	return codename != "" && series == codename && r.IsSupported[DevelAlias]
+}
+
+// SupportedCodenames returns the supported series with the devel alias resolved, in display order
+func (r *SupportedRelease) SupportedCodenames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for series := range r.IsSupported {
+		codename := ResolveSeries(series)
+		if !seen[codename] && r.Supports(codename) {
+			seen[codename] = true
+			names = append(names, codename)
+		}
+	}
+	if releasesConfig != nil {
+		return releasesConfig.Series.Sort(names)
+	}
+	return (&config.SeriesConfig{}).Sort(names)
+}
@@ -0,0 +1,131 @@
+package releases
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+func TestResolveDevelCodename(t *testing.T) {
+	defer func() {
+		SetReleasesConfig(nil)
+		SetDevelCodename("")
+	}()
+
+	up := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up || r.URL.Path != "/ubuntu/devel" {
+			http.Error(w, "read-only", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"name": "stonking", "status": "Active Development"}`)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.URLs.Launchpad.UbuntuSeriesBaseURL = server.URL + "/ubuntu"
+	SetReleasesConfig(cfg)
+
+	kernelSeries := func() (string, error) { return "resolute", nil }
+	if codename, err := ResolveDevelCodename(kernelSeries); err != nil || codename != "stonking" {
+		t.Errorf("ResolveDevelCodename() = %s, %v, expected stonking from Launchpad", codename, err)
+	}
+
+	up = false
+	if codename, _ := ResolveDevelCodename(kernelSeries); codename != "resolute" {
+		t.Errorf("ResolveDevelCodename() = %s, expected the kernel-series.yaml fallback", codename)
+	}
+	failing := func() (string, error) { return "", fmt.Errorf("unavailable") }
+	if codename, err := ResolveDevelCodename(failing); err != nil || codename != "resolute" {
+		t.Errorf("ResolveDevelCodename() = %s, %v, expected the previous codename to be kept", codename, err)
+	}
+
+	cfg.Series.Devel = "override"
+	if codename, _ := ResolveDevelCodename(failing); codename != "override" {
+		t.Errorf("ResolveDevelCodename() = %s, expected the configured override", codename)
+	}
+}
+
+func TestSupportsDevelAlias(t *testing.T) {
+	defer SetDevelCodename("")
+	SetDevelCodename("resolute")
+
+	release := SupportedRelease{IsSupported: map[string]bool{"devel": true, "noble": true, "focal": false}}
+	tests := []struct {
+		series   string
+		expected bool
+	}{
+		{"resolute", true},
+		{"noble", true},
+		{"focal", false},
+		{"jammy", false},
+	}
+	for _, test := range tests {
+		if result := release.Supports(test.series); result != test.expected {
+			t.Errorf("Supports(%s) = %t, expected %t", test.series, result, test.expected)
+		}
+	}
+
+	// An explicit entry for the codename wins over the alias
+	release.IsSupported["resolute"] = false
+	if release.Supports("resolute") {
+		t.Errorf("Supports(resolute) = true, expected the explicit entry to win")
+	}
+
+	release.IsSupported["resolute"] = true
+	if names := release.SupportedCodenames(); len(names) != 2 || names[0] != "resolute" || names[1] != "noble" {
+		t.Errorf("SupportedCodenames() = %v, expected [resolute noble]", names)
+	}
+}
+
+func TestLookupSeriesState(t *testing.T) {
+	defer func() {
+		SetReleasesConfig(nil)
+		seriesStateMemo.Clear()
+	}()
+
+	up := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case !up:
+			http.Error(w, "read-only", http.StatusServiceUnavailable)
+		case r.URL.Path == "/ubuntu/noble":
+			fmt.Fprint(w, `{"name": "noble", "status": "Supported"}`)
+		case r.URL.Path == "/ubuntu/resolute":
+			fmt.Fprint(w, `{"name": "resolute", "status": "Pre-release Freeze"}`)
+		case r.URL.Path == "/ubuntu/mantic":
+			fmt.Fprint(w, `{"name": "mantic", "status": "Obsolete"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.URLs.Launchpad.UbuntuSeriesBaseURL = server.URL + "/ubuntu"
+	SetReleasesConfig(cfg)
+
+	tests := []struct {
+		series   string
+		expected string
+	}{
+		{"noble", SeriesMaintained},
+		{"resolute", SeriesFrozen},
+		{"mantic", SeriesEOL},
+		{"hoary-typo", SeriesUnknown},
+	}
+	for _, test := range tests {
+		if state, err := LookupSeriesState(test.series); err != nil || state != test.expected {
+			t.Errorf("LookupSeriesState(%s) = %s, %v, expected %s", test.series, state, err, test.expected)
+		}
+	}
+
+	// Cached states are kept while Launchpad is down
+	up = false
+	if state, err := LookupSeriesState("mantic"); err != nil || state != SeriesEOL {
+		t.Errorf("LookupSeriesState(mantic) = %s, %v, expected the cached %s", state, err, SeriesEOL)
+	}
+}
@@ -0,0 +1,174 @@
+package releases
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	eolMu    sync.Mutex
+	eolDates map[string]time.Time // Nil until loaded from the configuration
+)
+
+// resetEOLDates drops the loaded EOL dates so the next lookup reads the configuration again
+func resetEOLDates() {
+	eolMu.Lock()
+	defer eolMu.Unlock()
+	eolDates = nil
+}
+
+// parseDistroInfo reads the end-of-life dates of a distro-info CSV file (ubuntu.csv). A series
+// is kept alive until the later of its eol and eol-server dates; ESM is not followed.
+func parseDistroInfo(r io.Reader) (map[string]time.Time, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read distro-info header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[name] = i
+	}
+	seriesColumn, ok := columns["series"]
+	if !ok {
+		return nil, fmt.Errorf("distro-info file has no series column")
+	}
+
+	dates := make(map[string]time.Time)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse distro-info file: %w", err)
+		}
+		if seriesColumn >= len(record) {
+			continue
+		}
+		var eol time.Time
+		for _, name := range []string{"eol", "eol-server"} {
+			column, ok := columns[name]
+			if !ok || column >= len(record) {
+				continue
+			}
+			if date, err := time.Parse("2006-01-02", record[column]); err == nil && date.After(eol) {
+				eol = date
+			}
+		}
+		if !eol.IsZero() {
+			dates[record[seriesColumn]] = eol
+		}
+	}
+	return dates, nil
+}
+
+// loadEOLDates reads the distro-info file and applies the static series.eol table over it
+func loadEOLDates() map[string]time.Time {
+	dates := make(map[string]time.Time)
+	if releasesConfig == nil {
+		return dates
+	}
+	series := &releasesConfig.Series
+
+	if series.EOLFile != "" {
+		file, err := os.Open(series.EOLFile)
+		switch {
+		case os.IsNotExist(err):
+			// distro-info-data is not installed; only the static table applies
+		case err != nil:
+			log.Printf("Warning: Could not read series EOL dates: %v", err)
+		default:
+			parsed, err := parseDistroInfo(file)
+			file.Close()
+			if err != nil {
+				log.Printf("Warning: Could not read series EOL dates from %s: %v", series.EOLFile, err)
+			}
+			for codename, date := range parsed {
+				dates[codename] = date
+			}
+		}
+	}
+
+	for codename, value := range series.EOL {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			log.Printf("Warning: Ignoring series.eol entry %s=%q, expected YYYY-MM-DD", codename, value)
+			continue
+		}
+		dates[codename] = date
+	}
+	return dates
+}
+
+// EOLDate returns the end-of-life date of a series, and false when it is not known
+func EOLDate(codename string) (time.Time, bool) {
+	eolMu.Lock()
+	defer eolMu.Unlock()
+	if eolDates == nil {
+		eolDates = loadEOLDates()
+		logRetiredLocked(time.Now())
+	}
+	date, ok := eolDates[codename]
+	return date, ok
+}
+
+// Retired reports whether a series is past its end-of-life date and the configured grace
+// period, so it is no longer queried or shown. Series without a known EOL are never retired.
+func Retired(codename string, now time.Time) bool {
+	date, ok := EOLDate(codename)
+	if !ok || releasesConfig == nil {
+		return false
+	}
+	return !now.Before(date.Add(releasesConfig.Series.GetEOLGrace()))
+}
+
+// RetiredSeries returns the series with a known EOL that are retired at now, sorted by name
+func RetiredSeries(now time.Time) []string {
+	EOLDate("") // Loads the dates
+	eolMu.Lock()
+	defer eolMu.Unlock()
+	return retiredLocked(now)
+}
+
+// logRetiredLocked names the configured series that are retired, once per configuration
+func logRetiredLocked(now time.Time) {
+	if releasesConfig == nil {
+		return
+	}
+	retired := make(map[string]bool)
+	for _, codename := range retiredLocked(now) {
+		retired[codename] = true
+	}
+	var names []string
+	for _, codename := range releasesConfig.Series.GetOrder() {
+		if retired[codename] {
+			names = append(names, codename)
+		}
+	}
+	if len(names) > 0 {
+		log.Printf("Series past their EOL are no longer queried or shown: %v", names)
+	}
+}
+
+func retiredLocked(now time.Time) []string {
+	if releasesConfig == nil {
+		return nil
+	}
+	grace := releasesConfig.Series.GetEOLGrace()
+	var retired []string
+	for codename, date := range eolDates {
+		if !now.Before(date.Add(grace)) {
+			retired = append(retired, codename)
+		}
+	}
+	sort.Strings(retired)
+	return retired
+}
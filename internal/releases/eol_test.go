@@ -0,0 +1,54 @@
+package releases
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+func TestRetiredSeries(t *testing.T) {
+	defer SetReleasesConfig(nil)
+
+	csv := `version,codename,series,created,release,eol,eol-server,eol-esm
+18.04 LTS,Bionic Beaver,bionic,2017-10-19,2018-04-26,2023-05-31,2023-05-31,2028-04-26
+20.04 LTS,Focal Fossa,focal,2019-10-17,2020-04-23,2025-05-29,2025-05-29,2030-04-23
+22.04 LTS,Jammy Jellyfish,jammy,2021-10-14,2022-04-21,2027-06-01,2027-06-01,2032-04-21
+25.10,Questing Quokka,questing,2025-04-17,2025-10-09,2026-07-01
+26.04 LTS,Resolute Raccoon,resolute,2025-10-16,2026-04-23
+`
+	path := filepath.Join(t.TempDir(), "ubuntu.csv")
+	if err := os.WriteFile(path, []byte(csv), 0644); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Series.EOLFile = path
+	cfg.Series.EOL = map[string]string{"jammy": "2025-01-01"} // The static table wins
+	cfg.Series.EOLGrace = "720h"
+	SetReleasesConfig(cfg)
+
+	now := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	if retired := RetiredSeries(now); !reflect.DeepEqual(retired, []string{"bionic", "jammy"}) {
+		t.Errorf("RetiredSeries() = %v, expected bionic and jammy; focal is within its grace period", retired)
+	}
+	if !Retired("focal", now.Add(30*24*time.Hour)) {
+		t.Errorf("focal should be retired once its grace period is over")
+	}
+	if Retired("resolute", now) || Retired("unknown", now) {
+		t.Errorf("Series without an EOL date should never be retired")
+	}
+	if !Retired("questing", time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("questing should be retired after its eol date and grace period")
+	}
+
+	// A missing file leaves the static table only
+	cfg.Series.EOLFile = filepath.Join(t.TempDir(), "missing.csv")
+	SetReleasesConfig(cfg)
+	if retired := RetiredSeries(now); !reflect.DeepEqual(retired, []string{"jammy"}) {
+		t.Errorf("RetiredSeries() = %v, expected jammy from the static table", retired)
+	}
+}
@@ -0,0 +1,170 @@
+package releases
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// composedFile is the object form of a supported releases file: the releases of its includes,
+// in order, followed by its own releases. A release replaces an earlier one of the same branch
+// and platform.
+type composedFile struct {
+	Includes []string           `json:"includes,omitempty"` // Paths relative to this file
+	Releases []SupportedRelease `json:"releases,omitempty"`
+	Profiles map[string]overlay `json:"profiles,omitempty"` // Only read in the file named at startup
+}
+
+// overlay is a named set of extra includes and releases applied over the base list
+type overlay struct {
+	Includes []string           `json:"includes,omitempty"`
+	Releases []SupportedRelease `json:"releases,omitempty"`
+}
+
+// ActiveProfile returns the supported releases profile selected in the configuration
+func ActiveProfile() string {
+	if releasesConfig != nil {
+		return releasesConfig.Releases.Profile
+	}
+	return ""
+}
+
+// LoadSupportedReleases reads a supported releases file, resolving its includes, and applies
+// the named profile on top. The file is either a plain array of releases or an object with
+// "includes", "releases" and "profiles". An empty profile applies none.
+func LoadSupportedReleases(filename, profile string) ([]SupportedRelease, error) {
+	file, err := readComposedFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var list releaseList
+	if err := list.addFile(filename, file, nil); err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		selected, ok := file.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("profile %q is not defined in %s (defined: %s)", profile, filename, profileNames(file.Profiles))
+		}
+		stack := []string{absPath(filename)}
+		if err := list.addIncludes(filename, selected.Includes, stack); err != nil {
+			return nil, err
+		}
+		list.add(selected.Releases)
+	}
+	if err := validateComparisonPolicies(filename, list.releases); err != nil {
+		return nil, err
+	}
+	return list.releases, nil
+}
+
+// IsComposed reports whether a supported releases file uses the object form, which must not
+// be overwritten with a flat list
+func IsComposed(filename string) bool {
+	data, err := os.ReadFile(filename)
+	return err == nil && bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// readComposedFile parses either form of a supported releases file
+func readComposedFile(filename string) (*composedFile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	var file composedFile
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &file.Releases); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON in %s: %w", filename, err)
+		}
+		return &file, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON in %s: %w", filename, err)
+	}
+	return &file, nil
+}
+
+// releaseList accumulates releases, later ones replacing earlier ones in place
+type releaseList struct {
+	releases []SupportedRelease
+	index    map[string]int
+}
+
+// add appends releases or replaces those with the same branch and platform
+func (l *releaseList) add(releases []SupportedRelease) {
+	if l.index == nil {
+		l.index = make(map[string]int)
+	}
+	for _, rel := range releases {
+		key := rel.Platform + "/" + rel.BranchName
+		if i, ok := l.index[key]; ok {
+			l.releases[i] = rel
+			continue
+		}
+		l.index[key] = len(l.releases)
+		l.releases = append(l.releases, rel)
+	}
+}
+
+// addFile adds the includes and then the releases of a parsed file. stack holds the files
+// being included, to reject include cycles.
+func (l *releaseList) addFile(filename string, file *composedFile, stack []string) error {
+	stack = append(stack, absPath(filename))
+	if err := l.addIncludes(filename, file.Includes, stack); err != nil {
+		return err
+	}
+	l.add(file.Releases)
+	return nil
+}
+
+// addIncludes adds the files included by filename, resolved relative to its directory
+func (l *releaseList) addIncludes(filename string, includes []string, stack []string) error {
+	for _, include := range includes {
+		path := include
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(filename), include)
+		}
+		for _, open := range stack {
+			if open == absPath(path) {
+				return fmt.Errorf("include cycle: %s includes %s", filename, include)
+			}
+		}
+		file, err := readComposedFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: include %q: %w", filename, include, err)
+		}
+		if err := l.addFile(path, file, stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// absPath returns the absolute form of a path, or the path itself if that fails
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// profileNames lists the defined profiles for error messages
+func profileNames(profiles map[string]overlay) string {
+	if len(profiles) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,119 @@
+package releases
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func branchNames(releases []SupportedRelease) string {
+	names := make([]string, len(releases))
+	for i, rel := range releases {
+		names[i] = rel.BranchName
+		if rel.TargetVersion != "" {
+			names[i] += "@" + rel.TargetVersion
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func TestLoadSupportedReleasesComposes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.json"), `[{"branch_name": "570"}, {"branch_name": "580"}]`)
+	writeFile(t, filepath.Join(dir, "overlays", "kernel.json"), `{"releases": [{"branch_name": "535"}]}`)
+	writeFile(t, filepath.Join(dir, "overlays", "lab.json"), `{"includes": ["kernel.json"], "releases": [{"branch_name": "580", "target_version": "580.65.06"}]}`)
+	main := filepath.Join(dir, "supportedReleases.json")
+	writeFile(t, main, `{
+  "includes": ["base.json"],
+  "releases": [{"branch_name": "36", "platform": "tegra"}],
+  "profiles": {
+    "kernel-team": {"includes": ["overlays/kernel.json"]},
+    "lab": {"includes": ["overlays/lab.json"], "releases": [{"branch_name": "570-server"}]}
+  }
+}`)
+
+	tests := []struct {
+		profile string
+		want    string
+	}{
+		{"", "570,580,36"},
+		{"kernel-team", "570,580,36,535"},
+		{"lab", "570,580@580.65.06,36,535,570-server"},
+	}
+	for _, tt := range tests {
+		got, err := LoadSupportedReleases(main, tt.profile)
+		if err != nil {
+			t.Fatalf("LoadSupportedReleases(%q) error = %v", tt.profile, err)
+		}
+		if names := branchNames(got); names != tt.want {
+			t.Errorf("LoadSupportedReleases(%q) = %s, want %s", tt.profile, names, tt.want)
+		}
+	}
+
+	if _, err := LoadSupportedReleases(main, "missing"); err == nil || !strings.Contains(err.Error(), "kernel-team, lab") {
+		t.Errorf("unknown profile error = %v, expected the defined profiles", err)
+	}
+
+	defer SetReleasesConfig(releasesConfig)
+	cfg := config.DefaultConfig()
+	cfg.Releases.Profile = "kernel-team"
+	SetReleasesConfig(cfg)
+	if got, err := ReadSupportedReleases(main); err != nil || branchNames(got) != "570,580,36,535" {
+		t.Errorf("ReadSupportedReleases() = %s, %v, expected the configured profile", branchNames(got), err)
+	}
+	if err := WriteSupportedReleases(main, nil); err == nil {
+		t.Error("WriteSupportedReleases() should not flatten a composed file")
+	}
+	if err := WriteSupportedReleases(filepath.Join(dir, "base.json"), []SupportedRelease{{BranchName: "590"}}); err != nil {
+		t.Errorf("WriteSupportedReleases() of a plain list error = %v", err)
+	}
+}
+
+func TestLoadSupportedReleasesRejectsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.json"), `{"includes": ["b.json"]}`)
+	writeFile(t, filepath.Join(dir, "b.json"), `{"includes": ["a.json"]}`)
+	writeFile(t, filepath.Join(dir, "typo.json"), `{"include": ["a.json"]}`)
+	writeFile(t, filepath.Join(dir, "missing.json"), `{"includes": ["nope.json"]}`)
+
+	for name, want := range map[string]string{
+		"a.json":       "include cycle",
+		"typo.json":    "unknown field",
+		"missing.json": "nope.json",
+	} {
+		if _, err := LoadSupportedReleases(filepath.Join(dir, name), ""); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadSupportedReleases(%s) error = %v, want %q", name, err, want)
+		}
+	}
+}
+
+func TestLoadSupportedReleasesRejectsUnknownPolicy(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "supportedReleases.json")
+	writeFile(t, main, `[{"branch_name": "570", "comparison_policy": "strict"}, {"branch_name": "580"}]`)
+	got, err := LoadSupportedReleases(main, "")
+	if err != nil {
+		t.Fatalf("LoadSupportedReleases() error = %v", err)
+	}
+	if got[0].GetComparisonPolicy() != PolicyStrict || got[1].GetComparisonPolicy() != PolicyPrefix {
+		t.Errorf("policies = %q, %q, expected strict and the prefix default", got[0].GetComparisonPolicy(), got[1].GetComparisonPolicy())
+	}
+
+	writeFile(t, main, `[{"branch_name": "570", "comparison_policy": "exact"}]`)
+	if _, err := LoadSupportedReleases(main, ""); err == nil || !strings.Contains(err.Error(), `branch 570 has unknown comparison_policy "exact"`) {
+		t.Errorf("LoadSupportedReleases() error = %v, expected the unknown policy rejected", err)
+	}
+}
@@ -0,0 +1,78 @@
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Launchpad states of a series, see LookupSeriesState
+const (
+	SeriesMaintained = "maintained" // Supported, current or in development
+	SeriesFrozen     = "frozen"     // In pre-release freeze; uploads need a freeze exception
+	SeriesEOL        = "eol"        // Obsolete in Launchpad
+	SeriesUnknown    = "unknown"    // Launchpad has no series with that name
+)
+
+// launchpadFrozenStatus is the Launchpad status of a development series in its final freeze
+const launchpadFrozenStatus = "Pre-release Freeze"
+
+// seriesStateMemo caches series lookups; a series changes state a few times per cycle, and a
+// freeze should show up on the day it starts
+var seriesStateMemo = utils.NewTTLMemo(6 * time.Hour)
+
+// seriesURL returns the Launchpad URL of a series
+func seriesURL(codename string) string {
+	if releasesConfig != nil {
+		effectiveURLs := releasesConfig.GetEffectiveURLs()
+		return effectiveURLs.Launchpad.GetUbuntuSeriesURL(codename)
+	}
+	return (&config.LaunchpadURLs{UbuntuSeriesBaseURL: "https://api.launchpad.net/devel/ubuntu"}).GetUbuntuSeriesURL(codename) // fallback
+}
+
+// LookupSeriesState asks Launchpad's /ubuntu/{series} whether a series exists, is frozen or
+// is still maintained. Answers are cached, and the last known state is kept while Launchpad is failing.
+func LookupSeriesState(codename string) (string, error) {
+	url := seriesURL(codename)
+	value, _, err := seriesStateMemo.GetStale(url, func() (interface{}, error) {
+		return fetchSeriesState(url)
+	})
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// fetchSeriesState reads the state of the series served at url
+func fetchSeriesState(url string) (string, error) {
+	resp, err := utils.LaunchpadGetWithRetry(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return SeriesUnknown, nil
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to fetch %s: unexpected status code %d", url, resp.StatusCode)
+	}
+
+	var series struct {
+		Status string `json:"status"` // e.g. "Supported", "Pre-release Freeze", "Obsolete"
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&series); err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	switch series.Status {
+	case "Obsolete":
+		return SeriesEOL, nil
+	case launchpadFrozenStatus:
+		return SeriesFrozen, nil
+	}
+	return SeriesMaintained, nil
+}
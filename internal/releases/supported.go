@@ -4,47 +4,118 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"os"
 	"strings"
 	"time"
 
+	"nvidia_driver_monitor/internal/config"
 	"nvidia_driver_monitor/internal/drivers"
 )
 
+// Global configuration for releases
+var releasesConfig *config.Config
+
+// SetReleasesConfig sets the global configuration for releases
+func SetReleasesConfig(cfg *config.Config) {
+	releasesConfig = cfg
+	resetEOLDates()
+}
+
+// SupportedSeriesNames returns the series of IsSupported in display order
+func (r *SupportedRelease) SupportedSeriesNames() []string {
+	names := make([]string, 0, len(r.IsSupported))
+	for series := range r.IsSupported {
+		names = append(names, series)
+	}
+	if releasesConfig != nil {
+		return releasesConfig.Series.Sort(names)
+	}
+	return (&config.SeriesConfig{}).Sort(names)
+}
+
+// PlatformTegra marks the Tegra/Jetson branches; their BranchName is the L4T major, e.g. "36"
+const PlatformTegra = "tegra"
+
+// driverPackagePrefix is the source package prefix of the desktop and server branches
+const driverPackagePrefix = "nvidia-graphics-drivers-"
+
 // SupportedRelease represents a supported release configuration
 type SupportedRelease struct {
 	BranchName             string            `json:"branch_name"`
 	IsServer               bool              `json:"is_server"`
+	Platform               string            `json:"platform,omitempty"` // Empty for desktop/server, or PlatformTegra
 	IsSupported            map[string]bool   `json:"is_supported"`
 	CurrentUpstreamVersion string            `json:"current_upstream_version"`
 	DatePublished          string            `json:"date_published"`
+	TargetVersion          string            `json:"target_version,omitempty"` // Set from the configured target provider
+	TargetNote             string            `json:"target_note,omitempty"`
+	TargetDate             string            `json:"target_date,omitempty"`
+	ComparisonPolicy       string            `json:"comparison_policy,omitempty"` // How packages match the comparison version; empty is PolicyPrefix
 	SourceVersionUpdates   map[string]string `json:"source_version_updates,omitempty"`
 	SourceVersionProposed  map[string]string `json:"source_version_proposed,omitempty"`
 }
 
-// ReadSupportedReleases reads the JSON file and returns an array of SupportedRelease
-func ReadSupportedReleases(filename string) ([]SupportedRelease, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+// IsTegra reports whether the release is a Tegra/Jetson branch
+func (r *SupportedRelease) IsTegra() bool {
+	return r.Platform == PlatformTegra
+}
+
+// tegraPackagePrefix returns the configured source package prefix of the Tegra branches
+func tegraPackagePrefix() string {
+	if releasesConfig != nil {
+		return releasesConfig.Tegra.GetPackagePrefix()
 	}
-	defer file.Close()
+	return (&config.TegraConfig{}).GetPackagePrefix()
+}
 
-	bytes, err := ioutil.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+// PackageName returns the source package of the release, e.g. "nvidia-graphics-drivers-570"
+func (r *SupportedRelease) PackageName() string {
+	if r.IsTegra() {
+		return tegraPackagePrefix() + r.BranchName
 	}
+	return driverPackagePrefix + r.BranchName
+}
 
-	var releases []SupportedRelease
-	if err := json.Unmarshal(bytes, &releases); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+// BranchFromPackage returns the branch of a source package, e.g. "570-server" for
+// "nvidia-graphics-drivers-570-server" or "36" for a Tegra package
+func BranchFromPackage(packageName string) string {
+	if tegra := tegraPackagePrefix(); strings.HasPrefix(packageName, tegra) {
+		return strings.TrimPrefix(packageName, tegra)
 	}
+	return strings.TrimPrefix(packageName, driverPackagePrefix)
+}
 
-	return releases, nil
+// TegraEnabled reports whether the Tegra branches are tracked
+func TegraEnabled() bool {
+	return releasesConfig != nil && releasesConfig.Tegra.Enabled
+}
+
+// EnabledReleases drops the Tegra branches unless they are enabled in the configuration
+func EnabledReleases(releases []SupportedRelease) []SupportedRelease {
+	if TegraEnabled() {
+		return releases
+	}
+	enabled := make([]SupportedRelease, 0, len(releases))
+	for _, rel := range releases {
+		if !rel.IsTegra() {
+			enabled = append(enabled, rel)
+		}
+	}
+	return enabled
 }
 
-// WriteSupportedReleases writes the supported releases to a JSON file
+// ReadSupportedReleases reads the supported releases file with the configured profile
+// applied, see LoadSupportedReleases
+func ReadSupportedReleases(filename string) ([]SupportedRelease, error) {
+	return LoadSupportedReleases(filename, ActiveProfile())
+}
+
+// WriteSupportedReleases writes the supported releases to a JSON file. A file composed from
+// includes is not overwritten, as that would flatten it.
 func WriteSupportedReleases(filename string, releases []SupportedRelease) error {
+	if IsComposed(filename) {
+		return fmt.Errorf("%s is composed from includes and profiles; not overwriting it", filename)
+	}
+
 	data, err := json.MarshalIndent(releases, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
@@ -65,8 +136,8 @@ func PrintSupportedReleases(releases []SupportedRelease) {
 	for _, r := range releases {
 		// Format IsSupported map as key:value pairs
 		supportedStr := ""
-		for k, v := range r.IsSupported {
-			supportedStr += fmt.Sprintf("%s:%t ", k, v)
+		for _, k := range r.SupportedSeriesNames() {
+			supportedStr += fmt.Sprintf("%s:%t ", k, r.IsSupported[k])
 		}
 
 		fmt.Printf("%-20s %-8t %-80s %-25s %-15s\n",
@@ -86,6 +157,9 @@ func GetUniqueBranchMajors(releases []SupportedRelease) []string {
 	majors := make([]string, 0, len(releases))
 
 	for _, rel := range releases {
+		if rel.IsTegra() {
+			continue // Tegra branches are not published on nvidia.com
+		}
 		branch := rel.BranchName
 		if idx := strings.Index(branch, "-"); idx >= 0 {
 			branch = branch[:idx]
@@ -120,6 +194,9 @@ func UpdateSupportedUDAReleases(udaEntries []drivers.DriverEntry, supportedRelea
 
 	// Update releases
 	for i, rel := range supportedReleases {
+		if rel.IsTegra() {
+			continue
+		}
 		major := rel.BranchName
 		if entry, ok := latestByMajor[major]; ok {
 			supportedReleases[i].CurrentUpstreamVersion = entry.Version
@@ -151,3 +228,28 @@ func UpdateSupportedReleasesWithLatestERD(allBranches drivers.AllBranches, suppo
 		}
 	}
 }
+
+// UpdateSupportedTegraReleases updates the Tegra branches with their latest L4T release
+func UpdateSupportedTegraReleases(tegraEntries []drivers.DriverEntry, supportedReleases []SupportedRelease) {
+	// Entries are sorted newest first, so the first one of each major is the latest
+	latestByMajor := make(map[string]drivers.DriverEntry)
+	for _, entry := range tegraEntries {
+		major := drivers.TegraMajor(entry.Version)
+		if _, ok := latestByMajor[major]; !ok {
+			latestByMajor[major] = entry
+		}
+	}
+
+	for i := range supportedReleases {
+		rel := &supportedReleases[i]
+		if !rel.IsTegra() {
+			continue
+		}
+		if entry, ok := latestByMajor[rel.BranchName]; ok {
+			rel.CurrentUpstreamVersion = entry.Version
+			if !entry.Date.IsZero() {
+				rel.DatePublished = entry.Date.Format("2006-01-02")
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package releases
+
+import (
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/drivers"
+)
+
+func TestTegraReleases(t *testing.T) {
+	defer SetReleasesConfig(releasesConfig)
+
+	supported := []SupportedRelease{
+		{BranchName: "570"},
+		{BranchName: "570-server", IsServer: true},
+		{BranchName: "36", Platform: PlatformTegra},
+	}
+
+	SetReleasesConfig(config.DefaultConfig())
+	if enabled := EnabledReleases(supported); len(enabled) != 2 {
+		t.Errorf("EnabledReleases() = %v, expected the Tegra branch to be dropped while disabled", enabled)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Tegra.Enabled = true
+	SetReleasesConfig(cfg)
+	if enabled := EnabledReleases(supported); len(enabled) != 3 {
+		t.Errorf("EnabledReleases() = %v, expected every branch once enabled", enabled)
+	}
+
+	tests := []struct {
+		release SupportedRelease
+		pkg     string
+	}{
+		{supported[0], "nvidia-graphics-drivers-570"},
+		{supported[1], "nvidia-graphics-drivers-570-server"},
+		{supported[2], "nvidia-tegra-drivers-36"},
+	}
+	for _, test := range tests {
+		if pkg := test.release.PackageName(); pkg != test.pkg {
+			t.Errorf("PackageName(%s) = %s, expected %s", test.release.BranchName, pkg, test.pkg)
+		}
+		if branch := BranchFromPackage(test.pkg); branch != test.release.BranchName {
+			t.Errorf("BranchFromPackage(%s) = %s, expected %s", test.pkg, branch, test.release.BranchName)
+		}
+	}
+
+	if majors := GetUniqueBranchMajors(supported); len(majors) != 1 || majors[0] != "570" {
+		t.Errorf("GetUniqueBranchMajors() = %v, expected only 570", majors)
+	}
+
+	// Entries are newest first, as returned by drivers.GetTegraReleases
+	UpdateSupportedTegraReleases([]drivers.DriverEntry{
+		{Version: "36.4", Date: time.Date(2024, 12, 10, 19, 3, 0, 0, time.UTC)},
+		{Version: "36.3", Date: time.Date(2024, 4, 29, 17, 55, 0, 0, time.UTC)},
+		{Version: "35.6", Date: time.Date(2024, 9, 18, 21, 4, 0, 0, time.UTC)},
+	}, supported)
+	if supported[2].CurrentUpstreamVersion != "36.4" || supported[2].DatePublished != "2024-12-10" {
+		t.Errorf("UpdateSupportedTegraReleases() set %s (%s), expected 36.4 (2024-12-10)", supported[2].CurrentUpstreamVersion, supported[2].DatePublished)
+	}
+	if supported[0].CurrentUpstreamVersion != "" {
+		t.Errorf("UpdateSupportedTegraReleases() should not touch branch %s", supported[0].BranchName)
+	}
+}
@@ -0,0 +1,145 @@
+package releases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// TargetVersion is a version blessed for a branch (e.g. by QA) that overrides the latest upstream
+// version as the reference for comparisons
+type TargetVersion struct {
+	Version string `json:"version"`
+	Note    string `json:"note,omitempty"`
+	Date    string `json:"date,omitempty"` // Upstream release date of the version, YYYY-MM-DD
+}
+
+// TargetProvider supplies per-branch target versions keyed by branch name (e.g. "570", "535-server")
+type TargetProvider interface {
+	Targets() (map[string]TargetVersion, error)
+}
+
+// FileTargetProvider reads target versions from a static JSON file
+type FileTargetProvider struct {
+	Path string
+}
+
+// Targets reads and parses the JSON file
+func (p *FileTargetProvider) Targets() (map[string]TargetVersion, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target versions %s: %w", p.Path, err)
+	}
+	return ParseTargets(data)
+}
+
+// HTTPTargetProvider fetches target versions from a JSON feed
+type HTTPTargetProvider struct {
+	URL string
+}
+
+// Targets fetches and parses the JSON feed
+func (p *HTTPTargetProvider) Targets() (map[string]TargetVersion, error) {
+	resp, err := utils.HTTPGetWithRetry(p.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target versions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code fetching target versions: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target versions: %w", err)
+	}
+	return ParseTargets(data)
+}
+
+// ParseTargets decodes a branch -> target version JSON object, dropping entries without a version
+func ParseTargets(data []byte) (map[string]TargetVersion, error) {
+	var targets map[string]TargetVersion
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal target versions: %w", err)
+	}
+	for branch, target := range targets {
+		if target.Version == "" {
+			delete(targets, branch)
+		}
+	}
+	return targets, nil
+}
+
+// ApplyTargetVersions sets the target version of every supported release that has one
+func ApplyTargetVersions(targets map[string]TargetVersion, supportedReleases []SupportedRelease) {
+	for i := range supportedReleases {
+		rel := &supportedReleases[i]
+		if target, ok := targets[rel.BranchName]; ok {
+			rel.TargetVersion = target.Version
+			rel.TargetNote = target.Note
+			rel.TargetDate = target.Date
+		}
+	}
+}
+
+// ComparisonVersion returns the version packages are compared against: the target when set,
+// otherwise the latest upstream version
+func (r *SupportedRelease) ComparisonVersion() string {
+	if r.TargetVersion != "" {
+		return r.TargetVersion
+	}
+	return r.CurrentUpstreamVersion
+}
+
+// ComparisonDate returns the upstream release date of the comparison version, empty when a
+// target without a date is set
+func (r *SupportedRelease) ComparisonDate() string {
+	if r.TargetVersion != "" {
+		return r.TargetDate
+	}
+	return r.DatePublished
+}
+
+// Comparison policies deciding whether a package version matches the comparison version
+const (
+	// PolicyPrefix matches a package version containing the comparison version, which allows
+	// Ubuntu-only revisions such as 570.172.08-0ubuntu0.24.04.1~rebuild1
+	PolicyPrefix = "prefix"
+	// PolicyStrict matches when the upstream part of the package version, without epoch and
+	// Debian revision, equals the comparison version exactly
+	PolicyStrict = "strict"
+	// PolicyEpochAware is PolicyStrict keeping the epoch: the comparison version may carry one,
+	// e.g. "1:535.261.03", and a package version without an epoch has epoch 0
+	PolicyEpochAware = "epoch-aware"
+)
+
+// ComparisonPolicies lists the valid comparison policies
+var ComparisonPolicies = []string{PolicyPrefix, PolicyStrict, PolicyEpochAware}
+
+// GetComparisonPolicy returns the comparison policy of the release, defaulting to PolicyPrefix
+func (r *SupportedRelease) GetComparisonPolicy() string {
+	if r.ComparisonPolicy == "" {
+		return PolicyPrefix
+	}
+	return r.ComparisonPolicy
+}
+
+// validateComparisonPolicies rejects releases with an unknown comparison policy
+func validateComparisonPolicies(filename string, releases []SupportedRelease) error {
+	for _, rel := range releases {
+		valid := false
+		for _, policy := range ComparisonPolicies {
+			valid = valid || rel.GetComparisonPolicy() == policy
+		}
+		if !valid {
+			return fmt.Errorf("%s: branch %s has unknown comparison_policy %q (valid: %s)",
+				filename, rel.BranchName, rel.ComparisonPolicy, strings.Join(ComparisonPolicies, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package releases
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileTargetProviderAppliesOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.json")
+	data := `{
+		"570": {"version": "570.172.08", "note": "QA blessed", "date": "2026-09-16"},
+		"535-server": {"version": ""}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	provider := &FileTargetProvider{Path: path}
+	targets, err := provider.Targets()
+	if err != nil {
+		t.Fatalf("Targets() returned error: %v", err)
+	}
+	if len(targets) != 1 {
+		t.Errorf("Targets() = %v, expected entries without a version to be dropped", targets)
+	}
+
+	supported := []SupportedRelease{
+		{BranchName: "570", CurrentUpstreamVersion: "570.195.03"},
+		{BranchName: "535-server", CurrentUpstreamVersion: "535.274.02", DatePublished: "2026-10-01"},
+	}
+	ApplyTargetVersions(targets, supported)
+
+	tests := []struct {
+		branch       string
+		expected     string
+		expectedDate string
+	}{
+		{"570", "570.172.08", "2026-09-16"},
+		{"535-server", "535.274.02", "2026-10-01"},
+	}
+	for i, tt := range tests {
+		if got := supported[i].ComparisonVersion(); got != tt.expected {
+			t.Errorf("ComparisonVersion(%s) = %s, expected %s", tt.branch, got, tt.expected)
+		}
+		if got := supported[i].ComparisonDate(); got != tt.expectedDate {
+			t.Errorf("ComparisonDate(%s) = %s, expected %s", tt.branch, got, tt.expectedDate)
+		}
+	}
+	if supported[0].TargetNote != "QA blessed" {
+		t.Errorf("TargetNote = %q, expected %q", supported[0].TargetNote, "QA blessed")
+	}
+}
@@ -0,0 +1,220 @@
+// Package sbom builds software bills of materials of the NVIDIA driver components published in
+// an Ubuntu series, in CycloneDX or SPDX JSON, for compliance tooling that ingests SBOMs.
+package sbom
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Component kinds
+const (
+	KindDriver   = "driver"   // nvidia-graphics-drivers-* source package
+	KindDKMS     = "dkms"     // nvidia-dkms-* binary package, built from the driver source
+	KindFirmware = "firmware" // nvidia-firmware-* binary package, built from the driver source
+	KindLRM      = "lrm"      // linux-restricted-modules-* source package of a kernel
+)
+
+// toolName identifies the generator in the documents
+const toolName = "nvidia-driver-monitor"
+
+// Component is a package of the GPU stack published in a series
+type Component struct {
+	Name    string // e.g. "nvidia-dkms-570"
+	Version string // Debian version, e.g. "570.195.03-0ubuntu0.24.04.1"
+	Kind    string // One of the Kind* constants
+	Source  bool   // Whether Name is a source package rather than a binary package
+	Branch  string // Driver branch, e.g. "570-server"; empty for L-R-M
+	Kernel  string // Kernel source the L-R-M package is built for, e.g. "linux-aws"
+}
+
+// PURL returns the package URL of the component in a series, e.g.
+// "pkg:deb/ubuntu/nvidia-dkms-570@570.195.03-0ubuntu0.24.04.1?distro=noble"
+func (c Component) PURL(series string) string {
+	query := url.Values{}
+	if c.Source {
+		query.Set("arch", "source")
+	}
+	query.Set("distro", series)
+	return fmt.Sprintf("pkg:deb/ubuntu/%s@%s?%s", c.Name, url.PathEscape(c.Version), query.Encode())
+}
+
+// sortComponents orders components by kind, then name
+func sortComponents(components []Component) {
+	order := map[string]int{KindDriver: 0, KindDKMS: 1, KindFirmware: 2, KindLRM: 3}
+	sort.SliceStable(components, func(i, j int) bool {
+		if components[i].Kind != components[j].Kind {
+			return order[components[i].Kind] < order[components[j].Kind]
+		}
+		return components[i].Name < components[j].Name
+	})
+}
+
+// CycloneDX is a CycloneDX 1.5 JSON document
+type CycloneDX struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    CycloneDXMetadata    `json:"metadata"`
+	Components  []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXMetadata describes when and by what the document was generated, and for which series
+type CycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     CycloneDXTools     `json:"tools"`
+	Component CycloneDXComponent `json:"component"`
+}
+
+// CycloneDXTools lists the generating tools
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXComponent is a component of the document
+type CycloneDXComponent struct {
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Type       string              `json:"type"` // "device-driver", "firmware", "operating-system" or "application"
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+// CycloneDXProperty is a name/value annotation of a component
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// BuildCycloneDX builds the CycloneDX document of the components of a series, as of created
+func BuildCycloneDX(series string, components []Component, created time.Time) CycloneDX {
+	components = append([]Component(nil), components...)
+	sortComponents(components)
+
+	doc := CycloneDX{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: created.UTC().Format(time.RFC3339),
+			Tools:     CycloneDXTools{Components: []CycloneDXComponent{{Type: "application", Name: toolName}}},
+			Component: CycloneDXComponent{Type: "operating-system", Name: "ubuntu", Version: series},
+		},
+		Components: []CycloneDXComponent{},
+	}
+	for _, c := range components {
+		ref := c.PURL(series)
+		component := CycloneDXComponent{BOMRef: ref, Type: "device-driver", Name: c.Name, Version: c.Version, PURL: ref}
+		if c.Kind == KindFirmware {
+			component.Type = "firmware"
+		}
+		component.Properties = append(component.Properties, CycloneDXProperty{Name: toolName + ":kind", Value: c.Kind})
+		if c.Branch != "" {
+			component.Properties = append(component.Properties, CycloneDXProperty{Name: toolName + ":branch", Value: c.Branch})
+		}
+		if c.Kernel != "" {
+			component.Properties = append(component.Properties, CycloneDXProperty{Name: toolName + ":kernel", Value: c.Kernel})
+		}
+		doc.Components = append(doc.Components, component)
+	}
+	return doc
+}
+
+// SPDX is an SPDX 2.3 JSON document
+type SPDX struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	Packages          []SPDXPackage      `json:"packages"`
+	Relationships     []SPDXRelationship `json:"relationships"`
+}
+
+// SPDXCreationInfo records when and by what the document was created
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// SPDXPackage is a package of the document
+type SPDXPackage struct {
+	SPDXID                string            `json:"SPDXID"`
+	Name                  string            `json:"name"`
+	VersionInfo           string            `json:"versionInfo"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose"` // "SOURCE", "LIBRARY" or "FIRMWARE"
+	Comment               string            `json:"comment,omitempty"`
+	ExternalRefs          []SPDXExternalRef `json:"externalRefs"`
+}
+
+// SPDXExternalRef points a package at its package URL
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// SPDXRelationship relates two elements of the document
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// BuildSPDX builds the SPDX document of the components of a series, as of created, relating the
+// dkms and firmware packages to the driver source they are generated from. The namespace must
+// be unique to the document, e.g. its URL and creation time.
+func BuildSPDX(series string, components []Component, created time.Time, namespace string) SPDX {
+	components = append([]Component(nil), components...)
+	sortComponents(components)
+
+	doc := SPDX{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              fmt.Sprintf("ubuntu-%s-nvidia", series),
+		DocumentNamespace: namespace,
+		CreationInfo:      SPDXCreationInfo{Created: created.UTC().Format(time.RFC3339), Creators: []string{"Tool: " + toolName}},
+		Packages:          []SPDXPackage{},
+		Relationships:     []SPDXRelationship{},
+	}
+	sources := make(map[string]string) // Branch to the SPDXID of its driver source
+	for i, c := range components {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		pkg := SPDXPackage{
+			SPDXID:                id,
+			Name:                  c.Name,
+			VersionInfo:           c.Version,
+			DownloadLocation:      "NOASSERTION",
+			PrimaryPackagePurpose: "LIBRARY",
+			ExternalRefs:          []SPDXExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: c.PURL(series)}},
+		}
+		switch {
+		case c.Kind == KindFirmware:
+			pkg.PrimaryPackagePurpose = "FIRMWARE"
+		case c.Source:
+			pkg.PrimaryPackagePurpose = "SOURCE"
+		}
+		if c.Kernel != "" {
+			pkg.Comment = "Pre-built NVIDIA modules of " + c.Kernel
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, SPDXRelationship{SPDXElementID: doc.SPDXID, RelationshipType: "DESCRIBES", RelatedSPDXElement: id})
+
+		switch c.Kind {
+		case KindDriver:
+			sources[c.Branch] = id
+		case KindDKMS, KindFirmware:
+			if source, ok := sources[c.Branch]; ok {
+				doc.Relationships = append(doc.Relationships, SPDXRelationship{SPDXElementID: id, RelationshipType: "GENERATED_FROM", RelatedSPDXElement: source})
+			}
+		}
+	}
+	return doc
+}
@@ -0,0 +1,81 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var components = []Component{
+	{Name: "linux-restricted-modules-aws", Version: "6.8.0-1036.38", Kind: KindLRM, Source: true, Kernel: "linux-aws"},
+	{Name: "nvidia-firmware-570-570.195.03", Version: "570.195.03-0ubuntu0.24.04.1", Kind: KindFirmware, Branch: "570"},
+	{Name: "nvidia-dkms-570", Version: "570.195.03-0ubuntu0.24.04.1", Kind: KindDKMS, Branch: "570"},
+	{Name: "nvidia-graphics-drivers-570", Version: "570.195.03-0ubuntu0.24.04.1", Kind: KindDriver, Source: true, Branch: "570"},
+}
+
+func TestPURL(t *testing.T) {
+	if purl := components[3].PURL("noble"); purl != "pkg:deb/ubuntu/nvidia-graphics-drivers-570@570.195.03-0ubuntu0.24.04.1?arch=source&distro=noble" {
+		t.Errorf("source purl = %s", purl)
+	}
+	if purl := components[2].PURL("noble"); purl != "pkg:deb/ubuntu/nvidia-dkms-570@570.195.03-0ubuntu0.24.04.1?distro=noble" {
+		t.Errorf("binary purl = %s", purl)
+	}
+}
+
+func TestBuildCycloneDX(t *testing.T) {
+	created := time.Date(2026, 10, 17, 9, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	doc := BuildCycloneDX("noble", components, created)
+	if doc.BOMFormat != "CycloneDX" || doc.Metadata.Timestamp != "2026-10-17T07:00:00Z" || doc.Metadata.Component.Version != "noble" {
+		t.Errorf("header = %+v", doc)
+	}
+	var order []string
+	for _, c := range doc.Components {
+		order = append(order, c.Type+" "+c.Name)
+		if c.BOMRef == "" || c.BOMRef != c.PURL {
+			t.Errorf("%s: bom-ref %q, purl %q", c.Name, c.BOMRef, c.PURL)
+		}
+	}
+	expected := "[device-driver nvidia-graphics-drivers-570 device-driver nvidia-dkms-570 firmware nvidia-firmware-570-570.195.03 device-driver linux-restricted-modules-aws]"
+	if got := fmt.Sprint(order); got != expected {
+		t.Errorf("components = %s, expected %s", got, expected)
+	}
+	if components[0].Kind != KindLRM {
+		t.Error("the input components were reordered")
+	}
+}
+
+func TestBuildSPDX(t *testing.T) {
+	doc := BuildSPDX("noble", components, time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC), "https://monitor.example.com/api/v1/sbom/noble/spdx/1")
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded["spdxVersion"] != "SPDX-2.3" || decoded["SPDXID"] != "SPDXRef-DOCUMENT" {
+		t.Fatalf("document = %s (%v)", data, err)
+	}
+
+	ids := make(map[string]string)
+	for _, pkg := range doc.Packages {
+		ids[pkg.Name] = pkg.SPDXID
+	}
+	generated := make(map[string]string)
+	describes := 0
+	for _, rel := range doc.Relationships {
+		switch rel.RelationshipType {
+		case "DESCRIBES":
+			describes++
+		case "GENERATED_FROM":
+			generated[rel.SPDXElementID] = rel.RelatedSPDXElement
+		}
+	}
+	source := ids["nvidia-graphics-drivers-570"]
+	if describes != len(components) || len(generated) != 2 ||
+		generated[ids["nvidia-dkms-570"]] != source || generated[ids["nvidia-firmware-570-570.195.03"]] != source {
+		t.Errorf("relationships = %+v", doc.Relationships)
+	}
+	if doc.Packages[2].PrimaryPackagePurpose != "FIRMWARE" || doc.Packages[0].PrimaryPackagePurpose != "SOURCE" {
+		t.Errorf("purposes = %+v", doc.Packages)
+	}
+}
@@ -0,0 +1,162 @@
+// Package schema derives JSON Schema documents from the Go types the API encodes, and checks
+// decoded JSON values against them. Only the parts of JSON Schema (2020-12) that the types
+// need are produced and checked: type, properties, required, additionalProperties, items,
+// format and $ref into $defs.
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the generated documents
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema
+type Schema struct {
+	SchemaURI            string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"` // A type name, or a list of them for nullable values
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"` // false, or the schema of map values
+	Items                *Schema            `json:"items,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// generator collects the definitions of the named struct types reached from the root
+type generator struct {
+	defs map[string]*Schema
+}
+
+// Generate returns the schema of the JSON encoding of values of type t, titled title and
+// identified by id. Named struct types are defined once in $defs.
+func Generate(t reflect.Type, id, title string) *Schema {
+	g := &generator{defs: make(map[string]*Schema)}
+	root := g.schemaOf(t)
+	root.SchemaURI = Draft
+	root.ID = id
+	root.Title = title
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+// nullable adds "null" to the type of a schema; references are left alone
+func nullable(s *Schema) *Schema {
+	if name, ok := s.Type.(string); ok {
+		s.Type = []string{name, "null"}
+	}
+	return s
+}
+
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() != reflect.Pointer && t.Implements(marshalerType):
+		return &Schema{} // Custom encodings accept any value
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := g.schemaOf(t.Elem())
+		if inner.Ref != "" {
+			return inner // Nil references are checked as null by the validator
+		}
+		return nullable(inner)
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"} // []byte is encoded as base64
+		}
+		return nullable(&Schema{Type: "array", Items: g.schemaOf(t.Elem())})
+	case reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return nullable(&Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())})
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := defName(t)
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = &Schema{} // Placeholder, so recursive types terminate
+			g.defs[name] = g.structSchema(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	}
+	return &Schema{} // Interfaces and other kinds accept any value
+}
+
+// defName names the definition of a struct type after its package and type, e.g. "lrm.BuildStatus"
+func defName(t reflect.Type) string {
+	path := t.PkgPath()
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[i+1:]
+	}
+	if path == "" {
+		return t.Name()
+	}
+	return path + "." + t.Name()
+}
+
+// structSchema follows encoding/json: exported fields, json tag names, "-" and omitempty, and
+// embedded structs whose fields are promoted
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				promoted := g.structSchema(embedded)
+				for key, value := range promoted.Properties {
+					s.Properties[key] = value
+				}
+				s.Required = append(s.Required, promoted.Required...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = g.schemaOf(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
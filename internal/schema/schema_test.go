@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testBuild struct {
+	Version string
+	Tags    []string
+}
+
+type testKernel struct {
+	Name     string            `json:"name"`
+	Updated  time.Time         `json:"updated"`
+	Count    int               `json:"count"`
+	Ratio    float64           `json:"ratio,omitempty"`
+	Build    *testBuild        `json:"build"`
+	Builds   []testBuild       `json:"builds"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Internal string            `json:"-"`
+	hidden   string
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(reflect.TypeOf(testKernel{}), "/api/v1/schema/kernel", "Kernel")
+	if s.SchemaURI != Draft || s.ID != "/api/v1/schema/kernel" || s.Title != "Kernel" {
+		t.Errorf("Generate() header = %q %q %q", s.SchemaURI, s.ID, s.Title)
+	}
+	root := s.Defs["schema.testKernel"]
+	if s.Ref != "#/$defs/schema.testKernel" || root == nil {
+		t.Fatalf("Generate() should reference the named struct, got %+v", s)
+	}
+	if expected := []string{"build", "builds", "count", "name", "updated"}; !reflect.DeepEqual(root.Required, expected) {
+		t.Errorf("Required = %v, expected %v", root.Required, expected)
+	}
+	if _, ok := root.Properties["Internal"]; ok {
+		t.Errorf("Fields tagged json:\"-\" should not be described")
+	}
+	if _, ok := root.Properties["hidden"]; ok {
+		t.Errorf("Unexported fields should not be described")
+	}
+	if updated := root.Properties["updated"]; updated.Type != "string" || updated.Format != "date-time" {
+		t.Errorf("time.Time should be a date-time string, got %+v", updated)
+	}
+	if _, ok := s.Defs["schema.testBuild"]; !ok {
+		t.Errorf("Nested struct types should be defined once in $defs")
+	}
+	if _, err := json.Marshal(s); err != nil {
+		t.Errorf("Generated schema does not encode: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := Generate(reflect.TypeOf(testKernel{}), "", "")
+
+	valid := testKernel{Name: "linux", Updated: time.Now(), Builds: []testBuild{{Version: "1"}}}
+	violations, err := ValidateValue(s, valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) > 0 {
+		t.Errorf("ValidateValue() of an encoded value = %v, expected no violations", violations)
+	}
+
+	invalid := `{"name": 5, "updated": "yesterday", "count": 1.5, "build": null,
+		"builds": [{"Version": "1", "Tags": null, "Extra": true}]}`
+	violations, err = ValidateJSON(s, []byte(invalid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, violation := range violations {
+		got = append(got, violation.String())
+	}
+	expected := []string{
+		`/builds/0/Extra: unexpected property`,
+		`/count: expected integer, got number`,
+		`/name: expected string, got integer`,
+		`/updated: expected an RFC 3339 date-time, got "yesterday"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("ValidateJSON() = %q, expected %q", got, expected)
+	}
+
+	violations, _ = ValidateJSON(s, []byte(`{"name": "linux", "updated": "2025-10-01T00:00:00Z", "count": 0, "builds": []}`))
+	if len(violations) != 1 || violations[0].Message != `missing required property "build"` {
+		t.Errorf("ValidateJSON() of an incomplete object = %v", violations)
+	}
+}
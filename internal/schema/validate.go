@@ -0,0 +1,184 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Violation is a place where a JSON value does not match its schema
+type Violation struct {
+	Path    string `json:"path"` // JSON pointer of the value, e.g. "/Series/0/OutdatedDays"
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ValidateJSON checks encoded JSON against a schema
+func ValidateJSON(root *Schema, data []byte) ([]Violation, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return Validate(root, value), nil
+}
+
+// ValidateValue encodes a Go value and checks it against a schema
+func ValidateValue(root *Schema, value interface{}) ([]Violation, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateJSON(root, data)
+}
+
+// Validate checks a decoded JSON value (as produced by json.Unmarshal into an interface{})
+// against a schema, returning every violation found
+func Validate(root *Schema, value interface{}) []Violation {
+	v := &validator{root: root}
+	v.check(root, value, "")
+	if v.violations == nil {
+		return nil
+	}
+	return v.violations
+}
+
+type validator struct {
+	root       *Schema
+	violations []Violation
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	if path == "" {
+		path = "/"
+	}
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// resolve follows a reference into the $defs of the root document
+func (v *validator) resolve(s *Schema) *Schema {
+	for depth := 0; s.Ref != "" && depth < 16; depth++ {
+		name := strings.TrimPrefix(s.Ref, "#/$defs/")
+		def, ok := v.root.Defs[name]
+		if !ok {
+			return nil
+		}
+		s = def
+	}
+	return s
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == float64(int64(value)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+// allowedTypes returns the type names a schema allows; nil allows any type
+func allowedTypes(s *Schema) []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	case []interface{}: // A schema decoded from JSON
+		var names []string
+		for _, name := range t {
+			if name, ok := name.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+func typeAllowed(allowed []string, actual string) bool {
+	for _, name := range allowed {
+		if name == actual || (name == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) check(s *Schema, value interface{}, path string) {
+	if s.Ref != "" {
+		if value == nil {
+			return // Pointers to named structs encode as null
+		}
+		resolved := v.resolve(s)
+		if resolved == nil {
+			v.fail(path, "unresolved reference %s", s.Ref)
+			return
+		}
+		s = resolved
+	}
+
+	actual := jsonType(value)
+	if allowed := allowedTypes(s); allowed != nil && !typeAllowed(allowed, actual) {
+		v.fail(path, "expected %s, got %s", strings.Join(allowed, " or "), actual)
+		return
+	}
+
+	switch value := value.(type) {
+	case string:
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+				v.fail(path, "expected an RFC 3339 date-time, got %q", value)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range value {
+				v.check(s.Items, item, fmt.Sprintf("%s/%d", path, i))
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				v.fail(path, "missing required property %q", name)
+			}
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+			if property, ok := s.Properties[key]; ok {
+				v.check(property, value[key], childPath)
+				continue
+			}
+			switch additional := s.AdditionalProperties.(type) {
+			case bool:
+				if !additional {
+					v.fail(childPath, "unexpected property")
+				}
+			case *Schema:
+				v.check(additional, value[key], childPath)
+			}
+		}
+	}
+}
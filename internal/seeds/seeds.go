@@ -0,0 +1,101 @@
+// Package seeds reads the restricted seed of the Ubuntu platform seeds, which lists the NVIDIA
+// driver and restricted kernel modules installed by default, to tell which driver branch is the
+// default one of each series.
+package seeds
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"nvidia_driver_monitor/internal/utils"
+)
+
+var (
+	// driverPattern matches the seeded driver metapackage and captures its branch,
+	// e.g. "nvidia-driver-570" or "nvidia-driver-570-server"
+	driverPattern = regexp.MustCompile(`^nvidia-driver-(\d{3}(?:-server)?)$`)
+	// modulesPattern matches the restricted modules metapackage of a kernel flavour and captures
+	// the branch and the flavour, e.g. "linux-modules-nvidia-570-generic-hwe-24.04"
+	modulesPattern = regexp.MustCompile(`^linux-modules-nvidia-(\d{3}(?:-server)?)-([a-z0-9.+-]+)$`)
+)
+
+// Flavour is a seeded kernel flavour and the driver branch of its restricted modules
+type Flavour struct {
+	Name   string `json:"name"` // e.g. "generic" or "generic-hwe-24.04"
+	Branch string `json:"branch"`
+}
+
+// Seed is the NVIDIA part of the restricted seed of a series
+type Seed struct {
+	Series   string    `json:"series"`
+	Drivers  []string  `json:"drivers"`  // Branches of the seeded nvidia-driver packages, in seed order
+	Flavours []Flavour `json:"flavours"` // Flavours with seeded restricted modules, in seed order
+	// DefaultBranch is the branch installed by default: the first seeded driver, else the
+	// branch of the generic flavour's modules, else of the first seeded flavour
+	DefaultBranch string `json:"default_branch"`
+}
+
+// SeedURL returns the restricted seed of a series, e.g.
+// https://git.launchpad.net/~ubuntu-core-dev/ubuntu-seeds/+git/platform/plain/restricted?h=noble
+func SeedURL(base, series string) string {
+	return fmt.Sprintf("%s/restricted?h=%s", strings.TrimSuffix(base, "/"), series)
+}
+
+// ParseSeed reads a seed (" * package [arch] # comment" per entry, parenthesized when only
+// recommended) and returns the NVIDIA drivers and restricted modules it lists
+func ParseSeed(series string, r io.Reader) (*Seed, error) {
+	seed := &Seed{Series: series, Drivers: []string{}, Flavours: []Flavour{}}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "* ") {
+			continue
+		}
+		line, _, _ = strings.Cut(line[2:], "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.Trim(fields[0], "()")
+		if match := driverPattern.FindStringSubmatch(name); match != nil {
+			seed.Drivers = append(seed.Drivers, match[1])
+		} else if match := modulesPattern.FindStringSubmatch(name); match != nil {
+			seed.Flavours = append(seed.Flavours, Flavour{Name: match[2], Branch: match[1]})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read seed: %w", err)
+	}
+
+	switch {
+	case len(seed.Drivers) > 0:
+		seed.DefaultBranch = seed.Drivers[0]
+	case len(seed.Flavours) > 0:
+		seed.DefaultBranch = seed.Flavours[0].Branch
+		for _, flavour := range seed.Flavours {
+			if flavour.Name == "generic" {
+				seed.DefaultBranch = flavour.Branch
+				break
+			}
+		}
+	}
+	return seed, nil
+}
+
+// FetchSeed downloads and parses the restricted seed of a series
+func FetchSeed(base, series string) (*Seed, error) {
+	url := SeedURL(base, series)
+	resp, err := utils.HTTPGetWithRetry(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status code %d", url, resp.StatusCode)
+	}
+	return ParseSeed(series, resp.Body)
+}
@@ -0,0 +1,63 @@
+package seeds
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const nobleSeed = `= NVIDIA =
+
+ * nvidia-driver-570 [amd64 arm64]  # ubuntu-drivers default
+ * (nvidia-driver-570-server) [amd64]
+ * linux-modules-nvidia-570-generic [amd64]
+ * (linux-modules-nvidia-570-generic-hwe-24.04) [amd64]
+ * nvidia-settings
+Task-Description: restricted drivers
+`
+
+func TestParseSeed(t *testing.T) {
+	seed, err := ParseSeed("noble", strings.NewReader(nobleSeed))
+	if err != nil {
+		t.Fatalf("ParseSeed() returned error: %v", err)
+	}
+	if len(seed.Drivers) != 2 || seed.Drivers[1] != "570-server" {
+		t.Errorf("Drivers = %v, expected 570 and the recommended 570-server", seed.Drivers)
+	}
+	if len(seed.Flavours) != 2 || seed.Flavours[1] != (Flavour{Name: "generic-hwe-24.04", Branch: "570"}) {
+		t.Errorf("Flavours = %+v, expected generic and generic-hwe-24.04", seed.Flavours)
+	}
+	if seed.DefaultBranch != "570" {
+		t.Errorf("DefaultBranch = %q, expected the first seeded driver", seed.DefaultBranch)
+	}
+}
+
+func TestParseSeedDefaultsToTheGenericFlavour(t *testing.T) {
+	seed, err := ParseSeed("jammy", strings.NewReader(" * linux-modules-nvidia-535-server-lowlatency\n * linux-modules-nvidia-550-generic\n"))
+	if err != nil || seed.DefaultBranch != "550" {
+		t.Errorf("ParseSeed() = %+v, %v, expected the generic flavour's branch", seed, err)
+	}
+	if seed, _ := ParseSeed("jammy", strings.NewReader("= Empty =\n")); seed.DefaultBranch != "" {
+		t.Errorf("DefaultBranch = %q without NVIDIA entries, expected none", seed.DefaultBranch)
+	}
+}
+
+func TestFetchSeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/restricted" || r.URL.Query().Get("h") != "noble" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(nobleSeed))
+	}))
+	defer server.Close()
+
+	seed, err := FetchSeed(server.URL+"/", "noble")
+	if err != nil || seed.DefaultBranch != "570" {
+		t.Errorf("FetchSeed() = %+v, %v, expected 570 as default", seed, err)
+	}
+	if _, err := FetchSeed(server.URL, "jammy"); err == nil {
+		t.Error("FetchSeed() of a missing series should return an error")
+	}
+}
@@ -0,0 +1,153 @@
+package slo
+
+import (
+	"sort"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/history"
+)
+
+// SLO states of the latest upstream release, from best to worst
+const (
+	StateNoData   = "no-data"  // No upstream release has been observed
+	StateMet      = "met"      // Published within the target
+	StatePending  = "pending"  // Not published yet but still within the target
+	StateBreached = "breached" // Published late, or still unpublished past the target
+)
+
+// SeriesStatus is the SLO evaluation of one series of a branch
+type SeriesStatus struct {
+	Series    string `json:"series"`
+	State     string `json:"state"`
+	Upstream  string `json:"upstream,omitempty"`  // Upstream version currently being waited for
+	DaysOpen  int    `json:"days_open,omitempty"` // Days since that upstream release
+	Evaluated int    `json:"evaluated"`           // Upstream releases counted in the compliance ratio
+	Met       int    `json:"met"`                 // Of those, published within the target
+}
+
+// Status is the SLO evaluation of a branch across its series
+type Status struct {
+	Branch      string         `json:"branch"`
+	Description string         `json:"description"`
+	TargetDays  int            `json:"target_days"`
+	WindowDays  int            `json:"window_days"`
+	State       string         `json:"state"`
+	Compliance  float64        `json:"compliance"` // Ratio of evaluated releases that met the target; 1 when none were evaluated
+	Evaluated   int            `json:"evaluated"`
+	Met         int            `json:"met"`
+	Series      []SeriesStatus `json:"series"`
+}
+
+// CompliancePercent returns the compliance ratio as a rounded percentage
+func (s *Status) CompliancePercent() int {
+	return int(s.Compliance*100 + 0.5)
+}
+
+// stateRank orders states so the worst series state becomes the branch state
+var stateRank = map[string]int{StateNoData: 0, StateMet: 1, StatePending: 2, StateBreached: 3}
+
+// Evaluate computes SLO compliance of a branch from the daily history of its series
+func Evaluate(branch string, target *config.SLOTarget, observations map[string][]history.Observation, now time.Time) *Status {
+	status := &Status{
+		Branch:      branch,
+		Description: target.Description,
+		TargetDays:  target.MaxDays,
+		WindowDays:  target.GetWindowDays(),
+		State:       StateNoData,
+		Series:      make([]SeriesStatus, 0, len(observations)),
+	}
+
+	windowStart := now.AddDate(0, 0, -status.WindowDays)
+	for series, rows := range observations {
+		ss := evaluateSeries(series, rows, target.MaxDays, windowStart, now)
+		status.Evaluated += ss.Evaluated
+		status.Met += ss.Met
+		if stateRank[ss.State] > stateRank[status.State] {
+			status.State = ss.State
+		}
+		status.Series = append(status.Series, ss)
+	}
+	sort.Slice(status.Series, func(i, j int) bool { return status.Series[i].Series < status.Series[j].Series })
+
+	status.Compliance = 1
+	if status.Evaluated > 0 {
+		status.Compliance = float64(status.Met) / float64(status.Evaluated)
+	}
+	return status
+}
+
+// upstreamRelease tracks one upstream version seen in a series history
+type upstreamRelease struct {
+	version    string
+	releasedAt time.Time
+	resolvedAt time.Time // First day the published version caught up; zero when it never did
+}
+
+// evaluateSeries groups observations by upstream version and measures how long each took to be published
+func evaluateSeries(series string, rows []history.Observation, targetDays int, windowStart, now time.Time) SeriesStatus {
+	ss := SeriesStatus{Series: series, State: StateNoData}
+
+	var releases []*upstreamRelease
+	byVersion := make(map[string]*upstreamRelease)
+	for _, row := range rows {
+		if row.Upstream == "" || row.Upstream == "-" {
+			continue
+		}
+		day, err := time.Parse(history.DateFormat, row.Date)
+		if err != nil {
+			continue
+		}
+
+		release, ok := byVersion[row.Upstream]
+		if !ok {
+			releasedAt := day
+			if upstreamDay, err := time.Parse(history.DateFormat, row.UpstreamDate); err == nil && upstreamDay.Before(day) {
+				releasedAt = upstreamDay
+			}
+			release = &upstreamRelease{version: row.Upstream, releasedAt: releasedAt}
+			byVersion[row.Upstream] = release
+			releases = append(releases, release)
+		}
+		if !row.Outdated && release.resolvedAt.IsZero() {
+			release.resolvedAt = day
+		}
+	}
+
+	target := time.Duration(targetDays) * 24 * time.Hour
+	for i, release := range releases {
+		current := i == len(releases)-1
+		resolved := !release.resolvedAt.IsZero()
+		withinTarget := resolved && release.resolvedAt.Sub(release.releasedAt) <= target
+		elapsed := now.Sub(release.releasedAt)
+
+		// The state reflects the latest upstream release; older ones only affect compliance
+		if current {
+			switch {
+			case withinTarget:
+				ss.State = StateMet
+			case !resolved && elapsed <= target:
+				ss.State = StatePending
+			default:
+				ss.State = StateBreached
+			}
+			if !resolved {
+				ss.Upstream = release.version
+				ss.DaysOpen = int(elapsed.Hours() / 24)
+			}
+		}
+
+		if release.releasedAt.Before(windowStart) {
+			continue
+		}
+		// Releases still waiting within the target are not counted until they resolve or breach
+		if !resolved && current && elapsed <= target {
+			continue
+		}
+		ss.Evaluated++
+		if withinTarget {
+			ss.Met++
+		}
+	}
+	return ss
+}
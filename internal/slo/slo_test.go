@@ -0,0 +1,80 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/history"
+)
+
+func observe(date, upstream, upstreamDate string, outdated bool) history.Observation {
+	return history.Observation{Date: date, Upstream: upstream, UpstreamDate: upstreamDate, Outdated: outdated}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	target := &config.SLOTarget{Branch: "570", MaxDays: 7}
+
+	tests := []struct {
+		name          string
+		rows          []history.Observation
+		expectedState string
+		evaluated     int
+		met           int
+	}{
+		{"no data", nil, StateNoData, 0, 0},
+		{"published within target", []history.Observation{
+			observe("2026-10-01", "570.195.03", "2026-10-01", true),
+			observe("2026-10-05", "570.195.03", "2026-10-01", false),
+		}, StateMet, 1, 1},
+		{"published late", []history.Observation{
+			observe("2026-10-01", "570.195.03", "2026-10-01", true),
+			observe("2026-10-12", "570.195.03", "2026-10-01", false),
+		}, StateBreached, 1, 0},
+		{"waiting within target", []history.Observation{
+			observe("2026-10-14", "570.195.03", "2026-10-14", true),
+		}, StatePending, 0, 0},
+		{"waiting past target", []history.Observation{
+			observe("2026-10-01", "570.195.03", "2026-10-01", true),
+			observe("2026-10-17", "570.195.03", "2026-10-01", true),
+		}, StateBreached, 1, 0},
+		{"superseded release counts as missed", []history.Observation{
+			observe("2026-09-01", "570.181", "2026-09-01", true),
+			observe("2026-09-20", "570.195.03", "2026-09-20", true),
+			observe("2026-09-22", "570.195.03", "2026-09-20", false),
+		}, StateMet, 2, 1},
+		{"releases outside the window are ignored", []history.Observation{
+			observe("2026-01-01", "570.133.07", "2026-01-01", true),
+			observe("2026-02-01", "570.133.07", "2026-01-01", false),
+		}, StateBreached, 0, 0},
+	}
+
+	for _, tt := range tests {
+		status := Evaluate("570", target, map[string][]history.Observation{"noble": tt.rows}, now)
+		if status.State != tt.expectedState || status.Evaluated != tt.evaluated || status.Met != tt.met {
+			t.Errorf("Evaluate(%s) = %s %d/%d, expected %s %d/%d", tt.name,
+				status.State, status.Met, status.Evaluated, tt.expectedState, tt.met, tt.evaluated)
+		}
+	}
+}
+
+func TestEvaluateUsesWorstSeriesState(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	target := &config.SLOTarget{Branch: "*", MaxDays: 7}
+
+	status := Evaluate("570", target, map[string][]history.Observation{
+		"noble": {observe("2026-10-01", "570.195.03", "2026-10-01", false)},
+		"jammy": {observe("2026-10-01", "570.195.03", "2026-10-01", true)},
+	}, now)
+
+	if status.State != StateBreached {
+		t.Errorf("Evaluate() state = %s, expected %s", status.State, StateBreached)
+	}
+	if status.CompliancePercent() != 50 {
+		t.Errorf("CompliancePercent() = %d, expected 50", status.CompliancePercent())
+	}
+	if len(status.Series) != 2 || status.Series[0].Series != "jammy" || status.Series[0].DaysOpen != 16 {
+		t.Errorf("Evaluate() series = %+v, expected jammy open for 16 days first", status.Series)
+	}
+}
@@ -2,11 +2,11 @@ package sru
 
 import (
 	"fmt"
-	"io"
 	"sort"
 	"time"
 
 	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/faults"
 	"nvidia_driver_monitor/internal/utils"
 
 	"gopkg.in/yaml.v2"
@@ -29,6 +29,15 @@ func GetSRUCycleURL() string {
 	return "https://kernel.ubuntu.com/forgejo/kernel/kernel-versions/raw/branch/main/info/sru-cycle.yaml" // fallback
 }
 
+// GetSRUCycleURLs returns the SRU cycle URL followed by its configured mirrors
+func GetSRUCycleURLs() []string {
+	if sruConfig != nil {
+		effectiveURLs := sruConfig.GetEffectiveURLs()
+		return effectiveURLs.Kernel.SRUCycleURLs()
+	}
+	return []string{GetSRUCycleURL()}
+}
+
 // SRUCycle represents a single SRU cycle entry
 type SRUCycle struct {
 	Name           string    `yaml:"-"` // The cycle name (extracted from map key)
@@ -48,73 +57,102 @@ type SRUCycle struct {
 // SRUCycles holds a collection of SRU cycles
 type SRUCycles struct {
 	Cycles []SRUCycle
+	// Warnings describe the entries of sru-cycle.yaml that were skipped because they are invalid
+	Warnings []string `json:",omitempty"`
+	// Estimated is set on the fallback cycles used while sru-cycle.yaml cannot be loaded
+	Estimated bool `json:",omitempty"`
 }
 
 // FetchSRUCycles fetches and parses SRU cycles from the Ubuntu kernel repository
 func FetchSRUCycles() (*SRUCycles, error) {
-	url := GetSRUCycleURL()
-
-	resp, err := utils.HTTPGetWithRetry(url)
+	if err := faults.Check(faults.StageSRU); err != nil {
+		return nil, err
+	}
+	body, err := utils.FetchYAMLFromMirrors("sru-cycle.yaml", GetSRUCycleURLs())
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch SRU cycles: %w", err)
 	}
-	defer resp.Body.Close()
+	return ParseSRUCycles(body)
+}
+
+// ParseSRUCycles parses sru-cycle.yaml entry by entry. Invalid entries are skipped and
+// described in Warnings; only a document that is not a mapping or has no valid entry fails.
+func ParseSRUCycles(body []byte) (*SRUCycles, error) {
+	// Parse YAML into an ordered map, leaving the entries to be decoded one by one
+	var entries yaml.MapSlice
+	if err := yaml.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("HTTP error: %d", resp.StatusCode)
+	// Convert map to slice and add cycle names
+	var cycles []SRUCycle
+	var warnings []string
+	for _, entry := range entries {
+		name := fmt.Sprint(entry.Key)
+		cycle, err := parseCycle(name, entry.Value)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipped cycle %q: %v", name, err))
+			continue
+		}
+		cycles = append(cycles, *cycle)
+	}
+	if len(cycles) == 0 && len(warnings) > 0 {
+		return nil, fmt.Errorf("no valid SRU cycle in %d entries: %s", len(warnings), warnings[0])
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	// Sort by release date (newest first)
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i].ParsedDate.After(cycles[j].ParsedDate)
+	})
+
+	return &SRUCycles{Cycles: cycles, Warnings: warnings}, nil
+}
+
+// parseCycle decodes one entry of sru-cycle.yaml and fills in the derived fields
+func parseCycle(name string, value interface{}) (*SRUCycle, error) {
+	if _, ok := value.(yaml.MapSlice); !ok {
+		return nil, fmt.Errorf("entry is not a mapping")
+	}
+	raw, err := yaml.Marshal(value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
-	if err := utils.ValidateYAMLResponse(resp, body, "SRU cycle YAML"); err != nil {
+	var cycle SRUCycle
+	if err := yaml.Unmarshal(raw, &cycle); err != nil {
 		return nil, err
 	}
+	cycle.Name = name
 
-	// Parse YAML into a map
-	var cycleMap map[string]SRUCycle
-	if err := yaml.Unmarshal(body, &cycleMap); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	// Parse release date for sorting
+	if cycle.ReleaseDate != "" {
+		parsedDate, err := time.Parse("2006-01-02", cycle.ReleaseDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid release-date %q", cycle.ReleaseDate)
+		}
+		cycle.ParsedDate = parsedDate
 	}
-
-	// Convert map to slice and add cycle names
-	var cycles []SRUCycle
-	for name, cycle := range cycleMap {
-		cycle.Name = name
-
-		// Parse release date for sorting
-		if cycle.ReleaseDate != "" {
-			if parsedDate, err := time.Parse("2006-01-02", cycle.ReleaseDate); err == nil {
-				cycle.ParsedDate = parsedDate
-			}
+	if cycle.CutoffDate != "" {
+		if _, err := time.Parse("2006-01-02", cycle.CutoffDate); err != nil {
+			return nil, fmt.Errorf("invalid cutoff-date %q", cycle.CutoffDate)
 		}
+	}
 
-		// Set default stream if not specified
-		if cycle.Stream == 0 {
-			cycle.Stream = 1
-		}
+	// Set default stream if not specified
+	if cycle.Stream == 0 {
+		cycle.Stream = 1
+	}
 
-		// If CutoffDate is empty, calculate as Name minus 5 days (Name format: YYYY.MM.DD)
-		if cycle.CutoffDate == "" {
-			// Try to parse the date from the Name
-			if len(name) >= 10 {
-				if t, err := time.Parse("2006.01.02", name[:10]); err == nil {
-					cutoff := t.AddDate(0, 0, -5)
-					cycle.CutoffDate = cutoff.Format("2006-01-02")
-				}
+	// If CutoffDate is empty, calculate as Name minus 5 days (Name format: YYYY.MM.DD)
+	if cycle.CutoffDate == "" {
+		// Try to parse the date from the Name
+		if len(name) >= 10 {
+			if t, err := time.Parse("2006.01.02", name[:10]); err == nil {
+				cutoff := t.AddDate(0, 0, -5)
+				cycle.CutoffDate = cutoff.Format("2006-01-02")
 			}
 		}
-
-		cycles = append(cycles, cycle)
 	}
-
-	// Sort by release date (newest first)
-	sort.Slice(cycles, func(i, j int) bool {
-		return cycles[i].ParsedDate.After(cycles[j].ParsedDate)
-	})
-
-	return &SRUCycles{Cycles: cycles}, nil
+	return &cycle, nil
 }
 
 // PrintSRUCycles prints all SRU cycles in a formatted table
@@ -210,6 +248,9 @@ func (sru *SRUCycles) AddPredictedCycles() {
 	}
 
 	// Parse the name date (format: YYYY.MM.DD)
+	if len(newest.Name) < 10 {
+		return
+	}
 	baseNameDate, err := time.Parse("2006.01.02", newest.Name[:10])
 	if err != nil {
 		return
@@ -291,7 +332,7 @@ func (sru *SRUCycles) GetMinimumCutoffAfterDate(driverReleaseDate string) *SRUCy
 // This is used when the external SRU cycles service is unavailable
 func CreateFallbackSRUCycles() *SRUCycles {
 	now := time.Now()
-	cycles := &SRUCycles{}
+	cycles := &SRUCycles{Estimated: true}
 
 	// Create estimated SRU cycles for the next 12 months
 	// SRU cycles typically happen every 3 weeks (21 days)
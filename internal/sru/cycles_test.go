@@ -0,0 +1,62 @@
+package sru
+
+import (
+	"strings"
+	"testing"
+)
+
+const cyclesYAML = `# sru-cycle.yaml
+'2026.10.12':
+  release-date: '2026-11-02'
+  cutoff-date: '2026-10-07'
+  stream: 2
+'s2026.09.14':
+  release-date: '2026-10-05'
+'2026.09.14':
+  release-date: 'next monday'
+'2026.08.17':
+  release-date: '2026-09-07'
+  stream: many
+'2026.07.20': on hold
+'2026.06.22':
+  release-date: '2026-07-13'
+`
+
+func TestParseSRUCyclesSkipsInvalidEntries(t *testing.T) {
+	cycles, err := ParseSRUCycles([]byte(cyclesYAML))
+	if err != nil {
+		t.Fatalf("ParseSRUCycles() returned error: %v", err)
+	}
+
+	var names []string
+	for _, cycle := range cycles.Cycles {
+		names = append(names, cycle.Name)
+	}
+	if got := strings.Join(names, ","); got != "2026.10.12,s2026.09.14,2026.06.22" {
+		t.Errorf("cycles = %s, expected the valid entries newest first", got)
+	}
+	if cycles.Cycles[0].Stream != 2 || cycles.Cycles[2].Stream != 1 || cycles.Cycles[2].CutoffDate != "2026-06-17" {
+		t.Errorf("cycles = %+v, expected streams and derived cutoff dates kept", cycles.Cycles)
+	}
+
+	if len(cycles.Warnings) != 3 {
+		t.Fatalf("Warnings = %q, expected the 3 invalid entries", cycles.Warnings)
+	}
+	for i, want := range []string{`"2026.09.14": invalid release-date "next monday"`, `"2026.08.17"`, `"2026.07.20": entry is not a mapping`} {
+		if !strings.Contains(cycles.Warnings[i], want) {
+			t.Errorf("Warnings[%d] = %q, expected it to mention %s", i, cycles.Warnings[i], want)
+		}
+	}
+}
+
+func TestParseSRUCyclesFailsWithoutValidEntries(t *testing.T) {
+	if _, err := ParseSRUCycles([]byte("'2026.07.20': on hold\n")); err == nil {
+		t.Error("ParseSRUCycles() should fail when no entry is valid")
+	}
+	if _, err := ParseSRUCycles([]byte("- not\n- a mapping\n")); err == nil {
+		t.Error("ParseSRUCycles() should fail on a document that is not a mapping")
+	}
+	if cycles := CreateFallbackSRUCycles(); !cycles.Estimated {
+		t.Error("fallback cycles should be marked estimated")
+	}
+}
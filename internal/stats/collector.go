@@ -4,13 +4,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/supervise"
 )
 
+// legacyWindowSize is the window of data files saved before the window became configurable
+const legacyWindowSize = 10 * time.Minute
+
 // APIStats represents statistics for API calls
 type APIStats struct {
 	Domain          string        `json:"domain"`          // e.g., "launchpad.net", "nvidia.com", "kernel.ubuntu.com"
@@ -20,9 +28,27 @@ type APIStats struct {
 	TotalRetries    int64         `json:"total_retries"`   // Total number of retries across all requests
 	AverageRespTime float64       `json:"avg_response_ms"` // Average response time in milliseconds
 	TotalRespTime   time.Duration `json:"-"`               // Internal: sum of all response times
+	// BytesTransferred is the size of the response bodies read, counted once the body is closed
+	BytesTransferred int64 `json:"bytes_transferred"`
+	// StatusCodes counts the responses per HTTP status code, e.g. "200" or "503"; requests that
+	// got no response at all only count as failed
+	StatusCodes map[string]int64 `json:"status_codes,omitempty"`
 }
 
-// TimeWindow represents a 10-minute window of statistics
+// copyStats returns a copy of stats that does not share the status code counts
+func copyStats(stats *APIStats) *APIStats {
+	copied := *stats
+	copied.StatusCodes = nil
+	for code, count := range stats.StatusCodes {
+		if copied.StatusCodes == nil {
+			copied.StatusCodes = make(map[string]int64, len(stats.StatusCodes))
+		}
+		copied.StatusCodes[code] = count
+	}
+	return &copied
+}
+
+// TimeWindow represents a window of statistics, 10 minutes by default
 type TimeWindow struct {
 	StartTime time.Time            `json:"start_time"`
 	EndTime   time.Time            `json:"end_time"`
@@ -32,8 +58,10 @@ type TimeWindow struct {
 // StatsCollector manages API statistics collection
 type StatsCollector struct {
 	mu           sync.RWMutex
-	windows      []*TimeWindow // Last 100 windows (1000 minutes of data)
+	windows      []*TimeWindow // Last maxWindows windows
 	currentWin   *TimeWindow
+	totals       map[string]*APIStats // Domain -> statistics since the process started, for the metrics
+	window       time.Duration        // Duration of each window
 	maxWindows   int
 	persistFile  string // Path to persistence file
 	saveInterval time.Duration
@@ -42,18 +70,42 @@ type StatsCollector struct {
 var (
 	globalCollector *StatsCollector
 	once            sync.Once
+	statsConfig     config.StatsConfig // Settings of the global collector
 )
 
+// SetStatsConfig sets the configuration of the global collector. It only takes effect when
+// called before the first request is recorded.
+func SetStatsConfig(cfg *config.Config) {
+	if cfg != nil {
+		statsConfig = cfg.Stats
+	}
+}
+
+// newCollector creates a collector without loading data or starting its goroutines
+func newCollector(persistFile string, window time.Duration, maxWindows int, saveInterval time.Duration) *StatsCollector {
+	return &StatsCollector{
+		window:       window,
+		maxWindows:   maxWindows,
+		persistFile:  persistFile,
+		saveInterval: saveInterval,
+		windows:      make([]*TimeWindow, 0, maxWindows),
+		totals:       make(map[string]*APIStats),
+	}
+}
+
+// NewMemoryCollector creates a collector with an open window that is neither loaded from nor
+// saved to a file, e.g. for tests
+func NewMemoryCollector(window time.Duration, maxWindows int) *StatsCollector {
+	sc := newCollector("", window, maxWindows, 0)
+	sc.startNewWindow()
+	return sc
+}
+
 // GetStatsCollector returns the global statistics collector instance
 func GetStatsCollector() *StatsCollector {
 	once.Do(func() {
-		persistFile := "statistics_data.json"
-		globalCollector = &StatsCollector{
-			maxWindows:   100,
-			persistFile:  persistFile,
-			saveInterval: 5 * time.Minute, // Save every 5 minutes
-			windows:      make([]*TimeWindow, 0, 100),
-		}
+		globalCollector = newCollector(statsConfig.GetDataFile(), statsConfig.GetWindow(),
+			statsConfig.GetRetention(), statsConfig.GetSaveInterval())
 
 		// Load existing data if available
 		if err := globalCollector.loadFromFile(); err != nil {
@@ -71,26 +123,26 @@ func GetStatsCollector() *StatsCollector {
 	return globalCollector
 }
 
-// startNewWindow creates a new 10-minute time window
+// startNewWindow creates a new time window
 func (sc *StatsCollector) startNewWindow() {
 	now := time.Now()
 	sc.currentWin = &TimeWindow{
 		StartTime: now,
-		EndTime:   now.Add(10 * time.Minute),
+		EndTime:   now.Add(sc.window),
 		Stats:     make(map[string]*APIStats),
 	}
 }
 
-// startWindowRotation starts a goroutine that rotates windows every 10 minutes
+// startWindowRotation starts a goroutine that rotates windows at the end of each window
 func (sc *StatsCollector) startWindowRotation() {
-	go func() {
-		ticker := time.NewTicker(10 * time.Minute)
+	supervise.Loop("stats-rotation", func() {
+		ticker := time.NewTicker(sc.window)
 		defer ticker.Stop()
 
 		for range ticker.C {
 			sc.rotateWindow()
 		}
-	}()
+	})
 }
 
 // rotateWindow moves current window to history and starts a new one
@@ -101,7 +153,7 @@ func (sc *StatsCollector) rotateWindow() {
 	// Add current window to history
 	sc.windows = append(sc.windows, sc.currentWin)
 
-	// Keep only the last maxWindows (100)
+	// Keep only the last maxWindows
 	if len(sc.windows) > sc.maxWindows {
 		sc.windows = sc.windows[1:]
 	}
@@ -117,8 +169,37 @@ func (sc *StatsCollector) rotateWindow() {
 	}()
 }
 
+// mockTag marks the domains of requests served by a local mock server
+const mockTag = " [MOCK]"
+
+// mockDomain categorizes a request to the mock server by the upstream it stands in for, from
+// the first path segment after an optional /scenarios/<name> prefix, e.g. "launchpad [MOCK]"
+func mockDomain(rawURL string) string {
+	var segments []string
+	if parsed, err := url.Parse(rawURL); err == nil {
+		segments = strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	}
+	if len(segments) > 2 && segments[0] == "scenarios" {
+		segments = segments[2:]
+	}
+	upstream := "mock"
+	if len(segments) > 0 {
+		switch segments[0] {
+		case "launchpad", "nvidia", "ubuntu":
+			upstream = segments[0]
+		case "kernel":
+			upstream = "ubuntu-kernel"
+		}
+	}
+	return upstream + mockTag
+}
+
 // extractDomain extracts domain from URL for categorization
 func extractDomain(url string) string {
+	if config.IsLocalURL(url) {
+		return mockDomain(url)
+	}
+
 	// Simple domain extraction
 	if len(url) < 8 {
 		return "unknown"
@@ -144,7 +225,7 @@ func extractDomain(url string) string {
 	domain := url[start:end]
 
 	// Categorize known domains - use contains for more robust matching
-	if strings.Contains(domain, "launchpad.net") || domain == "localhost" && strings.Contains(url, "launchpad") {
+	if strings.Contains(domain, "launchpad.net") {
 		return "launchpad"
 	} else if strings.Contains(domain, "nvidia.com") {
 		return "nvidia"
@@ -155,38 +236,72 @@ func extractDomain(url string) string {
 	return domain
 }
 
-// RecordRequest records an API request with its outcome
-func (sc *StatsCollector) RecordRequest(url string, duration time.Duration, retries int, success bool) {
+// domainStats returns the statistics of a domain in the current window and since start,
+// creating them as needed
+func (sc *StatsCollector) domainStats(domain string) (window, total *APIStats) {
+	if sc.currentWin.Stats[domain] == nil {
+		sc.currentWin.Stats[domain] = &APIStats{Domain: domain}
+	}
+	if sc.totals[domain] == nil {
+		sc.totals[domain] = &APIStats{Domain: domain}
+	}
+	return sc.currentWin.Stats[domain], sc.totals[domain]
+}
+
+// RecordRequest records an API request with its outcome. statusCode is 0 when no response was
+// received.
+func (sc *StatsCollector) RecordRequest(url string, duration time.Duration, retries int, success bool, statusCode int) {
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 
-	domain := extractDomain(url)
+	window, total := sc.domainStats(extractDomain(url))
+	for _, stats := range []*APIStats{window, total} {
+		stats.TotalRequests++
+		stats.TotalRetries += int64(retries)
+		stats.TotalRespTime += duration
 
-	// Get or create stats for this domain in current window
-	if sc.currentWin.Stats[domain] == nil {
-		sc.currentWin.Stats[domain] = &APIStats{
-			Domain: domain,
+		// Calculate average response time
+		if stats.TotalRequests > 0 {
+			stats.AverageRespTime = float64(stats.TotalRespTime.Nanoseconds()) / float64(stats.TotalRequests) / 1e6 // Convert to milliseconds
+		}
+
+		if success {
+			stats.SuccessfulReqs++
+		} else {
+			stats.FailedReqs++
+		}
+		if statusCode > 0 {
+			if stats.StatusCodes == nil {
+				stats.StatusCodes = make(map[string]int64)
+			}
+			stats.StatusCodes[strconv.Itoa(statusCode)]++
 		}
 	}
+}
 
-	stats := sc.currentWin.Stats[domain]
-	stats.TotalRequests++
-	stats.TotalRetries += int64(retries)
-	stats.TotalRespTime += duration
+// RecordBytes records the size of a response body read from url
+func (sc *StatsCollector) RecordBytes(url string, bytes int64) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
 
-	// Calculate average response time
-	if stats.TotalRequests > 0 {
-		stats.AverageRespTime = float64(stats.TotalRespTime.Nanoseconds()) / float64(stats.TotalRequests) / 1e6 // Convert to milliseconds
-	}
+	window, total := sc.domainStats(extractDomain(url))
+	window.BytesTransferred += bytes
+	total.BytesTransferred += bytes
+}
+
+// GetTotals returns the statistics of each domain since the process started
+func (sc *StatsCollector) GetTotals() map[string]*APIStats {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
 
-	if success {
-		stats.SuccessfulReqs++
-	} else {
-		stats.FailedReqs++
+	result := make(map[string]*APIStats, len(sc.totals))
+	for domain, stats := range sc.totals {
+		result[domain] = copyStats(stats)
 	}
+	return result
 }
 
-// GetCurrentWindowStats returns statistics for the current 10-minute window
+// GetCurrentWindowStats returns statistics for the current window
 func (sc *StatsCollector) GetCurrentWindowStats() map[string]*APIStats {
 	sc.mu.RLock()
 	defer sc.mu.RUnlock()
@@ -194,14 +309,7 @@ func (sc *StatsCollector) GetCurrentWindowStats() map[string]*APIStats {
 	// Create a copy to avoid race conditions
 	result := make(map[string]*APIStats)
 	for domain, stats := range sc.currentWin.Stats {
-		result[domain] = &APIStats{
-			Domain:          stats.Domain,
-			TotalRequests:   stats.TotalRequests,
-			SuccessfulReqs:  stats.SuccessfulReqs,
-			FailedReqs:      stats.FailedReqs,
-			TotalRetries:    stats.TotalRetries,
-			AverageRespTime: stats.AverageRespTime,
-		}
+		result[domain] = copyStats(stats)
 	}
 
 	return result
@@ -223,14 +331,7 @@ func (sc *StatsCollector) GetAllWindowsStats() []*TimeWindow {
 
 		// Copy stats
 		for domain, stats := range window.Stats {
-			result[i].Stats[domain] = &APIStats{
-				Domain:          stats.Domain,
-				TotalRequests:   stats.TotalRequests,
-				SuccessfulReqs:  stats.SuccessfulReqs,
-				FailedReqs:      stats.FailedReqs,
-				TotalRetries:    stats.TotalRetries,
-				AverageRespTime: stats.AverageRespTime,
-			}
+			result[i].Stats[domain] = copyStats(stats)
 		}
 	}
 
@@ -254,6 +355,7 @@ type PersistentData struct {
 	Windows    []*TimeWindow `json:"windows"`
 	CurrentWin *TimeWindow   `json:"current_window"`
 	SavedAt    time.Time     `json:"saved_at"`
+	WindowSize string        `json:"window_size,omitempty"` // e.g. "10m"; files without it use legacyWindowSize
 }
 
 // saveToFile saves current statistics to a JSON file
@@ -265,6 +367,7 @@ func (sc *StatsCollector) saveToFile() error {
 		Windows:    sc.windows,
 		CurrentWin: sc.currentWin,
 		SavedAt:    time.Now(),
+		WindowSize: sc.window.String(),
 	}
 
 	// Create directory if it doesn't exist
@@ -323,15 +426,37 @@ func (sc *StatsCollector) loadFromFile() error {
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 
-	// Load windows
-	sc.windows = data.Windows
+	// Windows saved with another size are merged into windows of the configured size; the
+	// current window is closed since it was opened with the old size
+	windows, currentWin := data.Windows, data.CurrentWin
+	savedWindow := legacyWindowSize
+	if data.WindowSize != "" {
+		if size, err := time.ParseDuration(data.WindowSize); err == nil && size > 0 {
+			savedWindow = size
+		}
+	}
+	if savedWindow != sc.window {
+		if currentWin != nil {
+			windows = append(windows, currentWin)
+			currentWin = nil
+		}
+		migrated := resizeWindows(windows, sc.window)
+		log.Printf("Migrated %d statistics windows of %v into %d windows of %v", len(windows), savedWindow, len(migrated), sc.window)
+		windows = migrated
+	}
+
+	// Load windows, keeping only the configured retention
+	if len(windows) > sc.maxWindows {
+		windows = windows[len(windows)-sc.maxWindows:]
+	}
+	sc.windows = windows
 	if sc.windows == nil {
 		sc.windows = make([]*TimeWindow, 0, sc.maxWindows)
 	}
 
 	// Load current window if it's still valid (not expired)
-	if data.CurrentWin != nil && time.Now().Before(data.CurrentWin.EndTime) {
-		sc.currentWin = data.CurrentWin
+	if currentWin != nil && time.Now().Before(currentWin.EndTime) {
+		sc.currentWin = currentWin
 	}
 
 	log.Printf("Loaded %d historical windows from %s", len(sc.windows), sc.persistFile)
@@ -340,7 +465,7 @@ func (sc *StatsCollector) loadFromFile() error {
 
 // startPeriodicSaving starts a goroutine that periodically saves statistics
 func (sc *StatsCollector) startPeriodicSaving() {
-	go func() {
+	supervise.Loop("stats-saving", func() {
 		ticker := time.NewTicker(sc.saveInterval)
 		defer ticker.Stop()
 
@@ -349,10 +474,63 @@ func (sc *StatsCollector) startPeriodicSaving() {
 				log.Printf("Error during periodic save: %v", err)
 			}
 		}
-	}()
+	})
 }
 
 // GetMaxWindows returns the maximum number of windows stored
 func (sc *StatsCollector) GetMaxWindows() int {
 	return sc.maxWindows
 }
+
+// GetWindowDuration returns the duration of each window
+func (sc *StatsCollector) GetWindowDuration() time.Duration {
+	return sc.window
+}
+
+// resizeWindows merges chronological windows into windows of the given size aligned to the
+// clock. A window longer than size is kept whole under the window it started in.
+func resizeWindows(windows []*TimeWindow, size time.Duration) []*TimeWindow {
+	var resized []*TimeWindow
+	for _, win := range windows {
+		if win == nil {
+			continue
+		}
+		start := win.StartTime.Truncate(size)
+		if n := len(resized); n > 0 && resized[n-1].StartTime.Equal(start) {
+			mergeStats(resized[n-1].Stats, win.Stats)
+			continue
+		}
+		merged := &TimeWindow{StartTime: start, EndTime: start.Add(size), Stats: make(map[string]*APIStats)}
+		mergeStats(merged.Stats, win.Stats)
+		resized = append(resized, merged)
+	}
+	return resized
+}
+
+// mergeStats adds the per-domain statistics of src to dst, weighting the average response
+// times by request count
+func mergeStats(dst, src map[string]*APIStats) {
+	for domain, stats := range src {
+		merged, ok := dst[domain]
+		if !ok {
+			merged = &APIStats{Domain: domain}
+			dst[domain] = merged
+		}
+		totalMs := merged.AverageRespTime*float64(merged.TotalRequests) + stats.AverageRespTime*float64(stats.TotalRequests)
+		merged.TotalRequests += stats.TotalRequests
+		merged.SuccessfulReqs += stats.SuccessfulReqs
+		merged.FailedReqs += stats.FailedReqs
+		merged.TotalRetries += stats.TotalRetries
+		merged.BytesTransferred += stats.BytesTransferred
+		for code, count := range stats.StatusCodes {
+			if merged.StatusCodes == nil {
+				merged.StatusCodes = make(map[string]int64)
+			}
+			merged.StatusCodes[code] += count
+		}
+		if merged.TotalRequests > 0 {
+			merged.AverageRespTime = totalMs / float64(merged.TotalRequests)
+		}
+		merged.TotalRespTime = time.Duration(totalMs * float64(time.Millisecond))
+	}
+}
@@ -0,0 +1,123 @@
+package stats
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMigratesWindowSize(t *testing.T) {
+	base := time.Now().Add(-2 * time.Hour).Truncate(time.Hour)
+	window := func(offset time.Duration, requests, failed int64, avgMs float64) *TimeWindow {
+		return &TimeWindow{
+			StartTime: base.Add(offset),
+			EndTime:   base.Add(offset + legacyWindowSize),
+			Stats: map[string]*APIStats{
+				"launchpad": {Domain: "launchpad", TotalRequests: requests, SuccessfulReqs: requests - failed, FailedReqs: failed, AverageRespTime: avgMs},
+			},
+		}
+	}
+
+	// A file saved before the window size was recorded holds 10 minute windows
+	data := PersistentData{
+		Windows: []*TimeWindow{
+			window(0, 10, 0, 100),
+			window(10*time.Minute, 30, 3, 200),
+			window(time.Hour, 5, 0, 50),
+		},
+		CurrentWin: window(time.Hour+10*time.Minute, 5, 5, 150),
+		SavedAt:    time.Now(),
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "statistics_data.json")
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	sc := newCollector(path, time.Hour, 100, time.Minute)
+	if err := sc.loadFromFile(); err != nil {
+		t.Fatalf("loadFromFile returned error: %v", err)
+	}
+	if sc.currentWin != nil {
+		t.Errorf("The current window of the old size should be closed on migration")
+	}
+	if len(sc.windows) != 2 {
+		t.Fatalf("loadFromFile() kept %d windows, expected 2 hourly windows", len(sc.windows))
+	}
+
+	first := sc.windows[0]
+	if !first.StartTime.Equal(base) || !first.EndTime.Equal(base.Add(time.Hour)) {
+		t.Errorf("First window spans %v-%v, expected the hour starting %v", first.StartTime, first.EndTime, base)
+	}
+	lp := first.Stats["launchpad"]
+	if lp.TotalRequests != 40 || lp.FailedReqs != 3 || lp.AverageRespTime != 175 {
+		t.Errorf("First window = %+v, expected 40 requests, 3 failed, 175ms average", lp)
+	}
+	if lp := sc.windows[1].Stats["launchpad"]; lp.TotalRequests != 10 || lp.FailedReqs != 5 || lp.AverageRespTime != 100 {
+		t.Errorf("Second window = %+v, expected 10 requests, 5 failed, 100ms average", lp)
+	}
+
+	// Saving records the new size, so the next load does not migrate again
+	sc.startNewWindow()
+	if err := sc.saveToFile(); err != nil {
+		t.Fatalf("saveToFile returned error: %v", err)
+	}
+	reloaded := newCollector(path, time.Hour, 1, time.Minute)
+	if err := reloaded.loadFromFile(); err != nil {
+		t.Fatalf("loadFromFile returned error: %v", err)
+	}
+	if reloaded.currentWin == nil || len(reloaded.windows) != 1 || !reloaded.windows[0].StartTime.Equal(base.Add(time.Hour)) {
+		t.Errorf("Reload kept %d windows and current %v, expected the last window and the open current one", len(reloaded.windows), reloaded.currentWin)
+	}
+}
+
+func TestRecordStatusCodesAndBytes(t *testing.T) {
+	sc := newCollector(filepath.Join(t.TempDir(), "statistics_data.json"), time.Hour, 10, time.Minute)
+	sc.startNewWindow()
+
+	url := "https://api.launchpad.net/devel/ubuntu/+archive/primary"
+	sc.RecordRequest(url, 100*time.Millisecond, 0, true, 200)
+	sc.RecordRequest(url, 300*time.Millisecond, 1, true, 503)
+	sc.RecordRequest(url, time.Second, 2, false, 0)
+	sc.RecordBytes(url, 4096)
+
+	lp := sc.GetCurrentWindowStats()["launchpad"]
+	if lp.BytesTransferred != 4096 || lp.StatusCodes["200"] != 1 || lp.StatusCodes["503"] != 1 || len(lp.StatusCodes) != 2 {
+		t.Errorf("current window = %+v, expected 4096 bytes and one 200 and one 503", lp)
+	}
+
+	// The totals survive window rotation
+	sc.windows = append(sc.windows, sc.currentWin)
+	sc.startNewWindow()
+	sc.RecordRequest(url, 100*time.Millisecond, 0, true, 200)
+	total := sc.GetTotals()["launchpad"]
+	if total.TotalRequests != 4 || total.FailedReqs != 1 || total.StatusCodes["200"] != 2 || total.BytesTransferred != 4096 {
+		t.Errorf("totals = %+v, expected every request since start", total)
+	}
+
+	// Resized windows keep the counts
+	merged := resizeWindows(append(sc.windows, sc.currentWin), 24*time.Hour)
+	if lp := merged[len(merged)-1].Stats["launchpad"]; lp.StatusCodes["200"] != 2 || lp.BytesTransferred != 4096 {
+		t.Errorf("merged = %+v, expected the status codes and bytes summed", lp)
+	}
+}
+
+func TestExtractDomainTagsMockRequests(t *testing.T) {
+	for url, want := range map[string]string{
+		"https://api.launchpad.net/devel/ubuntu/+archive/primary":                           "launchpad",
+		"http://localhost:9999/launchpad/ubuntu/+archive/primary":                           "launchpad [MOCK]",
+		"http://127.0.0.1:9999/scenarios/proposed-stuck/nvidia/datacenter/releases.json":    "nvidia [MOCK]",
+		"http://localhost:9999/kernel/series.yaml":                                          "ubuntu-kernel [MOCK]",
+		"http://localhost:9999/":                                                            "mock [MOCK]",
+		"https://kernel.ubuntu.com/forgejo/kernel/kernel-versions/raw/branch/main/info/x.y": "ubuntu-kernel",
+	} {
+		if got := extractDomain(url); got != want {
+			t.Errorf("extractDomain(%q) = %q, expected %q", url, got, want)
+		}
+	}
+}
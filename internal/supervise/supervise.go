@@ -0,0 +1,60 @@
+package supervise
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// RestartDelay is how long a crashed background loop waits before restarting
+var RestartDelay = 5 * time.Second
+
+var (
+	loopRestartsMu sync.Mutex
+	loopRestarts   = make(map[string]int)
+)
+
+// Loop runs loop in a new goroutine and restarts it whenever it panics.
+// A normal return from loop ends supervision.
+func Loop(name string, loop func()) {
+	go func() {
+		for {
+			if !runRecovered(name, loop) {
+				return
+			}
+
+			loopRestartsMu.Lock()
+			loopRestarts[name]++
+			restarts := loopRestarts[name]
+			loopRestartsMu.Unlock()
+
+			log.Printf("Restarting background loop %s in %v (restart #%d)", name, RestartDelay, restarts)
+			time.Sleep(RestartDelay)
+		}
+	}()
+}
+
+// runRecovered runs fn and reports whether it panicked
+func runRecovered(name string, fn func()) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("PANIC in background loop %s: %v\n%s", name, r, debug.Stack())
+			panicked = true
+		}
+	}()
+	fn()
+	return false
+}
+
+// GetRestarts returns how often each supervised loop was restarted after a panic
+func GetRestarts() map[string]int {
+	loopRestartsMu.Lock()
+	defer loopRestartsMu.Unlock()
+
+	restarts := make(map[string]int, len(loopRestarts))
+	for name, count := range loopRestarts {
+		restarts[name] = count
+	}
+	return restarts
+}
@@ -0,0 +1,30 @@
+package supervise
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoopRestartsAfterPanic(t *testing.T) {
+	RestartDelay = time.Millisecond
+
+	var runs int32
+	done := make(chan struct{})
+	Loop("test-loop", func() {
+		if atomic.AddInt32(&runs, 1) < 3 {
+			panic("boom")
+		}
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("loop was not restarted, ran %d times", atomic.LoadInt32(&runs))
+	}
+
+	if restarts := GetRestarts()["test-loop"]; restarts != 2 {
+		t.Errorf("GetRestarts()[test-loop] = %d, expected 2", restarts)
+	}
+}
@@ -0,0 +1,119 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Supported issue trackers; Forgejo also covers Gitea, whose API it shares
+const (
+	KindGitHub  = "github"
+	KindForgejo = "forgejo"
+)
+
+// Client opens and closes issues in a repository of a GitHub or Forgejo instance
+type Client struct {
+	kind       string
+	apiURL     string // e.g. "https://api.github.com" or "https://forgejo.example.com/api/v1"
+	repository string // "owner/repo"
+	token      string
+	labels     []string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for a repository. Labels are names and only sent to GitHub,
+// as Forgejo expects label IDs.
+func NewClient(kind, apiURL, repository, token string, labels []string) (*Client, error) {
+	if kind != KindGitHub && kind != KindForgejo {
+		return nil, fmt.Errorf("unknown issue tracker kind %q (expected %s or %s)", kind, KindGitHub, KindForgejo)
+	}
+	if apiURL == "" || !strings.Contains(repository, "/") {
+		return nil, fmt.Errorf("api_url and repository (owner/repo) are required")
+	}
+	return &Client{
+		kind:       kind,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		repository: repository,
+		token:      token,
+		labels:     labels,
+		httpClient: utils.NewHTTPClient(),
+	}, nil
+}
+
+// Issue is an issue opened in the tracker
+type Issue struct {
+	Number int    `json:"number"`
+	URL    string `json:"html_url"`
+}
+
+// Open creates an issue and returns its number and web URL
+func (c *Client) Open(title, body string) (*Issue, error) {
+	payload := map[string]interface{}{"title": title, "body": body}
+	if c.kind == KindGitHub && len(c.labels) > 0 {
+		payload["labels"] = c.labels
+	}
+	var issue Issue
+	if err := c.do(http.MethodPost, "/issues", payload, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// Close comments on an issue, then closes it
+func (c *Client) Close(number int, comment string) error {
+	path := fmt.Sprintf("/issues/%d", number)
+	if comment != "" {
+		if err := c.do(http.MethodPost, path+"/comments", map[string]string{"body": comment}, nil); err != nil {
+			return err
+		}
+	}
+	return c.do(http.MethodPatch, path, map[string]string{"state": "closed"}, nil)
+}
+
+// do sends a JSON request to a path under the repository and decodes the response into out
+func (c *Client) do(method, path string, payload, out interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	url := c.apiURL + "/repos/" + c.repository + path
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if utils.HTTPUserAgent != "" {
+		req.Header.Set("User-Agent", utils.HTTPUserAgent)
+	}
+	if c.token != "" {
+		if c.kind == KindGitHub {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		} else {
+			req.Header.Set("Authorization", "token "+c.token)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		preview, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("%s %s: HTTP %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(preview)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, url, err)
+	}
+	return nil
+}
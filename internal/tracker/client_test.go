@@ -0,0 +1,86 @@
+package tracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientOpenAndClose(t *testing.T) {
+	for _, tc := range []struct {
+		kind       string
+		auth       string
+		wantLabels bool
+	}{
+		{KindGitHub, "Bearer secret", true},
+		{KindForgejo, "token secret", false},
+	} {
+		var requests []string
+		var opened map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got := r.Header.Get("Authorization"); got != tc.auth {
+				t.Errorf("%s: Authorization = %q, expected %q", tc.kind, got, tc.auth)
+			}
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			if r.Method == http.MethodPost && r.URL.Path == "/repos/canonical/drivers/issues" {
+				json.NewDecoder(r.Body).Decode(&opened)
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"number": 42, "html_url": "https://example.com/canonical/drivers/issues/42"}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+
+		client, err := NewClient(tc.kind, server.URL+"/", "canonical/drivers", "secret", []string{"outdated"})
+		if err != nil {
+			t.Fatalf("%s: NewClient() returned error: %v", tc.kind, err)
+		}
+		issue, err := client.Open("570 outdated in noble", "body")
+		if err != nil || issue.Number != 42 || issue.URL != "https://example.com/canonical/drivers/issues/42" {
+			t.Fatalf("%s: Open() = %+v, %v, expected issue 42", tc.kind, issue, err)
+		}
+		if _, hasLabels := opened["labels"]; hasLabels != tc.wantLabels {
+			t.Errorf("%s: labels sent = %v, expected %v", tc.kind, hasLabels, tc.wantLabels)
+		}
+		if err := client.Close(42, "fixed"); err != nil {
+			t.Fatalf("%s: Close() returned error: %v", tc.kind, err)
+		}
+		want := []string{
+			"POST /repos/canonical/drivers/issues",
+			"POST /repos/canonical/drivers/issues/42/comments",
+			"PATCH /repos/canonical/drivers/issues/42",
+		}
+		if len(requests) != len(want) {
+			t.Fatalf("%s: requests = %v, expected %v", tc.kind, requests, want)
+		}
+		for i := range want {
+			if requests[i] != want[i] {
+				t.Errorf("%s: request %d = %q, expected %q", tc.kind, i, requests[i], want[i])
+			}
+		}
+		server.Close()
+	}
+
+	if _, err := NewClient("gitlab", "https://gitlab.com/api/v4", "canonical/drivers", "", nil); err == nil {
+		t.Errorf("NewClient(gitlab) expected an error")
+	}
+	if _, err := NewClient(KindGitHub, "https://api.github.com", "drivers", "", nil); err == nil {
+		t.Errorf("NewClient() without an owner expected an error")
+	}
+}
+
+func TestClientReportsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(KindGitHub, server.URL, "canonical/drivers", "wrong", nil)
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	if _, err := client.Open("title", "body"); err == nil {
+		t.Errorf("Open() with bad credentials expected an error")
+	}
+}
@@ -0,0 +1,144 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"nvidia_driver_monitor/internal/atomicfile"
+)
+
+// TrackedIssue is an open tracker issue about an outdated package/series cell
+type TrackedIssue struct {
+	Package  string    `json:"package"` // e.g. "nvidia-graphics-drivers-570"
+	Series   string    `json:"series"`  // Codename, e.g. "noble"
+	Number   int       `json:"number"`
+	URL      string    `json:"url"`
+	OpenedAt time.Time `json:"opened_at"`
+}
+
+// SchemaVersion is the version of the format of the tracked issues file, bumped when it changes
+const SchemaVersion = 1
+
+// Store keeps the open issues of each cell and persists them to disk, so a restart does not
+// open duplicates
+type Store struct {
+	mu          sync.RWMutex
+	issues      map[string]*TrackedIssue // Keyed by package/series
+	persistFile string
+}
+
+// NewStore creates a store, loading previously persisted issues if available
+func NewStore(persistFile string) *Store {
+	s := &Store{
+		issues:      make(map[string]*TrackedIssue),
+		persistFile: persistFile,
+	}
+	if err := s.loadFromFile(); err != nil {
+		log.Printf("Warning: Could not load existing tracker issues: %v", err)
+	}
+	return s
+}
+
+// key identifies a cell
+func key(packageName, series string) string {
+	return packageName + "/" + series
+}
+
+// Set records the open issue of a cell
+func (s *Store) Set(issue TrackedIssue) {
+	s.mu.Lock()
+	s.issues[key(issue.Package, issue.Series)] = &issue
+	s.mu.Unlock()
+	s.persist()
+}
+
+// Remove forgets the issue of a cell once closed
+func (s *Store) Remove(packageName, series string) {
+	s.mu.Lock()
+	delete(s.issues, key(packageName, series))
+	s.mu.Unlock()
+	s.persist()
+}
+
+// Get returns the open issue of a cell
+func (s *Store) Get(packageName, series string) (TrackedIssue, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	issue, ok := s.issues[key(packageName, series)]
+	if !ok {
+		return TrackedIssue{}, false
+	}
+	return *issue, true
+}
+
+// List returns the open issues ordered by package and series
+func (s *Store) List() []TrackedIssue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]TrackedIssue, 0, len(s.issues))
+	for _, issue := range s.issues {
+		list = append(list, *issue)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Package != list[j].Package {
+			return list[i].Package < list[j].Package
+		}
+		return list[i].Series < list[j].Series
+	})
+	return list
+}
+
+// persist saves the issues, logging failures
+func (s *Store) persist() {
+	if err := s.saveToFile(); err != nil {
+		log.Printf("Warning: Failed to persist tracker issues: %v", err)
+	}
+}
+
+// saveToFile writes all issues to the persistence file
+func (s *Store) saveToFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	return atomicfile.WriteJSON(s.persistFile, s.List())
+}
+
+// loadFromFile restores issues from the persistence file
+func (s *Store) loadFromFile() error {
+	if s.persistFile == "" {
+		return nil
+	}
+
+	jsonData, err := os.ReadFile(s.persistFile)
+	if os.IsNotExist(err) {
+		return nil // No existing data, start fresh
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read tracker issues file: %w", err)
+	}
+
+	var list []TrackedIssue
+	if err := json.Unmarshal(jsonData, &list); err != nil {
+		return fmt.Errorf("failed to parse tracker issues JSON: %w", err)
+	}
+
+	issues := make(map[string]*TrackedIssue, len(list))
+	for i := range list {
+		issues[key(list[i].Package, list[i].Series)] = &list[i]
+	}
+
+	s.mu.Lock()
+	s.issues = issues
+	s.mu.Unlock()
+
+	log.Printf("Loaded %d tracker issues from %s", len(issues), s.persistFile)
+	return nil
+}
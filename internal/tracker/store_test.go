@@ -0,0 +1,29 @@
+package tracker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStorePersistsIssues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tracker_issues.json")
+	store := NewStore(path)
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	store.Set(TrackedIssue{Package: "nvidia-graphics-drivers-570", Series: "noble", Number: 7, OpenedAt: now})
+	store.Set(TrackedIssue{Package: "nvidia-graphics-drivers-535", Series: "jammy", Number: 8, OpenedAt: now})
+
+	reloaded := NewStore(path)
+	if issue, ok := reloaded.Get("nvidia-graphics-drivers-570", "noble"); !ok || issue.Number != 7 || !issue.OpenedAt.Equal(now) {
+		t.Errorf("Get(570, noble) after reload = %+v, %v, expected issue 7", issue, ok)
+	}
+	if list := reloaded.List(); len(list) != 2 || list[0].Package != "nvidia-graphics-drivers-535" {
+		t.Errorf("List() = %+v, expected two issues ordered by package", list)
+	}
+
+	store.Remove("nvidia-graphics-drivers-570", "noble")
+	if _, ok := NewStore(path).Get("nvidia-graphics-drivers-570", "noble"); ok {
+		t.Errorf("Get(570, noble) after removal expected no issue")
+	}
+}
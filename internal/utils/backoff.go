@@ -0,0 +1,16 @@
+package utils
+
+import "time"
+
+// Backoff returns the wait before the next attempt after the given number of consecutive
+// failures, doubling from base and capped at max. No failures waits base.
+func Backoff(failures int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < failures && delay < max; i++ {
+		delay *= 2
+	}
+	if max > 0 && delay > max {
+		delay = max
+	}
+	return delay
+}
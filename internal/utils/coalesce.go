@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// inflightCall is an upstream GET shared by every caller asking for the same URL meanwhile
+type inflightCall struct {
+	wg   sync.WaitGroup
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+var (
+	inflightMu        sync.Mutex
+	inflightCalls     = make(map[string]*inflightCall)
+	coalescedRequests int64
+)
+
+// coalescedGet runs fetch once for concurrent callers of the same URL and gives each
+// caller its own copy of the response with an independent body reader. A panicking fetch
+// fails the callers waiting for it with an error, then panics on in its own caller.
+func coalescedGet(url string, fetch func(string) (*http.Response, error)) (*http.Response, error) {
+	inflightMu.Lock()
+	if call, ok := inflightCalls[url]; ok {
+		inflightMu.Unlock()
+		atomic.AddInt64(&coalescedRequests, 1)
+		call.wg.Wait()
+		return call.response()
+	}
+	call := &inflightCall{}
+	call.wg.Add(1)
+	inflightCalls[url] = call
+	inflightMu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("request for %s panicked: %v", url, r)
+			call.finish(url)
+			panic(r)
+		}
+		call.finish(url)
+	}()
+
+	resp, err := fetch(url)
+	if err == nil {
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			err = fmt.Errorf("failed to read response body: %w", readErr)
+		}
+		call.resp = resp
+		call.body = body
+	}
+	call.err = err
+	return call.response()
+}
+
+// finish releases the callers waiting for the call and lets later callers fetch again
+func (c *inflightCall) finish(url string) {
+	inflightMu.Lock()
+	delete(inflightCalls, url)
+	inflightMu.Unlock()
+	c.wg.Done()
+}
+
+// response returns a private copy of the shared response
+func (c *inflightCall) response() (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	copied := *c.resp
+	copied.Header = c.resp.Header.Clone()
+	copied.Body = io.NopCloser(bytes.NewReader(c.body))
+	copied.ContentLength = int64(len(c.body))
+	return &copied, nil
+}
+
+// GetCoalescedRequests returns how many GETs were served by joining an identical in-flight request
+func GetCoalescedRequests() int64 {
+	return atomic.LoadInt64(&coalescedRequests)
+}
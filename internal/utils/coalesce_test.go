@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalescedGetSharesInflightRequests(t *testing.T) {
+	var upstream int32
+	release := make(chan struct{})
+	fetch := func(url string) (*http.Response, error) {
+		atomic.AddInt32(&upstream, 1)
+		<-release
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"ok": true}`)),
+		}, nil
+	}
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 5)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := coalescedGet("https://example.invalid/shared", fetch)
+			if err != nil {
+				t.Errorf("coalescedGet returned error: %v", err)
+				return
+			}
+			defer resp.Body.Close()
+			data, _ := io.ReadAll(resp.Body)
+			bodies[i] = string(data)
+		}(i)
+	}
+
+	// Give every caller time to join the in-flight request before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&upstream); n != 1 {
+		t.Errorf("upstream requests = %d, expected 1", n)
+	}
+	for i, body := range bodies {
+		if body != `{"ok": true}` {
+			t.Errorf("caller %d body = %q, expected full shared body", i, body)
+		}
+	}
+}
+
+func TestCoalescedGetReleasesWaitersWhenFetchPanics(t *testing.T) {
+	url := "https://example.invalid/panics"
+	started := make(chan struct{})
+	release := make(chan struct{})
+	panicking := func(string) (*http.Response, error) {
+		close(started)
+		<-release
+		panic("boom")
+	}
+
+	go func() {
+		defer func() { recover() }()
+		coalescedGet(url, panicking)
+	}()
+	<-started
+
+	waited := make(chan error, 1)
+	go func() {
+		_, err := coalescedGet(url, panicking)
+		waited <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-waited:
+		if err == nil {
+			t.Errorf("waiting caller got no error from a panicked request")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiting caller still blocked after the request panicked")
+	}
+
+	// Later callers fetch again instead of joining the failed request
+	resp, err := coalescedGet(url, func(string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	})
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("coalescedGet after a panic = %v, %v, expected a fresh request", resp, err)
+	}
+}
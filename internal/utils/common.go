@@ -1,12 +1,18 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"nvidia_driver_monitor/internal/budget"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/debuglog"
 	"nvidia_driver_monitor/internal/stats"
 )
 
@@ -32,9 +38,7 @@ func SetHTTPConfig(timeout time.Duration, retries int) {
 
 	HTTPTimeout = timeout
 	HTTPRetries = retries
-	httpClient = &http.Client{
-		Timeout: HTTPTimeout,
-	}
+	httpClient = NewHTTPClient()
 
 	log.Printf("HTTP configuration updated: timeout=%v, retries=%d", HTTPTimeout, HTTPRetries)
 }
@@ -52,7 +56,23 @@ func SetHTTPAuthToken(token string) {
 	forgejoToken = strings.TrimSpace(token)
 }
 
-// HTTPGetWithRetry performs an HTTP GET request with timeout and retry logic
+// mockLogTag prefixes the log lines of requests to a local mock server, so they cannot be taken
+// for upstream failures
+func mockLogTag(url string) string {
+	if config.IsLocalURL(url) {
+		return "[MOCK] "
+	}
+	return ""
+}
+
+// LaunchpadGetWithRetry performs a Launchpad API lookup like HTTPGetWithRetry. Concurrent
+// lookups of the same URL, e.g. the same DKMS package from the L-R-M and dashboard refreshes,
+// share a single upstream request; the JSON responses are small enough to be buffered.
+func LaunchpadGetWithRetry(url string) (*http.Response, error) {
+	return coalescedGet(url, HTTPGetWithRetry)
+}
+
+// HTTPGetWithRetry performs an HTTP GET request with timeout and retry logic.
 func HTTPGetWithRetry(url string) (*http.Response, error) {
 	startTime := time.Now()
 	var lastErr error
@@ -72,33 +92,65 @@ func HTTPGetWithRetry(url string) (*http.Response, error) {
 			req.Header.Set("User-Agent", HTTPUserAgent)
 		}
 
+		budget.Record(url)
 		resp, err := httpClient.Do(req)
 		if err == nil {
 			// Record successful request
 			duration := time.Since(startTime)
-			collector.RecordRequest(url, duration, totalRetries, true)
+			collector.RecordRequest(url, duration, totalRetries, true, resp.StatusCode)
+			recordFetch(url, resp.StatusCode, nil)
+			resp.Body = &countingBody{ReadCloser: resp.Body, url: url}
+			debuglog.Printf(debuglog.ModuleHTTP, "%sGET %s: %s in %v after %d retries", mockLogTag(url), url, resp.Status, duration, totalRetries)
 			return resp, nil
 		}
 
 		lastErr = err
 		totalRetries = attempt - 1 // Don't count the first attempt as a retry
 
+		// A request blocked by offline mode would be blocked again
+		if errors.Is(err, ErrOffline) {
+			break
+		}
+
 		if attempt < HTTPRetries {
 			waitTime := time.Duration(attempt) * time.Second
-			log.Printf("HTTP request failed (attempt %d/%d): %v. Retrying in %v...", attempt, HTTPRetries, err, waitTime)
+			log.Printf("%sHTTP request failed (attempt %d/%d): %v. Retrying in %v...", mockLogTag(url), attempt, HTTPRetries, err, waitTime)
 			time.Sleep(waitTime)
 		} else {
-			log.Printf("HTTP request failed after %d attempts: %v", HTTPRetries, err)
+			log.Printf("%sHTTP request failed after %d attempts: %v", mockLogTag(url), HTTPRetries, err)
 		}
 	}
 
 	// Record failed request
 	duration := time.Since(startTime)
-	collector.RecordRequest(url, duration, HTTPRetries-1, false)
+	collector.RecordRequest(url, duration, totalRetries, false, 0)
+	recordFetch(url, 0, lastErr)
 
+	if errors.Is(lastErr, ErrOffline) {
+		return nil, lastErr
+	}
 	return nil, fmt.Errorf("all %d HTTP attempts failed, last error: %v", HTTPRetries, lastErr)
 }
 
+// countingBody records the bytes read from a response body in the statistics once it is closed
+type countingBody struct {
+	io.ReadCloser
+	url   string
+	bytes int64
+	once  sync.Once
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.once.Do(func() { stats.GetStatsCollector().RecordBytes(b.url, b.bytes) })
+	return b.ReadCloser.Close()
+}
+
 func forgejoAuthHeader(url string) string {
 	if forgejoToken == "" {
 		return ""
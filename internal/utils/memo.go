@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// TTLMemo caches the results of expensive lookups for a limited time.
+// Concurrent callers asking for the same key wait for a single computation.
+// Errors are never cached so a failed lookup is retried by the next caller.
+type TTLMemo struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxBackoff time.Duration
+	entries    map[string]*memoEntry
+}
+
+type memoEntry struct {
+	mu        sync.Mutex
+	value     interface{}
+	fetchedAt time.Time
+	valid     bool
+	failures  int       // Consecutive failed refreshes of a valid value
+	retryAt   time.Time // GetStale serves the stale value without refetching until then
+}
+
+// NewTTLMemo creates a memo whose entries expire after ttl
+func NewTTLMemo(ttl time.Duration) *TTLMemo {
+	return &TTLMemo{
+		ttl:     ttl,
+		entries: make(map[string]*memoEntry),
+	}
+}
+
+// SetTTL changes the expiry used for subsequent lookups. A zero or negative ttl disables caching.
+func (m *TTLMemo) SetTTL(ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ttl = ttl
+}
+
+// SetMaxBackoff caps how long GetStale waits between refresh attempts of a failing key
+func (m *TTLMemo) SetMaxBackoff(maxBackoff time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxBackoff = maxBackoff
+}
+
+// entry returns the entry for key, creating it if needed; nil means caching is disabled
+func (m *TTLMemo) entry(key string) (*memoEntry, time.Duration, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ttl <= 0 {
+		return nil, 0, 0
+	}
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoEntry{}
+		m.entries[key] = entry
+	}
+	return entry, m.ttl, m.maxBackoff
+}
+
+// Get returns the cached value for key or calls fetch to compute it
+func (m *TTLMemo) Get(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	entry, ttl, _ := m.entry(key)
+	if entry == nil {
+		return fetch()
+	}
+
+	// Holding the entry lock while fetching makes concurrent callers share one lookup
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.valid && time.Since(entry.fetchedAt) < ttl {
+		return entry.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	entry.value = value
+	entry.fetchedAt = time.Now()
+	entry.valid = true
+	entry.failures = 0
+	return value, nil
+}
+
+// GetStale is Get with stale-while-revalidate semantics: when fetch fails and an earlier value
+// exists, that value is returned together with the time it was fetched, and further fetches of
+// the key back off exponentially until one succeeds. staleSince is zero for fresh values.
+func (m *TTLMemo) GetStale(key string, fetch func() (interface{}, error)) (value interface{}, staleSince time.Time, err error) {
+	entry, ttl, maxBackoff := m.entry(key)
+	if entry == nil {
+		value, err = fetch()
+		return value, time.Time{}, err
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	now := time.Now()
+	if entry.valid && now.Sub(entry.fetchedAt) < ttl {
+		return entry.value, time.Time{}, nil
+	}
+	if entry.valid && now.Before(entry.retryAt) {
+		return entry.value, entry.fetchedAt, nil
+	}
+
+	value, err = fetch()
+	if err != nil {
+		if !entry.valid {
+			return nil, time.Time{}, err
+		}
+		entry.failures++
+		entry.retryAt = now.Add(Backoff(entry.failures, ttl, maxBackoff))
+		return entry.value, entry.fetchedAt, nil
+	}
+	entry.value = value
+	entry.fetchedAt = now
+	entry.valid = true
+	entry.failures = 0
+	return value, time.Time{}, nil
+}
+
+// Refresh calls fetch regardless of the cached value's age and caches the result. On failure
+// the cached value, if any, is kept and the error is returned.
+func (m *TTLMemo) Refresh(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	entry, _, _ := m.entry(key)
+	if entry == nil {
+		return fetch()
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	entry.value = value
+	entry.fetchedAt = time.Now()
+	entry.valid = true
+	entry.failures = 0
+	entry.retryAt = time.Time{}
+	return value, nil
+}
+
+// Clear drops all cached entries
+func (m *TTLMemo) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*memoEntry)
+}
+
+// Len returns the number of keys currently tracked
+func (m *TTLMemo) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTTLMemoSharesConcurrentLookups(t *testing.T) {
+	memo := NewTTLMemo(time.Minute)
+	var calls int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := memo.Get("nvidia-graphics-drivers-580", func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(10 * time.Millisecond)
+				return "580.95.05", nil
+			})
+			if err != nil || value.(string) != "580.95.05" {
+				t.Errorf("Unexpected result %v, %v", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected a single lookup, got %d", calls)
+	}
+}
+
+func TestTTLMemoExpiryAndErrors(t *testing.T) {
+	memo := NewTTLMemo(20 * time.Millisecond)
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+
+	memo.Get("key", fetch)
+	memo.Get("key", fetch)
+	if calls != 1 {
+		t.Errorf("Expected cached value within TTL, got %d calls", calls)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	memo.Get("key", fetch)
+	if calls != 2 {
+		t.Errorf("Expected refetch after TTL, got %d calls", calls)
+	}
+
+	failures := 0
+	failing := func() (interface{}, error) {
+		failures++
+		return nil, errors.New("launchpad unavailable")
+	}
+	memo.Get("broken", failing)
+	memo.Get("broken", failing)
+	if failures != 2 {
+		t.Errorf("Errors should not be cached, got %d calls", failures)
+	}
+}
+
+func TestTTLMemoServesStaleWhileRevalidating(t *testing.T) {
+	memo := NewTTLMemo(10 * time.Millisecond)
+	memo.SetMaxBackoff(time.Hour)
+	calls := 0
+	available := true
+	fetch := func() (interface{}, error) {
+		calls++
+		if !available {
+			return nil, errors.New("launchpad is in read-only mode")
+		}
+		return calls, nil
+	}
+
+	if _, _, err := memo.GetStale("missing", func() (interface{}, error) { return nil, errors.New("503") }); err == nil {
+		t.Errorf("GetStale without an earlier value should return the error")
+	}
+
+	memo.GetStale("key", fetch)
+	time.Sleep(20 * time.Millisecond)
+	available = false
+	value, staleSince, err := memo.GetStale("key", fetch)
+	if err != nil || value.(int) != 1 || staleSince.IsZero() {
+		t.Fatalf("GetStale() = %v, %v, %v, expected the stale first value", value, staleSince, err)
+	}
+
+	// Further lookups back off instead of hitting the failing API again
+	memo.GetStale("key", fetch)
+	if calls != 2 {
+		t.Errorf("Expected no refetch during backoff, got %d calls", calls)
+	}
+}
+
+func TestTTLMemoRefresh(t *testing.T) {
+	memo := NewTTLMemo(time.Hour)
+	calls := 0
+	available := true
+	fetch := func() (interface{}, error) {
+		calls++
+		if !available {
+			return nil, errors.New("launchpad unavailable")
+		}
+		return calls, nil
+	}
+
+	memo.Get("key", fetch)
+	if value, err := memo.Refresh("key", fetch); err != nil || value.(int) != 2 {
+		t.Errorf("Refresh() = %v, %v, expected a new lookup within the TTL", value, err)
+	}
+	if value, _ := memo.Get("key", fetch); value.(int) != 2 {
+		t.Errorf("Get() = %v, expected the refreshed value", value)
+	}
+
+	available = false
+	if _, err := memo.Refresh("key", fetch); err == nil {
+		t.Errorf("Refresh() should return the error of a failed lookup")
+	}
+	if value, _ := memo.Get("key", fetch); value.(int) != 2 {
+		t.Errorf("Get() = %v, expected a failed refresh to keep the cached value", value)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{0, 5 * time.Minute},
+		{1, 5 * time.Minute},
+		{2, 10 * time.Minute},
+		{4, 40 * time.Minute},
+		{10, time.Hour},
+	}
+	for _, test := range tests {
+		if result := Backoff(test.failures, 5*time.Minute, time.Hour); result != test.expected {
+			t.Errorf("Backoff(%d) = %v, expected %v", test.failures, result, test.expected)
+		}
+	}
+}
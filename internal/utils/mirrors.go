@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MirrorStatus records which URL served the last fetch of a mirrored file
+type MirrorStatus struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url,omitempty"` // URL that served the data; empty when every URL failed
+	Primary   bool      `json:"primary"`       // Whether the data came from the first configured URL
+	FetchedAt time.Time `json:"fetched_at"`
+	Failed    []string  `json:"failed,omitempty"` // URLs that failed during that fetch, in order
+	Error     string    `json:"error,omitempty"`
+}
+
+var (
+	mirrorsMu      sync.Mutex
+	mirrorStatuses = make(map[string]*MirrorStatus)
+)
+
+// FetchYAMLFromMirrors fetches a YAML file from the first of urls that serves valid YAML, so a
+// failing primary falls over to its mirrors. label names the file in errors and the record of
+// which mirror served it.
+func FetchYAMLFromMirrors(label string, urls []string) ([]byte, error) {
+	return fetchFromMirrors(label, urls, HTTPGetWithRetry)
+}
+
+// fetchFromMirrors implements FetchYAMLFromMirrors with the given GET function
+func fetchFromMirrors(label string, urls []string, get func(string) (*http.Response, error)) ([]byte, error) {
+	status := &MirrorStatus{Name: label, FetchedAt: time.Now()}
+	defer func() {
+		mirrorsMu.Lock()
+		mirrorStatuses[label] = status
+		mirrorsMu.Unlock()
+	}()
+
+	var errs []string
+	for i, url := range urls {
+		body, err := fetchYAML(label, url, get)
+		if err != nil {
+			status.Failed = append(status.Failed, url)
+			errs = append(errs, err.Error())
+			if i+1 < len(urls) {
+				log.Printf("Warning: Failed to fetch %s from %s, trying the next mirror: %v", label, url, err)
+			}
+			continue
+		}
+		status.URL = url
+		status.Primary = i == 0
+		if i > 0 {
+			log.Printf("Fetched %s from mirror %s after %d failed", label, url, i)
+		}
+		return body, nil
+	}
+
+	if len(urls) == 0 {
+		errs = append(errs, "no URL configured")
+	}
+	status.Error = strings.Join(errs, "; ")
+	return nil, fmt.Errorf("failed to fetch %s from %d mirror(s): %s", label, len(urls), status.Error)
+}
+
+// fetchYAML downloads one URL and checks that it returned YAML
+func fetchYAML(label, url string, get func(string) (*http.Response, error)) ([]byte, error) {
+	resp, err := get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %v", label, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", label, err)
+	}
+	if err := ValidateYAMLResponse(resp, body, label); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// MirrorStatuses returns which URL served each mirrored file last, ordered by name
+func MirrorStatuses() []MirrorStatus {
+	mirrorsMu.Lock()
+	defer mirrorsMu.Unlock()
+
+	statuses := make([]MirrorStatus, 0, len(mirrorStatuses))
+	for _, status := range mirrorStatuses {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
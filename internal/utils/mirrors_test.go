@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFetchFromMirrorsFailsOver(t *testing.T) {
+	responses := map[string]struct {
+		status int
+		body   string
+	}{
+		"https://primary/kernel-series.yaml":  {http.StatusBadGateway, "<html>502 Bad Gateway</html>"},
+		"https://login/kernel-series.yaml":    {http.StatusOK, "<!DOCTYPE html><html>Sign in</html>"},
+		"https://mirror/kernel-series.yaml":   {http.StatusOK, "'2404':\n  codename: noble\n"},
+		"https://unused/kernel-series.yaml":   {http.StatusOK, "'2204':\n  codename: jammy\n"},
+		"https://primary/sru-cycle.yaml":      {http.StatusOK, "2026.10.13:\n  release-date: '2026-11-03'\n"},
+		"https://unreachable/sru-cycle.yaml":  {0, ""},
+		"https://unreachable2/sru-cycle.yaml": {0, ""},
+	}
+	var requested []string
+	get := func(rawURL string) (*http.Response, error) {
+		requested = append(requested, rawURL)
+		response := responses[rawURL]
+		if response.status == 0 {
+			return nil, errors.New("connection refused")
+		}
+		u, _ := url.Parse(rawURL)
+		return &http.Response{
+			StatusCode: response.status,
+			Status:     http.StatusText(response.status),
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(response.body)),
+			Request:    &http.Request{URL: u},
+		}, nil
+	}
+
+	body, err := fetchFromMirrors("kernel-series.yaml", []string{
+		"https://primary/kernel-series.yaml", "https://login/kernel-series.yaml",
+		"https://mirror/kernel-series.yaml", "https://unused/kernel-series.yaml",
+	}, get)
+	if err != nil || !strings.Contains(string(body), "noble") {
+		t.Fatalf("fetchFromMirrors() = %q, %v, expected the mirror's content", body, err)
+	}
+	if len(requested) != 3 {
+		t.Errorf("requested %v, expected to stop at the first working mirror", requested)
+	}
+
+	if _, err := fetchFromMirrors("sru-cycle.yaml", []string{"https://primary/sru-cycle.yaml"}, get); err != nil {
+		t.Fatalf("fetchFromMirrors(primary) failed: %v", err)
+	}
+	if _, err := fetchFromMirrors("down.yaml", []string{"https://unreachable/sru-cycle.yaml", "https://unreachable2/sru-cycle.yaml"}, get); err == nil {
+		t.Error("fetchFromMirrors() should fail when every mirror fails")
+	}
+
+	statuses := make(map[string]MirrorStatus)
+	for _, status := range MirrorStatuses() {
+		statuses[status.Name] = status
+	}
+	if s := statuses["kernel-series.yaml"]; s.URL != "https://mirror/kernel-series.yaml" || s.Primary || len(s.Failed) != 2 {
+		t.Errorf("kernel-series.yaml status = %+v, expected the mirror after 2 failures", s)
+	}
+	if s := statuses["sru-cycle.yaml"]; s.URL != "https://primary/sru-cycle.yaml" || !s.Primary {
+		t.Errorf("sru-cycle.yaml status = %+v, expected the primary", s)
+	}
+	if s := statuses["down.yaml"]; s.URL != "" || s.Error == "" || len(s.Failed) != 2 {
+		t.Errorf("down.yaml status = %+v, expected an error and no URL", s)
+	}
+}
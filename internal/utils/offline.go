@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"nvidia_driver_monitor/internal/config"
+)
+
+// ErrOffline is the error of outbound requests blocked by offline mode
+var ErrOffline = errors.New("outbound request blocked by offline mode")
+
+// offline blocks the outbound requests of the clients created while it is set, except those to
+// a local mock server
+var offline bool
+
+// SetHTTPOffline blocks (or allows again) every outbound request that does not go to a local
+// mock server, so the service can run on its cached and persisted data alone
+func SetHTTPOffline(enabled bool) {
+	offline = enabled
+	httpClient = NewHTTPClient()
+	if enabled {
+		log.Printf("Offline mode: outbound requests are blocked except to a local mock server; serving cached and persisted data")
+	}
+}
+
+// offlineTransport refuses the requests to anything but a local mock server
+type offlineTransport struct {
+	next http.RoundTripper
+}
+
+func (t offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !config.IsLocalURL(req.URL.String()) {
+		return nil, fmt.Errorf("%w: %s %s (http.offline is set: the last cached or persisted data is served; enable testing mode to answer it from the mock server, or unset http.offline)",
+			ErrOffline, req.Method, req.URL.Redacted())
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOfflineBlocksUpstreams(t *testing.T) {
+	defer SetHTTPOffline(false)
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("mock"))
+	}))
+	defer mock.Close()
+
+	SetHTTPOffline(true)
+	started := time.Now()
+	_, err := HTTPGetWithRetry("https://api.launchpad.net/devel/ubuntu/devel")
+	if !errors.Is(err, ErrOffline) || !strings.Contains(err.Error(), "api.launchpad.net") {
+		t.Errorf("HTTPGetWithRetry() offline = %v, expected ErrOffline naming the URL", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("a blocked request took %v, expected it to fail without retries", elapsed)
+	}
+	if _, err := NewHTTPClient().Get("https://hooks.example.com/alert"); !errors.Is(err, ErrOffline) {
+		t.Errorf("NewHTTPClient().Get() offline = %v, expected ErrOffline", err)
+	}
+
+	resp, err := HTTPGetWithRetry(mock.URL + "/launchpad/devel/ubuntu")
+	if err != nil {
+		t.Fatalf("HTTPGetWithRetry() of the local mock server offline returned error: %v", err)
+	}
+	resp.Body.Close()
+}
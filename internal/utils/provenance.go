@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// FetchRecord is the outcome of the last upstream GET of a URL
+type FetchRecord struct {
+	URL        string    `json:"url"`
+	StatusCode int       `json:"status_code,omitempty"` // Zero when no response was received
+	FetchedAt  time.Time `json:"fetched_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+var (
+	fetchesMu    sync.Mutex
+	fetchRecords = make(map[string]*FetchRecord)
+)
+
+// recordFetch stores the outcome of a GET. Records are keyed by the URL without its query so
+// API queries that only differ in their parameters share one record.
+func recordFetch(url string, statusCode int, err error) {
+	record := &FetchRecord{URL: url, StatusCode: statusCode, FetchedAt: time.Now()}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	key, _, _ := strings.Cut(url, "?")
+
+	fetchesMu.Lock()
+	fetchRecords[key] = record
+	fetchesMu.Unlock()
+}
+
+// LastFetch returns the most recent GET of a URL starting with prefix
+func LastFetch(prefix string) (FetchRecord, bool) {
+	fetchesMu.Lock()
+	defer fetchesMu.Unlock()
+
+	var latest *FetchRecord
+	for _, record := range fetchRecords {
+		if !strings.HasPrefix(record.URL, prefix) {
+			continue
+		}
+		if latest == nil || record.FetchedAt.After(latest.FetchedAt) {
+			latest = record
+		}
+	}
+	if latest == nil {
+		return FetchRecord{}, false
+	}
+	return *latest, true
+}
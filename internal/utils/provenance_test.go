@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLastFetch(t *testing.T) {
+	recordFetch("https://api.example/devel/ubuntu/+archive/primary?ws.op=getPublishedSources&source_name=a", 200, nil)
+	time.Sleep(time.Millisecond)
+	recordFetch("https://api.example/devel/ubuntu/+archive/primary?ws.op=getPublishedSources&source_name=b", 503, nil)
+	recordFetch("https://other.example/releases.json", 0, errors.New("connection refused"))
+
+	tests := []struct {
+		prefix string
+		found  bool
+		url    string
+		status int
+		err    string
+	}{
+		{"https://api.example/devel", true, "https://api.example/devel/ubuntu/+archive/primary?ws.op=getPublishedSources&source_name=b", 503, ""},
+		{"https://other.example/", true, "https://other.example/releases.json", 0, "connection refused"},
+		{"https://missing.example/", false, "", 0, ""},
+	}
+	for _, tt := range tests {
+		record, ok := LastFetch(tt.prefix)
+		if ok != tt.found {
+			t.Fatalf("LastFetch(%q) found = %v, want %v", tt.prefix, ok, tt.found)
+		}
+		if record.URL != tt.url || record.StatusCode != tt.status || record.Error != tt.err {
+			t.Errorf("LastFetch(%q) = %+v, want url %q status %d error %q", tt.prefix, record, tt.url, tt.status, tt.err)
+		}
+	}
+
+	fetchesMu.Lock()
+	defer fetchesMu.Unlock()
+	if _, ok := fetchRecords["https://api.example/devel/ubuntu/+archive/primary"]; !ok {
+		t.Errorf("queries of one endpoint should share a record keyed without the query")
+	}
+}
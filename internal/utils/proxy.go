@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyFunc picks the proxy of an outbound request; nil keeps the environment based default
+var proxyFunc func(*url.URL) (*url.URL, error)
+
+// SetHTTPProxy sets the proxies used for outbound requests. Configured proxies override the
+// HTTP_PROXY and HTTPS_PROXY environment variables, and empty ones fall back to them. Hosts in
+// noProxy are reached directly, in addition to the ones listed in NO_PROXY.
+func SetHTTPProxy(httpProxy, httpsProxy string, noProxy []string) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if httpProxy = strings.TrimSpace(httpProxy); httpProxy != "" {
+		proxyConfig.HTTPProxy = httpProxy
+	}
+	if httpsProxy = strings.TrimSpace(httpsProxy); httpsProxy != "" {
+		proxyConfig.HTTPSProxy = httpsProxy
+	}
+	if len(noProxy) > 0 {
+		hosts := append([]string{}, noProxy...)
+		if proxyConfig.NoProxy != "" {
+			hosts = append(hosts, proxyConfig.NoProxy)
+		}
+		proxyConfig.NoProxy = strings.Join(hosts, ",")
+	}
+
+	proxyFunc = proxyConfig.ProxyFunc()
+	httpClient = NewHTTPClient()
+
+	log.Printf("HTTP proxy configuration updated: http=%t, https=%t, no_proxy=%q",
+		proxyConfig.HTTPProxy != "", proxyConfig.HTTPSProxy != "", proxyConfig.NoProxy)
+}
+
+// NewHTTPClient returns a client with the configured timeout and proxies, for outbound
+// requests that do not go through HTTPGetWithRetry. In offline mode it only reaches a local
+// mock server.
+func NewHTTPClient() *http.Client {
+	var transport http.RoundTripper
+	if proxyFunc != nil {
+		proxied := http.DefaultTransport.(*http.Transport).Clone()
+		proxied.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+		transport = proxied
+	}
+	if offline {
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		transport = offlineTransport{next: transport}
+	}
+	return &http.Client{Timeout: HTTPTimeout, Transport: transport}
+}
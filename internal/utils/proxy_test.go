@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSetHTTPProxy(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+	t.Setenv("NO_PROXY", "localhost")
+	defer func() {
+		proxyFunc = nil
+		httpClient = NewHTTPClient()
+	}()
+
+	SetHTTPProxy("", "http://egress:3128", []string{"launchpad.net", ".ubuntu.com"})
+
+	for _, test := range []struct {
+		url      string
+		expected string
+	}{
+		{"https://www.nvidia.com/drivers", "http://egress:3128"},
+		{"https://api.launchpad.net/devel/ubuntu", ""},
+		{"http://us.download.nvidia.com/XFree86/", ""},
+		{"https://launchpad.net/ubuntu", ""},
+		{"http://archive.ubuntu.com/ubuntu/dists/noble/Release", ""},
+		{"https://localhost/health", ""},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, test.url, nil)
+		proxy, err := NewHTTPClient().Transport.(*http.Transport).Proxy(req)
+		if err != nil {
+			t.Fatalf("Proxy(%s) returned error: %v", test.url, err)
+		}
+		got := ""
+		if proxy != nil {
+			got = proxy.String()
+		}
+		if got != test.expected {
+			t.Errorf("Proxy(%s) = %q, expected %q", test.url, got, test.expected)
+		}
+	}
+}
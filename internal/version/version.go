@@ -0,0 +1,44 @@
+// Package version identifies the running build of the monitor, so a change in the numbers it
+// reports can be told apart from a change in the tool itself.
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Version is the release of the binary, set at build time with
+// -ldflags "-X nvidia_driver_monitor/internal/version.Version=v1.2.3"
+var Version = "dev"
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`   // VCS commit the binary was built from
+	BuildTime string `json:"build_time,omitempty"` // Time of that commit
+	Modified  bool   `json:"modified,omitempty"`   // Built with uncommitted changes
+}
+
+// Get returns the build information embedded in the binary
+func Get() Info {
+	info := Info{Version: Version, GoVersion: runtime.Version()}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.BuildTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	if info.Version == "dev" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+	}
+	return info
+}
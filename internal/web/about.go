@@ -0,0 +1,157 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"nvidia_driver_monitor/internal/acks"
+	"nvidia_driver_monitor/internal/advisories"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/fleet"
+	"nvidia_driver_monitor/internal/gpus"
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/lrmreport"
+	"nvidia_driver_monitor/internal/notes"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/tracker"
+	"nvidia_driver_monitor/internal/version"
+)
+
+// VersionInfo is what changed in the tool itself: its build, the features enabled by the
+// configuration, and the formats of the files it persists
+type VersionInfo struct {
+	version.Info
+	Features   map[string]bool `json:"features"`    // Configuration sections by their "enabled" flag
+	ConfigHash string          `json:"config_hash"` // Changes whenever the effective configuration does
+	Schemas    map[string]int  `json:"schemas"`     // Format version of each persisted store
+}
+
+// storeSchemas returns the format version of each persisted store
+func storeSchemas() map[string]int {
+	return map[string]int{
+		"history":          history.SchemaVersion,
+		"advisories":       advisories.SchemaVersion,
+		"notes":            notes.SchemaVersion,
+		"acknowledgements": acks.SchemaVersion,
+		"gpu_inventories":  gpus.SchemaVersion,
+		"fleet":            fleet.SchemaVersion,
+		"issue_tracker":    tracker.SchemaVersion,
+		"lrm_report":       lrmreport.SchemaVersion,
+	}
+}
+
+// configFeatures returns the "enabled" flag of every configuration section, keyed by its JSON
+// path (e.g. "jobs" or "auth.oidc"), so new sections are listed without being added here
+func configFeatures(cfg *config.Config) map[string]bool {
+	features := make(map[string]bool)
+	if cfg != nil {
+		collectFeatures(reflect.ValueOf(*cfg), "", features)
+	}
+	return features
+}
+
+// collectFeatures adds the "enabled" flags of a configuration struct and its nested sections
+func collectFeatures(v reflect.Value, prefix string, features map[string]bool) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		value := v.Field(i)
+		switch {
+		case name == "enabled" && value.Kind() == reflect.Bool && prefix != "":
+			features[prefix] = value.Bool()
+		case value.Kind() == reflect.Struct:
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+			collectFeatures(value, path, features)
+		}
+	}
+}
+
+// configHash returns a short digest of the effective configuration, defaults included. Only the
+// digest is exposed, not the values, as the configuration holds tokens.
+func configHash(cfg *config.Config) string {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// versionInfo describes the running build and configuration
+func (ws *WebService) versionInfo() VersionInfo {
+	return VersionInfo{
+		Info:       version.Get(),
+		Features:   configFeatures(ws.config),
+		ConfigHash: configHash(ws.config),
+		Schemas:    storeSchemas(),
+	}
+}
+
+// versionHandler reports the build, enabled features, configuration hash and store formats
+// (/api/version)
+func (ws *WebService) versionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if err := json.NewEncoder(w).Encode(ws.versionInfo()); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// aboutItem is a name and value listed on the about page, in name order
+type aboutItem struct {
+	Name  string
+	Value string
+}
+
+// sortedItems lists a map by key
+func sortedItems[V any](values map[string]V) []aboutItem {
+	items := make([]aboutItem, 0, len(values))
+	for name, value := range values {
+		items = append(items, aboutItem{Name: name, Value: fmt.Sprint(value)})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items
+}
+
+// aboutPageHandler renders the build, enabled features and store formats (/about)
+func (ws *WebService) aboutPageHandler(w http.ResponseWriter, r *http.Request) {
+	locale := requestLocale(w, r, ws.config)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	templateFile := filepath.Join(ws.templatePath, "about.html")
+	tmpl, err := template.New("about.html").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, requestTimezone(w, r, ws.config))).ParseFiles(templateFile)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Template parsing error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	info := ws.versionInfo()
+	templateData := struct {
+		VersionInfo
+		FeatureList []aboutItem
+		SchemaList  []aboutItem
+		CDN         map[string]string
+	}{
+		VersionInfo: info,
+		FeatureList: sortedItems(info.Features),
+		SchemaList:  sortedItems(info.Schemas),
+		CDN:         GetCDNResources(ws.config),
+	}
+	renderPage(w, tmpl, templateData)
+}
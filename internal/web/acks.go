@@ -0,0 +1,142 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/acks"
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// acknowledgedColor replaces "danger" on outdated cells acknowledged as intentional
+const acknowledgedColor = "acknowledged"
+
+// applyAcknowledgements turns the outdated rows of a package acknowledged at now from "danger"
+// to acknowledgedColor, keeping their versions, and restores the rows whose acknowledgement
+// was removed or has expired
+func applyAcknowledgements(store *acks.Store, packageName string, seriesData []SeriesData, now time.Time) {
+	if store == nil {
+		return
+	}
+	for i := range seriesData {
+		row := &seriesData[i]
+		if row.UpdatesColor == acknowledgedColor {
+			row.UpdatesColor = "danger"
+		}
+		row.Acknowledged, row.AcknowledgedUntil = "", ""
+
+		ack, ok := store.Active(packageName, row.Series, now)
+		if !ok || row.UpdatesColor != "danger" {
+			continue
+		}
+		row.UpdatesColor = acknowledgedColor
+		row.Acknowledged = ack.Reason
+		row.AcknowledgedUntil = ack.Expires.UTC().Format("2006-01-02 15:04 UTC")
+	}
+}
+
+// parseAckExpiry reads when an acknowledgement expires: an RFC 3339 time, a date (the
+// acknowledgement lasts through that day, UTC), or a duration from now such as "72h" or "30d"
+func parseAckExpiry(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("expires is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("expires must be an RFC 3339 time, a YYYY-MM-DD date or a duration such as 30d")
+}
+
+// acknowledgementsHandler lists the active acknowledgements (GET), or acknowledges (PUT) or
+// un-acknowledges (DELETE) one cell (/api/v1/acknowledgements?package={name}&series={codename})
+func (ws *WebService) acknowledgementsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodGet {
+		response := map[string]interface{}{"acknowledgements": ws.ackStore.List(time.Now())}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+		return
+	}
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !ws.requireAdmin(w, r) {
+		return
+	}
+
+	packageName := r.URL.Query().Get("package")
+	series := r.URL.Query().Get("series")
+	if packageName == "" || series == "" {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "package and series are required")
+		return
+	}
+	if !strings.HasPrefix(packageName, "nvidia-graphics-drivers-") {
+		packageName = "nvidia-graphics-drivers-" + packageName
+	}
+
+	index, _, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	if _, ok := index.row(packageName, series); !ok {
+		problems.Write(w, http.StatusNotFound, problems.PackageNotFound, "No such package and series on the dashboard")
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if !ws.ackStore.Remove(packageName, series) {
+			problems.Write(w, http.StatusNotFound, problems.NotFound, "Acknowledgement not found")
+			return
+		}
+		ws.reapplyCellAnnotations()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var body struct {
+		Reason  string `json:"reason"`
+		Expires string `json:"expires"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid JSON")
+		return
+	}
+	now := time.Now()
+	expires, err := parseAckExpiry(body.Expires, now)
+	if err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		return
+	}
+	createdBy := ""
+	if session, ok := auth.SessionFromContext(r.Context()); ok {
+		createdBy = session.Name
+	}
+	ack, err := ws.ackStore.Set(packageName, series, body.Reason, expires, createdBy, now)
+	if err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		return
+	}
+	ws.reapplyCellAnnotations()
+	json.NewEncoder(w).Encode(ack)
+}
@@ -0,0 +1,41 @@
+package web
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// requireAdmin writes the error of a request that needs an admin and returns false. An admin
+// session or the configured admin token is accepted; with neither OIDC nor a token configured,
+// every request is refused.
+func (ws *WebService) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if session, ok := auth.SessionFromContext(r.Context()); ok {
+		if session.Role != auth.RoleAdmin {
+			problems.Write(w, http.StatusForbidden, problems.Forbidden, "Admin role required")
+			return false
+		}
+		return true
+	}
+	token, oidc := "", false
+	if ws.config != nil {
+		token, oidc = ws.config.Auth.GetAdminToken(), ws.config.Auth.OIDC.Enabled
+	}
+	if token == "" {
+		if !oidc {
+			problems.Write(w, http.StatusForbidden, problems.Forbidden, "Admin endpoints need auth.oidc or auth.admin_token to be configured")
+			return false
+		}
+		problems.Write(w, http.StatusUnauthorized, problems.Unauthorized, "Login required")
+		return false
+	}
+	presented := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+		problems.Write(w, http.StatusUnauthorized, problems.Unauthorized, "Invalid or missing admin token")
+		return false
+	}
+	return true
+}
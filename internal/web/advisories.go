@@ -0,0 +1,132 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/advisories"
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// AdvisoryMatch flags a driver published for a kernel as a known bad combination
+type AdvisoryMatch struct {
+	Source     string              `json:"source"`
+	Codename   string              `json:"codename"`
+	Routing    string              `json:"routing"`
+	DriverName string              `json:"driver_name"`
+	Advisory   advisories.Advisory `json:"advisory"`
+}
+
+// matchAdvisories returns the advisories applying to the published drivers of each kernel
+func matchAdvisories(store *advisories.Store, results []lrm.KernelLRMResult) []AdvisoryMatch {
+	if store == nil {
+		return nil
+	}
+	var matches []AdvisoryMatch
+	for _, result := range results {
+		for _, driver := range result.NvidiaDriverStatuses {
+			for _, advisory := range store.Match(result.Codename, result.Source, result.SourceVersion, driver.DriverName, driver.DKMSVersion) {
+				matches = append(matches, AdvisoryMatch{
+					Source:     result.Source,
+					Codename:   result.Codename,
+					Routing:    result.Routing,
+					DriverName: driver.DriverName,
+					Advisory:   advisory,
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// decodeAdvisory reads an advisory from a request body
+func decodeAdvisory(w http.ResponseWriter, r *http.Request) (advisories.Advisory, bool) {
+	var advisory advisories.Advisory
+	if err := json.NewDecoder(r.Body).Decode(&advisory); err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid JSON")
+		return advisory, false
+	}
+	return advisory, true
+}
+
+// advisoriesHandler lists (GET) and adds (POST) known bad driver/kernel combinations
+// (/api/v1/advisories)
+func (ws *WebService) advisoriesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"advisories": ws.advisoryStore.List()}); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+	case http.MethodPost:
+		if !ws.requireAdmin(w, r) {
+			return
+		}
+		advisory, ok := decodeAdvisory(w, r)
+		if !ok {
+			return
+		}
+		createdBy := ""
+		if session, ok := auth.SessionFromContext(r.Context()); ok {
+			createdBy = session.Name
+		}
+		added, err := ws.advisoryStore.Add(advisory, createdBy, time.Now())
+		if err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(added)
+	default:
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+	}
+}
+
+// advisoryHandler replaces (PUT) or removes (DELETE) one advisory (/api/v1/advisories/{id})
+func (ws *WebService) advisoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/advisories/")
+	if id == "" || strings.Contains(id, "/") {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Advisory ID is required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if !ws.requireAdmin(w, r) {
+			return
+		}
+		advisory, ok := decodeAdvisory(w, r)
+		if !ok {
+			return
+		}
+		updated, err := ws.advisoryStore.Update(id, advisory)
+		if errors.Is(err, advisories.ErrNotFound) {
+			problems.Write(w, http.StatusNotFound, problems.NotFound, "Advisory not found")
+			return
+		}
+		if err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+			return
+		}
+		json.NewEncoder(w).Encode(updated)
+	case http.MethodDelete:
+		if !ws.requireAdmin(w, r) {
+			return
+		}
+		if !ws.advisoryStore.Remove(id) {
+			problems.Write(w, http.StatusNotFound, problems.NotFound, "Advisory not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+	}
+}
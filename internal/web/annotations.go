@@ -0,0 +1,169 @@
+package web
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/acks"
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/notes"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// annotationColumns are the columns of the CSV of notes and acknowledgements
+var annotationColumns = []string{"branch", "series", "note", "expiry"}
+
+// parseAnnotationsCSV reads a CSV of notes and acknowledgements against the dashboard cells. A
+// row with an expiry acknowledges its cell until then, with the note as reason; a row without
+// one sets the note of the cell. Every invalid row is reported, by line.
+func parseAnnotationsCSV(r io.Reader, index *packageIndex, createdBy string, now time.Time) ([]acks.Acknowledgement, []notes.Note, []string) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(annotationColumns)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, []string{fmt.Sprintf("failed to read the header: %v", err)}
+	}
+	for i, column := range annotationColumns {
+		if !strings.EqualFold(strings.TrimSpace(header[i]), column) {
+			return nil, nil, []string{fmt.Sprintf("the header must be %s", strings.Join(annotationColumns, ","))}
+		}
+	}
+
+	var ackList []acks.Acknowledgement
+	var noteList []notes.Note
+	var problems []string
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && parseErr.Err == csv.ErrFieldCount {
+				problems = append(problems, fmt.Sprintf("line %d: expected %d columns", parseErr.Line, len(annotationColumns)))
+				continue
+			}
+			problems = append(problems, err.Error())
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		fail := func(format string, args ...interface{}) {
+			problems = append(problems, fmt.Sprintf("line %d: ", line)+fmt.Sprintf(format, args...))
+		}
+
+		branch, series := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		packageName := branch
+		if !strings.HasPrefix(packageName, "nvidia-graphics-drivers-") {
+			packageName = "nvidia-graphics-drivers-" + branch
+		}
+		if _, ok := index.row(packageName, series); !ok {
+			fail("no %s/%s cell on the dashboard", branch, series)
+			continue
+		}
+
+		kind := "note"
+		if strings.TrimSpace(record[3]) != "" {
+			kind = "acknowledgement"
+		}
+		if first, ok := seen[kind+" "+packageName+"/"+series]; ok {
+			fail("the %s of %s/%s is already set on line %d", kind, branch, series, first)
+			continue
+		}
+		seen[kind+" "+packageName+"/"+series] = line
+
+		if kind == "note" {
+			note := notes.Note{Package: packageName, Series: series, Text: record[2], UpdatedAt: now, UpdatedBy: createdBy}
+			if err := notes.Validate(&note); err != nil {
+				fail("%v", err)
+				continue
+			}
+			noteList = append(noteList, note)
+			continue
+		}
+		expires, err := parseAckExpiry(record[3], now)
+		if err != nil {
+			fail("%v", err)
+			continue
+		}
+		ack := acks.Acknowledgement{Package: packageName, Series: series, Reason: record[2], Expires: expires, CreatedAt: now, CreatedBy: createdBy}
+		if err := acks.Validate(&ack, now); err != nil {
+			fail("%v", err)
+			continue
+		}
+		ackList = append(ackList, ack)
+	}
+	return ackList, noteList, problems
+}
+
+// annotationsHandler exports the notes and active acknowledgements as CSV (GET), or applies a
+// CSV of them (POST, /api/v1/annotations). An upload is applied only when every row is valid;
+// otherwise it is rejected with the problem of each invalid row.
+func (ws *WebService) annotationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		ws.exportAnnotations(w, time.Now())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !ws.requireAdmin(w, r) {
+		return
+	}
+
+	index, _, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	createdBy := ""
+	if session, ok := auth.SessionFromContext(r.Context()); ok {
+		createdBy = session.Name
+	}
+	now := time.Now()
+	ackList, noteList, rejected := parseAnnotationsCSV(r.Body, index, createdBy, now)
+	if len(rejected) > 0 {
+		problem := problems.New(http.StatusBadRequest, problems.ValidationFailed, "Invalid CSV, nothing was applied")
+		problem.Extensions = map[string]interface{}{"problems": rejected}
+		problem.Write(w)
+		return
+	}
+
+	// Every row was validated above, so neither batch can be refused halfway
+	if err := ws.ackStore.SetAll(ackList, now); err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		return
+	}
+	if err := ws.noteStore.SetAll(noteList, now); err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		return
+	}
+	ws.reapplyCellAnnotations()
+	json.NewEncoder(w).Encode(map[string]int{"acknowledgements": len(ackList), "notes": len(noteList)})
+}
+
+// exportAnnotations writes the notes and the acknowledgements active at now in the CSV format
+// the upload reads, so an export can be edited and uploaded back
+func (ws *WebService) exportAnnotations(w http.ResponseWriter, now time.Time) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="annotations.csv"`)
+	writer := csv.NewWriter(w)
+	writer.Write(annotationColumns)
+	for _, note := range ws.noteStore.List() {
+		writer.Write([]string{branchFromPackage(note.Package), note.Series, note.Text, ""})
+	}
+	for _, ack := range ws.ackStore.List(now) {
+		writer.Write([]string{branchFromPackage(ack.Package), ack.Series, ack.Reason, ack.Expires.UTC().Format(time.RFC3339)})
+	}
+	writer.Flush()
+}
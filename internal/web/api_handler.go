@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -8,18 +9,48 @@ import (
 	"strings"
 	"time"
 
+	"nvidia_driver_monitor/internal/advisories"
+	"nvidia_driver_monitor/internal/alerts"
 	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/problems"
 	"nvidia_driver_monitor/internal/stats"
+	"nvidia_driver_monitor/internal/supervise"
+	"nvidia_driver_monitor/internal/utils"
 )
 
 // APIHandler handles REST API endpoints
-type APIHandler struct{}
+type APIHandler struct {
+	advisoryStore *advisories.Store // Known bad driver/kernel combinations flagged in LRM data; optional
+	// lrmData and collector replace the global L-R-M cache and statistics collector when set
+	lrmData   func() (*lrm.LRMVerifierData, error)
+	collector *stats.StatsCollector
+	// dataSources reports the state of the data sources other than L-R-M; optional
+	dataSources func() []DataSourceStatus
+	// requireAdmin admits the requests that change state; without it they are refused
+	requireAdmin func(w http.ResponseWriter, r *http.Request) bool
+}
 
 // NewAPIHandler creates a new API handler
 func NewAPIHandler() *APIHandler {
 	return &APIHandler{}
 }
 
+// getLRMData returns the L-R-M data served by the handler
+func (h *APIHandler) getLRMData() (*lrm.LRMVerifierData, error) {
+	if h.lrmData != nil {
+		return h.lrmData()
+	}
+	return lrm.GetCachedLRMData()
+}
+
+// statsCollector returns the statistics collector served by the handler
+func (h *APIHandler) statsCollector() *stats.StatsCollector {
+	if h.collector != nil {
+		return h.collector
+	}
+	return stats.GetStatsCollector()
+}
+
 // LRMProgressHandler returns current LRM processing progress
 func (h *APIHandler) LRMProgressHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -50,7 +81,7 @@ func (h *APIHandler) LRMProgressHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := json.NewEncoder(w).Encode(progress); err != nil {
-		http.Error(w, `{"error": "Failed to encode response"}`, http.StatusInternalServerError)
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
 		return
 	}
 }
@@ -68,16 +99,19 @@ func (h *APIHandler) LRMDataHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get query parameters for filtering
-	series := r.URL.Query().Get("series")
-	status := r.URL.Query().Get("status")
-	routing := r.URL.Query().Get("routing")
+	filters := parseLRMFilters(r.URL.Query())
 	limit := r.URL.Query().Get("limit")
 	offset := r.URL.Query().Get("offset")
+	fields, err := parseFields(r, lrm.KernelLRMResult{})
+	if err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		return
+	}
 
 	// Fetch LRM data - use cached version to avoid refetching if less than 5 minutes old
-	lrmData, err := lrm.GetCachedLRMData()
+	lrmData, err := h.getLRMData()
 	if err != nil {
-		http.Error(w, `{"error": "Failed to fetch LRM data"}`, http.StatusInternalServerError)
+		problems.Write(w, http.StatusBadGateway, problems.UpstreamUnavailable, "Failed to fetch LRM data")
 		return
 	}
 
@@ -86,16 +120,7 @@ func (h *APIHandler) LRMDataHandler(w http.ResponseWriter, r *http.Request) {
 		len(lrmData.KernelResults), lrmData.TotalKernels, lrmData.SupportedLRM)
 
 	// Apply filters
-	filteredResults := lrmData.KernelResults
-	if series != "" {
-		filteredResults = filterBySeries(filteredResults, series)
-	}
-	if status != "" {
-		filteredResults = filterByStatus(filteredResults, status)
-	}
-	if routing != "" {
-		filteredResults = filterByRouting(filteredResults, routing)
-	}
+	filteredResults := filters.Apply(lrmData.KernelResults)
 
 	// Apply pagination
 	if limit != "" || offset != "" {
@@ -105,11 +130,13 @@ func (h *APIHandler) LRMDataHandler(w http.ResponseWriter, r *http.Request) {
 	// Create response
 	response := APIResponse{
 		Data: APILRMData{
-			KernelResults: filteredResults,
-			TotalKernels:  lrmData.TotalKernels,
-			SupportedLRM:  lrmData.SupportedLRM,
-			LastUpdated:   lrmData.LastUpdated,
-			IsInitialized: lrmData.IsInitialized,
+			KernelResults:   filteredResults,
+			TotalKernels:    lrmData.TotalKernels,
+			SupportedLRM:    lrmData.SupportedLRM,
+			LastUpdated:     lrmData.LastUpdated,
+			IsInitialized:   lrmData.IsInitialized,
+			Stale:           lrmData.Stale,
+			AdvisoryMatches: matchAdvisories(h.advisoryStore, filteredResults),
 		},
 		Meta: APIMeta{
 			Total:    len(lrmData.KernelResults),
@@ -117,27 +144,182 @@ func (h *APIHandler) LRMDataHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	if fields != nil {
+		kernels, err := fields.sparseKernels(filteredResults)
+		if err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+			return
+		}
+		data := map[string]interface{}{
+			"kernel_results": kernels,
+			"total_kernels":  response.Data.TotalKernels,
+			"supported_lrm":  response.Data.SupportedLRM,
+			"last_updated":   response.Data.LastUpdated,
+			"is_initialized": response.Data.IsInitialized,
+			"stale":          response.Data.Stale,
+		}
+		if len(response.Data.AdvisoryMatches) > 0 {
+			data["advisory_matches"] = response.Data.AdvisoryMatches
+		}
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "meta": response.Meta}); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+		return
+	}
+
+	for _, kernel := range filteredResults {
+		checkSchema(schemaKernel, kernel)
+	}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, `{"error": "Failed to encode response"}`, http.StatusInternalServerError)
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		return
+	}
+}
+
+// LRMStreamHandler streams the LRM data as newline-delimited JSON, one kernel per line,
+// flushing after each record (/api/v1/lrm/stream). It accepts the filters of /api/lrm.
+func (h *APIHandler) LRMStreamHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	lrmData, err := h.getLRMData()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		problems.Write(w, http.StatusBadGateway, problems.UpstreamUnavailable, "Failed to fetch LRM data")
 		return
 	}
+
+	results := parseLRMFilters(r.URL.Query()).Apply(lrmData.KernelResults)
+
+	// Dataset metadata goes in headers so every line is a kernel record
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(results)))
+	w.Header().Set("X-Last-Updated", lrmData.LastUpdated.UTC().Format(time.RFC3339))
+	w.Header().Set("X-Stale", strconv.FormatBool(lrmData.Stale))
+	streamKernels(r.Context(), w, results)
+}
+
+// streamKernels writes one JSON record per kernel, flushing after each, until done or the
+// client goes away
+func streamKernels(ctx context.Context, w http.ResponseWriter, results []lrm.KernelLRMResult) {
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for i := range results {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := encoder.Encode(&results[i]); err != nil {
+			log.Printf("LRM stream stopped after %d of %d kernels: %v", i, len(results), err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
 }
 
 // HealthHandler returns health status
 func (h *APIHandler) HealthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
+	activeAlerts := alerts.Active()
+	status := "healthy"
+	if len(activeAlerts) > 0 {
+		status = "degraded"
+	}
+
 	health := map[string]interface{}{
-		"status":  "healthy",
-		"service": "nvidia-driver-monitor",
+		"status":        status,
+		"service":       "nvidia-driver-monitor",
+		"alerts":        activeAlerts,
+		"maintenance":   alerts.ActiveMaintenance(time.Now()),
+		"loop_restarts": supervise.GetRestarts(),
 	}
 
 	if err := json.NewEncoder(w).Encode(health); err != nil {
-		http.Error(w, `{"error": "Failed to encode response"}`, http.StatusInternalServerError)
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
 		return
 	}
 }
 
+// maintenanceRequest is the body of POST /api/maintenance
+type maintenanceRequest struct {
+	Name     string    `json:"name"`
+	Reason   string    `json:"reason"`
+	Start    time.Time `json:"start"`    // Defaults to now
+	End      time.Time `json:"end"`      // Either end or duration is required
+	Duration string    `json:"duration"` // Duration string like "2h"
+}
+
+// admitAdmin writes the error of a request that needs an admin and returns false
+func (h *APIHandler) admitAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if h.requireAdmin == nil {
+		problems.Write(w, http.StatusForbidden, problems.Forbidden, "Admin endpoints are not configured")
+		return false
+	}
+	return h.requireAdmin(w, r)
+}
+
+// MaintenanceHandler lists (GET), schedules (POST) and cancels (DELETE ?id=) maintenance windows.
+// Scheduling and cancelling require an admin.
+func (h *APIHandler) MaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if (r.Method == http.MethodPost || r.Method == http.MethodDelete) && !h.admitAdmin(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		now := time.Now()
+		response := map[string]interface{}{
+			"active":    alerts.ActiveMaintenance(now),
+			"adhoc":     alerts.AdhocMaintenance(now),
+			"recurring": alerts.RecurringMaintenance(),
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+
+	case http.MethodPost:
+		var req maintenanceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid maintenance window body")
+			return
+		}
+		if req.Start.IsZero() {
+			req.Start = time.Now()
+		}
+		if req.End.IsZero() && req.Duration != "" {
+			duration, err := time.ParseDuration(req.Duration)
+			if err != nil {
+				problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid duration")
+				return
+			}
+			req.End = req.Start.Add(duration)
+		}
+		window, err := alerts.AddMaintenance(req.Name, req.Reason, req.Start, req.End)
+		if err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(window)
+
+	case http.MethodDelete:
+		if !alerts.RemoveMaintenance(r.URL.Query().Get("id")) {
+			problems.Write(w, http.StatusNotFound, problems.NotFound, "Maintenance window not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+	}
+}
+
 // RoutingsHandler returns available routing values
 func (h *APIHandler) RoutingsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -153,7 +335,7 @@ func (h *APIHandler) RoutingsHandler(w http.ResponseWriter, r *http.Request) {
 	// Get available routings
 	routings, err := lrm.GetAvailableRoutings()
 	if err != nil {
-		http.Error(w, `{"error": "Failed to fetch routing data"}`, http.StatusInternalServerError)
+		problems.Write(w, http.StatusBadGateway, problems.UpstreamUnavailable, "Failed to fetch routing data")
 		return
 	}
 
@@ -164,7 +346,74 @@ func (h *APIHandler) RoutingsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, `{"error": "Failed to encode response"}`, http.StatusInternalServerError)
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		return
+	}
+}
+
+// RoutingDetailHandler returns the archive destinations for routings.
+// /api/routings/ lists every definition, /api/routings/{name} returns a single one.
+func (h *APIHandler) RoutingDetailHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	definitions, err := lrm.GetRoutingDefinitions()
+	if err != nil {
+		problems.Write(w, http.StatusBadGateway, problems.UpstreamUnavailable, "Failed to fetch routing definitions")
+		return
+	}
+
+	// Routing names contain a slash (e.g. "ubuntu/4"), so take everything after the prefix
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/routings/"), "/")
+
+	var response interface{}
+	if name == "" {
+		response = map[string]interface{}{
+			"routings": definitions,
+			"count":    len(definitions),
+		}
+	} else {
+		definition, ok := definitions[name]
+		if !ok {
+			problems.Write(w, http.StatusNotFound, problems.NotFound, "Routing not found")
+			return
+		}
+		response = definition
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		return
+	}
+}
+
+// KernelSeriesDiagnosticsHandler returns the schema version and warnings of the last parsed kernel-series.yaml
+func (h *APIHandler) KernelSeriesDiagnosticsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	diagnostics := lrm.GetKernelSeriesDiagnostics()
+	if diagnostics == nil {
+		problems.Write(w, http.StatusNotFound, problems.NotFound, "kernel-series.yaml has not been parsed yet")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(diagnostics); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
 		return
 	}
 }
@@ -181,21 +430,21 @@ func (h *APIHandler) StatisticsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	collector := stats.GetStatsCollector()
+	collector := h.statsCollector()
 
 	// Prepare response data
 	response := map[string]interface{}{
 		"current_window":          collector.GetCurrentWindowInfo(),
 		"historical_windows":      collector.GetAllWindowsStats(),
 		"server_time":             time.Now().Format("2006-01-02 15:04:05 UTC"),
-		"window_duration_minutes": 10,
+		"window_duration_minutes": collector.GetWindowDuration().Minutes(),
 		"max_stored_windows":      collector.GetMaxWindows(),
 	}
 
 	// Encode and send response
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		log.Printf("Error encoding statistics response: %v", err)
-		http.Error(w, `{"error": "Failed to encode response"}`, http.StatusInternalServerError)
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
 		return
 	}
 }
@@ -212,6 +461,9 @@ type APILRMData struct {
 	SupportedLRM  int                   `json:"supported_lrm"`
 	LastUpdated   interface{}           `json:"last_updated"`
 	IsInitialized bool                  `json:"is_initialized"`
+	Stale         bool                  `json:"stale"`
+	// AdvisoryMatches lists the kernels with a published driver known to be broken on them
+	AdvisoryMatches []AdvisoryMatch `json:"advisory_matches,omitempty"`
 }
 
 type APIMeta struct {
@@ -255,11 +507,15 @@ func filterByStatus(results []lrm.KernelLRMResult, status string) []lrm.KernelLR
 	return filtered
 }
 
-func filterByRouting(results []lrm.KernelLRMResult, routing string) []lrm.KernelLRMResult {
+// filterByBranch keeps the kernels carrying a driver of the branch, e.g. "570" or "570-server"
+func filterByBranch(results []lrm.KernelLRMResult, branch string) []lrm.KernelLRMResult {
 	var filtered []lrm.KernelLRMResult
 	for _, result := range results {
-		if result.Routing == routing {
-			filtered = append(filtered, result)
+		for _, driver := range result.NvidiaDriverStatuses {
+			if branchFromPackage(driver.DriverName) == branch {
+				filtered = append(filtered, result)
+				break
+			}
 		}
 	}
 	return filtered
@@ -310,11 +566,29 @@ func (h *APIHandler) CacheStatusHandler(w http.ResponseWriter, r *http.Request)
 
 	// Add server timestamp
 	status["server_time"] = time.Now().Format("2006-01-02 15:04:05 UTC")
+	status["coalesced_requests"] = utils.GetCoalescedRequests()
+	status["mirrors"] = utils.MirrorStatuses()
+
+	// One list answers whether any part of the service is stale, L-R-M included
+	stale := []string{}
+	if status["initialized"] != true || status["refresh_failures"] != 0 {
+		stale = append(stale, "lrm")
+	}
+	if h.dataSources != nil {
+		sources := h.dataSources()
+		for _, source := range sources {
+			if source.Stale {
+				stale = append(stale, source.Name)
+			}
+		}
+		status["sources"] = sources
+	}
+	status["stale_sources"] = stale
 
 	// Encode and send response
 	if err := json.NewEncoder(w).Encode(status); err != nil {
 		log.Printf("Error encoding cache status response: %v", err)
-		http.Error(w, `{"error": "Failed to encode response"}`, http.StatusInternalServerError)
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
 		return
 	}
 }
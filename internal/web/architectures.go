@@ -0,0 +1,76 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/releases"
+)
+
+// arm64LagAlertName is the alert raised when the arm64 build of a server branch lags behind x86_64
+func arm64LagAlertName(branch string) string {
+	return "arm64-lag-" + branch
+}
+
+// serverArchitectures returns the per-architecture availability of each supported server branch
+// found in the datacenter releases
+func serverArchitectures(supported []releases.SupportedRelease, allBranches drivers.AllBranches) []drivers.BranchArchitectures {
+	var result []drivers.BranchArchitectures
+	for _, release := range supported {
+		if !release.IsServer {
+			continue
+		}
+		entry, ok := allBranches[strings.TrimSuffix(release.BranchName, "-server")]
+		if !ok {
+			continue
+		}
+		result = append(result, drivers.GetBranchArchitectures(release.BranchName, entry))
+	}
+	return result
+}
+
+// evaluateArchitectureAlerts raises an alert for each supported server branch whose newest
+// x86_64 release has no arm64 (SBSA) build yet
+func (ws *WebService) evaluateArchitectureAlerts(supported []releases.SupportedRelease) {
+	for _, branch := range serverArchitectures(supported, ws.getUpstreamData().allBranches) {
+		if !branch.ARM64Lag {
+			alerts.Resolve(arm64LagAlertName(branch.Branch))
+			continue
+		}
+		var x86, arm64 = "-", "none"
+		for _, latest := range branch.Latest {
+			switch latest.Architecture {
+			case drivers.ArchitectureX86:
+				x86 = latest.Version
+			case drivers.ArchitectureARM64:
+				arm64 = latest.Version
+			}
+		}
+		alerts.Fire(arm64LagAlertName(branch.Branch), alerts.SeverityWarning,
+			fmt.Sprintf("arm64 (SBSA) datacenter driver of %s lags behind x86_64: %s vs %s", branch.Branch, arm64, x86))
+	}
+}
+
+// architecturesHandler reports the per-architecture availability of the supported server branches
+func (ws *WebService) architecturesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, _, isInitialized := ws.getCachedPackages(); !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+
+	upstream := ws.getUpstreamData()
+	branches := serverArchitectures(upstream.supportedReleases, upstream.allBranches)
+	if branches == nil {
+		branches = []drivers.BranchArchitectures{}
+	}
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"branches": branches}); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
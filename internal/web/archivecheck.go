@@ -0,0 +1,154 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"nvidia_driver_monitor/internal/archive"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/pkg/debversion"
+)
+
+// Checks of the scheduled consistency check against the archive Sources indexes
+const (
+	checkArchiveNewer       = "archive-newer-than-dashboard"
+	checkArchiveOlder       = "dashboard-newer-than-archive"
+	checkArchiveUnavailable = "archive-index-unavailable"
+)
+
+// archiveCheckRetry is the delay before checking again when no dashboard data is loaded yet
+const archiveCheckRetry = 5 * time.Minute
+
+// archiveSuites returns the suites holding the published and proposed versions of the shown series
+func archiveSuites(pkgs []*PackageData, publishedPockets []string) []string {
+	pockets := append(append([]string{}, publishedPockets...), "Proposed")
+	var suites []string
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, row := range pkg.Series {
+			for _, pocket := range pockets {
+				suite := archive.Suite(row.Series, pocket)
+				if !seen[suite] {
+					seen[suite] = true
+					suites = append(suites, suite)
+				}
+			}
+		}
+	}
+	return suites
+}
+
+// compareWithArchive reports the series rows whose published or proposed version differs from
+// the Sources indexes. Suites missing from the index are not compared.
+func compareWithArchive(pkgs []*PackageData, index archive.Index, publishedPockets []string) []DataIssue {
+	var issues []DataIssue
+	for _, pkg := range pkgs {
+		for _, row := range pkg.Series {
+			published, complete := "", true
+			for _, pocket := range publishedPockets {
+				suite := archive.Suite(row.Series, pocket)
+				if _, ok := index[suite]; !ok {
+					complete = false
+					break
+				}
+				if ver := index.Version(suite, pkg.PackageName); ver != "" && (published == "" || debversion.OlderThan(published, ver)) {
+					published = ver
+				}
+			}
+			if complete {
+				issues = appendArchiveIssue(issues, pkg.PackageName, row.Series, "published", row.UpdatesSecurity, published)
+			}
+
+			proposedSuite := archive.Suite(row.Series, "Proposed")
+			if _, ok := index[proposedSuite]; ok {
+				issues = appendArchiveIssue(issues, pkg.PackageName, row.Series, "proposed", row.Proposed, index.Version(proposedSuite, pkg.PackageName))
+			}
+		}
+	}
+	return issues
+}
+
+// appendArchiveIssue compares a dashboard cell with the version found in the Sources index
+func appendArchiveIssue(issues []DataIssue, packageName, series, column, shown, indexed string) []DataIssue {
+	hasShown := isArchiveVersion(shown)
+	switch {
+	case indexed == "" && !hasShown, indexed == shown:
+		return issues
+	case indexed != "" && (!hasShown || debversion.OlderThan(shown, indexed)):
+		if !hasShown {
+			shown = "nothing"
+		}
+		return append(issues, DataIssue{
+			Check:   checkArchiveNewer,
+			Package: packageName,
+			Series:  series,
+			Message: fmt.Sprintf("Sources index has %s %s but the dashboard shows %s", column, indexed, shown),
+		})
+	default:
+		if indexed == "" {
+			indexed = "nothing"
+		}
+		return append(issues, DataIssue{
+			Check:   checkArchiveOlder,
+			Package: packageName,
+			Series:  series,
+			Message: fmt.Sprintf("dashboard shows %s %s but the Sources index has %s", column, shown, indexed),
+		})
+	}
+}
+
+// runArchiveCheck compares the cached dashboard data with the archive Sources indexes and keeps
+// the divergences for the diagnostics. It returns false when there is no data to compare yet.
+func (ws *WebService) runArchiveCheck() bool {
+	pkgs, _, isInitialized := ws.getCachedPackages()
+	if !isInitialized {
+		return false
+	}
+
+	wanted := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		wanted[pkg.PackageName] = true
+	}
+	publishedPockets := packages.PublishedPockets()
+	checkCfg := ws.config.ArchiveCheck
+
+	log.Printf("Checking dashboard data against the archive Sources indexes...")
+	index, errs := archive.FetchIndex(checkCfg.GetMirrorURL(), archiveSuites(pkgs, publishedPockets), checkCfg.GetComponents(), wanted)
+
+	issues := compareWithArchive(pkgs, index, publishedPockets)
+	for _, err := range errs {
+		issues = append(issues, DataIssue{
+			Check:   checkArchiveUnavailable,
+			Message: err.Error(),
+		})
+	}
+	log.Printf("Archive consistency check found %d divergences", len(issues))
+
+	ws.cacheMux.Lock()
+	ws.archiveIssues = issues
+	ws.archiveCheckedAt = time.Now()
+	ws.cacheMux.Unlock()
+	return true
+}
+
+// archiveCheckLoop runs the archive consistency check once the first data is loaded, then
+// at the configured interval
+func (ws *WebService) archiveCheckLoop() {
+	timer := time.NewTimer(archiveCheckRetry)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			next := ws.config.ArchiveCheck.GetInterval()
+			if !ws.runArchiveCheck() {
+				next = archiveCheckRetry
+			}
+			timer.Reset(next)
+		case <-ws.stopChan:
+			log.Printf("Stopping archive check loop...")
+			return
+		}
+	}
+}
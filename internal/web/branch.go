@@ -0,0 +1,231 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/i18n"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/releases"
+)
+
+// sparklineDays is how far back the history sparklines of the branch page reach
+const sparklineDays = 30
+
+// BranchKernel is a kernel whose linux-restricted-modules carry the branch's driver
+type BranchKernel struct {
+	Series        string `json:"series"`
+	Codename      string `json:"codename"`
+	Source        string `json:"source"`
+	Routing       string `json:"routing"`
+	KernelVersion string `json:"kernel_version"`
+	DriverVersion string `json:"driver_version"` // Driver version in the L-R-M dsc
+	Status        string `json:"status"`         // e.g. "Up to date", "Update available"
+}
+
+// SparkPoint is one observed day of a series
+type SparkPoint struct {
+	Date      string `json:"date"`
+	Published string `json:"published"`
+	Outdated  bool   `json:"outdated"`
+}
+
+// BranchSparkline is the recent published history of the branch in one series
+type BranchSparkline struct {
+	Series string       `json:"series"`
+	Points []SparkPoint `json:"points"`
+}
+
+// BranchOverview combines every data source the dashboard has for one driver branch
+type BranchOverview struct {
+	Branch      string                     `json:"branch"` // e.g. "550", "570-server"
+	PackageName string                     `json:"package_name"`
+	Release     *releases.SupportedRelease `json:"release,omitempty"`
+	// UDAReleases are the nvidia.com releases of the branch, ERDReleases the datacenter ones; newest first
+	UDAReleases []drivers.DriverEntry `json:"uda_releases"`
+	ERDReleases []drivers.DriverInfo  `json:"erd_releases"`
+	// Architectures is the per-architecture availability of server branches
+	Architectures *drivers.BranchArchitectures `json:"architectures,omitempty"`
+	Package       *PackageData                 `json:"package,omitempty"`
+	Kernels       []BranchKernel               `json:"kernels"`
+	// Bugs and CVEs are those referenced by the changelogs of the shown versions
+	Bugs    []string          `json:"bugs"`
+	CVEs    []string          `json:"cves"`
+	History []BranchSparkline `json:"history"`
+	// I386Warnings lists the i386 libraries missing or behind the source, see runI386Check;
+	// I386CheckedAt is nil until the branch has been checked
+	I386Warnings  []I386Warning `json:"i386_warnings"`
+	I386CheckedAt *time.Time    `json:"i386_checked_at,omitempty"`
+}
+
+// buildBranchOverview gathers the upstream releases, archive rows, L-R-M kernels, changelog
+// references and history of a branch. Any source may be missing.
+func buildBranchOverview(branch string, release *releases.SupportedRelease, udaEntries []drivers.DriverEntry, allBranches drivers.AllBranches,
+	pkg *PackageData, kernels []lrm.KernelLRMResult, historyStore *history.Store, now time.Time) BranchOverview {
+	overview := BranchOverview{
+		Branch:       branch,
+		PackageName:  "nvidia-graphics-drivers-" + branch,
+		Release:      release,
+		UDAReleases:  []drivers.DriverEntry{},
+		ERDReleases:  []drivers.DriverInfo{},
+		Package:      pkg,
+		Kernels:      []BranchKernel{},
+		Bugs:         []string{},
+		CVEs:         []string{},
+		History:      []BranchSparkline{},
+		I386Warnings: []I386Warning{},
+	}
+
+	// Server branches follow the datacenter releases, the others the nvidia.com ones
+	major := strings.TrimSuffix(branch, "-server")
+	if major != branch {
+		overview.ERDReleases = append(overview.ERDReleases, allBranches[major].DriverInfo...)
+		if entry, ok := allBranches[major]; ok {
+			architectures := drivers.GetBranchArchitectures(branch, entry)
+			overview.Architectures = &architectures
+		}
+		sort.SliceStable(overview.ERDReleases, func(i, j int) bool {
+			return overview.ERDReleases[i].ReleaseDate > overview.ERDReleases[j].ReleaseDate
+		})
+	} else {
+		for _, entry := range udaEntries {
+			if strings.SplitN(entry.Version, ".", 2)[0] == major {
+				overview.UDAReleases = append(overview.UDAReleases, entry)
+			}
+		}
+		sort.SliceStable(overview.UDAReleases, func(i, j int) bool {
+			return overview.UDAReleases[i].Date.After(overview.UDAReleases[j].Date)
+		})
+	}
+
+	for _, kernel := range kernels {
+		for _, driver := range kernel.NvidiaDriverStatuses {
+			if branchFromPackage(driver.DriverName) != branch {
+				continue
+			}
+			overview.Kernels = append(overview.Kernels, BranchKernel{
+				Series:        kernel.Series,
+				Codename:      kernel.Codename,
+				Source:        kernel.Source,
+				Routing:       kernel.Routing,
+				KernelVersion: kernel.SourceVersion,
+				DriverVersion: driver.DSCVersion,
+				Status:        driver.Status,
+			})
+		}
+	}
+
+	if pkg == nil {
+		return overview
+	}
+
+	bugs, cves := make(map[string]bool), make(map[string]bool)
+	for _, entry := range pkg.Changelogs {
+		for _, bug := range entry.Bugs {
+			bugs[bug] = true
+		}
+		for _, cve := range entry.CVEs {
+			cves[cve] = true
+		}
+	}
+	overview.Bugs = append(overview.Bugs, sortedKeys(bugs)...)
+	overview.CVEs = append(overview.CVEs, sortedKeys(cves)...)
+
+	if historyStore != nil {
+		since := now.AddDate(0, 0, -sparklineDays).Format(history.DateFormat)
+		for _, row := range pkg.Series {
+			sparkline := BranchSparkline{Series: row.Series, Points: []SparkPoint{}}
+			for _, obs := range historyStore.Series(pkg.PackageName, row.Series) {
+				if obs.Date > since {
+					sparkline.Points = append(sparkline.Points, SparkPoint{Date: obs.Date, Published: obs.Published, Outdated: obs.Outdated})
+				}
+			}
+			overview.History = append(overview.History, sparkline)
+		}
+	}
+
+	return overview
+}
+
+// branchHandler shows everything known about one driver branch (/branch/{name}); the branch
+// may be given as e.g. "550" or "nvidia-graphics-drivers-550", and format=json returns the data
+func (ws *WebService) branchHandler(w http.ResponseWriter, r *http.Request) {
+	locale := requestLocale(w, r, ws.config)
+
+	branch := branchFromPackage(strings.Trim(strings.TrimPrefix(r.URL.Path, "/branch/"), "/"))
+	if branch == "" {
+		http.Error(w, i18n.T(locale, "error.branch_required"), http.StatusBadRequest)
+		return
+	}
+
+	index, _, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		http.Error(w, i18n.T(locale, "error.initializing"), http.StatusServiceUnavailable)
+		return
+	}
+
+	upstream := ws.getUpstreamData()
+	var release *releases.SupportedRelease
+	for i := range upstream.supportedReleases {
+		if upstream.supportedReleases[i].BranchName == branch {
+			release = &upstream.supportedReleases[i]
+			break
+		}
+	}
+	pkg := index.byBranch[branch]
+	if release == nil && pkg == nil {
+		http.Error(w, i18n.T(locale, "error.branch_not_found"), http.StatusNotFound)
+		return
+	}
+
+	// The page does not wait for the kernel data to be loaded
+	var kernels []lrm.KernelLRMResult
+	if initialized, _ := lrm.GetCacheStatus()["initialized"].(bool); initialized {
+		if lrmData, err := lrm.GetCachedLRMData(); err == nil {
+			kernels = lrmData.KernelResults
+		}
+	}
+	overview := buildBranchOverview(branch, release, upstream.udaEntries, upstream.allBranches, pkg, kernels, ws.historyStore, time.Now())
+	if warnings, checkedAt := ws.getI386Warnings(branch); !checkedAt.IsZero() {
+		overview.I386Warnings = append(overview.I386Warnings, warnings...)
+		overview.I386CheckedAt = &checkedAt
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(overview); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	templateFile := filepath.Join(ws.templatePath, "branch.html")
+	tmpl, err := template.New("branch.html").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, requestTimezone(w, r, ws.config))).ParseFiles(templateFile)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Template parsing error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := struct {
+		BranchOverview
+		Branches       []string
+		PublishedLabel string
+		CDN            map[string]string
+	}{
+		BranchOverview: overview,
+		Branches:       index.branches,
+		PublishedLabel: publishedLabel(),
+		CDN:            GetCDNResources(ws.config),
+	}
+	renderPage(w, tmpl, templateData)
+}
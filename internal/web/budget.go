@@ -0,0 +1,108 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/budget"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// budgetCheckInterval is how often the budgets are checked and the counts persisted
+const budgetCheckInterval = time.Minute
+
+// budgetAlertName returns the alert raised for the budget of a host
+func budgetAlertName(host string) string {
+	return "budget-" + host
+}
+
+// evaluateBudgets raises or resolves the budget alert of each host with a budget
+func evaluateBudgets(tracker *budget.Tracker, now time.Time) {
+	for _, usage := range tracker.Usage(now) {
+		if usage.Limit == 0 {
+			continue
+		}
+		name := budgetAlertName(usage.Host)
+		switch threshold := tracker.Threshold(usage); {
+		case usage.Requests >= usage.Limit:
+			alerts.Fire(name, alerts.SeverityCritical,
+				fmt.Sprintf("%d of %d daily requests to %s used; automatic refreshes paused until %s",
+					usage.Requests, usage.Limit, usage.Host, budget.NextDay(now).Format("2006-01-02 15:04 UTC")))
+		case threshold > 0:
+			alerts.Fire(name, alerts.SeverityWarning,
+				fmt.Sprintf("%d of %d daily requests to %s used (over %d%%); automatic refreshes slowed down %dx",
+					usage.Requests, usage.Limit, usage.Host, threshold, tracker.Slowdown(now)))
+		default:
+			alerts.Resolve(name)
+		}
+	}
+}
+
+// budgetDelay stretches the wait before the next automatic refresh while nearing a budget,
+// and postpones it to the next budget day once one is used up
+func budgetDelay(delay time.Duration, now time.Time) time.Duration {
+	tracker := budget.Current()
+	if tracker == nil {
+		return delay
+	}
+	if tracker.Exhausted(now) {
+		wait := budget.NextDay(now).Sub(now)
+		log.Printf("Upstream request budget used up, next refresh in %v", wait.Round(time.Second))
+		return wait
+	}
+	if slowdown := tracker.Slowdown(now); slowdown > 1 {
+		log.Printf("Nearing the upstream request budget, refreshing %dx less often", slowdown)
+		return delay * time.Duration(slowdown)
+	}
+	return delay
+}
+
+// budgetLoop checks the budgets and persists the request counts every minute
+func (ws *WebService) budgetLoop() {
+	ticker := time.NewTicker(budgetCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			tracker := budget.Current()
+			if tracker == nil {
+				continue
+			}
+			now := time.Now()
+			evaluateBudgets(tracker, now)
+			if err := tracker.Save(now); err != nil {
+				log.Printf("Warning: Failed to persist budget data: %v", err)
+			}
+		case <-ws.stopChan:
+			log.Printf("Stopping budget loop...")
+			return
+		}
+	}
+}
+
+// budgetHandler reports today's upstream requests per host against their budgets
+func (ws *WebService) budgetHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	tracker := budget.Current()
+	if tracker == nil {
+		problems.Write(w, http.StatusServiceUnavailable, problems.NotConfigured, "Budgets are not configured")
+		return
+	}
+
+	now := time.Now()
+	response := map[string]interface{}{
+		"hosts":     tracker.Usage(now),
+		"slowdown":  tracker.Slowdown(now),
+		"exhausted": tracker.Exhausted(now),
+		"resets_at": budget.NextDay(now),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
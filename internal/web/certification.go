@@ -0,0 +1,213 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/certification"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// Status of the driver branch of a certified platform in the archive of its series
+const (
+	certifiedCurrent     = "current"       // The published version is up to date
+	certifiedLagging     = "lagging"       // The published version is behind upstream
+	certifiedUnpublished = "not-published" // The dashboard has no published version in the series
+	certifiedUntracked   = "untracked"     // The branch is not on the dashboard
+)
+
+// EOL status of the driver branch of a certified platform
+const (
+	branchEOLApproaching = "approaching" // Within the configured warning period of its EOL
+	branchEOLPast        = "past"
+)
+
+// CertifiedPlatform is a certified platform with the state of its driver branch
+type CertifiedPlatform struct {
+	certification.Platform
+	Published string `json:"published,omitempty"` // Published source version on the dashboard
+	Status    string `json:"status"`
+	EOL       string `json:"eol,omitempty"`        // Branch EOL date, when configured
+	EOLStatus string `json:"eol_status,omitempty"` // "approaching" or "past"
+}
+
+// CertifiedBranch sums up the certified platforms relying on a driver branch
+type CertifiedBranch struct {
+	Branch    string   `json:"branch"`
+	Platforms int      `json:"platforms"`
+	Series    []string `json:"series"`
+	EOL       string   `json:"eol,omitempty"`
+	EOLStatus string   `json:"eol_status,omitempty"`
+	Lagging   []string `json:"lagging,omitempty"` // Series where certified hardware runs a lagging or unpublished branch
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// branchEOL returns the configured EOL date of a branch; server branches share the date of
+// their desktop branch unless they have their own
+func branchEOL(cfg *config.CertificationConfig, branch string) (time.Time, bool) {
+	value, ok := cfg.BranchEOL[branch]
+	if !ok {
+		value, ok = cfg.BranchEOL[strings.TrimSuffix(branch, "-server")]
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	date, err := time.Parse("2006-01-02", value)
+	return date, err == nil
+}
+
+// crossReferenceCertification compares the branches of the certified platforms with the
+// dashboard rows of their series and the configured branch EOL dates
+func crossReferenceCertification(index *packageIndex, platforms []certification.Platform, cfg *config.CertificationConfig, now time.Time) ([]CertifiedPlatform, []CertifiedBranch) {
+	result := []CertifiedPlatform{}
+	byBranch := make(map[string]*CertifiedBranch)
+	var order []string
+	for _, platform := range platforms {
+		certified := CertifiedPlatform{Platform: platform, Status: certifiedUntracked}
+		if pkg, ok := index.byBranch[platform.Branch]; ok {
+			row, _ := index.row(pkg.PackageName, platform.Series)
+			switch {
+			case !isArchiveVersion(row.UpdatesSecurity):
+				certified.Status = certifiedUnpublished
+			case row.UpdatesColor == "danger":
+				certified.Published = row.UpdatesSecurity
+				certified.Status = certifiedLagging
+			default:
+				certified.Published = row.UpdatesSecurity
+				certified.Status = certifiedCurrent
+			}
+		}
+		if eol, ok := branchEOL(cfg, platform.Branch); ok {
+			certified.EOL = eol.Format("2006-01-02")
+			switch {
+			case !now.Before(eol):
+				certified.EOLStatus = branchEOLPast
+			case now.Add(cfg.GetEOLWarning()).After(eol):
+				certified.EOLStatus = branchEOLApproaching
+			}
+		}
+		result = append(result, certified)
+
+		summary, ok := byBranch[platform.Branch]
+		if !ok {
+			summary = &CertifiedBranch{Branch: platform.Branch, Series: []string{}, EOL: certified.EOL, EOLStatus: certified.EOLStatus}
+			byBranch[platform.Branch] = summary
+			order = append(order, platform.Branch)
+		}
+		summary.Platforms++
+		if !contains(summary.Series, platform.Series) {
+			summary.Series = append(summary.Series, platform.Series)
+		}
+		if (certified.Status == certifiedLagging || certified.Status == certifiedUnpublished) && !contains(summary.Lagging, platform.Series) {
+			summary.Lagging = append(summary.Lagging, platform.Series)
+		}
+	}
+
+	sort.Strings(order)
+	branches := make([]CertifiedBranch, 0, len(order))
+	for _, branch := range order {
+		summary := byBranch[branch]
+		switch summary.EOLStatus {
+		case branchEOLPast:
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("reached its EOL on %s", summary.EOL))
+		case branchEOLApproaching:
+			eol, _ := time.Parse("2006-01-02", summary.EOL)
+			days := int(math.Ceil(eol.Sub(now).Hours() / 24))
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("reaches its EOL on %s, in %d days", summary.EOL, days))
+		}
+		if len(summary.Lagging) > 0 {
+			summary.Warnings = append(summary.Warnings, "lagging in the archive of "+strings.Join(summary.Lagging, ", "))
+		}
+		branches = append(branches, *summary)
+	}
+	return result, branches
+}
+
+// runCertificationCheck reads the certified platforms from the certification API or file. A
+// failure keeps the platforms of the previous check and is reported by the endpoint.
+func (ws *WebService) runCertificationCheck() {
+	certCfg := &ws.config.Certification
+	log.Printf("Reading the certified hardware platforms...")
+	var platforms []certification.Platform
+	var err error
+	if certCfg.File != "" {
+		platforms, err = certification.ReadFile(certCfg.File)
+	} else {
+		platforms, err = certification.Fetch(certCfg.URL)
+	}
+	if err != nil {
+		log.Printf("Warning: Could not read the certified platforms: %v", err)
+	} else {
+		log.Printf("Certification check read %d platforms certified with an NVIDIA driver", len(platforms))
+	}
+
+	ws.cacheMux.Lock()
+	defer ws.cacheMux.Unlock()
+	ws.certificationError = ""
+	if err != nil {
+		ws.certificationError = err.Error()
+	} else {
+		ws.certifiedPlatforms = platforms
+	}
+	ws.certificationCheckedAt = time.Now()
+}
+
+// certificationCheckLoop reads the certified platforms at startup, then at the configured interval
+func (ws *WebService) certificationCheckLoop() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			ws.runCertificationCheck()
+			timer.Reset(ws.config.Certification.GetInterval())
+		case <-ws.stopChan:
+			log.Printf("Stopping certification check loop...")
+			return
+		}
+	}
+}
+
+// certificationHandler lists the certified platforms and the state of the driver branches they
+// rely on (/api/v1/certification)
+func (ws *WebService) certificationHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ws.cacheMux.RLock()
+	platforms, checkedAt, checkErr := ws.certifiedPlatforms, ws.certificationCheckedAt, ws.certificationError
+	ws.cacheMux.RUnlock()
+	index, _, isInitialized := ws.getPackageIndex()
+	if checkedAt.IsZero() || !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Certified platforms have not been checked yet")
+		return
+	}
+
+	query := r.URL.Query()
+	var selected []certification.Platform
+	for _, platform := range platforms {
+		if branch := query.Get("branch"); branch != "" && platform.Branch != branch {
+			continue
+		}
+		if series := query.Get("series"); series != "" && platform.Series != series {
+			continue
+		}
+		selected = append(selected, platform)
+	}
+	certified, branches := crossReferenceCertification(index, selected, &ws.config.Certification, time.Now())
+
+	response := map[string]interface{}{"platforms": certified, "branches": branches, "checked_at": checkedAt}
+	if checkErr != "" {
+		response["error"] = checkErr
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
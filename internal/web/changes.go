@@ -0,0 +1,238 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+const (
+	// maxRecentChanges is how many refresh change summaries are kept
+	maxRecentChanges = 20
+	// indexRecentChanges is how many summaries the index page shows
+	indexRecentChanges = 5
+	// summaryChangeItems is how many changes the one-line summary names before eliding the rest
+	summaryChangeItems = 3
+)
+
+// RowChange is a version cell of the status table that changed between two refreshes
+type RowChange struct {
+	Package string `json:"package"`
+	Branch  string `json:"branch"`
+	Series  string `json:"series"`
+	Column  string `json:"column"` // "updates", "proposed", "upstream", "component" or "section"
+	From    string `json:"from"`   // "-" when the cell was empty
+	To      string `json:"to"`
+	// ReleaseNotes is what the new archive version's changelog adds, when changelogs are enabled
+	ReleaseNotes *packages.ReleaseNotes `json:"release_notes,omitempty"`
+}
+
+// String describes the change, e.g. "550/jammy updates 550.127.05→550.127.08" or
+// "570/noble component restricted→multiverse"
+func (c RowChange) String() string {
+	return fmt.Sprintf("%s/%s %s %s→%s", c.Branch, c.Series, c.Column, c.From, c.To)
+}
+
+// ChangeSummary lists the cells changed by one refresh
+type ChangeSummary struct {
+	Time    time.Time   `json:"time"`
+	Rows    int         `json:"rows"`    // Number of series rows with a change
+	Summary string      `json:"summary"` // e.g. "3 rows changed: 550/jammy updates 550.127.05→550.127.08, …"
+	Changes []RowChange `json:"changes"`
+}
+
+// changeReleaseNotes diffs the changelogs of the new and replaced archive versions of a cell.
+// The replaced version's entry comes from the previous generation, or from the current one
+// when another cell still shows it.
+func changeReleaseNotes(previous, current *PackageData, from, to string) *packages.ReleaseNotes {
+	if !isArchiveVersion(to) || current.Changelogs[to] == nil {
+		return nil
+	}
+	var replaced *packages.ChangelogEntry
+	if isArchiveVersion(from) {
+		replaced = previous.Changelogs[from]
+		if replaced == nil {
+			replaced = current.Changelogs[from]
+		}
+	}
+	return packages.DiffChangelogEntries(replaced, current.Changelogs[to])
+}
+
+// diffPackages returns the version cells that differ between two generations of packages, and
+// the component and section moves of published versions, which matter for licensing reviews.
+// Packages missing from either side, or served stale, are not compared.
+func diffPackages(previous, current []*PackageData) []RowChange {
+	previousPackages := make(map[string]*PackageData, len(previous))
+	previousRows := make(map[string]map[string]SeriesData, len(previous))
+	for _, pkg := range previous {
+		rows := make(map[string]SeriesData, len(pkg.Series))
+		for _, row := range pkg.Series {
+			rows[row.Series] = row
+		}
+		previousRows[pkg.PackageName] = rows
+		previousPackages[pkg.PackageName] = pkg
+	}
+
+	var changes []RowChange
+	for _, pkg := range current {
+		rows, ok := previousRows[pkg.PackageName]
+		if !ok || pkg.StaleSince != nil {
+			continue
+		}
+		branch := branchFromPackage(pkg.PackageName)
+		for _, row := range pkg.Series {
+			before := rows[row.Series]
+			for _, cell := range []struct{ column, from, to string }{
+				{"updates", before.UpdatesSecurity, row.UpdatesSecurity},
+				{"proposed", before.Proposed, row.Proposed},
+				{"upstream", before.UpstreamVersion, row.UpstreamVersion},
+			} {
+				from, to := orDash(cell.from), orDash(cell.to)
+				if from == to {
+					continue
+				}
+				change := RowChange{Package: pkg.PackageName, Branch: branch, Series: row.Series, Column: cell.column, From: from, To: to}
+				if cell.column == "updates" || cell.column == "proposed" {
+					change.ReleaseNotes = changeReleaseNotes(previousPackages[pkg.PackageName], pkg, from, to)
+				}
+				changes = append(changes, change)
+			}
+			// A publication appearing or going away is a version change, not a move
+			for _, move := range []struct{ column, from, to string }{
+				{"component", before.Component, row.Component},
+				{"section", before.Section, row.Section},
+			} {
+				if move.from == "" || move.to == "" || move.from == move.to {
+					continue
+				}
+				changes = append(changes, RowChange{Package: pkg.PackageName, Branch: branch, Series: row.Series, Column: move.column, From: move.from, To: move.to})
+			}
+		}
+	}
+	return changes
+}
+
+// summarizeChanges builds the summary of one refresh's changes
+func summarizeChanges(changes []RowChange, now time.Time) ChangeSummary {
+	rows := make(map[string]bool)
+	items := make([]string, 0, summaryChangeItems+1)
+	for i, change := range changes {
+		rows[change.Package+"/"+change.Series] = true
+		if i < summaryChangeItems {
+			items = append(items, change.String())
+		}
+	}
+	if len(changes) > summaryChangeItems {
+		items = append(items, "…")
+	}
+
+	noun := "rows"
+	if len(rows) == 1 {
+		noun = "row"
+	}
+	return ChangeSummary{
+		Time:    now,
+		Rows:    len(rows),
+		Summary: fmt.Sprintf("%d %s changed: %s", len(rows), noun, strings.Join(items, ", ")),
+		Changes: changes,
+	}
+}
+
+// updateNoticeName is the notice name for a new archive version of a series
+func updateNoticeName(change RowChange) string {
+	return fmt.Sprintf("updated-%s-%s-%s", change.Package, change.Series, change.Column)
+}
+
+// announceUpdates sends a one-time notice carrying the release notes of each new archive version,
+// when enabled. Notices held back by a maintenance window are not retried; the change feed keeps
+// the release notes.
+func (ws *WebService) announceUpdates(changes []RowChange) {
+	if ws.config == nil || !ws.config.Changelog.NotifyUpdates {
+		return
+	}
+	for _, change := range changes {
+		if change.ReleaseNotes == nil {
+			continue
+		}
+		message := fmt.Sprintf("%s: %d new changelog items", change.String(), len(change.ReleaseNotes.Added))
+		if len(change.ReleaseNotes.Bugs) > 0 {
+			message += ", LP: #" + strings.Join(change.ReleaseNotes.Bugs, ", #")
+		}
+		alerts.NotifyWithReleaseNotes(updateNoticeName(change), alerts.SeverityInfo, message, change.ReleaseNotes)
+	}
+}
+
+// recordChanges keeps the summary of what a refresh changed; refreshes changing nothing and
+// the first load are not recorded
+func (ws *WebService) recordChanges(previous, current []*PackageData, now time.Time) {
+	changes := diffPackages(previous, current)
+	if len(changes) == 0 {
+		return
+	}
+	summary := summarizeChanges(changes, now)
+	ws.announceUpdates(changes)
+
+	ws.cacheMux.Lock()
+	defer ws.cacheMux.Unlock()
+	ws.recentChanges = append([]ChangeSummary{summary}, ws.recentChanges...)
+	if len(ws.recentChanges) > maxRecentChanges {
+		ws.recentChanges = ws.recentChanges[:maxRecentChanges]
+	}
+}
+
+// getRecentChanges returns up to limit change summaries, newest first
+func (ws *WebService) getRecentChanges(limit int) []ChangeSummary {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	if limit > len(ws.recentChanges) {
+		limit = len(ws.recentChanges)
+	}
+	return append([]ChangeSummary{}, ws.recentChanges[:limit]...)
+}
+
+// recentChangesHandler returns the changes of the last refreshes (/api/changes/recent?limit=N)
+func (ws *WebService) recentChangesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit := 10
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxRecentChanges {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, fmt.Sprintf("limit must be between 1 and %d", maxRecentChanges))
+			return
+		}
+		limit = parsed
+	}
+
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"changes": ws.getRecentChanges(limit)}); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// filterChangesForView keeps the changes of the branches and series shown in a view
+func filterChangesForView(summaries []ChangeSummary, view *config.ViewConfig) []ChangeSummary {
+	if view == nil {
+		return summaries
+	}
+	var filtered []ChangeSummary
+	for _, summary := range summaries {
+		var changes []RowChange
+		for _, change := range summary.Changes {
+			if view.IncludesBranch(change.Branch) && view.IncludesSeries(change.Series) {
+				changes = append(changes, change)
+			}
+		}
+		if len(changes) > 0 {
+			filtered = append(filtered, summarizeChanges(changes, summary.Time))
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,173 @@
+package web
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"nvidia_driver_monitor/internal/cloudimages"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/pkg/debversion"
+)
+
+// Status of a driver shipped in a cloud image against the archive
+const (
+	cloudImageCurrent     = "current"          // The image ships the published version
+	cloudImageRebuild     = "rebuild-updates"  // A newer version is published: the next image rebuild picks it up
+	cloudImageProposed    = "proposed-pending" // Current with the published version, a newer one is in proposed
+	cloudImageUntracked   = "untracked"        // The branch is not on the dashboard
+	cloudImageUnpublished = "not-published"    // The dashboard has no published version in the series
+)
+
+// CloudImageDriver is a driver binary shipped in a cloud image compared with the archive
+type CloudImageDriver struct {
+	Binary    string `json:"binary"`
+	Branch    string `json:"branch"`
+	Version   string `json:"version"`             // Version in the image
+	Published string `json:"published,omitempty"` // Published source version on the dashboard
+	Proposed  string `json:"proposed,omitempty"`
+	Status    string `json:"status"`
+}
+
+// CloudImageReport lists the drivers shipped in the current build of a cloud image
+type CloudImageReport struct {
+	cloudimages.Image
+	URL     string             `json:"url"`
+	Drivers []CloudImageDriver `json:"drivers"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// cloudImageSeries returns the series shown on the dashboard that still receive uploads
+func cloudImageSeries(pkgs []*PackageData) []string {
+	shown := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, row := range pkg.Series {
+			if row.Removed == "" && row.Availability != availabilitySeriesEOL && row.Availability != availabilitySeriesUnknown {
+				shown[row.Series] = true
+			}
+		}
+	}
+	var series []string
+	for _, name := range packages.SeriesOrder() {
+		if shown[name] {
+			series = append(series, name)
+		}
+	}
+	return series
+}
+
+// compareCloudImage compares the driver binaries of an image with the dashboard rows of its series
+func compareCloudImage(index *packageIndex, series string, binaries []cloudimages.Binary) []CloudImageDriver {
+	drivers := []CloudImageDriver{}
+	for _, binary := range binaries {
+		driver := CloudImageDriver{Binary: binary.Name, Branch: binary.Branch, Version: binary.Version, Status: cloudImageUntracked}
+		if pkg, ok := index.byBranch[binary.Branch]; ok {
+			row, _ := index.row(pkg.PackageName, series)
+			if isArchiveVersion(row.UpdatesSecurity) {
+				driver.Published = row.UpdatesSecurity
+			}
+			if isArchiveVersion(row.Proposed) {
+				driver.Proposed = row.Proposed
+			}
+			switch {
+			case driver.Published == "":
+				driver.Status = cloudImageUnpublished
+			case debversion.OlderThan(binary.Version, driver.Published):
+				driver.Status = cloudImageRebuild
+			case driver.Proposed != "" && debversion.OlderThan(binary.Version, driver.Proposed):
+				driver.Status = cloudImageProposed
+			default:
+				driver.Status = cloudImageCurrent
+			}
+		}
+		drivers = append(drivers, driver)
+	}
+	return drivers
+}
+
+// runCloudImageCheck reads the manifests of the cloud images of the shown series and compares
+// their drivers with the archive. It returns false when there is no data to compare yet.
+func (ws *WebService) runCloudImageCheck() bool {
+	index, _, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		return false
+	}
+
+	imagesCfg := ws.config.CloudImages
+	log.Printf("Checking the drivers shipped in the cloud images...")
+	var reports []CloudImageReport
+	for _, series := range cloudImageSeries(index.packages) {
+		for _, variant := range imagesCfg.GetVariants() {
+			for _, arch := range imagesCfg.GetArchitectures() {
+				image := cloudimages.Image{Series: series, Variant: variant, Arch: arch}
+				report := CloudImageReport{Image: image, URL: cloudimages.ManifestURL(imagesCfg.GetBaseURL(), image), Drivers: []CloudImageDriver{}}
+				binaries, err := cloudimages.FetchManifest(imagesCfg.GetBaseURL(), image)
+				if err != nil {
+					report.Error = err.Error()
+				} else {
+					report.Drivers = compareCloudImage(index, series, binaries)
+				}
+				reports = append(reports, report)
+			}
+		}
+	}
+	log.Printf("Cloud image check read %d manifests", len(reports))
+
+	ws.cacheMux.Lock()
+	ws.cloudImages = reports
+	ws.cloudImagesCheckedAt = time.Now()
+	ws.cacheMux.Unlock()
+	return true
+}
+
+// cloudImageCheckLoop runs the cloud image check once the first data is loaded, then at the
+// configured interval
+func (ws *WebService) cloudImageCheckLoop() {
+	timer := time.NewTimer(archiveCheckRetry)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			next := ws.config.CloudImages.GetInterval()
+			if !ws.runCloudImageCheck() {
+				next = archiveCheckRetry
+			}
+			timer.Reset(next)
+		case <-ws.stopChan:
+			log.Printf("Stopping cloud image check loop...")
+			return
+		}
+	}
+}
+
+// cloudImagesHandler returns the drivers shipped in the current cloud images (/api/v1/cloud-images)
+func (ws *WebService) cloudImagesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ws.cacheMux.RLock()
+	reports, checkedAt := ws.cloudImages, ws.cloudImagesCheckedAt
+	ws.cacheMux.RUnlock()
+	if checkedAt.IsZero() {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Cloud images have not been checked yet")
+		return
+	}
+
+	if series := r.URL.Query().Get("series"); series != "" {
+		var filtered []CloudImageReport
+		for _, report := range reports {
+			if report.Series == series {
+				filtered = append(filtered, report)
+			}
+		}
+		reports = filtered
+	}
+	if reports == nil {
+		reports = []CloudImageReport{}
+	}
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"images": reports, "checked_at": checkedAt}); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
@@ -0,0 +1,76 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// filterPackagesByComponent keeps the series rows whose published or proposed version is in
+// the given archive component, dropping packages left without rows
+func filterPackagesByComponent(pkgs []*PackageData, component string) []*PackageData {
+	if component == "" {
+		return pkgs
+	}
+	var filtered []*PackageData
+	for _, pkg := range pkgs {
+		scoped := *pkg
+		scoped.Series = nil
+		for _, row := range pkg.Series {
+			if strings.EqualFold(row.Component, component) || strings.EqualFold(row.ProposedComponent, component) {
+				scoped.Series = append(scoped.Series, row)
+			}
+		}
+		if len(scoped.Series) > 0 {
+			filtered = append(filtered, &scoped)
+		}
+	}
+	return filtered
+}
+
+// componentTransitions returns the component and section moves of a package's published version
+// in a series, or in all its series when series is empty, ordered by date
+func (ws *WebService) componentTransitions(packageName, series string) []history.ComponentTransition {
+	transitions := []history.ComponentTransition{}
+	if ws.historyStore == nil {
+		return transitions
+	}
+	seriesNames := ws.historyStore.SeriesNames(packageName)
+	if series != "" {
+		seriesNames = []string{series}
+	}
+	for _, name := range seriesNames {
+		transitions = append(transitions, ws.historyStore.ComponentTransitions(packageName, name)...)
+	}
+	sort.SliceStable(transitions, func(i, j int) bool { return transitions[i].Date < transitions[j].Date })
+	return transitions
+}
+
+// componentTransitionsHandler lists the days on which a package's published version moved to
+// another archive component or section (/api/v1/history/components?package={name}&series={series})
+func (ws *WebService) componentTransitionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	packageName := r.URL.Query().Get("package")
+	if packageName == "" {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Package name is required")
+		return
+	}
+
+	response := map[string]interface{}{
+		"package":     packageName,
+		"transitions": ws.componentTransitions(packageName, r.URL.Query().Get("series")),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
@@ -0,0 +1,90 @@
+package web
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/sru"
+)
+
+// cutoffAlertName is the alert raised when an outdated branch approaches its SRU cutoff
+func cutoffAlertName(branch string) string {
+	return "sru-cutoff-" + branch
+}
+
+// needsUpload reports whether a row is outdated with nothing fixing it in proposed yet
+func needsUpload(row *SeriesData) bool {
+	if row.Removed != "" {
+		return false
+	}
+	return (row.UpdatesColor == "danger" && row.ProposedColor != "success") || row.Availability == availabilityNotUploaded
+}
+
+// targetCutoff returns the first SRU cycle whose cutoff is after the upstream release and not
+// yet passed, i.e. the cycle an upload made now would still make
+func targetCutoff(cycles *sru.SRUCycles, releaseDate string, now time.Time) *sru.SRUCycle {
+	if _, err := time.Parse("2006-01-02", releaseDate); err != nil {
+		return nil
+	}
+	after := now.AddDate(0, 0, -1).Format("2006-01-02")
+	if releaseDate > after {
+		after = releaseDate
+	}
+	return cycles.GetMinimumCutoffAfterDate(after)
+}
+
+// evaluateCutoffAlerts fires an alert for each branch covered by a cutoff rule whose target
+// SRU cutoff is within the rule's days while some series still needs an upload to proposed
+func (ws *WebService) evaluateCutoffAlerts(pkgs []*PackageData, now time.Time) {
+	if ws.config == nil || len(ws.config.Alerts.CutoffRules) == 0 {
+		return
+	}
+	today := now.UTC().Truncate(24 * time.Hour)
+	sruCycles := ws.getUpstreamData().sruCycles
+	for _, pkg := range pkgs {
+		branch := branchFromPackage(pkg.PackageName)
+		rule := ws.config.Alerts.CutoffRuleFor(branch)
+		if rule == nil {
+			continue
+		}
+
+		var cycle *sru.SRUCycle
+		var series []string
+		if sruCycles != nil {
+			for i := range pkg.Series {
+				row := &pkg.Series[i]
+				if !needsUpload(row) {
+					continue
+				}
+				target := targetCutoff(sruCycles, row.ReleaseDate, now)
+				if target == nil {
+					continue
+				}
+				if cycle == nil || target.CutoffDate < cycle.CutoffDate {
+					cycle, series = target, nil
+				}
+				if target.CutoffDate == cycle.CutoffDate {
+					series = append(series, row.Series)
+				}
+			}
+		}
+
+		if cycle == nil {
+			alerts.Resolve(cutoffAlertName(branch))
+			continue
+		}
+		cutoff, err := time.Parse("2006-01-02", cycle.CutoffDate)
+		days := int(cutoff.Sub(today).Hours() / 24)
+		if err != nil || days > rule.DaysBefore {
+			alerts.Resolve(cutoffAlertName(branch))
+			continue
+		}
+		sort.Strings(series)
+		alerts.Fire(cutoffAlertName(branch), rule.GetSeverity(),
+			fmt.Sprintf("%s is not uploaded to proposed for %s, %d days before the SRU cutoff of %s on %s",
+				pkg.PackageName, strings.Join(series, ", "), days, cycle.Name, cycle.CutoffDate))
+	}
+}
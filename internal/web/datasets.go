@@ -0,0 +1,388 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/sru"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Sub-datasets of a refresh. Each is loaded on its own so the dashboard serves whichever
+// loaded while the others are retried.
+const (
+	datasetUDA      = "uda"      // nvidia.com driver releases
+	datasetERD      = "erd"      // Datacenter (server) driver releases
+	datasetSRU      = "sru"      // Kernel SRU cycles
+	datasetPackages = "packages" // Archive versions from Launchpad
+	datasetTegra    = "tegra"    // L4T releases, only loaded when the Tegra branches are enabled
+)
+
+// datasetNames lists the sub-datasets in load order
+func datasetNames() []string {
+	names := []string{datasetUDA, datasetERD, datasetSRU}
+	if releases.TegraEnabled() {
+		names = append(names, datasetTegra)
+	}
+	return append(names, datasetPackages)
+}
+
+// Load states of a sub-dataset
+const (
+	DatasetPending = "pending" // Not loaded yet
+	DatasetLoaded  = "loaded"
+	DatasetFailed  = "failed" // The last load failed; previous data, if any, is still served
+)
+
+// datasetRetryDelay is the first wait before failed sub-datasets are loaded again
+const datasetRetryDelay = 30 * time.Second
+
+// DatasetStatus is the load state of one sub-dataset
+type DatasetStatus struct {
+	Name     string     `json:"name"`
+	State    string     `json:"state"`
+	Error    string     `json:"error,omitempty"`
+	LoadedAt *time.Time `json:"loaded_at,omitempty"` // Last successful load
+	Failures int        `json:"failures,omitempty"`  // Consecutive failed loads
+}
+
+// setDatasetResult records the outcome of loading a sub-dataset
+func (ws *WebService) setDatasetResult(name string, err error) {
+	ws.cacheMux.Lock()
+	defer ws.cacheMux.Unlock()
+
+	if ws.datasets == nil {
+		ws.datasets = make(map[string]*DatasetStatus)
+	}
+	status, ok := ws.datasets[name]
+	if !ok {
+		status = &DatasetStatus{Name: name}
+		ws.datasets[name] = status
+	}
+	if err != nil {
+		status.State = DatasetFailed
+		status.Error = err.Error()
+		status.Failures++
+		return
+	}
+	now := time.Now()
+	status.State = DatasetLoaded
+	status.Error = ""
+	status.LoadedAt = &now
+	status.Failures = 0
+}
+
+// getDatasets returns the state of every sub-dataset; those never attempted are pending
+func (ws *WebService) getDatasets() []DatasetStatus {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+
+	names := datasetNames()
+	statuses := make([]DatasetStatus, 0, len(names))
+	for _, name := range names {
+		if status, ok := ws.datasets[name]; ok {
+			statuses = append(statuses, *status)
+		} else {
+			statuses = append(statuses, DatasetStatus{Name: name, State: DatasetPending})
+		}
+	}
+	return statuses
+}
+
+// unavailableDatasets returns the sub-datasets that are pending or failed
+func (ws *WebService) unavailableDatasets() []DatasetStatus {
+	var unavailable []DatasetStatus
+	for _, status := range ws.getDatasets() {
+		if status.State != DatasetLoaded {
+			unavailable = append(unavailable, status)
+		}
+	}
+	return unavailable
+}
+
+// datasetsError summarizes the failed sub-datasets, or returns nil when all loaded
+func datasetsError(statuses []DatasetStatus) error {
+	var failed []string
+	for _, status := range statuses {
+		if status.State == DatasetFailed {
+			failed = append(failed, fmt.Sprintf("%s (%s)", status.Name, status.Error))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("failed to load %s", strings.Join(failed, ", "))
+}
+
+// DataSourceStatus is the freshness of one source of the served data, reported by /api/cache-status
+type DataSourceStatus struct {
+	Name        string     `json:"name"`
+	LastRefresh *time.Time `json:"last_refresh,omitempty"` // Last successful refresh
+	LastError   string     `json:"last_error,omitempty"`
+	Items       int        `json:"items"`
+	StaleItems  int        `json:"stale_items,omitempty"`  // Items served from an earlier refresh
+	NextRefresh *time.Time `json:"next_refresh,omitempty"` // Next scheduled refresh
+	Stale       bool       `json:"stale"`                  // Never loaded, failed or serving stale items
+}
+
+// mergeDatasets combines the load states of sub-datasets into one source: refreshed when the
+// oldest of them was, failing with the errors of each
+func mergeDatasets(name string, statuses []DatasetStatus, items int) DataSourceStatus {
+	source := DataSourceStatus{Name: name, Items: items}
+	var errs []string
+	loaded := true
+	for _, status := range statuses {
+		if status.Error != "" {
+			errs = append(errs, fmt.Sprintf("%s: %s", status.Name, status.Error))
+		}
+		if status.LoadedAt == nil {
+			loaded = false
+		} else if source.LastRefresh == nil || status.LoadedAt.Before(*source.LastRefresh) {
+			source.LastRefresh = status.LoadedAt
+		}
+	}
+	if !loaded {
+		source.LastRefresh = nil
+	}
+	source.LastError = strings.Join(errs, "; ")
+	source.Stale = source.LastRefresh == nil || source.LastError != ""
+	return source
+}
+
+// setNextRefresh records when dataRefreshLoop refreshes the data next
+func (ws *WebService) setNextRefresh(at time.Time) {
+	ws.cacheMux.Lock()
+	defer ws.cacheMux.Unlock()
+	ws.nextRefreshAt = at
+}
+
+// dataSources reports the freshness of the packages, SRU cycles, upstream driver releases and
+// history store. All of them are refreshed together by dataRefreshLoop.
+func (ws *WebService) dataSources() []DataSourceStatus {
+	byName := make(map[string]DatasetStatus)
+	for _, status := range ws.getDatasets() {
+		byName[status.Name] = status
+	}
+	pkgs, _, _ := ws.getCachedPackages()
+
+	ws.cacheMux.RLock()
+	var next *time.Time
+	if !ws.nextRefreshAt.IsZero() {
+		at := ws.nextRefreshAt
+		next = &at
+	}
+	cycles := 0
+	if ws.sruCycles != nil {
+		cycles = len(ws.sruCycles.Cycles)
+	}
+	upstream := len(ws.udaEntries) + len(ws.allBranches) + len(ws.tegraEntries)
+	var packageErrors []string
+	for _, pkgErr := range ws.cache.PackageErrors {
+		packageErrors = append(packageErrors, fmt.Sprintf("%s: %s", pkgErr.PackageName, pkgErr.Error))
+	}
+	ws.cacheMux.RUnlock()
+
+	packagesSource := mergeDatasets("packages", []DatasetStatus{byName[datasetPackages]}, len(pkgs))
+	for _, pkg := range pkgs {
+		if pkg.StaleSince != nil {
+			packagesSource.StaleItems++
+		}
+	}
+	if len(packageErrors) > 0 && packagesSource.LastError == "" {
+		packagesSource.LastError = strings.Join(packageErrors, "; ")
+	}
+	packagesSource.Stale = packagesSource.Stale || packagesSource.StaleItems > 0 || packagesSource.LastError != ""
+
+	upstreamDatasets := []DatasetStatus{byName[datasetUDA], byName[datasetERD]}
+	if releases.TegraEnabled() {
+		upstreamDatasets = append(upstreamDatasets, byName[datasetTegra])
+	}
+	sources := []DataSourceStatus{
+		packagesSource,
+		mergeDatasets("sru", []DatasetStatus{byName[datasetSRU]}, cycles),
+		mergeDatasets("upstream", upstreamDatasets, upstream),
+	}
+
+	if ws.historyStore != nil {
+		// Observations are recorded after each refresh of the packages
+		history := DataSourceStatus{Name: "history", Items: ws.historyStore.Len()}
+		recordedAt, lastErr := ws.historyStore.LastRecord()
+		if !recordedAt.IsZero() {
+			history.LastRefresh = &recordedAt
+		}
+		history.LastError = lastErr
+		history.Stale = history.LastRefresh == nil || lastErr != ""
+		sources = append(sources, history)
+	}
+	for i := range sources {
+		sources[i].NextRefresh = next
+	}
+	return sources
+}
+
+// upstreamData is the upstream data the packages are generated from. Each field is replaced
+// on refresh rather than modified, so a copy stays consistent without holding the lock.
+type upstreamData struct {
+	supportedReleases []releases.SupportedRelease
+	udaEntries        []drivers.DriverEntry
+	tegraEntries      []drivers.DriverEntry
+	allBranches       drivers.AllBranches
+	sruCycles         *sru.SRUCycles
+}
+
+// getUpstreamData returns the current upstream data
+func (ws *WebService) getUpstreamData() upstreamData {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	return upstreamData{
+		supportedReleases: ws.supportedReleases,
+		udaEntries:        ws.udaEntries,
+		tegraEntries:      ws.tegraEntries,
+		allBranches:       ws.allBranches,
+		sruCycles:         ws.sruCycles,
+	}
+}
+
+// loadUDA fetches the nvidia.com releases of the supported branch majors. On failure the
+// previous releases are kept.
+func (ws *WebService) loadUDA(branchMajors []string) {
+	udaEntries, err := drivers.GetNvidiaDriverEntries(ws.config, branchMajors)
+	ws.setDatasetResult(datasetUDA, err)
+	if err != nil {
+		log.Printf("Warning: Failed to get UDA entries: %v", err)
+		return
+	}
+	ws.cacheMux.Lock()
+	ws.udaEntries = udaEntries
+	ws.cacheMux.Unlock()
+}
+
+// loadERD fetches the datacenter driver releases. On failure the previous releases are kept.
+func (ws *WebService) loadERD() {
+	_, allBranches, err := drivers.GetLatestServerDriverVersions(ws.config)
+	ws.setDatasetResult(datasetERD, err)
+	ws.cacheMux.Lock()
+	defer ws.cacheMux.Unlock()
+	if err != nil {
+		log.Printf("Warning: Failed to get server driver versions: %v", err)
+		if ws.allBranches == nil {
+			ws.allBranches = make(drivers.AllBranches)
+		}
+		return
+	}
+	ws.allBranches = allBranches
+}
+
+// loadSRU fetches the SRU cycles. On failure the previous cycles are kept, or estimated
+// cycles are used until the first successful load.
+func (ws *WebService) loadSRU() {
+	sruCycles, err := sru.FetchSRUCycles()
+	ws.setDatasetResult(datasetSRU, err)
+	if err != nil {
+		log.Printf("Warning: Failed to fetch SRU cycles: %v", err)
+		ws.cacheMux.Lock()
+		if ws.sruCycles == nil {
+			log.Printf("Using fallback SRU cycles with estimated dates")
+			ws.sruCycles = sru.CreateFallbackSRUCycles()
+		}
+		ws.cacheMux.Unlock()
+		return
+	}
+	for _, warning := range sruCycles.Warnings {
+		log.Printf("Warning: sru-cycle.yaml: %s", warning)
+	}
+	sruCycles.AddPredictedCycles()
+	ws.cacheMux.Lock()
+	ws.sruCycles = sruCycles
+	ws.cacheMux.Unlock()
+}
+
+// loadTegra fetches the L4T releases. On failure the previous releases are kept.
+func (ws *WebService) loadTegra() {
+	tegraEntries, err := drivers.GetTegraReleases(ws.config)
+	ws.setDatasetResult(datasetTegra, err)
+	if err != nil {
+		log.Printf("Warning: Failed to get L4T releases: %v", err)
+		return
+	}
+	ws.cacheMux.Lock()
+	ws.tegraEntries = tegraEntries
+	ws.cacheMux.Unlock()
+}
+
+// readSupportedReleases reads the supported releases the dashboard tracks
+func (ws *WebService) readSupportedReleases() ([]releases.SupportedRelease, error) {
+	supportedReleases, err := releases.ReadSupportedReleases(ws.supportedReleasesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read supported releases: %v", err)
+	}
+	return releases.EnabledReleases(supportedReleases), nil
+}
+
+// retryFailedDatasets loads the failed sub-datasets again and regenerates the packages when
+// any of them recovered or the packages themselves failed
+func (ws *WebService) retryFailedDatasets() error {
+	ws.refreshMux.Lock()
+	defer ws.refreshMux.Unlock()
+
+	supportedReleases, err := ws.readSupportedReleases()
+	if err != nil {
+		return err
+	}
+
+	recovered := false
+	for _, status := range ws.unavailableDatasets() {
+		switch status.Name {
+		case datasetUDA:
+			ws.loadUDA(releases.GetUniqueBranchMajors(supportedReleases))
+		case datasetERD:
+			ws.loadERD()
+		case datasetSRU:
+			ws.loadSRU()
+		case datasetTegra:
+			ws.loadTegra()
+		case datasetPackages:
+			recovered = true
+			continue
+		}
+		for _, retried := range ws.getDatasets() {
+			if retried.Name == status.Name && retried.State == DatasetLoaded {
+				log.Printf("Dataset %s loaded after %d failures", status.Name, status.Failures)
+				recovered = true
+			}
+		}
+	}
+	if recovered {
+		ws.generateAllPackages(supportedReleases)
+	}
+	return datasetsError(ws.getDatasets())
+}
+
+// initialLoadLoop performs the first refresh, then retries the sub-datasets that failed with
+// exponential backoff until every one has loaded. Later refreshes are left to dataRefreshLoop.
+// With a peer configured, its snapshot is used instead when fresh enough.
+func (ws *WebService) initialLoadLoop() {
+	if ws.primeFromPeer(time.Now()) {
+		log.Printf("Initial data load completed from peer")
+		return
+	}
+	err := ws.refreshData()
+	for failures := 1; err != nil; failures++ {
+		delay := utils.Backoff(failures, datasetRetryDelay, ws.maxBackoff())
+		log.Printf("Initial data load incomplete: %v; retrying in %v", err, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+			err = ws.retryFailedDatasets()
+		case <-ws.stopChan:
+			timer.Stop()
+			return
+		}
+	}
+	log.Printf("Initial data load completed")
+}
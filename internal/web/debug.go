@@ -0,0 +1,77 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/debuglog"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// debugRequest switches on debug logging of modules for a duration, e.g.
+// {"modules": ["lrm"], "duration": "30m"}
+type debugRequest struct {
+	Modules  []string `json:"modules"`
+	Duration string   `json:"duration"` // Defaults to debug.default_duration
+}
+
+// debugHandler shows (GET), switches on (PUT) or off (DELETE) the debug logging of modules
+// (/api/v1/debug). Debugging switches itself off after the requested duration. With OIDC login
+// only admins may change it, as for every other write.
+func (ws *WebService) debugHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	debugConfig := config.DefaultConfig().Debug
+	if ws.config != nil {
+		debugConfig = ws.config.Debug
+	}
+
+	var status debuglog.Status
+	switch r.Method {
+	case http.MethodGet:
+		status = debuglog.CurrentStatus()
+	case http.MethodPut:
+		if !ws.requireAdmin(w, r) {
+			return
+		}
+		var req debugRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid debug request body")
+			return
+		}
+		duration := debugConfig.GetDefaultDuration()
+		if req.Duration != "" {
+			parsed, err := time.ParseDuration(req.Duration)
+			if err != nil || parsed <= 0 {
+				problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid duration")
+				return
+			}
+			duration = parsed
+		}
+		if max := debugConfig.GetMaxDuration(); duration > max {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, fmt.Sprintf("duration must not exceed %s", max))
+			return
+		}
+		var err error
+		status, err = debuglog.Enable(req.Modules, duration)
+		if err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+			return
+		}
+	case http.MethodDelete:
+		if !ws.requireAdmin(w, r) {
+			return
+		}
+		status = debuglog.Disable()
+	default:
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
@@ -0,0 +1,155 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/seeds"
+)
+
+// SeedReport is the restricted seed of a series and the dashboard package of its default branch
+type SeedReport struct {
+	seeds.Seed
+	URL     string `json:"url"`
+	Package string `json:"package,omitempty"` // Empty when the default branch is not on the dashboard
+	Error   string `json:"error,omitempty"`
+}
+
+// defaultBranchAlertName is the alert raised while the default branch of a series is outdated
+func defaultBranchAlertName(series string) string {
+	return "default-branch-outdated-" + series
+}
+
+// defaultBranches returns the default branch of each series from the last seed check
+func (ws *WebService) defaultBranches() map[string]string {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	return ws.seedDefaults
+}
+
+// applyDefaultBranch flags the rows of a package whose branch is the default one of their series
+func applyDefaultBranch(defaults map[string]string, packageName string, seriesData []SeriesData) {
+	branch := branchFromPackage(packageName)
+	for i := range seriesData {
+		seriesData[i].DefaultBranch = defaults[seriesData[i].Series] == branch
+	}
+}
+
+// evaluateDefaultBranchAlerts raises an alert for each series whose default branch is outdated,
+// as that is the branch most users have installed
+func (ws *WebService) evaluateDefaultBranchAlerts(pkgs []*PackageData) {
+	outdated := make(map[string]string)
+	for _, pkg := range pkgs {
+		for _, row := range pkg.Series {
+			if row.DefaultBranch && row.UpdatesColor == "danger" {
+				outdated[row.Series] = fmt.Sprintf("%s, the default driver of %s, is outdated: %s published, %s upstream",
+					pkg.PackageName, row.Series, row.UpdatesSecurity, row.UpstreamVersion)
+			}
+		}
+	}
+	for series := range ws.defaultBranches() {
+		if message, ok := outdated[series]; ok {
+			alerts.Fire(defaultBranchAlertName(series), alerts.SeverityWarning, message)
+		} else {
+			alerts.Resolve(defaultBranchAlertName(series))
+		}
+	}
+}
+
+// runSeedsCheck reads the restricted seeds of the shown series to find their default branch,
+// then flags its rows and re-evaluates the alerts. It returns false when there is no data yet.
+func (ws *WebService) runSeedsCheck() bool {
+	index, _, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		return false
+	}
+
+	base := ws.config.Seeds.GetBaseURL()
+	log.Printf("Checking the default driver branches in the seeds...")
+	var reports []SeedReport
+	defaults := make(map[string]string)
+	for _, series := range cloudImageSeries(index.packages) {
+		report := SeedReport{Seed: seeds.Seed{Series: series}, URL: seeds.SeedURL(base, series)}
+		seed, err := seeds.FetchSeed(base, series)
+		switch {
+		case err != nil:
+			report.Error = err.Error()
+		case seed.DefaultBranch == "":
+			report.Seed = *seed
+			report.Error = "no NVIDIA driver is seeded"
+		default:
+			report.Seed = *seed
+			defaults[series] = seed.DefaultBranch
+			if pkg, ok := index.byBranch[seed.DefaultBranch]; ok {
+				report.Package = pkg.PackageName
+			}
+		}
+		reports = append(reports, report)
+	}
+	log.Printf("Seed check found the default branch of %d of %d series", len(defaults), len(reports))
+
+	ws.cacheMux.Lock()
+	ws.seedReports = reports
+	ws.seedDefaults = defaults
+	ws.seedsCheckedAt = time.Now()
+	ws.cacheMux.Unlock()
+
+	ws.reapplyCellAnnotations()
+	pkgs, _, _ := ws.getCachedPackages()
+	ws.evaluateDefaultBranchAlerts(pkgs)
+	return true
+}
+
+// seedsCheckLoop runs the seed check once the first data is loaded, then at the configured interval
+func (ws *WebService) seedsCheckLoop() {
+	timer := time.NewTimer(archiveCheckRetry)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			next := ws.config.Seeds.GetInterval()
+			if !ws.runSeedsCheck() {
+				next = archiveCheckRetry
+			}
+			timer.Reset(next)
+		case <-ws.stopChan:
+			log.Printf("Stopping seed check loop...")
+			return
+		}
+	}
+}
+
+// seedsHandler returns the default driver branch seeded in each series (/api/v1/seeds)
+func (ws *WebService) seedsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ws.cacheMux.RLock()
+	reports, checkedAt := ws.seedReports, ws.seedsCheckedAt
+	ws.cacheMux.RUnlock()
+	if checkedAt.IsZero() {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Seeds have not been checked yet")
+		return
+	}
+
+	if series := r.URL.Query().Get("series"); series != "" {
+		var filtered []SeedReport
+		for _, report := range reports {
+			if report.Series == series {
+				filtered = append(filtered, report)
+			}
+		}
+		reports = filtered
+	}
+	if reports == nil {
+		reports = []SeedReport{}
+	}
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"seeds": reports, "checked_at": checkedAt}); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
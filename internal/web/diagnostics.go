@@ -0,0 +1,229 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"nvidia_driver_monitor/internal/i18n"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/sru"
+	"nvidia_driver_monitor/pkg/debversion"
+)
+
+// Checks of the validation pass run after each refresh
+const (
+	checkProposedOlder   = "proposed-older-than-published"
+	checkFutureUpstream  = "upstream-date-in-future"
+	checkMissingSeries   = "supported-series-missing"
+	checkDuplicateBranch = "duplicate-branch"
+	checkSRUEntryInvalid = "sru-cycle-entry-invalid"
+	checkSRUEstimated    = "sru-cycles-estimated"
+)
+
+// DataIssue is an internal inconsistency of the dashboard data, usually pointing at a data bug
+type DataIssue struct {
+	Check   string `json:"check"`
+	Package string `json:"package,omitempty"`
+	Series  string `json:"series,omitempty"`
+	Message string `json:"message"`
+}
+
+// validateDashboard checks freshly generated packages and the supported releases they were
+// built from for inconsistencies
+func validateDashboard(pkgs []*PackageData, supportedReleases []releases.SupportedRelease, now time.Time) []DataIssue {
+	var issues []DataIssue
+
+	index := newPackageIndex(pkgs)
+	for _, pkg := range pkgs {
+		for _, row := range pkg.Series {
+			if isArchiveVersion(row.UpdatesSecurity) && isArchiveVersion(row.Proposed) &&
+				debversion.OlderThan(row.Proposed, row.UpdatesSecurity) {
+				issues = append(issues, DataIssue{
+					Check:   checkProposedOlder,
+					Package: pkg.PackageName,
+					Series:  row.Series,
+					Message: fmt.Sprintf("proposed %s is older than published %s", row.Proposed, row.UpdatesSecurity),
+				})
+			}
+		}
+	}
+
+	today := now.Format("2006-01-02")
+	seen := make(map[string]bool)
+	for i := range supportedReleases {
+		release := &supportedReleases[i]
+		packageName := release.PackageName()
+		if seen[release.BranchName] {
+			issues = append(issues, DataIssue{
+				Check:   checkDuplicateBranch,
+				Package: packageName,
+				Message: fmt.Sprintf("branch %s is listed more than once in the supported releases", release.BranchName),
+			})
+			continue
+		}
+		seen[release.BranchName] = true
+
+		// Dates are YYYY-MM-DD, so they compare as strings
+		if release.DatePublished != "" && release.DatePublished > today {
+			issues = append(issues, DataIssue{
+				Check:   checkFutureUpstream,
+				Package: packageName,
+				Message: fmt.Sprintf("upstream %s is dated %s, in the future", release.CurrentUpstreamVersion, release.DatePublished),
+			})
+		}
+
+		// Packages that failed or are not in the archive at all are already shown as such
+		pkg, ok := index.byName[packageName]
+		if !ok || !inArchive(pkg) {
+			continue
+		}
+		for _, series := range packages.SeriesOrder() {
+			if !release.Supports(series) {
+				continue
+			}
+			row, _ := index.row(packageName, series)
+			if isArchiveVersion(row.UpdatesSecurity) || isArchiveVersion(row.Proposed) {
+				continue
+			}
+			message := fmt.Sprintf("supported in %s but not published there", series)
+			if row.Removed != "" {
+				message = fmt.Sprintf("supported in %s but %s", series, row.Removed)
+			}
+			issues = append(issues, DataIssue{
+				Check:   checkMissingSeries,
+				Package: packageName,
+				Series:  series,
+				Message: message,
+			})
+		}
+	}
+	return issues
+}
+
+// isArchiveVersion reports whether a table cell holds a version rather than a placeholder
+func isArchiveVersion(cell string) bool {
+	return cell != "" && cell != "-" && cell != "N/A"
+}
+
+// inArchive reports whether any series of the package has a published or proposed version
+func inArchive(pkg *PackageData) bool {
+	for _, row := range pkg.Series {
+		if isArchiveVersion(row.UpdatesSecurity) || isArchiveVersion(row.Proposed) {
+			return true
+		}
+	}
+	return false
+}
+
+// sruCycleIssues reports the skipped entries of sru-cycle.yaml, and whether the SRU cycles
+// shown are estimates because the file could not be loaded
+func sruCycleIssues(cycles *sru.SRUCycles) []DataIssue {
+	if cycles == nil {
+		return nil
+	}
+	var issues []DataIssue
+	if cycles.Estimated {
+		issues = append(issues, DataIssue{
+			Check:   checkSRUEstimated,
+			Message: "sru-cycle.yaml could not be loaded; SRU cycle dates are estimated",
+		})
+	}
+	for _, warning := range cycles.Warnings {
+		issues = append(issues, DataIssue{Check: checkSRUEntryInvalid, Message: "sru-cycle.yaml: " + warning})
+	}
+	return issues
+}
+
+// getCachedIssues returns the inconsistencies found by the last refresh followed by the
+// problems of the SRU cycle data, the divergences found by the last archive consistency check,
+// the problems found by the last Release file check and the untracked packages found by the
+// last discovery scan
+func (ws *WebService) getCachedIssues() ([]DataIssue, time.Time, bool) {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+
+	sruIssues := sruCycleIssues(ws.sruCycles)
+	issues := make([]DataIssue, 0, len(ws.cache.Issues)+len(sruIssues)+len(ws.archiveIssues)+len(ws.releaseIssues)+len(ws.discoveryIssues))
+	issues = append(issues, ws.cache.Issues...)
+	issues = append(issues, sruIssues...)
+	issues = append(issues, ws.archiveIssues...)
+	issues = append(issues, ws.releaseIssues...)
+	issues = append(issues, ws.discoveryIssues...)
+	return issues, ws.cache.LastUpdated, ws.cache.IsInitialized
+}
+
+// getArchiveCheckedAt returns when the archive consistency check last ran; zero if it never did
+func (ws *WebService) getArchiveCheckedAt() time.Time {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	return ws.archiveCheckedAt
+}
+
+// diagnosticsHandler returns the inconsistencies found by the last refresh (/api/diagnostics)
+func (ws *WebService) diagnosticsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	issues, lastUpdated, isInitialized := ws.getCachedIssues()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	response := map[string]interface{}{
+		"issues":       issues,
+		"count":        len(issues),
+		"last_updated": lastUpdated,
+	}
+	if checkedAt := ws.getArchiveCheckedAt(); !checkedAt.IsZero() {
+		response["archive_checked_at"] = checkedAt
+	}
+	if checkedAt := ws.getReleaseCheckedAt(); !checkedAt.IsZero() {
+		response["release_checked_at"] = checkedAt
+	}
+	if checkedAt := ws.getDiscoveryCheckedAt(); !checkedAt.IsZero() {
+		response["discovery_checked_at"] = checkedAt
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// diagnosticsPageHandler renders the inconsistencies found by the last refresh (/diagnostics)
+func (ws *WebService) diagnosticsPageHandler(w http.ResponseWriter, r *http.Request) {
+	locale := requestLocale(w, r, ws.config)
+	issues, lastUpdated, isInitialized := ws.getCachedIssues()
+	if !isInitialized {
+		http.Error(w, i18n.T(locale, "error.initializing"), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	templateFile := filepath.Join(ws.templatePath, "diagnostics.html")
+	tmpl, err := template.New("diagnostics.html").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, requestTimezone(w, r, ws.config))).ParseFiles(templateFile)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Template parsing error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := struct {
+		Issues             []DataIssue
+		LastUpdated        time.Time
+		ArchiveCheckedAt   time.Time
+		ReleaseCheckedAt   time.Time
+		DiscoveryCheckedAt time.Time
+		CDN                map[string]string
+	}{
+		Issues:             issues,
+		LastUpdated:        lastUpdated,
+		ArchiveCheckedAt:   ws.getArchiveCheckedAt(),
+		ReleaseCheckedAt:   ws.getReleaseCheckedAt(),
+		DiscoveryCheckedAt: ws.getDiscoveryCheckedAt(),
+		CDN:                GetCDNResources(ws.config),
+	}
+	renderPage(w, tmpl, templateData)
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/discovery"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// checkUntrackedPackage is reported by the discovery scan for NVIDIA related source packages
+// the dashboard does not show
+const checkUntrackedPackage = "untracked-package"
+
+// discoveryIssues converts the untracked packages found by a discovery scan
+func discoveryIssues(found []discovery.Package) []DataIssue {
+	issues := make([]DataIssue, 0, len(found))
+	for _, pkg := range found {
+		issues = append(issues, DataIssue{
+			Check:   checkUntrackedPackage,
+			Package: pkg.Name,
+			Message: fmt.Sprintf("%s %s (%s) is published in %s but not tracked", pkg.Name, pkg.Version, pkg.Component, strings.Join(pkg.Series, ", ")),
+		})
+	}
+	return issues
+}
+
+// runDiscovery searches the archive for NVIDIA related source packages that neither the
+// dashboard nor the supported releases know, so that new packaging is noticed. It returns
+// false when there is no data to compare with yet.
+func (ws *WebService) runDiscovery(now time.Time) bool {
+	index, _, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		return false
+	}
+	discoveryCfg := &ws.config.Discovery
+
+	supportedReleases := ws.getUpstreamData().supportedReleases
+	tracked := make(map[string]bool, len(index.byName)+len(supportedReleases))
+	for name := range index.byName {
+		tracked[name] = true
+	}
+	for i := range supportedReleases {
+		tracked[supportedReleases[i].PackageName()] = true
+	}
+
+	log.Printf("Scanning the archive for untracked NVIDIA packages...")
+	found, requests, err := discovery.Scan(utils.HTTPGetWithRetry, ws.config.URLs.Launchpad.PublishedSourcesAPI,
+		discoveryCfg.GetSearch(), discoveryCfg.GetPatterns(), discoveryCfg.Ignore, tracked, packages.SeriesOrder())
+	if err != nil {
+		// Keep the previous findings rather than reporting every package as gone
+		log.Printf("Discovery scan failed after %d requests: %v", requests, err)
+		return true
+	}
+	log.Printf("Discovery scan found %d untracked packages in %d requests", len(found), requests)
+
+	ws.cacheMux.Lock()
+	ws.discoveryIssues = discoveryIssues(found)
+	ws.discoveryCheckedAt = now
+	ws.cacheMux.Unlock()
+	return true
+}
+
+// getDiscoveryCheckedAt returns when the discovery scan last succeeded; zero if it never did
+func (ws *WebService) getDiscoveryCheckedAt() time.Time {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	return ws.discoveryCheckedAt
+}
+
+// discoveryLoop runs the discovery scan once the first data is loaded, then at the configured
+// interval
+func (ws *WebService) discoveryLoop() {
+	timer := time.NewTimer(archiveCheckRetry)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			next := ws.config.Discovery.GetInterval()
+			if !ws.runDiscovery(time.Now()) {
+				next = archiveCheckRetry
+			}
+			timer.Reset(next)
+		case <-ws.stopChan:
+			log.Printf("Stopping discovery scan loop...")
+			return
+		}
+	}
+}
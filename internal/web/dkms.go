@@ -0,0 +1,97 @@
+package web
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"nvidia_driver_monitor/internal/dkms"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// publishedBranches maps each series to the driver branches with a published version in it
+func publishedBranches(pkgs []*PackageData) map[string][]string {
+	branches := make(map[string][]string)
+	for _, pkg := range pkgs {
+		branch := strings.TrimPrefix(pkg.PackageName, "nvidia-graphics-drivers-")
+		for _, row := range pkg.Series {
+			if row.Removed != "" || row.UpdatesSecurity == "-" || row.UpdatesSecurity == "N/A" {
+				continue
+			}
+			branches[row.Series] = append(branches[row.Series], branch)
+		}
+	}
+	return branches
+}
+
+// lrmKernels returns the kernels with a known source version from the L-R-M data
+func lrmKernels(data *lrm.LRMVerifierData) []dkms.Kernel {
+	var kernels []dkms.Kernel
+	for _, result := range data.KernelResults {
+		if dkms.KernelVersion(result.SourceVersion) == "" {
+			continue
+		}
+		kernels = append(kernels, dkms.Kernel{Series: result.Codename, Source: result.Source, Version: result.SourceVersion})
+	}
+	return kernels
+}
+
+// dkmsMatrixHandler checks the published driver branches against the kernels of each series
+// (/api/dkms/matrix?series={series}&flagged=true)
+func (ws *WebService) dkmsMatrixHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	compatFile := "data/dkms-compat.json"
+	if ws.config != nil {
+		compatFile = ws.config.DKMS.GetCompatFile()
+	}
+	ranges, err := dkms.LoadCompat(compatFile)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		problems.Write(w, http.StatusServiceUnavailable, problems.NotConfigured, "DKMS compatibility data is not available")
+		return
+	}
+
+	pkgs, _, isInitialized := ws.getCachedPackages()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	lrmData, err := lrm.GetCachedLRMData()
+	if err != nil {
+		problems.Write(w, http.StatusServiceUnavailable, problems.UpstreamUnavailable, "Kernel data is not available")
+		return
+	}
+
+	series := r.URL.Query().Get("series")
+	onlyFlagged := r.URL.Query().Get("flagged") == "true"
+	findings := []dkms.Finding{}
+	flagged := 0
+	for _, finding := range dkms.Matrix(ranges, lrmKernels(lrmData), publishedBranches(pkgs)) {
+		if series != "" && finding.Series != series {
+			continue
+		}
+		if finding.Flagged {
+			flagged++
+		} else if onlyFlagged {
+			continue
+		}
+		findings = append(findings, finding)
+	}
+
+	response := map[string]interface{}{
+		"findings": findings,
+		"flagged":  flagged,
+		"stale":    lrmData.Stale,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
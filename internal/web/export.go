@@ -0,0 +1,216 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nvidia_driver_monitor/internal/acks"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/notes"
+)
+
+// staticPage describes a single file produced by the static site export
+type staticPage struct {
+	Path    string
+	Handler http.HandlerFunc
+	Query   string
+}
+
+// newOfflineService creates a service for the modes that load the data once and exit, without
+// starting the server or any background loop
+func newOfflineService(cfg *config.Config, templatePath, supportedReleasesPath string) *WebService {
+	applyGlobalConfig(cfg)
+
+	return &WebService{
+		cache: &CachedData{
+			AllPackages:   make([]*PackageData, 0),
+			IsInitialized: false,
+		},
+		stopChan:              make(chan bool),
+		config:                cfg,
+		templatePath:          templatePath,
+		supportedReleasesPath: supportedReleasesPath,
+		noteStore:             notes.NewStore(cfg.Notes.GetDataFile()),
+		ackStore:              acks.NewStore(cfg.Acks.GetDataFile()),
+	}
+}
+
+// loadOnce loads the data of an offline service. It fails only when the nvidia.com releases or
+// the archive versions could not be loaded; the datacenter releases and SRU cycles can be missed.
+func (ws *WebService) loadOnce(mode string) error {
+	log.Printf("%s: loading package data...", mode)
+	if err := ws.refreshData(); err != nil {
+		for _, status := range ws.getDatasets() {
+			if status.State != DatasetLoaded && (status.Name == datasetUDA || status.Name == datasetPackages) {
+				return fmt.Errorf("failed to load package data: %v", err)
+			}
+		}
+		log.Printf("Warning: %s continues with partial data: %v", mode, err)
+	}
+	return nil
+}
+
+// ExportStaticSite renders the whole dashboard into outDir as self-contained
+// HTML and JSON files that can be hosted on any static file server.
+// Data is loaded synchronously and no background goroutines are started.
+func ExportStaticSite(cfg *config.Config, templatePath, supportedReleasesPath, outDir string) error {
+	ws := newOfflineService(cfg, templatePath, supportedReleasesPath)
+
+	if err := ws.loadOnce("Static export"); err != nil {
+		return err
+	}
+
+	lrmAvailable := true
+	log.Printf("Static export: loading L-R-M data...")
+	if err := lrm.InitializeLRMCache(); err != nil {
+		log.Printf("Warning: L-R-M data unavailable, skipping L-R-M pages: %v", err)
+		lrmAvailable = false
+	}
+
+	if err := os.MkdirAll(filepath.Join(outDir, "api"), 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %v", err)
+	}
+
+	allPackages, _, _ := ws.getCachedPackages()
+	apiHandler := NewAPIHandler()
+
+	pages := []staticPage{
+		{Path: "index.html", Handler: ws.indexHandler},
+		{Path: "statistics.html", Handler: ws.statisticsPageHandler},
+		{Path: "api/packages.json", Handler: ws.apiHandler},
+		{Path: "api/statistics.json", Handler: apiHandler.StatisticsHandler},
+	}
+	for _, pkg := range allPackages {
+		pages = append(pages,
+			staticPage{Path: staticPackagePage(pkg.PackageName), Handler: ws.packageHandler, Query: "name=" + pkg.PackageName},
+			staticPage{Path: staticPackageJSON(pkg.PackageName), Handler: ws.apiHandler, Query: "package=" + pkg.PackageName},
+		)
+	}
+	if lrmAvailable {
+		lrmHandler := NewLRMHandler(ws.templatePath, ws.config)
+		pages = append(pages,
+			staticPage{Path: "l-r-m-verifier.html", Handler: lrmHandler.ServeHTTP},
+			staticPage{Path: "api/lrm.json", Handler: apiHandler.LRMDataHandler},
+			staticPage{Path: "api/lrm-progress.json", Handler: apiHandler.LRMProgressHandler},
+			staticPage{Path: "api/routings.json", Handler: apiHandler.RoutingDetailHandler},
+		)
+	}
+
+	rewriter := newStaticLinkRewriter(allPackages)
+
+	for _, page := range pages {
+		body, err := renderStaticPage(page)
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %v", page.Path, err)
+		}
+		if strings.HasSuffix(page.Path, ".html") {
+			body = []byte(rewriter.Replace(string(body)))
+		}
+		if err := os.WriteFile(filepath.Join(outDir, page.Path), body, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", page.Path, err)
+		}
+		log.Printf("Static export: wrote %s", page.Path)
+	}
+
+	if sruCycles := ws.getUpstreamData().sruCycles; sruCycles != nil {
+		data, err := json.MarshalIndent(sruCycles.Cycles, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal SRU cycles: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(outDir, "api", "sru-cycles.json"), data, 0644); err != nil {
+			return fmt.Errorf("failed to write SRU cycles: %v", err)
+		}
+	}
+
+	if err := copyStaticAssets("static", filepath.Join(outDir, "static"), rewriter); err != nil {
+		return fmt.Errorf("failed to copy static assets: %v", err)
+	}
+
+	log.Printf("Static export completed: %d pages written to %s", len(pages), outDir)
+	return nil
+}
+
+// renderStaticPage runs a handler against an in-memory recorder and returns the body
+func renderStaticPage(page staticPage) ([]byte, error) {
+	target := "/"
+	if page.Query != "" {
+		target += "?" + page.Query
+	}
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+
+	page.Handler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		return nil, fmt.Errorf("handler returned status %d: %s", rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+	return rec.Body.Bytes(), nil
+}
+
+// staticPackagePage returns the exported HTML file name for a package
+func staticPackagePage(packageName string) string {
+	return "package-" + packageName + ".html"
+}
+
+// staticPackageJSON returns the exported JSON file name for a package
+func staticPackageJSON(packageName string) string {
+	return "api/package-" + packageName + ".json"
+}
+
+// newStaticLinkRewriter maps server routes used by templates to exported file names
+func newStaticLinkRewriter(allPackages []*PackageData) *strings.Replacer {
+	pairs := []string{
+		`href="/static/`, `href="static/`,
+		`src="/static/`, `src="static/`,
+		`href="/l-r-m-verifier"`, `href="l-r-m-verifier.html"`,
+		`href="/statistics"`, `href="statistics.html"`,
+		`href="/api"`, `href="api/packages.json"`,
+		`href="/"`, `href="index.html"`,
+		`'/api/lrm/progress'`, `'api/lrm-progress.json'`,
+		`'/api/lrm'`, `'api/lrm.json'`,
+		`'/api/statistics'`, `'api/statistics.json'`,
+		`'/api/routings/'`, `'api/routings.json'`,
+	}
+	for _, pkg := range allPackages {
+		pairs = append(pairs,
+			`href="/package?name=`+pkg.PackageName+`"`, `href="`+staticPackagePage(pkg.PackageName)+`"`,
+			`href="/api?package=`+pkg.PackageName+`"`, `href="`+staticPackageJSON(pkg.PackageName)+`"`,
+			`data-src="/api?package=`+pkg.PackageName+`"`, `data-src="`+staticPackageJSON(pkg.PackageName)+`"`,
+		)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// copyStaticAssets copies the static asset tree, rewriting links in scripts
+func copyStaticAssets(srcDir, dstDir string, rewriter *strings.Replacer) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dstDir, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(path, ".js") {
+			data = []byte(rewriter.Replace(string(data)))
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
@@ -0,0 +1,42 @@
+package web
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStaticLinkRewriter(t *testing.T) {
+	rewriter := newStaticLinkRewriter([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-580"},
+	})
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`<a href="/">Back</a>`, `<a href="index.html">Back</a>`},
+		{`<link href="/static/css/ubuntu-theme.css">`, `<link href="static/css/ubuntu-theme.css">`},
+		{`<a href="/l-r-m-verifier">`, `<a href="l-r-m-verifier.html">`},
+		{`<a href="/api">`, `<a href="api/packages.json">`},
+		{`<a href="/api?package=nvidia-graphics-drivers-580">`, `<a href="api/package-nvidia-graphics-drivers-580.json">`},
+		{`<details data-src="/api?package=nvidia-graphics-drivers-580">`, `<details data-src="api/package-nvidia-graphics-drivers-580.json">`},
+		{`fetch('/api/lrm');`, `fetch('api/lrm.json');`},
+		{`fetch('/api/lrm/progress', {})`, `fetch('api/lrm-progress.json', {})`},
+	}
+
+	for _, test := range tests {
+		result := rewriter.Replace(test.input)
+		if result != test.expected {
+			t.Errorf("Replace(%s) = %s, expected %s", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestStaticPackagePaths(t *testing.T) {
+	if page := staticPackagePage("nvidia-graphics-drivers-570-server"); page != "package-nvidia-graphics-drivers-570-server.html" {
+		t.Errorf("Unexpected package page path: %s", page)
+	}
+	if path := staticPackageJSON("nvidia-graphics-drivers-570"); !strings.HasPrefix(path, "api/") {
+		t.Errorf("Package JSON should live under api/, got %s", path)
+	}
+}
@@ -0,0 +1,44 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/faults"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// faultsHandler lists (GET), injects (POST) and clears (DELETE, ?stage= for a single stage)
+// the faults of the refresh pipeline (/api/v1/testing/faults). It is only registered in
+// testing mode.
+func (ws *WebService) faultsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var fault config.FaultConfig
+		if err := json.NewDecoder(r.Body).Decode(&fault); err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid JSON")
+			return
+		}
+		if err := faults.Inject(fault.Stage, fault.Mode); err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if stage := r.URL.Query().Get("stage"); stage != "" {
+			faults.Clear(stage)
+		} else {
+			faults.Reset()
+		}
+	default:
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"faults": faults.Active(), "stages": faults.Stages()}); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
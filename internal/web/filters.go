@@ -0,0 +1,176 @@
+package web
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/i18n"
+)
+
+// Row statuses the dashboard can be filtered by
+const (
+	filterStatusOutdated = "outdated"   // Rows whose published version is behind upstream
+	filterStatusUpToDate = "up-to-date" // Rows whose published version matches upstream
+)
+
+// indexColumns are the dashboard columns that can be hidden, in table order; the series
+// column is always shown
+var indexColumns = []string{"published", "proposed", "upstream", "target", "release_date", "sru_cycle"}
+
+// IndexColumn is a dashboard column that can be hidden, with its localized header
+type IndexColumn struct {
+	Key   string
+	Label string
+}
+
+// localizedColumns returns the hideable dashboard columns with their headers
+func localizedColumns(locale string) []IndexColumn {
+	labels := map[string]string{
+		"published":    publishedLabel(),
+		"proposed":     i18n.T(locale, "common.proposed"),
+		"upstream":     i18n.T(locale, "common.upstream_version"),
+		"target":       i18n.T(locale, "common.target"),
+		"release_date": i18n.T(locale, "common.release_date"),
+		"sru_cycle":    i18n.T(locale, "common.next_sru_cycle"),
+	}
+	columns := make([]IndexColumn, 0, len(indexColumns))
+	for _, key := range indexColumns {
+		columns = append(columns, IndexColumn{Key: key, Label: labels[key]})
+	}
+	return columns
+}
+
+// IndexFilters are the dashboard filters and column choices of the query string, so a
+// filtered dashboard has a shareable URL
+type IndexFilters struct {
+	Branch string   // Branch name or pattern, e.g. "570" or "*-server"
+	Series []string // Series shown; empty shows every series
+	Status string   // "outdated" or "up-to-date"; empty shows every row
+	Hide   []string // Columns hidden, see indexColumns
+}
+
+// splitList splits a comma separated query value, dropping empty entries
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// withPreset returns the query with the values of a preset filled in; parameters given in the
+// query take precedence, so an empty one clears a filter of the preset
+func withPreset(query url.Values, preset *config.PresetConfig) url.Values {
+	merged := url.Values{}
+	for key, values := range preset.Values() {
+		merged[key] = values
+	}
+	for key, values := range query {
+		merged[key] = values
+	}
+	return merged
+}
+
+// filterAction returns the page the dashboard filter form submits to
+func filterAction(view *config.ViewConfig) string {
+	if view != nil {
+		return "/view/" + url.PathEscape(view.Name)
+	}
+	return "/"
+}
+
+// parseIndexFilters reads the dashboard filters from the query parameters. Unknown statuses
+// and columns are ignored.
+func parseIndexFilters(query url.Values) IndexFilters {
+	filters := IndexFilters{
+		Branch: strings.TrimSpace(query.Get("branch")),
+		Series: splitList(query.Get("series")),
+	}
+	switch status := strings.ToLower(query.Get("status")); status {
+	case filterStatusOutdated, filterStatusUpToDate:
+		filters.Status = status
+	}
+	for _, column := range splitList(query.Get("hide")) {
+		if contains(indexColumns, column) && !contains(filters.Hide, column) {
+			filters.Hide = append(filters.Hide, column)
+		}
+	}
+	return filters
+}
+
+// Active reports whether any row filter is set; hidden columns do not filter rows
+func (f IndexFilters) Active() bool {
+	return f.Branch != "" || len(f.Series) > 0 || f.Status != ""
+}
+
+// Hidden reports whether a column is hidden
+func (f IndexFilters) Hidden(column string) bool {
+	return contains(f.Hide, column)
+}
+
+// SeriesList returns the series filter as it is written in the query
+func (f IndexFilters) SeriesList() string {
+	return strings.Join(f.Series, ",")
+}
+
+// Query returns the filters as query parameters, to forward them to the row requests
+func (f IndexFilters) Query() url.Values {
+	query := url.Values{}
+	if f.Branch != "" {
+		query.Set("branch", f.Branch)
+	}
+	if len(f.Series) > 0 {
+		query.Set("series", f.SeriesList())
+	}
+	if f.Status != "" {
+		query.Set("status", f.Status)
+	}
+	return query
+}
+
+// matchesRow reports whether a series row passes the series and status filters
+func (f IndexFilters) matchesRow(row *SeriesData) bool {
+	if len(f.Series) > 0 && !contains(f.Series, row.Series) {
+		return false
+	}
+	switch f.Status {
+	case filterStatusOutdated:
+		return row.UpdatesColor == "danger"
+	case filterStatusUpToDate:
+		return row.UpdatesColor == "success"
+	}
+	return true
+}
+
+// Apply returns the packages of the matching branches with their rows restricted to the
+// matching series and status. Packages left without rows are dropped while a row filter is set.
+func (f IndexFilters) Apply(pkgs []*PackageData) []*PackageData {
+	if !f.Active() {
+		return pkgs
+	}
+	var filtered []*PackageData
+	for _, pkg := range pkgs {
+		branch := branchFromPackage(pkg.PackageName)
+		if f.Branch != "" && branch != f.Branch {
+			if matched, err := filepath.Match(f.Branch, branch); err != nil || !matched {
+				continue
+			}
+		}
+		scoped := *pkg
+		scoped.Series = nil
+		for i := range pkg.Series {
+			if f.matchesRow(&pkg.Series[i]) {
+				scoped.Series = append(scoped.Series, pkg.Series[i])
+			}
+		}
+		if len(scoped.Series) == 0 && (len(f.Series) > 0 || f.Status != "") {
+			continue
+		}
+		filtered = append(filtered, &scoped)
+	}
+	return filtered
+}
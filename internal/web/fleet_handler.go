@@ -0,0 +1,134 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/fleet"
+	"nvidia_driver_monitor/internal/hostcheck"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// FleetHandler handles host-check report ingestion and the fleet view
+type FleetHandler struct {
+	templatePath string
+	config       *config.Config
+	store        *fleet.Store
+	// gpuNeeds summarizes the uploaded GPU inventories for the fleet page, when set
+	gpuNeeds func() *GPUNeeds
+}
+
+// NewFleetHandler creates a new fleet handler backed by a persistent report store
+func NewFleetHandler(templatePath string, cfg *config.Config) *FleetHandler {
+	fleetCfg := config.DefaultConfig().Fleet
+	if cfg != nil {
+		fleetCfg = cfg.Fleet
+	}
+	return &FleetHandler{
+		templatePath: templatePath,
+		config:       cfg,
+		store:        fleet.NewStore(fleetCfg.GetDataFile(), fleetCfg.GetStaleAfter()),
+	}
+}
+
+// reportAuthorized reports whether a host report may be ingested: from a logged-in user, with
+// the report token, or from anyone when neither a token nor a login is configured
+func (h *FleetHandler) reportAuthorized(r *http.Request) bool {
+	if _, ok := auth.SessionFromContext(r.Context()); ok {
+		return true
+	}
+	token, oidc := "", false
+	if h.config != nil {
+		token, oidc = h.config.Fleet.GetReportToken(), h.config.Auth.OIDC.Enabled
+	}
+	if token == "" {
+		return !oidc
+	}
+	presented := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
+
+// ReportHandler ingests a host-check report (POST /api/v1/hosts/report)
+func (h *FleetHandler) ReportHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !h.reportAuthorized(r) {
+		problems.Write(w, http.StatusUnauthorized, problems.Unauthorized, "Invalid or missing report token")
+		return
+	}
+
+	var report hostcheck.Report
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid report body")
+		return
+	}
+
+	if err := h.store.Record(&report); err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		return
+	}
+	log.Printf("Fleet report received from %s: %s (%s)", report.Hostname, report.Status, report.InstalledVersion)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":   "accepted",
+		"hostname": report.Hostname,
+	})
+}
+
+// HostsHandler returns the fleet summary and every known host (GET /api/v1/hosts)
+func (h *FleetHandler) HostsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	now := time.Now()
+	response := map[string]interface{}{
+		"summary": h.store.Summary(now),
+		"hosts":   h.store.Hosts(now),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// ServeHTTP renders the fleet compliance page
+func (h *FleetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	locale := requestLocale(w, r, h.config)
+
+	templateFile := filepath.Join(h.templatePath, "fleet.html")
+	tmpl, err := template.New("fleet.html").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, requestTimezone(w, r, h.config))).ParseFiles(templateFile)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Template parsing error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	templateData := struct {
+		Summary *fleet.Summary
+		Hosts   []*fleet.HostRecord
+		GPUs    *GPUNeeds
+		CDN     map[string]string
+	}{
+		Summary: h.store.Summary(now),
+		Hosts:   h.store.Hosts(now),
+		CDN:     GetCDNResources(h.config),
+	}
+	if h.gpuNeeds != nil {
+		templateData.GPUs = h.gpuNeeds()
+	}
+
+	renderPage(w, tmpl, templateData)
+}
@@ -0,0 +1,270 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/schema"
+	"nvidia_driver_monitor/internal/stats"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the API golden files")
+
+// jsonSchema reduces a decoded JSON value to its shape: objects keep their keys, arrays hold
+// the merged shape of their elements and scalars become their type name. Values such as
+// timestamps change between runs, the shape must not.
+func jsonSchema(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		schema := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			schema[key] = jsonSchema(item)
+		}
+		return schema
+	case []interface{}:
+		var merged interface{}
+		for _, item := range v {
+			merged = mergeSchema(merged, jsonSchema(item))
+		}
+		if merged == nil {
+			return []interface{}{}
+		}
+		return []interface{}{merged}
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+// mergeSchema joins two shapes; nullable values take the shape of their non-null occurrences
+// and differing scalars are joined as "number|string"
+func mergeSchema(a, b interface{}) interface{} {
+	if a == nil || a == "null" {
+		return b
+	}
+	if b == nil || b == "null" {
+		return a
+	}
+	if am, ok := a.(map[string]interface{}); ok {
+		if bm, ok := b.(map[string]interface{}); ok {
+			merged := make(map[string]interface{}, len(am))
+			for key, value := range am {
+				merged[key] = value
+			}
+			for key, value := range bm {
+				merged[key] = mergeSchema(merged[key], value)
+			}
+			return merged
+		}
+	}
+	if as, ok := a.([]interface{}); ok {
+		if bs, ok := b.([]interface{}); ok {
+			switch {
+			case len(as) == 0:
+				return bs
+			case len(bs) == 0:
+				return as
+			}
+			return []interface{}{mergeSchema(as[0], bs[0])}
+		}
+	}
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			types := strings.Split(as, "|")
+			for _, name := range strings.Split(bs, "|") {
+				if !contains(types, name) {
+					types = append(types, name)
+				}
+			}
+			sort.Strings(types)
+			return strings.Join(types, "|")
+		}
+	}
+	return b
+}
+
+// loadAPIFixtures returns a service primed from testdata/api/snapshot.json, as a replica is
+// primed from its peer, and an API handler serving testdata/api/lrm.json
+func loadAPIFixtures(t *testing.T) (*WebService, *APIHandler) {
+	t.Helper()
+	var snapshot Snapshot
+	var lrmData lrm.LRMVerifierData
+	for path, target := range map[string]interface{}{
+		filepath.Join("testdata", "api", "snapshot.json"): &snapshot,
+		filepath.Join("testdata", "api", "lrm.json"):      &lrmData,
+	} {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(body, target); err != nil {
+			t.Fatalf("Failed to decode %s: %v", path, err)
+		}
+	}
+
+	// The snapshot sets the devel series unless one is known
+	previous := releases.DevelCodename()
+	t.Cleanup(func() { releases.SetDevelCodename(previous) })
+
+	ws := &WebService{cache: &CachedData{}, templatePath: "../../templates"}
+	ws.applySnapshot(&snapshot)
+
+	collector := stats.NewMemoryCollector(time.Hour, 24)
+	url := "https://api.launchpad.net/devel/ubuntu/+archive/primary"
+	collector.RecordRequest(url, 120*time.Millisecond, 0, true, http.StatusOK)
+	collector.RecordRequest(url, time.Second, 2, false, http.StatusServiceUnavailable)
+	collector.RecordBytes(url, 4096)
+
+	handler := NewAPIHandler()
+	handler.lrmData = func() (*lrm.LRMVerifierData, error) { return &lrmData, nil }
+	handler.collector = collector
+	return ws, handler
+}
+
+// TestAPIGoldenSchemas pins the response schema of the API endpoints downstream tools read, so
+// refactors cannot change the wire format unnoticed. Run go test -update after an intended change.
+func TestAPIGoldenSchemas(t *testing.T) {
+	ws, handler := loadAPIFixtures(t)
+
+	endpoints := []struct {
+		name    string
+		path    string
+		handler http.HandlerFunc
+	}{
+		{"packages", "/api", ws.apiHandler},
+		{"package", "/api?package=nvidia-graphics-drivers-570", ws.apiHandler},
+		{"packages_v1", "/api/v1/packages", ws.packagesV1Handler},
+		{"packages_v1_fields", "/api/v1/packages?fields=UpdatesSecurity,StaleSince", ws.packagesV1Handler},
+		{"snapshot", "/api/v1/snapshot", ws.snapshotHandler},
+		{"lrm", "/api/lrm", handler.LRMDataHandler},
+		{"lrm_fields", "/api/lrm?fields=Source,NvidiaDriverStatuses", handler.LRMDataHandler},
+		{"statistics", "/api/statistics", handler.StatisticsHandler},
+	}
+	for _, endpoint := range endpoints {
+		t.Run(endpoint.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			endpoint.handler(w, httptest.NewRequest("GET", endpoint.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("GET %s = %d: %s", endpoint.path, w.Code, w.Body.String())
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("GET %s Content-Type = %q, expected application/json", endpoint.path, contentType)
+			}
+
+			var response interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("GET %s returned invalid JSON: %v", endpoint.path, err)
+			}
+			got, err := json.MarshalIndent(jsonSchema(response), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", "api", endpoint.name+".golden.json")
+			if *updateGolden {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("missing golden file (run go test -update): %v", err)
+			}
+			if !bytes.Equal(got, expected) {
+				t.Errorf("GET %s does not match %s:\n%s", endpoint.path, golden, got)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaMergesElements(t *testing.T) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(`[{"a": 1, "b": null}, {"a": "x", "b": [true], "c": {}}]`), &value); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(jsonSchema(value))
+	if expected := `[{"a":"number|string","b":["boolean"],"c":{}}]`; string(got) != expected {
+		t.Errorf("jsonSchema() = %s, expected %s", got, expected)
+	}
+}
+
+// TestServedSchemasMatchFixtures checks the fixture data against the schemas served at
+// /api/v1/schema/{type}, so the generated schemas and the wire format cannot drift apart
+func TestServedSchemasMatchFixtures(t *testing.T) {
+	ws, handler := loadAPIFixtures(t)
+	snapshot, ok := ws.getSnapshot()
+	if !ok {
+		t.Fatal("getSnapshot() returned no data")
+	}
+	lrmData, _ := handler.getLRMData()
+
+	values := map[string][]interface{}{schemaSnapshot: {snapshot}}
+	for _, pkg := range snapshot.Packages {
+		values[schemaPackage] = append(values[schemaPackage], pkg)
+	}
+	for _, kernel := range lrmData.KernelResults {
+		values[schemaKernel] = append(values[schemaKernel], kernel)
+	}
+	if snapshot.SRUCycles != nil {
+		for _, cycle := range snapshot.SRUCycles.Cycles {
+			values[schemaSRUCycle] = append(values[schemaSRUCycle], cycle)
+		}
+	}
+
+	for name := range schemaTypes {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ws.schemaHandler(w, httptest.NewRequest("GET", "/api/v1/schema/"+name, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("GET /api/v1/schema/%s = %d: %s", name, w.Code, w.Body.String())
+			}
+			var served schema.Schema
+			if err := json.Unmarshal(w.Body.Bytes(), &served); err != nil {
+				t.Fatalf("Served schema is invalid JSON: %v", err)
+			}
+			if served.ID != "/api/v1/schema/"+name || served.SchemaURI != schema.Draft {
+				t.Errorf("Served schema has $id %q and $schema %q", served.ID, served.SchemaURI)
+			}
+
+			if len(values[name]) == 0 {
+				t.Fatalf("The fixtures have no %s values", name)
+			}
+			for _, value := range values[name] {
+				violations, err := schema.ValidateValue(apiSchema(name), value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, violation := range violations {
+					t.Errorf("%s fixture does not match its schema: %s", name, violation)
+				}
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	ws.schemaHandler(w, httptest.NewRequest("GET", "/api/v1/schema/unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET /api/v1/schema/unknown = %d, expected 404", w.Code)
+	}
+	w = httptest.NewRecorder()
+	ws.schemaHandler(w, httptest.NewRequest("GET", "/api/v1/schema", nil))
+	if !strings.Contains(w.Body.String(), `"types":["kernel","package","snapshot","sru-cycle"]`) {
+		t.Errorf("GET /api/v1/schema = %s, expected the sorted type names", w.Body.String())
+	}
+}
@@ -0,0 +1,155 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/gpus"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// maxInventoryGPUs bounds the lines of one uploaded inventory
+const maxInventoryGPUs = 10000
+
+// GPUBranchStatus is a branch needed by the GPU inventories with its archive status
+type GPUBranchStatus struct {
+	gpus.BranchNeed
+	Package        string `json:"package"`
+	Tracked        bool   `json:"tracked"` // The branch is on the dashboard
+	Series         int    `json:"series,omitempty"`
+	OutdatedSeries int    `json:"outdated_series,omitempty"`
+}
+
+// GPUNeeds maps the uploaded GPU inventories to the branches they need
+type GPUNeeds struct {
+	Branches    []GPUBranchStatus `json:"branches"`
+	Unmapped    []gpus.ModelCount `json:"unmapped"`
+	Inventories []string          `json:"inventories"`
+	GPUs        int               `json:"gpus"`
+	TableError  string            `json:"table_error,omitempty"` // Set when the device table cannot be read
+}
+
+// gpuBranchesFile returns the configured device-to-branch table
+func (ws *WebService) gpuBranchesFile() string {
+	if ws.config == nil {
+		return config.DefaultConfig().Fleet.GetGPUBranchesFile()
+	}
+	return ws.config.Fleet.GetGPUBranchesFile()
+}
+
+// getGPUNeeds summarizes the GPU inventories and looks up the archive status of each branch
+func (ws *WebService) getGPUNeeds() *GPUNeeds {
+	table, err := gpus.LoadTable(ws.gpuBranchesFile())
+	summary := gpus.Summarize(ws.gpuStore.List(), table)
+	needs := &GPUNeeds{
+		Branches:    make([]GPUBranchStatus, 0, len(summary.Branches)),
+		Unmapped:    summary.Unmapped,
+		Inventories: summary.Inventories,
+		GPUs:        summary.GPUs,
+	}
+	if err != nil {
+		needs.TableError = err.Error()
+	}
+
+	allPackages, _, _ := ws.getCachedPackages()
+	byName := make(map[string]*PackageData, len(allPackages))
+	for _, pkg := range allPackages {
+		byName[pkg.PackageName] = pkg
+	}
+	for _, need := range summary.Branches {
+		status := GPUBranchStatus{BranchNeed: need, Package: "nvidia-graphics-drivers-" + need.Branch}
+		if pkg, ok := byName[status.Package]; ok {
+			status.Tracked = true
+			status.Series = len(pkg.Series)
+			status.OutdatedSeries = pkg.OutdatedSeries()
+		}
+		needs.Branches = append(needs.Branches, status)
+	}
+	return needs
+}
+
+// gpusHandler returns the uploaded inventories and the branches their GPUs need (/api/v1/gpus)
+func (ws *WebService) gpusHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	response := map[string]interface{}{
+		"needs":       ws.getGPUNeeds(),
+		"inventories": ws.gpuStore.List(),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// gpuInventoryHandler replaces (PUT or POST) or deletes (DELETE) a named GPU inventory
+// (/api/v1/gpus/inventory?name={name}). The body is CSV with a pci_id and/or model column and
+// an optional count column when the Content-Type is text/csv, otherwise JSON {"gpus": [...]}.
+func (ws *WebService) gpuInventoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "name is required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete, http.MethodPut, http.MethodPost:
+		if !ws.requireAdmin(w, r) {
+			return
+		}
+	default:
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if !ws.gpuStore.Remove(name) {
+			problems.Write(w, http.StatusNotFound, problems.NotFound, "Inventory not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var list []gpus.GPU
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		parsed, err := gpus.ParseCSV(r.Body)
+		if err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid CSV: "+err.Error())
+			return
+		}
+		list = parsed
+	} else {
+		var body struct {
+			GPUs []gpus.GPU `json:"gpus"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid inventory body")
+			return
+		}
+		list = body.GPUs
+	}
+	if len(list) > maxInventoryGPUs {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, fmt.Sprintf("An inventory may list at most %d GPU models", maxInventoryGPUs))
+		return
+	}
+
+	inventory, err := ws.gpuStore.Put(name, list, time.Now())
+	if err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		return
+	}
+	if err := json.NewEncoder(w).Encode(inventory); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
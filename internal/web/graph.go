@@ -0,0 +1,72 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+
+	"nvidia_driver_monitor/internal/graph"
+	"nvidia_driver_monitor/internal/i18n"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// graphPageHandler renders the delivery graph of a driver branch (/graph?branch=550).
+// With format=json the graph is returned as JSON instead.
+func (ws *WebService) graphPageHandler(w http.ResponseWriter, r *http.Request) {
+	index, _, _ := ws.getPackageIndex()
+	branches := index.branches
+	branch := r.URL.Query().Get("branch")
+	current := index.byBranch[branch]
+	if branch == "" && len(branches) > 0 {
+		branch = branches[len(branches)-1]
+		http.Redirect(w, r, "/graph?branch="+branch, http.StatusFound)
+		return
+	}
+
+	locale := requestLocale(w, r, ws.config)
+	asJSON := r.URL.Query().Get("format") == "json"
+	g, err := graph.Build(branch)
+	if err != nil {
+		if asJSON {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		} else {
+			http.Error(w, i18n.T(locale, "error.invalid_branch"), http.StatusBadRequest)
+		}
+		return
+	}
+
+	if asJSON {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(g); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	templateFile := filepath.Join(ws.templatePath, "graph.html")
+	tmpl, err := template.New("graph.html").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, requestTimezone(w, r, ws.config))).ParseFiles(templateFile)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Template parsing error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := struct {
+		Branch   string
+		Branches []string
+		Graph    *graph.Graph
+		Mermaid  string
+		Package  *PackageData
+		CDN      map[string]string
+	}{
+		Branch:   branch,
+		Branches: branches,
+		Graph:    g,
+		Mermaid:  g.Mermaid(),
+		Package:  current,
+		CDN:      GetCDNResources(ws.config),
+	}
+	renderPage(w, tmpl, templateData)
+}
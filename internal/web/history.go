@@ -0,0 +1,217 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/releases"
+)
+
+// packagesAsOf reconstructs the status table from the history store as it was on the given day
+func (ws *WebService) packagesAsOf(date time.Time) map[string]*PackageData {
+	result := make(map[string]*PackageData)
+	if ws.historyStore == nil {
+		return result
+	}
+
+	// Rows are compared under the comparison policy of their branch, as on the live dashboard
+	policies := make(map[string]string)
+	for _, rel := range ws.getUpstreamData().supportedReleases {
+		policies[rel.PackageName()] = rel.GetComparisonPolicy()
+	}
+
+	rows := make(map[string]map[string]history.Observation)
+	for _, obs := range ws.historyStore.AsOf(date.Format(history.DateFormat)) {
+		if rows[obs.Package] == nil {
+			rows[obs.Package] = make(map[string]history.Observation)
+		}
+		rows[obs.Package][obs.Series] = obs
+	}
+
+	for packageName, bySeries := range rows {
+		names := make([]string, 0, len(bySeries))
+		for series := range bySeries {
+			names = append(names, series)
+		}
+
+		policy, ok := policies[packageName]
+		if !ok {
+			policy = releases.PolicyPrefix
+		}
+		pkg := &PackageData{PackageName: packageName}
+		for _, series := range packages.SortSeries(names) {
+			pkg.Series = append(pkg.Series, seriesFromObservation(bySeries[series], policy, date))
+		}
+		result[packageName] = pkg
+	}
+	return result
+}
+
+// seriesFromObservation turns a history observation back into a status table row, comparing
+// its versions under a comparison policy
+func seriesFromObservation(obs history.Observation, policy string, date time.Time) SeriesData {
+	row := SeriesData{
+		Series:          obs.Series,
+		UpdatesSecurity: orDash(obs.Published),
+		Proposed:        orDash(obs.Proposed),
+		UpstreamVersion: orDash(obs.Upstream),
+		TargetVersion:   "-",
+		ReleaseDate:     orDash(obs.UpstreamDate),
+		SRUCycle:        "-",
+		Component:       obs.Component,
+	}
+	if row.UpstreamVersion == "-" {
+		return row
+	}
+
+	row.UpdatesColor = "success"
+	if row.UpdatesSecurity != "-" {
+		if !packages.MatchesUpstream(row.UpdatesSecurity, obs.Upstream, policy) {
+			row.UpdatesColor = "danger"
+		}
+		row.Comparisons = append(row.Comparisons, packages.NewVersionComparison("published", row.UpdatesSecurity, obs.Upstream, obs.UpstreamDate, policy, date))
+	} else if obs.Outdated {
+		row.UpdatesColor = "danger"
+	}
+	if row.Proposed != "-" {
+		row.ProposedColor = "danger"
+		if packages.MatchesUpstream(row.Proposed, obs.Upstream, policy) {
+			row.ProposedColor = "success"
+		}
+		row.Comparisons = append(row.Comparisons, packages.NewVersionComparison("proposed", row.Proposed, obs.Upstream, obs.UpstreamDate, policy, date))
+	}
+	return row
+}
+
+// orDash returns "-" for empty values, as shown in the status table
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// packagesV1Handler returns the status table, optionally reconstructed from history with
+// as_of=YYYY-MM-DD, and optionally restricted to a view, an archive component, the dashboard
+// filters (branch, series, status) or one package
+func (ws *WebService) packagesV1Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	fields, err := parseFields(r, PackageData{}, SeriesData{})
+	if err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		return
+	}
+
+	view, ok := ws.resolveView(w, r, r.URL.Query().Get("view"))
+	if !ok {
+		return
+	}
+
+	response := struct {
+		AsOf        string                  `json:"as_of,omitempty"`
+		Packages    map[string]*PackageData `json:"packages"`
+		LastUpdated *time.Time              `json:"last_updated,omitempty"`
+	}{}
+
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		date, err := time.Parse(history.DateFormat, asOf)
+		if err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid as_of date, expected YYYY-MM-DD")
+			return
+		}
+		// Observations are recorded once per day, so the end of the day is reconstructed
+		response.AsOf = asOf
+		response.Packages = ws.packagesAsOf(date.Add(24*time.Hour - time.Second))
+	} else {
+		index, lastUpdated, isInitialized := ws.getPackageIndex()
+		if !isInitialized {
+			problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+			return
+		}
+		response.LastUpdated = &lastUpdated
+		// The shared index map is only read; the filters below build new maps
+		response.Packages = index.byName
+		if packageName := r.URL.Query().Get("package"); packageName != "" {
+			response.Packages = make(map[string]*PackageData, 1)
+			if pkg, ok := index.byName[packageName]; ok {
+				response.Packages[packageName] = pkg
+			}
+		}
+	}
+
+	if view != nil {
+		scoped := make(map[string]*PackageData)
+		for _, pkg := range response.Packages {
+			for _, filtered := range filterPackagesForView([]*PackageData{pkg}, view) {
+				scoped[filtered.PackageName] = filtered
+			}
+		}
+		response.Packages = scoped
+	}
+
+	if component := r.URL.Query().Get("component"); component != "" {
+		scoped := make(map[string]*PackageData)
+		for _, pkg := range response.Packages {
+			for _, filtered := range filterPackagesByComponent([]*PackageData{pkg}, component) {
+				scoped[filtered.PackageName] = filtered
+			}
+		}
+		response.Packages = scoped
+	}
+
+	if filters := parseIndexFilters(r.URL.Query()); filters.Active() {
+		scoped := make(map[string]*PackageData)
+		for _, pkg := range response.Packages {
+			for _, filtered := range filters.Apply([]*PackageData{pkg}) {
+				scoped[filtered.PackageName] = filtered
+			}
+		}
+		response.Packages = scoped
+	}
+
+	if packageName := r.URL.Query().Get("package"); packageName != "" {
+		pkg, ok := response.Packages[packageName]
+		if !ok {
+			problems.Write(w, http.StatusNotFound, problems.PackageNotFound, "Package not found")
+			return
+		}
+		response.Packages = map[string]*PackageData{packageName: pkg}
+	}
+
+	if fields != nil {
+		sparse := make(map[string]interface{}, len(response.Packages))
+		for name, pkg := range response.Packages {
+			object, err := fields.sparsePackage(pkg)
+			if err != nil {
+				problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+				return
+			}
+			sparse[name] = object
+		}
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"as_of":        response.AsOf,
+			"packages":     sparse,
+			"last_updated": response.LastUpdated,
+		}); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+		return
+	}
+
+	for _, pkg := range response.Packages {
+		checkSchema(schemaPackage, pkg)
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
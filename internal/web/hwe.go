@@ -0,0 +1,140 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"nvidia_driver_monitor/internal/dkms"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// HWEWarning is a driver branch of an LTS whose archive does not have the version the L-R-M of
+// an upcoming HWE kernel is built against yet
+type HWEWarning struct {
+	Series       string `json:"series"`        // LTS codename, e.g. "noble"
+	Kernel       string `json:"kernel"`        // Upstream kernel version, e.g. "6.14"
+	FromSeries   string `json:"from_series"`   // Codename of the series the kernel is backported from
+	KernelSource string `json:"kernel_source"` // Its source there, e.g. "linux"
+	Package      string `json:"package"`
+	Branch       string `json:"branch"`
+	Required     string `json:"required_version"`  // Driver version the kernel's L-R-M carries
+	Published    string `json:"published_version"` // Version published in the LTS, "-" when none
+	// Proposed is set when the proposed version of the LTS already satisfies the kernel
+	Proposed string `json:"proposed_version,omitempty"`
+}
+
+// satisfies reports whether an archive version carries at least the required driver version
+func satisfies(archiveVersion, required string) bool {
+	if !isArchiveVersion(archiveVersion) {
+		return false
+	}
+	comparison := packages.CompareToUpstream(archiveVersion, required)
+	return comparison == packages.ComparisonEqual || comparison == packages.ComparisonAhead
+}
+
+// hweWarnings lists the driver branches of each LTS that an upcoming HWE kernel needs a newer
+// version of. The kernels rolled out to an LTS are those listing a "-hwe-<LTS>" variant in
+// kernel-series.yaml; one is upcoming while the LTS has no linux-hwe-<kernel> source yet. Its
+// L-R-M is built against the driver versions of its own series, which the LTS must then have.
+func hweWarnings(kernels []lrm.KernelLRMResult, pkgs []*PackageData) []HWEWarning {
+	codenames := make(map[string]string)
+	sources := make(map[string]bool)
+	for _, kernel := range kernels {
+		codenames[kernel.Series] = kernel.Codename
+		sources[kernel.Codename+"/"+kernel.Source] = true
+	}
+	rows := make(map[string]*SeriesData)
+	for _, pkg := range pkgs {
+		for i := range pkg.Series {
+			rows[pkg.PackageName+"/"+pkg.Series[i].Series] = &pkg.Series[i]
+		}
+	}
+
+	warnings := []HWEWarning{}
+	for _, kernel := range kernels {
+		version := dkms.KernelVersion(kernel.SourceVersion)
+		if version == "" {
+			continue
+		}
+		for _, target := range kernel.HWETargets {
+			lts := codenames[target]
+			if lts == "" || sources[lts+"/linux-hwe-"+version] {
+				continue
+			}
+			for _, driver := range kernel.NvidiaDriverStatuses {
+				row := rows[driver.DriverName+"/"+lts]
+				required, _, _ := strings.Cut(driver.DSCVersion, "-")
+				if row == nil || row.Removed != "" || required == "" || satisfies(row.UpdatesSecurity, required) {
+					continue
+				}
+				warning := HWEWarning{
+					Series:       lts,
+					Kernel:       version,
+					FromSeries:   kernel.Codename,
+					KernelSource: kernel.Source,
+					Package:      driver.DriverName,
+					Branch:       branchFromPackage(driver.DriverName),
+					Required:     required,
+					Published:    orDash(row.UpdatesSecurity),
+				}
+				if satisfies(row.Proposed, required) {
+					warning.Proposed = row.Proposed
+				}
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		a, b := warnings[i], warnings[j]
+		if a.Series != b.Series {
+			return a.Series < b.Series
+		}
+		if a.Kernel != b.Kernel {
+			return a.Kernel < b.Kernel
+		}
+		return a.Package < b.Package
+	})
+	return warnings
+}
+
+// hweHandler lists the driver versions upcoming HWE kernels need in their LTS
+// (/api/v1/hwe?series={codename})
+func (ws *WebService) hweHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	pkgs, _, isInitialized := ws.getCachedPackages()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	lrmData, err := lrm.GetCachedLRMData()
+	if err != nil {
+		problems.Write(w, http.StatusServiceUnavailable, problems.UpstreamUnavailable, "Kernel data is not available")
+		return
+	}
+
+	series := r.URL.Query().Get("series")
+	warnings := []HWEWarning{}
+	for _, warning := range hweWarnings(lrmData.KernelResults, pkgs) {
+		if series == "" || warning.Series == series {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	response := map[string]interface{}{
+		"warnings": warnings,
+		"count":    len(warnings),
+		"stale":    lrmData.Stale,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
@@ -0,0 +1,140 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/pkg/debversion"
+)
+
+// udaPackagePrefix names the source packages of the desktop (UDA) and server branches
+const udaPackagePrefix = "nvidia-graphics-drivers-"
+
+// I386Warning is an expected i386 library missing or behind its source in a series pocket
+type I386Warning struct {
+	Binary  string `json:"binary"`           // e.g. "libnvidia-gl-570"
+	Series  string `json:"series,omitempty"` // Empty when the binary could not be looked up
+	Pocket  string `json:"pocket,omitempty"` // "published" or "proposed"
+	Source  string `json:"source,omitempty"` // Source version shown on the dashboard
+	I386    string `json:"i386,omitempty"`   // Greatest i386 binary version; empty when missing
+	Message string `json:"message"`
+}
+
+// udaBranch returns the branch of a desktop driver package, or "" for server and Tegra packages
+func udaBranch(packageName string) string {
+	if !strings.HasPrefix(packageName, udaPackagePrefix) || strings.HasSuffix(packageName, "-server") {
+		return ""
+	}
+	return branchFromPackage(packageName)
+}
+
+// compareI386 reports the series where the source of pkg is published or proposed but the
+// i386 binary is missing or older, e.g. after an i386 build failure
+func compareI386(pkg *PackageData, binary string, versions *packages.BinaryVersionPerSeries) []I386Warning {
+	var warnings []I386Warning
+	for _, row := range pkg.Series {
+		if row.Removed != "" {
+			continue
+		}
+		pocket := versions.VersionMap[row.Series]
+		if pocket == nil {
+			pocket = &packages.BinaryVersionPerPocket{}
+		}
+		for _, check := range []struct {
+			name   string
+			source string
+			i386   string
+		}{
+			{"published", row.UpdatesSecurity, pocket.I386UpdatesSecurity.String()},
+			{"proposed", row.Proposed, pocket.I386Proposed.String()},
+		} {
+			if !isArchiveVersion(check.source) {
+				continue
+			}
+			warning := I386Warning{Binary: binary, Series: row.Series, Pocket: check.name, Source: check.source, I386: check.i386}
+			switch {
+			case check.i386 == "":
+				warning.Message = fmt.Sprintf("%s:i386 is not %s in %s", binary, check.name, row.Series)
+			case debversion.OlderThan(check.i386, check.source):
+				warning.Message = fmt.Sprintf("%s:i386 %s %s in %s is behind the source %s", binary, check.name, check.i386, row.Series, check.source)
+			default:
+				continue
+			}
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// runI386Check looks up the i386 libraries of every UDA branch and keeps the warnings per
+// branch for the branch pages. It returns false when there is no data to compare yet.
+func (ws *WebService) runI386Check() bool {
+	pkgs, _, isInitialized := ws.getCachedPackages()
+	if !isInitialized {
+		return false
+	}
+
+	log.Printf("Checking the i386 libraries of the UDA branches...")
+	warnings := make(map[string][]I386Warning)
+	count := 0
+	for _, pkg := range pkgs {
+		branch := udaBranch(pkg.PackageName)
+		if branch == "" {
+			continue
+		}
+		branchWarnings := []I386Warning{}
+		for _, library := range ws.config.I386.GetLibraries() {
+			binary := library + "-" + branch
+			versions, err := packages.GetMaxBinaryVersionsArchive(ws.config, binary)
+			if err != nil {
+				branchWarnings = append(branchWarnings, I386Warning{Binary: binary, Message: err.Error()})
+				continue
+			}
+			branchWarnings = append(branchWarnings, compareI386(pkg, binary, versions)...)
+		}
+		warnings[branch] = branchWarnings
+		count += len(branchWarnings)
+	}
+	log.Printf("i386 check found %d warnings", count)
+
+	ws.cacheMux.Lock()
+	ws.i386Warnings = warnings
+	ws.i386CheckedAt = time.Now()
+	ws.cacheMux.Unlock()
+	return true
+}
+
+// getI386Warnings returns the i386 warnings of a branch and when they were checked; a zero
+// time means the branch has not been checked
+func (ws *WebService) getI386Warnings(branch string) ([]I386Warning, time.Time) {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	warnings, ok := ws.i386Warnings[branch]
+	if !ok {
+		return nil, time.Time{}
+	}
+	return warnings, ws.i386CheckedAt
+}
+
+// i386CheckLoop runs the i386 check once the first data is loaded, then at the configured interval
+func (ws *WebService) i386CheckLoop() {
+	timer := time.NewTimer(archiveCheckRetry)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			next := ws.config.I386.GetInterval()
+			if !ws.runI386Check() {
+				next = archiveCheckRetry
+			}
+			timer.Reset(next)
+		case <-ws.stopChan:
+			log.Printf("Stopping i386 check loop...")
+			return
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package web
+
+import (
+	"sort"
+	"time"
+)
+
+// packageIndex gives constant time access to the cached packages. It is rebuilt on every cache
+// swap and never modified afterwards, so readers may share it without holding cacheMux.
+type packageIndex struct {
+	packages []*PackageData // In supported release order
+	byName   map[string]*PackageData
+	byBranch map[string]*PackageData // e.g. "570", "535-server"
+	branches []string                // Sorted branch names
+	// rows maps a package name and series codename to the package's row in that series
+	rows map[string]map[string]SeriesData
+}
+
+// newPackageIndex indexes packages by name, branch and series
+func newPackageIndex(pkgs []*PackageData) *packageIndex {
+	index := &packageIndex{
+		packages: pkgs,
+		byName:   make(map[string]*PackageData, len(pkgs)),
+		byBranch: make(map[string]*PackageData, len(pkgs)),
+		rows:     make(map[string]map[string]SeriesData, len(pkgs)),
+	}
+	for _, pkg := range pkgs {
+		index.byName[pkg.PackageName] = pkg
+		branch := branchFromPackage(pkg.PackageName)
+		if _, ok := index.byBranch[branch]; !ok {
+			index.branches = append(index.branches, branch)
+		}
+		index.byBranch[branch] = pkg
+
+		rows := make(map[string]SeriesData, len(pkg.Series))
+		for _, row := range pkg.Series {
+			rows[row.Series] = row
+		}
+		index.rows[pkg.PackageName] = rows
+	}
+	sort.Strings(index.branches)
+	return index
+}
+
+// row returns a package's row in a series
+func (index *packageIndex) row(packageName, series string) (SeriesData, bool) {
+	row, ok := index.rows[packageName][series]
+	return row, ok
+}
+
+// setPackages swaps the cached packages and rebuilds their index; callers hold cacheMux
+func (c *CachedData) setPackages(pkgs []*PackageData) {
+	c.AllPackages = pkgs
+	c.index = newPackageIndex(pkgs)
+	c.generation++
+}
+
+// getPackageIndex returns the index of the cached packages
+func (ws *WebService) getPackageIndex() (*packageIndex, time.Time, bool) {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+
+	index := ws.cache.index
+	if index == nil {
+		// Caches filled without setPackages (e.g. before the first refresh) are indexed on demand
+		index = newPackageIndex(ws.cache.AllPackages)
+	}
+	return index, ws.cache.LastUpdated, ws.cache.IsInitialized
+}
@@ -194,7 +194,8 @@ func (v *InputValidator) ValidateURLPath(path string) string {
 	return ""
 }
 
-// SanitizeHTML removes or escapes HTML content from user input
+// SanitizeHTML removes or escapes HTML content from user input. Not for data rendered by the
+// page templates, which escape it themselves (see render.go)
 func (v *InputValidator) SanitizeHTML(input string) string {
 	// Basic HTML escaping
 	input = strings.ReplaceAll(input, "&", "&amp;")
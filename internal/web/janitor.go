@@ -0,0 +1,129 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/janitor"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// janitorCaches lists the disk caches of a configuration with their limits
+func janitorCaches(cfg *config.Config) []janitor.Cache {
+	dirs := []struct{ name, dir string }{
+		{config.JanitorCacheDSC, lrm.DSCCacheDir},
+		{config.JanitorCacheChangelog, cfg.Changelog.GetCacheDir()},
+		{config.JanitorCacheFixtures, cfg.Testing.DataDir},
+	}
+	caches := make([]janitor.Cache, 0, len(dirs))
+	for _, d := range dirs {
+		if d.dir == "" {
+			continue
+		}
+		limits := cfg.Janitor.Caches[d.name]
+		caches = append(caches, janitor.Cache{Name: d.name, Dir: d.dir, MaxAge: limits.GetMaxAge(), MaxBytes: limits.GetMaxBytes()})
+	}
+	return caches
+}
+
+// runJanitor prunes the disk caches and logs what was evicted
+func (ws *WebService) runJanitor() []janitor.Report {
+	reports := ws.janitor.Prune(time.Now())
+	for _, report := range reports {
+		if report.Error != "" {
+			log.Printf("Warning: Failed to prune the %s cache: %s", report.Name, report.Error)
+		}
+	}
+	return reports
+}
+
+// janitorLoop prunes the disk caches at startup, then at the configured interval
+func (ws *WebService) janitorLoop() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			before := ws.janitor.Usage()
+			after := ws.runJanitor()
+			for i := range after {
+				if evicted := after[i].Evictions - before[i].Evictions; evicted > 0 {
+					log.Printf("Cache janitor evicted %d files (%d bytes) from the %s cache, %d bytes left",
+						evicted, after[i].EvictedBytes-before[i].EvictedBytes, after[i].Name, after[i].Bytes)
+				}
+			}
+			timer.Reset(ws.config.Janitor.GetInterval())
+		case <-ws.stopChan:
+			log.Printf("Stopping cache janitor loop...")
+			return
+		}
+	}
+}
+
+// writeCacheMetrics writes the disk footprint of the caches and the janitor's evictions
+func writeCacheMetrics(b *strings.Builder, reports []janitor.Report) {
+	b.WriteString("# HELP nvidia_monitor_cache_disk_bytes Bytes the disk cache takes\n")
+	b.WriteString("# TYPE nvidia_monitor_cache_disk_bytes gauge\n")
+	for _, report := range reports {
+		fmt.Fprintf(b, "nvidia_monitor_cache_disk_bytes{cache=%q} %d\n", report.Name, report.Bytes)
+	}
+
+	b.WriteString("# HELP nvidia_monitor_cache_files Files in the disk cache\n")
+	b.WriteString("# TYPE nvidia_monitor_cache_files gauge\n")
+	for _, report := range reports {
+		fmt.Fprintf(b, "nvidia_monitor_cache_files{cache=%q} %d\n", report.Name, report.Files)
+	}
+
+	b.WriteString("# HELP nvidia_monitor_cache_evictions_total Files the cache janitor evicted from the disk cache\n")
+	b.WriteString("# TYPE nvidia_monitor_cache_evictions_total counter\n")
+	for _, report := range reports {
+		fmt.Fprintf(b, "nvidia_monitor_cache_evictions_total{cache=%q} %d\n", report.Name, report.Evictions)
+	}
+
+	b.WriteString("# HELP nvidia_monitor_cache_evicted_bytes_total Bytes the cache janitor freed from the disk cache\n")
+	b.WriteString("# TYPE nvidia_monitor_cache_evicted_bytes_total counter\n")
+	for _, report := range reports {
+		fmt.Fprintf(b, "nvidia_monitor_cache_evicted_bytes_total{cache=%q} %d\n", report.Name, report.EvictedBytes)
+	}
+}
+
+// cachesHandler reports the disk footprint of each cache, and prunes them now on POST
+// (/api/v1/caches). Both require an admin, as the report lists server paths.
+func (ws *WebService) cachesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !ws.requireAdmin(w, r) {
+		return
+	}
+	if ws.janitor == nil {
+		problems.Write(w, http.StatusServiceUnavailable, problems.NotConfigured, "Cache janitor is not configured")
+		return
+	}
+
+	var reports []janitor.Report
+	switch r.Method {
+	case http.MethodGet:
+		reports = ws.janitor.Usage()
+	case http.MethodPost:
+		reports = ws.runJanitor()
+	default:
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	response := map[string]interface{}{
+		"caches":   reports,
+		"enabled":  ws.config.Janitor.Enabled,
+		"interval": ws.config.Janitor.GetInterval().String(),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
@@ -0,0 +1,248 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/jobs"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// Job kinds
+const (
+	jobKindExport = "export"
+	jobKindReport = "report"
+)
+
+// jobTarget is the endpoint whose response a job saves
+type jobTarget struct {
+	path    string
+	handler http.HandlerFunc
+	// pathParam is the query parameter appended to path, e.g. the series of an SBOM
+	pathParam string
+}
+
+// jobTargets returns the exports or the reports that can be run as jobs, by type
+func (ws *WebService) jobTargets(kind string) map[string]jobTarget {
+	if kind == jobKindReport {
+		return map[string]jobTarget{
+			"lrm":          {path: "/api/v1/lrm/report", handler: ws.lrmReportPreviewHandler},
+			"lrm-rebuilds": {path: "/api/v1/lrm/rebuilds", handler: ws.lrmRebuildsHandler},
+			"dashboard":    {path: "/", handler: ws.indexHandler},
+		}
+	}
+	return map[string]jobTarget{
+		"packages":    {path: "/api/v1/packages", handler: ws.packagesV1Handler},
+		"matrix":      {path: "/api/v1/matrix", handler: ws.matrixHandler},
+		"pins":        {path: "/api/v1/export/pins", handler: ws.pinsHandler},
+		"annotations": {path: "/api/v1/annotations", handler: ws.annotationsHandler},
+		"sbom":        {path: "/api/v1/sbom/", handler: ws.sbomHandler, pathParam: "series"},
+	}
+}
+
+// jobTypes lists the types of a kind of job, for error messages
+func (ws *WebService) jobTypes(kind string) string {
+	var types []string
+	for name := range ws.jobTargets(kind) {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return strings.Join(types, ", ")
+}
+
+// renderEndpoint runs a GET request against a handler in process and returns its response
+func renderEndpoint(handler http.HandlerFunc, target string) (jobs.Output, error) {
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		return jobs.Output{}, fmt.Errorf("%s returned status %d: %s", target, rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+	return jobs.Output{ContentType: rec.Header().Get("Content-Type"), Body: rec.Body.Bytes()}, nil
+}
+
+// jobFileExtension returns the file extension of the downloaded output of a job
+func jobFileExtension(contentType string) string {
+	switch {
+	case strings.Contains(contentType, "json"):
+		return ".json"
+	case strings.HasPrefix(contentType, "text/html"):
+		return ".html"
+	case strings.HasPrefix(contentType, "text/csv"):
+		return ".csv"
+	}
+	return ".txt"
+}
+
+// jobStatus is a job as returned by the API, with where to poll and download it
+type jobStatus struct {
+	jobs.Job
+	StatusURL   string `json:"status_url"`
+	DownloadURL string `json:"download_url,omitempty"` // Set once the job succeeded
+}
+
+// newJobStatus adds the URLs of a job
+func newJobStatus(job jobs.Job) jobStatus {
+	status := jobStatus{Job: job, StatusURL: "/api/v1/jobs/" + job.ID}
+	if job.Status == jobs.StatusSucceeded {
+		status.DownloadURL = status.StatusURL + "/download"
+	}
+	return status
+}
+
+// jobsAuthorized reports whether a request may use the job endpoints: a logged in user, or a
+// CI system presenting the job token. Without a token and without login, anyone may.
+func (ws *WebService) jobsAuthorized(r *http.Request) bool {
+	if _, ok := auth.SessionFromContext(r.Context()); ok {
+		return true
+	}
+	token := ws.config.Jobs.GetToken()
+	if token == "" {
+		return !ws.config.Auth.OIDC.Enabled
+	}
+	presented := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
+
+// checkJobsRequest writes the error of a job request that cannot be served and returns false
+func (ws *WebService) checkJobsRequest(w http.ResponseWriter, r *http.Request, method string) bool {
+	if ws.jobStore == nil {
+		problems.Write(w, http.StatusServiceUnavailable, problems.NotConfigured, "Jobs are not enabled")
+		return false
+	}
+	if !ws.jobsAuthorized(r) {
+		problems.Write(w, http.StatusUnauthorized, problems.Unauthorized, "Invalid or missing job token")
+		return false
+	}
+	if r.Method != method {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return false
+	}
+	return true
+}
+
+// submitJob starts a job saving the response of an export or report. The query parameters of
+// the request, other than type, are passed on to the endpoint.
+func (ws *WebService) submitJob(w http.ResponseWriter, r *http.Request, kind, jobType string) {
+	target, ok := ws.jobTargets(kind)[jobType]
+	if !ok {
+		problems.Write(w, http.StatusNotFound, problems.NotFound, fmt.Sprintf("Unknown %s type %q; expected one of %s", kind, jobType, ws.jobTypes(kind)))
+		return
+	}
+	query := r.URL.Query()
+	query.Del("type")
+	path := target.path
+	if target.pathParam != "" {
+		value := query.Get(target.pathParam)
+		if value == "" {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, fmt.Sprintf("%s is required", target.pathParam))
+			return
+		}
+		path += url.PathEscape(value)
+		query.Del(target.pathParam)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+
+	job, err := ws.jobStore.Submit(kind, jobType, func() (jobs.Output, error) {
+		return renderEndpoint(target.handler, path)
+	}, time.Now())
+	if errors.Is(err, jobs.ErrBusy) {
+		problems.Write(w, http.StatusTooManyRequests, problems.RateLimited, "Too many jobs are running, retry later")
+		return
+	}
+
+	status := newJobStatus(job)
+	w.Header().Set("Location", status.StatusURL)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(status)
+}
+
+// reportsGenerateHandler starts a report job (POST /api/v1/reports/generate), of the type
+// given by ?type= or a {"type": ...} body: "lrm" (default), "lrm-rebuilds" or "dashboard"
+func (ws *WebService) reportsGenerateHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ws.checkJobsRequest(w, r, http.MethodPost) {
+		return
+	}
+
+	var body struct {
+		Type string `json:"type"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid report request body")
+			return
+		}
+	}
+	reportType := r.URL.Query().Get("type")
+	if reportType == "" {
+		reportType = body.Type
+	}
+	if reportType == "" {
+		reportType = "lrm"
+	}
+	ws.submitJob(w, r, jobKindReport, reportType)
+}
+
+// exportsHandler starts an export job (POST /api/v1/exports/{type})
+func (ws *WebService) exportsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ws.checkJobsRequest(w, r, http.MethodPost) {
+		return
+	}
+	ws.submitJob(w, r, jobKindExport, strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/exports"), "/"))
+}
+
+// jobsHandler lists the jobs (/api/v1/jobs), returns one (/api/v1/jobs/{id}) or downloads its
+// output (/api/v1/jobs/{id}/download)
+func (ws *WebService) jobsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ws.checkJobsRequest(w, r, http.MethodGet) {
+		return
+	}
+
+	now := time.Now()
+	id, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/jobs"), "/"), "/")
+	switch {
+	case id == "":
+		list := []jobStatus{}
+		for _, job := range ws.jobStore.List(now) {
+			list = append(list, newJobStatus(job))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jobs": list})
+	case action == "":
+		job, ok := ws.jobStore.Get(id, now)
+		if !ok {
+			problems.Write(w, http.StatusNotFound, problems.NotFound, "Job not found or expired")
+			return
+		}
+		json.NewEncoder(w).Encode(newJobStatus(job))
+	case action == "download":
+		job, body, err := ws.jobStore.Read(id, now)
+		switch {
+		case job.ID == "":
+			problems.Write(w, http.StatusNotFound, problems.NotFound, "Job not found or expired")
+		case job.Status != jobs.StatusSucceeded:
+			problems.Write(w, http.StatusConflict, problems.ValidationFailed, fmt.Sprintf("Job is %s, it has no output", job.Status))
+		case err != nil:
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to read the job output")
+		default:
+			filename := job.Type + "-" + job.CreatedAt.UTC().Format("20060102-150405") + jobFileExtension(job.ContentType)
+			w.Header().Set("Content-Type", job.ContentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+			w.Write(body)
+		}
+	default:
+		problems.Write(w, http.StatusNotFound, problems.NotFound, "Not found")
+	}
+}
@@ -5,10 +5,14 @@ import (
 	"html/template"
 	"log"
 	"net/http"
+	"net/url"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/i18n"
 	"nvidia_driver_monitor/internal/lrm"
 )
 
@@ -27,17 +31,140 @@ func NewLRMHandler(templatePath string, cfg *config.Config) *LRMHandler {
 	}
 }
 
+// LRMFilters selects kernels of the L-R-M data. The page and /api/lrm read them from the same
+// query parameters, so a filtered page URL can be shared and matches the API.
+type LRMFilters struct {
+	Series  string // e.g. "24.04"
+	Routing string // e.g. "ubuntu/4"
+	Status  string // SUPPORTED, LTS, ESM or DEV
+	Branch  string // Driver branch carried by the kernel, e.g. "570-server"
+}
+
+// parseLRMFilters reads the filters from the query parameters
+func parseLRMFilters(query url.Values) LRMFilters {
+	return LRMFilters{
+		Series:  query.Get("series"),
+		Routing: query.Get("routing"),
+		Status:  strings.ToUpper(query.Get("status")),
+		Branch:  query.Get("branch"),
+	}
+}
+
+// Query returns the filters as a query string, "" when none is set
+func (f LRMFilters) Query() string {
+	query := url.Values{}
+	for key, value := range map[string]string{"series": f.Series, "routing": f.Routing, "status": f.Status, "branch": f.Branch} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+// Active reports whether any filter is set
+func (f LRMFilters) Active() bool {
+	return f.Series != "" || f.Routing != "" || f.Status != "" || f.Branch != ""
+}
+
+// Apply returns the kernels matching every filter that is set
+func (f LRMFilters) Apply(results []lrm.KernelLRMResult) []lrm.KernelLRMResult {
+	var criteria lrm.FilterCriteria
+	if f.Routing != "" {
+		criteria.Routing = &f.Routing
+	}
+	matched := true
+	switch f.Status {
+	case "SUPPORTED":
+		criteria.Supported = &matched
+	case "DEV", "DEVELOPMENT":
+		criteria.Development = &matched
+	}
+	results = lrm.FilterKernelData(results, criteria)
+
+	if f.Series != "" {
+		results = filterBySeries(results, f.Series)
+	}
+	// LTS and ESM are not part of the criteria; unknown statuses match nothing
+	if f.Status != "" && criteria.Supported == nil && criteria.Development == nil {
+		results = filterByStatus(results, f.Status)
+	}
+	if f.Branch != "" {
+		results = filterByBranch(results, f.Branch)
+	}
+	return results
+}
+
+// LRMFilterOptions lists the values the page filters can take in the L-R-M data
+type LRMFilterOptions struct {
+	Series   []string
+	Routings []string
+	Branches []string
+}
+
+// lrmFilterOptions collects the series, routings and driver branches of the kernels
+func lrmFilterOptions(results []lrm.KernelLRMResult) LRMFilterOptions {
+	series := make(map[string]bool)
+	routings := make(map[string]bool)
+	branches := make(map[string]bool)
+	for _, result := range results {
+		series[result.Series] = true
+		routings[result.Routing] = true
+		for _, driver := range result.NvidiaDriverStatuses {
+			branches[branchFromPackage(driver.DriverName)] = true
+		}
+	}
+
+	options := LRMFilterOptions{
+		Series:   sortedKeys(series),
+		Routings: sortedKeys(routings),
+		Branches: sortedKeys(branches),
+	}
+	// Newest series first, as in the table
+	sort.Sort(sort.Reverse(sort.StringSlice(options.Series)))
+	return options
+}
+
+// sortedKeys returns the non-empty keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // ServeHTTP handles requests for L-R-M verifier information
 func (h *LRMHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	reqID := start.UnixNano()
 	log.Printf("[LRM ServeHTTP] start req=%d method=%s path=%s at=%s", reqID, r.Method, r.URL.Path, start.Format(time.RFC3339Nano))
 
+	locale := requestLocale(w, r, h.config)
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 	w.Header().Set("Pragma", "no-cache")
 	w.Header().Set("Expires", "0")
 
+	// ?view= opens a preset, whose filters the query can override
+	query := r.URL.Query()
+	var preset *config.PresetConfig
+	if name := query.Get("view"); name != "" {
+		if h.config != nil {
+			preset = h.config.Preset(config.PresetPageLRM, name)
+		}
+		if preset == nil {
+			http.Error(w, i18n.T(locale, "error.view_not_found"), http.StatusNotFound)
+			return
+		}
+		query = withPreset(query, preset)
+	}
+
 	var lrmData *lrm.LRMVerifierData
 	cacheStart := time.Now()
 
@@ -65,9 +192,18 @@ func (h *LRMHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		lrmData = realData
 	}
 
+	// Filters come from the query so filtered views have shareable URLs
+	filters := parseLRMFilters(query)
+	options := lrmFilterOptions(lrmData.KernelResults)
+	if filters.Active() {
+		filtered := *lrmData
+		filtered.KernelResults = filters.Apply(lrmData.KernelResults)
+		lrmData = &filtered
+	}
+
 	// Load and parse template
 	templateFile := filepath.Join(h.templatePath, "lrm_verifier.html")
-	tmpl := template.New("lrm_verifier.html").Funcs(TemplateFunctions())
+	tmpl := template.New("lrm_verifier.html").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, requestTimezone(w, r, h.config)))
 
 	var err error
 	parseStart := time.Now()
@@ -81,19 +217,21 @@ func (h *LRMHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Prepare template data
 	templateData := struct {
-		Data *lrm.LRMVerifierData
-		CDN  map[string]string
+		Data    *lrm.LRMVerifierData
+		Filters LRMFilters
+		Options LRMFilterOptions
+		Preset  *config.PresetConfig
+		CDN     map[string]string
 	}{
-		Data: lrmData,
-		CDN:  GetCDNResources(h.config),
+		Data:    lrmData,
+		Filters: filters,
+		Options: options,
+		Preset:  preset,
+		CDN:     GetCDNResources(h.config),
 	}
 
 	// Execute template
 	execStart := time.Now()
-	if err := tmpl.Execute(w, templateData); err != nil {
-		log.Printf("[LRM ServeHTTP] req=%d template exec error after=%s err=%v", reqID, time.Since(execStart), err)
-		http.Error(w, fmt.Sprintf("Template execution error: %v", err), http.StatusInternalServerError)
-		return
-	}
+	renderPage(w, tmpl, templateData)
 	log.Printf("[LRM ServeHTTP] done req=%d total=%s (cache=%s, parse=%s, exec=%s)", reqID, time.Since(start), time.Since(cacheStart), time.Since(parseStart), time.Since(execStart))
 }
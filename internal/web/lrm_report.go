@@ -0,0 +1,178 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/i18n"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/lrmreport"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/sru"
+)
+
+// lrmReportCheckInterval is how often the L-R-M report loop looks for a cycle whose report is due
+const lrmReportCheckInterval = time.Hour
+
+// lrmProblems returns the supported kernels with L-R-M whose verification found a problem: a
+// driver behind its DKMS version, a failed build, or a version that could not be read
+func lrmProblems(kernels []lrm.KernelLRMResult) []lrmreport.Problem {
+	var problems []lrmreport.Problem
+	for _, kernel := range kernels {
+		if !kernel.Supported || !kernel.HasLRM {
+			continue
+		}
+		problem := lrmreport.Problem{Kernel: kernel.Source, Series: kernel.Series, Version: kernel.LatestLRMVersion}
+		for _, driver := range kernel.NvidiaDriverStatuses {
+			if !strings.Contains(driver.Status, "Up to date") && driver.DKMSVersion != "" {
+				problem.Drivers = append(problem.Drivers, fmt.Sprintf("%s %s, DKMS %s", driver.DriverName, driver.DSCVersion, driver.DKMSVersion))
+			}
+		}
+		var failed []string
+		for _, build := range kernel.BuildStatuses {
+			if build.State == lrm.BuildStateFailed {
+				failed = append(failed, build.Package+": "+build.Summary)
+			}
+		}
+		problem.BuildStatus = strings.Join(failed, "; ")
+		if len(problem.Drivers) == 0 && problem.BuildStatus == "" && kernel.LatestLRMVersion != "ERROR" {
+			continue
+		}
+		problems = append(problems, problem)
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Series != problems[j].Series {
+			return problems[i].Series > problems[j].Series
+		}
+		return problems[i].Kernel < problems[j].Kernel
+	})
+	return problems
+}
+
+// buildLRMReport renders the report of the L-R-M problems for an SRU cycle, in the configured
+// locale and time zone since it is sent rather than requested
+func (ws *WebService) buildLRMReport(data *lrm.LRMVerifierData, cycle *sru.SRUCycle, now time.Time) (lrmreport.Report, error) {
+	report := lrmreport.Report{
+		Cycle:       cycle.Name,
+		ReleaseDate: cycle.ReleaseDate,
+		GeneratedAt: now,
+		Problems:    lrmProblems(data.KernelResults),
+	}
+	report.Subject = fmt.Sprintf("L-R-M verification for SRU cycle %s: %d kernels with problems", cycle.Name, len(report.Problems))
+
+	locale, loc, dashboardURL := i18n.DefaultLocale, time.UTC, ""
+	if ws.config != nil {
+		locale = ws.config.I18n.GetDefaultLocale()
+		dashboardURL = strings.TrimSuffix(ws.config.LRMReport.DashboardURL, "/")
+		if zone, err := time.LoadLocation(ws.config.I18n.GetDefaultTimezone()); err == nil {
+			loc = zone
+		}
+	}
+	tmpl, err := template.New("lrm_report.html").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, loc)).
+		ParseFiles(filepath.Join(ws.templatePath, "lrm_report.html"))
+	if err != nil {
+		return report, fmt.Errorf("failed to parse report template: %w", err)
+	}
+
+	var html bytes.Buffer
+	err = tmpl.Execute(&html, struct {
+		lrmreport.Report
+		DataUpdated  time.Time
+		DashboardURL string
+	}{
+		Report:       report,
+		DataUpdated:  data.LastUpdated,
+		DashboardURL: dashboardURL,
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to render report: %w", err)
+	}
+	report.HTML = html.String()
+	return report, nil
+}
+
+// runLRMReport sends the L-R-M report of the cycle due at now unless it was already sent. It
+// returns false when the L-R-M data or the SRU cycles are not loaded yet.
+func (ws *WebService) runLRMReport(now time.Time) bool {
+	data, err := lrm.GetCachedLRMData()
+	sruCycles := ws.getUpstreamData().sruCycles
+	if err != nil || !data.IsInitialized || sruCycles == nil {
+		return false
+	}
+	cfg := &ws.config.LRMReport
+	cycle := lrmreport.DueCycle(sruCycles.Cycles, cfg.GetDaysBeforeRelease(), now)
+	if cycle == nil || ws.lrmReportStore.Sent(cycle.Name) {
+		return true
+	}
+
+	report, err := ws.buildLRMReport(data, cycle, now)
+	if err != nil {
+		log.Printf("Warning: Failed to build the L-R-M report for %s: %v", cycle.Name, err)
+		return true
+	}
+	if err := lrmreport.Deliver(cfg, report); err != nil {
+		// Retried at the next check; deliveries that succeeded are sent again then
+		log.Printf("Warning: Failed to deliver the L-R-M report for %s: %v", cycle.Name, err)
+		return true
+	}
+	log.Printf("Sent the L-R-M report for %s with %d problems", cycle.Name, len(report.Problems))
+	ws.lrmReportStore.Record(lrmreport.SentReport{Cycle: cycle.Name, SentAt: now, Problems: len(report.Problems)})
+	return true
+}
+
+// lrmReportLoop sends the L-R-M report once the data is loaded, then checks hourly for a cycle
+// whose report is due
+func (ws *WebService) lrmReportLoop() {
+	timer := time.NewTimer(archiveCheckRetry)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			next := lrmReportCheckInterval
+			if !ws.runLRMReport(time.Now()) {
+				next = archiveCheckRetry
+			}
+			timer.Reset(next)
+		case <-ws.stopChan:
+			log.Printf("Stopping L-R-M report loop...")
+			return
+		}
+	}
+}
+
+// lrmReportPreviewHandler shows the L-R-M report as it would be sent now for the next cycle
+// (/api/v1/lrm/report), to check the template and recipients' view before it goes out
+func (ws *WebService) lrmReportPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	data, err := lrm.GetCachedLRMData()
+	sruCycles := ws.getUpstreamData().sruCycles
+	if err != nil || !data.IsInitialized || sruCycles == nil {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "L-R-M data is not loaded yet")
+		return
+	}
+	// The next cycle that is not complete, however far its release
+	cycle := lrmreport.DueCycle(sruCycles.Cycles, 366, time.Now())
+	if cycle == nil {
+		problems.Write(w, http.StatusNotFound, problems.NotFound, "No upcoming SRU cycle")
+		return
+	}
+	report, err := ws.buildLRMReport(data, cycle, time.Now())
+	if err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to render the report")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Report-Subject", report.Subject)
+	w.Write([]byte(report.HTML))
+}
@@ -0,0 +1,203 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/releases"
+)
+
+// MatrixLRM is the L-R-M coverage of a branch in a series: the kernels whose
+// linux-restricted-modules carry the branch's driver
+type MatrixLRM struct {
+	Kernels         int `json:"kernels"`
+	UpToDate        int `json:"up_to_date"`
+	UpdateAvailable int `json:"update_available"`
+}
+
+// MatrixSRU is the SRU targeting of a branch in a series
+type MatrixSRU struct {
+	Cycle      string `json:"cycle"`                 // Release date of the SRU cycle shown, "-" when none
+	NextCutoff string `json:"next_cutoff,omitempty"` // Cutoff an upload made now would make; only set while one is needed
+}
+
+// MatrixCell joins the supported releases, archive, L-R-M and SRU data of a (branch, series) pair
+type MatrixCell struct {
+	Branch      string `json:"branch"`
+	PackageName string `json:"package_name"`
+	Series      string `json:"series"`
+	Supported   bool   `json:"supported"` // Whether supportedReleases lists the series for the branch
+	// Pockets maps the published pockets holding the published version, and Proposed, to their versions
+	Pockets  map[string]string `json:"pockets"`
+	Upstream string            `json:"upstream,omitempty"`
+	Target   string            `json:"target,omitempty"`
+	Status   string            `json:"status,omitempty"` // Color of the published version, e.g. "success" or "danger"
+	Removed  string            `json:"removed,omitempty"`
+	LRM      *MatrixLRM        `json:"lrm,omitempty"` // Nil until the L-R-M data is loaded
+	SRU      MatrixSRU         `json:"sru"`
+}
+
+// rowPockets returns the versions of a row per pocket. The published version is attributed to
+// the pockets its markers name, e.g. " (U/S/-)" for Updates and Security.
+func rowPockets(row *SeriesData, publishedPockets []string) map[string]string {
+	pockets := make(map[string]string)
+	if isArchiveVersion(row.UpdatesSecurity) {
+		markers := strings.Split(strings.Trim(strings.TrimSpace(row.PocketMarkers), "()"), "/")
+		for i, pocket := range publishedPockets {
+			if i < len(markers) && markers[i] != "-" && markers[i] != "" {
+				pockets[pocket] = row.UpdatesSecurity
+			}
+		}
+	}
+	if isArchiveVersion(row.Proposed) {
+		pockets["Proposed"] = row.Proposed
+	}
+	return pockets
+}
+
+// matrixLRM counts the kernels of a series carrying the branch's driver
+func matrixLRM(kernels []lrm.KernelLRMResult, branch, series string) *MatrixLRM {
+	coverage := &MatrixLRM{}
+	for _, kernel := range kernels {
+		if kernel.Codename != series {
+			continue
+		}
+		for _, driver := range kernel.NvidiaDriverStatuses {
+			if branchFromPackage(driver.DriverName) != branch {
+				continue
+			}
+			coverage.Kernels++
+			switch {
+			case strings.Contains(driver.Status, "Up to date"):
+				coverage.UpToDate++
+			case strings.Contains(driver.Status, "Update available"):
+				coverage.UpdateAvailable++
+			}
+		}
+	}
+	return coverage
+}
+
+// buildMatrix returns a cell for every branch and every series it is supported in or has a row
+// for. kernels is nil while the L-R-M data is not loaded.
+func (ws *WebService) buildMatrix(index *packageIndex, supportedReleases []releases.SupportedRelease, kernels []lrm.KernelLRMResult, now time.Time) []MatrixCell {
+	releaseByBranch := make(map[string]*releases.SupportedRelease, len(supportedReleases))
+	branches := append([]string{}, index.branches...)
+	for i := range supportedReleases {
+		release := &supportedReleases[i]
+		if _, ok := index.byBranch[release.BranchName]; !ok {
+			branches = append(branches, release.BranchName)
+		}
+		releaseByBranch[release.BranchName] = release
+	}
+	sort.Strings(branches)
+
+	sruCycles := ws.getUpstreamData().sruCycles
+	publishedPockets := packages.PublishedPockets()
+	cells := []MatrixCell{}
+	for _, branch := range branches {
+		release := releaseByBranch[branch]
+		pkg := index.byBranch[branch]
+		var packageName string
+		if release != nil {
+			packageName = release.PackageName()
+		}
+
+		var series []string
+		rows := make(map[string]*SeriesData)
+		if pkg != nil {
+			packageName = pkg.PackageName
+			for i := range pkg.Series {
+				rows[pkg.Series[i].Series] = &pkg.Series[i]
+				series = append(series, pkg.Series[i].Series)
+			}
+		}
+		if release != nil {
+			var extra []string
+			for codename, supported := range release.IsSupported {
+				if _, ok := rows[codename]; !ok && supported {
+					extra = append(extra, codename)
+				}
+			}
+			sort.Strings(extra)
+			series = append(series, extra...)
+		}
+
+		for _, codename := range series {
+			cell := MatrixCell{
+				Branch:      branch,
+				PackageName: packageName,
+				Series:      codename,
+				Supported:   release != nil && release.IsSupported[codename],
+				Pockets:     map[string]string{},
+				SRU:         MatrixSRU{Cycle: "-"},
+			}
+			if row := rows[codename]; row != nil {
+				cell.Pockets = rowPockets(row, publishedPockets)
+				cell.Upstream = row.UpstreamVersion
+				cell.Target = row.TargetVersion
+				cell.Status = row.UpdatesColor
+				cell.Removed = row.Removed
+				cell.SRU.Cycle = row.SRUCycle
+				if needsUpload(row) && sruCycles != nil {
+					if cycle := targetCutoff(sruCycles, row.ReleaseDate, now); cycle != nil {
+						cell.SRU.NextCutoff = cycle.CutoffDate
+					}
+				}
+			}
+			if kernels != nil {
+				cell.LRM = matrixLRM(kernels, branch, codename)
+			}
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// matrixHandler returns the support matrix of every branch and series (/api/v1/matrix),
+// optionally filtered with ?branch= and ?series=
+func (ws *WebService) matrixHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	index, lastUpdated, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+
+	// The matrix does not wait for the kernel data to be loaded
+	var kernels []lrm.KernelLRMResult
+	if initialized, _ := lrm.GetCacheStatus()["initialized"].(bool); initialized {
+		if lrmData, err := lrm.GetCachedLRMData(); err == nil {
+			kernels = lrmData.KernelResults
+		}
+	}
+
+	branch := r.URL.Query().Get("branch")
+	if branch != "" {
+		branch = branchFromPackage(branch)
+	}
+	series := r.URL.Query().Get("series")
+	cells := []MatrixCell{}
+	for _, cell := range ws.buildMatrix(index, ws.getUpstreamData().supportedReleases, kernels, time.Now()) {
+		if (branch == "" || cell.Branch == branch) && (series == "" || cell.Series == series) {
+			cells = append(cells, cell)
+		}
+	}
+
+	response := map[string]interface{}{
+		"cells":        cells,
+		"count":        len(cells),
+		"lrm_loaded":   kernels != nil,
+		"last_updated": lastUpdated,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/notes"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// applyNotes copies the operator notes of a package onto its series rows
+func applyNotes(store *notes.Store, packageName string, seriesData []SeriesData) {
+	if store == nil {
+		return
+	}
+	for i := range seriesData {
+		seriesData[i].Note, seriesData[i].NoteUpdated = "", ""
+		if note, ok := store.Get(packageName, seriesData[i].Series); ok {
+			seriesData[i].Note = note.Text
+			seriesData[i].NoteUpdated = noteUpdated(note)
+		}
+	}
+}
+
+// noteUpdated describes who last changed a note and when, e.g. "jdoe on 2026-10-17"
+func noteUpdated(note notes.Note) string {
+	updated := "on " + note.UpdatedAt.Format("2006-01-02")
+	if note.UpdatedBy != "" {
+		updated = note.UpdatedBy + " " + updated
+	}
+	return updated
+}
+
+// reapplyCellAnnotations swaps the cached packages for copies carrying the current notes,
+// acknowledgements and default branches, so edits show up without waiting for the next refresh
+func (ws *WebService) reapplyCellAnnotations() {
+	ws.cacheMux.Lock()
+	defer ws.cacheMux.Unlock()
+
+	updated := make([]*PackageData, 0, len(ws.cache.AllPackages))
+	for _, pkg := range ws.cache.AllPackages {
+		copied := *pkg
+		copied.Series = append([]SeriesData(nil), pkg.Series...)
+		applyNotes(ws.noteStore, copied.PackageName, copied.Series)
+		applyAcknowledgements(ws.ackStore, copied.PackageName, copied.Series, time.Now())
+		applyDefaultBranch(ws.seedDefaults, copied.PackageName, copied.Series)
+		updated = append(updated, &copied)
+	}
+	ws.cache.setPackages(updated)
+}
+
+// notesHandler lists notes (GET), or sets (PUT) or removes (DELETE) the note of one cell
+// (/api/v1/notes?package={name}&series={codename})
+func (ws *WebService) notesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodGet {
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"notes": ws.noteStore.List()}); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+		return
+	}
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !ws.requireAdmin(w, r) {
+		return
+	}
+
+	packageName := r.URL.Query().Get("package")
+	series := r.URL.Query().Get("series")
+	if packageName == "" || series == "" {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "package and series are required")
+		return
+	}
+	if !strings.HasPrefix(packageName, "nvidia-graphics-drivers-") {
+		packageName = "nvidia-graphics-drivers-" + packageName
+	}
+
+	index, _, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	if _, ok := index.row(packageName, series); !ok {
+		problems.Write(w, http.StatusNotFound, problems.PackageNotFound, "No such package and series on the dashboard")
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if !ws.noteStore.Remove(packageName, series) {
+			problems.Write(w, http.StatusNotFound, problems.NotFound, "Note not found")
+			return
+		}
+		ws.reapplyCellAnnotations()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var body struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid JSON")
+		return
+	}
+	updatedBy := ""
+	if session, ok := auth.SessionFromContext(r.Context()); ok {
+		updatedBy = session.Name
+	}
+	note, err := ws.noteStore.Set(packageName, series, body.Text, updatedBy, time.Now())
+	if err != nil {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, err.Error())
+		return
+	}
+	ws.reapplyCellAnnotations()
+	json.NewEncoder(w).Encode(note)
+}
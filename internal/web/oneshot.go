@@ -0,0 +1,119 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/atomicfile"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// Exit codes of the oneshot mode
+const (
+	OneshotHealthy  = 0
+	OneshotFailed   = 1 // The data could not be loaded or the output could not be written
+	OneshotDegraded = 2 // The output was written, but part of the data is missing or alerts fired
+)
+
+// OneshotResult is the health of a oneshot run, written as health.json
+type OneshotResult struct {
+	Status        string          `json:"status"` // "healthy" or "degraded"
+	GeneratedAt   time.Time       `json:"generated_at"`
+	Reasons       []string        `json:"reasons"`
+	Datasets      []DatasetStatus `json:"datasets"`
+	PackageErrors int             `json:"package_errors"`
+	Alerts        []*alerts.Alert `json:"alerts"`
+}
+
+// ExitCode returns the process exit code reflecting the health of the run
+func (r *OneshotResult) ExitCode() int {
+	if r.Status == "healthy" {
+		return OneshotHealthy
+	}
+	return OneshotDegraded
+}
+
+// oneshotHealth reports the run as degraded when a dataset did not load, a package could not
+// be generated, or an alert is firing
+func oneshotHealth(datasets []DatasetStatus, packageErrors []*PackageError, activeAlerts []*alerts.Alert, now time.Time) *OneshotResult {
+	result := &OneshotResult{
+		Status:        "healthy",
+		GeneratedAt:   now,
+		Reasons:       []string{},
+		Datasets:      datasets,
+		PackageErrors: len(packageErrors),
+		Alerts:        activeAlerts,
+	}
+	for _, status := range datasets {
+		if status.State != DatasetLoaded {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("dataset %s %s", status.Name, status.State))
+		}
+	}
+	for _, packageError := range packageErrors {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s could not be generated", packageError.PackageName))
+	}
+	for _, alert := range activeAlerts {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("alert %s firing", alert.Name))
+	}
+	if len(result.Reasons) > 0 {
+		result.Status = "degraded"
+	}
+	return result
+}
+
+// RunOneshot refreshes the data once, writes the rendered index page (index.html), the API
+// data (api/packages.json), the snapshot of /api/v1/snapshot (snapshot.json) and the health of
+// the run (health.json) into outDir, and returns that health. No server or background loop is
+// started, for cron jobs and serverless functions.
+func RunOneshot(cfg *config.Config, templatePath, supportedReleasesPath, outDir string) (*OneshotResult, error) {
+	ws := newOfflineService(cfg, templatePath, supportedReleasesPath)
+	if err := ws.loadOnce("Oneshot"); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(outDir, "api"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %v", err)
+	}
+	for _, page := range []staticPage{
+		{Path: "index.html", Handler: ws.indexHandler},
+		{Path: "api/packages.json", Handler: ws.apiHandler},
+		{Path: "snapshot.json", Handler: ws.snapshotJSONHandler},
+	} {
+		body, err := renderStaticPage(page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %v", page.Path, err)
+		}
+		// Readers of the output directory never see a partially written file
+		if err := atomicfile.Write(filepath.Join(outDir, page.Path), body); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", page.Path, err)
+		}
+		log.Printf("Oneshot: wrote %s", page.Path)
+	}
+
+	result := oneshotHealth(ws.getDatasets(), ws.getPackageErrors(), alerts.Active(), time.Now())
+	if err := atomicfile.WriteJSON(filepath.Join(outDir, "health.json"), result); err != nil {
+		return nil, fmt.Errorf("failed to write health.json: %v", err)
+	}
+	log.Printf("Oneshot completed: %s, output written to %s", result.Status, outDir)
+	return result, nil
+}
+
+// snapshotJSONHandler writes the snapshot without the peer token check of snapshotHandler,
+// as the oneshot mode writes it to a local directory
+func (ws *WebService) snapshotJSONHandler(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := ws.getSnapshot()
+	if !ok {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Data is still loading")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode snapshot")
+	}
+}
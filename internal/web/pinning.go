@@ -0,0 +1,152 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// Formats of the recommended versions export
+const (
+	pinFormatJSON      = "json"
+	pinFormatApt       = "apt"       // apt_preferences(5) snippet, one stanza per source package
+	pinFormatLandscape = "landscape" // name=version lines of the driver metapackages
+)
+
+// defaultPinPriority lets apt downgrade to the pinned version, e.g. after a proposed test
+const defaultPinPriority = 1001
+
+// PinnedVersion is the driver version recommended for a series: the published version, never
+// the proposed one
+type PinnedVersion struct {
+	Package     string `json:"package"`               // Source package, e.g. "nvidia-graphics-drivers-570"
+	Branch      string `json:"branch"`                // e.g. "570" or "535-server"
+	Series      string `json:"series"`                // Codename, e.g. "noble"
+	Version     string `json:"version"`               // Published (updates/security) version
+	Metapackage string `json:"metapackage,omitempty"` // e.g. "nvidia-driver-570"; empty for Tegra branches
+	UpToDate    bool   `json:"up_to_date"`            // False while the published version is behind upstream
+}
+
+// driverMetapackage returns the binary package installing a desktop or server driver branch,
+// or "" for Tegra packages
+func driverMetapackage(packageName string) string {
+	if !strings.HasPrefix(packageName, udaPackagePrefix) {
+		return ""
+	}
+	return "nvidia-driver-" + strings.TrimPrefix(packageName, udaPackagePrefix)
+}
+
+// recommendedVersions returns the published version of each package in each series, optionally
+// limited to a series and a branch. Removed rows and series without a publication are left out.
+func recommendedVersions(pkgs []*PackageData, series, branch string) []PinnedVersion {
+	pins := []PinnedVersion{}
+	for _, pkg := range pkgs {
+		pkgBranch := branchFromPackage(pkg.PackageName)
+		if branch != "" && pkgBranch != branch {
+			continue
+		}
+		for _, row := range pkg.Series {
+			if (series != "" && row.Series != series) || row.Removed != "" || !isArchiveVersion(row.UpdatesSecurity) {
+				continue
+			}
+			pins = append(pins, PinnedVersion{
+				Package:     pkg.PackageName,
+				Branch:      pkgBranch,
+				Series:      row.Series,
+				Version:     row.UpdatesSecurity,
+				Metapackage: driverMetapackage(pkg.PackageName),
+				UpToDate:    row.UpdatesColor != "danger",
+			})
+		}
+	}
+	return pins
+}
+
+// aptPreferences renders pins of a single series as an apt_preferences(5) snippet. Pinning the
+// source package covers every binary built from it.
+func aptPreferences(pins []PinnedVersion, series string, priority int, generated time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# NVIDIA driver versions recommended for %s by nvidia_driver_monitor\n", series)
+	fmt.Fprintf(&b, "# Data from %s; install as /etc/apt/preferences.d/nvidia-drivers.pref\n", generated.UTC().Format("2006-01-02 15:04 UTC"))
+	for _, pin := range pins {
+		b.WriteString("\n")
+		if !pin.UpToDate {
+			fmt.Fprintf(&b, "Explanation: %s is behind upstream; an update is expected\n", pin.Version)
+		}
+		fmt.Fprintf(&b, "Package: src:%s\nPin: version %s\nPin-Priority: %d\n", pin.Package, pin.Version, priority)
+	}
+	return b.String()
+}
+
+// landscapePackageList renders pins as name=version lines of the driver metapackages, as taken
+// by Landscape package profiles and apt-get install. Tegra branches have no metapackage and are
+// left out.
+func landscapePackageList(pins []PinnedVersion) string {
+	var b strings.Builder
+	for _, pin := range pins {
+		if pin.Metapackage != "" {
+			fmt.Fprintf(&b, "%s=%s\n", pin.Metapackage, pin.Version)
+		}
+	}
+	return b.String()
+}
+
+// pinsHandler exports the recommended driver version per series for fleet management tooling
+// (/api/v1/export/pins?format=json|apt|landscape&series=&branch=&priority=). The apt and
+// landscape formats need a series, as a host only installs the versions of its own.
+func (ws *WebService) pinsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = pinFormatJSON
+	}
+	series := query.Get("series")
+	priority := defaultPinPriority
+	if value := query.Get("priority"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			w.Header().Set("Content-Type", "application/json")
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "priority must be a positive integer")
+			return
+		}
+		priority = parsed
+	}
+	switch {
+	case format != pinFormatJSON && format != pinFormatApt && format != pinFormatLandscape:
+		w.Header().Set("Content-Type", "application/json")
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "format must be json, apt or landscape")
+		return
+	case format != pinFormatJSON && series == "":
+		w.Header().Set("Content-Type", "application/json")
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "series is required for the apt and landscape formats")
+		return
+	}
+
+	pkgs, lastUpdated, isInitialized := ws.getCachedPackages()
+	if !isInitialized {
+		w.Header().Set("Content-Type", "application/json")
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	pins := recommendedVersions(pkgs, series, query.Get("branch"))
+
+	switch format {
+	case pinFormatApt:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(aptPreferences(pins, series, priority, lastUpdated)))
+	case pinFormatLandscape:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(landscapePackageList(pins)))
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		response := map[string]interface{}{"pins": pins, "count": len(pins), "last_updated": lastUpdated}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+	}
+}
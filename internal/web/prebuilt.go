@@ -0,0 +1,137 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/pkg/debversion"
+)
+
+// Divergence of the pre-built signed modules of a kernel from the dkms package of a branch
+const (
+	prebuiltInSync  = "in-sync"         // The pre-built modules carry the published dkms version
+	prebuiltBehind  = "prebuilt-behind" // Secure Boot users get an older driver than dkms users
+	prebuiltAhead   = "prebuilt-ahead"  // The pre-built modules carry a version not published yet
+	prebuiltMissing = "not-prebuilt"    // The kernel has no pre-built modules of a branch published in its series
+)
+
+// PrebuiltDivergence compares, for one branch and kernel, the driver version of the pre-built
+// linux-modules-nvidia packages with the version delivered through nvidia-dkms
+type PrebuiltDivergence struct {
+	Branch        string `json:"branch"`
+	Series        string `json:"series"` // Codename, e.g. "noble"
+	Kernel        string `json:"kernel"` // Kernel source, e.g. "linux-aws"
+	KernelVersion string `json:"kernel_version"`
+	DKMS          string `json:"dkms"`               // Published version of the branch in the series
+	Prebuilt      string `json:"prebuilt,omitempty"` // Version pinned by the kernel's L-R-M dsc
+	Status        string `json:"status"`
+	Diverged      bool   `json:"diverged"`
+}
+
+// prebuiltDivergences compares the driver versions the L-R-M dsc of each kernel pins with the
+// versions the dashboard shows as published in the kernel's series. Kernels whose dsc was not
+// read are left out, as are branches not published in the series since they have no dkms path.
+func prebuiltDivergences(index *packageIndex, kernels []lrm.KernelLRMResult) []PrebuiltDivergence {
+	divergences := []PrebuiltDivergence{}
+	for _, kernel := range kernels {
+		if !kernel.HasLRM || len(kernel.NvidiaDriverVersions) == 0 {
+			continue
+		}
+		prebuilt := make(map[string]string)
+		for _, driver := range kernel.NvidiaDriverVersions {
+			if name, version, ok := strings.Cut(driver, "="); ok {
+				prebuilt[name] = version
+			}
+		}
+
+		for _, pkg := range index.packages {
+			row, ok := index.row(pkg.PackageName, kernel.Codename)
+			if !ok || row.Removed != "" || !isArchiveVersion(row.UpdatesSecurity) {
+				continue
+			}
+			divergence := PrebuiltDivergence{
+				Branch:        branchFromPackage(pkg.PackageName),
+				Series:        kernel.Codename,
+				Kernel:        kernel.Source,
+				KernelVersion: kernel.SourceVersion,
+				DKMS:          row.UpdatesSecurity,
+				Prebuilt:      prebuilt[pkg.PackageName],
+				Status:        prebuiltInSync,
+			}
+			switch {
+			case divergence.Prebuilt == "":
+				divergence.Status = prebuiltMissing
+			case debversion.OlderThan(divergence.Prebuilt, divergence.DKMS):
+				divergence.Status = prebuiltBehind
+			case debversion.OlderThan(divergence.DKMS, divergence.Prebuilt):
+				divergence.Status = prebuiltAhead
+			}
+			divergence.Diverged = divergence.Status != prebuiltInSync
+			divergences = append(divergences, divergence)
+		}
+	}
+
+	sort.SliceStable(divergences, func(i, j int) bool {
+		a, b := divergences[i], divergences[j]
+		if a.Branch != b.Branch {
+			return a.Branch < b.Branch
+		}
+		if a.Series != b.Series {
+			return a.Series < b.Series
+		}
+		return a.Kernel < b.Kernel
+	})
+	return divergences
+}
+
+// prebuiltHandler compares the pre-built signed modules of each kernel with the dkms packages
+// (/api/v1/prebuilt?branch={branch}&series={codename}&diverged=true)
+func (ws *WebService) prebuiltHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	index, lastUpdated, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	lrmData, err := lrm.GetCachedLRMData()
+	if err != nil {
+		problems.Write(w, http.StatusServiceUnavailable, problems.UpstreamUnavailable, "Kernel data is not available")
+		return
+	}
+
+	branch := r.URL.Query().Get("branch")
+	series := r.URL.Query().Get("series")
+	onlyDiverged := r.URL.Query().Get("diverged") == "true"
+	results := []PrebuiltDivergence{}
+	diverged := 0
+	for _, divergence := range prebuiltDivergences(index, lrmData.KernelResults) {
+		if (branch != "" && divergence.Branch != branch) || (series != "" && divergence.Series != series) {
+			continue
+		}
+		if divergence.Diverged {
+			diverged++
+		} else if onlyDiverged {
+			continue
+		}
+		results = append(results, divergence)
+	}
+
+	response := map[string]interface{}{
+		"kernels":      results,
+		"diverged":     diverged,
+		"last_updated": lastUpdated,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
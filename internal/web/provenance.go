@@ -0,0 +1,125 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// Provenance records where the data of one upstream source came from
+type Provenance struct {
+	Source          string     `json:"source"`
+	URL             string     `json:"url,omitempty"` // URL of the last fetch, or the configured URL if none happened yet
+	FetchedAt       *time.Time `json:"fetched_at,omitempty"`
+	HTTPStatus      int        `json:"http_status,omitempty"`
+	LoadedAt        *time.Time `json:"loaded_at,omitempty"`         // When the data being served was loaded
+	CacheAgeSeconds *int64     `json:"cache_age_seconds,omitempty"` // Age of the data being served
+	Error           string     `json:"error,omitempty"`
+}
+
+// CacheAge formats the age of the data being served, or returns "" when nothing was loaded
+func (p Provenance) CacheAge() string {
+	if p.CacheAgeSeconds == nil {
+		return ""
+	}
+	return (time.Duration(*p.CacheAgeSeconds) * time.Second).String()
+}
+
+// provenanceSource maps an upstream source to the URLs it fetches and the dataset holding its data
+type provenanceSource struct {
+	name    string
+	prefix  string // Every URL of the source starts with it
+	dataset string // Sub-dataset whose load time is the cache age; "" for mirrored files
+	mirror  string // Label of a mirrored file, see utils.FetchYAMLFromMirrors
+}
+
+// provenanceSources lists the upstream sources shown in the provenance panel
+func provenanceSources(cfg *config.Config) []provenanceSource {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	effective := cfg.GetEffectiveURLs()
+	archiveURL := cfg.URLs.NVIDIA.DriverArchiveURL
+	if !strings.HasSuffix(archiveURL, "/") {
+		archiveURL += "/"
+	}
+	return []provenanceSource{
+		{name: "nvidia.com UDA", prefix: archiveURL, dataset: datasetUDA},
+		{name: "Datacenter JSON", prefix: effective.NVIDIA.ServerDriversAPI, dataset: datasetERD},
+		{name: "Launchpad", prefix: cfg.URLs.Launchpad.BaseURL, dataset: datasetPackages},
+		{name: "kernel-series.yaml", prefix: effective.Kernel.SeriesYAMLURL, mirror: "kernel-series.yaml"},
+		{name: "sru-cycle.yaml", prefix: effective.Kernel.SRUCycleURL, dataset: datasetSRU, mirror: "sru-cycle.yaml"},
+	}
+}
+
+// getProvenance reports the last fetch and cache age of every upstream source
+func (ws *WebService) getProvenance(now time.Time) []Provenance {
+	datasets := make(map[string]DatasetStatus)
+	for _, status := range ws.getDatasets() {
+		datasets[status.Name] = status
+	}
+	mirrors := make(map[string]utils.MirrorStatus)
+	for _, status := range utils.MirrorStatuses() {
+		mirrors[status.Name] = status
+	}
+
+	var entries []Provenance
+	for _, source := range provenanceSources(ws.config) {
+		entry := Provenance{Source: source.name, URL: source.prefix}
+		prefix := source.prefix
+		if mirror, ok := mirrors[source.mirror]; ok {
+			// A mirror may have served the data, so look up the fetch of that URL
+			if mirror.URL != "" {
+				prefix = mirror.URL
+			}
+			if mirror.Error == "" && source.dataset == "" {
+				loadedAt := mirror.FetchedAt
+				entry.LoadedAt = &loadedAt
+			}
+			entry.Error = mirror.Error
+		}
+		if record, ok := utils.LastFetch(prefix); ok {
+			fetchedAt := record.FetchedAt
+			entry.URL = record.URL
+			entry.FetchedAt = &fetchedAt
+			entry.HTTPStatus = record.StatusCode
+			if entry.Error == "" {
+				entry.Error = record.Error
+			}
+		}
+		if status, ok := datasets[source.dataset]; ok {
+			entry.LoadedAt = status.LoadedAt
+			if status.Error != "" {
+				entry.Error = status.Error
+			}
+		}
+		if entry.LoadedAt != nil {
+			age := int64(now.Sub(*entry.LoadedAt) / time.Second)
+			entry.CacheAgeSeconds = &age
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// provenanceHandler reports where the data of every upstream source came from (/api/provenance)
+func (ws *WebService) provenanceHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	response := map[string]interface{}{
+		"sources":     ws.getProvenance(time.Now()),
+		"server_time": time.Now().UTC(),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
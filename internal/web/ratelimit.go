@@ -2,8 +2,14 @@ package web
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/rediscache"
 )
 
 // RateLimiter implements a simple rate limiter
@@ -12,6 +18,11 @@ type RateLimiter struct {
 	mu       sync.RWMutex
 	rate     int // requests per minute
 	enabled  bool
+
+	// shared keeps a token bucket per client in Redis, so the limit holds across replicas and
+	// restarts; the in-memory counts are only used while it is unavailable
+	shared *rediscache.Cache
+	burst  int
 }
 
 type visitor struct {
@@ -34,6 +45,15 @@ func NewRateLimiter(requestsPerMinute int, enabled bool) *RateLimiter {
 	return rl
 }
 
+// NewDistributedRateLimiter creates a rate limiter sharing its token buckets through Redis,
+// falling back to the in-memory counts of this instance while Redis is unavailable
+func NewDistributedRateLimiter(requestsPerMinute, burst int, shared *rediscache.Cache) *RateLimiter {
+	rl := NewRateLimiter(requestsPerMinute, true)
+	rl.shared = shared
+	rl.burst = burst
+	return rl
+}
+
 // Middleware returns a middleware function for rate limiting
 func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -42,9 +62,21 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
-		ip := getClientIP(r)
-		if !rl.allow(ip) {
-			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+		client := clientIdentity(r)
+		if rl.shared != nil {
+			allowed, wait, err := rl.shared.TakeToken(client, rl.burst, rl.rate)
+			if err == nil {
+				if !allowed {
+					w.Header().Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+					rateLimitExceeded(w, r)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		if !rl.allow(client) {
+			rateLimitExceeded(w, r)
 			return
 		}
 
@@ -95,6 +127,24 @@ func (rl *RateLimiter) cleanupVisitors() {
 	}
 }
 
+// rateLimitExceeded answers a denied request, as a problem on the API
+func rateLimitExceeded(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/api") {
+		problems.Write(w, http.StatusTooManyRequests, problems.RateLimited, "Rate limit exceeded")
+		return
+	}
+	http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+}
+
+// clientIdentity returns the key requests are counted under: the logged in user when there is
+// one, so users behind a shared proxy are limited separately, else the client IP
+func clientIdentity(r *http.Request) string {
+	if session, ok := auth.SessionFromContext(r.Context()); ok && session.Subject != "" {
+		return "user:" + session.Subject
+	}
+	return "ip:" + getClientIP(r)
+}
+
 // getClientIP gets the client IP address from the request
 func getClientIP(r *http.Request) string {
 	// Check X-Forwarded-For header
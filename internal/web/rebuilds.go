@@ -0,0 +1,170 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"nvidia_driver_monitor/internal/i18n"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/lrmreport"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/pkg/debversion"
+)
+
+// RebuildDriver is a driver the L-R-M of a kernel was built against in an earlier cycle,
+// with the newer version the cycle ships
+type RebuildDriver struct {
+	Package string `json:"package"`
+	Branch  string `json:"branch"`
+	Built   string `json:"built"`  // Version in the .dsc of the latest L-R-M
+	Target  string `json:"target"` // Version released to the series in the cycle
+	Pocket  string `json:"pocket"` // "proposed" or "updates", where Target is published
+}
+
+// RebuildKernel is a kernel whose L-R-M packages need a rebuild
+type RebuildKernel struct {
+	Source     string          `json:"source"`
+	Series     string          `json:"series"`
+	Codename   string          `json:"codename"`
+	Routing    string          `json:"routing"`
+	LRMVersion string          `json:"lrm_version"`
+	Packages   []string        `json:"packages"` // L-R-M packages to rebuild
+	Drivers    []RebuildDriver `json:"drivers"`
+}
+
+// RebuildList is the list of L-R-M rebuilds handed to the kernel team for an SRU cycle
+type RebuildList struct {
+	Cycle       string          `json:"cycle,omitempty"` // Empty when no upcoming cycle is known
+	ReleaseDate string          `json:"release_date,omitempty"`
+	Rebuilds    []RebuildKernel `json:"rebuilds"`
+	Packages    int             `json:"packages"` // L-R-M packages to rebuild, across kernels
+	DataUpdated time.Time       `json:"data_updated"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// rebuildTarget returns the version of a driver the cycle releases to a series and its pocket:
+// the version in proposed, released with the cycle, or else the published one
+func rebuildTarget(index *packageIndex, driver lrm.NvidiaDriverStatus, codename string) (string, string) {
+	published := driver.DKMSVersion
+	row, ok := index.row(driver.DriverName, codename)
+	if ok && published == "" && isArchiveVersion(row.UpdatesSecurity) {
+		published = row.UpdatesSecurity
+	}
+	if ok && isArchiveVersion(row.Proposed) && (published == "" || debversion.OlderThan(published, row.Proposed)) {
+		return row.Proposed, "proposed"
+	}
+	return published, "updates"
+}
+
+// buildLRMRebuilds lists the supported kernels with L-R-M built against a driver version the
+// cycle replaces, grouped by series (newest first) and kernel source
+func buildLRMRebuilds(kernels []lrm.KernelLRMResult, index *packageIndex) []RebuildKernel {
+	rebuilds := []RebuildKernel{}
+	for _, kernel := range kernels {
+		if !kernel.Supported || !kernel.HasLRM {
+			continue
+		}
+		rebuild := RebuildKernel{Source: kernel.Source, Series: kernel.Series, Codename: kernel.Codename, Routing: kernel.Routing, LRMVersion: kernel.LatestLRMVersion, Packages: kernel.LRMPackages}
+		for _, driver := range kernel.NvidiaDriverStatuses {
+			target, pocket := rebuildTarget(index, driver, kernel.Codename)
+			if target == "" || driver.DSCVersion == "" || !debversion.OlderThan(driver.DSCVersion, target) {
+				continue
+			}
+			rebuild.Drivers = append(rebuild.Drivers, RebuildDriver{
+				Package: driver.DriverName,
+				Branch:  branchFromPackage(driver.DriverName),
+				Built:   driver.DSCVersion,
+				Target:  target,
+				Pocket:  pocket,
+			})
+		}
+		if len(rebuild.Drivers) > 0 {
+			rebuilds = append(rebuilds, rebuild)
+		}
+	}
+	sort.Slice(rebuilds, func(i, j int) bool {
+		if rebuilds[i].Series != rebuilds[j].Series {
+			return rebuilds[i].Series > rebuilds[j].Series
+		}
+		return rebuilds[i].Source < rebuilds[j].Source
+	})
+	return rebuilds
+}
+
+// newLRMRebuilds builds the rebuild list of the next SRU cycle from the L-R-M data
+func (ws *WebService) newLRMRebuilds(data *lrm.LRMVerifierData, now time.Time) *RebuildList {
+	index, _, _ := ws.getPackageIndex()
+	result := &RebuildList{Rebuilds: buildLRMRebuilds(data.KernelResults, index), DataUpdated: data.LastUpdated, GeneratedAt: now}
+	if sruCycles := ws.getUpstreamData().sruCycles; sruCycles != nil {
+		// The next cycle that is not complete, however far its release
+		if cycle := lrmreport.DueCycle(sruCycles.Cycles, 366, now); cycle != nil {
+			result.Cycle, result.ReleaseDate = cycle.Name, cycle.ReleaseDate
+		}
+	}
+	for _, rebuild := range result.Rebuilds {
+		result.Packages += len(rebuild.Packages)
+	}
+	return result
+}
+
+// currentLRMRebuilds returns the rebuild list of the next cycle, nil while the L-R-M data loads
+func (ws *WebService) currentLRMRebuilds() *RebuildList {
+	data, err := lrm.GetCachedLRMData()
+	if err != nil || !data.IsInitialized {
+		return nil
+	}
+	return ws.newLRMRebuilds(data, time.Now())
+}
+
+// lrmRebuildsHandler serves the L-R-M rebuilds needed in the next SRU cycle (/api/v1/lrm/rebuilds)
+func (ws *WebService) lrmRebuildsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	rebuilds := ws.currentLRMRebuilds()
+	if rebuilds == nil {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "L-R-M data is not loaded yet")
+		return
+	}
+	if err := json.NewEncoder(w).Encode(rebuilds); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// lrmRebuildsPageHandler renders the L-R-M rebuilds needed in the next SRU cycle (/lrm/rebuilds)
+func (ws *WebService) lrmRebuildsPageHandler(w http.ResponseWriter, r *http.Request) {
+	locale := requestLocale(w, r, ws.config)
+	rebuilds := ws.currentLRMRebuilds()
+	if rebuilds == nil {
+		http.Error(w, i18n.T(locale, "error.initializing"), http.StatusServiceUnavailable)
+		return
+	}
+	ws.writeLRMRebuildsPage(w, r, locale, rebuilds)
+}
+
+// writeLRMRebuildsPage renders a rebuild list
+func (ws *WebService) writeLRMRebuildsPage(w http.ResponseWriter, r *http.Request, locale string, rebuilds *RebuildList) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	templateFile := filepath.Join(ws.templatePath, "lrm_rebuilds.html")
+	tmpl, err := template.New("lrm_rebuilds.html").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, requestTimezone(w, r, ws.config))).ParseFiles(templateFile)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Template parsing error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := struct {
+		*RebuildList
+		CDN map[string]string
+	}{
+		RebuildList: rebuilds,
+		CDN:         GetCDNResources(ws.config),
+	}
+	renderPage(w, tmpl, templateData)
+}
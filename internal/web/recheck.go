@@ -0,0 +1,95 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// recheckInterval is the shortest time between two forced rechecks of the same package
+const recheckInterval = time.Minute
+
+// isSupportedPackage reports whether a package is one of the supported releases
+func (ws *WebService) isSupportedPackage(packageName string) bool {
+	for _, release := range ws.getUpstreamData().supportedReleases {
+		if release.PackageName() == packageName {
+			return true
+		}
+	}
+	return false
+}
+
+// reserveRecheck records a recheck of a package at now, or returns how long to wait when the
+// package was rechecked less than recheckInterval ago
+func (ws *WebService) reserveRecheck(packageName string, now time.Time) (time.Duration, bool) {
+	ws.recheckMux.Lock()
+	defer ws.recheckMux.Unlock()
+
+	if last, ok := ws.rechecks[packageName]; ok {
+		if wait := last.Add(recheckInterval).Sub(now); wait > 0 {
+			return wait, false
+		}
+	}
+	if ws.rechecks == nil {
+		ws.rechecks = make(map[string]time.Time)
+	}
+	ws.rechecks[packageName] = now
+	return 0, true
+}
+
+// recheckHandler re-fetches the Launchpad data of one package, bypassing the lookup cache,
+// and regenerates it (POST /api/recheck?package=...)
+func (ws *WebService) recheckHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	packageName := r.URL.Query().Get("package")
+	if packageName == "" {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Package name is required")
+		return
+	}
+
+	if _, _, isInitialized := ws.getCachedPackages(); !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	if !ws.isSupportedPackage(packageName) {
+		problems.Write(w, http.StatusNotFound, problems.PackageNotFound, "Package not found")
+		return
+	}
+
+	if wait, ok := ws.reserveRecheck(packageName, time.Now()); !ok {
+		seconds := int(wait.Round(time.Second).Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		problems.Write(w, http.StatusTooManyRequests, problems.RateLimited, fmt.Sprintf("%s was rechecked recently, try again in %ds", packageName, seconds))
+		return
+	}
+
+	log.Printf("Forced recheck of %s", packageName)
+	if err := packages.RefreshSourceVersions(ws.config, packageName); err != nil {
+		log.Printf("Recheck failed for %s: %v", packageName, err)
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(newPackageError(packageName, err))
+		return
+	}
+
+	packageData, err := ws.retryPackage(packageName)
+	if err != nil {
+		log.Printf("Recheck failed for %s: %v", packageName, err)
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(newPackageError(packageName, err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(packageData)
+}
@@ -0,0 +1,196 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// Use cases a driver can be recommended for
+const (
+	useCaseDesktop = "desktop" // Graphics workstations and laptops
+	useCaseServer  = "server"  // Datacenter GPUs, on the server branches
+	useCaseCUDA    = "cuda"    // Headless compute, on the server branches
+)
+
+// openModulesMinBranch is the first branch NVIDIA installs with the open kernel modules by default
+const openModulesMinBranch = 560
+
+// Recommendation is the driver to install in a series for a use case, and why
+type Recommendation struct {
+	Series        string   `json:"series"`
+	UseCase       string   `json:"use_case"`
+	Package       string   `json:"package"`        // Binary package to install, e.g. "nvidia-driver-570-open"
+	SourcePackage string   `json:"source_package"` // e.g. "nvidia-graphics-drivers-570"
+	Branch        string   `json:"branch"`
+	Version       string   `json:"version"` // Published version in updates/security
+	Open          bool     `json:"open"`    // Whether the package uses the open kernel modules
+	Justification []string `json:"justification"`
+}
+
+// recommendCandidate is a branch published in the series for the use case
+type recommendCandidate struct {
+	pkg     *PackageData
+	row     SeriesData
+	branch  string
+	major   int
+	lts     bool
+	seeded  bool
+	eolSoon string // Set to the EOL date when it falls within the warning period
+}
+
+// recommendDriver picks the driver branch to install in a series for a use case: branches past
+// their EOL are skipped and those nearing it only picked as a last resort, then desktops get the
+// default seeded branch, servers the newest LTS branch and CUDA the newest branch. modules is
+// "open", "proprietary" or empty for NVIDIA's default of the branch. It returns nil when no
+// branch is published in the series for the use case.
+func recommendDriver(index *packageIndex, series, useCase, modules, seededBranch string,
+	branchTypes drivers.AllBranches, eolCfg *config.CertificationConfig, now time.Time) *Recommendation {
+	var candidates []recommendCandidate
+	var skipped []string
+	for _, pkg := range index.packages {
+		if !strings.HasPrefix(pkg.PackageName, "nvidia-graphics-drivers-") {
+			continue
+		}
+		branch := branchFromPackage(pkg.PackageName)
+		if strings.HasSuffix(branch, "-server") != (useCase != useCaseDesktop) {
+			continue
+		}
+		row, ok := index.row(pkg.PackageName, series)
+		if !ok || row.Removed != "" || !isArchiveVersion(row.UpdatesSecurity) {
+			continue
+		}
+		number, _, _ := strings.Cut(branch, "-")
+		major, err := strconv.Atoi(number)
+		if err != nil {
+			continue
+		}
+		candidate := recommendCandidate{
+			pkg: pkg, row: row, branch: branch, major: major,
+			lts:    strings.Contains(strings.ToLower(branchTypes[number].Type), "lts"),
+			seeded: branch == seededBranch,
+		}
+		if eol, ok := branchEOL(eolCfg, branch); ok {
+			if !now.Before(eol) {
+				skipped = append(skipped, fmt.Sprintf("%s reached its EOL on %s", branch, eol.Format("2006-01-02")))
+				continue
+			}
+			if now.Add(eolCfg.GetEOLWarning()).After(eol) {
+				candidate.eolSoon = eol.Format("2006-01-02")
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.eolSoon == "") != (b.eolSoon == "") {
+			return a.eolSoon == ""
+		}
+		if useCase == useCaseDesktop && a.seeded != b.seeded {
+			return a.seeded
+		}
+		if useCase != useCaseCUDA && a.lts != b.lts {
+			return a.lts
+		}
+		return a.major > b.major
+	})
+	chosen := candidates[0]
+
+	var justification []string
+	switch {
+	case useCase == useCaseDesktop && chosen.seeded:
+		justification = append(justification, fmt.Sprintf("%s is the default branch seeded in %s, the one installed by default", chosen.branch, series))
+	case useCase == useCaseCUDA:
+		justification = append(justification, fmt.Sprintf("%s is the newest server branch published in %s, supporting the newest CUDA releases", chosen.branch, series))
+	case chosen.lts:
+		justification = append(justification, fmt.Sprintf("%s is the newest long-term support branch published in %s", chosen.branch, series))
+	default:
+		justification = append(justification, fmt.Sprintf("%s is the newest branch published in %s", chosen.branch, series))
+	}
+	if chosen.eolSoon != "" {
+		justification = append(justification, fmt.Sprintf("%s reaches its EOL on %s, but no other branch is available", chosen.branch, chosen.eolSoon))
+	}
+	justification = append(justification, skipped...)
+
+	open := modules == "open" || (modules == "" && chosen.major >= openModulesMinBranch)
+	switch {
+	case modules != "":
+		justification = append(justification, fmt.Sprintf("%s kernel modules as requested", modules))
+	case open:
+		justification = append(justification, fmt.Sprintf("open kernel modules, the default from %d onward; they require a Turing or newer GPU", openModulesMinBranch))
+	default:
+		justification = append(justification, fmt.Sprintf("proprietary kernel modules, the default before %d", openModulesMinBranch))
+	}
+	if chosen.row.UpdatesColor == "danger" {
+		justification = append(justification, fmt.Sprintf("the published version is behind upstream %s; an update is pending", chosen.row.UpstreamVersion))
+	}
+
+	binary := "nvidia-driver-" + chosen.branch
+	if useCase == useCaseCUDA {
+		binary = "nvidia-headless-" + chosen.branch
+		justification = append(justification, "headless packages leave out the display stack, install the CUDA toolkit on top")
+	}
+	if open {
+		binary += "-open"
+	}
+	return &Recommendation{
+		Series:        series,
+		UseCase:       useCase,
+		Package:       binary,
+		SourcePackage: chosen.pkg.PackageName,
+		Branch:        chosen.branch,
+		Version:       chosen.row.UpdatesSecurity,
+		Open:          open,
+		Justification: justification,
+	}
+}
+
+// recommendationHandler returns the driver to install today in a series for a use case
+// (/api/v1/recommendation?series={codename}&use_case=desktop|server|cuda&modules=open|proprietary)
+func (ws *WebService) recommendationHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	series, useCase, modules := query.Get("series"), query.Get("use_case"), query.Get("modules")
+	if useCase == "" {
+		useCase = useCaseDesktop
+	}
+	switch {
+	case series == "":
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "series is required")
+		return
+	case useCase != useCaseDesktop && useCase != useCaseServer && useCase != useCaseCUDA:
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "use_case must be desktop, server or cuda")
+		return
+	case modules != "" && modules != "open" && modules != "proprietary":
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "modules must be open or proprietary")
+		return
+	}
+
+	index, _, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	recommendation := recommendDriver(index, series, useCase, modules, ws.defaultBranches()[series],
+		ws.getUpstreamData().allBranches, &ws.config.Certification, time.Now())
+	if recommendation == nil {
+		problems.Write(w, http.StatusNotFound, problems.NotFound, "No driver branch is published in this series for this use case")
+		return
+	}
+	if err := json.NewEncoder(w).Encode(recommendation); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
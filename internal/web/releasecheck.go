@@ -0,0 +1,115 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/archive"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/packages"
+)
+
+// Checks of the scheduled health check of the archive Release files
+const (
+	checkReleaseUnavailable     = "release-file-unavailable"
+	checkReleaseSignature       = "release-signature-invalid"
+	checkReleaseStale           = "release-file-stale"
+	checkReleaseExpired         = "release-file-expired"
+	checkReleaseComponentAbsent = "release-component-missing"
+)
+
+// checkReleaseFile reports the problems of the InRelease file of a suite. Only pocket suites
+// are expected to be republished regularly: the release suite of a stable series is frozen.
+func checkReleaseFile(series, suite string, data []byte, cfg *config.ReleaseCheckConfig, now time.Time) []DataIssue {
+	issue := func(check, format string, args ...interface{}) DataIssue {
+		return DataIssue{Check: check, Series: series, Message: suite + ": " + fmt.Sprintf(format, args...)}
+	}
+
+	release, err := archive.ParseRelease(data)
+	if err != nil {
+		return []DataIssue{issue(checkReleaseUnavailable, "InRelease could not be parsed: %v", err)}
+	}
+
+	var issues []DataIssue
+	if !release.Signed {
+		issues = append(issues, issue(checkReleaseSignature, "InRelease is not signed"))
+	} else if cfg.Keyring != "" {
+		if err := archive.VerifySignature(data, cfg.Keyring); err != nil {
+			issues = append(issues, issue(checkReleaseSignature, "%v", err))
+		}
+	}
+	if !release.ValidUntil.IsZero() && now.After(release.ValidUntil) {
+		issues = append(issues, issue(checkReleaseExpired, "Release file expired on %s", release.ValidUntil.UTC().Format("2006-01-02 15:04 UTC")))
+	}
+	if suite != series {
+		if age := now.Sub(release.Date); age > cfg.GetMaxAge() {
+			issues = append(issues, issue(checkReleaseStale, "Release file is dated %s, %v old", release.Date.UTC().Format("2006-01-02 15:04 UTC"), age.Round(time.Hour)))
+		}
+	}
+	for _, component := range cfg.GetComponents() {
+		if !contains(release.Components, component) {
+			issues = append(issues, issue(checkReleaseComponentAbsent, "component %s is not listed (%s)", component, strings.Join(release.Components, " ")))
+		}
+	}
+	return issues
+}
+
+// runReleaseCheck reads the InRelease file of each shown suite and keeps the problems found for
+// the diagnostics, so that archive infrastructure failures are not mistaken for the absence of
+// new uploads. It returns false when there is no data to check yet.
+func (ws *WebService) runReleaseCheck(now time.Time) bool {
+	pkgs, _, isInitialized := ws.getCachedPackages()
+	if !isInitialized {
+		return false
+	}
+	checkCfg := &ws.config.ReleaseCheck
+
+	log.Printf("Checking the archive Release files...")
+	var issues []DataIssue
+	for _, suite := range archiveSuites(pkgs, packages.PublishedPockets()) {
+		series, _, _ := strings.Cut(suite, "-")
+		data, err := archive.FetchRelease(archive.ReleaseURL(checkCfg.GetMirrorURL(), suite))
+		if err != nil {
+			issues = append(issues, DataIssue{Check: checkReleaseUnavailable, Series: series, Message: err.Error()})
+			continue
+		}
+		issues = append(issues, checkReleaseFile(series, suite, data, checkCfg, now)...)
+	}
+	log.Printf("Release file check found %d problems", len(issues))
+
+	ws.cacheMux.Lock()
+	ws.releaseIssues = issues
+	ws.releaseCheckedAt = now
+	ws.cacheMux.Unlock()
+	return true
+}
+
+// getReleaseCheckedAt returns when the Release file check last ran; zero if it never did
+func (ws *WebService) getReleaseCheckedAt() time.Time {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	return ws.releaseCheckedAt
+}
+
+// releaseCheckLoop runs the Release file check once the first data is loaded, then at the
+// configured interval
+func (ws *WebService) releaseCheckLoop() {
+	timer := time.NewTimer(archiveCheckRetry)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			next := ws.config.ReleaseCheck.GetInterval()
+			if !ws.runReleaseCheck(time.Now()) {
+				next = archiveCheckRetry
+			}
+			timer.Reset(next)
+		case <-ws.stopChan:
+			log.Printf("Stopping Release file check loop...")
+			return
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package web
+
+import (
+	"fmt"
+	"time"
+
+	"nvidia_driver_monitor/internal/alerts"
+)
+
+// removalAnnounceWindow limits notices to recent removals, so a restart does not announce
+// every historical deletion (e.g. 390 dropped from newer series) again
+const removalAnnounceWindow = 7 * 24 * time.Hour
+
+// removalAlertName is the notice name for a package removed from a series
+func removalAlertName(packageName, series string) string {
+	return fmt.Sprintf("removed-%s-%s", packageName, series)
+}
+
+// announceRemovals sends a one-time informational notice for each recent removal of a
+// package from a series, so documentation listing the driver can be updated
+func (ws *WebService) announceRemovals(pkgs []*PackageData, now time.Time) {
+	ws.removalsMux.Lock()
+	defer ws.removalsMux.Unlock()
+
+	if ws.announcedRemovals == nil {
+		ws.announcedRemovals = make(map[string]bool)
+	}
+	for _, pkg := range pkgs {
+		for _, row := range pkg.Series {
+			if row.Removed == "" {
+				continue
+			}
+			name := removalAlertName(pkg.PackageName, row.Series)
+			if ws.announcedRemovals[name] {
+				continue
+			}
+			removedOn, err := time.Parse("removed on 2006-01-02", row.Removed)
+			if err != nil || now.Sub(removedOn) > removalAnnounceWindow {
+				// Undated or old removals are shown on the dashboard only
+				ws.announcedRemovals[name] = true
+				continue
+			}
+			message := fmt.Sprintf("%s was %s from %s (%s)", pkg.PackageName, row.Removed, row.Series, row.RemovalNote)
+			// Notices held back by a maintenance window are retried on the next refresh
+			if alerts.Notify(name, alerts.SeverityInfo, message) {
+				ws.announcedRemovals[name] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+)
+
+// HTML escaping policy: pages are only produced by html/template, which escapes every value
+// for the context it lands in (element text, attribute, URL or script). Handlers pass raw data,
+// upstream-controlled strings included, and never pre-escape it, which would show entities
+// twice, nor convert it to template.HTML, JS or URL, which would skip the escaping. The only
+// value trusted as is comes from trustedQuery.
+
+// renderPage executes a page template into a buffer first, so a failure returns a clean error
+// instead of a half-written page with the error text appended
+func renderPage(w http.ResponseWriter, tmpl *template.Template, data interface{}) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		http.Error(w, fmt.Sprintf("Template execution error: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// trustedQuery returns query parameters to append to a URL in a template, e.g. "&view=gaming".
+// The values are percent-encoded here, which is what makes them safe to mark as a URL.
+func trustedQuery(values url.Values) template.URL {
+	if len(values) == 0 {
+		return ""
+	}
+	return template.URL("&" + values.Encode())
+}
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/sru"
+)
+
+// hostilePayloads break out of element text, double- and single-quoted attributes and script
+// strings when rendered unescaped
+var hostilePayloads = []string{
+	`<script>alert("xss")</script>`,
+	`"><img src=x onerror=alert(1)>`,
+	`' onmouseover='alert(1)`,
+	`</script><script>alert(2)</script>`,
+}
+
+// hostileChangelog is a Launchpad changelog whose every free-text field carries a payload
+const hostileChangelog = `nvidia-graphics-drivers-570 (570.195.03-0ubuntu1) noble<img src=x onerror=alert(1)>; urgency=<script>alert("xss")</script>
+
+  * Fix "><img src=x onerror=alert(1)> (LP: #2012345)
+
+ -- ' onmouseover='alert(1) <evil@example.com>  </script><script>alert(2)</script>
+`
+
+// hostileSRUCycles is an sru-cycle.yaml whose invalid entry is echoed in the diagnostics
+const hostileSRUCycles = `'<script>alert("xss")</script>':
+  release-date: '"><img src=x onerror=alert(1)>'
+'2026.06.22':
+  release-date: '2026-07-13'
+`
+
+// hostileWebService caches packages built from hostile upstream data, as after a refresh
+func hostileWebService(t *testing.T) *WebService {
+	entry, err := packages.ParseLatestChangelogEntry(hostileChangelog)
+	if err != nil {
+		t.Fatalf("ParseLatestChangelogEntry() returned error: %v", err)
+	}
+	cycles, err := sru.ParseSRUCycles([]byte(hostileSRUCycles))
+	if err != nil || len(cycles.Warnings) == 0 {
+		t.Fatalf("ParseSRUCycles() = %+v, %v, expected the hostile entry skipped with a warning", cycles, err)
+	}
+
+	ws := &WebService{cache: &CachedData{IsInitialized: true}, templatePath: "../../templates", sruCycles: cycles}
+	ws.cache.setPackages([]*PackageData{
+		{
+			PackageName: "nvidia-graphics-drivers-570",
+			Series: []SeriesData{{
+				Series:          "noble",
+				UpdatesSecurity: "570.195.03-0ubuntu1",
+				Proposed:        hostilePayloads[1],
+				UpstreamVersion: hostilePayloads[0],
+				ReleaseDate:     hostilePayloads[2],
+				SRUCycle:        hostilePayloads[3],
+				UpdatesColor:    "danger",
+				QueueStatus:     hostilePayloads[0],
+				QueueVersion:    hostilePayloads[2],
+				Note:            hostilePayloads[1],
+				NoteUpdated:     hostilePayloads[2],
+				Acknowledged:    hostilePayloads[3],
+			}},
+			Changelogs: map[string]*packages.ChangelogEntry{"570.195.03-0ubuntu1": entry},
+		},
+	})
+	ws.cache.PackageErrors = []*PackageError{{PackageName: "nvidia-graphics-drivers-535", Error: hostilePayloads[0]}}
+	ws.cache.Issues = []DataIssue{{Check: checkProposedOlder, Package: "nvidia-graphics-drivers-570", Series: "noble", Message: hostilePayloads[1]}}
+	return ws
+}
+
+func TestPagesEscapeUpstreamStrings(t *testing.T) {
+	ws := hostileWebService(t)
+	pages := []struct {
+		path    string
+		handler http.HandlerFunc
+	}{
+		{"/", ws.indexHandler},
+		{"/package?name=nvidia-graphics-drivers-570", ws.packageHandler},
+		{"/branch/570", ws.branchHandler},
+		{"/diagnostics", ws.diagnosticsPageHandler},
+	}
+	for _, page := range pages {
+		w := httptest.NewRecorder()
+		page.handler(w, httptest.NewRequest("GET", page.path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s = %d %s, expected 200", page.path, w.Code, w.Body.String())
+			continue
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, "&lt;script&gt;alert") && !strings.Contains(body, "&lt;img src=x") {
+			t.Errorf("GET %s does not show the hostile strings escaped, expected them rendered as text", page.path)
+		}
+		for _, payload := range hostilePayloads {
+			if strings.Contains(body, payload) {
+				t.Errorf("GET %s rendered %q unescaped", page.path, payload)
+			}
+		}
+		for _, fragment := range []string{"<img src=x", "<script>alert", "onmouseover='"} {
+			if strings.Contains(body, fragment) {
+				t.Errorf("GET %s contains the injected markup %q", page.path, fragment)
+			}
+		}
+	}
+}
+
+func TestRenderPageDoesNotServeHalfPages(t *testing.T) {
+	ws := &WebService{cache: &CachedData{IsInitialized: true}, templatePath: t.TempDir()}
+	w := httptest.NewRecorder()
+	ws.diagnosticsPageHandler(w, httptest.NewRequest("GET", "/diagnostics", nil))
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("missing template = %d %q, expected a plain text 500", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	if got := trustedQuery(url.Values{"view": {`gaming"><script>`}}); got != "&view=gaming%22%3E%3Cscript%3E" {
+		t.Errorf("trustedQuery() = %q, expected the values percent-encoded", got)
+	}
+	if got := trustedQuery(nil); got != "" {
+		t.Errorf("trustedQuery(nil) = %q, expected nothing", got)
+	}
+}
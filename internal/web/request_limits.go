@@ -4,6 +4,9 @@ import (
 	"context"
 	"net/http"
 	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/problems"
 )
 
 // RequestLimitsMiddleware enforces request body size limits and timeouts
@@ -12,6 +15,12 @@ func RequestLimitsMiddleware(maxBodySize int64, requestTimeout time.Duration) fu
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Limit request body size to prevent large request DoS attacks
 			if maxBodySize > 0 {
+				// A body announced as too large is refused before any handler reads it
+				if r.ContentLength > maxBodySize {
+					w.Header().Set("Content-Type", "application/json")
+					problems.Write(w, http.StatusRequestEntityTooLarge, problems.ValidationFailed, "Request body too large")
+					return
+				}
 				r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
 			}
 
@@ -26,3 +35,24 @@ func RequestLimitsMiddleware(maxBodySize int64, requestTimeout time.Duration) fu
 		})
 	}
 }
+
+// newServer builds the server of a listener with the configured timeouts and header limit;
+// the HTTP and HTTPS listeners are both built here so they apply the same limits
+func newServer(addr string, handler http.Handler, limits *config.RequestLimitConfig) *http.Server {
+	return &http.Server{
+		Addr:           addr,
+		Handler:        handler,
+		ReadTimeout:    limits.GetReadTimeout(),
+		WriteTimeout:   limits.GetWriteTimeout(),
+		IdleTimeout:    limits.GetIdleTimeout(),
+		MaxHeaderBytes: limits.GetMaxHeaderBytes(),
+	}
+}
+
+// requestLimits returns the configured request limits, or the defaults without a configuration
+func (ws *WebService) requestLimits() *config.RequestLimitConfig {
+	if ws.config == nil {
+		return &config.RequestLimitConfig{}
+	}
+	return &ws.config.RequestLimit
+}
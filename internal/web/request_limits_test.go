@@ -2,12 +2,16 @@ package web
 
 import (
 	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"nvidia_driver_monitor/internal/config"
 )
 
 func TestRequestLimitsMiddleware(t *testing.T) {
@@ -203,3 +207,86 @@ func TestRequestLimitsIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestServerAppliesRequestLimits(t *testing.T) {
+	limits := &config.RequestLimitConfig{ReadTimeout: "200ms", MaxBodySize: 16, MaxHeaderBytes: 1024}
+	handler := RequestLimitsMiddleware(limits.GetMaxBodySize(), limits.GetRequestTimeout())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tooLarge *http.MaxBytesError
+		if _, err := io.ReadAll(r.Body); errors.As(err, &tooLarge) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newServer(listener.Addr().String(), handler, limits)
+	go server.Serve(listener)
+	defer server.Close()
+	url := "http://" + listener.Addr().String() + "/"
+
+	if server.ReadTimeout != 200*time.Millisecond || server.WriteTimeout != 15*time.Second || server.IdleTimeout != 60*time.Second || server.MaxHeaderBytes != 1024 {
+		t.Errorf("newServer() applied read=%v write=%v idle=%v max_header_bytes=%d, expected the configured limits and the defaults",
+			server.ReadTimeout, server.WriteTimeout, server.IdleTimeout, server.MaxHeaderBytes)
+	}
+
+	post := func(body io.Reader) int {
+		resp, err := http.Post(url, "text/plain", body)
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := post(strings.NewReader("small")); code != http.StatusOK {
+		t.Errorf("a body within the limit got %d, expected 200", code)
+	}
+	if code := post(strings.NewReader(strings.Repeat("x", 64))); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("a body announced larger than the limit got %d, expected 413", code)
+	}
+	// Without a Content-Length the body is sent chunked and cut by MaxBytesReader while read
+	if code := post(io.MultiReader(strings.NewReader(strings.Repeat("x", 64)))); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("a chunked body larger than the limit got %d, expected 413", code)
+	}
+
+	// net/http allows 4096 bytes of slack over MaxHeaderBytes
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("X-Padding", strings.Repeat("x", 8192))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("headers over the limit got %d, expected 431", resp.StatusCode)
+	}
+
+	// A client that stops sending its request is disconnected after the read timeout
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n"))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	started := time.Now()
+	_, err = io.ReadAll(conn)
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		t.Errorf("the server kept a stalled request open for %v, expected it to close it after the 200ms read timeout", time.Since(started))
+	}
+}
+
+func TestRequestLimitDefaults(t *testing.T) {
+	limits := (&WebService{}).requestLimits()
+	if limits.GetMaxBodySize() != 1048576 || limits.GetMaxHeaderBytes() != 1048576 {
+		t.Errorf("default limits = %d body bytes, %d header bytes, expected 1MB each", limits.GetMaxBodySize(), limits.GetMaxHeaderBytes())
+	}
+	server := newServer(":0", nil, limits)
+	if server.ReadTimeout != 15*time.Second || server.WriteTimeout != 15*time.Second || server.IdleTimeout != 60*time.Second {
+		t.Errorf("default server timeouts = read %v, write %v, idle %v", server.ReadTimeout, server.WriteTimeout, server.IdleTimeout)
+	}
+}
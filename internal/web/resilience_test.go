@@ -0,0 +1,182 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/faults"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/sru"
+)
+
+// The tests below run the refresh pipeline into injected faults, one failure mode at a time,
+// and check what the cache, /api/ready and the alerts make of it.
+
+// injectFault enables injection and makes a stage fail or hang for the rest of the test
+func injectFault(t *testing.T, stage, mode string) {
+	t.Helper()
+	faults.Enable(true)
+	t.Cleanup(func() {
+		faults.Enable(false)
+		alerts.Resolve(alertDashboardStale)
+	})
+	if err := faults.Inject(stage, mode); err != nil {
+		t.Fatalf("Inject(%s, %s): %v", stage, mode, err)
+	}
+}
+
+func TestFailingUpstreamStagesKeepPreviousData(t *testing.T) {
+	injectFault(t, faults.StageUDA, faults.ModeFail)
+	faults.Inject(faults.StageERD, faults.ModeFail)
+	faults.Inject(faults.StageSRU, faults.ModeFail)
+
+	previousUDA := []drivers.DriverEntry{{Version: "570.195.03"}}
+	previousCycles := &sru.SRUCycles{Cycles: []sru.SRUCycle{{Name: "s2026.10.05", ReleaseDate: "2026-10-26"}}}
+	ws := &WebService{config: config.DefaultConfig(), udaEntries: previousUDA, sruCycles: previousCycles}
+	ws.loadUDA([]string{"570"})
+	ws.loadERD()
+	ws.loadSRU()
+
+	if len(ws.udaEntries) != 1 || ws.sruCycles != previousCycles {
+		t.Errorf("failed loads replaced the previous data: uda %+v, sru %+v", ws.udaEntries, ws.sruCycles)
+	}
+	for _, status := range ws.getDatasets()[:3] {
+		if status.State != DatasetFailed || !strings.Contains(status.Error, faults.ErrInjected.Error()) {
+			t.Errorf("dataset %+v, expected failed with the injected error", status)
+		}
+	}
+
+	// Without previous cycles the estimated ones are used, so SRU dates still show
+	ws.sruCycles = nil
+	ws.loadSRU()
+	if ws.sruCycles == nil || len(ws.sruCycles.Cycles) == 0 {
+		t.Error("no fallback SRU cycles after a failed first load")
+	}
+}
+
+func TestLaunchpadOutageServesStaleDataUntilTheWatchdogFires(t *testing.T) {
+	injectFault(t, faults.StagePackages, faults.ModeFail)
+
+	lastGood := time.Now().Add(-time.Minute)
+	ws := &WebService{
+		config: config.DefaultConfig(),
+		cache:  &CachedData{IsInitialized: true, LastUpdated: lastGood},
+	}
+	ws.cache.setPackages([]*PackageData{{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1"}}}})
+	ws.generateAllPackages([]releases.SupportedRelease{{BranchName: "570", IsSupported: map[string]bool{"noble": true}}})
+
+	pkgs, lastUpdated, _ := ws.getCachedPackages()
+	if len(pkgs) != 1 || pkgs[0].StaleSince == nil || !pkgs[0].StaleSince.Equal(lastGood) {
+		t.Fatalf("cached packages = %+v, expected the last good data marked stale", pkgs)
+	}
+	if !lastUpdated.Equal(lastGood) {
+		t.Errorf("LastUpdated = %v, expected the last good refresh %v", lastUpdated, lastGood)
+	}
+	if status := ws.getDatasets()[len(ws.getDatasets())-1]; status.Name != datasetPackages || status.State != DatasetFailed {
+		t.Errorf("packages dataset = %+v, expected failed", status)
+	}
+
+	// Still ready within the stale limit; not ready with the alert fired past it
+	if ready, reasons := ws.checkFreshness(time.Now()); !ready {
+		t.Errorf("not ready right after the outage started: %v", reasons)
+	}
+	if ready, _ := ws.checkFreshness(lastGood.Add(4 * dataRefreshInterval)); ready || !alerts.IsFiring(alertDashboardStale) {
+		t.Errorf("ready = %v, alert firing = %v past the stale limit; expected not ready with the alert", ready, alerts.IsFiring(alertDashboardStale))
+	}
+}
+
+func TestLaunchpadDownAtStartupIsNotReady(t *testing.T) {
+	injectFault(t, faults.StagePackages, faults.ModeFail)
+
+	started := time.Now()
+	ws := &WebService{config: config.DefaultConfig(), cache: &CachedData{}, startedAt: started}
+	ws.generateAllPackages([]releases.SupportedRelease{{BranchName: "570", IsSupported: map[string]bool{"noble": true}}})
+
+	if _, _, isInitialized := ws.getCachedPackages(); !isInitialized || len(ws.cache.PackageErrors) != 1 {
+		t.Fatalf("expected the package errors to be served, got %+v", ws.cache.PackageErrors)
+	}
+	ready, reasons := ws.checkFreshness(started)
+	if ready || len(reasons) != 1 || !strings.Contains(reasons[0], "no package") {
+		t.Errorf("checkFreshness = %v, %v; expected not ready as no package was loaded", ready, reasons)
+	}
+	if alerts.IsFiring(alertDashboardStale) {
+		t.Error("alert fired before the stale limit")
+	}
+	if ws.checkFreshness(started.Add(4 * dataRefreshInterval)); !alerts.IsFiring(alertDashboardStale) {
+		t.Error("no alert once the outage outlasted the stale limit")
+	}
+}
+
+func TestHangingStageIsReportedAndAlerted(t *testing.T) {
+	injectFault(t, faults.StageERD, faults.ModeHang)
+
+	lastGood := time.Now()
+	ws := &WebService{config: config.DefaultConfig(), cache: &CachedData{IsInitialized: true, LastUpdated: lastGood}}
+	ws.setDatasetResult(datasetERD, nil)
+	done := make(chan struct{})
+	go func() {
+		ws.loadERD()
+		close(done)
+	}()
+	deadline := time.Now().Add(time.Second)
+	for active := faults.Active(); len(active) != 1 || active[0].Waiting != 1; active = faults.Active() {
+		if time.Now().After(deadline) {
+			t.Fatal("the ERD load did not reach the hang")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// A hung refresh never updates the cache, so the data ages past the limit
+	if ready, _ := ws.checkFreshness(lastGood.Add(4 * dataRefreshInterval)); ready || !alerts.IsFiring(alertDashboardStale) {
+		t.Error("a hung refresh was not detected as stale data")
+	}
+	w := httptest.NewRecorder()
+	ws.readyHandler(w, httptest.NewRequest("GET", "/api/ready", nil))
+	var response struct {
+		Faults []faults.Fault `json:"faults"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || len(response.Faults) != 1 || response.Faults[0].Stage != faults.StageERD {
+		t.Errorf("/api/ready faults = %+v (%v), expected the ERD hang", response.Faults, err)
+	}
+
+	faults.Clear(faults.StageERD)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the ERD load still hangs after the fault was cleared")
+	}
+	if status := ws.getDatasets()[1]; status.State != DatasetFailed || status.LoadedAt == nil {
+		t.Errorf("erd dataset = %+v, expected failed, keeping its last load", status)
+	}
+}
+
+func TestFaultsHandler(t *testing.T) {
+	faults.Enable(true)
+	defer faults.Enable(false)
+	ws := &WebService{}
+
+	w := httptest.NewRecorder()
+	ws.faultsHandler(w, httptest.NewRequest("POST", "/api/v1/testing/faults", strings.NewReader(`{"stage": "sru", "mode": "fail"}`)))
+	if w.Code != http.StatusCreated || !errors.Is(faults.Check(faults.StageSRU), faults.ErrInjected) {
+		t.Errorf("POST = %d %s, expected the SRU stage to fail", w.Code, w.Body)
+	}
+	w = httptest.NewRecorder()
+	ws.faultsHandler(w, httptest.NewRequest("POST", "/api/v1/testing/faults", strings.NewReader(`{"stage": "launchpad", "mode": "fail"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("POST of an unknown stage = %d, expected 400", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	ws.faultsHandler(w, httptest.NewRequest("DELETE", "/api/v1/testing/faults?stage=sru", nil))
+	if w.Code != http.StatusOK || len(faults.Active()) != 0 {
+		t.Errorf("DELETE = %d, faults left %+v", w.Code, faults.Active())
+	}
+}
@@ -0,0 +1,132 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"nvidia_driver_monitor/internal/rediscache"
+)
+
+// cachedHeaders are the response headers stored with a cached body
+var cachedHeaders = []string{
+	"Content-Type",
+	"Access-Control-Allow-Origin",
+	"Access-Control-Allow-Methods",
+	"Access-Control-Allow-Headers",
+}
+
+// responseCapture passes a response through while keeping a copy of its status and body
+type responseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *responseCapture) Write(data []byte) (int, error) {
+	c.body.Write(data)
+	return c.ResponseWriter.Write(data)
+}
+
+// encodeCachedResponse stores the cached headers of a response ahead of its body, as in HTTP
+func encodeCachedResponse(header http.Header, body []byte) []byte {
+	var buf bytes.Buffer
+	for _, name := range cachedHeaders {
+		if value := header.Get(name); value != "" {
+			fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+		}
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// decodeCachedResponse splits a cached response into its headers and body
+func decodeCachedResponse(data []byte) (http.Header, []byte, bool) {
+	if bytes.HasPrefix(data, []byte("\r\n")) {
+		return http.Header{}, data[2:], true // No headers
+	}
+	end := bytes.Index(data, []byte("\r\n\r\n"))
+	if end < 0 {
+		return nil, nil, false
+	}
+	header := http.Header{}
+	for _, line := range strings.Split(string(data[:end]), "\r\n") {
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, nil, false
+		}
+		header.Set(name, value)
+	}
+	return header, data[end+4:], true
+}
+
+// cachedResponse serves the GET requests of an endpoint from the Redis response cache. The key
+// holds the generation of the data the response is built from, so a refresh invalidates every
+// cached response; generation returns false while there is no data to cache. Only successful
+// responses are stored.
+func cachedResponse(cache *rediscache.Cache, endpoint string, generation func() (string, bool), next http.Handler) http.Handler {
+	if cache == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+		current, ok := generation()
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := cache.Key(endpoint, current, r.URL.Query())
+		if cached, ok := cache.Get(key); ok {
+			if header, body, ok := decodeCachedResponse(cached); ok {
+				for name, values := range header {
+					w.Header()[name] = values
+				}
+				w.Header().Set("X-Response-Cache", "hit")
+				w.Write(body)
+				return
+			}
+		}
+
+		w.Header().Set("X-Response-Cache", "miss")
+		capture := &responseCapture{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(capture, r)
+		if capture.status == http.StatusOK {
+			cache.Set(key, encodeCachedResponse(w.Header(), capture.body.Bytes()))
+		}
+	})
+}
+
+// packagesGeneration identifies the cached packages; it changes on every refresh and on edits
+// of the notes and acknowledgements shown in the rows
+func (ws *WebService) packagesGeneration() (string, bool) {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	if !ws.cache.IsInitialized {
+		return "", false
+	}
+	return fmt.Sprintf("%d.%d", ws.cache.LastUpdated.UnixNano(), ws.cache.generation), true
+}
+
+// lrmGeneration identifies the L-R-M data and the advisories matched against it
+func (h *APIHandler) lrmGeneration() (string, bool) {
+	data, err := h.getLRMData()
+	if err != nil || !data.IsInitialized {
+		return "", false
+	}
+	revision := 0
+	if h.advisoryStore != nil {
+		revision = h.advisoryStore.Revision()
+	}
+	return fmt.Sprintf("%d.%t.%d", data.LastUpdated.UnixNano(), data.Stale, revision), true
+}
@@ -0,0 +1,109 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/sbom"
+)
+
+// firmwareMinBranch is the first branch whose GSP firmware is packaged apart, as
+// nvidia-firmware-<branch>-<upstream version>
+const firmwareMinBranch = 525
+
+// sbomComponents lists the NVIDIA components published in updates/security of a series: each
+// driver source with its dkms and firmware binaries, and the L-R-M package of each supported
+// kernel that has one. Removed and unpublished branches are left out.
+func sbomComponents(index *packageIndex, kernels []lrm.KernelLRMResult, codename string) []sbom.Component {
+	var components []sbom.Component
+	for _, pkg := range index.packages {
+		if !strings.HasPrefix(pkg.PackageName, "nvidia-graphics-drivers-") {
+			continue
+		}
+		row, ok := index.row(pkg.PackageName, codename)
+		if !ok || row.Removed != "" || !isArchiveVersion(row.UpdatesSecurity) {
+			continue
+		}
+		branch := branchFromPackage(pkg.PackageName)
+		version := row.UpdatesSecurity
+		components = append(components,
+			sbom.Component{Name: pkg.PackageName, Version: version, Kind: sbom.KindDriver, Source: true, Branch: branch},
+			sbom.Component{Name: "nvidia-dkms-" + branch, Version: version, Kind: sbom.KindDKMS, Branch: branch})
+		major, _, _ := strings.Cut(branch, "-")
+		if n, err := strconv.Atoi(major); err == nil && n >= firmwareMinBranch {
+			upstream, _, _ := strings.Cut(version, "-")
+			components = append(components, sbom.Component{
+				Name: fmt.Sprintf("nvidia-firmware-%s-%s", branch, upstream), Version: version, Kind: sbom.KindFirmware, Branch: branch,
+			})
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, kernel := range kernels {
+		if kernel.Codename != codename || !kernel.Supported || !kernel.HasLRM || len(kernel.LRMPackages) == 0 {
+			continue
+		}
+		if !isArchiveVersion(kernel.LatestLRMVersion) || kernel.LatestLRMVersion == "ERROR" || seen[kernel.LRMPackages[0]] {
+			continue
+		}
+		seen[kernel.LRMPackages[0]] = true
+		components = append(components, sbom.Component{
+			Name: kernel.LRMPackages[0], Version: kernel.LatestLRMVersion, Kind: sbom.KindLRM, Source: true, Kernel: kernel.Source,
+		})
+	}
+	return components
+}
+
+// sbomHandler serves the SBOM of the NVIDIA components of a series, in CycloneDX or with
+// ?format=spdx in SPDX (/api/v1/sbom/{codename})
+func (ws *WebService) sbomHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	series := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/sbom"), "/")
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "cyclonedx" && format != "spdx" {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "format must be cyclonedx or spdx")
+		return
+	}
+
+	index, lastUpdated, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	// Without L-R-M data the document only lacks the L-R-M packages
+	var kernels []lrm.KernelLRMResult
+	if lrmData, err := lrm.GetCachedLRMData(); err == nil {
+		kernels = lrmData.KernelResults
+	}
+	components := sbomComponents(index, kernels, series)
+	if len(components) == 0 {
+		problems.Write(w, http.StatusNotFound, problems.NotFound, "No NVIDIA components are published in this series")
+		return
+	}
+
+	var document interface{}
+	if format == "spdx" {
+		// Unique to the series and data refresh; it does not need to resolve
+		namespace := fmt.Sprintf("https://%s/api/v1/sbom/%s/spdx/%d", r.Host, series, lastUpdated.Unix())
+		w.Header().Set("Content-Type", "application/spdx+json")
+		document = sbom.BuildSPDX(series, components, lastUpdated, namespace)
+	} else {
+		w.Header().Set("Content-Type", "application/vnd.cyclonedx+json")
+		document = sbom.BuildCycloneDX(series, components, lastUpdated)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
@@ -0,0 +1,101 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"nvidia_driver_monitor/internal/debuglog"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/schema"
+	"nvidia_driver_monitor/internal/sru"
+)
+
+// Schema types served at /api/v1/schema/{type}
+const (
+	schemaPackage  = "package"
+	schemaKernel   = "kernel"
+	schemaSRUCycle = "sru-cycle"
+	schemaSnapshot = "snapshot"
+)
+
+// schemaTypes maps the schema names to the Go types the API encodes
+var schemaTypes = map[string]struct {
+	Title string
+	Type  reflect.Type
+}{
+	schemaPackage:  {"Package", reflect.TypeOf(PackageData{})},
+	schemaKernel:   {"Kernel L-R-M result", reflect.TypeOf(lrm.KernelLRMResult{})},
+	schemaSRUCycle: {"SRU cycle", reflect.TypeOf(sru.SRUCycle{})},
+	schemaSnapshot: {"Snapshot", reflect.TypeOf(Snapshot{})},
+}
+
+var (
+	schemasOnce sync.Once
+	schemas     map[string]*schema.Schema
+)
+
+// apiSchema returns the generated schema of a type, or nil when the name is unknown. The
+// types do not change at runtime, so each schema is generated once.
+func apiSchema(name string) *schema.Schema {
+	schemasOnce.Do(func() {
+		schemas = make(map[string]*schema.Schema, len(schemaTypes))
+		for key, entry := range schemaTypes {
+			schemas[key] = schema.Generate(entry.Type, "/api/v1/schema/"+key, entry.Title)
+		}
+	})
+	return schemas[name]
+}
+
+// schemaHandler lists the schema types (/api/v1/schema) or serves the JSON Schema of one of
+// them (/api/v1/schema/{type})
+func (ws *WebService) schemaHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/schema"), "/")
+	if name == "" {
+		names := make([]string, 0, len(schemaTypes))
+		for key := range schemaTypes {
+			names = append(names, key)
+		}
+		sort.Strings(names)
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"types": names}); err != nil {
+			problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+		}
+		return
+	}
+
+	document := apiSchema(name)
+	if document == nil {
+		problems.Write(w, http.StatusNotFound, problems.NotFound, "Unknown schema type")
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	if err := json.NewEncoder(w).Encode(document); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// checkSchema validates an outgoing value against its published schema while the schema
+// debug module is on, logging every violation. It never changes the response.
+func checkSchema(name string, value interface{}) {
+	if !debuglog.Enabled(debuglog.ModuleSchema) {
+		return
+	}
+	violations, err := schema.ValidateValue(apiSchema(name), value)
+	if err != nil {
+		debuglog.Printf(debuglog.ModuleSchema, "could not check %s response: %v", name, err)
+		return
+	}
+	for _, violation := range violations {
+		debuglog.Printf(debuglog.ModuleSchema, "%s response does not match its schema at %s", name, violation)
+	}
+}
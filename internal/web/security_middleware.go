@@ -2,10 +2,27 @@ package web
 
 import (
 	"net/http"
+
+	"nvidia_driver_monitor/internal/config"
 )
 
-// SecurityHeadersMiddleware adds security headers to all responses
+// SecurityHeadersMiddleware adds security headers to all responses, with the Content Security
+// Policy of the default configuration
 func SecurityHeadersMiddleware(next http.Handler) http.Handler {
+	cfg := config.DefaultConfig()
+	return NewSecurityHeadersMiddleware(cfg.Security.GetContentSecurityPolicy(cfg.URLs.CDN))(next)
+}
+
+// NewSecurityHeadersMiddleware creates a middleware adding security headers to all responses,
+// with the given Content Security Policy
+func NewSecurityHeadersMiddleware(csp string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return securityHeaders(csp, next)
+	}
+}
+
+// securityHeaders adds the security headers before calling next
+func securityHeaders(csp string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Basic security headers
 		w.Header().Set("X-Content-Type-Options", "nosniff")
@@ -13,16 +30,7 @@ func SecurityHeadersMiddleware(next http.Handler) http.Handler {
 		w.Header().Set("X-XSS-Protection", "1; mode=block")
 		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
 
-		// Content Security Policy - restrictive but allows the app to function
-		csp := "default-src 'self'; " +
-			"script-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net https://cdnjs.cloudflare.com; " +
-			"style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net https://cdnjs.cloudflare.com; " +
-			"img-src 'self' data:; " +
-			"connect-src 'self'; " +
-			"font-src 'self' https://cdn.jsdelivr.net https://cdnjs.cloudflare.com; " +
-			"object-src 'none'; " +
-			"base-uri 'self'; " +
-			"form-action 'self'"
+		// Content Security Policy - restrictive but allows the app and its CDN assets to function
 		w.Header().Set("Content-Security-Policy", csp)
 
 		// HSTS header - only set for HTTPS connections
@@ -4,7 +4,12 @@ import (
 	"crypto/tls"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"nvidia_driver_monitor/internal/config"
 )
 
 func TestSecurityHeadersMiddleware(t *testing.T) {
@@ -73,3 +78,47 @@ func assertHeader(t *testing.T, w *httptest.ResponseRecorder, header, expected s
 		t.Errorf("Header %s: expected %q, got %q", header, expected, actual)
 	}
 }
+
+func TestContentSecurityPolicyCoversCDNs(t *testing.T) {
+	cfg := config.DefaultConfig()
+	csp := cfg.Security.GetContentSecurityPolicy(cfg.URLs.CDN)
+	if !strings.Contains(csp, "script-src 'self' https://assets.ubuntu.com https://cdn.jsdelivr.net ") {
+		t.Errorf("default CSP %q should allow the CDN origins of the assets", csp)
+	}
+
+	cfg.URLs.CDN.Offline = true
+	if csp := cfg.Security.GetContentSecurityPolicy(cfg.URLs.CDN); strings.Contains(csp, "https://") {
+		t.Errorf("offline CSP %q should not allow any CDN", csp)
+	}
+
+	cfg.Security.ContentSecurityPolicy = "default-src 'none'"
+	w := httptest.NewRecorder()
+	NewSecurityHeadersMiddleware(cfg.Security.GetContentSecurityPolicy(cfg.URLs.CDN))(http.NotFoundHandler()).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assertHeader(t, w, "Content-Security-Policy", "default-src 'none'")
+}
+
+func TestCDNResourcesIntegrityAndOffline(t *testing.T) {
+	cfg := config.DefaultConfig()
+	resources := GetCDNResources(cfg)
+	if resources["BootstrapCSS"] != cfg.URLs.CDN.BootstrapCSS || resources["BootstrapCSSIntegrity"] != cfg.URLs.CDN.BootstrapCSSIntegrity {
+		t.Errorf("BootstrapCSS = %q (%q), expected the CDN URL and its integrity", resources["BootstrapCSS"], resources["BootstrapCSSIntegrity"])
+	}
+
+	cfg.URLs.CDN.Offline = true
+	resources = GetCDNResources(cfg)
+	if resources["BootstrapJS"] != "/static/vendor/bootstrap.bundle.min.js" || resources["BootstrapJSIntegrity"] == "" {
+		t.Errorf("offline BootstrapJS = %q (%q), expected the vendored copy with its integrity", resources["BootstrapJS"], resources["BootstrapJSIntegrity"])
+	}
+
+	staticDir := t.TempDir()
+	os.MkdirAll(filepath.Join(staticDir, "vendor"), 0755)
+	os.WriteFile(filepath.Join(staticDir, "vendor", "chart.min.js"), []byte("//"), 0644)
+	if missing := missingVendoredAssets(cfg.URLs.CDN, staticDir); len(missing) != 4 {
+		t.Errorf("missingVendoredAssets() = %v, expected the 4 assets not vendored", missing)
+	}
+
+	cfg.URLs.CDN.ChartJSIntegrity = "md5-abc"
+	if err := cfg.URLs.CDN.Validate(); err == nil {
+		t.Error("Validate() should reject an unsupported integrity algorithm")
+	}
+}
@@ -0,0 +1,169 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/i18n"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/pkg/debversion"
+)
+
+// State of a driver of the previous series in the development series
+const (
+	seedingDone      = "seeded"      // Devel publishes at least the version of the previous series
+	seedingBehind    = "behind"      // Devel publishes an older version than the previous series
+	seedingProposed  = "in-proposed" // Only devel-proposed has a version so far
+	seedingPending   = "pending"     // Not copied or synced to devel yet
+	seedingUntracked = "untracked"   // The supported releases do not track the package in devel
+)
+
+// SeedingItem is a driver published in the previous series and its state in the devel series
+type SeedingItem struct {
+	Package  string `json:"package"`
+	Branch   string `json:"branch"`
+	Previous string `json:"previous"`           // Published version in the previous series
+	Devel    string `json:"devel,omitempty"`    // Published version in the devel series
+	Proposed string `json:"proposed,omitempty"` // Version in devel-proposed
+	Status   string `json:"status"`
+}
+
+// DevelSeeding is the checklist of the drivers the devel series still needs from the previous one
+type DevelSeeding struct {
+	Devel       string        `json:"devel"`
+	Previous    string        `json:"previous"`
+	Items       []SeedingItem `json:"items"`
+	Remaining   int           `json:"remaining"` // Items not seeded yet
+	DetectedAt  time.Time     `json:"detected_at"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+// previousSeries returns the series before devel in the configured order, newest first; a devel
+// series missing from the order has just opened and follows the newest configured series
+func previousSeries(cfg *config.SeriesConfig, devel string) string {
+	order := cfg.GetOrder()
+	for i, series := range order {
+		if series == devel {
+			if i+1 < len(order) {
+				return order[i+1]
+			}
+			return ""
+		}
+	}
+	return order[0]
+}
+
+// buildDevelSeeding compares the drivers published in the previous series with the devel series
+func buildDevelSeeding(index *packageIndex, devel, previous string) []SeedingItem {
+	items := []SeedingItem{}
+	for _, pkg := range index.packages {
+		prev, ok := index.row(pkg.PackageName, previous)
+		if !ok || prev.Removed != "" || !isArchiveVersion(prev.UpdatesSecurity) {
+			continue
+		}
+		item := SeedingItem{Package: pkg.PackageName, Branch: branchFromPackage(pkg.PackageName), Previous: prev.UpdatesSecurity, Status: seedingUntracked}
+		if row, ok := index.row(pkg.PackageName, devel); ok {
+			if isArchiveVersion(row.Proposed) {
+				item.Proposed = row.Proposed
+			}
+			switch {
+			case isArchiveVersion(row.UpdatesSecurity):
+				item.Devel = row.UpdatesSecurity
+				item.Status = seedingDone
+				if debversion.OlderThan(row.UpdatesSecurity, prev.UpdatesSecurity) {
+					item.Status = seedingBehind
+				}
+			case item.Proposed != "":
+				item.Status = seedingProposed
+			default:
+				item.Status = seedingPending
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// updateDevelSeeding regenerates the seeding checklist after a refresh. The detection of a new
+// devel series is logged with what it still needs from the previous series.
+func (ws *WebService) updateDevelSeeding(now time.Time) {
+	devel := releases.DevelCodename()
+	if devel == "" || ws.config == nil {
+		return
+	}
+	previous := previousSeries(&ws.config.Series, devel)
+	if previous == "" {
+		return
+	}
+	index, _, _ := ws.getPackageIndex()
+	seeding := &DevelSeeding{Devel: devel, Previous: previous, Items: buildDevelSeeding(index, devel, previous), DetectedAt: now, GeneratedAt: now}
+	for _, item := range seeding.Items {
+		if item.Status != seedingDone {
+			seeding.Remaining++
+		}
+	}
+
+	ws.cacheMux.Lock()
+	defer ws.cacheMux.Unlock()
+	if ws.develSeeding != nil && ws.develSeeding.Devel == devel {
+		seeding.DetectedAt = ws.develSeeding.DetectedAt
+	} else {
+		log.Printf("Development series %s detected: %d of the %d drivers of %s still to seed",
+			devel, seeding.Remaining, len(seeding.Items), previous)
+	}
+	ws.develSeeding = seeding
+}
+
+// getDevelSeeding returns the last generated seeding checklist, nil before the first one
+func (ws *WebService) getDevelSeeding() *DevelSeeding {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	return ws.develSeeding
+}
+
+// seedingHandler serves the seeding checklist of the devel series (/api/v1/seeding)
+func (ws *WebService) seedingHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	seeding := ws.getDevelSeeding()
+	if seeding == nil {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "The development series has not been detected yet")
+		return
+	}
+	if err := json.NewEncoder(w).Encode(seeding); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// seedingPageHandler renders the seeding checklist of the devel series (/seeding)
+func (ws *WebService) seedingPageHandler(w http.ResponseWriter, r *http.Request) {
+	locale := requestLocale(w, r, ws.config)
+	seeding := ws.getDevelSeeding()
+	if seeding == nil {
+		http.Error(w, i18n.T(locale, "error.initializing"), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	templateFile := filepath.Join(ws.templatePath, "seeding.html")
+	tmpl, err := template.New("seeding.html").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, requestTimezone(w, r, ws.config))).ParseFiles(templateFile)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Template parsing error: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := struct {
+		*DevelSeeding
+		CDN map[string]string
+	}{
+		DevelSeeding: seeding,
+		CDN:          GetCDNResources(ws.config),
+	}
+	renderPage(w, tmpl, templateData)
+}
@@ -14,21 +14,43 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"nvidia_driver_monitor/internal/acks"
+	"nvidia_driver_monitor/internal/advisories"
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/budget"
+	"nvidia_driver_monitor/internal/certification"
 	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/debuglog"
+	"nvidia_driver_monitor/internal/domain"
 	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/faults"
+	"nvidia_driver_monitor/internal/gpus"
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/i18n"
+	"nvidia_driver_monitor/internal/janitor"
+	"nvidia_driver_monitor/internal/jobs"
 	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/lrmreport"
+	"nvidia_driver_monitor/internal/notes"
 	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/phasing"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/queue"
+	"nvidia_driver_monitor/internal/rediscache"
 	"nvidia_driver_monitor/internal/releases"
 	"nvidia_driver_monitor/internal/sru"
+	"nvidia_driver_monitor/internal/stats"
+	"nvidia_driver_monitor/internal/supervise"
+	"nvidia_driver_monitor/internal/tracker"
 	"nvidia_driver_monitor/internal/utils"
-
-	version "github.com/knqyf263/go-deb-version"
 )
 
 // majorVersion extracts the leading numeric component (major) from a Debian version string.
@@ -64,36 +86,31 @@ func majorVersion(v string) string {
 	return base
 }
 
-// SeriesData represents the data for a single series row
-type SeriesData struct {
-	Series          string
-	UpdatesSecurity string
-	PocketMarkers   string
-	Proposed        string
-	UpstreamVersion string
-	ReleaseDate     string
-	SRUCycle        string
-	UpdatesColor    string
-	ProposedColor   string
-}
-
-// PackageData represents the data for a complete package table
-type PackageData struct {
-	PackageName string
-	Series      []SeriesData
-}
+// The dashboard rows are the domain types shared with the other layers
+type (
+	SeriesData   = domain.SeriesData
+	PackageData  = domain.PackageData
+	PackageError = domain.PackageError
+)
 
 // CachedData holds all the cached package data
 type CachedData struct {
 	AllPackages   []*PackageData
+	PackageErrors []*PackageError
+	Issues        []DataIssue // Inconsistencies found by the validation pass of the last refresh
 	LastUpdated   time.Time
 	IsInitialized bool
+	index         *packageIndex // Lookups into AllPackages, rebuilt by setPackages
+	generation    int           // Bumped by setPackages, so responses built from older packages are not reused
 }
 
 // WebService handles the web server functionality
 type WebService struct {
+	// The upstream data the packages are generated from, replaced under cacheMux on refresh;
+	// read it through getUpstreamData
 	supportedReleases []releases.SupportedRelease
 	udaEntries        []drivers.DriverEntry
+	tegraEntries      []drivers.DriverEntry // L4T releases, when the Tegra branches are enabled
 	allBranches       drivers.AllBranches
 	sruCycles         *sru.SRUCycles
 
@@ -102,6 +119,12 @@ type WebService struct {
 	cacheMux sync.RWMutex
 	stopChan chan bool
 
+	// refreshMux serializes full refreshes and retries of failed sub-datasets
+	refreshMux sync.Mutex
+
+	// datasets holds the load state of each sub-dataset, guarded by cacheMux
+	datasets map[string]*DatasetStatus
+
 	// HTTPS Configuration
 	EnableHTTPS bool
 	CertFile    string
@@ -111,53 +134,85 @@ type WebService struct {
 	config                *config.Config
 	templatePath          string
 	supportedReleasesPath string
-}
-
-// NewWebService creates a new web service instance
-func NewWebService() (*WebService, error) {
-	// Initialize the service with empty cache
-	ws := &WebService{
-		cache: &CachedData{
-			AllPackages:   make([]*PackageData, 0),
-			IsInitialized: false,
-		},
-		stopChan:              make(chan bool),
-		supportedReleasesPath: "data/supportedReleases.json", // Default path for development
-	}
-
-	// Perform initial data load
-	if err := ws.refreshData(); err != nil {
-		return nil, fmt.Errorf("failed to perform initial data load: %v", err)
-	}
-
-	// Initialize LRM cache
-	if err := lrm.InitializeLRMCache(); err != nil {
-		log.Printf("Warning: Failed to initialize LRM cache: %v", err)
-		// Don't fail startup, just log the warning
-	} else {
-		// Start background LRM cache refresh
-		lrm.StartBackgroundRefresh()
-	}
 
-	// Start background data refresh goroutine
-	go ws.dataRefreshLoop()
-
-	return ws, nil
+	// startedAt is used by the watchdog to detect an initial load that never completes
+	startedAt time.Time
+
+	// historyStore keeps daily observations used for SLO tracking
+	historyStore *history.Store
+
+	// advisoryStore keeps the known bad driver/kernel combinations
+	advisoryStore *advisories.Store
+
+	// noteStore keeps the operator notes attached to package/series cells
+	noteStore *notes.Store
+	// ackStore keeps the cells acknowledged as intentionally outdated
+	ackStore *acks.Store
+	// gpuStore keeps the uploaded GPU inventories
+	gpuStore *gpus.Store
+	// trackerStore keeps the issues opened for cells outdated for too long, and trackerClient
+	// opens and closes them; nil unless the issue tracker is enabled
+	trackerStore  *tracker.Store
+	trackerClient *tracker.Client
+	// lrmReportStore remembers the SRU cycles whose L-R-M report was sent; nil unless the
+	// report is enabled
+	lrmReportStore *lrmreport.Store
+
+	// archiveIssues are the divergences found by the last archive consistency check
+	archiveIssues    []DataIssue
+	archiveCheckedAt time.Time
+	// releaseIssues are the problems found by the last archive Release file check
+	releaseIssues    []DataIssue
+	releaseCheckedAt time.Time
+	// discoveryIssues are the untracked packages found by the last discovery scan
+	discoveryIssues    []DataIssue
+	discoveryCheckedAt time.Time
+	// i386Warnings are the i386 library warnings of each UDA branch from the last i386 check
+	i386Warnings  map[string][]I386Warning
+	i386CheckedAt time.Time
+	// cloudImages are the drivers shipped in the current cloud images from the last check
+	cloudImages          []CloudImageReport
+	cloudImagesCheckedAt time.Time
+	// seedReports are the restricted seeds from the last seed check, and seedDefaults the
+	// default branch they seed in each series
+	seedReports    []SeedReport
+	seedDefaults   map[string]string
+	seedsCheckedAt time.Time
+	// certifiedPlatforms are the certified hardware platforms from the last certification check
+	certifiedPlatforms     []certification.Platform
+	certificationCheckedAt time.Time
+	certificationError     string // Why the last check failed; the previous platforms are kept
+	// develSeeding is the checklist of the drivers the devel series needs from the previous series
+	develSeeding *DevelSeeding
+	// recentChanges summarizes what the last refreshes changed, newest first
+	recentChanges []ChangeSummary
+
+	// refreshFailures counts consecutive refreshes that failed or served stale data
+	refreshFailures int
+	// nextRefreshAt is when dataRefreshLoop refreshes the data next, guarded by cacheMux
+	nextRefreshAt time.Time
+
+	// announcedRemovals holds the package/series removals already notified
+	announcedRemovals map[string]bool
+	removalsMux       sync.Mutex
+
+	// rechecks holds the time of the last forced recheck of each package
+	rechecks   map[string]time.Time
+	recheckMux sync.Mutex
+
+	// janitor prunes and reports the disk caches; nil without a configuration
+	janitor *janitor.Janitor
+	// jobStore runs the exports and reports triggered through the API; nil unless jobs are enabled
+	jobStore *jobs.Store
 }
 
-// NewWebServiceWithConfig creates a new web service instance with configuration
-func NewWebServiceWithConfig(cfg *config.Config, templatePath string, supportedReleasesPath string) (*WebService, error) {
-	// Set global configuration for packages
-	packages.SetPackagesConfig(cfg)
-	// Ensure LRM and SRU processors use this configuration (for effective URL switching and HTTP settings)
-	lrm.SetProcessorConfig(cfg)
-	sru.SetSRUConfig(cfg)
-	// Apply HTTP client settings to LRM (timeouts/retries) if provided in config
-	if cfg != nil {
-		lrm.SetHTTPConfig(cfg.HTTP.GetTimeout(), cfg.HTTP.Retries)
-		lrm.SetMaxConcurrency(cfg.Processing.GetMaxConcurrency())
-		utils.SetHTTPUserAgent(cfg.HTTP.UserAgent)
-		utils.SetHTTPAuthToken(cfg.HTTP.GetForgejoToken())
+// NewWebService creates a new web service instance. The data is loaded in the background:
+// the dashboard serves whichever sub-datasets loaded while the failed ones are retried.
+func NewWebService(cfg *config.Config, templatePath string, supportedReleasesPath string) *WebService {
+	applyGlobalConfig(cfg)
+
+	if supportedReleasesPath == "" {
+		supportedReleasesPath = "data/supportedReleases.json" // Default path for development
 	}
 
 	// Initialize the service with empty cache
@@ -170,20 +225,32 @@ func NewWebServiceWithConfig(cfg *config.Config, templatePath string, supportedR
 		config:                cfg,
 		templatePath:          templatePath,
 		supportedReleasesPath: supportedReleasesPath,
+		startedAt:             time.Now(),
+		historyStore:          history.NewStore(""),
+		advisoryStore:         advisories.NewStore(""),
+		noteStore:             notes.NewStore(""),
+		ackStore:              acks.NewStore(""),
+		gpuStore:              gpus.NewStore(""),
+	}
+	if cfg != nil {
+		ws.historyStore = history.NewStore(cfg.History.GetDataFile())
+		ws.historyStore.SetRetention(cfg.History.GetRetentionDays())
+		ws.advisoryStore = advisories.NewStore(cfg.Advisories.GetDataFile())
+		ws.noteStore = notes.NewStore(cfg.Notes.GetDataFile())
+		ws.ackStore = acks.NewStore(cfg.Acks.GetDataFile())
+		ws.gpuStore = gpus.NewStore(cfg.Fleet.GetGPUInventoryFile())
+		ws.janitor = janitor.New(janitorCaches(cfg))
+		if cfg.Jobs.Enabled {
+			ws.jobStore = jobs.NewStore(cfg.Jobs.GetDir(), cfg.Jobs.GetRetention(), cfg.Jobs.GetMaxRunning())
+		}
 	}
 
 	// Start initial data load in background
 	log.Printf("Starting background data refresh...")
-	go func() {
-		if err := ws.refreshData(); err != nil {
-			log.Printf("Background data refresh failed: %v", err)
-		} else {
-			log.Printf("Background data refresh completed successfully")
-		}
-	}()
+	supervise.Loop("initial-refresh", ws.initialLoadLoop)
 
 	// Initialize LRM cache in background
-	go func() {
+	supervise.Loop("lrm-initialize", func() {
 		if err := lrm.InitializeLRMCache(); err != nil {
 			log.Printf("Warning: Failed to initialize LRM cache: %v", err)
 			// Don't fail startup, just log the warning
@@ -192,94 +259,347 @@ func NewWebServiceWithConfig(cfg *config.Config, templatePath string, supportedR
 			// Start background LRM cache refresh
 			lrm.StartBackgroundRefresh()
 		}
-	}()
+	})
 
-	// Start background data refresh goroutine with configured interval
-	go ws.dataRefreshLoop()
+	// Start background data refresh and watchdog goroutines, restarted on panic
+	supervise.Loop("data-refresh", ws.dataRefreshLoop)
+	supervise.Loop("watchdog", ws.watchdogLoop)
+	supervise.Loop("budget", ws.budgetLoop)
+	if cfg != nil && cfg.Janitor.Enabled {
+		supervise.Loop("cache-janitor", ws.janitorLoop)
+	}
+	if cfg != nil && cfg.ArchiveCheck.Enabled {
+		supervise.Loop("archive-check", ws.archiveCheckLoop)
+	}
+	if cfg != nil && cfg.ReleaseCheck.Enabled {
+		supervise.Loop("release-check", ws.releaseCheckLoop)
+	}
+	if cfg != nil && cfg.Discovery.Enabled {
+		supervise.Loop("discovery", ws.discoveryLoop)
+	}
+	if cfg != nil && cfg.I386.Enabled {
+		supervise.Loop("i386-check", ws.i386CheckLoop)
+	}
+	if cfg != nil && cfg.CloudImages.Enabled {
+		supervise.Loop("cloud-image-check", ws.cloudImageCheckLoop)
+	}
+	if cfg != nil && cfg.Seeds.Enabled {
+		supervise.Loop("seeds-check", ws.seedsCheckLoop)
+	}
+	if cfg != nil && cfg.Certification.Enabled {
+		supervise.Loop("certification-check", ws.certificationCheckLoop)
+	}
+	if cfg != nil && cfg.IssueTracker.Enabled {
+		tc := &cfg.IssueTracker
+		client, err := tracker.NewClient(tc.Kind, tc.GetAPIURL(), tc.Repository, tc.GetToken(), tc.Labels)
+		if err != nil {
+			log.Printf("Warning: Issue tracker disabled: %v", err)
+		} else {
+			ws.trackerClient = client
+			ws.trackerStore = tracker.NewStore(tc.GetDataFile())
+			supervise.Loop("issue-tracker", ws.trackerLoop)
+		}
+	}
+	if cfg != nil && cfg.LRMReport.Enabled {
+		ws.lrmReportStore = lrmreport.NewStore(cfg.LRMReport.GetDataFile())
+		supervise.Loop("lrm-report", ws.lrmReportLoop)
+	}
 
-	return ws, nil
+	return ws
 }
 
-// refreshData fetches all data and updates the cache
+// applyGlobalConfig propagates configuration to the package-level fetchers
+func applyGlobalConfig(cfg *config.Config) {
+	// Set global configuration for packages
+	packages.SetPackagesConfig(cfg)
+	releases.SetReleasesConfig(cfg)
+	// Ensure LRM and SRU processors use this configuration (for effective URL switching and HTTP settings)
+	lrm.SetProcessorConfig(cfg)
+	sru.SetSRUConfig(cfg)
+	stats.SetStatsConfig(cfg)
+	budget.SetBudgetConfig(cfg)
+	alerts.SetAlertsConfig(cfg)
+	queue.SetQueueConfig(cfg)
+	phasing.SetPhasingConfig(cfg)
+	debuglog.SetDebugConfig(cfg)
+	// Apply HTTP client settings to LRM (timeouts/retries) if provided in config
+	if cfg != nil {
+		lrm.SetHTTPConfig(cfg.HTTP.GetTimeout(), cfg.HTTP.Retries)
+		lrm.SetMaxConcurrency(cfg.Processing.GetMaxConcurrency())
+		utils.SetHTTPUserAgent(cfg.HTTP.UserAgent)
+		utils.SetHTTPAuthToken(cfg.HTTP.GetForgejoToken())
+		utils.SetHTTPProxy(cfg.HTTP.HTTPProxy, cfg.HTTP.HTTPSProxy, cfg.HTTP.NoProxy)
+		utils.SetHTTPOffline(cfg.HTTP.Offline)
+		faults.Enable(cfg.Testing.Enabled)
+		if cfg.Testing.Enabled {
+			log.Printf("[MOCK] Testing mode: upstream requests are served by the mock server on port %d (scenario %q); statistics domains are tagged [MOCK]",
+				cfg.Testing.MockServerPort, cfg.Testing.Scenario)
+			for _, fault := range cfg.Testing.Faults {
+				if err := faults.Inject(fault.Stage, fault.Mode); err != nil {
+					log.Printf("Warning: Could not inject fault into %s: %v", fault.Stage, err)
+					continue
+				}
+				log.Printf("[MOCK] Injected fault: %s will %s", fault.Stage, fault.Mode)
+			}
+		}
+	}
+}
+
+// refreshData fetches all data and updates the cache. Sub-datasets that fail keep their
+// previous data; the returned error names them.
 func (ws *WebService) refreshData() error {
+	ws.refreshMux.Lock()
+	defer ws.refreshMux.Unlock()
+
 	log.Printf("Refreshing data...")
 
 	// Read supported releases configuration
-	supportedReleases, err := releases.ReadSupportedReleases(ws.supportedReleasesPath)
+	supportedReleases, err := ws.readSupportedReleases()
 	if err != nil {
-		return fmt.Errorf("failed to read supported releases: %v", err)
+		return err
 	}
 
-	branchMajors := releases.GetUniqueBranchMajors(supportedReleases)
-
-	// Get the latest UDA releases from nvidia.com limited to supported majors
-	udaEntries, err := drivers.GetNvidiaDriverEntries(ws.config, branchMajors)
-	if err != nil {
-		return fmt.Errorf("failed to get UDA entries: %v", err)
+	// Resolve the "devel" alias used in is_supported to the current development series
+	if _, err := releases.ResolveDevelCodename(lrm.DevelopmentCodename); err != nil {
+		log.Printf("Warning: %v", err)
 	}
 
-	// Get server driver versions
-	_, allBranches, err := drivers.GetLatestServerDriverVersions(ws.config)
-	if err != nil {
-		log.Printf("Warning: Failed to get server driver versions: %v", err)
-		log.Printf("Continuing refresh without ERD/server driver updates")
-		allBranches = make(drivers.AllBranches)
+	// Get the latest UDA releases from nvidia.com limited to supported majors, the server
+	// driver versions and the SRU cycles
+	ws.loadUDA(releases.GetUniqueBranchMajors(supportedReleases))
+	ws.loadERD()
+	ws.loadSRU()
+	if releases.TegraEnabled() {
+		ws.loadTegra()
 	}
 
+	ws.generateAllPackages(supportedReleases)
+	ws.updateDevelSeeding(time.Now())
+	return datasetsError(ws.getDatasets())
+}
+
+// generateAllPackages updates the supported releases with the loaded upstream versions and
+// regenerates every package into the cache
+func (ws *WebService) generateAllPackages(supportedReleases []releases.SupportedRelease) {
 	// Update supported releases with latest versions
-	releases.UpdateSupportedUDAReleases(udaEntries, supportedReleases)
-	releases.UpdateSupportedReleasesWithLatestERD(allBranches, supportedReleases)
+	upstream := ws.getUpstreamData()
+	releases.UpdateSupportedUDAReleases(upstream.udaEntries, supportedReleases)
+	releases.UpdateSupportedReleasesWithLatestERD(upstream.allBranches, supportedReleases)
+	releases.UpdateSupportedTegraReleases(upstream.tegraEntries, supportedReleases)
+	ws.applyTargetVersions(supportedReleases)
+	ws.cacheMux.Lock()
+	ws.supportedReleases = supportedReleases
+	ws.cacheMux.Unlock()
 
-	// Fetch SRU cycles with fallback
-	sruCycles, err := sru.FetchSRUCycles()
-	if err != nil {
-		log.Printf("Warning: Failed to fetch SRU cycles: %v", err)
-		log.Printf("Using fallback SRU cycles with estimated dates")
-		sruCycles = sru.CreateFallbackSRUCycles()
-	} else {
-		sruCycles.AddPredictedCycles()
+	// Packages that fail keep their last good data, labeled stale, when there is any
+	previous, previousUpdated, _ := ws.getCachedPackages()
+	lastGood := make(map[string]*PackageData, len(previous))
+	for _, pkg := range previous {
+		lastGood[pkg.PackageName] = pkg
 	}
 
-	// Update service state
-	ws.udaEntries = udaEntries
-	ws.allBranches = allBranches
-	ws.supportedReleases = supportedReleases
-	ws.sruCycles = sruCycles
-
-	// Generate all package data
+	// Generate all package data, keeping failures so they can be shown instead of dropped
 	var allPackages []*PackageData
-	for _, release := range ws.supportedReleases {
-		packageName := "nvidia-graphics-drivers-" + release.BranchName
+	var packageErrors []*PackageError
+	generated := 0
+	for _, release := range supportedReleases {
+		packageName := release.PackageName()
 		packageData, err := ws.generatePackageData(packageName)
 		if err != nil {
+			if last, ok := lastGood[packageName]; ok {
+				log.Printf("Error generating data for %s, serving stale data: %v", packageName, err)
+				allPackages = append(allPackages, staleCopy(last, previousUpdated))
+				continue
+			}
 			log.Printf("Error generating data for %s: %v", packageName, err)
+			packageErrors = append(packageErrors, newPackageError(packageName, err))
 			continue
 		}
 		allPackages = append(allPackages, packageData)
+		generated++
+	}
+	ws.trackHistory(allPackages, time.Now())
+	ws.recordChanges(previous, allPackages, time.Now())
+	ws.evaluateViewAlerts(allPackages)
+	ws.evaluateArchitectureAlerts(supportedReleases)
+	ws.evaluateCutoffAlerts(allPackages, time.Now())
+	ws.evaluateDefaultBranchAlerts(allPackages)
+	ws.announceRemovals(allPackages, time.Now())
+	issues := validateDashboard(allPackages, supportedReleases, time.Now())
+	if len(issues) > 0 {
+		log.Printf("Validation found %d data inconsistencies, see /diagnostics", len(issues))
 	}
 
+	// Launchpad is considered down only when no package could be generated
+	var packagesErr error
+	if generated == 0 && len(supportedReleases) > 0 {
+		packagesErr = fmt.Errorf("no package could be generated from Launchpad")
+	}
+	ws.setDatasetResult(datasetPackages, packagesErr)
+
 	// Update cache with write lock
 	ws.cacheMux.Lock()
-	ws.cache.AllPackages = allPackages
-	ws.cache.LastUpdated = time.Now()
+	ws.cache.setPackages(allPackages)
+	ws.cache.PackageErrors = packageErrors
+	ws.cache.Issues = issues
+	// During a Launchpad outage the data is only as recent as the stale copies served, so the
+	// watchdog can tell it is not refreshing
+	if packagesErr == nil || previousUpdated.IsZero() {
+		ws.cache.LastUpdated = time.Now()
+	}
 	ws.cache.IsInitialized = true
 	ws.cacheMux.Unlock()
 
-	log.Printf("Data refresh completed. Generated %d packages.", len(allPackages))
+	log.Printf("Data refresh completed. Generated %d packages, %d failed.", len(allPackages), len(packageErrors))
+}
+
+// targetProvider returns the configured source of target version overrides, or nil
+func (ws *WebService) targetProvider() releases.TargetProvider {
+	if ws.config == nil {
+		return nil
+	}
+	switch {
+	case ws.config.Targets.URL != "":
+		return &releases.HTTPTargetProvider{URL: ws.config.Targets.URL}
+	case ws.config.Targets.File != "":
+		return &releases.FileTargetProvider{Path: ws.config.Targets.File}
+	}
 	return nil
 }
 
-// dataRefreshLoop runs in the background and refreshes data every 5 minutes
+// applyTargetVersions overrides the comparison version of branches that have a target
+func (ws *WebService) applyTargetVersions(supportedReleases []releases.SupportedRelease) {
+	provider := ws.targetProvider()
+	if provider == nil {
+		return
+	}
+	targets, err := provider.Targets()
+	if err != nil {
+		log.Printf("Warning: Failed to load target versions: %v", err)
+		log.Printf("Continuing refresh with latest upstream versions only")
+		return
+	}
+	releases.ApplyTargetVersions(targets, supportedReleases)
+}
+
+// publishedLabel returns the column header for the configured published pockets
+func publishedLabel() string {
+	return strings.Join(packages.PublishedPockets(), "/")
+}
+
+// staleCopy returns a copy of the last good data of a package labeled stale. Data that is
+// already stale keeps its original time.
+func staleCopy(pkg *PackageData, lastUpdated time.Time) *PackageData {
+	stale := *pkg
+	if stale.StaleSince == nil {
+		stale.StaleSince = &lastUpdated
+	}
+	return &stale
+}
+
+// hasStalePackages reports whether any cached package is served from stale data
+func (ws *WebService) hasStalePackages() bool {
+	pkgs, _, _ := ws.getCachedPackages()
+	for _, pkg := range pkgs {
+		if pkg.StaleSince != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// maxBackoff returns the longest wait between refreshes while upstream APIs fail
+func (ws *WebService) maxBackoff() time.Duration {
+	if ws.config != nil {
+		return ws.config.Cache.GetMaxBackoff()
+	}
+	return time.Hour
+}
+
+// nextRefreshDelay returns the wait before the next background refresh. Refreshes that fail
+// or serve stale data back off exponentially until the upstream APIs recover.
+func (ws *WebService) nextRefreshDelay(refreshErr error) time.Duration {
+	if refreshErr == nil && !ws.hasStalePackages() {
+		if ws.refreshFailures > 0 {
+			log.Printf("Upstream APIs recovered after %d degraded refreshes", ws.refreshFailures)
+		}
+		ws.refreshFailures = 0
+		return dataRefreshInterval
+	}
+	ws.refreshFailures++
+	delay := utils.Backoff(ws.refreshFailures, dataRefreshInterval, ws.maxBackoff())
+	log.Printf("Refresh degraded (%d in a row), next attempt in %v", ws.refreshFailures, delay)
+	return delay
+}
+
+// newPackageError builds the error record for a package that failed to generate
+func newPackageError(packageName string, err error) *PackageError {
+	return &PackageError{
+		PackageName: packageName,
+		Error:       err.Error(),
+		FailedAt:    time.Now(),
+	}
+}
+
+// retryPackage regenerates a single package and updates its cache entry
+func (ws *WebService) retryPackage(packageName string) (*PackageData, error) {
+	packageData, genErr := ws.generatePackageData(packageName)
+	if genErr == nil {
+		ws.trackHistory([]*PackageData{packageData}, time.Now())
+	}
+	supportedReleases := ws.getUpstreamData().supportedReleases
+
+	ws.cacheMux.Lock()
+	defer ws.cacheMux.Unlock()
+
+	remainingErrors := make([]*PackageError, 0, len(ws.cache.PackageErrors))
+	for _, pkgErr := range ws.cache.PackageErrors {
+		if pkgErr.PackageName != packageName {
+			remainingErrors = append(remainingErrors, pkgErr)
+		}
+	}
+
+	if genErr != nil {
+		ws.cache.PackageErrors = append(remainingErrors, newPackageError(packageName, genErr))
+		return nil, genErr
+	}
+	ws.cache.PackageErrors = remainingErrors
+
+	// Rebuild the package list in supported release order
+	byName := make(map[string]*PackageData, len(ws.cache.AllPackages)+1)
+	for _, pkg := range ws.cache.AllPackages {
+		byName[pkg.PackageName] = pkg
+	}
+	byName[packageName] = packageData
+
+	allPackages := make([]*PackageData, 0, len(byName))
+	for _, release := range supportedReleases {
+		if pkg, ok := byName[release.PackageName()]; ok {
+			allPackages = append(allPackages, pkg)
+		}
+	}
+	ws.cache.setPackages(allPackages)
+
+	return packageData, nil
+}
+
+// dataRefreshLoop runs in the background and refreshes data every 5 minutes, backing off
+// while upstream APIs are unavailable
 func (ws *WebService) dataRefreshLoop() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
+	timer := time.NewTimer(dataRefreshInterval)
+	defer timer.Stop()
+	ws.setNextRefresh(time.Now().Add(dataRefreshInterval))
 
 	for {
 		select {
-		case <-ticker.C:
-			if err := ws.refreshData(); err != nil {
+		case <-timer.C:
+			err := ws.refreshData()
+			if err != nil {
 				log.Printf("Background data refresh failed: %v", err)
 			}
+			delay := budgetDelay(ws.nextRefreshDelay(err), time.Now())
+			ws.setNextRefresh(time.Now().Add(delay))
+			timer.Reset(delay)
 		case <-ws.stopChan:
 			log.Printf("Stopping data refresh loop...")
 			return
@@ -312,24 +632,42 @@ func (ws *WebService) getCachedPackages() ([]*PackageData, time.Time, bool) {
 	return packages, ws.cache.LastUpdated, ws.cache.IsInitialized
 }
 
+// getPackageErrors returns a copy of the packages that failed during the last refresh
+func (ws *WebService) getPackageErrors() []*PackageError {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+
+	packageErrors := make([]*PackageError, len(ws.cache.PackageErrors))
+	copy(packageErrors, ws.cache.PackageErrors)
+
+	return packageErrors
+}
+
 // generatePackageData generates the table data for a specific package
 func (ws *WebService) generatePackageData(packageName string) (*PackageData, error) {
+	if err := faults.Check(faults.StagePackages); err != nil {
+		return nil, err
+	}
 	// Get source package versions
-	sourceVersions, err := packages.GetMaxSourceVersionsArchive(ws.config, packageName)
+	sourceVersions, err := packages.GetMaxSourceVersionsCached(ws.config, packageName)
 	if err != nil {
 		return nil, err
 	}
 
 	// Build a lookup: branch name -> SupportedRelease
+	upstream := ws.getUpstreamData()
 	supportedMap := make(map[string]releases.SupportedRelease)
-	for _, rel := range ws.supportedReleases {
+	branchName := ""
+	for _, rel := range upstream.supportedReleases {
 		supportedMap[rel.BranchName] = rel
+		if rel.PackageName() == packageName {
+			branchName = rel.BranchName
+		}
 	}
 
 	// Extract branch name from package name
-	branchName := ""
 	parts := strings.Split(packageName, "-")
-	for i := len(parts) - 1; i >= 0; i-- {
+	for i := len(parts) - 1; i >= 0 && branchName == ""; i-- {
 		if parts[i] == "server" && i > 0 {
 			branchName = parts[i-1] + "-server"
 			break
@@ -351,29 +689,44 @@ func (ws *WebService) generatePackageData(packageName string) (*PackageData, err
 
 	supported, found := supportedMap[branchName]
 
-	orderedSeries := []string{"resolute", "noble", "jammy", "focal", "bionic"}
+	publishedPockets := packages.PublishedPockets()
 	var seriesData []SeriesData
 
 	// Check if we have any source versions at all
-	hasSourceVersions := len(sourceVersions.VersionMap) > 0
+	hasSourceVersions := len(sourceVersions.VersionMap) > 0 || len(sourceVersions.Removals) > 0
 
 	if hasSourceVersions {
 		// Normal case: package exists in Launchpad archive
-		for _, series := range orderedSeries {
-			pocket, exists := sourceVersions.VersionMap[series]
-			if !exists {
-				continue // Skip series that don't exist in the version map
+		for _, series := range sourceVersions.AllSeriesNames() {
+			pocket := sourceVersions.VersionMap[series]
+
+			if removal, ok := sourceVersions.Removals[series]; ok && pocket == nil {
+				seriesData = append(seriesData, removedSeriesData(series, removal))
+				continue
 			}
 
 			updates := "-"
 			pocketMarkers := ""
+			component := ""
+			proposedComponent := ""
+			section := ""
 			proposed := "-"
 			updatesColor := ""
 			proposedColor := ""
 			upstreamVersion := "-"
+			targetVersion := "-"
 			releaseDate := "-"
 			sruCycleDate := "-"
 
+			// Packages are compared against the target version when one is configured
+			comparisonVersion := ""
+			if found {
+				comparisonVersion = supported.ComparisonVersion()
+				if supported.TargetVersion != "" {
+					targetVersion = supported.TargetVersion
+				}
+			}
+
 			if found && supported.CurrentUpstreamVersion != "" {
 				upstreamVersion = supported.CurrentUpstreamVersion
 				if supported.DatePublished != "" {
@@ -382,52 +735,23 @@ func (ws *WebService) generatePackageData(packageName string) (*PackageData, err
 			}
 
 			if pocket != nil {
-				// Determine greatest version among Release/Updates/Security
-				bestSet := false
-				var best version.Version
-				if pocket.Release.String() != "" {
-					best = pocket.Release
-					bestSet = true
-				}
-				if pocket.Updates.String() != "" {
-					if !bestSet || pocket.Updates.GreaterThan(best) {
-						best = pocket.Updates
-						bestSet = true
-					}
-				}
-				if pocket.Security.String() != "" {
-					if !bestSet || pocket.Security.GreaterThan(best) {
-						best = pocket.Security
-						bestSet = true
-					}
-				}
-
-				if bestSet {
+				// Determine greatest version among the configured published pockets
+				if best, ok := pocket.LatestPublished(publishedPockets); ok {
 					updates = best.String()
-					// Build pocket markers in order U/S/R
-					u := "-"
-					s := "-"
-					r := "-"
-					if pocket.Updates.String() == updates {
-						u = "U"
-					}
-					if pocket.Security.String() == updates {
-						s = "S"
-					}
-					if pocket.Release.String() == updates {
-						r = "R"
-					}
-					pocketMarkers = fmt.Sprintf(" (%s/%s/%s)", u, s, r)
+					// Build pocket markers in configured display order
+					pocketMarkers = pocket.PocketMarkers(publishedPockets, updates)
+					component = pocket.PublishedComponent(publishedPockets, updates)
+					section = pocket.PublishedSection(publishedPockets, updates)
 				}
-				if found && supported.CurrentUpstreamVersion != "" {
-					// Check if the upstream version is contained in the package version
-					if strings.Contains(updates, supported.CurrentUpstreamVersion) {
+				if comparisonVersion != "" {
+					// Check if the package version matches the upstream (or target) version
+					if packages.MatchesUpstream(updates, comparisonVersion, supported.GetComparisonPolicy()) {
 						updatesColor = "success"
 					} else {
 						updatesColor = "danger"
 						// If version is red (upstream is greater), find SRU cycle
-						if ws.sruCycles != nil && supported.DatePublished != "" {
-							if sruCycle := ws.sruCycles.GetMinimumCutoffAfterDate(supported.DatePublished); sruCycle != nil {
+						if upstream.sruCycles != nil && supported.DatePublished != "" {
+							if sruCycle := upstream.sruCycles.GetMinimumCutoffAfterDate(supported.DatePublished); sruCycle != nil {
 								sruCycleDate = sruCycle.ReleaseDate
 							}
 						}
@@ -437,15 +761,16 @@ func (ws *WebService) generatePackageData(packageName string) (*PackageData, err
 
 			if pocket != nil && pocket.Proposed.String() != "" {
 				proposed = pocket.Proposed.String()
-				if found && supported.CurrentUpstreamVersion != "" {
-					// Check if the upstream version is contained in the package version
-					if strings.Contains(proposed, supported.CurrentUpstreamVersion) {
+				proposedComponent = pocket.PocketComponent("Proposed")
+				if comparisonVersion != "" {
+					// Check if the package version matches the upstream (or target) version
+					if packages.MatchesUpstream(proposed, comparisonVersion, supported.GetComparisonPolicy()) {
 						proposedColor = "success"
 					} else {
 						proposedColor = "danger"
 						// If version is red (upstream is greater), find SRU cycle
-						if ws.sruCycles != nil && supported.DatePublished != "" && sruCycleDate == "-" {
-							if sruCycle := ws.sruCycles.GetMinimumCutoffAfterDate(supported.DatePublished); sruCycle != nil {
+						if upstream.sruCycles != nil && supported.DatePublished != "" && sruCycleDate == "-" {
+							if sruCycle := upstream.sruCycles.GetMinimumCutoffAfterDate(supported.DatePublished); sruCycle != nil {
 								sruCycleDate = sruCycle.ReleaseDate
 							}
 						}
@@ -453,16 +778,33 @@ func (ws *WebService) generatePackageData(packageName string) (*PackageData, err
 				}
 			}
 
+			var comparisons []packages.VersionComparison
+			if comparisonVersion != "" {
+				now := time.Now()
+				if updates != "-" {
+					comparisons = append(comparisons, packages.NewVersionComparison("published", updates, comparisonVersion, supported.ComparisonDate(), supported.GetComparisonPolicy(), now))
+				}
+				if proposed != "-" {
+					comparisons = append(comparisons, packages.NewVersionComparison("proposed", proposed, comparisonVersion, supported.ComparisonDate(), supported.GetComparisonPolicy(), now))
+				}
+			}
+
 			seriesData = append(seriesData, SeriesData{
-				Series:          series,
-				UpdatesSecurity: updates,
-				PocketMarkers:   pocketMarkers,
-				Proposed:        proposed,
-				UpstreamVersion: upstreamVersion,
-				ReleaseDate:     releaseDate,
-				SRUCycle:        sruCycleDate,
-				UpdatesColor:    updatesColor,
-				ProposedColor:   proposedColor,
+				Series:            series,
+				UpdatesSecurity:   updates,
+				PocketMarkers:     pocketMarkers,
+				Proposed:          proposed,
+				UpstreamVersion:   upstreamVersion,
+				TargetVersion:     targetVersion,
+				TargetNote:        supported.TargetNote,
+				ReleaseDate:       releaseDate,
+				SRUCycle:          sruCycleDate,
+				UpdatesColor:      updatesColor,
+				ProposedColor:     proposedColor,
+				Component:         component,
+				ProposedComponent: proposedComponent,
+				Section:           section,
+				Comparisons:       comparisons,
 			})
 		}
 	} else if found && supported.CurrentUpstreamVersion != "" {
@@ -471,52 +813,232 @@ func (ws *WebService) generatePackageData(packageName string) (*PackageData, err
 		upstreamVersion := supported.CurrentUpstreamVersion
 		releaseDate := supported.DatePublished
 		sruCycleDate := "-"
+		targetVersion := "-"
+		if supported.TargetVersion != "" {
+			targetVersion = supported.TargetVersion
+		}
 
 		// Calculate SRU cycle for when this might be available
-		if ws.sruCycles != nil && supported.DatePublished != "" {
-			if sruCycle := ws.sruCycles.GetMinimumCutoffAfterDate(supported.DatePublished); sruCycle != nil {
+		if upstream.sruCycles != nil && supported.DatePublished != "" {
+			if sruCycle := upstream.sruCycles.GetMinimumCutoffAfterDate(supported.DatePublished); sruCycle != nil {
 				sruCycleDate = sruCycle.ReleaseDate
 			}
 		}
 
 		// Show entry for supported series where this driver should be available
-		for _, series := range orderedSeries {
-			// Check if this series is supported for this branch
-			if supported.IsSupported != nil {
-				seriesSupported := false
-				switch series {
-				case "bionic":
-					seriesSupported = supported.IsSupported["bionic"]
-				case "focal":
-					seriesSupported = supported.IsSupported["focal"]
-				case "jammy":
-					seriesSupported = supported.IsSupported["jammy"]
-				case "noble":
-					seriesSupported = supported.IsSupported["noble"]
-				case "resolute":
-					seriesSupported = supported.IsSupported["resolute"]
-				}
-
-				if seriesSupported {
-					seriesData = append(seriesData, SeriesData{
-						Series:          series,
-						UpdatesSecurity: "N/A",
-						Proposed:        "N/A",
-						UpstreamVersion: upstreamVersion,
-						ReleaseDate:     releaseDate,
-						SRUCycle:        sruCycleDate,
-						UpdatesColor:    "",
-						ProposedColor:   "",
-					})
-				}
+		for _, series := range notUploadedSeries(&supported) {
+			availability := seriesAvailability(series)
+			rowSRUCycle := sruCycleDate
+			if availability != availabilityNotUploaded {
+				rowSRUCycle = "-" // Nothing will be released to a series Launchpad no longer maintains
 			}
+			seriesData = append(seriesData, SeriesData{
+				Series:          series,
+				UpdatesSecurity: "N/A",
+				Proposed:        "N/A",
+				UpstreamVersion: upstreamVersion,
+				TargetVersion:   targetVersion,
+				TargetNote:      supported.TargetNote,
+				ReleaseDate:     releaseDate,
+				SRUCycle:        rowSRUCycle,
+				UpdatesColor:    "",
+				ProposedColor:   "",
+				Availability:    availability,
+			})
 		}
 	}
 
-	return &PackageData{
+	ws.applyQueueStatus(packageName, seriesData)
+	applyPhasing(packageName, seriesData)
+	applyUploadProgress(seriesData)
+	applyNotes(ws.noteStore, packageName, seriesData)
+	applyAcknowledgements(ws.ackStore, packageName, seriesData, time.Now())
+	applyDefaultBranch(ws.defaultBranches(), packageName, seriesData)
+	applyFreezeState(seriesData)
+
+	packageData := &PackageData{
 		PackageName: packageName,
 		Series:      seriesData,
-	}, nil
+		Changelogs:  ws.fetchChangelogs(sourceVersions, seriesData),
+	}
+	if !sourceVersions.StaleSince.IsZero() {
+		staleSince := sourceVersions.StaleSince
+		packageData.StaleSince = &staleSince
+	}
+	return packageData, nil
+}
+
+// Availability of the rows without a published version, e.g. the N/A rows shown for a package
+// that has no uploads yet
+const (
+	availabilityNotUploaded   = "not-uploaded"   // The series exists; nothing was uploaded yet
+	availabilityInQueue       = "in-queue"       // An upload waits in the unapproved or NEW queue
+	availabilityInProposed    = "in-proposed"    // Only proposed has a version so far
+	availabilitySeriesEOL     = "series-eol"     // Launchpad marks the series obsolete
+	availabilitySeriesUnknown = "series-unknown" // Launchpad does not know the series
+)
+
+// applyUploadProgress tells the rows without a published version whose upload is in proposed
+// or waiting in the queue from those still to be uploaded. Must run after applyQueueStatus.
+func applyUploadProgress(seriesData []SeriesData) {
+	for i := range seriesData {
+		row := &seriesData[i]
+		if row.Removed != "" || isArchiveVersion(row.UpdatesSecurity) {
+			continue
+		}
+		if row.Availability != "" && row.Availability != availabilityNotUploaded {
+			continue
+		}
+		switch {
+		case isArchiveVersion(row.Proposed):
+			row.Availability = availabilityInProposed
+		case row.QueueStatus != "":
+			row.Availability = availabilityInQueue
+		}
+	}
+}
+
+// notUploadedSeries returns the series a branch with no uploads yet gets a row in: its supported
+// series, the devel alias resolved, filtered and ordered as the rest of the table
+func notUploadedSeries(supported *releases.SupportedRelease) []string {
+	return packages.DisplaySeries(supported.SupportedCodenames())
+}
+
+// seriesAvailability checks a series against Launchpad before an N/A row is shown for it.
+// When Launchpad cannot be asked the series is assumed to exist.
+func seriesAvailability(series string) string {
+	state, err := releases.LookupSeriesState(series)
+	if err != nil {
+		log.Printf("Warning: Could not check series %s in Launchpad: %v", series, err)
+		return availabilityNotUploaded
+	}
+	switch state {
+	case releases.SeriesEOL:
+		return availabilitySeriesEOL
+	case releases.SeriesUnknown:
+		return availabilitySeriesUnknown
+	default:
+		return availabilityNotUploaded
+	}
+}
+
+// Freeze states of the development series rows
+const (
+	freezeFrozen         = "frozen"          // Launchpad has the series in pre-release freeze
+	freezeNeedsException = "needs-exception" // Frozen, and the row still needs an upload
+)
+
+// applyFreezeState marks the development series rows while the series is frozen, so uploads
+// planned for it account for a freeze exception. Acknowledged rows need no upload.
+func applyFreezeState(seriesData []SeriesData) {
+	devel := releases.DevelCodename()
+	if devel == "" {
+		return
+	}
+	for i := range seriesData {
+		row := &seriesData[i]
+		if row.Series != devel || row.Removed != "" {
+			continue
+		}
+		state, err := releases.LookupSeriesState(devel)
+		if err != nil {
+			log.Printf("Warning: Could not check the freeze state of %s in Launchpad: %v", devel, err)
+			return
+		}
+		if state != releases.SeriesFrozen {
+			return
+		}
+		row.Freeze = freezeFrozen
+		if row.UpdatesColor == "danger" || row.Availability == availabilityNotUploaded {
+			row.Freeze = freezeNeedsException
+		}
+	}
+}
+
+// removedSeriesData returns the row shown for a series the package was deleted from
+func removedSeriesData(series string, removal packages.Removal) SeriesData {
+	removed := "removed"
+	if !removal.Date.IsZero() {
+		removed = "removed on " + removal.Date.Format("2006-01-02")
+	}
+	note := removal.Version
+	if removal.Comment != "" {
+		note += ": " + removal.Comment
+	}
+	return SeriesData{
+		Series:          series,
+		UpdatesSecurity: "-",
+		Proposed:        "-",
+		UpstreamVersion: "-",
+		TargetVersion:   "-",
+		ReleaseDate:     "-",
+		SRUCycle:        "-",
+		Removed:         removed,
+		RemovalNote:     note,
+	}
+}
+
+// applyQueueStatus marks series rows that have an upload waiting in the unapproved or NEW queue
+func (ws *WebService) applyQueueStatus(packageName string, seriesData []SeriesData) {
+	if !queue.Enabled() {
+		return
+	}
+
+	for i := range seriesData {
+		uploads, err := queue.FetchUploads(seriesData[i].Series, packageName)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		if len(uploads) == 0 {
+			continue
+		}
+		// Show the upload that has been waiting the longest
+		seriesData[i].QueueStatus = uploads[0].Label()
+		seriesData[i].QueueVersion = uploads[0].Version
+	}
+}
+
+// applyPhasing marks the published versions that -updates is still phasing with their percentage
+func applyPhasing(packageName string, seriesData []SeriesData) {
+	binary := phasing.BinaryName(packageName)
+	if !phasing.Enabled() || binary == "" {
+		return
+	}
+
+	phases, err := phasing.FetchPhases(binary)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+	for i := range seriesData {
+		if phase, ok := phases[seriesData[i].Series]; ok && phase.Version == seriesData[i].UpdatesSecurity {
+			seriesData[i].Phasing = phase.Label()
+		}
+	}
+}
+
+// fetchChangelogs loads changelog entries for every version shown in the series rows
+func (ws *WebService) fetchChangelogs(sourceVersions *packages.SourceVersionPerSeries, seriesData []SeriesData) map[string]*packages.ChangelogEntry {
+	if ws.config == nil || !ws.config.Changelog.Enabled {
+		return nil
+	}
+
+	changelogs := make(map[string]*packages.ChangelogEntry)
+	for _, row := range seriesData {
+		for _, ver := range []string{row.UpdatesSecurity, row.Proposed} {
+			if ver == "-" || ver == "N/A" || changelogs[ver] != nil {
+				continue
+			}
+			entry, err := packages.GetChangelogEntry(ws.config.Changelog.GetCacheDir(), sourceVersions.PackageName, ver, sourceVersions.PublicationLinks[ver])
+			if err != nil {
+				log.Printf("Warning: Failed to get changelog for %s %s: %v", sourceVersions.PackageName, ver, err)
+				continue
+			}
+			changelogs[ver] = entry
+		}
+	}
+	return changelogs
 }
 
 // generateSelfSignedCert generates a self-signed certificate for HTTPS
@@ -582,15 +1104,52 @@ func generateSelfSignedCert(certFile, keyFile string) error {
 	return nil
 }
 
-// indexHandler handles the main page request
+// indexHandler handles the main page request. ?view= opens a configured view, as /view/{name}
+// does, or a dashboard preset.
 func (ws *WebService) indexHandler(w http.ResponseWriter, r *http.Request) {
+	if name := r.URL.Query().Get("view"); name != "" && ws.config != nil && ws.config.View(name) != nil {
+		ws.serveView(w, r, name)
+		return
+	}
+	ws.renderIndex(w, r, nil, nil)
+}
+
+// renderIndex renders the dashboard, scoped to a view when one is given and to the filters of
+// the query or its preset. apiQuery is appended to the row requests made by the page.
+func (ws *WebService) renderIndex(w http.ResponseWriter, r *http.Request, view *config.ViewConfig, apiQuery url.Values) {
+	locale := requestLocale(w, r, ws.config)
+
+	query := r.URL.Query()
+	var preset *config.PresetConfig
+	if name := query.Get("view"); view == nil && name != "" {
+		if ws.config != nil {
+			preset = ws.config.Preset(config.PresetPageIndex, name)
+		}
+		if preset == nil {
+			http.Error(w, i18n.T(locale, "error.view_not_found"), http.StatusNotFound)
+			return
+		}
+		query = withPreset(query, preset)
+	}
+	filters := parseIndexFilters(query)
+
 	// Get cached data
 	allPackages, lastUpdated, isInitialized := ws.getCachedPackages()
 
 	if !isInitialized {
-		http.Error(w, "Service is still initializing, please try again in a moment", http.StatusServiceUnavailable)
+		http.Error(w, i18n.T(locale, "error.initializing"), http.StatusServiceUnavailable)
 		return
 	}
+	allPackages = sortLongestOutdatedFirst(filters.Apply(filterPackagesForView(allPackages, view)))
+
+	// Row requests made by the page carry the filters
+	if filters.Active() {
+		merged := filters.Query()
+		for key, values := range apiQuery {
+			merged[key] = values
+		}
+		apiQuery = merged
+	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
@@ -603,7 +1162,7 @@ func (ws *WebService) indexHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse the template
-	tmpl, err := template.New("index").Parse(string(templateContent))
+	tmpl, err := template.New("index").Funcs(LocalizedFunctions(locale, requestTimezone(w, r, ws.config))).Parse(string(templateContent))
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error parsing index template: %v", err), http.StatusInternalServerError)
 		return
@@ -611,59 +1170,80 @@ func (ws *WebService) indexHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Create template data
 	templateData := struct {
-		AllPackages []*PackageData
-		LastUpdated time.Time
-		CDN         map[string]string
+		AllPackages    []*PackageData
+		PackageErrors  []*PackageError
+		LastUpdated    time.Time
+		PublishedLabel string
+		Stale          bool
+		Unavailable    []DatasetStatus
+		Maintenance    []*alerts.Window
+		View           *config.ViewConfig
+		ViewSummary    ViewSummary
+		Filters        IndexFilters
+		Columns        []IndexColumn
+		Preset         *config.PresetConfig
+		FilterAction   string // Page the filter form submits to, keeping the view
+		FilterToken    string // Token of the view, resubmitted with the filters
+		APIQuery       template.URL
+		CDN            map[string]string
+		Provenance     []Provenance
+		RecentChanges  []ChangeSummary
 	}{
-		AllPackages: allPackages,
-		LastUpdated: lastUpdated,
-		CDN:         GetCDNResources(ws.config),
+		AllPackages:    allPackages,
+		PackageErrors:  filterErrorsForView(ws.getPackageErrors(), view),
+		LastUpdated:    lastUpdated,
+		PublishedLabel: publishedLabel(),
+		Stale:          ws.hasStalePackages(),
+		Unavailable:    ws.unavailableDatasets(),
+		Maintenance:    alerts.ActiveMaintenance(time.Now()),
+		View:           view,
+		ViewSummary:    summarizeView(allPackages),
+		Filters:        filters,
+		Columns:        localizedColumns(locale),
+		Preset:         preset,
+		FilterAction:   filterAction(view),
+		FilterToken:    r.URL.Query().Get("token"),
+		APIQuery:       trustedQuery(apiQuery),
+		CDN:            GetCDNResources(ws.config),
+		Provenance:     ws.getProvenance(time.Now()),
+		RecentChanges:  filterChangesForView(ws.getRecentChanges(indexRecentChanges), view),
 	}
 
 	// Execute the template
-	if err := tmpl.Execute(w, templateData); err != nil {
-		http.Error(w, fmt.Sprintf("Error executing index template: %v", err), http.StatusInternalServerError)
-		return
-	}
+	renderPage(w, tmpl, templateData)
 }
 
 // packageHandler handles requests for specific package information
 func (ws *WebService) packageHandler(w http.ResponseWriter, r *http.Request) {
+	locale := requestLocale(w, r, ws.config)
+
 	packageName := r.URL.Query().Get("name")
 	if packageName == "" {
-		http.Error(w, "Package name is required", http.StatusBadRequest)
+		http.Error(w, i18n.T(locale, "error.package_required"), http.StatusBadRequest)
 		return
 	}
 
 	// Check cache first for the specific package
-	allPackages, _, isInitialized := ws.getCachedPackages()
+	index, _, isInitialized := ws.getPackageIndex()
 	if !isInitialized {
-		http.Error(w, "Service is still initializing, please try again in a moment", http.StatusServiceUnavailable)
+		http.Error(w, i18n.T(locale, "error.initializing"), http.StatusServiceUnavailable)
 		return
 	}
 
-	// Find the package in cache
-	var packageData *PackageData
-	for _, pkg := range allPackages {
-		if pkg.PackageName == packageName {
-			packageData = pkg
-			break
-		}
-	}
-
-	if packageData == nil {
-		http.Error(w, "Package not found", http.StatusNotFound)
+	packageData, ok := index.byName[packageName]
+	if !ok {
+		http.Error(w, i18n.T(locale, "error.package_not_found"), http.StatusNotFound)
 		return
 	}
 
 	packageTemplate := `
 <!DOCTYPE html>
-<html>
+<html lang="{{locale}}">
 <head>
-    <title>{{.PackageName}} - NVIDIA Driver Package Status</title>
+    <title>{{.PackageName}} - {{t "index.title"}}</title>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <link href="{{.CDN.BootstrapCSS}}" rel="stylesheet">
+    <link href="{{.CDN.BootstrapCSS}}" rel="stylesheet"{{with .CDN.BootstrapCSSIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}>
     <style>
         .container-fluid { max-width: 1200px; }
         .table-success { background-color: #d1e7dd !important; }
@@ -676,34 +1256,47 @@ func (ws *WebService) packageHandler(w http.ResponseWriter, r *http.Request) {
         <h1 class="mb-4">{{.PackageName}}</h1>
         
         <div class="alert alert-info">
-            <strong>Status Legend:</strong>
-            <span class="badge bg-success ms-2">Green</span> = Up to date with upstream
-            <span class="badge bg-danger ms-2">Red</span> = Outdated (shows next SRU cycle date)
+            <strong>{{t "legend.title"}}</strong>
+            <span class="badge bg-success ms-2">{{t "legend.green"}}</span> = {{t "legend.up_to_date"}}
+            <span class="badge bg-danger ms-2">{{t "legend.red"}}</span> = {{t "legend.outdated"}}
         </div>
 
         <div class="table-responsive">
-            <table class="table table-striped table-bordered">
+            <table class="table table-striped table-bordered sortable">
                 <thead class="table-dark">
                     <tr>
-                        <th>Series</th>
-						<th>Updates/Security/Release</th>
-                        <th>Proposed</th>
-                        <th>Upstream Version</th>
-                        <th>Release Date</th>
-                        <th>Next SRU Cycle</th>
+                        <th>{{t "common.series"}}</th>
+						<th>{{.PublishedLabel}}</th>
+                        <th>{{t "common.proposed"}}</th>
+                        <th>{{t "common.upstream_version"}}</th>
+                        <th>{{t "common.target"}}</th>
+                        <th>{{t "common.release_date"}}</th>
+                        <th>{{t "common.next_sru_cycle"}}</th>
                     </tr>
                 </thead>
                 <tbody>
                     {{range .Series}}
                     <tr>
-                        <td><strong>{{.Series}}</strong></td>
-                        <td class="{{if eq .UpdatesColor "success"}}table-success{{else if eq .UpdatesColor "danger"}}table-danger{{end}}">
-							{{.UpdatesSecurity}}{{.PocketMarkers}}
+                        <td><strong>{{.Series}}</strong>
+                            {{if .DefaultBranch}}<div><span class="badge bg-primary" title="{{t "cell.default_branch_title"}}">{{t "cell.default_branch"}}</span></div>{{end}}
+                            {{if .Note}}<div><span class="badge bg-secondary" title="{{.NoteUpdated}}">{{.Note}}</span></div>{{end}}
                         </td>
-                        <td class="{{if eq .ProposedColor "success"}}table-success{{else if eq .ProposedColor "danger"}}table-danger{{end}}">
+                        <td class="{{if eq .UpdatesColor "success"}}table-success{{else if eq .UpdatesColor "danger"}}table-danger{{else if eq .UpdatesColor "acknowledged"}}table-secondary{{end}}"{{if .Component}} title="{{t "cell.component"}} {{.Component}}"{{end}} data-sort="{{versionKey .UpdatesSecurity}}">
+							{{if .Removed}}<span class="text-muted" title="{{.RemovalNote}}">{{.Removed}}</span>{{else}}{{.UpdatesSecurity}}{{.PocketMarkers}}{{end}}
+							{{if .Acknowledged}}<div><span class="badge bg-secondary" title="{{t "cell.acknowledged_until"}} {{.AcknowledgedUntil}}">{{t "cell.acknowledged"}} {{.Acknowledged}}</span></div>{{end}}
+							{{if .Phasing}}<div><span class="badge bg-info text-dark" title="{{t "cell.phasing_title"}}">{{t "cell.phasing" .Phasing}}</span></div>{{end}}
+							{{if .OutdatedDays}}<div><span class="badge bg-danger" title="{{t "cell.outdated_since"}} {{.OutdatedSince}}">{{t "badge.red_for_days" .OutdatedDays}}</span></div>{{end}}
+							{{if eq .Availability "not-uploaded"}}<div class="small text-muted">{{t "availability.not_uploaded"}}</div>{{else if eq .Availability "in-queue"}}<div><span class="badge bg-info text-dark">{{t "availability.in_queue"}}</span></div>{{else if eq .Availability "in-proposed"}}<div><span class="badge bg-warning text-dark">{{t "availability.in_proposed"}}</span></div>{{else if eq .Availability "series-eol"}}<div><span class="badge bg-secondary">{{t "availability.series_eol"}}</span></div>{{else if eq .Availability "series-unknown"}}<div><span class="badge bg-danger">{{t "availability.series_unknown"}}</span></div>{{end}}
+							{{if eq .Freeze "needs-exception"}}<div><span class="badge bg-warning text-dark">{{t "freeze.needs_exception"}}</span></div>{{else if eq .Freeze "frozen"}}<div><span class="badge bg-info text-dark">{{t "freeze.frozen"}}</span></div>{{end}}
+                        </td>
+                        <td class="{{if eq .ProposedColor "success"}}table-success{{else if eq .ProposedColor "danger"}}table-danger{{end}}"{{if .ProposedComponent}} title="{{t "cell.component"}} {{.ProposedComponent}}"{{end}} data-sort="{{versionKey .Proposed}}">
                             {{.Proposed}}
+                            {{if .QueueStatus}}
+                            <div><span class="badge bg-info text-dark" title="{{.QueueVersion}}">{{.QueueStatus}}</span></div>
+                            {{end}}
                         </td>
-                        <td>{{.UpstreamVersion}}</td>
+                        <td data-sort="{{versionKey .UpstreamVersion}}">{{.UpstreamVersion}}</td>
+                        <td title="{{.TargetNote}}" data-sort="{{versionKey .TargetVersion}}">{{.TargetVersion}}</td>
                         <td>{{.ReleaseDate}}</td>
                         <td>
                             {{if ne .SRUCycle "-"}}
@@ -718,17 +1311,97 @@ func (ws *WebService) packageHandler(w http.ResponseWriter, r *http.Request) {
             </table>
         </div>
         
+        {{if .Transitions}}
+        <h2 class="mt-4 mb-3">{{t "package.transitions"}}</h2>
+        <table class="table table-sm table-bordered">
+            <thead>
+                <tr>
+                    <th>{{t "common.date"}}</th>
+                    <th>{{t "common.series"}}</th>
+                    <th>{{t "package.transition_change"}}</th>
+                    <th>{{t "package.transition_version"}}</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Transitions}}
+                <tr>
+                    <td>{{.Date}}</td>
+                    <td>{{.Series}}</td>
+                    <td>{{if eq .Field "section"}}{{t "package.transition_section"}}{{else}}{{t "package.transition_component"}}{{end}} <code>{{.From}}</code> → <code>{{.To}}</code></td>
+                    <td>{{.Version}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+
+        {{if .Changelogs}}
+        <h2 class="mt-4 mb-3">{{t "package.changelog"}}</h2>
+        {{range $version, $entry := .Changelogs}}
+        <div class="card mb-3">
+            <div class="card-header">
+                <strong>{{$entry.Version}}</strong>
+                <span class="text-muted">{{$entry.Distribution}}; urgency={{$entry.Urgency}}</span>
+                <span class="float-end small text-muted">{{$entry.Date}}</span>
+            </div>
+            <div class="card-body">
+                {{if $entry.Bugs}}
+                <div class="mb-2">
+                    <strong>{{t "package.bugs"}}</strong>
+                    {{range $entry.Bugs}}<a href="https://bugs.launchpad.net/bugs/{{.}}" class="badge bg-info text-dark me-1">LP: #{{.}}</a>{{end}}
+                </div>
+                {{end}}
+                {{if $entry.CVEs}}
+                <div class="mb-2">
+                    <strong>{{t "package.cves"}}</strong>
+                    {{range $entry.CVEs}}<a href="https://ubuntu.com/security/{{.}}" class="badge bg-danger me-1">{{.}}</a>{{end}}
+                </div>
+                {{end}}
+                <pre class="small mb-0">{{range $entry.Changes}}{{.}}
+{{end}}</pre>
+                <div class="small text-muted mt-2">{{$entry.Maintainer}}</div>
+            </div>
+        </div>
+        {{end}}
+        {{end}}
+
         <div class="mt-4">
-            <a href="/" class="btn btn-secondary">← Back to Overview</a>
-            <a href="/api?package={{.PackageName}}" class="btn btn-outline-primary">View JSON Data</a>
+            <a href="/" class="btn btn-secondary">← {{t "package.back"}}</a>
+            <a href="/api?package={{.PackageName}}" class="btn btn-outline-primary">{{t "action.view_json"}}</a>
+            <button type="button" class="btn btn-outline-secondary" id="recheck" data-package="{{.PackageName}}">{{t "package.recheck"}}</button>
+            <span class="small text-muted ms-2" id="recheck-status"></span>
         </div>
     </div>
 
-    <script src="{{.CDN.BootstrapJS}}"></script>
+    <script src="{{.CDN.BootstrapJS}}"{{with .CDN.BootstrapJSIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
+    <script src="/static/js/tablesort.js"></script>
+    <script>
+        document.getElementById('recheck').addEventListener('click', function() {
+            const button = this;
+            const status = document.getElementById('recheck-status');
+            button.disabled = true;
+            status.textContent = {{t "package.rechecking"}};
+            fetch('/api/recheck?package=' + encodeURIComponent(button.dataset.package), { method: 'POST' })
+                .then(function(response) {
+                    if (response.ok) {
+                        window.location.reload();
+                        return;
+                    }
+                    return response.json().then(function(body) {
+                        status.textContent = body.error || {{t "package.recheck_failed"}}.replace('%s', response.status);
+                        button.disabled = false;
+                    });
+                })
+                .catch(function(err) {
+                    status.textContent = {{t "package.recheck_failed"}}.replace('%s', err.message);
+                    button.disabled = false;
+                });
+        });
+    </script>
 </body>
 </html>`
 
-	tmpl, err := template.New("package").Parse(packageTemplate)
+	tmpl, err := template.New("package").Funcs(TemplateFunctions()).Funcs(LocalizedFunctions(locale, requestTimezone(w, r, ws.config))).Parse(packageTemplate)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error parsing template: %v", err), http.StatusInternalServerError)
 		return
@@ -739,129 +1412,269 @@ func (ws *WebService) packageHandler(w http.ResponseWriter, r *http.Request) {
 	// Create template data with CDN resources
 	templateData := struct {
 		*PackageData
-		CDN map[string]string
+		PublishedLabel string
+		CDN            map[string]string
+		Provenance     []Provenance
+		Transitions    []history.ComponentTransition
 	}{
-		PackageData: packageData,
-		CDN:         GetCDNResources(ws.config),
+		PackageData:    packageData,
+		PublishedLabel: publishedLabel(),
+		CDN:            GetCDNResources(ws.config),
+		Transitions:    ws.componentTransitions(packageName, ""),
 	}
 
-	if err := tmpl.Execute(w, templateData); err != nil {
-		http.Error(w, fmt.Sprintf("Error executing template: %v", err), http.StatusInternalServerError)
-		return
-	}
+	renderPage(w, tmpl, templateData)
 }
 
 // apiHandler handles JSON API requests
 func (ws *WebService) apiHandler(w http.ResponseWriter, r *http.Request) {
 	packageName := r.URL.Query().Get("package")
+	view, ok := ws.resolveView(w, r, r.URL.Query().Get("view"))
+	if !ok {
+		return
+	}
 
 	// Get cached data
-	allPackages, lastUpdated, isInitialized := ws.getCachedPackages()
+	index, lastUpdated, isInitialized := ws.getPackageIndex()
 	if !isInitialized {
-		http.Error(w, "Service is still initializing, please try again in a moment", http.StatusServiceUnavailable)
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing, please try again in a moment")
 		return
 	}
+	component := r.URL.Query().Get("component")
+	filters := parseIndexFilters(r.URL.Query())
 
 	if packageName != "" {
-		// Return data for specific package
-		for _, pkg := range allPackages {
-			if pkg.PackageName == packageName {
+		// Return data for specific package, scoped to the view, component and dashboard filters
+		if pkg, ok := index.byName[packageName]; ok {
+			if scoped := filters.Apply(filterPackagesByComponent(filterPackagesForView([]*PackageData{pkg}, view), component)); len(scoped) > 0 {
+				checkSchema(schemaPackage, scoped[0])
 				w.Header().Set("Content-Type", "application/json")
-				json.NewEncoder(w).Encode(pkg)
+				json.NewEncoder(w).Encode(scoped[0])
 				return
 			}
 		}
-		http.Error(w, "Package not found", http.StatusNotFound)
+		problems.Write(w, http.StatusNotFound, problems.PackageNotFound, "Package not found")
 		return
 	}
 
 	// Return data for all packages
 	allData := struct {
 		Packages    map[string]*PackageData `json:"packages"`
+		Errors      []*PackageError         `json:"errors"`
 		LastUpdated time.Time               `json:"last_updated"`
 	}{
-		Packages:    make(map[string]*PackageData),
+		Packages:    index.byName,
+		Errors:      filterErrorsForView(ws.getPackageErrors(), view),
 		LastUpdated: lastUpdated,
 	}
 
-	for _, pkg := range allPackages {
-		allData.Packages[pkg.PackageName] = pkg
+	if view != nil || component != "" || filters.Active() {
+		allData.Packages = make(map[string]*PackageData)
+		for _, pkg := range filters.Apply(filterPackagesByComponent(filterPackagesForView(index.packages, view), component)) {
+			allData.Packages[pkg.PackageName] = pkg
+		}
 	}
 
+	for _, pkg := range allData.Packages {
+		checkSchema(schemaPackage, pkg)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(allData)
 }
 
+// retryHandler regenerates a single failed package on demand
+func (ws *WebService) retryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	packageName := r.URL.Query().Get("package")
+	if packageName == "" {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Package name is required")
+		return
+	}
+
+	if _, _, isInitialized := ws.getCachedPackages(); !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+
+	if !ws.isSupportedPackage(packageName) {
+		problems.Write(w, http.StatusNotFound, problems.PackageNotFound, "Package not found")
+		return
+	}
+
+	packageData, err := ws.retryPackage(packageName)
+	if err != nil {
+		log.Printf("Retry failed for %s: %v", packageName, err)
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(newPackageError(packageName, err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(packageData)
+}
+
 // Start starts the web server with optional HTTPS support
 func (ws *WebService) Start(addr string) error {
 	// Create rate limiter if configured
 	var rateLimiter *RateLimiter
 	if ws.config != nil && ws.config.RateLimit.Enabled {
-		rateLimiter = NewRateLimiter(ws.config.RateLimit.RequestsPerMinute, true)
-		log.Printf("Rate limiting enabled: %d requests per minute", ws.config.RateLimit.RequestsPerMinute)
+		limits := &ws.config.RateLimit
+		if limits.GetBackend() == "redis" {
+			rateLimiter = NewDistributedRateLimiter(limits.RequestsPerMinute, limits.GetBurst(), rediscache.Connect(&ws.config.Redis))
+			log.Printf("Rate limiting enabled: %d requests per minute, bursts of %d, shared through Redis at %s", limits.RequestsPerMinute, limits.GetBurst(), ws.config.Redis.Address)
+		} else {
+			rateLimiter = NewRateLimiter(limits.RequestsPerMinute, true)
+			log.Printf("Rate limiting enabled: %d requests per minute", limits.RequestsPerMinute)
+		}
 	}
 
 	// Create handlers
 	lrmHandler := NewLRMHandler(ws.templatePath, ws.config)
 	apiHandler := NewAPIHandler()
+	apiHandler.advisoryStore = ws.advisoryStore
+	apiHandler.dataSources = ws.dataSources
+	apiHandler.requireAdmin = ws.requireAdmin
+	fleetHandler := NewFleetHandler(ws.templatePath, ws.config)
+	fleetHandler.gpuNeeds = ws.getGPUNeeds
+
+	// Every route reads its body through the body limit, the default one when none is configured
+	limits := ws.requestLimits()
+	requestLimitsMiddleware := RequestLimitsMiddleware(limits.GetMaxBodySize(), limits.GetRequestTimeout())
+	log.Printf("Request limits enabled: max_body_size=%d bytes, request_timeout=%v", limits.GetMaxBodySize(), limits.GetRequestTimeout())
+
+	// Optional OIDC login protecting the UI and API
+	authenticator := auth.NewAuthenticator(ws.config, ws.EnableHTTPS)
+	if authenticator != nil {
+		log.Printf("OIDC login enabled with issuer %s", ws.config.Auth.OIDC.IssuerURL)
+	}
 
-	// Create request limits middleware if configured
-	var requestLimitsMiddleware func(http.Handler) http.Handler
+	// Security headers, with the configured Content Security Policy
+	securityHeadersMiddleware := SecurityHeadersMiddleware
 	if ws.config != nil {
-		maxBodySize := ws.config.RequestLimit.MaxBodySize
-		requestTimeout := ws.config.RequestLimit.GetRequestTimeout()
-		requestLimitsMiddleware = RequestLimitsMiddleware(maxBodySize, requestTimeout)
-		log.Printf("Request limits enabled: max_body_size=%d bytes, request_timeout=%v", maxBodySize, requestTimeout)
-	} else {
-		// Use default limits if no config
-		requestLimitsMiddleware = RequestLimitsMiddleware(1048576, 30*time.Second) // 1MB, 30s
+		securityHeadersMiddleware = NewSecurityHeadersMiddleware(ws.config.Security.GetContentSecurityPolicy(ws.config.URLs.CDN))
+		if ws.config.URLs.CDN.Offline {
+			log.Printf("CDN offline mode: serving vendored assets from /static/vendor")
+			for _, file := range missingVendoredAssets(ws.config.URLs.CDN, "static") {
+				log.Printf("Warning: Vendored asset %s is missing, run 'make vendor-assets'", file)
+			}
+		}
 	}
 
-	// Setup middleware chain: Request Limits -> Security Headers -> Rate Limiting -> Handlers
+	// Optional Redis cache of the hot API responses
+	var responseCache *rediscache.Cache
+	if ws.config != nil {
+		if responseCache = rediscache.New(&ws.config.Redis); responseCache != nil {
+			log.Printf("Redis response cache enabled at %s for /api/v1/packages and /api/v1/lrm", ws.config.Redis.Address)
+		}
+	}
+
+	// Setup middleware chain: Request Limits -> Security Headers -> Authentication -> Rate Limiting -> Display Times -> Handlers
+	displayTimesMiddleware := DisplayTimesMiddleware(ws.config)
 	chainMiddleware := func(h http.Handler) http.Handler {
+		h = displayTimesMiddleware(h)
 		if rateLimiter != nil {
-			return requestLimitsMiddleware(SecurityHeadersMiddleware(rateLimiter.Middleware(h)))
+			h = rateLimiter.Middleware(h)
 		}
-		return requestLimitsMiddleware(SecurityHeadersMiddleware(h))
+		if authenticator != nil {
+			h = authenticator.Middleware(h)
+		}
+		return requestLimitsMiddleware(securityHeadersMiddleware(h))
 	}
 
 	// Setup routes with middleware chain
 	http.Handle("/", chainMiddleware(http.HandlerFunc(ws.indexHandler)))
 	http.Handle("/package", chainMiddleware(http.HandlerFunc(ws.packageHandler)))
 	http.Handle("/api", chainMiddleware(http.HandlerFunc(ws.apiHandler)))
+	http.Handle("/api/retry", chainMiddleware(http.HandlerFunc(ws.retryHandler)))
+	http.Handle("/api/recheck", chainMiddleware(http.HandlerFunc(ws.recheckHandler)))
 	http.Handle("/l-r-m-verifier", chainMiddleware(lrmHandler))
 	http.Handle("/statistics", chainMiddleware(http.HandlerFunc(ws.statisticsPageHandler)))
+	http.Handle("/fleet", chainMiddleware(fleetHandler))
+	http.Handle("/view/", chainMiddleware(http.HandlerFunc(ws.viewHandler)))
+	http.Handle("/graph", chainMiddleware(http.HandlerFunc(ws.graphPageHandler)))
+	http.Handle("/branch/", chainMiddleware(http.HandlerFunc(ws.branchHandler)))
+	http.Handle("/diagnostics", chainMiddleware(http.HandlerFunc(ws.diagnosticsPageHandler)))
+	http.Handle("/api/diagnostics", chainMiddleware(http.HandlerFunc(ws.diagnosticsHandler)))
+	http.Handle("/seeding", chainMiddleware(http.HandlerFunc(ws.seedingPageHandler)))
+	http.Handle("/about", chainMiddleware(http.HandlerFunc(ws.aboutPageHandler)))
+	http.Handle("/lrm/rebuilds", chainMiddleware(http.HandlerFunc(ws.lrmRebuildsPageHandler)))
+
+	if authenticator != nil {
+		http.Handle("/auth/login", chainMiddleware(http.HandlerFunc(authenticator.LoginHandler)))
+		http.Handle("/auth/callback", chainMiddleware(http.HandlerFunc(authenticator.CallbackHandler)))
+		http.Handle("/auth/logout", chainMiddleware(http.HandlerFunc(authenticator.LogoutHandler)))
+		http.Handle("/auth/me", chainMiddleware(http.HandlerFunc(authenticator.MeHandler)))
+	}
 
 	// Static files for statistics dashboard
 	http.Handle("/static/", chainMiddleware(http.StripPrefix("/static", http.FileServer(http.Dir("static")))))
 
 	// New API endpoints
-	http.Handle("/api/lrm", chainMiddleware(http.HandlerFunc(apiHandler.LRMDataHandler)))
+	lrmData := cachedResponse(responseCache, "lrm", apiHandler.lrmGeneration, http.HandlerFunc(apiHandler.LRMDataHandler))
+	http.Handle("/api/lrm", chainMiddleware(lrmData))
+	http.Handle("/api/v1/lrm", chainMiddleware(lrmData))
 	http.Handle("/api/lrm/progress", chainMiddleware(http.HandlerFunc(apiHandler.LRMProgressHandler)))
+	http.Handle("/api/v1/lrm/stream", chainMiddleware(http.HandlerFunc(apiHandler.LRMStreamHandler)))
+	http.Handle("/api/v1/lrm/report", chainMiddleware(http.HandlerFunc(ws.lrmReportPreviewHandler)))
+	http.Handle("/api/v1/lrm/rebuilds", chainMiddleware(http.HandlerFunc(ws.lrmRebuildsHandler)))
 	http.Handle("/api/health", chainMiddleware(http.HandlerFunc(apiHandler.HealthHandler)))
+	http.Handle("/api/maintenance", chainMiddleware(http.HandlerFunc(apiHandler.MaintenanceHandler)))
+	http.Handle("/api/ready", chainMiddleware(http.HandlerFunc(ws.readyHandler)))
+	http.Handle("/metrics", chainMiddleware(http.HandlerFunc(ws.metricsHandler)))
 	http.Handle("/api/routings", chainMiddleware(http.HandlerFunc(apiHandler.RoutingsHandler)))
+	http.Handle("/api/routings/", chainMiddleware(http.HandlerFunc(apiHandler.RoutingDetailHandler)))
+	http.Handle("/api/diagnostics/kernel-series", chainMiddleware(http.HandlerFunc(apiHandler.KernelSeriesDiagnosticsHandler)))
+	http.Handle("/api/v1/reports/generate", chainMiddleware(http.HandlerFunc(ws.reportsGenerateHandler)))
+	http.Handle("/api/v1/exports/", chainMiddleware(http.HandlerFunc(ws.exportsHandler)))
+	http.Handle("/api/v1/jobs", chainMiddleware(http.HandlerFunc(ws.jobsHandler)))
+	http.Handle("/api/v1/jobs/", chainMiddleware(http.HandlerFunc(ws.jobsHandler)))
+	http.Handle("/api/v1/caches", chainMiddleware(http.HandlerFunc(ws.cachesHandler)))
 	http.Handle("/api/cache-status", chainMiddleware(http.HandlerFunc(apiHandler.CacheStatusHandler)))
+	http.Handle("/api/version", chainMiddleware(http.HandlerFunc(ws.versionHandler)))
 	http.Handle("/api/statistics", chainMiddleware(http.HandlerFunc(apiHandler.StatisticsHandler)))
-
-	// Configure server timeouts
-	var readTimeout, writeTimeout, idleTimeout time.Duration
-	var maxHeaderBytes int
-
-	if ws.config != nil {
-		readTimeout = ws.config.RequestLimit.GetReadTimeout()
-		writeTimeout = ws.config.RequestLimit.GetWriteTimeout()
-		idleTimeout = ws.config.RequestLimit.GetIdleTimeout()
-		maxHeaderBytes = ws.config.RequestLimit.MaxHeaderBytes
-		if maxHeaderBytes <= 0 {
-			maxHeaderBytes = 1048576 // 1MB default
-		}
-	} else {
-		// Default timeouts
-		readTimeout = 15 * time.Second
-		writeTimeout = 15 * time.Second
-		idleTimeout = 60 * time.Second
-		maxHeaderBytes = 1048576 // 1MB
+	http.Handle("/api/budget", chainMiddleware(http.HandlerFunc(ws.budgetHandler)))
+	http.Handle("/api/architectures", chainMiddleware(http.HandlerFunc(ws.architecturesHandler)))
+
+	// Fleet host-check report ingestion
+	http.Handle("/api/v1/packages", chainMiddleware(cachedResponse(responseCache, "packages", ws.packagesGeneration, http.HandlerFunc(ws.packagesV1Handler))))
+	http.Handle("/api/v1/history/components", chainMiddleware(http.HandlerFunc(ws.componentTransitionsHandler)))
+	http.Handle("/api/v1/advisories", chainMiddleware(http.HandlerFunc(ws.advisoriesHandler)))
+	http.Handle("/api/v1/advisories/", chainMiddleware(http.HandlerFunc(ws.advisoryHandler)))
+	http.Handle("/api/v1/notes", chainMiddleware(http.HandlerFunc(ws.notesHandler)))
+	http.Handle("/api/v1/acknowledgements", chainMiddleware(http.HandlerFunc(ws.acknowledgementsHandler)))
+	http.Handle("/api/v1/annotations", chainMiddleware(http.HandlerFunc(ws.annotationsHandler)))
+	http.Handle("/api/provenance", chainMiddleware(http.HandlerFunc(ws.provenanceHandler)))
+	http.Handle("/api/v1/simulate/promotion", chainMiddleware(http.HandlerFunc(ws.promotionSimulatorHandler)))
+	http.Handle("/api/dkms/matrix", chainMiddleware(http.HandlerFunc(ws.dkmsMatrixHandler)))
+	http.Handle("/api/v1/prebuilt", chainMiddleware(http.HandlerFunc(ws.prebuiltHandler)))
+	http.Handle("/api/v1/sbom/", chainMiddleware(http.HandlerFunc(ws.sbomHandler)))
+	if ws.config != nil && ws.config.Testing.Enabled {
+		http.Handle("/api/v1/testing/faults", chainMiddleware(http.HandlerFunc(ws.faultsHandler)))
 	}
+	http.Handle("/api/v1/hosts", chainMiddleware(http.HandlerFunc(fleetHandler.HostsHandler)))
+	http.Handle("/api/v1/hosts/report", chainMiddleware(http.HandlerFunc(fleetHandler.ReportHandler)))
+	http.Handle("/api/v1/gpus", chainMiddleware(http.HandlerFunc(ws.gpusHandler)))
+	http.Handle("/api/v1/gpus/inventory", chainMiddleware(http.HandlerFunc(ws.gpuInventoryHandler)))
+	http.Handle("/api/v1/snapshot", chainMiddleware(http.HandlerFunc(ws.snapshotHandler)))
+	http.Handle("/api/v1/upstream/uda", chainMiddleware(http.HandlerFunc(ws.upstreamUDAHandler)))
+	http.Handle("/api/v1/upstream/erd", chainMiddleware(http.HandlerFunc(ws.upstreamERDHandler)))
+	http.Handle("/api/changes/recent", chainMiddleware(http.HandlerFunc(ws.recentChangesHandler)))
+	http.Handle("/api/v1/cloud-images", chainMiddleware(http.HandlerFunc(ws.cloudImagesHandler)))
+	http.Handle("/api/v1/seeds", chainMiddleware(http.HandlerFunc(ws.seedsHandler)))
+	http.Handle("/api/v1/recommendation", chainMiddleware(http.HandlerFunc(ws.recommendationHandler)))
+	http.Handle("/api/v1/certification", chainMiddleware(http.HandlerFunc(ws.certificationHandler)))
+	http.Handle("/api/v1/seeding", chainMiddleware(http.HandlerFunc(ws.seedingHandler)))
+	http.Handle("/api/v1/export/pins", chainMiddleware(http.HandlerFunc(ws.pinsHandler)))
+	http.Handle("/api/v1/matrix", chainMiddleware(http.HandlerFunc(ws.matrixHandler)))
+	http.Handle("/api/v1/debug", chainMiddleware(http.HandlerFunc(ws.debugHandler)))
+	http.Handle("/api/v1/hwe", chainMiddleware(http.HandlerFunc(ws.hweHandler)))
+	http.Handle("/api/v1/schema", chainMiddleware(http.HandlerFunc(ws.schemaHandler)))
+	http.Handle("/api/v1/schema/", chainMiddleware(http.HandlerFunc(ws.schemaHandler)))
 
 	if ws.EnableHTTPS {
 		// Check if certificates exist, generate if they don't
@@ -886,30 +1699,18 @@ func (ws *WebService) Start(addr string) error {
 			Certificates: []tls.Certificate{cert},
 		}
 
-		server := &http.Server{
-			Addr:           addr,
-			TLSConfig:      tlsConfig,
-			ReadTimeout:    readTimeout,
-			WriteTimeout:   writeTimeout,
-			IdleTimeout:    idleTimeout,
-			MaxHeaderBytes: maxHeaderBytes,
-		}
+		server := newServer(addr, nil, limits)
+		server.TLSConfig = tlsConfig
 
-		log.Printf("Starting HTTPS server on %s with timeouts: read=%v, write=%v, idle=%v",
-			addr, readTimeout, writeTimeout, idleTimeout)
+		log.Printf("Starting HTTPS server on %s with timeouts: read=%v, write=%v, idle=%v, max_header_bytes=%d",
+			addr, server.ReadTimeout, server.WriteTimeout, server.IdleTimeout, server.MaxHeaderBytes)
 		log.Printf("Access the service at: https://localhost%s", addr)
 		return server.ListenAndServeTLS("", "")
 	} else {
-		server := &http.Server{
-			Addr:           addr,
-			ReadTimeout:    readTimeout,
-			WriteTimeout:   writeTimeout,
-			IdleTimeout:    idleTimeout,
-			MaxHeaderBytes: maxHeaderBytes,
-		}
+		server := newServer(addr, nil, limits)
 
-		log.Printf("Starting HTTP server on %s with timeouts: read=%v, write=%v, idle=%v",
-			addr, readTimeout, writeTimeout, idleTimeout)
+		log.Printf("Starting HTTP server on %s with timeouts: read=%v, write=%v, idle=%v, max_header_bytes=%d",
+			addr, server.ReadTimeout, server.WriteTimeout, server.IdleTimeout, server.MaxHeaderBytes)
 		log.Printf("Access the service at: http://localhost%s", addr)
 		return server.ListenAndServe()
 	}
@@ -925,14 +1726,14 @@ func (ws *WebService) lrmVerifierHandler(w http.ResponseWriter, r *http.Request)
 	var lrmData *lrm.LRMVerifierData
 	if realData, fetchErr := lrm.GetCachedLRMData(); fetchErr != nil {
 		log.Printf("Failed to fetch cached L-R-M data, falling back to supported releases: %v", fetchErr)
-		lrmData = generateLRMDataFromSupportedReleases(ws.supportedReleases)
+		lrmData = generateLRMDataFromSupportedReleases(ws.getUpstreamData().supportedReleases)
 	} else {
 		log.Printf("Successfully fetched cached L-R-M data with %d kernels", len(realData.KernelResults))
 		lrmData = realData
 	}
 
 	// Note: The FetchKernelLRMData function already calculates the update status
-	// using the same DKMS version source as the main dashboard (packages.GetMaxSourceVersionsArchive).
+	// using the same DKMS version source as the main dashboard (packages.GetMaxSourceVersionsCached).
 	// No need to override it here.
 
 	// Create template
@@ -943,7 +1744,7 @@ func (ws *WebService) lrmVerifierHandler(w http.ResponseWriter, r *http.Request)
     <title>Linux Restricted Modules (L-R-M) Verifier</title>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <link href="{{.CDN.BootstrapCSS}}" rel="stylesheet">
+    <link href="{{.CDN.BootstrapCSS}}" rel="stylesheet"{{with .CDN.BootstrapCSSIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}>
     <style>
         .container-fluid { max-width: 1600px; }
         .table-success { background-color: #d1e7dd !important; }
@@ -1092,31 +1893,13 @@ func (ws *WebService) lrmVerifierHandler(w http.ResponseWriter, r *http.Request)
         </div>
     </div>
 
-    <script src="{{.CDN.BootstrapJS}}"></script>
+    <script src="{{.CDN.BootstrapJS}}"{{with .CDN.BootstrapJSIntegrity}} integrity="{{.}}" crossorigin="anonymous"{{end}}></script>
 </body>
 </html>
 `
 
 	// Create template with custom functions
-	tmpl := template.New("lrm").Funcs(template.FuncMap{
-		"eq": func(a, b string) bool {
-			return a == b
-		},
-		"contains": func(s, substr string) bool {
-			return strings.Contains(s, substr)
-		},
-		"simplifyDriver": func(driver string) string {
-			return lrm.SimplifyNvidiaDriverName(driver)
-		},
-		"simplifyDriverName": func(driverName string) string {
-			// Extract the driver branch (e.g., "535", "470-server") from the full name
-			prefix := "nvidia-graphics-drivers-"
-			if strings.HasPrefix(driverName, prefix) {
-				return driverName[len(prefix):]
-			}
-			return driverName
-		},
-	})
+	tmpl := template.New("lrm").Funcs(TemplateFunctions())
 
 	var err error
 	tmpl, err = tmpl.Parse(lrmTemplate)
@@ -1135,15 +1918,13 @@ func (ws *WebService) lrmVerifierHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Execute template
-	if err := tmpl.Execute(w, templateData); err != nil {
-		http.Error(w, fmt.Sprintf("Template execution error: %v", err), http.StatusInternalServerError)
-		return
-	}
+	renderPage(w, tmpl, templateData)
 }
 
 // statisticsPageHandler serves the statistics dashboard HTML page
 func (ws *WebService) statisticsPageHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	locale := requestLocale(w, r, ws.config)
 
 	// Read the statistics template
 	templatePath := filepath.Join(ws.templatePath, "statistics.html")
@@ -1154,7 +1935,7 @@ func (ws *WebService) statisticsPageHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Parse and execute the template
-	tmpl, err := template.New("statistics").Parse(string(templateContent))
+	tmpl, err := template.New("statistics").Funcs(LocalizedFunctions(locale, requestTimezone(w, r, ws.config))).Parse(string(templateContent))
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error parsing statistics template: %v", err), http.StatusInternalServerError)
 		return
@@ -1166,10 +1947,7 @@ func (ws *WebService) statisticsPageHandler(w http.ResponseWriter, r *http.Reque
 	}{
 		CDN: GetCDNResources(ws.config),
 	}
-	if err := tmpl.Execute(w, templateData); err != nil {
-		http.Error(w, fmt.Sprintf("Error executing statistics template: %v", err), http.StatusInternalServerError)
-		return
-	}
+	renderPage(w, tmpl, templateData)
 }
 
 // Helper functions for L-R-M verifier
@@ -1190,6 +1968,8 @@ func generateLRMDataFromSupportedReleases(supportedReleases []releases.Supported
 		"oracular": "24.10",
 	}
 
+	develCodename := releases.DevelCodename()
+
 	// Common kernel sources that have L-R-M packages
 	kernelSources := []string{"linux", "linux-aws", "linux-azure", "linux-gcp", "linux-oracle"}
 
@@ -1201,15 +1981,19 @@ func generateLRMDataFromSupportedReleases(supportedReleases []releases.Supported
 			continue
 		}
 
-		for codename, isSupported := range release.IsSupported {
-			if isSupported {
-				releasesByCodename[codename] = append(releasesByCodename[codename], release)
-			}
+		for _, codename := range release.SupportedCodenames() {
+			releasesByCodename[codename] = append(releasesByCodename[codename], release)
 		}
 	}
 
+	codenames := make([]string, 0, len(releasesByCodename))
+	for codename := range releasesByCodename {
+		codenames = append(codenames, codename)
+	}
+
 	// Generate L-R-M data for each codename and kernel source combination
-	for codename, releases := range releasesByCodename {
+	for _, codename := range packages.SortSeries(codenames) {
+		releases := releasesByCodename[codename]
 		series, exists := codenameToSeries[codename]
 		if !exists {
 			series = codename
@@ -1249,7 +2033,7 @@ func generateLRMDataFromSupportedReleases(supportedReleases []releases.Supported
 					LRMPackages:          []string{lrmPackage}, // Actual L-R-M package
 					HasLRM:               true,
 					Supported:            true,
-					Development:          codename == "devel" || codename == "resolute",
+					Development:          codename == develCodename,
 					LTS:                  series == "20.04" || series == "22.04" || series == "24.04",
 					ESM:                  series == "18.04",
 					LatestLRMVersion:     "1.0.0",       // Will be updated later
@@ -0,0 +1,200 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/sru"
+	"nvidia_driver_monitor/pkg/debversion"
+)
+
+// versionSeriesPattern finds the Ubuntu release in a backport version, e.g. "0ubuntu0.24.04.1"
+var versionSeriesPattern = regexp.MustCompile(`ubuntu\d*\.(\d{2}\.\d{2})`)
+
+// CycleEstimate is an SRU cycle an upload could land in
+type CycleEstimate struct {
+	Name            string `json:"name"`
+	CutoffDate      string `json:"cutoff_date"`
+	ReleaseDate     string `json:"release_date"`
+	Predicted       bool   `json:"predicted"`
+	DaysUntilCutoff int    `json:"days_until_cutoff"`
+}
+
+// LRMRebuild is a kernel whose linux-restricted-modules would be rebuilt for the upload
+type LRMRebuild struct {
+	Series        string `json:"series"`
+	Source        string `json:"source"`
+	Routing       string `json:"routing"`
+	KernelVersion string `json:"kernel_version"`
+	DriverVersion string `json:"driver_version"` // Driver version the kernel currently carries
+}
+
+// PromotionSimulation estimates how a hypothetical upload would move through the pockets
+type PromotionSimulation struct {
+	Package    string `json:"package"`
+	Version    string `json:"version"`
+	Series     string `json:"series,omitempty"` // Codename the upload targets; empty means every series
+	UploadDate string `json:"upload_date"`
+	// TargetCycle is the first SRU cycle whose cutoff is after the upload, NextCycle the one after
+	TargetCycle         *CycleEstimate `json:"target_cycle"`
+	NextCycle           *CycleEstimate `json:"next_cycle,omitempty"`
+	ExpectedUpdatesDate string         `json:"expected_updates_date,omitempty"`
+	Rebuilds            []LRMRebuild   `json:"rebuilds"`
+	Warnings            []string       `json:"warnings,omitempty"`
+}
+
+// cycleEstimate describes an SRU cycle relative to the upload date
+func cycleEstimate(cycle *sru.SRUCycle, uploadDate time.Time) *CycleEstimate {
+	if cycle == nil {
+		return nil
+	}
+	estimate := &CycleEstimate{
+		Name:        cycle.Name,
+		CutoffDate:  cycle.CutoffDate,
+		ReleaseDate: cycle.ReleaseDate,
+		Predicted:   cycle.PredictedCycle,
+	}
+	if cutoff, err := time.Parse("2006-01-02", cycle.CutoffDate); err == nil {
+		estimate.DaysUntilCutoff = int(cutoff.Sub(uploadDate).Hours() / 24)
+	}
+	return estimate
+}
+
+// simulatePromotion computes the SRU cycle an upload made on uploadDate would target, when it
+// would reach updates, and the kernels carrying the driver whose L-R-M would be rebuilt.
+// pkg and lrmData may be nil when the package or the kernel data is not known yet.
+func simulatePromotion(cycles *sru.SRUCycles, pkg *PackageData, lrmData *lrm.LRMVerifierData, packageName, ver, series string, uploadDate time.Time) PromotionSimulation {
+	simulation := PromotionSimulation{
+		Package:    packageName,
+		Version:    ver,
+		Series:     series,
+		UploadDate: uploadDate.Format("2006-01-02"),
+		Rebuilds:   []LRMRebuild{},
+	}
+
+	// A backport version names its release, e.g. 570.195.03-0ubuntu0.24.04.1 is for 24.04
+	release := ""
+	if match := versionSeriesPattern.FindStringSubmatch(ver); match != nil {
+		release = match[1]
+	}
+
+	if cycles != nil {
+		target := cycles.GetMinimumCutoffAfterDate(simulation.UploadDate)
+		simulation.TargetCycle = cycleEstimate(target, uploadDate)
+		if target != nil {
+			simulation.ExpectedUpdatesDate = target.ReleaseDate
+			simulation.NextCycle = cycleEstimate(cycles.GetMinimumCutoffAfterDate(target.CutoffDate), uploadDate)
+		}
+	}
+	if simulation.TargetCycle == nil {
+		simulation.Warnings = append(simulation.Warnings, "no SRU cycle with a cutoff after the upload date is known")
+	}
+
+	if pkg == nil {
+		simulation.Warnings = append(simulation.Warnings, fmt.Sprintf("%s is not tracked by the dashboard", packageName))
+	} else {
+		for _, row := range pkg.Series {
+			if series != "" && row.Series != series {
+				continue
+			}
+			// Versions without a backport release are uploaded to the development series
+			if row.Freeze != "" && release == "" {
+				simulation.Warnings = append(simulation.Warnings,
+					fmt.Sprintf("devel series %s is frozen: the upload needs a freeze exception (FFe)", row.Series))
+			}
+			for _, current := range []string{row.UpdatesSecurity, row.Proposed} {
+				if isArchiveVersion(current) && !debversion.OlderThan(current, ver) && sameRelease(current, release) {
+					simulation.Warnings = append(simulation.Warnings,
+						fmt.Sprintf("%s is not newer than %s already in %s", ver, current, row.Series))
+					break
+				}
+			}
+		}
+	}
+
+	if lrmData != nil {
+		for _, result := range lrmData.KernelResults {
+			if series != "" && result.Codename != series {
+				continue
+			}
+			if release != "" && result.Series != release {
+				continue
+			}
+			for _, driver := range result.NvidiaDriverStatuses {
+				if driver.DriverName != packageName {
+					continue
+				}
+				simulation.Rebuilds = append(simulation.Rebuilds, LRMRebuild{
+					Series:        result.Codename,
+					Source:        result.Source,
+					Routing:       result.Routing,
+					KernelVersion: result.SourceVersion,
+					DriverVersion: driver.DSCVersion,
+				})
+			}
+		}
+	}
+
+	return simulation
+}
+
+// sameRelease reports whether a version was built for the release; any release matches when it is unknown
+func sameRelease(ver, release string) bool {
+	return release == "" || strings.Contains(ver, "ubuntu0."+release) || strings.Contains(ver, "ubuntu1."+release)
+}
+
+// promotionSimulatorHandler answers "what if" questions about a hypothetical upload
+// (/api/v1/simulate/promotion?package={name}&version={version}&date={YYYY-MM-DD}&series={codename})
+func (ws *WebService) promotionSimulatorHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	packageName := query.Get("package")
+	ver := query.Get("version")
+	if packageName == "" || ver == "" {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "package and version are required")
+		return
+	}
+	if !strings.HasPrefix(packageName, "nvidia-graphics-drivers-") {
+		packageName = "nvidia-graphics-drivers-" + packageName
+	}
+	if !debversion.Valid(ver) {
+		problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Invalid version")
+		return
+	}
+
+	uploadDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if date := query.Get("date"); date != "" {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "date must be formatted as YYYY-MM-DD")
+			return
+		}
+		uploadDate = parsed
+	}
+
+	index, _, isInitialized := ws.getPackageIndex()
+	if !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+	pkg := index.byName[packageName]
+	// Without kernel data the simulation still reports the cycles
+	lrmData, _ := lrm.GetCachedLRMData()
+
+	simulation := simulatePromotion(ws.getUpstreamData().sruCycles, pkg, lrmData, packageName, ver, query.Get("series"), uploadDate)
+	if err := json.NewEncoder(w).Encode(simulation); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
@@ -0,0 +1,191 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/slo"
+	"nvidia_driver_monitor/internal/stats"
+)
+
+// branchFromPackage returns the driver branch of a package, e.g. "570-server"
+func branchFromPackage(packageName string) string {
+	return releases.BranchFromPackage(packageName)
+}
+
+// trackHistory records the current state of the given packages and re-evaluates their SLOs and
+// outdated streaks
+func (ws *WebService) trackHistory(pkgs []*PackageData, now time.Time) {
+	if ws.historyStore == nil {
+		return
+	}
+
+	var observations []history.Observation
+	for _, pkg := range pkgs {
+		// Stale data was observed earlier and must not be recorded as today's state
+		if pkg.StaleSince != nil {
+			continue
+		}
+		for _, row := range pkg.Series {
+			upstreamDate := row.ReleaseDate
+			if upstreamDate == "-" {
+				upstreamDate = ""
+			}
+			observations = append(observations, history.Observation{
+				Package:      pkg.PackageName,
+				Series:       row.Series,
+				Published:    row.UpdatesSecurity,
+				Proposed:     row.Proposed,
+				Upstream:     row.UpstreamVersion,
+				UpstreamDate: upstreamDate,
+				Outdated:     row.UpdatesColor == "danger" || row.UpdatesColor == acknowledgedColor,
+				Component:    row.Component,
+				Section:      row.Section,
+			})
+		}
+	}
+	if err := ws.historyStore.Record(now, observations); err != nil {
+		log.Printf("Warning: Failed to persist history: %v", err)
+	}
+
+	for _, pkg := range pkgs {
+		pkg.SLO = ws.evaluateSLO(pkg.PackageName, now)
+	}
+	ws.applyOutdatedStreaks(pkgs, now)
+}
+
+// evaluateSLO computes the SLO status of a package's branch, or nil when no target applies
+func (ws *WebService) evaluateSLO(packageName string, now time.Time) *slo.Status {
+	if ws.config == nil || ws.historyStore == nil {
+		return nil
+	}
+	branch := branchFromPackage(packageName)
+	target := ws.config.SLO.TargetFor(branch)
+	if target == nil {
+		return nil
+	}
+
+	observations := make(map[string][]history.Observation)
+	for _, series := range ws.historyStore.SeriesNames(packageName) {
+		observations[series] = ws.historyStore.Series(packageName, series)
+	}
+	return slo.Evaluate(branch, target, observations, now)
+}
+
+// metricsHandler exposes dashboard and SLO state in the Prometheus text format
+func (ws *WebService) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	pkgs, lastUpdated, isInitialized := ws.getCachedPackages()
+	sorted := make([]*PackageData, len(pkgs))
+	copy(sorted, pkgs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PackageName < sorted[j].PackageName })
+
+	var b strings.Builder
+
+	b.WriteString("# HELP nvidia_monitor_data_age_seconds Seconds since the dashboard data was last refreshed\n")
+	b.WriteString("# TYPE nvidia_monitor_data_age_seconds gauge\n")
+	if isInitialized {
+		fmt.Fprintf(&b, "nvidia_monitor_data_age_seconds %.0f\n", time.Since(lastUpdated).Seconds())
+	}
+
+	b.WriteString("# HELP nvidia_monitor_outdated_series Number of series whose published version is behind upstream\n")
+	b.WriteString("# TYPE nvidia_monitor_outdated_series gauge\n")
+	for _, pkg := range sorted {
+		fmt.Fprintf(&b, "nvidia_monitor_outdated_series{package=%q} %d\n", pkg.PackageName, pkg.OutdatedSeries())
+	}
+
+	b.WriteString("# HELP nvidia_monitor_slo_target_days Days allowed between an upstream release and its publication\n")
+	b.WriteString("# TYPE nvidia_monitor_slo_target_days gauge\n")
+	for _, pkg := range sorted {
+		if pkg.SLO != nil {
+			fmt.Fprintf(&b, "nvidia_monitor_slo_target_days{branch=%q} %d\n", pkg.SLO.Branch, pkg.SLO.TargetDays)
+		}
+	}
+
+	b.WriteString("# HELP nvidia_monitor_slo_compliance_ratio Ratio of upstream releases in the window published within the target\n")
+	b.WriteString("# TYPE nvidia_monitor_slo_compliance_ratio gauge\n")
+	for _, pkg := range sorted {
+		if pkg.SLO != nil {
+			fmt.Fprintf(&b, "nvidia_monitor_slo_compliance_ratio{branch=%q} %g\n", pkg.SLO.Branch, pkg.SLO.Compliance)
+		}
+	}
+
+	b.WriteString("# HELP nvidia_monitor_slo_breached Whether the latest upstream release of a series missed the target\n")
+	b.WriteString("# TYPE nvidia_monitor_slo_breached gauge\n")
+	for _, pkg := range sorted {
+		if pkg.SLO == nil {
+			continue
+		}
+		for _, ss := range pkg.SLO.Series {
+			breached := 0
+			if ss.State == slo.StateBreached {
+				breached = 1
+			}
+			fmt.Fprintf(&b, "nvidia_monitor_slo_breached{branch=%q,series=%q} %d\n", pkg.SLO.Branch, ss.Series, breached)
+		}
+	}
+
+	// Alerts silenced by a maintenance window are left out, so external alerting honours it too
+	b.WriteString("# HELP nvidia_monitor_alert_firing Alerts raised by the monitor that are firing and delivered\n")
+	b.WriteString("# TYPE nvidia_monitor_alert_firing gauge\n")
+	for _, alert := range alerts.Active() {
+		if !alert.Silenced {
+			fmt.Fprintf(&b, "nvidia_monitor_alert_firing{name=%q,severity=%q} 1\n", alert.Name, alert.Severity)
+		}
+	}
+
+	writeUpstreamMetrics(&b, stats.GetStatsCollector().GetTotals())
+	if ws.janitor != nil {
+		writeCacheMetrics(&b, ws.janitor.Usage())
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+// writeUpstreamMetrics writes the upstream request counters since the process started, so the
+// share of each upstream quota and 5xx spikes can be graphed
+func writeUpstreamMetrics(b *strings.Builder, totals map[string]*stats.APIStats) {
+	domains := make([]string, 0, len(totals))
+	for domain := range totals {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	b.WriteString("# HELP nvidia_monitor_upstream_responses_total Upstream HTTP responses by domain and status code\n")
+	b.WriteString("# TYPE nvidia_monitor_upstream_responses_total counter\n")
+	for _, domain := range domains {
+		codes := make([]string, 0, len(totals[domain].StatusCodes))
+		for code := range totals[domain].StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			fmt.Fprintf(b, "nvidia_monitor_upstream_responses_total{domain=%q,code=%q} %d\n", domain, code, totals[domain].StatusCodes[code])
+		}
+	}
+
+	b.WriteString("# HELP nvidia_monitor_upstream_failed_requests_total Upstream requests that got no response after all retries\n")
+	b.WriteString("# TYPE nvidia_monitor_upstream_failed_requests_total counter\n")
+	for _, domain := range domains {
+		fmt.Fprintf(b, "nvidia_monitor_upstream_failed_requests_total{domain=%q} %d\n", domain, totals[domain].FailedReqs)
+	}
+
+	b.WriteString("# HELP nvidia_monitor_upstream_response_bytes_total Bytes of upstream response bodies read\n")
+	b.WriteString("# TYPE nvidia_monitor_upstream_response_bytes_total counter\n")
+	for _, domain := range domains {
+		fmt.Fprintf(b, "nvidia_monitor_upstream_response_bytes_total{domain=%q} %d\n", domain, totals[domain].BytesTransferred)
+	}
+}
@@ -0,0 +1,189 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/sru"
+	"nvidia_driver_monitor/internal/utils"
+)
+
+// snapshotVersion is bumped when the snapshot format changes incompatibly
+const snapshotVersion = 1
+
+// Snapshot is the loaded state of an instance, enough for a new replica to serve the
+// dashboard without waiting for the upstreams
+type Snapshot struct {
+	Version           int                         `json:"version"`
+	LastUpdated       time.Time                   `json:"last_updated"` // When the packages were generated
+	DevelSeries       string                      `json:"devel_series,omitempty"`
+	SupportedReleases []releases.SupportedRelease `json:"supported_releases"`
+	UDAEntries        []drivers.DriverEntry       `json:"uda_entries"`
+	TegraEntries      []drivers.DriverEntry       `json:"tegra_entries,omitempty"`
+	AllBranches       drivers.AllBranches         `json:"all_branches"`
+	SRUCycles         *sru.SRUCycles              `json:"sru_cycles,omitempty"`
+	Packages          []*PackageData              `json:"packages"`
+	PackageErrors     []*PackageError             `json:"package_errors"`
+	Issues            []DataIssue                 `json:"issues"`
+}
+
+// peerConfig returns the peer settings, or the defaults without a configuration
+func (ws *WebService) peerConfig() *config.PeerConfig {
+	if ws.config == nil {
+		return &config.DefaultConfig().Peer
+	}
+	return &ws.config.Peer
+}
+
+// getSnapshot captures the cached data; ok is false until the first data load completed
+func (ws *WebService) getSnapshot() (*Snapshot, bool) {
+	ws.cacheMux.RLock()
+	defer ws.cacheMux.RUnlock()
+	if !ws.cache.IsInitialized {
+		return nil, false
+	}
+	return &Snapshot{
+		Version:           snapshotVersion,
+		LastUpdated:       ws.cache.LastUpdated,
+		DevelSeries:       releases.DevelCodename(),
+		SupportedReleases: ws.supportedReleases,
+		UDAEntries:        ws.udaEntries,
+		TegraEntries:      ws.tegraEntries,
+		AllBranches:       ws.allBranches,
+		SRUCycles:         ws.sruCycles,
+		Packages:          ws.cache.AllPackages,
+		PackageErrors:     ws.cache.PackageErrors,
+		Issues:            ws.cache.Issues,
+	}, true
+}
+
+// snapshotHandler returns the cached data for priming another instance (/api/v1/snapshot).
+// When a peer token is configured, requests must present it as a bearer token.
+func (ws *WebService) snapshotHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		problems.Write(w, http.StatusMethodNotAllowed, problems.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if token := ws.peerConfig().GetToken(); token != "" {
+		presented := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			problems.Write(w, http.StatusUnauthorized, problems.Unauthorized, "Invalid or missing peer token")
+			return
+		}
+	}
+
+	snapshot, ok := ws.getSnapshot()
+	if !ok {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Data is still loading")
+		return
+	}
+	checkSchema(schemaSnapshot, snapshot)
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// fetchSnapshot downloads the snapshot of the peer at baseURL
+func fetchSnapshot(client *http.Client, baseURL, token string) (*Snapshot, error) {
+	url := strings.TrimRight(baseURL, "/") + "/api/v1/snapshot"
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if utils.HTTPUserAgent != "" {
+		req.Header.Set("User-Agent", utils.HTTPUserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot from %s: %v", url, err)
+	}
+	if snapshot.Version != snapshotVersion {
+		return nil, fmt.Errorf("peer snapshot version %d is not supported, expected %d", snapshot.Version, snapshotVersion)
+	}
+	return &snapshot, nil
+}
+
+// applySnapshot loads a peer snapshot into the cache, marking its datasets loaded. The
+// packages keep the peer's generation time, so the data age shown stays truthful.
+func (ws *WebService) applySnapshot(snapshot *Snapshot) {
+	if snapshot.DevelSeries != "" && releases.DevelCodename() == "" {
+		releases.SetDevelCodename(snapshot.DevelSeries)
+	}
+
+	for _, name := range []string{datasetUDA, datasetERD, datasetPackages} {
+		ws.setDatasetResult(name, nil)
+	}
+	if snapshot.SRUCycles != nil {
+		ws.setDatasetResult(datasetSRU, nil)
+	}
+	if releases.TegraEnabled() && snapshot.TegraEntries != nil {
+		ws.setDatasetResult(datasetTegra, nil)
+	}
+
+	ws.cacheMux.Lock()
+	ws.supportedReleases = snapshot.SupportedReleases
+	ws.udaEntries = snapshot.UDAEntries
+	ws.tegraEntries = snapshot.TegraEntries
+	ws.allBranches = snapshot.AllBranches
+	if ws.allBranches == nil {
+		ws.allBranches = make(drivers.AllBranches)
+	}
+	ws.sruCycles = snapshot.SRUCycles
+	ws.cache.setPackages(snapshot.Packages)
+	ws.cache.PackageErrors = snapshot.PackageErrors
+	ws.cache.Issues = snapshot.Issues
+	ws.cache.LastUpdated = snapshot.LastUpdated
+	ws.cache.IsInitialized = true
+	ws.cacheMux.Unlock()
+}
+
+// primeFromPeer loads the cache from the configured peer instead of the upstreams. It returns
+// false, leaving the cache untouched, when no peer is configured or its snapshot is unusable.
+func (ws *WebService) primeFromPeer(now time.Time) bool {
+	peer := ws.peerConfig()
+	if peer.URL == "" {
+		return false
+	}
+
+	ws.refreshMux.Lock()
+	defer ws.refreshMux.Unlock()
+
+	client := utils.NewHTTPClient()
+	client.Timeout = peer.GetTimeout()
+	snapshot, err := fetchSnapshot(client, peer.URL, peer.GetToken())
+	if err != nil {
+		log.Printf("Warning: Could not prime the cache from peer %s, loading from the upstreams: %v", peer.URL, err)
+		return false
+	}
+	if age := now.Sub(snapshot.LastUpdated); age > peer.GetMaxAge() {
+		log.Printf("Warning: Peer %s snapshot is %v old (max_age %v), loading from the upstreams", peer.URL, age.Round(time.Second), peer.GetMaxAge())
+		return false
+	}
+
+	ws.applySnapshot(snapshot)
+	log.Printf("Primed the cache from peer %s: %d packages from %s", peer.URL, len(snapshot.Packages), snapshot.LastUpdated.Format(time.RFC3339))
+	return true
+}
@@ -0,0 +1,132 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"nvidia_driver_monitor/internal/lrm"
+)
+
+// fieldSet is the set of JSON fields requested with ?fields=, nil when all fields are wanted
+type fieldSet map[string]bool
+
+// parseFields reads a comma separated ?fields= list and checks every name against the JSON
+// fields of the given record types
+func parseFields(r *http.Request, types ...interface{}) (fieldSet, error) {
+	param := r.URL.Query().Get("fields")
+	if param == "" {
+		return nil, nil
+	}
+	known := make(map[string]bool)
+	for _, t := range types {
+		for name := range jsonFieldNames(reflect.TypeOf(t)) {
+			known[name] = true
+		}
+	}
+	fields := make(fieldSet)
+	for _, name := range strings.Split(param, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// jsonFieldNames returns the JSON names of a struct type's exported fields
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// pick returns the JSON object of v reduced to the requested fields and the always kept ones
+func (f fieldSet) pick(v interface{}, keep ...string) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	for name := range object {
+		if !f[name] && !contains(keep, name) {
+			delete(object, name)
+		}
+	}
+	return object, nil
+}
+
+// contains reports whether list holds value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sparsePackage reduces a package to the requested fields. Row fields such as UpdatesSecurity
+// select columns of every series row; rows always keep their Series name.
+func (f fieldSet) sparsePackage(pkg *PackageData) (map[string]interface{}, error) {
+	packageFields := jsonFieldNames(reflect.TypeOf(PackageData{}))
+	rowFields := make(fieldSet)
+	for name := range f {
+		if !packageFields[name] || name == "Series" {
+			rowFields[name] = true
+		}
+	}
+
+	object, err := f.pick(pkg, "PackageName")
+	if err != nil {
+		return nil, err
+	}
+	if len(rowFields) == 0 {
+		return object, nil
+	}
+	rows := make([]map[string]interface{}, 0, len(pkg.Series))
+	for i := range pkg.Series {
+		row, err := rowFields.pick(&pkg.Series[i], "Series")
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	object["Series"] = rows
+	return object, nil
+}
+
+// sparseKernels reduces kernel records to the requested fields, keeping Series and Source
+func (f fieldSet) sparseKernels(results []lrm.KernelLRMResult) ([]map[string]interface{}, error) {
+	kernels := make([]map[string]interface{}, 0, len(results))
+	for i := range results {
+		kernel, err := f.pick(&results[i], "Series", "Source")
+		if err != nil {
+			return nil, err
+		}
+		kernels = append(kernels, kernel)
+	}
+	return kernels, nil
+}
@@ -0,0 +1,42 @@
+package web
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"nvidia_driver_monitor/internal/history"
+)
+
+// applyOutdatedStreaks sets how long each outdated row of the packages has been outdated,
+// according to the history store
+func (ws *WebService) applyOutdatedStreaks(pkgs []*PackageData, now time.Time) {
+	today, _ := time.ParseInLocation(history.DateFormat, now.Format(history.DateFormat), now.Location())
+	for _, pkg := range pkgs {
+		for i := range pkg.Series {
+			row := &pkg.Series[i]
+			row.OutdatedSince, row.OutdatedDays = "", 0
+			if row.UpdatesColor != "danger" {
+				continue
+			}
+			since := ws.historyStore.OutdatedSince(pkg.PackageName, row.Series)
+			sinceDate, err := time.ParseInLocation(history.DateFormat, since, now.Location())
+			if err != nil {
+				continue
+			}
+			row.OutdatedSince = since
+			row.OutdatedDays = int(math.Round(today.Sub(sinceDate).Hours() / 24))
+		}
+	}
+}
+
+// sortLongestOutdatedFirst returns the packages ordered by how long their longest outdated row
+// has been outdated, keeping the configured order otherwise
+func sortLongestOutdatedFirst(pkgs []*PackageData) []*PackageData {
+	sorted := make([]*PackageData, len(pkgs))
+	copy(sorted, pkgs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LongestOutdatedDays() > sorted[j].LongestOutdatedDays()
+	})
+	return sorted
+}
@@ -2,10 +2,17 @@ package web
 
 import (
 	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/i18n"
 	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/pkg/debversion"
 )
 
 // TemplateFunctions returns a map of custom template functions
@@ -17,6 +24,9 @@ func TemplateFunctions() template.FuncMap {
 		"contains": func(s, substr string) bool {
 			return strings.Contains(s, substr)
 		},
+		"versionKey": func(v string) string {
+			return debversion.SortKey(v)
+		},
 		"simplifyDriver": func(driver string) string {
 			return lrm.SimplifyNvidiaDriverName(driver)
 		},
@@ -31,18 +41,70 @@ func TemplateFunctions() template.FuncMap {
 	}
 }
 
-// GetCDNResources returns a map of CDN resources for templates
+// LocalizedFunctions returns the translation template functions of a locale and time zone: "t"
+// translates a message, formatting its arguments, "locale" returns the locale for the lang
+// attribute, and "datetime" and "clock" show a timestamp, or only its time, in the time zone
+func LocalizedFunctions(locale string, loc *time.Location) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, args ...interface{}) string {
+			return i18n.T(locale, key, args...)
+		},
+		"locale": func() string {
+			return locale
+		},
+		"datetime": func(t time.Time) string {
+			return displayTime(t, locale, loc)
+		},
+		"clock": func(t time.Time) string {
+			return t.In(loc).Format(i18n.T(locale, "format.time"))
+		},
+	}
+}
+
+// requestLocale picks the UI locale of a request from its Accept-Language header, falling back
+// to the configured default, and announces it in the response headers
+func requestLocale(w http.ResponseWriter, r *http.Request, cfg *config.Config) string {
+	fallback := i18n.DefaultLocale
+	if cfg != nil {
+		fallback = cfg.I18n.GetDefaultLocale()
+	}
+	locale := i18n.Negotiate(r.Header.Get("Accept-Language"), fallback)
+	w.Header().Set("Content-Language", locale)
+	w.Header().Add("Vary", "Accept-Language")
+	return locale
+}
+
+// GetCDNResources returns a map of CDN resources for templates. Each asset comes with its
+// integrity hash under the asset name followed by "Integrity", and points to the vendored copy
+// under /static when the CDNs are configured offline.
 func GetCDNResources(cfg *config.Config) map[string]string {
 	if cfg == nil {
 		cfg = config.DefaultConfig()
 	}
-	return map[string]string{
-		"BootstrapCSS": cfg.URLs.CDN.BootstrapCSS,
-		"BootstrapJS":  cfg.URLs.CDN.BootstrapJS,
-		"ChartJS":      cfg.URLs.CDN.ChartJS,
-		"VanillaCSS":   cfg.URLs.CDN.VanillaCSS,
+	resources := map[string]string{
 		"UbuntuAssets": cfg.URLs.Ubuntu.AssetsBaseURL,
 	}
+	for name, asset := range cfg.URLs.CDN.Assets() {
+		resources[name] = asset[0]
+		if cfg.URLs.CDN.Offline {
+			resources[name] = "/static/" + config.VendorFile(asset[0])
+		}
+		resources[name+"Integrity"] = asset[1]
+	}
+	return resources
+}
+
+// missingVendoredAssets returns the vendored asset files missing from staticDir
+func missingVendoredAssets(cdn config.CDNURLs, staticDir string) []string {
+	var missing []string
+	for _, asset := range cdn.Assets() {
+		file := filepath.Join(staticDir, config.VendorFile(asset[0]))
+		if _, err := os.Stat(file); err != nil {
+			missing = append(missing, file)
+		}
+	}
+	sort.Strings(missing)
+	return missing
 }
 
 // TemplateData holds data passed to templates including configuration
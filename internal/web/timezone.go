@@ -0,0 +1,173 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	// Time zones are resolved the same way on hosts without a zoneinfo database
+	_ "time/tzdata"
+
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/i18n"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// timezoneCookie remembers the time zone picked with ?tz= on the pages that follow
+const timezoneCookie = "tz"
+
+// displaySuffix is appended to the key of a timestamp for its display string in the API
+const displaySuffix = "_display"
+
+// requestTimezone returns the time zone a request shows dates in: the ?tz= parameter, which is
+// remembered in a cookie for the next pages, then that cookie, then the configured default.
+// Unknown names are ignored.
+func requestTimezone(w http.ResponseWriter, r *http.Request, cfg *config.Config) *time.Location {
+	if name := r.URL.Query().Get("tz"); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			http.SetCookie(w, &http.Cookie{Name: timezoneCookie, Value: loc.String(), Path: "/", MaxAge: 365 * 24 * 3600, SameSite: http.SameSiteLaxMode})
+			return loc
+		}
+	}
+	if cookie, err := r.Cookie(timezoneCookie); err == nil {
+		if loc, err := time.LoadLocation(cookie.Value); err == nil {
+			return loc
+		}
+	}
+	if cfg != nil {
+		if loc, err := time.LoadLocation(cfg.I18n.GetDefaultTimezone()); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// displayTime formats a timestamp in a time zone with the date layout of a locale, which
+// always names the zone; the zero time is shown as empty
+func displayTime(t time.Time, locale string, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(i18n.T(locale, "format.datetime"))
+}
+
+// DisplayTimesMiddleware adds a display string next to every RFC3339 timestamp of the JSON
+// objects an API request (/api...) with ?tz= gets back, e.g. "last_updated_display" next to
+// "last_updated", in that time zone and the language of Accept-Language. Other responses pass
+// through untouched.
+func DisplayTimesMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := r.URL.Query().Get("tz")
+			if name == "" || !strings.HasPrefix(r.URL.Path, "/api") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			loc, err := time.LoadLocation(name)
+			if err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				problems.Write(w, http.StatusBadRequest, problems.ValidationFailed, "Unknown time zone in tz")
+				return
+			}
+			fallback := i18n.DefaultLocale
+			if cfg != nil {
+				fallback = cfg.I18n.GetDefaultLocale()
+			}
+			locale := i18n.Negotiate(r.Header.Get("Accept-Language"), fallback)
+
+			writer := &displayTimesWriter{ResponseWriter: w}
+			next.ServeHTTP(writer, r)
+			writer.finish(locale, loc)
+		})
+	}
+}
+
+// displayTimesWriter holds back JSON responses so their timestamps can be annotated; anything
+// else is written through as it comes
+type displayTimesWriter struct {
+	http.ResponseWriter
+	status  int
+	decided bool
+	buffer  *bytes.Buffer // Set while a JSON response is held back
+}
+
+func (w *displayTimesWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.buffer = &bytes.Buffer{}
+	}
+}
+
+func (w *displayTimesWriter) WriteHeader(status int) {
+	w.decide()
+	if w.buffer != nil {
+		w.status = status
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *displayTimesWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.buffer != nil {
+		return w.buffer.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// finish writes a held back response, annotated when it is a successful JSON document
+func (w *displayTimesWriter) finish(locale string, loc *time.Location) {
+	if w.buffer == nil {
+		return
+	}
+	body := w.buffer.Bytes()
+	if w.status < 300 {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var document interface{}
+		// Streamed responses of several documents are left as they are
+		if err := decoder.Decode(&document); err == nil && !decoder.More() {
+			if annotated, err := json.Marshal(addDisplayTimes(document, locale, loc)); err == nil {
+				body = append(annotated, '\n')
+			}
+		}
+	}
+	w.Header().Del("Content-Length")
+	w.Header().Add("Vary", "Accept-Language")
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	w.ResponseWriter.Write(body)
+}
+
+// addDisplayTimes walks a decoded JSON document and adds the display string of each RFC3339
+// timestamp member to its object
+func addDisplayTimes(value interface{}, locale string, loc *time.Location) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		display := make(map[string]string)
+		for key, member := range v {
+			if text, ok := member.(string); ok {
+				if t, err := time.Parse(time.RFC3339Nano, text); err == nil && !t.IsZero() {
+					display[key+displaySuffix] = displayTime(t, locale, loc)
+				}
+				continue
+			}
+			v[key] = addDisplayTimes(member, locale, loc)
+		}
+		for key, text := range display {
+			if _, exists := v[key]; !exists {
+				v[key] = text
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = addDisplayTimes(v[i], locale, loc)
+		}
+	}
+	return value
+}
@@ -0,0 +1,131 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"nvidia_driver_monitor/internal/tracker"
+)
+
+// trackerIssueTitle is the title of the issue opened for an outdated cell
+func trackerIssueTitle(packageName string, row *SeriesData) string {
+	return fmt.Sprintf("%s outdated in %s for %d days", packageName, row.Series, row.OutdatedDays)
+}
+
+// trackerIssueBody describes an outdated cell with links back to the dashboard and Launchpad
+func trackerIssueBody(packageName string, row *SeriesData, dashboardURL string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The published version of `%s` in %s has been outdated since %s.\n\n", packageName, row.Series, row.OutdatedSince)
+	b.WriteString("| Published | Proposed | Upstream | Target | Release date | SRU cycle |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n\n",
+		orDash(row.UpdatesSecurity), orDash(row.Proposed), orDash(row.UpstreamVersion),
+		orDash(row.TargetVersion), orDash(row.ReleaseDate), orDash(row.SRUCycle))
+	if dashboardURL != "" {
+		fmt.Fprintf(&b, "- Dashboard: %s/package?name=%s\n", strings.TrimSuffix(dashboardURL, "/"), url.QueryEscape(packageName))
+	}
+	fmt.Fprintf(&b, "- Launchpad: https://launchpad.net/ubuntu/+source/%s\n\n", packageName)
+	b.WriteString("This issue is closed automatically once the series is up to date or acknowledged.\n")
+	return b.String()
+}
+
+// runTrackerSync opens an issue for each cell outdated for longer than the threshold and closes
+// the issues of cells that recovered. Packages served from stale data are left alone. It
+// returns false when there is no data to compare yet.
+func (ws *WebService) runTrackerSync(now time.Time) bool {
+	pkgs, _, isInitialized := ws.getCachedPackages()
+	if !isInitialized {
+		return false
+	}
+	cfg := &ws.config.IssueTracker
+	threshold := cfg.GetThresholdDays()
+
+	byPackage := make(map[string]*PackageData, len(pkgs))
+	for _, pkg := range pkgs {
+		byPackage[pkg.PackageName] = pkg
+		if pkg.StaleSince != nil {
+			continue
+		}
+		for i := range pkg.Series {
+			row := &pkg.Series[i]
+			if row.UpdatesColor != "danger" || row.OutdatedDays < threshold {
+				continue
+			}
+			if _, ok := ws.trackerStore.Get(pkg.PackageName, row.Series); ok {
+				continue
+			}
+			issue, err := ws.trackerClient.Open(trackerIssueTitle(pkg.PackageName, row), trackerIssueBody(pkg.PackageName, row, cfg.DashboardURL))
+			if err != nil {
+				log.Printf("Warning: Failed to open tracker issue for %s/%s: %v", pkg.PackageName, row.Series, err)
+				continue
+			}
+			log.Printf("Opened tracker issue #%d for %s/%s", issue.Number, pkg.PackageName, row.Series)
+			ws.trackerStore.Set(tracker.TrackedIssue{
+				Package: pkg.PackageName, Series: row.Series, Number: issue.Number, URL: issue.URL, OpenedAt: now,
+			})
+		}
+	}
+
+	for _, tracked := range ws.trackerStore.List() {
+		pkg, ok := byPackage[tracked.Package]
+		if ok && pkg.StaleSince != nil {
+			continue
+		}
+		var reason string
+		switch row := findSeriesRow(pkg, tracked.Series); {
+		case !ok:
+			reason = fmt.Sprintf("`%s` is no longer tracked by the dashboard.", tracked.Package)
+		case row == nil || row.Removed != "":
+			reason = fmt.Sprintf("`%s` is no longer published in %s.", tracked.Package, tracked.Series)
+		case row.UpdatesColor == "acknowledged":
+			reason = fmt.Sprintf("`%s` in %s was acknowledged: %s", tracked.Package, tracked.Series, row.Acknowledged)
+		case row.UpdatesColor != "danger":
+			reason = fmt.Sprintf("`%s` in %s is up to date with %s.", tracked.Package, tracked.Series, row.UpdatesSecurity)
+		default:
+			continue
+		}
+		if err := ws.trackerClient.Close(tracked.Number, reason+" Closing."); err != nil {
+			log.Printf("Warning: Failed to close tracker issue #%d for %s/%s: %v", tracked.Number, tracked.Package, tracked.Series, err)
+			continue
+		}
+		log.Printf("Closed tracker issue #%d for %s/%s", tracked.Number, tracked.Package, tracked.Series)
+		ws.trackerStore.Remove(tracked.Package, tracked.Series)
+	}
+	return true
+}
+
+// findSeriesRow returns the row of a series in pkg, or nil
+func findSeriesRow(pkg *PackageData, series string) *SeriesData {
+	if pkg == nil {
+		return nil
+	}
+	for i := range pkg.Series {
+		if pkg.Series[i].Series == series {
+			return &pkg.Series[i]
+		}
+	}
+	return nil
+}
+
+// trackerLoop syncs the tracker issues once the first data is loaded, then at the configured interval
+func (ws *WebService) trackerLoop() {
+	timer := time.NewTimer(archiveCheckRetry)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			next := ws.config.IssueTracker.GetInterval()
+			if !ws.runTrackerSync(time.Now()) {
+				next = archiveCheckRetry
+			}
+			timer.Reset(next)
+		case <-ws.stopChan:
+			log.Printf("Stopping issue tracker loop...")
+			return
+		}
+	}
+}
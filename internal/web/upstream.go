@@ -0,0 +1,105 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// UpstreamUDAEntry is a UDA release parsed from the nvidia.com driver listing
+type UpstreamUDAEntry struct {
+	Version string `json:"version"`
+	Branch  string `json:"branch"` // Major version, e.g. "570"
+	Date    string `json:"date"`   // Release date as YYYY-MM-DD; empty when unknown
+	Beta    bool   `json:"beta"`
+	// Installer metadata for pre-staging mirrors; omitted when the listing shows no .run installer
+	Installer    string `json:"installer,omitempty"`
+	InstallerURL string `json:"installer_url,omitempty"`
+	Size         int64  `json:"size,omitempty"`   // Bytes as shown by the listing
+	SHA256       string `json:"sha256,omitempty"` // Only when NVIDIA publishes a .sha256sum file
+}
+
+// UpstreamERDBranch is a datacenter (ERD) branch parsed from the NVIDIA releases JSON
+type UpstreamERDBranch struct {
+	Branch   string               `json:"branch"`
+	Type     string               `json:"type"` // e.g. "production branch", "lts branch"
+	Releases []drivers.DriverInfo `json:"releases"`
+}
+
+// upstreamUDAEntries converts the scraped UDA releases, optionally restricted to one branch
+func upstreamUDAEntries(entries []drivers.DriverEntry, branch string) []UpstreamUDAEntry {
+	result := []UpstreamUDAEntry{}
+	for _, entry := range entries {
+		major := strings.SplitN(entry.Version, ".", 2)[0]
+		if branch != "" && major != branch {
+			continue
+		}
+		date := ""
+		if !entry.Date.IsZero() {
+			date = entry.Date.Format("2006-01-02")
+		}
+		result = append(result, UpstreamUDAEntry{
+			Version:      entry.Version,
+			Branch:       major,
+			Date:         date,
+			Beta:         entry.IsBeta,
+			Installer:    entry.Installer,
+			InstallerURL: entry.InstallerURL,
+			Size:         entry.InstallerSize,
+			SHA256:       entry.InstallerSHA256,
+		})
+	}
+	return result
+}
+
+// upstreamERDBranches converts the datacenter branches ordered by branch, optionally restricted to one
+func upstreamERDBranches(allBranches drivers.AllBranches, branch string) []UpstreamERDBranch {
+	result := []UpstreamERDBranch{}
+	for name, entry := range allBranches {
+		if branch != "" && name != strings.TrimSuffix(branch, "-server") {
+			continue
+		}
+		releases := entry.DriverInfo
+		if releases == nil {
+			releases = []drivers.DriverInfo{}
+		}
+		result = append(result, UpstreamERDBranch{Branch: name, Type: entry.Type, Releases: releases})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Branch < result[j].Branch })
+	return result
+}
+
+// upstreamUDAHandler returns the UDA releases scraped from nvidia.com (/api/v1/upstream/uda?branch={major})
+func (ws *WebService) upstreamUDAHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, _, isInitialized := ws.getCachedPackages(); !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+
+	entries := upstreamUDAEntries(ws.getUpstreamData().udaEntries, r.URL.Query().Get("branch"))
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"entries": entries, "count": len(entries)}); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
+
+// upstreamERDHandler returns the datacenter branches and releases published by NVIDIA
+// (/api/v1/upstream/erd?branch={major})
+func (ws *WebService) upstreamERDHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, _, isInitialized := ws.getCachedPackages(); !isInitialized {
+		problems.Write(w, http.StatusServiceUnavailable, problems.CacheWarming, "Service is still initializing")
+		return
+	}
+
+	branches := upstreamERDBranches(ws.getUpstreamData().allBranches, r.URL.Query().Get("branch"))
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"branches": branches, "count": len(branches)}); err != nil {
+		problems.Write(w, http.StatusInternalServerError, problems.Internal, "Failed to encode response")
+	}
+}
@@ -0,0 +1,153 @@
+package web
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/problems"
+)
+
+// ViewSummary counts the packages and series shown in a view
+type ViewSummary struct {
+	Packages       int `json:"packages"`
+	Series         int `json:"series"`
+	OutdatedSeries int `json:"outdated_series"`
+}
+
+// filterPackagesForView returns the packages of a view with their series restricted to it
+func filterPackagesForView(pkgs []*PackageData, view *config.ViewConfig) []*PackageData {
+	if view == nil {
+		return pkgs
+	}
+	var filtered []*PackageData
+	for _, pkg := range pkgs {
+		if !view.IncludesBranch(branchFromPackage(pkg.PackageName)) {
+			continue
+		}
+		scoped := *pkg
+		scoped.Series = nil
+		for _, row := range pkg.Series {
+			if view.IncludesSeries(row.Series) {
+				scoped.Series = append(scoped.Series, row)
+			}
+		}
+		filtered = append(filtered, &scoped)
+	}
+	return filtered
+}
+
+// filterErrorsForView returns the package errors of the branches in a view
+func filterErrorsForView(errors []*PackageError, view *config.ViewConfig) []*PackageError {
+	if view == nil {
+		return errors
+	}
+	var filtered []*PackageError
+	for _, pkgErr := range errors {
+		if view.IncludesBranch(branchFromPackage(pkgErr.PackageName)) {
+			filtered = append(filtered, pkgErr)
+		}
+	}
+	return filtered
+}
+
+// summarizeView counts the packages, series and outdated series of a package list
+func summarizeView(pkgs []*PackageData) ViewSummary {
+	summary := ViewSummary{Packages: len(pkgs)}
+	for _, pkg := range pkgs {
+		summary.Series += len(pkg.Series)
+		summary.OutdatedSeries += pkg.OutdatedSeries()
+	}
+	return summary
+}
+
+// viewToken returns the token presented with a request, from the Authorization header or the token parameter
+func viewToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return r.URL.Query().Get("token")
+}
+
+// authorizedForView reports whether a request may access a view
+func authorizedForView(r *http.Request, view *config.ViewConfig) bool {
+	if view.Token == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(viewToken(r)), []byte(view.Token)) == 1
+}
+
+// resolveView looks up the view named by the view parameter. It writes an error response and
+// returns false when the view is unknown or the request is not authorized; no parameter yields nil.
+func (ws *WebService) resolveView(w http.ResponseWriter, r *http.Request, name string) (*config.ViewConfig, bool) {
+	if name == "" {
+		return nil, true
+	}
+	var view *config.ViewConfig
+	if ws.config != nil {
+		view = ws.config.View(name)
+	}
+	if view == nil {
+		problems.Write(w, http.StatusNotFound, problems.NotFound, "View not found")
+		return nil, false
+	}
+	if !authorizedForView(r, view) {
+		problems.Write(w, http.StatusUnauthorized, problems.Unauthorized, "Invalid or missing view token")
+		return nil, false
+	}
+	return view, true
+}
+
+// viewHandler renders the dashboard scoped to a configured view (/view/{name})
+func (ws *WebService) viewHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/view/")
+	if name == "" || strings.Contains(name, "/") {
+		http.Error(w, "View not found", http.StatusNotFound)
+		return
+	}
+	ws.serveView(w, r, name)
+}
+
+// serveView renders the dashboard scoped to a configured view
+func (ws *WebService) serveView(w http.ResponseWriter, r *http.Request, name string) {
+	view, ok := ws.resolveView(w, r, name)
+	if !ok {
+		return
+	}
+
+	// Row requests made by the page carry the view, and the token it was opened with
+	query := url.Values{"view": {view.Name}}
+	if token := r.URL.Query().Get("token"); token != "" {
+		query.Set("token", token)
+	}
+	ws.renderIndex(w, r, view, query)
+}
+
+// viewAlertName is the alert raised when a view has too many outdated series
+func viewAlertName(view *config.ViewConfig) string {
+	return "view-" + view.Name + "-outdated"
+}
+
+// evaluateViewAlerts applies the alert rule of each view to freshly generated packages
+func (ws *WebService) evaluateViewAlerts(pkgs []*PackageData) {
+	if ws.config == nil {
+		return
+	}
+	for i := range ws.config.Views {
+		view := &ws.config.Views[i]
+		if view.AlertOutdatedSeries <= 0 {
+			continue
+		}
+		summary := summarizeView(filterPackagesForView(pkgs, view))
+		if summary.OutdatedSeries >= view.AlertOutdatedSeries {
+			alerts.Fire(viewAlertName(view), alerts.SeverityWarning,
+				fmt.Sprintf("%d series outdated in view %s (limit %d)", summary.OutdatedSeries, view.GetTitle(), view.AlertOutdatedSeries))
+		} else {
+			alerts.Resolve(viewAlertName(view))
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/faults"
+	"nvidia_driver_monitor/internal/lrm"
+)
+
+// dataRefreshInterval is how often the dashboard data is refreshed in the background
+const dataRefreshInterval = 5 * time.Minute
+
+// watchdogInterval is how often the watchdog checks data freshness
+const watchdogInterval = time.Minute
+
+// Alert names raised by the watchdog
+const (
+	alertDashboardStale = "dashboard-data-stale"
+	alertLRMStale       = "lrm-data-stale"
+)
+
+// staleFactor returns how many refresh intervals data may age before it is considered stale
+func (ws *WebService) staleFactor() int {
+	if ws.config != nil {
+		return ws.config.Alerts.GetStaleFactor()
+	}
+	return 3
+}
+
+// checkFreshness raises or resolves stale data alerts and returns whether the service is ready
+func (ws *WebService) checkFreshness(now time.Time) (bool, []string) {
+	var reasons []string
+	ready := true
+	factor := time.Duration(ws.staleFactor())
+
+	pkgs, lastUpdated, isInitialized := ws.getCachedPackages()
+	dashboardLimit := factor * dataRefreshInterval
+	switch {
+	case !isInitialized:
+		ready = false
+		reasons = append(reasons, "initial data load in progress")
+		if !ws.startedAt.IsZero() && now.Sub(ws.startedAt) > dashboardLimit {
+			alerts.Fire(alertDashboardStale, alerts.SeverityCritical,
+				fmt.Sprintf("initial data load has not completed after %v", now.Sub(ws.startedAt).Round(time.Second)))
+		}
+	case len(pkgs) == 0 && ws.datasetFailed(datasetPackages):
+		// No package was ever loaded: the dashboard only shows errors
+		ready = false
+		reasons = append(reasons, "no package could be loaded from Launchpad yet")
+		if !ws.startedAt.IsZero() && now.Sub(ws.startedAt) > dashboardLimit {
+			alerts.Fire(alertDashboardStale, alerts.SeverityCritical,
+				fmt.Sprintf("no package has been loaded after %v", now.Sub(ws.startedAt).Round(time.Second)))
+		}
+	case now.Sub(lastUpdated) > dashboardLimit:
+		ready = false
+		message := fmt.Sprintf("dashboard data last updated %v ago (limit %v)", now.Sub(lastUpdated).Round(time.Second), dashboardLimit)
+		reasons = append(reasons, message)
+		alerts.Fire(alertDashboardStale, alerts.SeverityCritical, message)
+	default:
+		alerts.Resolve(alertDashboardStale)
+	}
+
+	// Stale L-R-M data is alerted on but does not affect readiness of the dashboard
+	lrmLimit := factor * lrm.GetRefreshInterval()
+	if age, loaded := lrm.GetCacheAge(); loaded && age > lrmLimit {
+		message := fmt.Sprintf("L-R-M data last updated %v ago (limit %v)", age.Round(time.Second), lrmLimit)
+		reasons = append(reasons, message)
+		alerts.Fire(alertLRMStale, alerts.SeverityWarning, message)
+	} else if loaded {
+		alerts.Resolve(alertLRMStale)
+	}
+
+	return ready, reasons
+}
+
+// datasetFailed reports whether the last load of a sub-dataset failed
+func (ws *WebService) datasetFailed(name string) bool {
+	for _, status := range ws.getDatasets() {
+		if status.Name == name {
+			return status.State == DatasetFailed
+		}
+	}
+	return false
+}
+
+// watchdogLoop periodically checks that background refreshes are still making progress
+func (ws *WebService) watchdogLoop() {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ws.checkFreshness(time.Now())
+		case <-ws.stopChan:
+			log.Printf("Stopping watchdog loop...")
+			return
+		}
+	}
+}
+
+// readyHandler reports readiness; it returns 503 while data is missing or stale
+func (ws *WebService) readyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ready, reasons := ws.checkFreshness(time.Now())
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	response := map[string]interface{}{
+		"ready":    ready,
+		"reasons":  reasons,
+		"datasets": ws.getDatasets(),
+	}
+	// Injected faults explain failures that would otherwise look like upstream outages
+	if faults.Enabled() {
+		response["faults"] = faults.Active()
+	}
+	json.NewEncoder(w).Encode(response)
+}
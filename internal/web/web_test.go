@@ -1,10 +1,49 @@
 package web
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"nvidia_driver_monitor/internal/acks"
+	"nvidia_driver_monitor/internal/advisories"
+	"nvidia_driver_monitor/internal/alerts"
+	"nvidia_driver_monitor/internal/archive"
+	"nvidia_driver_monitor/internal/auth"
+	"nvidia_driver_monitor/internal/config"
+	"nvidia_driver_monitor/internal/debuglog"
+	"nvidia_driver_monitor/internal/drivers"
+	"nvidia_driver_monitor/internal/gpus"
+	"nvidia_driver_monitor/internal/history"
+	"nvidia_driver_monitor/internal/i18n"
+	"nvidia_driver_monitor/internal/janitor"
+	"nvidia_driver_monitor/internal/jobs"
+	"nvidia_driver_monitor/internal/lrm"
+	"nvidia_driver_monitor/internal/notes"
+	"nvidia_driver_monitor/internal/packages"
+	"nvidia_driver_monitor/internal/problems"
+	"nvidia_driver_monitor/internal/rediscache"
+	"nvidia_driver_monitor/internal/releases"
+	"nvidia_driver_monitor/internal/slo"
+	"nvidia_driver_monitor/internal/sru"
+	"nvidia_driver_monitor/internal/tracker"
+	"nvidia_driver_monitor/internal/utils"
+	"nvidia_driver_monitor/pkg/debversion"
 )
 
 func TestRateLimiter(t *testing.T) {
@@ -67,6 +106,58 @@ func TestRateLimiterDisabled(t *testing.T) {
 	}
 }
 
+func TestDistributedRateLimiter(t *testing.T) {
+	// A Redis that denies every request, answering each EVAL with 1.5s to wait
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					header, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					count, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+					for i := 0; i < 2*count; i++ {
+						reader.ReadString('\n')
+					}
+					conn.Write([]byte(":1500\r\n"))
+				}
+			}()
+		}
+	}()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	shared := NewDistributedRateLimiter(60, 10, rediscache.Connect(&config.RedisConfig{Address: listener.Addr().String()}))
+	w := httptest.NewRecorder()
+	shared.Middleware(ok).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "2" {
+		t.Errorf("request denied by Redis = %d, Retry-After %q; expected 429 after 2s", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	// Without Redis the counts of the instance apply
+	address := listener.Addr().String()
+	listener.Close()
+	fallback := NewDistributedRateLimiter(2, 10, rediscache.Connect(&config.RedisConfig{Address: address}))
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		fallback.Middleware(ok).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		if w.Code != want {
+			t.Errorf("request %d with Redis down = %d, expected %d", i+1, w.Code, want)
+		}
+	}
+}
+
 func TestAPIHandler(t *testing.T) {
 	apiHandler := NewAPIHandler()
 
@@ -189,3 +280,3143 @@ func TestTemplateFunctions(t *testing.T) {
 		t.Error("contains function not found")
 	}
 }
+
+func TestRetryHandlerValidation(t *testing.T) {
+	ws := &WebService{cache: &CachedData{IsInitialized: true}}
+
+	tests := []struct {
+		method   string
+		target   string
+		expected int
+	}{
+		{"GET", "/api/retry?package=nvidia-graphics-drivers-390", http.StatusMethodNotAllowed},
+		{"POST", "/api/retry", http.StatusBadRequest},
+		{"POST", "/api/retry?package=nvidia-graphics-drivers-000", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.target, nil)
+		w := httptest.NewRecorder()
+		ws.retryHandler(w, req)
+
+		if w.Code != test.expected {
+			t.Errorf("retryHandler(%s %s) = %d, expected %d", test.method, test.target, w.Code, test.expected)
+		}
+	}
+}
+
+func TestIndexHandlerRendersLazySections(t *testing.T) {
+	ws := &WebService{
+		cache: &CachedData{
+			IsInitialized: true,
+			AllPackages: []*PackageData{
+				{
+					PackageName: "nvidia-graphics-drivers-570",
+					Series: []SeriesData{
+						{Series: "noble", UpdatesColor: "danger"},
+						{Series: "jammy", UpdatesColor: "success"},
+					},
+				},
+			},
+		},
+		templatePath: "../../templates",
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	ws.indexHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body := w.Body.String()
+	for _, expected := range []string{
+		`id="nvidia-graphics-drivers-570"`,
+		`data-src="/api?package=nvidia-graphics-drivers-570"`,
+		`1 of 2 series outdated`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Index page should contain %s", expected)
+		}
+	}
+}
+
+func TestIndexHandlerFollowsAcceptLanguage(t *testing.T) {
+	ws := &WebService{
+		cache: &CachedData{
+			IsInitialized: true,
+			AllPackages: []*PackageData{
+				{
+					PackageName: "nvidia-graphics-drivers-570",
+					Series: []SeriesData{
+						{Series: "noble", UpdatesColor: "danger"},
+						{Series: "jammy", UpdatesColor: "success"},
+					},
+				},
+			},
+		},
+		templatePath: "../../templates",
+	}
+
+	tests := []struct {
+		acceptLanguage string
+		locale         string
+		expected       string
+	}{
+		{"", "en", "1 of 2 series outdated"},
+		{"es-AR,es;q=0.9,en;q=0.8", "es", "1 de 2 series desactualizadas"},
+		{"fr-FR", "en", "1 of 2 series outdated"},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Language", test.acceptLanguage)
+		w := httptest.NewRecorder()
+		ws.indexHandler(w, req)
+
+		body := w.Body.String()
+		if w.Code != http.StatusOK || !strings.Contains(body, test.expected) || !strings.Contains(body, `<html lang="`+test.locale+`">`) {
+			t.Errorf("index with Accept-Language %q = %d, expected %q in %s", test.acceptLanguage, w.Code, test.expected, test.locale)
+		}
+		if got := w.Header().Get("Content-Language"); got != test.locale {
+			t.Errorf("Content-Language with Accept-Language %q = %q, expected %q", test.acceptLanguage, got, test.locale)
+		}
+	}
+}
+
+func TestLRMVerifierPageIsTranslated(t *testing.T) {
+	handler := NewLRMHandler("../../templates", config.DefaultConfig())
+
+	req := httptest.NewRequest("GET", "/l-r-m-verifier", nil)
+	req.Header.Set("Accept-Language", "es")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	body := w.Body.String()
+	for _, expected := range []string{`<html lang="es">`, "<h1>Verificador de Linux Restricted Modules (L-R-M)</h1>", "Volver al inicio", `selectAll: "Seleccionar todas"`} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("L-R-M verifier in Spanish = %d, expected %q in the page", w.Code, expected)
+		}
+	}
+	if strings.Contains(body, "Back to Main") {
+		t.Error("L-R-M verifier in Spanish still shows English text")
+	}
+}
+
+func TestDatesFollowRequestTimezone(t *testing.T) {
+	updated := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	ws := &WebService{
+		config:       config.DefaultConfig(),
+		cache:        &CachedData{IsInitialized: true, LastUpdated: updated},
+		templatePath: "../../templates",
+	}
+
+	req := httptest.NewRequest("GET", "/?tz=America/Argentina/Buenos_Aires", nil)
+	req.Header.Set("Accept-Language", "es")
+	w := httptest.NewRecorder()
+	ws.indexHandler(w, req)
+	if !strings.Contains(w.Body.String(), "17/10/2026 03:00 -03") {
+		t.Errorf("index with ?tz= does not show the update time in Buenos Aires:\n%s", w.Body.String())
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != timezoneCookie || cookies[0].Value != "America/Argentina/Buenos_Aires" {
+		t.Fatalf("cookies = %v, expected the time zone to be remembered", cookies)
+	}
+
+	// The next pages keep the time zone without the parameter
+	req = httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	ws.indexHandler(w, req)
+	if !strings.Contains(w.Body.String(), "2026-10-17 03:00 -03") {
+		t.Errorf("index with the time zone cookie does not show the update time in Buenos Aires")
+	}
+
+	w = httptest.NewRecorder()
+	ws.indexHandler(w, httptest.NewRequest("GET", "/?tz=Not/AZone", nil))
+	if !strings.Contains(w.Body.String(), "2026-10-17 06:00 UTC") || len(w.Result().Cookies()) != 0 {
+		t.Errorf("index with an unknown time zone does not fall back to UTC")
+	}
+}
+
+func TestDisplayTimesMiddleware(t *testing.T) {
+	handler := DisplayTimesMiddleware(config.DefaultConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"last_updated":"2026-10-17T06:00:00Z","count":12345678901,"items":[{"fired_at":"2026-10-17T23:30:00.5Z","name":"2026-10-17"}]}`))
+	}))
+
+	req := httptest.NewRequest("GET", "/api/example?tz=Asia/Tokyo", nil)
+	req.Header.Set("Accept-Language", "es")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	expected := `{"count":12345678901,"items":[{"fired_at":"2026-10-17T23:30:00.5Z","fired_at_display":"18/10/2026 08:30 JST","name":"2026-10-17"}],` +
+		`"last_updated":"2026-10-17T06:00:00Z","last_updated_display":"17/10/2026 15:00 JST"}`
+	if got := strings.TrimSpace(w.Body.String()); got != expected {
+		t.Errorf("response with ?tz= = %s, expected %s", got, expected)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/example", nil))
+	if strings.Contains(w.Body.String(), displaySuffix) {
+		t.Errorf("response without ?tz= was annotated: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/example?tz=Not/AZone", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown time zone = %d, expected 400", w.Code)
+	}
+}
+
+func TestTemplatesUseKnownMessages(t *testing.T) {
+	files, _ := filepath.Glob("../../templates/*.html")
+	goFiles, _ := filepath.Glob("*.go")
+	messageKey := regexp.MustCompile(`\{\{t "([^"]+)"|i18n\.T\(locale, "([^"]+)"`)
+	for _, file := range append(files, goFiles...) {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) returned error: %v", file, err)
+		}
+		for _, match := range messageKey.FindAllStringSubmatch(string(content), -1) {
+			key := match[1] + match[2]
+			if i18n.T(i18n.DefaultLocale, key) == key {
+				t.Errorf("%s uses message %q missing from the catalog", file, key)
+			}
+		}
+	}
+}
+
+func TestWatchdogFlipsReadinessOnStaleData(t *testing.T) {
+	now := time.Now()
+	ws := &WebService{cache: &CachedData{IsInitialized: true, LastUpdated: now}}
+
+	if ready, reasons := ws.checkFreshness(now); !ready {
+		t.Errorf("checkFreshness(fresh) = not ready (%v), expected ready", reasons)
+	}
+
+	stale := now.Add(4 * dataRefreshInterval)
+	if ready, _ := ws.checkFreshness(stale); ready {
+		t.Errorf("checkFreshness(+4 intervals) = ready, expected not ready")
+	}
+	if !alerts.IsFiring(alertDashboardStale) {
+		t.Errorf("Expected %s alert to fire", alertDashboardStale)
+	}
+
+	req := httptest.NewRequest("GET", "/api/ready", nil)
+	w := httptest.NewRecorder()
+	ws.cache.LastUpdated = now.Add(-4 * dataRefreshInterval)
+	ws.readyHandler(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("readyHandler(stale) = %d, expected 503", w.Code)
+	}
+
+	ws.cache.LastUpdated = time.Now()
+	if ready, _ := ws.checkFreshness(time.Now()); !ready || alerts.IsFiring(alertDashboardStale) {
+		t.Errorf("Expected readiness to recover and alert to resolve after a fresh refresh")
+	}
+}
+
+func TestTrackHistoryExposesSLOMetrics(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SLO.Targets = []config.SLOTarget{{Branch: "570", MaxDays: 7}}
+	ws := &WebService{
+		cache:        &CachedData{IsInitialized: true, LastUpdated: time.Now()},
+		config:       cfg,
+		historyStore: history.NewStore(""),
+	}
+
+	released := time.Now().AddDate(0, 0, -10).Format(history.DateFormat)
+	pkgs := []*PackageData{
+		{
+			PackageName: "nvidia-graphics-drivers-570",
+			Series:      []SeriesData{{Series: "noble", UpstreamVersion: "570.195.03", ReleaseDate: released, UpdatesColor: "danger"}},
+		},
+		{
+			PackageName: "nvidia-graphics-drivers-535",
+			Series:      []SeriesData{{Series: "noble", UpstreamVersion: "535.274.02", UpdatesColor: "success"}},
+		},
+	}
+	ws.trackHistory(pkgs, time.Now())
+	ws.cache.AllPackages = pkgs
+
+	if pkgs[0].SLO == nil || pkgs[0].SLO.State != slo.StateBreached {
+		t.Fatalf("SLO of 570 = %+v, expected breached", pkgs[0].SLO)
+	}
+	if pkgs[1].SLO != nil {
+		t.Errorf("SLO of 535 = %+v, expected none without a target", pkgs[1].SLO)
+	}
+
+	alerts.Fire(cutoffAlertName("570"), alerts.SeverityCritical, "570 is outdated 2 days before the cutoff")
+	defer alerts.Resolve(cutoffAlertName("570"))
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	ws.metricsHandler(w, req)
+
+	body := w.Body.String()
+	for _, expected := range []string{
+		`nvidia_monitor_outdated_series{package="nvidia-graphics-drivers-570"} 1`,
+		`nvidia_monitor_slo_target_days{branch="570"} 7`,
+		`nvidia_monitor_slo_compliance_ratio{branch="570"} 0`,
+		`nvidia_monitor_slo_breached{branch="570",series="noble"} 1`,
+		`nvidia_monitor_alert_firing{name="sru-cutoff-570",severity="critical"} 1`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Metrics should contain %s, got:\n%s", expected, body)
+		}
+	}
+}
+
+func TestPackagesAsOfReconstructsHistory(t *testing.T) {
+	ws := &WebService{
+		cache:        &CachedData{IsInitialized: true, LastUpdated: time.Now()},
+		historyStore: history.NewStore(""),
+	}
+
+	day1 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	ws.historyStore.Record(day1, []history.Observation{
+		{Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.124.04-0ubuntu1", Upstream: "570.133.07", Outdated: true},
+		{Package: "nvidia-graphics-drivers-570", Series: "jammy", Published: "570.133.07-0ubuntu1", Upstream: "570.133.07"},
+	})
+	ws.historyStore.Record(day1.AddDate(0, 0, 3), []history.Observation{
+		{Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.133.07-0ubuntu1", Upstream: "570.133.07"},
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/packages?as_of=2026-03-02&package=nvidia-graphics-drivers-570", nil)
+	w := httptest.NewRecorder()
+	ws.packagesV1Handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		AsOf     string                  `json:"as_of"`
+		Packages map[string]*PackageData `json:"packages"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	pkg := response.Packages["nvidia-graphics-drivers-570"]
+	if pkg == nil || len(pkg.Series) != 2 {
+		t.Fatalf("Expected 2 series for 570, got %+v", pkg)
+	}
+	if pkg.Series[0].Series != "noble" || pkg.Series[0].UpdatesColor != "danger" || pkg.Series[0].UpdatesSecurity != "570.124.04-0ubuntu1" {
+		t.Errorf("noble row = %+v, expected the outdated 2026-03-01 state", pkg.Series[0])
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/packages?as_of=03/02/2026", nil)
+	w = httptest.NewRecorder()
+	ws.packagesV1Handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid date, got %d", w.Code)
+	}
+}
+
+func TestPackagesAsOfUsesComparisonPolicy(t *testing.T) {
+	ws := &WebService{
+		cache:             &CachedData{IsInitialized: true, LastUpdated: time.Now()},
+		historyStore:      history.NewStore(""),
+		supportedReleases: []releases.SupportedRelease{{BranchName: "570", ComparisonPolicy: releases.PolicyStrict}},
+	}
+	// An Ubuntu-only revision of upstream matches the default prefix policy, but not strict
+	ws.historyStore.Record(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), []history.Observation{
+		{Package: "nvidia-graphics-drivers-570", Series: "noble", Published: "570.133.07.1-0ubuntu1", Proposed: "570.133.07-0ubuntu2", Upstream: "570.133.07"},
+		{Package: "nvidia-graphics-drivers-550", Series: "noble", Published: "550.127.05.1-0ubuntu1", Upstream: "550.127.05"},
+	})
+
+	pkgs := ws.packagesAsOf(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	row := pkgs["nvidia-graphics-drivers-570"].Series[0]
+	if row.UpdatesColor != "danger" || row.ProposedColor != "success" || row.Comparisons[0].Comparison != packages.ComparisonAhead {
+		t.Errorf("strict 570 row = %+v, expected the published revision not to match", row)
+	}
+	if row := pkgs["nvidia-graphics-drivers-550"].Series[0]; row.UpdatesColor != "success" {
+		t.Errorf("550 row = %+v, expected the prefix policy of branches without one", row)
+	}
+}
+
+func TestFleetReportsNeedTheReportToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Fleet.DataFile = filepath.Join(t.TempDir(), "fleet.json")
+	cfg.Fleet.ReportToken = "host-token"
+	handler := NewFleetHandler("../../templates", cfg)
+	report := `{"hostname": "gpu-01", "series": "noble", "status": "up-to-date"}`
+
+	w := httptest.NewRecorder()
+	handler.ReportHandler(w, httptest.NewRequest("POST", "/api/v1/hosts/report", strings.NewReader(report)))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("POST report without the token = %d, expected %d", w.Code, http.StatusUnauthorized)
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/hosts/report", strings.NewReader(report))
+	req.Header.Set("Authorization", "Bearer host-token")
+	w = httptest.NewRecorder()
+	handler.ReportHandler(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("POST report with the token = %d, expected %d: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+}
+
+func TestMaintenanceHandler(t *testing.T) {
+	handler := NewAPIHandler()
+
+	req := adminRequest("POST", "/api/maintenance", strings.NewReader(`{"name": "archive-cleanup", "duration": "1h"}`))
+	w := httptest.NewRecorder()
+	handler.MaintenanceHandler(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("POST without an admin check = %d, expected %d", w.Code, http.StatusForbidden)
+	}
+	handler.requireAdmin = (&WebService{config: adminConfig()}).requireAdmin
+
+	req = httptest.NewRequest("POST", "/api/maintenance", strings.NewReader(`{"name": "archive-cleanup", "duration": "1h"}`))
+	w = httptest.NewRecorder()
+	handler.MaintenanceHandler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("POST without the admin token = %d, expected %d", w.Code, http.StatusUnauthorized)
+	}
+
+	req = adminRequest("POST", "/api/maintenance", strings.NewReader(`{"name": "archive-cleanup", "duration": "1h"}`))
+	w = httptest.NewRecorder()
+	handler.MaintenanceHandler(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var window alerts.Window
+	if err := json.Unmarshal(w.Body.Bytes(), &window); err != nil {
+		t.Fatalf("Failed to decode window: %v", err)
+	}
+	defer alerts.RemoveMaintenance(window.ID)
+
+	if active := alerts.ActiveMaintenance(time.Now()); len(active) != 1 {
+		t.Errorf("Expected one active maintenance window, got %d", len(active))
+	}
+
+	req = adminRequest("POST", "/api/maintenance", strings.NewReader(`{"name": "bad"}`))
+	w = httptest.NewRecorder()
+	handler.MaintenanceHandler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without end or duration, got %d", w.Code)
+	}
+
+	req = adminRequest("DELETE", "/api/maintenance?id="+window.ID, nil)
+	w = httptest.NewRecorder()
+	handler.MaintenanceHandler(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+}
+
+func TestViewScopesPackagesAndSeries(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Views = []config.ViewConfig{
+		{Name: "desktop", Title: "Desktop", Branches: []string{"[0-9][0-9][0-9]"}, Series: []string{"noble"}},
+		{Name: "server", Branches: []string{"*-server"}, Token: "secret", AlertOutdatedSeries: 1},
+	}
+	ws := &WebService{
+		cache: &CachedData{
+			IsInitialized: true,
+			AllPackages: []*PackageData{
+				{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesColor: "danger"}, {Series: "jammy"}}},
+				{PackageName: "nvidia-graphics-drivers-570-server", Series: []SeriesData{{Series: "jammy", UpdatesColor: "danger"}}},
+			},
+		},
+		config:       cfg,
+		templatePath: "../../templates",
+	}
+
+	req := httptest.NewRequest("GET", "/api?view=desktop", nil)
+	w := httptest.NewRecorder()
+	ws.apiHandler(w, req)
+	var response struct {
+		Packages map[string]*PackageData `json:"packages"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if pkg := response.Packages["nvidia-graphics-drivers-570"]; len(response.Packages) != 1 || pkg == nil || len(pkg.Series) != 1 {
+		t.Errorf("View desktop = %+v, expected only 570 restricted to noble", response.Packages)
+	}
+
+	for _, test := range []struct {
+		path     string
+		expected int
+	}{
+		{"/view/server", http.StatusUnauthorized},
+		{"/view/server?token=wrong", http.StatusUnauthorized},
+		{"/view/server?token=secret", http.StatusOK},
+		{"/view/unknown", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		ws.viewHandler(w, httptest.NewRequest("GET", test.path, nil))
+		if w.Code != test.expected {
+			t.Errorf("GET %s = %d, expected %d", test.path, w.Code, test.expected)
+		}
+		if w.Code == http.StatusOK && !strings.Contains(w.Body.String(), `data-src="/api?package=nvidia-graphics-drivers-570-server&amp;token=secret&amp;view=server"`) {
+			t.Errorf("GET %s should scope row requests to the view", test.path)
+		}
+	}
+
+	defer alerts.Resolve(viewAlertName(&cfg.Views[1]))
+	ws.evaluateViewAlerts(ws.cache.AllPackages)
+	if !alerts.IsFiring("view-server-outdated") {
+		t.Errorf("Expected view-server-outdated alert to fire")
+	}
+}
+
+func TestIndexFiltersAndPresets(t *testing.T) {
+	pkgs := []*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesColor: "danger"}, {Series: "jammy", UpdatesColor: "success"}}},
+		{PackageName: "nvidia-graphics-drivers-570-server", Series: []SeriesData{{Series: "noble", UpdatesColor: "success"}, {Series: "jammy", UpdatesColor: "danger"}}},
+	}
+
+	for _, test := range []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"570/noble", "570/jammy", "570-server/noble", "570-server/jammy"}},
+		{"branch=*-server", []string{"570-server/noble", "570-server/jammy"}},
+		{"series=jammy,%20noble&status=OUTDATED", []string{"570/noble", "570-server/jammy"}},
+		{"branch=570&status=up-to-date", []string{"570/jammy"}},
+		{"series=focal", nil},
+		{"status=unknown&hide=target,bogus", []string{"570/noble", "570/jammy", "570-server/noble", "570-server/jammy"}},
+	} {
+		query, _ := url.ParseQuery(test.query)
+		var got []string
+		for _, pkg := range parseIndexFilters(query).Apply(pkgs) {
+			for _, row := range pkg.Series {
+				got = append(got, branchFromPackage(pkg.PackageName)+"/"+row.Series)
+			}
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Apply(%s) = %v, expected %v", test.query, got, test.expected)
+		}
+	}
+	if hide := parseIndexFilters(url.Values{"hide": {"target,bogus,target"}}).Hide; !reflect.DeepEqual(hide, []string{"target"}) {
+		t.Errorf("Hide = %v, expected unknown and repeated columns dropped", hide)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Views = []config.ViewConfig{{Name: "server", Branches: []string{"*-server"}, Token: "secret"}}
+	cfg.Presets = []config.PresetConfig{
+		{Name: "server-jammy-outdated", Title: "Server outdated in jammy", Query: "branch=*-server&series=jammy&status=outdated&hide=target"},
+		{Name: "noble-kernels", Page: config.PresetPageLRM, Query: "series=24.04"},
+	}
+	if err := cfg.ValidatePresets(); err != nil {
+		t.Fatalf("ValidatePresets returned error: %v", err)
+	}
+	ws := &WebService{
+		cache:        &CachedData{IsInitialized: true, AllPackages: pkgs},
+		config:       cfg,
+		templatePath: "../../templates",
+	}
+
+	w := httptest.NewRecorder()
+	ws.indexHandler(w, httptest.NewRequest("GET", "/?view=server-jammy-outdated", nil))
+	body := w.Body.String()
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /?view=server-jammy-outdated = %d, expected 200", w.Code)
+	}
+	if strings.Contains(body, `id="nvidia-graphics-drivers-570"`) ||
+		!strings.Contains(body, `data-src="/api?package=nvidia-graphics-drivers-570-server&amp;branch=%2A-server&amp;series=jammy&amp;status=outdated"`) {
+		t.Errorf("The preset should scope the sections and their row requests to its filters")
+	}
+	if !strings.Contains(body, ".col-target { display: none; }") || !strings.Contains(body, "Server outdated in jammy") {
+		t.Errorf("The preset should hide its columns and show its title")
+	}
+
+	// The query overrides the preset, an empty value clears its filter
+	w = httptest.NewRecorder()
+	ws.indexHandler(w, httptest.NewRequest("GET", "/?view=server-jammy-outdated&status=", nil))
+	if !strings.Contains(w.Body.String(), `data-src="/api?package=nvidia-graphics-drivers-570-server&amp;branch=%2A-server&amp;series=jammy"`) {
+		t.Errorf("An empty status should clear the status filter of the preset")
+	}
+
+	for _, test := range []struct {
+		path     string
+		expected int
+	}{
+		{"/?view=server&token=secret", http.StatusOK},
+		{"/?view=server", http.StatusUnauthorized},
+		{"/?view=noble-kernels", http.StatusNotFound}, // An L-R-M preset
+		{"/?view=unknown", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		ws.indexHandler(w, httptest.NewRequest("GET", test.path, nil))
+		if w.Code != test.expected {
+			t.Errorf("GET %s = %d, expected %d", test.path, w.Code, test.expected)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	ws.apiHandler(w, httptest.NewRequest("GET", "/api?status=outdated&series=noble", nil))
+	var response struct {
+		Packages map[string]*PackageData `json:"packages"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if pkg := response.Packages["nvidia-graphics-drivers-570"]; len(response.Packages) != 1 || pkg == nil || len(pkg.Series) != 1 {
+		t.Errorf("/api filtered = %+v, expected only 570 in noble", response.Packages)
+	}
+
+	cfg.Presets = append(cfg.Presets, config.PresetConfig{Name: "server"})
+	if err := cfg.ValidatePresets(); err == nil {
+		t.Errorf("ValidatePresets should reject a dashboard preset named like a view")
+	}
+}
+
+func TestComponentFilterAndTransitions(t *testing.T) {
+	ws := &WebService{
+		cache: &CachedData{
+			IsInitialized: true,
+			AllPackages: []*PackageData{
+				{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+					{Series: "noble", Component: "restricted"},
+					{Series: "jammy", Component: "multiverse", ProposedComponent: "restricted"},
+				}},
+				{PackageName: "nvidia-graphics-drivers-390", Series: []SeriesData{{Series: "focal", Component: "multiverse"}}},
+			},
+		},
+		historyStore: history.NewStore(""),
+	}
+
+	req := httptest.NewRequest("GET", "/api?component=restricted", nil)
+	w := httptest.NewRecorder()
+	ws.apiHandler(w, req)
+	var response struct {
+		Packages map[string]*PackageData `json:"packages"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if pkg := response.Packages["nvidia-graphics-drivers-570"]; len(response.Packages) != 1 || pkg == nil || len(pkg.Series) != 2 {
+		t.Errorf("component=restricted = %+v, expected only the two 570 rows", response.Packages)
+	}
+
+	day := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	ws.trackHistory(ws.cache.AllPackages, day)
+	ws.cache.AllPackages[0].Series[0].Component = "multiverse"
+	ws.trackHistory(ws.cache.AllPackages, day.AddDate(0, 0, 1))
+
+	req = httptest.NewRequest("GET", "/api/v1/history/components?package=nvidia-graphics-drivers-570", nil)
+	w = httptest.NewRecorder()
+	ws.componentTransitionsHandler(w, req)
+	var transitions struct {
+		Transitions []history.ComponentTransition `json:"transitions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &transitions); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(transitions.Transitions) != 1 || transitions.Transitions[0].Series != "noble" || transitions.Transitions[0].To != "multiverse" {
+		t.Errorf("transitions = %+v, expected noble moving to multiverse", transitions.Transitions)
+	}
+}
+
+func TestCachesHandlerPrunesAndReports(t *testing.T) {
+	dir := t.TempDir()
+	for name, age := range map[string]time.Duration{"old.json": 100 * 24 * time.Hour, "recent.json": time.Hour} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Now().Add(-age)
+		os.Chtimes(path, modTime, modTime)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Changelog.CacheDir = dir
+	ws := &WebService{config: cfg, cache: &CachedData{}, janitor: janitor.New(janitorCaches(cfg))}
+
+	w := httptest.NewRecorder()
+	ws.cachesHandler(w, httptest.NewRequest("GET", "/api/v1/caches", nil))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("GET /api/v1/caches without an admin token configured = %d, expected %d", w.Code, http.StatusForbidden)
+	}
+	cfg.Auth.AdminToken = "test-admin-token"
+
+	w = httptest.NewRecorder()
+	ws.cachesHandler(w, adminRequest("POST", "/api/v1/caches", nil))
+	var response struct {
+		Caches []janitor.Report `json:"caches"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	var changelog *janitor.Report
+	for i := range response.Caches {
+		if response.Caches[i].Name == config.JanitorCacheChangelog {
+			changelog = &response.Caches[i]
+		}
+	}
+	if changelog == nil || changelog.Files != 1 || changelog.Evictions != 1 || changelog.MaxAge != "2160h0m0s" {
+		t.Fatalf("POST /api/v1/caches changelog = %+v, expected the entry past 90 days evicted", changelog)
+	}
+
+	w = httptest.NewRecorder()
+	ws.metricsHandler(w, httptest.NewRequest("GET", "/metrics", nil))
+	for _, expected := range []string{
+		`nvidia_monitor_cache_files{cache="changelog"} 1`,
+		`nvidia_monitor_cache_evictions_total{cache="changelog"} 1`,
+		`nvidia_monitor_cache_evicted_bytes_total{cache="changelog"} 2`,
+	} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("metrics missing %q", expected)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	ws.cachesHandler(w, adminRequest("DELETE", "/api/v1/caches", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE /api/v1/caches = %d, expected 405", w.Code)
+	}
+}
+
+func TestExportJobsWithToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Jobs.Enabled, cfg.Jobs.Token = true, "ci-token"
+	ws := &WebService{config: cfg, cache: &CachedData{IsInitialized: true}, jobStore: jobs.NewStore(t.TempDir(), time.Hour, 2)}
+	ws.cache.setPackages([]*PackageData{{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1"}}}})
+
+	post := func(target, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		if strings.HasPrefix(target, "/api/v1/reports") {
+			ws.reportsGenerateHandler(w, req)
+		} else {
+			ws.exportsHandler(w, req)
+		}
+		return w
+	}
+	for target, token := range map[string]string{"/api/v1/exports/packages": "", "/api/v1/reports/generate": "wrong"} {
+		if w := post(target, token); w.Code != http.StatusUnauthorized {
+			t.Errorf("POST %s with token %q = %d, expected 401", target, token, w.Code)
+		}
+	}
+	if w := post("/api/v1/exports/nope", "ci-token"); w.Code != http.StatusNotFound {
+		t.Errorf("POST /api/v1/exports/nope = %d, expected 404", w.Code)
+	}
+	if w := post("/api/v1/exports/sbom", "ci-token"); w.Code != http.StatusBadRequest {
+		t.Errorf("POST /api/v1/exports/sbom without series = %d, expected 400", w.Code)
+	}
+
+	w := post("/api/v1/exports/packages?series=noble", "ci-token")
+	var submitted jobStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &submitted); err != nil || w.Code != http.StatusAccepted || w.Header().Get("Location") != submitted.StatusURL {
+		t.Fatalf("POST /api/v1/exports/packages = %d %s, expected 202 with the job", w.Code, w.Body.String())
+	}
+	ws.jobStore.Wait()
+
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		req.Header.Set("Authorization", "Bearer ci-token")
+		w := httptest.NewRecorder()
+		ws.jobsHandler(w, req)
+		return w
+	}
+	var status jobStatus
+	json.Unmarshal(get(submitted.StatusURL).Body.Bytes(), &status)
+	if status.Status != jobs.StatusSucceeded || status.DownloadURL != submitted.StatusURL+"/download" {
+		t.Fatalf("job = %+v, expected it succeeded with a download URL", status)
+	}
+	w = get(status.DownloadURL)
+	if !strings.Contains(w.Body.String(), "570.195.03-0ubuntu0.24.04.1") || !strings.Contains(w.Header().Get("Content-Disposition"), ".json") {
+		t.Errorf("download = %s (%s), expected the packages export", w.Body.String(), w.Header().Get("Content-Disposition"))
+	}
+
+	// An export the endpoint refuses is a failed job rather than a failed request
+	w = post("/api/v1/exports/pins?format=apt", "ci-token")
+	json.Unmarshal(w.Body.Bytes(), &submitted)
+	ws.jobStore.Wait()
+	json.Unmarshal(get(submitted.StatusURL).Body.Bytes(), &status)
+	if w.Code != http.StatusAccepted || status.Status != jobs.StatusFailed || status.Error == "" {
+		t.Errorf("apt pins job = %+v, expected it failed without a series", status)
+	}
+	if w := get(submitted.StatusURL + "/download"); w.Code != http.StatusConflict {
+		t.Errorf("download of a failed job = %d, expected 409", w.Code)
+	}
+}
+
+func TestComponentAndSectionMovesAreRecorded(t *testing.T) {
+	previous := []*PackageData{{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+		{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1", Component: "restricted", Section: "restricted/misc"},
+		{Series: "jammy", UpdatesSecurity: "-"},
+	}}}
+	current := []*PackageData{{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+		{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1", Component: "multiverse", Section: "multiverse/libs"},
+		{Series: "jammy", UpdatesSecurity: "570.195.03-0ubuntu0.22.04.1", Component: "restricted", Section: "restricted/misc"},
+	}}}
+
+	changes := diffPackages(previous, current)
+	var moves []string
+	for _, change := range changes {
+		if change.Column == "component" || change.Column == "section" {
+			moves = append(moves, change.String())
+		}
+	}
+	want := []string{"570/noble component restricted→multiverse", "570/noble section restricted/misc→multiverse/libs"}
+	if strings.Join(moves, "|") != strings.Join(want, "|") {
+		t.Errorf("diffPackages() moves = %v, expected %v and no move for the new jammy publication", moves, want)
+	}
+
+	ws := &WebService{config: config.DefaultConfig(), cache: &CachedData{IsInitialized: true}, historyStore: history.NewStore("")}
+	day := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	ws.trackHistory(previous, day)
+	ws.trackHistory(current, day.AddDate(0, 0, 1))
+	ws.cache.setPackages(current)
+
+	w := httptest.NewRecorder()
+	ws.packageHandler(w, httptest.NewRequest("GET", "/package?name=nvidia-graphics-drivers-570", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, "<code>restricted</code> → <code>multiverse</code>") || !strings.Contains(body, "<code>restricted/misc</code> → <code>multiverse/libs</code>") {
+		t.Errorf("package page should list the component and section moves")
+	}
+}
+
+func TestRefreshBacksOffWhileServingStaleData(t *testing.T) {
+	lastGood := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	fresh := &PackageData{PackageName: "nvidia-graphics-drivers-570"}
+	stale := staleCopy(fresh, lastGood)
+	if fresh.StaleSince != nil || stale.StaleSince == nil || !stale.StaleSince.Equal(lastGood) {
+		t.Fatalf("staleCopy() = %+v, expected a copy stale since %v", stale, lastGood)
+	}
+	if again := staleCopy(stale, lastGood.Add(time.Hour)); !again.StaleSince.Equal(lastGood) {
+		t.Errorf("staleCopy() of stale data = %v, expected the original time", again.StaleSince)
+	}
+
+	ws := &WebService{cache: &CachedData{IsInitialized: true, AllPackages: []*PackageData{stale}}}
+	for _, expected := range []time.Duration{dataRefreshInterval, 2 * dataRefreshInterval, 4 * dataRefreshInterval} {
+		if delay := ws.nextRefreshDelay(nil); delay != expected {
+			t.Errorf("nextRefreshDelay() = %v, expected %v", delay, expected)
+		}
+	}
+
+	ws.cache.AllPackages = []*PackageData{fresh}
+	if delay := ws.nextRefreshDelay(nil); delay != dataRefreshInterval || ws.refreshFailures != 0 {
+		t.Errorf("nextRefreshDelay() after recovery = %v, expected %v", delay, dataRefreshInterval)
+	}
+}
+
+func TestAnnounceRemovalsOnce(t *testing.T) {
+	notices := make(chan string, 4)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			State string `json:"state"`
+			Name  string `json:"name"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		notices <- payload.State + " " + payload.Name
+	}))
+	defer webhook.Close()
+	alerts.SetAlertsConfig(&config.Config{Alerts: config.AlertsConfig{WebhookURL: webhook.URL}})
+	defer alerts.SetAlertsConfig(&config.Config{})
+
+	now := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+	recent := removedSeriesData("noble", packages.Removal{Version: "390.2", Date: now.AddDate(0, 0, -2), Comment: "EOL"})
+	if recent.Removed != "removed on 2024-05-01" || recent.RemovalNote != "390.2: EOL" {
+		t.Errorf("removedSeriesData() = %q (%q), expected removed on 2024-05-01 (390.2: EOL)", recent.Removed, recent.RemovalNote)
+	}
+	old := removedSeriesData("jammy", packages.Removal{Version: "390.1", Date: now.AddDate(-1, 0, 0)})
+	pkgs := []*PackageData{{PackageName: "nvidia-graphics-drivers-390", Series: []SeriesData{recent, old}}}
+
+	ws := &WebService{}
+	ws.announceRemovals(pkgs, now)
+	ws.announceRemovals(pkgs, now)
+
+	select {
+	case notice := <-notices:
+		if notice != "notice removed-nvidia-graphics-drivers-390-noble" {
+			t.Errorf("Unexpected notice %q", notice)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a notice for the recent removal")
+	}
+	select {
+	case notice := <-notices:
+		t.Errorf("Expected a single notice, also got %q", notice)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if alerts.IsFiring("removed-nvidia-graphics-drivers-390-noble") {
+		t.Errorf("Removal notices should not become active alerts")
+	}
+}
+
+func TestDKMSMatrixInputs(t *testing.T) {
+	pkgs := []*PackageData{
+		{PackageName: "nvidia-graphics-drivers-470", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "470.256.02-0ubuntu0.24.04.1"},
+			{Series: "resolute", UpdatesSecurity: "-", Removed: "removed on 2026-04-01"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "N/A"}}},
+	}
+	branches := publishedBranches(pkgs)
+	if len(branches) != 1 || strings.Join(branches["noble"], ",") != "470" {
+		t.Errorf("publishedBranches() = %v, expected only 470 in noble", branches)
+	}
+
+	kernels := lrmKernels(&lrm.LRMVerifierData{KernelResults: []lrm.KernelLRMResult{
+		{Codename: "noble", Source: "linux", SourceVersion: "6.8.0-45.45"},
+		{Codename: "noble", Source: "linux-oem-6.11", SourceVersion: "N/A"},
+	}})
+	if len(kernels) != 1 || kernels[0].Source != "linux" {
+		t.Errorf("lrmKernels() = %+v, expected only the kernel with a version", kernels)
+	}
+}
+
+func TestValidateDashboard(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	supported := []releases.SupportedRelease{
+		{BranchName: "570", IsSupported: map[string]bool{"noble": true, "jammy": true}, CurrentUpstreamVersion: "570.195.03", DatePublished: "2026-10-20"},
+		{BranchName: "580", IsSupported: map[string]bool{"noble": true}},
+		{BranchName: "570", IsSupported: map[string]bool{"noble": true}},
+	}
+	pkgs := []*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1", Proposed: "570.172.08-0ubuntu0.24.04.1"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "N/A", Proposed: "N/A"}}},
+	}
+
+	checks := make(map[string]int)
+	for _, issue := range validateDashboard(pkgs, supported, now) {
+		checks[issue.Check]++
+		if issue.Check == checkMissingSeries && issue.Series != "jammy" {
+			t.Errorf("Unexpected missing series %s", issue.Series)
+		}
+	}
+	for _, check := range []string{checkProposedOlder, checkFutureUpstream, checkMissingSeries, checkDuplicateBranch} {
+		if checks[check] != 1 {
+			t.Errorf("validateDashboard() reported %d %s issues, expected 1", checks[check], check)
+		}
+	}
+}
+
+func TestStreamKernelsWritesOneRecordPerLine(t *testing.T) {
+	results := []lrm.KernelLRMResult{
+		{Codename: "noble", Source: "linux"},
+		{Codename: "jammy", Source: "linux-hwe-6.8"},
+	}
+	w := httptest.NewRecorder()
+	streamKernels(context.Background(), w, results)
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if len(lines) != len(results) {
+		t.Fatalf("streamKernels() wrote %d lines, expected %d", len(lines), len(results))
+	}
+	for i, line := range lines {
+		var record lrm.KernelLRMResult
+		if err := json.Unmarshal([]byte(line), &record); err != nil || record.Source != results[i].Source {
+			t.Errorf("Line %d = %s, expected the %s record", i, line, results[i].Source)
+		}
+	}
+	if !w.Flushed {
+		t.Errorf("streamKernels() should flush after each record")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w = httptest.NewRecorder()
+	streamKernels(ctx, w, results)
+	if w.Body.Len() != 0 {
+		t.Errorf("streamKernels() should stop once the client is gone")
+	}
+}
+
+func TestSparseFieldsets(t *testing.T) {
+	ws := &WebService{
+		cache: &CachedData{
+			IsInitialized: true,
+			AllPackages: []*PackageData{
+				{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+					{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1", Proposed: "-", UpstreamVersion: "570.195.03"},
+				}},
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	ws.packagesV1Handler(w, httptest.NewRequest("GET", "/api/v1/packages?fields=UpdatesSecurity", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/v1/packages?fields=UpdatesSecurity = %d, expected %d", w.Code, http.StatusOK)
+	}
+	var response struct {
+		Packages map[string]map[string]json.RawMessage `json:"packages"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	pkg := response.Packages["nvidia-graphics-drivers-570"]
+	if len(pkg) != 2 || string(pkg["Series"]) != `[{"Series":"noble","UpdatesSecurity":"570.195.03-0ubuntu0.24.04.1"}]` {
+		t.Errorf("Sparse package = %v, expected the name and series rows with UpdatesSecurity only", pkg)
+	}
+
+	w = httptest.NewRecorder()
+	ws.packagesV1Handler(w, httptest.NewRequest("GET", "/api/v1/packages?fields=Updates", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Unknown field = %d, expected %d", w.Code, http.StatusBadRequest)
+	}
+
+	fields, err := parseFields(httptest.NewRequest("GET", "/api/v1/lrm?fields=UpdateStatus", nil), lrm.KernelLRMResult{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kernels, err := fields.sparseKernels([]lrm.KernelLRMResult{{Series: "24.04", Source: "linux", UpdateStatus: "OK", HasLRM: true}})
+	if err != nil || len(kernels) != 1 || len(kernels[0]) != 3 || kernels[0]["UpdateStatus"] != "OK" {
+		t.Errorf("sparseKernels() = %v, %v, expected Series, Source and UpdateStatus", kernels, err)
+	}
+}
+
+func TestAdvisoriesFlagPublishedCombinations(t *testing.T) {
+	ws := &WebService{config: adminConfig(), advisoryStore: advisories.NewStore("")}
+
+	body := `{"branch": "470", "kernel_version": "6.8.0-45", "title": "Black screen on boot", "links": ["https://bugs.launchpad.net/bugs/1"]}`
+	w := httptest.NewRecorder()
+	ws.advisoriesHandler(w, httptest.NewRequest("POST", "/api/v1/advisories", strings.NewReader(body)))
+	if w.Code != http.StatusUnauthorized || len(ws.advisoryStore.List()) != 0 {
+		t.Errorf("anonymous POST /api/v1/advisories = %d, expected %d and nothing added", w.Code, http.StatusUnauthorized)
+	}
+
+	w = httptest.NewRecorder()
+	ws.advisoriesHandler(w, adminRequest("POST", "/api/v1/advisories", strings.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /api/v1/advisories = %d, expected %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var added advisories.Advisory
+	json.Unmarshal(w.Body.Bytes(), &added)
+
+	results := []lrm.KernelLRMResult{
+		{Codename: "noble", Source: "linux", SourceVersion: "6.8.0-45.45", NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{
+			{DriverName: "nvidia-graphics-drivers-470", DKMSVersion: "470.256.02-0ubuntu0.24.04.1"},
+			{DriverName: "nvidia-graphics-drivers-550", DKMSVersion: "550.163.01-0ubuntu0.24.04.1"},
+		}},
+		{Codename: "jammy", Source: "linux", SourceVersion: "5.15.0-119.129", NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{
+			{DriverName: "nvidia-graphics-drivers-470", DKMSVersion: "470.256.02-0ubuntu0.22.04.1"},
+		}},
+	}
+	matches := matchAdvisories(ws.advisoryStore, results)
+	if len(matches) != 1 || matches[0].Codename != "noble" || matches[0].DriverName != "nvidia-graphics-drivers-470" {
+		t.Errorf("matchAdvisories() = %+v, expected the 470 driver on the noble 6.8 kernel", matches)
+	}
+
+	w = httptest.NewRecorder()
+	ws.advisoryHandler(w, httptest.NewRequest("DELETE", "/api/v1/advisories/"+added.ID, nil))
+	if w.Code != http.StatusUnauthorized || len(ws.advisoryStore.List()) != 1 {
+		t.Errorf("anonymous DELETE /api/v1/advisories/%s = %d, expected %d and the advisory kept", added.ID, w.Code, http.StatusUnauthorized)
+	}
+
+	w = httptest.NewRecorder()
+	ws.advisoryHandler(w, adminRequest("DELETE", "/api/v1/advisories/"+added.ID, nil))
+	if w.Code != http.StatusNoContent || len(matchAdvisories(ws.advisoryStore, results)) != 0 {
+		t.Errorf("DELETE /api/v1/advisories/%s = %d, expected the advisory to be removed", added.ID, w.Code)
+	}
+}
+
+func TestCompareWithArchive(t *testing.T) {
+	pkgs := []*PackageData{{
+		PackageName: "nvidia-graphics-drivers-550",
+		Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "550.120-0ubuntu0.24.04.1", Proposed: "550.127.05-0ubuntu0.24.04.1"},
+			{Series: "jammy", UpdatesSecurity: "550.127.05-0ubuntu0.22.04.1", Proposed: "-"},
+			{Series: "focal", UpdatesSecurity: "550.127.05-0ubuntu0.20.04.1", Proposed: "-"},
+		},
+	}}
+	index := archive.Index{
+		"noble":          {},
+		"noble-updates":  {"nvidia-graphics-drivers-550": "550.127.05-0ubuntu0.24.04.1"},
+		"noble-security": {"nvidia-graphics-drivers-550": "550.120-0ubuntu0.24.04.1"},
+		"noble-proposed": {"nvidia-graphics-drivers-550": "550.127.05-0ubuntu0.24.04.1"},
+		"jammy":          {},
+		"jammy-updates":  {},
+		"jammy-security": {},
+		"jammy-proposed": {},
+		// focal-proposed could not be fetched, so only the published column is compared
+		"focal":          {},
+		"focal-updates":  {"nvidia-graphics-drivers-550": "550.127.05-0ubuntu0.20.04.1"},
+		"focal-security": {},
+	}
+
+	issues := compareWithArchive(pkgs, index, []string{"Updates", "Security", "Release"})
+	expected := []DataIssue{
+		{Check: checkArchiveNewer, Package: "nvidia-graphics-drivers-550", Series: "noble"},
+		{Check: checkArchiveOlder, Package: "nvidia-graphics-drivers-550", Series: "jammy"},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("compareWithArchive() = %+v, expected %d issues", issues, len(expected))
+	}
+	for i, issue := range issues {
+		if issue.Check != expected[i].Check || issue.Series != expected[i].Series {
+			t.Errorf("compareWithArchive()[%d] = %+v, expected %s in %s", i, issue, expected[i].Check, expected[i].Series)
+		}
+	}
+
+	suites := archiveSuites(pkgs[:1], []string{"Updates"})
+	if strings.Join(suites, ",") != "noble-updates,noble-proposed,jammy-updates,jammy-proposed,focal-updates,focal-proposed" {
+		t.Errorf("archiveSuites() = %v", suites)
+	}
+}
+
+func TestSimulatePromotion(t *testing.T) {
+	cycles := &sru.SRUCycles{Cycles: []sru.SRUCycle{
+		{Name: "2026.11.09", CutoffDate: "2026-11-04", ReleaseDate: "2026-11-30", PredictedCycle: true},
+		{Name: "2026.10.12", CutoffDate: "2026-10-07", ReleaseDate: "2026-11-02"},
+		{Name: "2026.09.14", CutoffDate: "2026-09-09", ReleaseDate: "2026-10-05"},
+	}}
+	pkg := &PackageData{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+		{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu0.24.04.1", Proposed: "570.195.03-0ubuntu0.24.04.1"},
+		{Series: "jammy", UpdatesSecurity: "570.172.08-0ubuntu0.22.04.1", Proposed: "-"},
+	}}
+	lrmData := &lrm.LRMVerifierData{KernelResults: []lrm.KernelLRMResult{
+		{Series: "24.04", Codename: "noble", Source: "linux", Routing: "ubuntu/4", SourceVersion: "6.8.0-85.85",
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{{DriverName: "nvidia-graphics-drivers-570", DSCVersion: "570.172.08-0ubuntu0.24.04.1"}}},
+		{Series: "24.04", Codename: "noble", Source: "linux-aws", Routing: "ubuntu/4", SourceVersion: "6.8.0-1040.42",
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{{DriverName: "nvidia-graphics-drivers-550", DSCVersion: "550.163.01-0ubuntu0.24.04.1"}}},
+		{Series: "22.04", Codename: "jammy", Source: "linux", Routing: "ubuntu/4", SourceVersion: "5.15.0-160.170",
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{{DriverName: "nvidia-graphics-drivers-570", DSCVersion: "570.172.08-0ubuntu0.22.04.1"}}},
+	}}
+	uploadDate := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	simulation := simulatePromotion(cycles, pkg, lrmData, "nvidia-graphics-drivers-570", "570.195.03-0ubuntu0.24.04.2", "", uploadDate)
+	if simulation.TargetCycle == nil || simulation.TargetCycle.Name != "2026.10.12" || simulation.TargetCycle.DaysUntilCutoff != 6 {
+		t.Errorf("TargetCycle = %+v, expected 2026.10.12 six days before its cutoff", simulation.TargetCycle)
+	}
+	if simulation.NextCycle == nil || simulation.NextCycle.Name != "2026.11.09" || !simulation.NextCycle.Predicted {
+		t.Errorf("NextCycle = %+v, expected the predicted 2026.11.09 cycle", simulation.NextCycle)
+	}
+	if simulation.ExpectedUpdatesDate != "2026-11-02" {
+		t.Errorf("ExpectedUpdatesDate = %s, expected 2026-11-02", simulation.ExpectedUpdatesDate)
+	}
+	if len(simulation.Rebuilds) != 1 || simulation.Rebuilds[0].Source != "linux" || simulation.Rebuilds[0].Series != "noble" {
+		t.Errorf("Rebuilds = %+v, expected only the noble linux kernel carrying 570", simulation.Rebuilds)
+	}
+	if len(simulation.Warnings) != 0 {
+		t.Errorf("Warnings = %v, expected none", simulation.Warnings)
+	}
+
+	simulation = simulatePromotion(cycles, pkg, lrmData, "nvidia-graphics-drivers-570", "570.195.03-0ubuntu0.24.04.1", "noble", uploadDate)
+	if len(simulation.Warnings) != 1 {
+		t.Errorf("Warnings = %v, expected the upload to be flagged as not newer than proposed", simulation.Warnings)
+	}
+
+	pkg.Series = append(pkg.Series, SeriesData{Series: "resolute", UpdatesSecurity: "570.172.08-0ubuntu1", Freeze: freezeNeedsException})
+	simulation = simulatePromotion(cycles, pkg, lrmData, "nvidia-graphics-drivers-570", "570.195.03-0ubuntu1", "resolute", uploadDate)
+	if len(simulation.Warnings) != 1 || !strings.Contains(simulation.Warnings[0], "freeze exception") {
+		t.Errorf("Warnings = %v, expected the devel upload to need a freeze exception", simulation.Warnings)
+	}
+}
+
+func TestApplyFreezeState(t *testing.T) {
+	defer func() {
+		releases.SetReleasesConfig(nil)
+		releases.SetDevelCodename("")
+	}()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "resolute", "status": "Pre-release Freeze"}`))
+	}))
+	defer server.Close()
+	cfg := config.DefaultConfig()
+	cfg.URLs.Launchpad.UbuntuSeriesBaseURL = server.URL + "/ubuntu"
+	releases.SetReleasesConfig(cfg)
+
+	rows := []SeriesData{
+		{Series: "resolute", UpdatesColor: "danger"},
+		{Series: "noble", UpdatesColor: "danger"},
+	}
+	applyFreezeState(rows)
+	if rows[0].Freeze != "" {
+		t.Errorf("Freeze = %q without a known devel series, expected none", rows[0].Freeze)
+	}
+
+	releases.SetDevelCodename("resolute")
+	applyFreezeState(rows)
+	if rows[0].Freeze != freezeNeedsException || rows[1].Freeze != "" {
+		t.Errorf("Freeze = %q, %q, expected only the outdated devel row to need an exception", rows[0].Freeze, rows[1].Freeze)
+	}
+
+	rows = []SeriesData{{Series: "resolute", UpdatesColor: "success"}}
+	applyFreezeState(rows)
+	if rows[0].Freeze != freezeFrozen {
+		t.Errorf("Freeze = %q, expected an up-to-date devel row to be marked frozen only", rows[0].Freeze)
+	}
+}
+
+func TestApplyUploadProgress(t *testing.T) {
+	rows := []SeriesData{
+		{Series: "resolute", UpdatesSecurity: "N/A", Proposed: "N/A", Availability: availabilityNotUploaded},
+		{Series: "questing", UpdatesSecurity: "N/A", Proposed: "N/A", Availability: availabilityNotUploaded, QueueStatus: "Unapproved"},
+		{Series: "noble", UpdatesSecurity: "-", Proposed: "580.95.05-0ubuntu0.24.04.1", ProposedColor: "success"},
+		{Series: "jammy", UpdatesSecurity: "-", Proposed: "-", QueueStatus: "New"},
+		{Series: "focal", UpdatesSecurity: "N/A", Proposed: "N/A", Availability: availabilitySeriesEOL, QueueStatus: "New"},
+		{Series: "bionic", UpdatesSecurity: "-", Proposed: "-", QueueStatus: "New", Removed: "removed on 2024-05-01"},
+		{Series: "plucky", UpdatesSecurity: "580.95.05-0ubuntu0.25.04.1", Proposed: "-", QueueStatus: "Unapproved"},
+	}
+	applyUploadProgress(rows)
+
+	expected := []string{availabilityNotUploaded, availabilityInQueue, availabilityInProposed, availabilityInQueue, availabilitySeriesEOL, "", ""}
+	for i, want := range expected {
+		if rows[i].Availability != want {
+			t.Errorf("%s: Availability = %q, expected %q", rows[i].Series, rows[i].Availability, want)
+		}
+	}
+	if !needsUpload(&rows[0]) || needsUpload(&rows[1]) || needsUpload(&rows[2]) {
+		t.Error("expected only the row without any upload to need one")
+	}
+}
+
+func TestPackageIndexRebuiltOnSwap(t *testing.T) {
+	ws := &WebService{cache: &CachedData{IsInitialized: true}}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-535-server", Series: []SeriesData{{Series: "jammy", UpdatesSecurity: "535.261.03-0ubuntu0.22.04.1"}}},
+	})
+
+	index, _, _ := ws.getPackageIndex()
+	if pkg := index.byBranch["535-server"]; pkg == nil || pkg.PackageName != "nvidia-graphics-drivers-535-server" {
+		t.Errorf("byBranch[535-server] = %v, expected the 535-server package", pkg)
+	}
+	if row, ok := index.row("nvidia-graphics-drivers-570", "noble"); !ok || row.UpdatesSecurity != "570.172.08-0ubuntu0.24.04.1" {
+		t.Errorf("row(570, noble) = %+v, %v, expected the noble row", row, ok)
+	}
+	if strings.Join(index.branches, ",") != "535-server,570" {
+		t.Errorf("branches = %v, expected them sorted", index.branches)
+	}
+
+	ws.cache.setPackages([]*PackageData{{PackageName: "nvidia-graphics-drivers-580"}})
+	req := httptest.NewRequest("GET", "/api?package=nvidia-graphics-drivers-570", nil)
+	w := httptest.NewRecorder()
+	ws.apiHandler(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("/api?package=570 after the swap = %d, expected %d", w.Code, http.StatusNotFound)
+	}
+
+	req = httptest.NewRequest("GET", "/api", nil)
+	w = httptest.NewRecorder()
+	ws.apiHandler(w, req)
+	var response struct {
+		Packages map[string]*PackageData `json:"packages"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Packages) != 1 || response.Packages["nvidia-graphics-drivers-580"] == nil {
+		t.Errorf("/api after the swap = %s, expected only 580", w.Body.String())
+	}
+}
+
+// adminRequest returns a request carrying the admin token of adminConfig
+func adminRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Authorization", "Bearer test-admin-token")
+	return req
+}
+
+// adminConfig returns the default configuration with an admin token
+func adminConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Auth.AdminToken = "test-admin-token"
+	return cfg
+}
+
+func TestAdminWritesNeedSessionOrToken(t *testing.T) {
+	ws := &WebService{config: config.DefaultConfig(), cache: &CachedData{IsInitialized: true}, noteStore: notes.NewStore("")}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble"}}},
+	})
+
+	w := httptest.NewRecorder()
+	ws.notesHandler(w, adminRequest("PUT", "/api/v1/notes?package=570&series=noble", strings.NewReader(`{"text": "x"}`)))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("PUT note without OIDC or a token configured = %d, expected %d", w.Code, http.StatusForbidden)
+	}
+
+	ws.config = adminConfig()
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("PUT", "/api/v1/notes?package=570&series=noble", strings.NewReader(`{"text": "x"}`))
+	req.Header.Set("Authorization", "Bearer wrong")
+	ws.notesHandler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("PUT note with a wrong token = %d, expected %d", w.Code, http.StatusUnauthorized)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("PUT", "/api/v1/notes?package=570&series=noble", strings.NewReader(`{"text": "x"}`))
+	ws.notesHandler(w, req.WithContext(auth.WithSession(req.Context(), &auth.Session{Name: "viewer", Role: auth.RoleReadOnly})))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("PUT note by a read-only session = %d, expected %d", w.Code, http.StatusForbidden)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("PUT", "/api/v1/notes?package=570&series=noble", strings.NewReader(`{"text": "x"}`))
+	ws.notesHandler(w, req.WithContext(auth.WithSession(req.Context(), &auth.Session{Name: "jdoe", Role: auth.RoleAdmin})))
+	if note, _ := ws.noteStore.Get("nvidia-graphics-drivers-570", "noble"); w.Code != http.StatusOK || note.UpdatedBy != "jdoe" {
+		t.Errorf("PUT note by an admin session = %d with note %+v, expected it set by jdoe", w.Code, note)
+	}
+
+	w = httptest.NewRecorder()
+	ws.notesHandler(w, httptest.NewRequest("GET", "/api/v1/notes", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("GET notes without a token = %d, expected %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestNotesShownOnDashboardCells(t *testing.T) {
+	ws := &WebService{config: adminConfig(), cache: &CachedData{IsInitialized: true}, noteStore: notes.NewStore("")}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble"}, {Series: "jammy"}}},
+	})
+
+	w := httptest.NewRecorder()
+	ws.notesHandler(w, adminRequest("PUT", "/api/v1/notes?package=570&series=focal", strings.NewReader(`{"text": "waiting for SRU review"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("PUT note on a missing cell = %d, expected %d", w.Code, http.StatusNotFound)
+	}
+
+	w = httptest.NewRecorder()
+	ws.notesHandler(w, adminRequest("PUT", "/api/v1/notes?package=570&series=noble", strings.NewReader(`{"text": "blocked on LP#2012345"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT note = %d, expected %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	index, _, _ := ws.getPackageIndex()
+	if row, _ := index.row("nvidia-graphics-drivers-570", "noble"); row.Note != "blocked on LP#2012345" || row.NoteUpdated == "" {
+		t.Errorf("noble row after PUT = %+v, expected the note", row)
+	}
+	if row, _ := index.row("nvidia-graphics-drivers-570", "jammy"); row.Note != "" {
+		t.Errorf("jammy row after PUT = %+v, expected no note", row)
+	}
+
+	w = httptest.NewRecorder()
+	ws.notesHandler(w, adminRequest("DELETE", "/api/v1/notes?package=nvidia-graphics-drivers-570&series=noble", nil))
+	index, _, _ = ws.getPackageIndex()
+	if row, _ := index.row("nvidia-graphics-drivers-570", "noble"); w.Code != http.StatusNoContent || row.Note != "" {
+		t.Errorf("DELETE note = %d with row %+v, expected the note removed", w.Code, row)
+	}
+}
+
+func TestAnnotationsCSVRoundTrip(t *testing.T) {
+	ws := &WebService{config: adminConfig(), cache: &CachedData{IsInitialized: true}, noteStore: notes.NewStore(""), ackStore: acks.NewStore("")}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesColor: "danger"}, {Series: "jammy"}}},
+		{PackageName: "nvidia-graphics-drivers-470", Series: []SeriesData{{Series: "jammy", UpdatesColor: "danger"}}},
+	})
+
+	invalid := "branch,series,note,expiry\n" +
+		"570,noble,blocked on LP#2012345,\n" +
+		"570,focal,no such cell,\n" +
+		"470,jammy,frozen,2020-01-01\n" +
+		"570,noble,listed twice,\n" +
+		"470,jammy\n"
+	w := httptest.NewRecorder()
+	ws.annotationsHandler(w, adminRequest("POST", "/api/v1/annotations", strings.NewReader(invalid)))
+	var rejected struct {
+		Problems []string `json:"problems"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &rejected)
+	if w.Code != http.StatusBadRequest || len(rejected.Problems) != 4 || !strings.HasPrefix(rejected.Problems[0], "line 3: ") {
+		t.Errorf("POST of an invalid CSV = %d %s, expected the 4 invalid rows by line", w.Code, w.Body.String())
+	}
+	if len(ws.noteStore.List()) != 0 {
+		t.Errorf("notes = %+v after a rejected upload, expected nothing applied", ws.noteStore.List())
+	}
+
+	valid := "Branch,Series,Note,Expiry\n" +
+		"570,noble,\"blocked on LP#2012345, waiting for review\",\n" +
+		"nvidia-graphics-drivers-470,jammy,470 frozen on jammy,30d\n"
+	w = httptest.NewRecorder()
+	ws.annotationsHandler(w, adminRequest("POST", "/api/v1/annotations", strings.NewReader(valid)))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"acknowledgements":1`) {
+		t.Fatalf("POST of a valid CSV = %d %s", w.Code, w.Body.String())
+	}
+	index, _, _ := ws.getPackageIndex()
+	if row, _ := index.row("nvidia-graphics-drivers-570", "noble"); row.Note != "blocked on LP#2012345, waiting for review" {
+		t.Errorf("570/noble row = %+v, expected the uploaded note", row)
+	}
+	if row, _ := index.row("nvidia-graphics-drivers-470", "jammy"); row.UpdatesColor != acknowledgedColor {
+		t.Errorf("470/jammy row = %+v, expected the uploaded acknowledgement", row)
+	}
+
+	w = httptest.NewRecorder()
+	ws.annotationsHandler(w, httptest.NewRequest("GET", "/api/v1/annotations", nil))
+	exported := w.Body.String()
+	if !strings.HasPrefix(exported, "branch,series,note,expiry\n570,noble,\"blocked on LP#2012345, waiting for review\",\n470,jammy,470 frozen on jammy,") {
+		t.Errorf("GET /api/v1/annotations = %q, expected the note then the acknowledgement", exported)
+	}
+	w = httptest.NewRecorder()
+	ws.annotationsHandler(w, adminRequest("POST", "/api/v1/annotations", strings.NewReader(exported)))
+	if w.Code != http.StatusOK {
+		t.Errorf("POST of the export = %d %s, expected it to upload back", w.Code, w.Body.String())
+	}
+}
+
+func TestAcknowledgedCellsAreNotOutdated(t *testing.T) {
+	ws := &WebService{config: adminConfig(), cache: &CachedData{IsInitialized: true}, ackStore: acks.NewStore("")}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-470", Series: []SeriesData{
+			{Series: "bionic", UpdatesSecurity: "470.239.06-0ubuntu0.18.04.1", UpdatesColor: "danger"},
+			{Series: "jammy", UpdatesColor: "success"},
+		}},
+	})
+
+	tests := []struct {
+		series   string
+		body     string
+		expected int
+	}{
+		{"focal", `{"reason": "470 frozen", "expires": "30d"}`, http.StatusNotFound},
+		{"bionic", `{"reason": "", "expires": "30d"}`, http.StatusBadRequest},
+		{"bionic", `{"reason": "470 frozen", "expires": ""}`, http.StatusBadRequest},
+		{"bionic", `{"reason": "470 frozen", "expires": "2020-01-01"}`, http.StatusBadRequest},
+		{"bionic", `{"reason": "470 frozen", "expires": "soon"}`, http.StatusBadRequest},
+		{"jammy", `{"reason": "470 frozen", "expires": "72h"}`, http.StatusOK},
+		{"bionic", `{"reason": "470 frozen on bionic", "expires": "30d"}`, http.StatusOK},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		ws.acknowledgementsHandler(w, adminRequest("PUT", "/api/v1/acknowledgements?package=470&series="+test.series, strings.NewReader(test.body)))
+		if w.Code != test.expected {
+			t.Errorf("PUT %s %s = %d, expected %d: %s", test.series, test.body, w.Code, test.expected, w.Body.String())
+		}
+	}
+
+	index, _, _ := ws.getPackageIndex()
+	if row, _ := index.row("nvidia-graphics-drivers-470", "bionic"); row.UpdatesColor != acknowledgedColor || row.Acknowledged != "470 frozen on bionic" || row.UpdatesSecurity == "" {
+		t.Errorf("bionic row after PUT = %+v, expected an acknowledged row keeping its version", row)
+	}
+	if row, _ := index.row("nvidia-graphics-drivers-470", "jammy"); row.UpdatesColor != "success" || row.Acknowledged != "" {
+		t.Errorf("jammy row after PUT = %+v, expected up-to-date rows left alone", row)
+	}
+	if pkgs, _, _ := ws.getCachedPackages(); pkgs[0].OutdatedSeries() != 0 {
+		t.Errorf("OutdatedSeries() = %d, expected acknowledged rows not to count", pkgs[0].OutdatedSeries())
+	}
+
+	w := httptest.NewRecorder()
+	ws.acknowledgementsHandler(w, adminRequest("DELETE", "/api/v1/acknowledgements?package=470&series=bionic", nil))
+	index, _, _ = ws.getPackageIndex()
+	if row, _ := index.row("nvidia-graphics-drivers-470", "bionic"); w.Code != http.StatusNoContent || row.UpdatesColor != "danger" || row.Acknowledged != "" {
+		t.Errorf("DELETE acknowledgement = %d with row %+v, expected the row red again", w.Code, row)
+	}
+}
+
+func TestLRMFilters(t *testing.T) {
+	kernels := []lrm.KernelLRMResult{
+		{Series: "24.04", Source: "linux", Routing: "ubuntu/4", Supported: true, LTS: true,
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{{DriverName: "nvidia-graphics-drivers-570"}}},
+		{Series: "24.04", Source: "linux-aws", Routing: "pro/3", Supported: true, LTS: true,
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{{DriverName: "nvidia-graphics-drivers-570-server"}}},
+		{Series: "25.10", Source: "linux", Routing: "ubuntu/4", Development: true,
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{{DriverName: "nvidia-graphics-drivers-580"}}},
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"24.04/linux", "24.04/linux-aws", "25.10/linux"}},
+		{"series=24.04", []string{"24.04/linux", "24.04/linux-aws"}},
+		{"routing=ubuntu/4", []string{"24.04/linux", "25.10/linux"}},
+		{"status=supported", []string{"24.04/linux", "24.04/linux-aws"}},
+		{"status=DEV", []string{"25.10/linux"}},
+		{"status=LTS&routing=pro/3", []string{"24.04/linux-aws"}},
+		{"branch=570", []string{"24.04/linux"}},
+		{"branch=570-server&series=25.10", nil},
+		{"status=unknown", nil},
+	}
+
+	for _, test := range tests {
+		query, _ := url.ParseQuery(test.query)
+		var got []string
+		for _, kernel := range parseLRMFilters(query).Apply(kernels) {
+			got = append(got, kernel.Series+"/"+kernel.Source)
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Apply(%s) = %v, expected %v", test.query, got, test.expected)
+		}
+	}
+
+	options := lrmFilterOptions(kernels)
+	if !reflect.DeepEqual(options.Series, []string{"25.10", "24.04"}) ||
+		!reflect.DeepEqual(options.Routings, []string{"pro/3", "ubuntu/4"}) ||
+		!reflect.DeepEqual(options.Branches, []string{"570", "570-server", "580"}) {
+		t.Errorf("lrmFilterOptions() = %+v, expected every series, routing and branch", options)
+	}
+}
+
+func TestBranchPageCombinesSources(t *testing.T) {
+	now := time.Now()
+	store := history.NewStore("")
+	store.Record(now.AddDate(0, 0, -40), []history.Observation{{Package: "nvidia-graphics-drivers-550", Series: "noble", Published: "550.100-0ubuntu1"}})
+	store.Record(now.AddDate(0, 0, -1), []history.Observation{{Package: "nvidia-graphics-drivers-550", Series: "noble", Published: "550.127.05-0ubuntu1", Outdated: true}})
+
+	ws := &WebService{
+		cache:             &CachedData{IsInitialized: true},
+		templatePath:      "../../templates",
+		historyStore:      store,
+		supportedReleases: []releases.SupportedRelease{{BranchName: "550", CurrentUpstreamVersion: "550.144.03"}},
+		udaEntries: []drivers.DriverEntry{
+			{Version: "550.127.05", Date: now.AddDate(0, -2, 0)},
+			{Version: "550.144.03", Date: now.AddDate(0, 0, -5)},
+			{Version: "570.195.03", Date: now},
+		},
+	}
+	ws.cache.setPackages([]*PackageData{{
+		PackageName: "nvidia-graphics-drivers-550",
+		Series:      []SeriesData{{Series: "noble", UpdatesSecurity: "550.127.05-0ubuntu1", SRUCycle: "-"}},
+		Changelogs:  map[string]*packages.ChangelogEntry{"550.127.05-0ubuntu1": {Bugs: []string{"2012345"}, CVEs: []string{"CVE-2024-0126"}}},
+	}})
+
+	w := httptest.NewRecorder()
+	ws.branchHandler(w, httptest.NewRequest("GET", "/branch/550?format=json", nil))
+	var overview BranchOverview
+	if err := json.Unmarshal(w.Body.Bytes(), &overview); err != nil {
+		t.Fatalf("GET /branch/550?format=json = %d %s: %v", w.Code, w.Body.String(), err)
+	}
+	if len(overview.UDAReleases) != 2 || overview.UDAReleases[0].Version != "550.144.03" {
+		t.Errorf("UDA releases = %+v, expected 550.144.03 and 550.127.05", overview.UDAReleases)
+	}
+	if len(overview.History) != 1 || len(overview.History[0].Points) != 1 || !overview.History[0].Points[0].Outdated {
+		t.Errorf("History = %+v, expected the outdated point of yesterday only", overview.History)
+	}
+	if !reflect.DeepEqual(overview.Bugs, []string{"2012345"}) || !reflect.DeepEqual(overview.CVEs, []string{"CVE-2024-0126"}) {
+		t.Errorf("Bugs = %v, CVEs = %v, expected the changelog references", overview.Bugs, overview.CVEs)
+	}
+
+	w = httptest.NewRecorder()
+	ws.branchHandler(w, httptest.NewRequest("GET", "/branch/nvidia-graphics-drivers-550", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Branch 550") || !strings.Contains(w.Body.String(), "LP: #2012345") {
+		t.Errorf("GET /branch/nvidia-graphics-drivers-550 = %d, expected the branch page", w.Code)
+	}
+	if key := debversion.SortKey("550.127.05-0ubuntu1"); !strings.Contains(w.Body.String(), `data-sort="`+key+`"`) {
+		t.Errorf("branch page does not carry the sort key %q of the published version", key)
+	}
+
+	w = httptest.NewRecorder()
+	ws.branchHandler(w, httptest.NewRequest("GET", "/branch/999", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GET /branch/999 = %d, expected %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestDatasetStatus(t *testing.T) {
+	ws := &WebService{}
+	for _, status := range ws.getDatasets() {
+		if status.State != DatasetPending {
+			t.Errorf("Dataset %s is %s before loading, expected %s", status.Name, status.State, DatasetPending)
+		}
+	}
+
+	ws.setDatasetResult(datasetUDA, nil)
+	ws.setDatasetResult(datasetERD, errors.New("503 Service Unavailable"))
+	ws.setDatasetResult(datasetERD, errors.New("503 Service Unavailable"))
+
+	unavailable := ws.unavailableDatasets()
+	if len(unavailable) != 3 {
+		t.Fatalf("unavailableDatasets() = %+v, expected erd, sru and packages", unavailable)
+	}
+	if unavailable[0].Name != datasetERD || unavailable[0].State != DatasetFailed || unavailable[0].Failures != 2 {
+		t.Errorf("unavailableDatasets()[0] = %+v, expected erd failed twice", unavailable[0])
+	}
+	err := datasetsError(ws.getDatasets())
+	if err == nil || err.Error() != "failed to load erd (503 Service Unavailable)" {
+		t.Errorf("datasetsError() = %v, expected erd to be named", err)
+	}
+
+	ws.setDatasetResult(datasetERD, nil)
+	ws.setDatasetResult(datasetSRU, nil)
+	ws.setDatasetResult(datasetPackages, nil)
+	if unavailable := ws.unavailableDatasets(); len(unavailable) != 0 {
+		t.Errorf("unavailableDatasets() = %+v, expected none once all loaded", unavailable)
+	}
+	for _, status := range ws.getDatasets() {
+		if status.LoadedAt == nil || status.Failures != 0 || status.Error != "" {
+			t.Errorf("Dataset %+v should be loaded without failures", status)
+		}
+	}
+	if err := datasetsError(ws.getDatasets()); err != nil {
+		t.Errorf("datasetsError() = %v, expected nil", err)
+	}
+}
+
+func TestCacheStatusReportsEachDataSource(t *testing.T) {
+	ws := &WebService{cache: &CachedData{IsInitialized: true}, historyStore: history.NewStore("")}
+	lastUpdated := time.Now().Add(-time.Hour)
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570"},
+		{PackageName: "nvidia-graphics-drivers-580", StaleSince: &lastUpdated},
+	})
+	ws.sruCycles = &sru.SRUCycles{Cycles: []sru.SRUCycle{{Name: "s2026.10.05"}, {Name: "s2026.11.02"}}}
+	ws.udaEntries = []drivers.DriverEntry{{Version: "570.195.03"}}
+	ws.allBranches = drivers.AllBranches{"580": {}}
+	ws.setDatasetResult(datasetUDA, nil)
+	ws.setDatasetResult(datasetERD, errors.New("503 Service Unavailable"))
+	ws.setDatasetResult(datasetSRU, nil)
+	ws.setDatasetResult(datasetPackages, nil)
+	next := time.Now().Add(5 * time.Minute)
+	ws.setNextRefresh(next)
+
+	handler := NewAPIHandler()
+	handler.dataSources = ws.dataSources
+	w := httptest.NewRecorder()
+	handler.CacheStatusHandler(w, httptest.NewRequest("GET", "/api/cache-status", nil))
+	var status struct {
+		Sources      []DataSourceStatus `json:"sources"`
+		StaleSources []string           `json:"stale_sources"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("GET /api/cache-status = %d %s", w.Code, w.Body.String())
+	}
+	sources := make(map[string]DataSourceStatus)
+	for _, source := range status.Sources {
+		sources[source.Name] = source
+		if source.NextRefresh == nil || !source.NextRefresh.Equal(next) {
+			t.Errorf("source %s next refresh = %v, expected %v", source.Name, source.NextRefresh, next)
+		}
+	}
+	if s := sources["packages"]; s.Items != 2 || s.StaleItems != 1 || !s.Stale || s.LastRefresh == nil {
+		t.Errorf("packages = %+v, expected 2 packages, one stale", s)
+	}
+	if s := sources["sru"]; s.Items != 2 || s.Stale || s.LastError != "" {
+		t.Errorf("sru = %+v, expected 2 fresh cycles", s)
+	}
+	if s := sources["upstream"]; s.Items != 2 || !s.Stale || s.LastError != "erd: 503 Service Unavailable" || s.LastRefresh != nil {
+		t.Errorf("upstream = %+v, expected the erd failure", s)
+	}
+	if s := sources["history"]; !s.Stale || s.LastRefresh != nil {
+		t.Errorf("history = %+v, expected stale before the first record", s)
+	}
+	if !reflect.DeepEqual(status.StaleSources, []string{"lrm", "packages", "upstream", "history"}) {
+		t.Errorf("stale_sources = %v, expected L-R-M, packages, upstream and history", status.StaleSources)
+	}
+}
+
+func TestRecheckRateLimitedPerPackage(t *testing.T) {
+	ws := &WebService{
+		cache:             &CachedData{IsInitialized: true},
+		supportedReleases: []releases.SupportedRelease{{BranchName: "570"}},
+	}
+
+	req := httptest.NewRequest("POST", "/api/recheck?package=nvidia-graphics-drivers-000", nil)
+	w := httptest.NewRecorder()
+	ws.recheckHandler(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("recheckHandler(unknown package) = %d, expected %d", w.Code, http.StatusNotFound)
+	}
+
+	now := time.Now()
+	if _, ok := ws.reserveRecheck("nvidia-graphics-drivers-570", now); !ok {
+		t.Fatal("first recheck should be allowed")
+	}
+	if wait, ok := ws.reserveRecheck("nvidia-graphics-drivers-570", now.Add(20*time.Second)); ok || wait != 40*time.Second {
+		t.Errorf("reserveRecheck() after 20s = %v, %v, expected a 40s wait", wait, ok)
+	}
+	if _, ok := ws.reserveRecheck("nvidia-graphics-drivers-580", now.Add(20*time.Second)); !ok {
+		t.Error("rechecks of other packages should not be limited")
+	}
+	if _, ok := ws.reserveRecheck("nvidia-graphics-drivers-570", now.Add(recheckInterval)); !ok {
+		t.Error("recheck should be allowed again after the interval")
+	}
+
+	ws.reserveRecheck("nvidia-graphics-drivers-570", time.Now())
+	req = httptest.NewRequest("POST", "/api/recheck?package=nvidia-graphics-drivers-570", nil)
+	w = httptest.NewRecorder()
+	ws.recheckHandler(w, req)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("recheckHandler(rate limited) = %d with Retry-After %q, expected %d", w.Code, w.Header().Get("Retry-After"), http.StatusTooManyRequests)
+	}
+}
+
+func TestArchitectureAlertsForServerBranches(t *testing.T) {
+	ws := &WebService{
+		allBranches: drivers.AllBranches{
+			"570": {DriverInfo: []drivers.DriverInfo{
+				{ReleaseVersion: "570.172.08", ReleaseDate: "2025-07-17", Architectures: []string{"x86_64"}},
+				{ReleaseVersion: "570.158.01", ReleaseDate: "2025-06-02", Architectures: []string{"x86_64", "aarch64"}},
+			}},
+			"575": {DriverInfo: []drivers.DriverInfo{
+				{ReleaseVersion: "575.57.08", ReleaseDate: "2025-06-03", Architectures: []string{"x86_64", "aarch64"}},
+			}},
+		},
+	}
+	supported := []releases.SupportedRelease{
+		{BranchName: "570"},
+		{BranchName: "570-server", IsServer: true},
+		{BranchName: "575-server", IsServer: true},
+	}
+
+	branches := serverArchitectures(supported, ws.allBranches)
+	if len(branches) != 2 || branches[0].Branch != "570-server" || !branches[0].ARM64Lag || branches[1].ARM64Lag {
+		t.Errorf("serverArchitectures() = %+v, expected 570-server lagging and 575-server current", branches)
+	}
+
+	ws.evaluateArchitectureAlerts(supported)
+	defer alerts.Resolve(arm64LagAlertName("570-server"))
+	if !alerts.IsFiring(arm64LagAlertName("570-server")) || alerts.IsFiring(arm64LagAlertName("575-server")) {
+		t.Error("expected the arm64 lag alert for 570-server only")
+	}
+}
+
+func TestOutdatedStreaksSortIndex(t *testing.T) {
+	now := time.Now()
+	store := history.NewStore("")
+	for days := 12; days >= 1; days-- {
+		store.Record(now.AddDate(0, 0, -days), []history.Observation{
+			{Package: "nvidia-graphics-drivers-550", Series: "noble", Outdated: true},
+			{Package: "nvidia-graphics-drivers-570", Series: "noble", Outdated: days <= 3},
+		})
+	}
+
+	ws := &WebService{historyStore: store}
+	pkgs := []*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesColor: "danger"}}},
+		{PackageName: "nvidia-graphics-drivers-535", Series: []SeriesData{{Series: "noble", UpdatesColor: "success"}}},
+		{PackageName: "nvidia-graphics-drivers-550", Series: []SeriesData{{Series: "noble", UpdatesColor: "danger"}}},
+	}
+	ws.trackHistory(pkgs, now)
+
+	if row := pkgs[2].Series[0]; row.OutdatedDays != 12 || row.OutdatedSince != now.AddDate(0, 0, -12).Format(history.DateFormat) {
+		t.Errorf("550 noble outdated %d days since %s, expected 12 days", row.OutdatedDays, row.OutdatedSince)
+	}
+	if days := pkgs[0].Series[0].OutdatedDays; days != 3 {
+		t.Errorf("570 noble outdated %d days, expected 3", days)
+	}
+	if days := pkgs[1].Series[0].OutdatedDays; days != 0 {
+		t.Errorf("535 noble outdated %d days, expected 0", days)
+	}
+
+	var order []string
+	for _, pkg := range sortLongestOutdatedFirst(pkgs) {
+		order = append(order, branchFromPackage(pkg.PackageName))
+	}
+	if !reflect.DeepEqual(order, []string{"550", "570", "535"}) {
+		t.Errorf("index order = %v, expected the longest outdated branch first", order)
+	}
+}
+
+func TestProvenanceReportsFetchAndCacheAge(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer upstream.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.URLs.NVIDIA.ServerDriversAPI = upstream.URL + "/releases.json"
+	ws := &WebService{config: cfg}
+	resp, err := utils.HTTPGetWithRetry(cfg.URLs.NVIDIA.ServerDriversAPI)
+	if err != nil {
+		t.Fatalf("HTTPGetWithRetry() error = %v", err)
+	}
+	resp.Body.Close()
+	ws.setDatasetResult(datasetERD, nil)
+
+	entries := ws.getProvenance(time.Now().Add(90 * time.Second))
+	if len(entries) != 5 {
+		t.Fatalf("getProvenance() returned %d sources, expected 5", len(entries))
+	}
+	erd := entries[1]
+	if erd.Source != "Datacenter JSON" || erd.URL != cfg.URLs.NVIDIA.ServerDriversAPI || erd.HTTPStatus != http.StatusOK || erd.FetchedAt == nil {
+		t.Errorf("Datacenter JSON provenance = %+v, expected the fetch of %s", erd, cfg.URLs.NVIDIA.ServerDriversAPI)
+	}
+	if erd.CacheAgeSeconds == nil || *erd.CacheAgeSeconds < 89 || erd.CacheAge() == "" {
+		t.Errorf("Datacenter JSON cache age = %v, expected about 90s", erd.CacheAgeSeconds)
+	}
+	uda := entries[0]
+	if uda.FetchedAt != nil || uda.CacheAgeSeconds != nil || !strings.HasPrefix(uda.URL, cfg.URLs.NVIDIA.DriverArchiveURL) {
+		t.Errorf("UDA provenance = %+v, expected only the configured URL before any fetch", uda)
+	}
+
+	w := httptest.NewRecorder()
+	ws.provenanceHandler(w, httptest.NewRequest("GET", "/api/provenance", nil))
+	var response struct {
+		Sources []Provenance `json:"sources"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || len(response.Sources) != 5 {
+		t.Errorf("/api/provenance = %d %v, expected 5 sources", w.Code, err)
+	}
+}
+
+func TestGPUInventoryMapsToBranches(t *testing.T) {
+	cfg := adminConfig()
+	cfg.Fleet.GPUBranchesFile = filepath.Join("..", "..", "data", "gpu-branches.json")
+	ws := &WebService{config: cfg, cache: &CachedData{IsInitialized: true}, gpuStore: gpus.NewStore("")}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-535", Series: []SeriesData{
+			{Series: "jammy", UpdatesColor: "danger"},
+			{Series: "noble", UpdatesColor: "success"},
+		}},
+	})
+
+	csv := "pci_id,model,count\n,Tesla T4,6\n,NVIDIA GeForce RTX 4090,2\n,Tesla K80,1\n10de:ffff,Mystery GPU,3\n"
+	tests := []struct {
+		name, contentType, body string
+		expected                int
+	}{
+		{"", "text/csv", csv, http.StatusBadRequest},
+		{"lab-a", "text/csv", "vendor,count\nx,1\n", http.StatusBadRequest},
+		{"lab-a", "application/json", `{"gpus": []}`, http.StatusBadRequest},
+		{"lab-a", "text/csv", csv, http.StatusOK},
+		{"lab-b", "application/json", `{"gpus": [{"model": "tesla t4", "count": 2}]}`, http.StatusOK},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		r := adminRequest("PUT", "/api/v1/gpus/inventory?name="+test.name, strings.NewReader(test.body))
+		r.Header.Set("Content-Type", test.contentType)
+		ws.gpuInventoryHandler(w, r)
+		if w.Code != test.expected {
+			t.Errorf("PUT %q %s = %d, expected %d: %s", test.name, test.contentType, w.Code, test.expected, w.Body.String())
+		}
+	}
+
+	needs := ws.getGPUNeeds()
+	if needs.TableError != "" || needs.GPUs != 14 || len(needs.Inventories) != 2 {
+		t.Fatalf("getGPUNeeds() = %+v, expected 14 GPUs in 2 inventories", needs)
+	}
+	if len(needs.Branches) != 2 || needs.Branches[0].Branch != "535" || needs.Branches[0].GPUs != 10 || len(needs.Branches[0].Models) != 2 {
+		t.Fatalf("Branches = %+v, expected 535 first with T4 and 4090", needs.Branches)
+	}
+	if b := needs.Branches[0]; !b.Tracked || b.Series != 2 || b.OutdatedSeries != 1 || b.Models[0].Model != "Tesla T4" || b.Models[0].GPUs != 8 {
+		t.Errorf("535 status = %+v, expected tracked and outdated in 1 of 2 series", b)
+	}
+	if b := needs.Branches[1]; b.Branch != "470" || !b.Legacy || b.Tracked {
+		t.Errorf("470 status = %+v, expected an untracked legacy branch", b)
+	}
+	if len(needs.Unmapped) != 1 || needs.Unmapped[0].PCIID != "10de:ffff" || needs.Unmapped[0].GPUs != 3 {
+		t.Errorf("Unmapped = %+v, expected the mystery GPU", needs.Unmapped)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PUT", "/api/v1/gpus/inventory?name=lab-c", strings.NewReader(`{"gpus": [{"model": "Tesla T4"}]}`))
+	ws.gpuInventoryHandler(w, r)
+	if w.Code != http.StatusUnauthorized || len(ws.gpuStore.List()) != 2 {
+		t.Errorf("anonymous PUT lab-c = %d, expected %d and nothing stored", w.Code, http.StatusUnauthorized)
+	}
+
+	w = httptest.NewRecorder()
+	ws.gpuInventoryHandler(w, httptest.NewRequest("DELETE", "/api/v1/gpus/inventory?name=lab-b", nil))
+	if w.Code != http.StatusUnauthorized || len(ws.gpuStore.List()) != 2 {
+		t.Errorf("anonymous DELETE lab-b = %d, expected %d and the inventory kept", w.Code, http.StatusUnauthorized)
+	}
+
+	w = httptest.NewRecorder()
+	ws.gpuInventoryHandler(w, adminRequest("DELETE", "/api/v1/gpus/inventory?name=lab-b", nil))
+	if w.Code != http.StatusNoContent || len(ws.gpuStore.List()) != 1 {
+		t.Errorf("DELETE lab-b = %d, expected %d and one inventory left", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestI386CheckWarnsOnBranchPage(t *testing.T) {
+	binaries := map[string]string{
+		// Published on noble, but the i386 build of the proposed upload failed
+		"libnvidia-gl-570": `{"entries": [
+			{"binary_package_version": "570.172.08-0ubuntu0.24.04.1", "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/noble/i386", "pocket": "Updates", "status": "Published"},
+			{"binary_package_version": "570.195.03-0ubuntu0.24.04.1", "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/noble/amd64", "pocket": "Proposed", "status": "Published"},
+			{"binary_package_version": "570.133.07-0ubuntu0.22.04.1", "distro_arch_series_link": "https://api.launchpad.net/devel/ubuntu/jammy/i386", "pocket": "Updates", "status": "Published"}
+		]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := binaries[r.URL.Query().Get("binary_name")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.URLs.Launchpad.PublishedBinariesAPI = server.URL
+	cfg.I386.Libraries = []string{"libnvidia-gl", "libnvidia-missing"}
+	ws := &WebService{config: cfg, cache: &CachedData{IsInitialized: true}, templatePath: "../../templates"}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu0.24.04.1", Proposed: "570.195.03-0ubuntu0.24.04.1"},
+			{Series: "jammy", UpdatesSecurity: "570.172.08-0ubuntu0.22.04.1", Proposed: "-"},
+			{Series: "focal", Removed: "removed on 2025-05-01"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-570-server", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu0.24.04.1"}}},
+	})
+
+	if !ws.runI386Check() {
+		t.Fatal("runI386Check() = false, expected the cached packages to be checked")
+	}
+	if _, checkedAt := ws.getI386Warnings("570-server"); !checkedAt.IsZero() {
+		t.Error("570-server should not be checked, server branches have no i386 libraries")
+	}
+
+	w := httptest.NewRecorder()
+	ws.branchHandler(w, httptest.NewRequest("GET", "/branch/570?format=json", nil))
+	var overview BranchOverview
+	if err := json.Unmarshal(w.Body.Bytes(), &overview); err != nil {
+		t.Fatalf("GET /branch/570?format=json = %d %s: %v", w.Code, w.Body.String(), err)
+	}
+	if overview.I386CheckedAt == nil || len(overview.I386Warnings) != 3 {
+		t.Fatalf("I386Warnings = %+v, expected proposed noble, behind jammy and the failed lookup", overview.I386Warnings)
+	}
+	if warning := overview.I386Warnings[0]; warning.Series != "noble" || warning.Pocket != "proposed" || warning.I386 != "" {
+		t.Errorf("warning 0 = %+v, expected the missing proposed noble build", warning)
+	}
+	if warning := overview.I386Warnings[1]; warning.Series != "jammy" || warning.I386 != "570.133.07-0ubuntu0.22.04.1" {
+		t.Errorf("warning 1 = %+v, expected jammy behind the source", warning)
+	}
+	if warning := overview.I386Warnings[2]; warning.Binary != "libnvidia-missing-570" || warning.Series != "" {
+		t.Errorf("warning 2 = %+v, expected the lookup failure", warning)
+	}
+
+	w = httptest.NewRecorder()
+	ws.branchHandler(w, httptest.NewRequest("GET", "/branch/570", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "libnvidia-gl-570:i386") {
+		t.Errorf("GET /branch/570 = %d, expected the i386 section", w.Code)
+	}
+}
+
+func TestPrimeFromPeer(t *testing.T) {
+	generated := time.Now().Add(-10 * time.Minute).UTC().Truncate(time.Second)
+	peerCfg := config.DefaultConfig()
+	peerCfg.Peer.Token = "s3cret"
+	peer := &WebService{config: peerCfg, cache: &CachedData{}}
+	peer.udaEntries = []drivers.DriverEntry{{Version: "570.195.03", Date: generated}}
+	peer.allBranches = drivers.AllBranches{}
+	server := httptest.NewServer(http.HandlerFunc(peer.snapshotHandler))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Peer.URL = server.URL
+	cfg.Peer.Token = "s3cret"
+	ws := &WebService{config: cfg, cache: &CachedData{}}
+	if ws.primeFromPeer(time.Now()) {
+		t.Fatal("primeFromPeer() = true while the peer is still loading")
+	}
+
+	peer.cacheMux.Lock()
+	peer.cache.setPackages([]*PackageData{{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1"}}}})
+	peer.cache.LastUpdated = generated
+	peer.cache.IsInitialized = true
+	peer.cacheMux.Unlock()
+
+	cfg.Peer.Token = "wrong"
+	if ws.primeFromPeer(time.Now()) {
+		t.Fatal("primeFromPeer() = true with a wrong token")
+	}
+	cfg.Peer.Token = "s3cret"
+	cfg.Peer.MaxAge = "5m"
+	if ws.primeFromPeer(time.Now()) {
+		t.Fatal("primeFromPeer() = true with a snapshot older than max_age")
+	}
+	cfg.Peer.MaxAge = "1h"
+	if !ws.primeFromPeer(time.Now()) {
+		t.Fatal("primeFromPeer() = false, expected the peer snapshot to be applied")
+	}
+
+	pkgs, lastUpdated, isInitialized := ws.getCachedPackages()
+	if !isInitialized || len(pkgs) != 1 || pkgs[0].PackageName != "nvidia-graphics-drivers-570" {
+		t.Errorf("cached packages = %+v (initialized %v), expected the peer packages", pkgs, isInitialized)
+	}
+	if !lastUpdated.Equal(generated) {
+		t.Errorf("LastUpdated = %v, expected the peer generation time %v", lastUpdated, generated)
+	}
+	if len(ws.udaEntries) != 1 || ws.udaEntries[0].Version != "570.195.03" {
+		t.Errorf("udaEntries = %+v, expected the peer UDA releases", ws.udaEntries)
+	}
+	for _, status := range ws.getDatasets() {
+		if status.Name == datasetUDA && status.State != DatasetLoaded {
+			t.Errorf("dataset %s = %s, expected loaded from the peer", status.Name, status.State)
+		}
+	}
+}
+
+func TestCutoffAlerts(t *testing.T) {
+	now := time.Date(2026, 10, 5, 12, 0, 0, 0, time.UTC)
+	cfg := config.DefaultConfig()
+	cfg.Alerts.CutoffRules = []config.CutoffRule{{Branches: []string{"570", "575"}, DaysBefore: 3, Severity: "critical"}}
+	ws := &WebService{config: cfg, sruCycles: &sru.SRUCycles{Cycles: []sru.SRUCycle{
+		{Name: "2026.09.14", CutoffDate: "2026-09-09", ReleaseDate: "2026-10-05"},
+		{Name: "2026.10.12", CutoffDate: "2026-10-07", ReleaseDate: "2026-11-02"},
+		{Name: "2026.11.09", CutoffDate: "2026-11-04", ReleaseDate: "2026-11-30"},
+	}}}
+	pkgs := []*PackageData{
+		// Released before the September cutoff, which was missed: the October cutoff is 2 days away
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", ReleaseDate: "2026-09-01", UpdatesColor: "danger", ProposedColor: "danger"},
+			{Series: "jammy", ReleaseDate: "2026-09-01", UpdatesColor: "danger", ProposedColor: "success"},
+		}},
+		// Released after the October cutoff, so it targets November
+		{PackageName: "nvidia-graphics-drivers-575", Series: []SeriesData{
+			{Series: "noble", ReleaseDate: "2026-10-08", UpdatesColor: "danger", ProposedColor: "danger"},
+		}},
+		// Not covered by a rule
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{
+			{Series: "noble", ReleaseDate: "2026-09-01", UpdatesColor: "danger", ProposedColor: "danger"},
+		}},
+	}
+	defer alerts.Resolve(cutoffAlertName("570"))
+
+	ws.evaluateCutoffAlerts(pkgs, now)
+	var fired *alerts.Alert
+	for _, alert := range alerts.Active() {
+		if alert.Name == cutoffAlertName("570") {
+			fired = alert
+		}
+	}
+	if fired == nil || fired.Severity != alerts.SeverityCritical || !strings.Contains(fired.Message, "for noble, 2 days before the SRU cutoff of 2026.10.12") {
+		t.Fatalf("alert = %+v, expected 570 noble two days before the October cutoff", fired)
+	}
+	if alerts.IsFiring(cutoffAlertName("575")) || alerts.IsFiring(cutoffAlertName("580")) {
+		t.Error("expected no cutoff alert for 575 (November cutoff) or 580 (no rule)")
+	}
+
+	pkgs[0].Series[0].ProposedColor = "success"
+	ws.evaluateCutoffAlerts(pkgs, now)
+	if alerts.IsFiring(cutoffAlertName("570")) {
+		t.Error("expected the 570 alert to resolve once uploaded to proposed")
+	}
+}
+
+func TestUpstreamHandlers(t *testing.T) {
+	ws := &WebService{cache: &CachedData{IsInitialized: true}}
+	ws.udaEntries = []drivers.DriverEntry{
+		{Version: "580.82.09", Date: time.Date(2025, 9, 9, 0, 0, 0, 0, time.UTC)},
+		{Version: "575.51.02", IsBeta: true},
+		{Version: "570.195.03", Date: time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)},
+	}
+	ws.allBranches = drivers.AllBranches{
+		"570": {Type: "production branch", DriverInfo: []drivers.DriverInfo{{ReleaseVersion: "570.172.08", ReleaseDate: "2025-07-17"}}},
+		"535": {Type: "lts branch"},
+	}
+
+	w := httptest.NewRecorder()
+	ws.upstreamUDAHandler(w, httptest.NewRequest("GET", "/api/v1/upstream/uda", nil))
+	var uda struct {
+		Entries []UpstreamUDAEntry `json:"entries"`
+		Count   int                `json:"count"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &uda); err != nil || uda.Count != 3 {
+		t.Fatalf("GET /api/v1/upstream/uda = %d %s, expected 3 entries", w.Code, w.Body.String())
+	}
+	if entry := uda.Entries[1]; entry.Branch != "575" || !entry.Beta || entry.Date != "" {
+		t.Errorf("entry 1 = %+v, expected the undated 575 beta", entry)
+	}
+
+	w = httptest.NewRecorder()
+	ws.upstreamUDAHandler(w, httptest.NewRequest("GET", "/api/v1/upstream/uda?branch=570", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &uda); err != nil || uda.Count != 1 || uda.Entries[0].Date != "2025-09-30" {
+		t.Errorf("GET /api/v1/upstream/uda?branch=570 = %s, expected the 570 release", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	ws.upstreamERDHandler(w, httptest.NewRequest("GET", "/api/v1/upstream/erd", nil))
+	var erd struct {
+		Branches []UpstreamERDBranch `json:"branches"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &erd); err != nil || len(erd.Branches) != 2 {
+		t.Fatalf("GET /api/v1/upstream/erd = %d %s, expected 2 branches", w.Code, w.Body.String())
+	}
+	if erd.Branches[0].Branch != "535" || erd.Branches[0].Releases == nil || erd.Branches[1].Type != "production branch" {
+		t.Errorf("branches = %+v, expected 535 then 570 with their types", erd.Branches)
+	}
+
+	w = httptest.NewRecorder()
+	ws.upstreamERDHandler(w, httptest.NewRequest("GET", "/api/v1/upstream/erd?branch=570-server", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &erd); err != nil || len(erd.Branches) != 1 || erd.Branches[0].Releases[0].ReleaseVersion != "570.172.08" {
+		t.Errorf("GET /api/v1/upstream/erd?branch=570-server = %s, expected the 570 branch", w.Body.String())
+	}
+}
+
+func TestCloudImageCheck(t *testing.T) {
+	manifests := map[string]string{
+		"/noble/current/noble-server-cloudimg-amd64.manifest": "adduser\t3.137ubuntu1\n" +
+			"nvidia-utils-570-server\t570.172.08-0ubuntu0.24.04.1\n" +
+			"libnvidia-compute-535-server:amd64\t535.261.03-0ubuntu0.24.04.1\n" +
+			"nvidia-utils-390\t390.157-0ubuntu0.24.04.1\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := manifests[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.CloudImages.BaseURL = server.URL
+	ws := &WebService{config: cfg, cache: &CachedData{IsInitialized: true}}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570-server", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1", Proposed: "-"},
+			{Series: "jammy", UpdatesSecurity: "570.195.03-0ubuntu0.22.04.1", Proposed: "-"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-535-server", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "535.261.03-0ubuntu0.24.04.1", Proposed: "535.274.02-0ubuntu0.24.04.1"},
+		}},
+	})
+
+	w := httptest.NewRecorder()
+	ws.cloudImagesHandler(w, httptest.NewRequest("GET", "/api/v1/cloud-images", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /api/v1/cloud-images before the first check = %d, expected 503", w.Code)
+	}
+	if !ws.runCloudImageCheck() {
+		t.Fatal("runCloudImageCheck() = false, expected the cached packages to be compared")
+	}
+
+	w = httptest.NewRecorder()
+	ws.cloudImagesHandler(w, httptest.NewRequest("GET", "/api/v1/cloud-images?series=noble", nil))
+	var response struct {
+		Images []CloudImageReport `json:"images"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Images) != 1 {
+		t.Fatalf("GET /api/v1/cloud-images?series=noble = %d %s, expected the noble image", w.Code, w.Body.String())
+	}
+	want := map[string]string{
+		"nvidia-utils-570-server":      cloudImageRebuild,
+		"libnvidia-compute-535-server": cloudImageProposed,
+		"nvidia-utils-390":             cloudImageUntracked,
+	}
+	drivers := response.Images[0].Drivers
+	if len(drivers) != len(want) {
+		t.Fatalf("drivers = %+v, expected %d driver binaries", drivers, len(want))
+	}
+	for _, driver := range drivers {
+		if driver.Status != want[driver.Binary] {
+			t.Errorf("%s status = %q, expected %q", driver.Binary, driver.Status, want[driver.Binary])
+		}
+	}
+
+	var jammy *CloudImageReport
+	for i := range ws.cloudImages {
+		if ws.cloudImages[i].Series == "jammy" {
+			jammy = &ws.cloudImages[i]
+		}
+	}
+	if jammy == nil || jammy.Error == "" {
+		t.Errorf("jammy report = %+v, expected the missing manifest reported as an error", jammy)
+	}
+}
+
+func TestSeedsCheckFlagsAndAlertsTheDefaultBranch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("h") != "noble" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(" * nvidia-driver-570 [amd64]\n * linux-modules-nvidia-570-generic [amd64]\n"))
+	}))
+	defer server.Close()
+	defer alerts.Resolve(defaultBranchAlertName("noble"))
+
+	cfg := config.DefaultConfig()
+	cfg.Seeds.BaseURL = server.URL
+	ws := &WebService{config: cfg, cache: &CachedData{IsInitialized: true}}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu0.24.04.1", UpstreamVersion: "570.195.03", UpdatesColor: "danger"},
+			{Series: "jammy", UpdatesSecurity: "570.195.03-0ubuntu0.22.04.1", UpdatesColor: "success"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "580.95.05-0ubuntu0.24.04.1", UpdatesColor: "success"},
+		}},
+	})
+	if !ws.runSeedsCheck() {
+		t.Fatal("runSeedsCheck() = false, expected the cached packages to be checked")
+	}
+
+	pkgs, _, _ := ws.getCachedPackages()
+	if !pkgs[0].Series[0].DefaultBranch || pkgs[0].Series[1].DefaultBranch || pkgs[1].Series[0].DefaultBranch {
+		t.Errorf("rows = %+v, %+v, expected only 570 on noble flagged as the default branch", pkgs[0].Series, pkgs[1].Series)
+	}
+	if !alerts.IsFiring(defaultBranchAlertName("noble")) {
+		t.Error("no alert while the default branch of noble is outdated")
+	}
+
+	w := httptest.NewRecorder()
+	ws.seedsHandler(w, httptest.NewRequest("GET", "/api/v1/seeds", nil))
+	var response struct {
+		Seeds []SeedReport `json:"seeds"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Seeds) != 2 {
+		t.Fatalf("GET /api/v1/seeds = %d %s, expected noble and jammy", w.Code, w.Body.String())
+	}
+	for _, report := range response.Seeds {
+		switch {
+		case report.Series == "noble" && (report.DefaultBranch != "570" || report.Package != "nvidia-graphics-drivers-570"):
+			t.Errorf("noble seed = %+v, expected 570 as default", report)
+		case report.Series == "jammy" && report.Error == "":
+			t.Errorf("jammy seed = %+v, expected the fetch error", report)
+		}
+	}
+
+	// Once the default branch is current, the alert resolves
+	pkgs[0].Series[0].UpdatesColor = "success"
+	ws.evaluateDefaultBranchAlerts(pkgs)
+	if alerts.IsFiring(defaultBranchAlertName("noble")) {
+		t.Error("alert still firing once the default branch is up to date")
+	}
+}
+
+func TestCertificationCrossReference(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "platforms.json")
+	page := `{"objects": [
+	  {"canonical_id": "202404-1", "make": "Dell", "model": "Precision 5690", "release": {"codename": "noble"}, "driver": "nvidia-driver-570"},
+	  {"canonical_id": "202204-2", "make": "Dell", "model": "Precision 3660", "release": {"codename": "jammy"}, "driver": "nvidia-driver-535"},
+	  {"canonical_id": "202404-3", "make": "HPE", "model": "ProLiant DL380", "release": {"codename": "noble"}, "driver": "535-server"},
+	  {"canonical_id": "202404-4", "make": "Lenovo", "model": "ThinkStation P3", "release": {"codename": "noble"}, "driver": "nvidia-driver-390"}
+	]}`
+	if err := os.WriteFile(path, []byte(page), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Certification.File = path
+	cfg.Certification.BranchEOL = map[string]string{"535": "2026-12-31", "390": "2026-01-31"}
+	ws := &WebService{config: cfg, cache: &CachedData{IsInitialized: true}}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1", UpdatesColor: "success"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-535", Series: []SeriesData{
+			{Series: "jammy", UpdatesSecurity: "535.261.03-0ubuntu0.22.04.1", UpdatesColor: "danger"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-535-server", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "-", Availability: "not-uploaded"},
+		}},
+	})
+
+	w := httptest.NewRecorder()
+	ws.certificationHandler(w, httptest.NewRequest("GET", "/api/v1/certification", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /api/v1/certification before the first check = %d, expected 503", w.Code)
+	}
+	ws.runCertificationCheck()
+
+	index, _, _ := ws.getPackageIndex()
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	platforms, branches := crossReferenceCertification(index, ws.certifiedPlatforms, &cfg.Certification, now)
+	want := map[string][2]string{
+		"202404-1": {certifiedCurrent, ""},
+		"202204-2": {certifiedLagging, branchEOLApproaching},
+		"202404-3": {certifiedUnpublished, branchEOLApproaching},
+		"202404-4": {certifiedUntracked, branchEOLPast},
+	}
+	if len(platforms) != len(want) {
+		t.Fatalf("platforms = %+v, expected %d platforms", platforms, len(want))
+	}
+	for _, platform := range platforms {
+		if got := [2]string{platform.Status, platform.EOLStatus}; got != want[platform.CanonicalID] {
+			t.Errorf("%s status = %v, expected %v", platform.CanonicalID, got, want[platform.CanonicalID])
+		}
+	}
+	if len(branches) != 4 || branches[0].Branch != "390" || branches[1].Branch != "535" {
+		t.Fatalf("branches = %+v, expected 390, 535, 535-server and 570", branches)
+	}
+	if warnings := branches[1].Warnings; len(warnings) != 2 || warnings[0] != "reaches its EOL on 2026-12-31, in 75 days" || warnings[1] != "lagging in the archive of jammy" {
+		t.Errorf("535 warnings = %q, expected the EOL and the lagging series", warnings)
+	}
+	if len(branches[3].Warnings) != 0 {
+		t.Errorf("570 warnings = %q, expected none", branches[3].Warnings)
+	}
+
+	w = httptest.NewRecorder()
+	ws.certificationHandler(w, httptest.NewRequest("GET", "/api/v1/certification?branch=535-server", nil))
+	var response struct {
+		Platforms []CertifiedPlatform `json:"platforms"`
+		Branches  []CertifiedBranch   `json:"branches"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Platforms) != 1 || len(response.Branches) != 1 {
+		t.Fatalf("GET /api/v1/certification?branch=535-server = %d %s, expected the HPE server", w.Code, w.Body.String())
+	}
+	if response.Platforms[0].Vendor != "HPE" || response.Branches[0].Lagging[0] != "noble" {
+		t.Errorf("response = %+v, expected the unpublished noble row reported", response)
+	}
+}
+
+func TestDevelSeedingChecklist(t *testing.T) {
+	defer releases.SetDevelCodename("")
+	ws := &WebService{config: config.DefaultConfig(), cache: &CachedData{IsInitialized: true}, templatePath: "../../templates"}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "resolute", UpdatesSecurity: "570.195.03-0ubuntu1", Proposed: "-"},
+			{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu0.24.04.1"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{
+			{Series: "resolute", UpdatesSecurity: "-", Proposed: "580.82.07-0ubuntu1"},
+			{Series: "noble", UpdatesSecurity: "580.82.07-0ubuntu0.24.04.1"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-550", Series: []SeriesData{
+			{Series: "resolute", UpdatesSecurity: "-", Proposed: "-"},
+			{Series: "noble", UpdatesSecurity: "550.127.05-0ubuntu0.24.04.1"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-535", Series: []SeriesData{
+			{Series: "resolute", UpdatesSecurity: "535.247.01-0ubuntu1", Proposed: "-"},
+			{Series: "noble", UpdatesSecurity: "535.261.03-0ubuntu0.24.04.1"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-470", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "470.256.02-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-390", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "-", Removed: "removed on 2025-01-10"}}},
+	})
+
+	ws.updateDevelSeeding(time.Now())
+	if ws.getDevelSeeding() != nil {
+		t.Fatal("a checklist was generated without a known devel series")
+	}
+	w := httptest.NewRecorder()
+	ws.seedingHandler(w, httptest.NewRequest("GET", "/api/v1/seeding", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /api/v1/seeding = %d before detection, expected 503", w.Code)
+	}
+
+	releases.SetDevelCodename("resolute")
+	detected := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	ws.updateDevelSeeding(detected)
+	ws.updateDevelSeeding(detected.Add(time.Hour))
+	w = httptest.NewRecorder()
+	ws.seedingHandler(w, httptest.NewRequest("GET", "/api/v1/seeding", nil))
+	var seeding DevelSeeding
+	if err := json.Unmarshal(w.Body.Bytes(), &seeding); err != nil {
+		t.Fatalf("GET /api/v1/seeding = %d %s", w.Code, w.Body.String())
+	}
+	if seeding.Devel != "resolute" || seeding.Previous != "noble" || !seeding.DetectedAt.Equal(detected) || seeding.Remaining != 4 {
+		t.Errorf("seeding = %+v, expected noble seeding resolute since the first detection, 4 drivers remaining", seeding)
+	}
+	states := make(map[string]string)
+	for _, item := range seeding.Items {
+		states[item.Branch] = item.Status
+	}
+	want := map[string]string{"570": seedingDone, "580": seedingProposed, "550": seedingPending, "535": seedingBehind, "470": seedingUntracked}
+	if !reflect.DeepEqual(states, want) {
+		t.Errorf("seeding states = %v, expected %v", states, want)
+	}
+
+	w = httptest.NewRecorder()
+	ws.seedingPageHandler(w, httptest.NewRequest("GET", "/seeding", nil))
+	if body := w.Body.String(); w.Code != http.StatusOK || !strings.Contains(body, "4 of the 5 drivers published in noble are not seeded in resolute yet") {
+		t.Errorf("GET /seeding = %d, expected the checklist summary: %s", w.Code, w.Body.String())
+	}
+
+	if got := previousSeries(&config.SeriesConfig{}, "stonking"); got != "resolute" {
+		t.Errorf("previousSeries() of a series missing from the order = %q, expected the newest configured series", got)
+	}
+}
+
+func TestLRMRebuildsForNextCycle(t *testing.T) {
+	ws := &WebService{config: config.DefaultConfig(), cache: &CachedData{IsInitialized: true}, templatePath: "../../templates"}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "580.82.07-0ubuntu0.24.04.1", Proposed: "580.95.05-0ubuntu0.24.04.1"},
+			{Series: "jammy", UpdatesSecurity: "580.82.07-0ubuntu0.22.04.1", Proposed: "-"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1", Proposed: "-"},
+		}},
+	})
+	release, _ := time.Parse("2006-01-02", "2026-10-26")
+	ws.sruCycles = &sru.SRUCycles{Cycles: []sru.SRUCycle{{Name: "s2026.10.05", ReleaseDate: "2026-10-26", ParsedDate: release}}}
+
+	driver := func(name, dsc, dkms string) lrm.NvidiaDriverStatus {
+		return lrm.NvidiaDriverStatus{DriverName: name, DSCVersion: dsc, DKMSVersion: dkms}
+	}
+	data := &lrm.LRMVerifierData{IsInitialized: true, LastUpdated: time.Now(), KernelResults: []lrm.KernelLRMResult{
+		{Series: "24.04", Codename: "noble", Source: "linux-aws", Supported: true, HasLRM: true, LRMPackages: []string{"linux-restricted-modules-aws"},
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{
+				// Published version built, newer one in proposed for the cycle
+				driver("nvidia-graphics-drivers-580", "580.82.07-0ubuntu0.24.04.1", "580.82.07-0ubuntu0.24.04.1"),
+				// Built against an older version than published
+				driver("nvidia-graphics-drivers-570", "570.172.08-0ubuntu0.24.04.1", "570.195.03-0ubuntu0.24.04.1"),
+			}},
+		{Series: "24.04", Codename: "noble", Source: "linux", Supported: true, HasLRM: true, LRMPackages: []string{"linux-restricted-modules"},
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{
+				driver("nvidia-graphics-drivers-580", "580.95.05-0ubuntu0.24.04.1", "580.82.07-0ubuntu0.24.04.1"),
+				driver("nvidia-graphics-drivers-570", "570.172.08-0ubuntu0.24.04.1", "570.195.03-0ubuntu0.24.04.1"),
+			}},
+		{Series: "22.04", Codename: "jammy", Source: "linux", Supported: true, HasLRM: true, LRMPackages: []string{"linux-restricted-modules"},
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{driver("nvidia-graphics-drivers-580", "580.82.07-0ubuntu0.22.04.1", "580.82.07-0ubuntu0.22.04.1")}},
+		{Series: "20.04", Codename: "focal", Source: "linux", Supported: false, HasLRM: true, LRMPackages: []string{"linux-restricted-modules"},
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{driver("nvidia-graphics-drivers-580", "580.65.06-0ubuntu0.20.04.1", "580.82.07-0ubuntu0.20.04.1")}},
+	}}
+
+	now := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	rebuilds := ws.newLRMRebuilds(data, now)
+	if rebuilds.Cycle != "s2026.10.05" || rebuilds.Packages != 2 || len(rebuilds.Rebuilds) != 2 {
+		t.Fatalf("rebuilds = %+v, expected the noble linux and linux-aws L-R-M for s2026.10.05", rebuilds)
+	}
+	if rebuilds.Rebuilds[0].Source != "linux" || rebuilds.Rebuilds[1].Source != "linux-aws" {
+		t.Errorf("rebuilds are not sorted by kernel source: %+v", rebuilds.Rebuilds)
+	}
+	want := []RebuildDriver{
+		{Package: "nvidia-graphics-drivers-580", Branch: "580", Built: "580.82.07-0ubuntu0.24.04.1", Target: "580.95.05-0ubuntu0.24.04.1", Pocket: "proposed"},
+		{Package: "nvidia-graphics-drivers-570", Branch: "570", Built: "570.172.08-0ubuntu0.24.04.1", Target: "570.195.03-0ubuntu0.24.04.1", Pocket: "updates"},
+	}
+	if !reflect.DeepEqual(rebuilds.Rebuilds[1].Drivers, want) {
+		t.Errorf("linux-aws drivers = %+v, expected %+v", rebuilds.Rebuilds[1].Drivers, want)
+	}
+	if drivers := rebuilds.Rebuilds[0].Drivers; len(drivers) != 1 || drivers[0].Branch != "570" {
+		t.Errorf("linux drivers = %+v, expected only 570, 580 being built against proposed already", drivers)
+	}
+
+	w := httptest.NewRecorder()
+	ws.writeLRMRebuildsPage(w, httptest.NewRequest("GET", "/lrm/rebuilds", nil), i18n.DefaultLocale, rebuilds)
+	if body := w.Body.String(); w.Code != http.StatusOK || !strings.Contains(body, "2 L-R-M packages of 2 kernels need a rebuild") ||
+		!strings.Contains(body, "linux-restricted-modules-aws") {
+		t.Errorf("GET /lrm/rebuilds = %d, expected the rebuild list: %s", w.Code, body)
+	}
+}
+
+func TestPrebuiltDivergences(t *testing.T) {
+	index := newPackageIndex([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "580.82.07-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-550", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "550.127.05-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-535", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "535.261.03-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-390", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "-", Removed: "removed on 2025-01-10"}}},
+	})
+	kernels := []lrm.KernelLRMResult{
+		{Codename: "noble", Source: "linux-aws", SourceVersion: "6.8.0-1036.38", HasLRM: true, NvidiaDriverVersions: []string{
+			"nvidia-graphics-drivers-570=570.172.08-0ubuntu0.24.04.1",
+			"nvidia-graphics-drivers-580=580.82.07-0ubuntu0.24.04.1",
+			"nvidia-graphics-drivers-550=550.144.03-0ubuntu0.24.04.1",
+			"nvidia-graphics-drivers-390=390.157-0ubuntu0.24.04.1",
+		}},
+		// No dsc was read for this kernel, so nothing is known about its pre-built modules
+		{Codename: "noble", Source: "linux-gcp", SourceVersion: "6.8.0-1040.42", HasLRM: true},
+	}
+
+	statuses := make(map[string]string)
+	for _, divergence := range prebuiltDivergences(index, kernels) {
+		if divergence.Kernel != "linux-aws" {
+			t.Errorf("unexpected row %+v for a kernel without dsc", divergence)
+		}
+		if divergence.Diverged != (divergence.Status != prebuiltInSync) {
+			t.Errorf("%s: diverged = %v with status %s", divergence.Branch, divergence.Diverged, divergence.Status)
+		}
+		statuses[divergence.Branch] = divergence.Status
+	}
+	expected := map[string]string{
+		"535": prebuiltMissing,
+		"550": prebuiltAhead,
+		"570": prebuiltBehind,
+		"580": prebuiltInSync,
+	}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("statuses = %v, expected %v", statuses, expected)
+	}
+}
+
+func TestRecentChanges(t *testing.T) {
+	previous := []*PackageData{
+		{PackageName: "nvidia-graphics-drivers-550", Series: []SeriesData{
+			{Series: "jammy", UpdatesSecurity: "550.127.05-0ubuntu0.22.04.1", Proposed: "550.127.08-0ubuntu0.22.04.1", UpstreamVersion: "550.127.08"},
+			{Series: "noble", UpdatesSecurity: "550.127.05-0ubuntu0.24.04.1", Proposed: "-", UpstreamVersion: "550.127.08"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu0.24.04.1"}}},
+	}
+	current := []*PackageData{
+		{PackageName: "nvidia-graphics-drivers-550", Series: []SeriesData{
+			{Series: "jammy", UpdatesSecurity: "550.127.08-0ubuntu0.22.04.1", Proposed: "-", UpstreamVersion: "550.127.08"},
+			{Series: "noble", UpdatesSecurity: "550.127.05-0ubuntu0.24.04.1", Proposed: "550.127.08-0ubuntu0.24.04.1", UpstreamVersion: "550.127.08"},
+		}},
+		// Stale data is the previous data again, not a change
+		{PackageName: "nvidia-graphics-drivers-570", StaleSince: &time.Time{}, Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1"}}},
+		// The first appearance of a package is not a change either
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "580.82.07-0ubuntu0.24.04.1"}}},
+	}
+
+	ws := &WebService{cache: &CachedData{IsInitialized: true}, templatePath: "../../templates"}
+	ws.recordChanges(nil, previous, time.Now())
+	ws.recordChanges(previous, previous, time.Now())
+	if changes := ws.getRecentChanges(10); len(changes) != 0 {
+		t.Fatalf("recent changes = %+v, expected the first load and unchanged refreshes not recorded", changes)
+	}
+
+	ws.recordChanges(previous, current, time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC))
+	w := httptest.NewRecorder()
+	ws.recentChangesHandler(w, httptest.NewRequest("GET", "/api/changes/recent?limit=5", nil))
+	var response struct {
+		Changes []ChangeSummary `json:"changes"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Changes) != 1 {
+		t.Fatalf("GET /api/changes/recent = %d %s, expected one summary", w.Code, w.Body.String())
+	}
+	summary := response.Changes[0]
+	want := "2 rows changed: 550/jammy updates 550.127.05-0ubuntu0.22.04.1→550.127.08-0ubuntu0.22.04.1, 550/jammy proposed 550.127.08-0ubuntu0.22.04.1→-, 550/noble proposed -→550.127.08-0ubuntu0.24.04.1"
+	if summary.Rows != 2 || len(summary.Changes) != 3 || summary.Summary != want {
+		t.Errorf("summary = %+v, expected %q", summary, want)
+	}
+
+	w = httptest.NewRecorder()
+	ws.recentChangesHandler(w, httptest.NewRequest("GET", "/api/changes/recent?limit=0", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET /api/changes/recent?limit=0 = %d, expected 400", w.Code)
+	}
+
+	ws.cache.setPackages(current)
+	w = httptest.NewRecorder()
+	ws.indexHandler(w, httptest.NewRequest("GET", "/", nil))
+	if body := w.Body.String(); !strings.Contains(body, "2 rows changed") || !strings.Contains(body, `<a href="#nvidia-graphics-drivers-550">550/noble</a>`) {
+		t.Errorf("index page should list the recent changes")
+	}
+	view := &config.ViewConfig{Name: "desktop", Series: []string{"noble"}}
+	if filtered := filterChangesForView(ws.getRecentChanges(5), view); len(filtered) != 1 || filtered[0].Rows != 1 {
+		t.Errorf("filterChangesForView() = %+v, expected the noble change only", filtered)
+	}
+}
+
+func TestChangeReleaseNotes(t *testing.T) {
+	oldEntry := &packages.ChangelogEntry{Version: "550.127.05-0ubuntu0.22.04.1", Changes: []string{"  * New upstream release (LP: #2070000)."}, Bugs: []string{"2070000"}}
+	newEntry := &packages.ChangelogEntry{Version: "550.127.08-0ubuntu0.22.04.1", Changes: []string{"  * New upstream release (LP: #2084512).", "  * New upstream release (LP: #2070000)."}, Bugs: []string{"2084512", "2070000"}}
+	previous := []*PackageData{{PackageName: "nvidia-graphics-drivers-550",
+		Series:     []SeriesData{{Series: "jammy", UpdatesSecurity: "550.127.05-0ubuntu0.22.04.1", Proposed: "-", UpstreamVersion: "550.127.05"}},
+		Changelogs: map[string]*packages.ChangelogEntry{oldEntry.Version: oldEntry},
+	}}
+	current := []*PackageData{{PackageName: "nvidia-graphics-drivers-550",
+		Series:     []SeriesData{{Series: "jammy", UpdatesSecurity: "550.127.08-0ubuntu0.22.04.1", Proposed: "-", UpstreamVersion: "550.127.08"}},
+		Changelogs: map[string]*packages.ChangelogEntry{newEntry.Version: newEntry},
+	}}
+
+	changes := diffPackages(previous, current)
+	if len(changes) != 2 {
+		t.Fatalf("diffPackages() = %+v, expected the updates and upstream changes", changes)
+	}
+	notes := changes[0].ReleaseNotes
+	if notes == nil || notes.Previous != oldEntry.Version || len(notes.Added) != 1 || notes.Added[0] != "New upstream release (LP: #2084512)." || len(notes.Bugs) != 1 || notes.Bugs[0] != "2084512" {
+		t.Errorf("updates release notes = %+v, expected the new bullet and bug only", notes)
+	}
+	if changes[1].ReleaseNotes != nil {
+		t.Errorf("upstream change should carry no release notes")
+	}
+}
+
+func TestOneshotHealth(t *testing.T) {
+	now := time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC)
+	loaded := []DatasetStatus{{Name: datasetUDA, State: DatasetLoaded}, {Name: datasetPackages, State: DatasetLoaded}}
+	if result := oneshotHealth(loaded, nil, nil, now); result.Status != "healthy" || result.ExitCode() != OneshotHealthy || len(result.Reasons) != 0 {
+		t.Errorf("oneshotHealth() = %+v, expected healthy", result)
+	}
+
+	datasets := append(loaded, DatasetStatus{Name: datasetSRU, State: DatasetFailed})
+	packageErrors := []*PackageError{{PackageName: "nvidia-graphics-drivers-580"}}
+	firing := []*alerts.Alert{{Name: "view-desktop-outdated"}}
+	result := oneshotHealth(datasets, packageErrors, firing, now)
+	want := []string{"dataset sru failed", "nvidia-graphics-drivers-580 could not be generated", "alert view-desktop-outdated firing"}
+	if result.Status != "degraded" || result.ExitCode() != OneshotDegraded || strings.Join(result.Reasons, "|") != strings.Join(want, "|") {
+		t.Errorf("oneshotHealth() = %+v, expected degraded with %v", result, want)
+	}
+}
+
+func TestHWEWarnings(t *testing.T) {
+	kernels := []lrm.KernelLRMResult{
+		{Series: "24.04", Codename: "noble", Source: "linux", SourceVersion: "6.8.0-85.85"},
+		{Series: "24.04", Codename: "noble", Source: "linux-hwe-6.11", SourceVersion: "6.11.0-29.29~24.04.1"},
+		// Rolled out already, so its L-R-M is checked by the L-R-M verifier instead
+		{Series: "24.10", Codename: "oracular", Source: "linux", SourceVersion: "6.11.0-29.29", HWETargets: []string{"24.04"},
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{{DriverName: "nvidia-graphics-drivers-570", DSCVersion: "570.195.03-0ubuntu0.24.10.1"}}},
+		{Series: "25.04", Codename: "plucky", Source: "linux", SourceVersion: "6.14.0-33.33", HWETargets: []string{"24.04"},
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{
+				{DriverName: "nvidia-graphics-drivers-570", DSCVersion: "570.195.03-0ubuntu0.25.04.1"},
+				{DriverName: "nvidia-graphics-drivers-580", DSCVersion: "580.95.05-0ubuntu0.25.04.1"},
+				{DriverName: "nvidia-graphics-drivers-535", DSCVersion: "535.274.02-0ubuntu0.25.04.1"},
+				{DriverName: "nvidia-graphics-drivers-470", DSCVersion: "470.256.02-0ubuntu0.25.04.1"},
+			}},
+	}
+	pkgs := []*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu0.24.04.1", Proposed: "570.195.03-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "-", Proposed: "-"}}},
+		{PackageName: "nvidia-graphics-drivers-535", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "535.274.02-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-470", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "-", Removed: "removed on 2025-06-01"}}},
+	}
+
+	warnings := hweWarnings(kernels, pkgs)
+	if len(warnings) != 2 {
+		t.Fatalf("hweWarnings() = %+v, expected the 570 and 580 branches of noble", warnings)
+	}
+	if w := warnings[0]; w.Series != "noble" || w.Kernel != "6.14" || w.FromSeries != "plucky" || w.Branch != "570" || w.Required != "570.195.03" || w.Proposed != "570.195.03-0ubuntu0.24.04.1" {
+		t.Errorf("first warning = %+v, expected 570 needing 570.195.03, already in proposed", w)
+	}
+	if w := warnings[1]; w.Branch != "580" || w.Published != "-" || w.Proposed != "" {
+		t.Errorf("second warning = %+v, expected 580 missing from noble", w)
+	}
+}
+
+func TestDiagnosticsReportSRUCycleProblems(t *testing.T) {
+	ws := &WebService{cache: &CachedData{IsInitialized: true}}
+	ws.sruCycles = &sru.SRUCycles{Warnings: []string{`skipped cycle "2026.09.14": invalid release-date "next monday"`}}
+
+	w := httptest.NewRecorder()
+	ws.diagnosticsHandler(w, httptest.NewRequest("GET", "/api/diagnostics", nil))
+	var response struct {
+		Issues []DataIssue `json:"issues"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Issues) != 1 {
+		t.Fatalf("GET /api/diagnostics = %d %s, expected the skipped cycle", w.Code, w.Body.String())
+	}
+	if issue := response.Issues[0]; issue.Check != checkSRUEntryInvalid || !strings.Contains(issue.Message, "2026.09.14") {
+		t.Errorf("issue = %+v, expected the skipped entry", issue)
+	}
+
+	ws.sruCycles = sru.CreateFallbackSRUCycles()
+	if issues, _, _ := ws.getCachedIssues(); len(issues) != 1 || issues[0].Check != checkSRUEstimated {
+		t.Errorf("issues = %+v, expected the estimated SRU cycles reported", issues)
+	}
+}
+
+func TestTrackerSync(t *testing.T) {
+	var opened []string
+	var closed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/canonical/drivers/issues":
+			var payload struct {
+				Title string `json:"title"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			opened = append(opened, payload.Title)
+			w.Write([]byte(`{"number": 12, "html_url": "https://github.com/canonical/drivers/issues/12"}`))
+		case r.Method == http.MethodPatch:
+			closed = append(closed, r.URL.Path)
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.IssueTracker.ThresholdDays = 10
+	client, err := tracker.NewClient(tracker.KindGitHub, server.URL, "canonical/drivers", "", nil)
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	ws := &WebService{
+		config:        cfg,
+		cache:         &CachedData{IsInitialized: true},
+		trackerClient: client,
+		trackerStore:  tracker.NewStore(filepath.Join(t.TempDir(), "tracker_issues.json")),
+	}
+	outdated := []*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu1", UpdatesColor: "danger", OutdatedDays: 12, OutdatedSince: "2026-10-05"},
+			{Series: "jammy", UpdatesSecurity: "570.172.08-0ubuntu1", UpdatesColor: "danger", OutdatedDays: 3, OutdatedSince: "2026-10-14"},
+		}},
+	}
+	ws.cache.setPackages(outdated)
+
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	if !ws.runTrackerSync(now) || !ws.runTrackerSync(now) {
+		t.Fatal("runTrackerSync() = false, expected the cached packages to be synced")
+	}
+	if len(opened) != 1 || opened[0] != "nvidia-graphics-drivers-570 outdated in noble for 12 days" {
+		t.Fatalf("opened = %v, expected one issue for noble across both syncs", opened)
+	}
+	if issue, ok := ws.trackerStore.Get("nvidia-graphics-drivers-570", "noble"); !ok || issue.Number != 12 {
+		t.Errorf("tracked issue = %+v, %v, expected issue 12", issue, ok)
+	}
+
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu1", UpdatesColor: "success"},
+		}},
+	})
+	ws.runTrackerSync(now)
+	if len(closed) != 1 || closed[0] != "/repos/canonical/drivers/issues/12" {
+		t.Errorf("closed = %v, expected issue 12 closed once noble is up to date", closed)
+	}
+	if list := ws.trackerStore.List(); len(list) != 0 {
+		t.Errorf("tracked issues = %+v, expected none after closing", list)
+	}
+}
+
+func TestReleaseCheck(t *testing.T) {
+	releaseFiles := map[string]string{
+		"/dists/noble/InRelease": "-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA512\n\n" +
+			"Suite: noble\nDate: Thu, 25 Apr 2024 15:10:33 UTC\nComponents: main restricted universe multiverse\n" +
+			"-----BEGIN PGP SIGNATURE-----\n\nabc\n-----END PGP SIGNATURE-----\n",
+		"/dists/noble-updates/InRelease": "Suite: noble-updates\nDate: Mon, 06 Oct 2026 08:00:00 UTC\n" +
+			"Valid-Until: Mon, 13 Oct 2026 08:00:00 UTC\nComponents: main restricted universe\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := releaseFiles[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.ReleaseCheck.MirrorURL = server.URL
+	cfg.ReleaseCheck.Keyring = ""
+	ws := &WebService{config: cfg, cache: &CachedData{IsInitialized: true}}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble"}}},
+	})
+
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	if !ws.runReleaseCheck(now) {
+		t.Fatal("runReleaseCheck() = false, expected the cached suites to be checked")
+	}
+	got := make(map[string]int)
+	for _, issue := range ws.releaseIssues {
+		if issue.Series != "noble" {
+			t.Errorf("issue %+v, expected series noble", issue)
+		}
+		got[issue.Check]++
+	}
+	// The frozen noble release suite is old but healthy; noble-updates is unsigned, expired,
+	// stale and lacks multiverse; noble-security and noble-proposed are missing
+	want := map[string]int{
+		checkReleaseSignature:       1,
+		checkReleaseExpired:         1,
+		checkReleaseStale:           1,
+		checkReleaseComponentAbsent: 1,
+		checkReleaseUnavailable:     len(archiveSuites(ws.cache.AllPackages, packages.PublishedPockets())) - len(releaseFiles),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %+v, expected counts %v", ws.releaseIssues, want)
+	}
+	if issues, _, _ := ws.getCachedIssues(); len(issues) != len(ws.releaseIssues) {
+		t.Errorf("diagnostics issues = %d, expected the %d Release file problems", len(issues), len(ws.releaseIssues))
+	}
+}
+
+func TestPinsHandler(t *testing.T) {
+	ws := &WebService{cache: &CachedData{IsInitialized: true, LastUpdated: time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)}}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1", Proposed: "570.200.01-0ubuntu0.24.04.1", UpdatesColor: "success"},
+			{Series: "jammy", UpdatesSecurity: "570.172.08-0ubuntu0.22.04.1", UpdatesColor: "danger"},
+			{Series: "focal", UpdatesSecurity: "N/A"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-535-server", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "535.274.02-0ubuntu0.24.04.1", UpdatesColor: "success"},
+		}},
+	})
+
+	w := httptest.NewRecorder()
+	ws.pinsHandler(w, httptest.NewRequest("GET", "/api/v1/export/pins?series=noble&format=apt", nil))
+	want := "Package: src:nvidia-graphics-drivers-570\nPin: version 570.195.03-0ubuntu0.24.04.1\nPin-Priority: 1001\n"
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), want) || strings.Contains(w.Body.String(), "570.200.01") {
+		t.Errorf("apt pins = %d %q, expected the published 570 version pinned", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	ws.pinsHandler(w, httptest.NewRequest("GET", "/api/v1/export/pins?series=noble&format=landscape", nil))
+	if want := "nvidia-driver-570=570.195.03-0ubuntu0.24.04.1\nnvidia-driver-535-server=535.274.02-0ubuntu0.24.04.1\n"; w.Body.String() != want {
+		t.Errorf("landscape list = %q, expected %q", w.Body.String(), want)
+	}
+
+	w = httptest.NewRecorder()
+	ws.pinsHandler(w, httptest.NewRequest("GET", "/api/v1/export/pins?branch=570", nil))
+	var response struct {
+		Pins []PinnedVersion `json:"pins"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Pins) != 2 {
+		t.Fatalf("GET /api/v1/export/pins?branch=570 = %d %s, expected the noble and jammy pins", w.Code, w.Body.String())
+	}
+	if response.Pins[1].Series != "jammy" || response.Pins[1].UpToDate {
+		t.Errorf("jammy pin = %+v, expected it marked behind upstream", response.Pins[1])
+	}
+
+	for _, query := range []string{"format=apt", "format=yaml&series=noble", "series=noble&format=apt&priority=high"} {
+		w = httptest.NewRecorder()
+		ws.pinsHandler(w, httptest.NewRequest("GET", "/api/v1/export/pins?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET /api/v1/export/pins?%s = %d, expected 400", query, w.Code)
+		}
+	}
+}
+
+func TestSupportMatrix(t *testing.T) {
+	ws := &WebService{cache: &CachedData{IsInitialized: true}}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.172.08-0ubuntu1", PocketMarkers: " (U/S/-)", Proposed: "570.195.03-0ubuntu1",
+				UpstreamVersion: "570.195.03", UpdatesColor: "danger", ProposedColor: "success", SRUCycle: "2026-11-02"},
+		}},
+	})
+	supportedReleases := []releases.SupportedRelease{
+		{BranchName: "570", IsSupported: map[string]bool{"noble": true, "jammy": true, "focal": false}},
+		{BranchName: "580", IsSupported: map[string]bool{"noble": true}},
+	}
+	kernels := []lrm.KernelLRMResult{
+		{Codename: "noble", Source: "linux", NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{
+			{DriverName: "nvidia-graphics-drivers-570", Status: "✅ Up to date"},
+			{DriverName: "nvidia-graphics-drivers-535", Status: "Update available"},
+		}},
+		{Codename: "noble", Source: "linux-hwe", NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{
+			{DriverName: "nvidia-graphics-drivers-570", Status: "Update available"},
+		}},
+	}
+
+	index, _, _ := ws.getPackageIndex()
+	cells := ws.buildMatrix(index, supportedReleases, kernels, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))
+	var pairs []string
+	for _, cell := range cells {
+		pairs = append(pairs, cell.Branch+"/"+cell.Series)
+	}
+	if got := strings.Join(pairs, ","); got != "570/noble,570/jammy,580/noble" {
+		t.Fatalf("cells = %s, expected the rows then the other supported series of each branch", got)
+	}
+
+	noble := cells[0]
+	wantPockets := map[string]string{"Security": "570.172.08-0ubuntu1", "Updates": "570.172.08-0ubuntu1", "Proposed": "570.195.03-0ubuntu1"}
+	if !noble.Supported || !reflect.DeepEqual(noble.Pockets, wantPockets) || noble.Status != "danger" || noble.SRU.Cycle != "2026-11-02" {
+		t.Errorf("570/noble = %+v, expected the supported row with its pockets", noble)
+	}
+	if noble.LRM == nil || *noble.LRM != (MatrixLRM{Kernels: 2, UpToDate: 1, UpdateAvailable: 1}) {
+		t.Errorf("570/noble L-R-M = %+v, expected two kernels, one up to date", noble.LRM)
+	}
+	if jammy := cells[1]; !jammy.Supported || len(jammy.Pockets) != 0 || jammy.SRU.Cycle != "-" || jammy.LRM.Kernels != 0 {
+		t.Errorf("570/jammy = %+v, expected a supported series without data", jammy)
+	}
+	if cells[2].PackageName != "nvidia-graphics-drivers-580" {
+		t.Errorf("580/noble package = %q, expected the name from the supported release", cells[2].PackageName)
+	}
+
+	ws.supportedReleases = supportedReleases
+	w := httptest.NewRecorder()
+	ws.matrixHandler(w, httptest.NewRequest("GET", "/api/v1/matrix?branch=nvidia-graphics-drivers-570&series=jammy", nil))
+	var response struct {
+		Cells []MatrixCell `json:"cells"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Cells) != 1 || response.Cells[0].Series != "jammy" {
+		t.Errorf("GET /api/v1/matrix?branch=570&series=jammy = %d %s, expected the jammy cell", w.Code, w.Body.String())
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	defer debuglog.Disable()
+	ws := &WebService{}
+
+	w := httptest.NewRecorder()
+	ws.debugHandler(w, adminRequest("PUT", "/api/v1/debug", strings.NewReader(`{"modules": ["lrm"]}`)))
+	if w.Code != http.StatusForbidden || debuglog.Enabled(debuglog.ModuleLRM) {
+		t.Fatalf("PUT /api/v1/debug without an admin token configured = %d, expected %d", w.Code, http.StatusForbidden)
+	}
+	ws.config = adminConfig()
+
+	w = httptest.NewRecorder()
+	ws.debugHandler(w, adminRequest("PUT", "/api/v1/debug", strings.NewReader(`{"modules": ["lrm"], "duration": "30m"}`)))
+	var status debuglog.Status
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("PUT /api/v1/debug = %d %s", w.Code, w.Body.String())
+	}
+	if len(status.Modules) != 1 || status.Modules[0] != debuglog.ModuleLRM || status.Until == nil || !debuglog.Enabled(debuglog.ModuleLRM) {
+		t.Errorf("status = %+v, expected lrm debugging on", status)
+	}
+
+	for _, body := range []string{`{"modules": ["lrm"], "duration": "24h"}`, `{"modules": ["dsc"]}`, `{"modules": []}`, `not json`} {
+		w = httptest.NewRecorder()
+		ws.debugHandler(w, adminRequest("PUT", "/api/v1/debug", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("PUT /api/v1/debug %s = %d, expected 400", body, w.Code)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	ws.debugHandler(w, adminRequest("DELETE", "/api/v1/debug", nil))
+	if w.Code != http.StatusOK || debuglog.Enabled(debuglog.ModuleLRM) {
+		t.Errorf("DELETE /api/v1/debug = %d %s, expected debugging off", w.Code, w.Body.String())
+	}
+}
+
+func TestDiscoveryScan(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry := func(name, series string) string {
+			return fmt.Sprintf(`{"source_package_name": %q, "source_package_version": "1.0-1", "distro_series_link": %q, "status": "Published", "component_name": "restricted"}`,
+				name, server.URL+"/ubuntu/"+series)
+		}
+		fmt.Fprintf(w, `{"entries": [%s, %s, %s]}`, entry("nvidia-graphics-drivers-570", "noble"),
+			entry("nvidia-graphics-drivers-580", "noble"), entry("nvidia-graphics-drivers-590", "noble"))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.URLs.Launchpad.PublishedSourcesAPI = server.URL
+	ws := &WebService{
+		config:            cfg,
+		cache:             &CachedData{IsInitialized: true},
+		supportedReleases: []releases.SupportedRelease{{BranchName: "580", IsSupported: map[string]bool{"noble": true}}},
+	}
+	ws.cache.setPackages([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble"}}},
+	})
+
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	if !ws.runDiscovery(now) {
+		t.Fatal("runDiscovery() = false, expected the archive to be scanned")
+	}
+	issues, _, _ := ws.getCachedIssues()
+	var untracked []string
+	for _, issue := range issues {
+		if issue.Check == checkUntrackedPackage {
+			untracked = append(untracked, issue.Package)
+		}
+	}
+	if len(untracked) != 1 || untracked[0] != "nvidia-graphics-drivers-590" || !ws.getDiscoveryCheckedAt().Equal(now) {
+		t.Errorf("untracked = %v, expected only the 590 branch neither shown nor supported", untracked)
+	}
+}
+
+func TestResponseCacheGenerationAndEncoding(t *testing.T) {
+	ws := &WebService{cache: &CachedData{}}
+	if _, ok := ws.packagesGeneration(); ok {
+		t.Errorf("packagesGeneration() should not allow caching before the first load")
+	}
+	ws.cache.IsInitialized = true
+	ws.cache.LastUpdated = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	ws.cache.setPackages([]*PackageData{{PackageName: "nvidia-graphics-drivers-570"}})
+	before, _ := ws.packagesGeneration()
+	ws.reapplyCellAnnotations() // As after a note is edited
+	if after, _ := ws.packagesGeneration(); after == before {
+		t.Errorf("packagesGeneration() = %s after an edit, expected a new generation", after)
+	}
+
+	store := advisories.NewStore("")
+	handler := NewAPIHandler()
+	handler.advisoryStore = store
+	handler.lrmData = func() (*lrm.LRMVerifierData, error) {
+		return &lrm.LRMVerifierData{IsInitialized: true, LastUpdated: ws.cache.LastUpdated}, nil
+	}
+	before, _ = handler.lrmGeneration()
+	if _, err := store.Add(advisories.Advisory{Branch: "570", KernelSource: "linux-aws", Title: "Broken"}, "", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := handler.lrmGeneration(); after == before {
+		t.Errorf("lrmGeneration() should change when an advisory is added")
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Access-Control-Allow-Origin", "*")
+	header.Set("X-Request-Id", "not cached")
+	body := []byte("{\"data\": \"a\\r\\n\\r\\nb\"}\n")
+	decoded, decodedBody, ok := decodeCachedResponse(encodeCachedResponse(header, body))
+	if !ok || string(decodedBody) != string(body) {
+		t.Fatalf("decodeCachedResponse() = %q, %v, expected the encoded body", decodedBody, ok)
+	}
+	if decoded.Get("Content-Type") != "application/json" || decoded.Get("Access-Control-Allow-Origin") != "*" || decoded.Get("X-Request-Id") != "" {
+		t.Errorf("decodeCachedResponse() headers = %v, expected only the cached headers", decoded)
+	}
+	if _, body, ok := decodeCachedResponse(encodeCachedResponse(http.Header{}, []byte("{}"))); !ok || string(body) != "{}" {
+		t.Errorf("A response without headers should round-trip, got %q", body)
+	}
+
+	// Without Redis the handler is served as is
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	if reflect.ValueOf(cachedResponse(nil, "packages", ws.packagesGeneration, next)).Pointer() != reflect.ValueOf(next).Pointer() {
+		t.Errorf("cachedResponse() without a cache should return the handler unchanged")
+	}
+}
+
+func TestLRMReportListsOnlyProblems(t *testing.T) {
+	kernels := []lrm.KernelLRMResult{
+		{Series: "24.04", Source: "linux-aws", Supported: true, HasLRM: true, LatestLRMVersion: "6.8.0-1036.38",
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{
+				{DriverName: "nvidia-graphics-drivers-570", DSCVersion: "570.172.08-0ubuntu0.24.04.1", DKMSVersion: "570.195.03-0ubuntu0.24.04.1", Status: "Update available"},
+				{DriverName: "nvidia-graphics-drivers-580", DSCVersion: "580.82.07-0ubuntu0.24.04.1", DKMSVersion: "580.82.07-0ubuntu0.24.04.1", Status: "✅ Up to date"},
+			}},
+		{Series: "24.04", Source: "linux-gcp", Supported: true, HasLRM: true, LatestLRMVersion: "6.8.0-1040.42",
+			BuildStatuses: []lrm.BuildStatus{{Package: "linux-restricted-modules-gcp", State: lrm.BuildStateFailed, Summary: "Failed to build on arm64"}}},
+		{Series: "22.04", Source: "linux-azure", Supported: true, HasLRM: true, LatestLRMVersion: "ERROR"},
+		// Up to date, unsupported, without L-R-M or without a DKMS version to compare with: not problems
+		{Series: "24.04", Source: "linux-oracle", Supported: true, HasLRM: true, LatestLRMVersion: "6.8.0-1030.31",
+			NvidiaDriverStatuses: []lrm.NvidiaDriverStatus{{DriverName: "nvidia-graphics-drivers-570", DSCVersion: "570.195.03-0ubuntu0.24.04.1", Status: "⚠️ Unknown"}}},
+		{Series: "23.10", Source: "linux", HasLRM: true, LatestLRMVersion: "ERROR"},
+		{Series: "24.04", Source: "linux-ibm", Supported: true, LatestLRMVersion: "ERROR"},
+	}
+
+	var got []string
+	for _, problem := range lrmProblems(kernels) {
+		got = append(got, problem.Series+"/"+problem.Kernel)
+	}
+	if expected := []string{"24.04/linux-aws", "24.04/linux-gcp", "22.04/linux-azure"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("problems = %v, expected %v", got, expected)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.LRMReport.DashboardURL = "https://nvidia.example.com/"
+	ws := &WebService{config: cfg, templatePath: "../../templates"}
+	cycle := &sru.SRUCycle{Name: "s2026.10.05", ReleaseDate: "2026-10-26"}
+	report, err := ws.buildLRMReport(&lrm.LRMVerifierData{KernelResults: kernels, IsInitialized: true}, cycle, time.Now())
+	if err != nil {
+		t.Fatalf("buildLRMReport: %v", err)
+	}
+	if !strings.Contains(report.Subject, "s2026.10.05") || !strings.Contains(report.Subject, "3 kernels") {
+		t.Errorf("subject = %q", report.Subject)
+	}
+	for _, text := range []string{"linux-aws", "570.195.03-0ubuntu0.24.04.1", "Failed to build on arm64", "https://nvidia.example.com/l-r-m-verifier"} {
+		if !strings.Contains(report.HTML, text) {
+			t.Errorf("report does not show %q", text)
+		}
+	}
+	if strings.Contains(report.HTML, "linux-oracle") || strings.Contains(report.HTML, "<link") {
+		t.Error("report shows a kernel without problems or loads a stylesheet")
+	}
+}
+
+func TestSBOMComponents(t *testing.T) {
+	index := newPackageIndex([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-570-server", Series: []SeriesData{
+			{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1"},
+			{Series: "jammy", UpdatesSecurity: "570.195.03-0ubuntu0.22.04.1"},
+		}},
+		{PackageName: "nvidia-graphics-drivers-470", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "470.256.02-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "-", Proposed: "580.82.07-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-390", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "390.157-0ubuntu0.24.04.1", Removed: "removed on 2025-01-10"}}},
+	})
+	kernels := []lrm.KernelLRMResult{
+		{Codename: "noble", Source: "linux-aws", Supported: true, HasLRM: true, LRMPackages: []string{"linux-restricted-modules-aws"}, LatestLRMVersion: "6.8.0-1036.38"},
+		{Codename: "noble", Source: "linux-gcp", Supported: true, HasLRM: true, LRMPackages: []string{"linux-restricted-modules-gcp"}, LatestLRMVersion: "ERROR"},
+		{Codename: "jammy", Source: "linux-aws", Supported: true, HasLRM: true, LRMPackages: []string{"linux-restricted-modules-aws"}, LatestLRMVersion: "5.15.0-1080.87"},
+	}
+
+	var got []string
+	for _, c := range sbomComponents(index, kernels, "noble") {
+		got = append(got, c.Kind+" "+c.Name+" "+c.Version)
+	}
+	expected := []string{
+		"driver nvidia-graphics-drivers-570-server 570.195.03-0ubuntu0.24.04.1",
+		"dkms nvidia-dkms-570-server 570.195.03-0ubuntu0.24.04.1",
+		"firmware nvidia-firmware-570-server-570.195.03 570.195.03-0ubuntu0.24.04.1",
+		// 470 predates the separate firmware package
+		"driver nvidia-graphics-drivers-470 470.256.02-0ubuntu0.24.04.1",
+		"dkms nvidia-dkms-470 470.256.02-0ubuntu0.24.04.1",
+		"lrm linux-restricted-modules-aws 6.8.0-1036.38",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("components = %q, expected %q", got, expected)
+	}
+	if components := sbomComponents(index, kernels, "plucky"); len(components) != 0 {
+		t.Errorf("components of a series without drivers = %+v", components)
+	}
+}
+
+func TestRecommendDriver(t *testing.T) {
+	index := newPackageIndex([]*PackageData{
+		{PackageName: "nvidia-graphics-drivers-535", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "535.274.02-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-570", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "570.195.03-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-580", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "580.95.05-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-470-server", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "470.256.02-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-535-server", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "535.274.02-0ubuntu0.24.04.1"}}},
+		{PackageName: "nvidia-graphics-drivers-580-server", Series: []SeriesData{{Series: "noble", UpdatesSecurity: "580.95.05-0ubuntu0.24.04.1"}}},
+	})
+	branchTypes := drivers.AllBranches{"535": {Type: "lts branch"}, "580": {Type: "production branch"}}
+	eolCfg := &config.CertificationConfig{BranchEOL: map[string]string{"470": "2024-09-30"}, EOLWarning: "2160h"}
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		useCase, modules, seeded string
+		wantPackage, wantBranch  string
+	}{
+		{useCaseDesktop, "", "570", "nvidia-driver-570-open", "570"},
+		{useCaseDesktop, "proprietary", "", "nvidia-driver-535", "535"},
+		{useCaseServer, "", "", "nvidia-driver-535-server", "535-server"},
+		{useCaseCUDA, "", "", "nvidia-headless-580-server-open", "580-server"},
+	}
+	for _, tt := range tests {
+		got := recommendDriver(index, "noble", tt.useCase, tt.modules, tt.seeded, branchTypes, eolCfg, now)
+		if got == nil || got.Package != tt.wantPackage || got.Branch != tt.wantBranch || len(got.Justification) == 0 {
+			t.Errorf("recommendDriver(%s, %q, seeded %q) = %+v, expected %s", tt.useCase, tt.modules, tt.seeded, got, tt.wantPackage)
+		}
+	}
+
+	// Branches nearing their EOL are only picked when nothing else is published
+	eolCfg.BranchEOL["535"] = "2026-11-30"
+	if got := recommendDriver(index, "noble", useCaseServer, "", "", branchTypes, eolCfg, now); got == nil || got.Branch != "580-server" {
+		t.Errorf("server recommendation = %+v, expected 580-server over the LTS branch nearing its EOL", got)
+	}
+	if got := recommendDriver(index, "jammy", useCaseDesktop, "", "", branchTypes, eolCfg, now); got != nil {
+		t.Errorf("recommendation in a series without drivers = %+v, expected none", got)
+	}
+
+	ws := &WebService{config: config.DefaultConfig(), cache: &CachedData{IsInitialized: true}}
+	w := httptest.NewRecorder()
+	ws.recommendationHandler(w, httptest.NewRequest("GET", "/api/v1/recommendation?series=noble&use_case=gaming", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET with an unknown use case = %d, expected 400", w.Code)
+	}
+}
+
+func TestAPIErrorsAreProblems(t *testing.T) {
+	warming := &WebService{config: config.DefaultConfig(), cache: &CachedData{}}
+	ready := &WebService{config: config.DefaultConfig(), cache: &CachedData{IsInitialized: true}}
+	ready.cache.setPackages([]*PackageData{{PackageName: "nvidia-graphics-drivers-570"}})
+
+	tests := []struct {
+		handler     http.HandlerFunc
+		url         string
+		status      int
+		problemType string
+	}{
+		{warming.apiHandler, "/api?package=nvidia-graphics-drivers-570", http.StatusServiceUnavailable, problems.CacheWarming},
+		{ready.apiHandler, "/api?package=nvidia-graphics-drivers-999", http.StatusNotFound, problems.PackageNotFound},
+		{ready.recommendationHandler, "/api/v1/recommendation?series=noble&use_case=gaming", http.StatusBadRequest, problems.ValidationFailed},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler(w, httptest.NewRequest("GET", tt.url, nil))
+		var problem problems.Problem
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Errorf("GET %s = %s, expected problem details: %v", tt.url, w.Body, err)
+			continue
+		}
+		if w.Code != tt.status || w.Header().Get("Content-Type") != problems.ContentType || problem.Type != tt.problemType || problem.Status != tt.status {
+			t.Errorf("GET %s = %d %s %+v, expected a %s problem with status %d", tt.url, w.Code, w.Header().Get("Content-Type"), problem, tt.problemType, tt.status)
+		}
+	}
+}
+
+func TestVersionReportsBuildFeaturesAndSchemas(t *testing.T) {
+	ws := &WebService{config: config.DefaultConfig(), templatePath: "../../templates"}
+	w := httptest.NewRecorder()
+	ws.versionHandler(w, httptest.NewRequest("GET", "/api/version", nil))
+	var info VersionInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+		t.Fatalf("GET /api/version = %d %s", w.Code, w.Body.String())
+	}
+	if info.Version == "" || info.GoVersion == "" || len(info.ConfigHash) != 16 {
+		t.Errorf("version = %+v, expected the build and a configuration hash", info)
+	}
+	if info.Schemas["history"] != history.SchemaVersion {
+		t.Errorf("schemas = %v, expected the history format", info.Schemas)
+	}
+	if enabled, ok := info.Features["jobs"]; !ok || enabled {
+		t.Errorf("features = %v, expected jobs disabled by default", info.Features)
+	}
+	if _, ok := info.Features["auth.oidc"]; !ok {
+		t.Errorf("features = %v, expected nested sections", info.Features)
+	}
+
+	// Any change of the effective configuration changes the hash
+	ws.config.Jobs.Enabled = true
+	if changed := ws.versionInfo(); changed.ConfigHash == info.ConfigHash || !changed.Features["jobs"] {
+		t.Errorf("version after enabling jobs = %+v, expected a new hash", changed)
+	}
+
+	w = httptest.NewRecorder()
+	ws.aboutPageHandler(w, httptest.NewRequest("GET", "/about", nil))
+	if body := w.Body.String(); w.Code != http.StatusOK || !strings.Contains(body, "About the Monitor") || !strings.Contains(body, "acknowledgements") {
+		t.Errorf("GET /about = %d, expected the build and store formats: %s", w.Code, body)
+	}
+}
+
+func TestGraphPageRejectsInvalidBranch(t *testing.T) {
+	ws := &WebService{cache: &CachedData{IsInitialized: true}, templatePath: "../../templates"}
+
+	w := httptest.NewRecorder()
+	ws.graphPageHandler(w, httptest.NewRequest("GET", "/graph?branch=550;rm&format=json", nil))
+	if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != problems.ContentType {
+		t.Errorf("format=json: status %d, Content-Type %q, expected 400 problem", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	w = httptest.NewRecorder()
+	ws.graphPageHandler(w, httptest.NewRequest("GET", "/graph?branch=550;rm", nil))
+	if w.Code != http.StatusBadRequest || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("page: status %d, Content-Type %q, expected a plain 400", w.Code, w.Header().Get("Content-Type"))
+	}
+	if body := w.Body.String(); !strings.Contains(body, i18n.T("en", "error.invalid_branch")) {
+		t.Errorf("page body = %q, expected the invalid branch error", body)
+	}
+}
+
+func TestNotUploadedSeriesKeepsDevel(t *testing.T) {
+	defer releases.SetDevelCodename("")
+	releases.SetDevelCodename("stonking")
+
+	supported := releases.SupportedRelease{BranchName: "590", IsSupported: map[string]bool{"devel": true, "noble": true, "jammy": false}}
+	if series := strings.Join(notUploadedSeries(&supported), ","); series != "noble,stonking" {
+		t.Errorf("notUploadedSeries() = %s, expected the devel series missing from series.order and noble", series)
+	}
+}
@@ -0,0 +1,88 @@
+// Package debversion compares Debian package versions, such as the archive versions of the
+// NVIDIA driver packages ("570.172.08-0ubuntu0.24.04.1"). It is the public part of the
+// monitor's version handling, for tools importing it.
+package debversion
+
+import (
+	"fmt"
+	"strings"
+
+	version "github.com/knqyf263/go-deb-version"
+)
+
+// OlderThan reports whether Debian version a is older than b. Unparsable versions are never older.
+func OlderThan(a, b string) bool {
+	va, err := version.NewVersion(a)
+	if err != nil {
+		return false
+	}
+	vb, err := version.NewVersion(b)
+	if err != nil {
+		return false
+	}
+	return va.LessThan(vb)
+}
+
+// Valid reports whether s parses as a Debian version
+func Valid(s string) bool {
+	_, err := version.NewVersion(s)
+	return err == nil
+}
+
+// SortKey returns a key of a Debian version that sorts as the version does when compared
+// byte by byte, for sorting version columns without a Debian comparison; "" when s is not a
+// version. Each part (epoch, upstream, revision) is written as its alternating non-digit and
+// digit runs: "~" as "0", other characters as "2" (letters) or "3" followed by the character,
+// the end of a run as "1", and numbers by their length and digits.
+func SortKey(s string) string {
+	s = strings.TrimSpace(s)
+	if !Valid(s) {
+		return ""
+	}
+	epoch, rest := "0", s
+	if i := strings.Index(s, ":"); i >= 0 {
+		epoch, rest = s[:i], s[i+1:]
+	}
+	upstream, revision := rest, ""
+	if i := strings.LastIndex(rest, "-"); i >= 0 {
+		upstream, revision = rest[:i], rest[i+1:]
+	}
+	return sortKeyNumber(epoch) + "!" + sortKeyPart(upstream) + "!" + sortKeyPart(revision)
+}
+
+// sortKeyPart writes the runs of a version part, ending with the empty non-digit run that
+// makes a part sort before its extensions, except those starting with "~"
+func sortKeyPart(part string) string {
+	var key strings.Builder
+	for part != "" {
+		i := 0
+		for ; i < len(part) && (part[i] < '0' || part[i] > '9'); i++ {
+			switch c := part[i]; {
+			case c == '~':
+				key.WriteByte('0')
+			case c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z':
+				key.WriteByte('2')
+				key.WriteByte(c)
+			default:
+				key.WriteByte('3')
+				key.WriteByte(c)
+			}
+		}
+		key.WriteByte('1')
+		part = part[i:]
+
+		i = 0
+		for ; i < len(part) && part[i] >= '0' && part[i] <= '9'; i++ {
+		}
+		key.WriteString(sortKeyNumber(part[:i]))
+		part = part[i:]
+	}
+	key.WriteByte('1')
+	return key.String()
+}
+
+// sortKeyNumber writes a run of digits as its length and digits without leading zeros
+func sortKeyNumber(digits string) string {
+	digits = strings.TrimLeft(digits, "0")
+	return fmt.Sprintf("%02d%s", len(digits), digits)
+}
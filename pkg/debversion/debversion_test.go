@@ -0,0 +1,40 @@
+package debversion
+
+import "testing"
+
+func TestSortKey(t *testing.T) {
+	versions := []string{
+		"570.195.03-0ubuntu0.24.04.1",
+		"570.195.03-0ubuntu0.22.04.1",
+		"570.86.10-0ubuntu1",
+		"570.172.08-0ubuntu0.24.04.1",
+		"570.172.08-0ubuntu0.24.04.1~ppa1",
+		"570.172.08-0ubuntu0.24.04.10",
+		"570.172.08-0ubuntu0.24.04",
+		"570.172.08-0ubuntu1",
+		"570.172.08-1",
+		"570.172.08",
+		"570.172.08~rc1-0ubuntu1",
+		"570.172.08+really570.124-0ubuntu1",
+		"1:535.261.03-0ubuntu1",
+		"535.261.03-0ubuntu0.20.04.1",
+		"535.261.03a-0ubuntu1",
+		"535.261.03.1-0ubuntu1",
+	}
+	for _, a := range versions {
+		for _, b := range versions {
+			keyA, keyB := SortKey(a), SortKey(b)
+			switch {
+			case OlderThan(a, b) && keyA >= keyB:
+				t.Errorf("%s is older than %s, but key %q >= %q", a, b, keyA, keyB)
+			case !OlderThan(a, b) && !OlderThan(b, a) && keyA != keyB:
+				t.Errorf("%s equals %s, but key %q != %q", a, b, keyA, keyB)
+			}
+		}
+	}
+	for _, value := range []string{"-", "N/A", ""} {
+		if key := SortKey(value); key != "" {
+			t.Errorf("SortKey(%q) = %q, expected no key", value, key)
+		}
+	}
+}
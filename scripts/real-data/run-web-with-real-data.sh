@@ -24,9 +24,8 @@ fi
 
 # Check if real mock data exists
 if [ ! -d "test-data/launchpad/sources" ] || [ -z "$(ls -A test-data/launchpad/sources)" ]; then
-    echo "❌ Real mock data not found. Run setup first:"
-    echo "   bash setup-real-mock-data.sh"
-    echo "   bash organize-real-mock-data.sh"
+    echo "❌ Real mock data not found. Capture it first:"
+    echo "   make refresh-fixtures"
     exit 1
 fi
 
@@ -18,8 +18,8 @@ MOCK_DIR="test-data"
 if [ ! -d "$CAPTURE_DIR" ]; then
     echo "❌ ERROR: No captured data found!"
     echo ""
-    echo "🔧 Please run the capture script first:"
-    echo "   ./capture-real-data-simple.sh"
+    echo "🔧 Refresh test-data/ directly with the capture command instead:"
+    echo "   make refresh-fixtures"
     exit 1
 fi
 
@@ -28,8 +28,8 @@ captured_files=$(find "$CAPTURE_DIR" -type f 2>/dev/null | wc -l)
 if [ "$captured_files" -eq 0 ]; then
     echo "❌ ERROR: Capture directory is empty!"
     echo ""
-    echo "🔧 Please run the capture script first:"
-    echo "   ./capture-real-data-simple.sh"
+    echo "🔧 Refresh test-data/ directly with the capture command instead:"
+    echo "   make refresh-fixtures"
     exit 1
 fi
 
@@ -319,12 +319,28 @@ class StatisticsDashboard {
     }
 
     updateDashboard(data) {
+        this.updateWindowSettings(data);
         this.updateSummaryCards(this.calculateSummaryFromCurrentWindow(data.current_window));
         this.updateCharts(data);
         this.updateDomainTable(this.extractDomainsFromCurrentWindow(data.current_window));
         this.updateHistoricalWindowsTable(data.historical_windows || []);
     }
 
+    updateWindowSettings(data) {
+        const minutes = data.window_duration_minutes;
+        if (minutes) {
+            const label = minutes >= 60 && minutes % 60 === 0 ? `${minutes / 60} hour(s)` : `${minutes} minutes`;
+            document.getElementById('window-duration').textContent = label;
+            const subtitle = document.getElementById('historical-subtitle');
+            if (subtitle) {
+                subtitle.textContent = `Historical windows will appear after ${label}`;
+            }
+        }
+        if (data.max_stored_windows) {
+            document.getElementById('max-windows').textContent = data.max_stored_windows;
+        }
+    }
+
     calculateSummaryFromCurrentWindow(currentWindow) {
         if (!currentWindow || !currentWindow.stats) {
             return {
@@ -391,10 +407,31 @@ class StatisticsDashboard {
                 (domainStats.successful_reqs / domainStats.total_requests * 100) : 0,
             failedRequests: domainStats.failed_reqs || 0,
             avgResponseTime: domainStats.avg_response_ms || 0,
-            totalRetries: domainStats.total_retries || 0
+            totalRetries: domainStats.total_retries || 0,
+            bytesTransferred: domainStats.bytes_transferred || 0,
+            statusCodes: domainStats.status_codes || {}
         }));
     }
 
+    formatBytes(bytes) {
+        const units = ['B', 'KiB', 'MiB', 'GiB'];
+        let value = bytes;
+        let unit = 0;
+        while (value >= 1024 && unit < units.length - 1) {
+            value /= 1024;
+            unit++;
+        }
+        return unit === 0 ? `${value} B` : `${value.toFixed(1)} ${units[unit]}`;
+    }
+
+    formatStatusCodes(statusCodes) {
+        // 5xx responses are highlighted, as spikes usually mean the upstream is struggling
+        return Object.keys(statusCodes).sort().map(code => {
+            const text = `${code}×${statusCodes[code]}`;
+            return code.startsWith('5') ? `<span style="color: #E53E3E;">${text}</span>` : text;
+        }).join(' ') || '-';
+    }
+
     updateHistoricalChart(historicalData) {
         if (!this.charts.historical || !historicalData.length) {
             this.showNoHistoricalData(true);
@@ -532,8 +569,9 @@ class StatisticsDashboard {
 
         domains.forEach(domain => {
             const row = tbody.insertRow();
-            // Match the HTML table column order: Domain, Total Requests, Success Rate, Failed Requests, Total Retries, Avg Response Time, Status
-            const status = domain.failedRequests === 0 ? 
+            // Match the HTML table column order: Domain, Total Requests, Success Rate, Failed Requests, Total Retries, Avg Response Time, Data Transferred, Status Codes, Status
+            const serverErrors = Object.keys(domain.statusCodes).some(code => code.startsWith('5'));
+            const status = domain.failedRequests === 0 && !serverErrors ? 
                 '<span style="color: #38A169;">✓ Healthy</span>' : 
                 '<span style="color: #E53E3E;">⚠ Issues</span>';
             
@@ -544,6 +582,8 @@ class StatisticsDashboard {
                 <td>${domain.failedRequests || 0}</td>
                 <td>${domain.totalRetries || 0}</td>
                 <td>${domain.avgResponseTime ? domain.avgResponseTime.toFixed(0) + ' ms' : '0 ms'}</td>
+                <td>${this.formatBytes(domain.bytesTransferred)}</td>
+                <td>${this.formatStatusCodes(domain.statusCodes)}</td>
                 <td>${status}</td>
             `;
         });
@@ -0,0 +1,105 @@
+// Sorts the rows of tables with the "sortable" class when one of their column headers is clicked.
+// Cells are compared by their data-sort attribute when they have one, code unit by code unit:
+// version cells carry a key built by debversion.SortKey, which sorts as Debian versions do.
+// Other cells are compared by their text, in the order of the page language.
+(function() {
+    'use strict';
+
+    const collator = new Intl.Collator(document.documentElement.lang || undefined, { numeric: true, sensitivity: 'base' });
+
+    function sortKeyNumber(digits) {
+        digits = digits.replace(/^0+/, '');
+        return (digits.length < 10 ? '0' : '') + digits.length + digits;
+    }
+
+    function sortKeyPart(part) {
+        let key = '';
+        while (part !== '') {
+            const text = part.match(/^[^0-9]*/)[0];
+            for (const c of text) {
+                if (c === '~') key += '0';
+                else if (/[A-Za-z]/.test(c)) key += '2' + c;
+                else key += '3' + c;
+            }
+            key += '1';
+            part = part.slice(text.length);
+            const digits = part.match(/^[0-9]*/)[0];
+            key += sortKeyNumber(digits);
+            part = part.slice(digits.length);
+        }
+        return key + '1';
+    }
+
+    // versionKey is debversion.SortKey for the rows rendered in the browser
+    function versionKey(value) {
+        value = (value || '').trim();
+        if (!/^([0-9]+:)?[0-9][A-Za-z0-9.+~:-]*$/.test(value)) return '';
+        let epoch = '0';
+        let rest = value;
+        const colon = value.indexOf(':');
+        if (colon >= 0) {
+            epoch = value.slice(0, colon);
+            rest = value.slice(colon + 1);
+        }
+        let upstream = rest;
+        let revision = '';
+        const dash = rest.lastIndexOf('-');
+        if (dash >= 0) {
+            upstream = rest.slice(0, dash);
+            revision = rest.slice(dash + 1);
+        }
+        return sortKeyNumber(epoch) + '!' + sortKeyPart(upstream) + '!' + sortKeyPart(revision);
+    }
+
+    function compareCells(a, b) {
+        if (a && b && a.hasAttribute('data-sort') && b.hasAttribute('data-sort')) {
+            const keyA = a.getAttribute('data-sort');
+            const keyB = b.getAttribute('data-sort');
+            return keyA < keyB ? -1 : keyA > keyB ? 1 : 0;
+        }
+        return collator.compare(a ? a.textContent.trim() : '', b ? b.textContent.trim() : '');
+    }
+
+    function sortTable(table, header) {
+        const column = Array.from(header.parentNode.children).indexOf(header);
+        const descending = header.getAttribute('aria-sort') === 'ascending';
+        table.querySelectorAll('thead th').forEach(function(th) { th.removeAttribute('aria-sort'); });
+        header.setAttribute('aria-sort', descending ? 'descending' : 'ascending');
+
+        Array.from(table.tBodies).forEach(function(tbody) {
+            const rows = Array.from(tbody.rows);
+            // Placeholder rows, e.g. "loading", span every column and are left alone
+            if (rows.length < 2 || rows.some(function(row) { return row.cells.length <= column; })) return;
+            rows.sort(function(a, b) {
+                const order = compareCells(a.cells[column], b.cells[column]);
+                return descending ? -order : order;
+            });
+            rows.forEach(function(row) { tbody.appendChild(row); });
+        });
+    }
+
+    // The sort direction is shown after the header text on every page, whatever its stylesheet
+    const style = document.createElement('style');
+    style.textContent = 'table.sortable th[aria-sort="ascending"]::after { content: " \\25B2"; }' +
+        ' table.sortable th[aria-sort="descending"]::after { content: " \\25BC"; }';
+    document.head.appendChild(style);
+
+    function init(root) {
+        (root || document).querySelectorAll('table.sortable').forEach(function(table) {
+            if (table.dataset.sortReady) return;
+            table.dataset.sortReady = 'true';
+            table.querySelectorAll('thead th').forEach(function(header) {
+                if (header.textContent.trim() === '') return;
+                header.style.cursor = 'pointer';
+                header.addEventListener('click', function() { sortTable(table, header); });
+            });
+        });
+    }
+
+    window.TableSort = { init: init, versionKey: versionKey };
+    if (document.readyState === 'loading') {
+        document.addEventListener('DOMContentLoaded', function() { init(); });
+    } else {
+        init();
+    }
+})();